@@ -0,0 +1,298 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/audit"
+	"github.com/unstablemind/pocket/internal/common/cache"
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/policy"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// DefaultConcurrency is how many requests run at once unless -j is given
+const DefaultConcurrency = 8
+
+// maxConcurrency keeps a typo in -j from opening hundreds of connections
+const maxConcurrency = 64
+
+// Request is one line of a batch file
+type Request struct {
+	// ID is echoed on the request's response: any JSON value
+	ID  json.RawMessage `json:"id"`
+	Cmd []string        `json:"cmd"`
+}
+
+// nested are commands a batch can't run: they read stdin, never return or
+// run requests of their own
+var nested = map[string]bool{"batch": true, "mcp": true, "serve": true, "run": true}
+
+// inherited are global flags given to batch itself that apply to every
+// request, unless the request sets them too
+var inherited = []string{"profile", "no-cache", "cache-ttl", "dry-run"}
+
+// Runner runs requests against fresh command trees
+type Runner struct {
+	newRoot     func() *cobra.Command
+	concurrency int
+	// flags are prepended to every request's command line
+	flags []string
+}
+
+// NewContext returns a context carrying the state of one command run: its
+// output settings, profile, cache settings and dry-run plan. The root
+// command's flags fill it in, and everything the command does reads it
+// from the context it is given, so commands running alongside each other
+// in one process never see each other's settings.
+func NewContext(parent context.Context) context.Context {
+	ctx := output.NewContext(parent)
+	ctx = config.NewContext(ctx)
+	ctx = cache.NewContext(ctx)
+	return dryrun.NewContext(ctx)
+}
+
+// NewRunner returns a Runner that runs up to concurrency requests at once
+func NewRunner(newRoot func() *cobra.Command, concurrency int) *Runner {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Runner{newRoot: newRoot, concurrency: concurrency}
+}
+
+func NewCmd(newRoot func() *cobra.Command) *cobra.Command {
+	var concurrency int
+
+	cmd := &cobra.Command{
+		Use:   "batch [file]",
+		Short: "Run many commands from a JSONL request file",
+		Long: `Run pocket commands listed one per line as JSON, such as
+  {"id": 1, "cmd": ["dev", "npm", "info", "react"]}
+from a file, or stdin when no file (or "-") is given.
+
+Requests run in this process, up to -j at a time, which saves process start-up and connection setup for each call. Each request gets a JSON response envelope tagged with its id, written as one line as soon as it finishes, so lines may come out of order. Global flags given to batch (--profile, --no-cache, --cache-ttl, --dry-run) apply to every request.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if concurrency < 1 || concurrency > maxConcurrency {
+				return output.PrintError(cmd.Context(), "invalid_input", fmt.Sprintf("--concurrency must be between 1 and %d", maxConcurrency), nil)
+			}
+
+			var in io.Reader = os.Stdin
+			if len(args) == 1 && args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return output.PrintErr(cmd.Context(), "read_failed", err, nil)
+				}
+				defer f.Close()
+				in = f
+			}
+
+			r := NewRunner(newRoot, concurrency)
+			r.Inherit(cmd)
+
+			// Responses go where output is printed; anything a command
+			// writes to stdout directly is diverted to stderr
+			w := output.Writer(cmd.Context())
+			stdout := os.Stdout
+			os.Stdout = os.Stderr
+			defer func() { os.Stdout = stdout }()
+
+			if err := r.Run(in, w); err != nil {
+				return output.PrintErr(cmd.Context(), "read_failed", err, nil)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "j", DefaultConcurrency, "Requests to run at once")
+
+	return cmd
+}
+
+// Inherit makes every request use the global flags (--profile,
+// --no-cache, --cache-ttl, --dry-run) that were given to cmd, unless the
+// request sets them itself
+func (r *Runner) Inherit(cmd *cobra.Command) {
+	for _, name := range inherited {
+		if f := cmd.Flags().Lookup(name); f != nil && f.Changed {
+			r.flags = append(r.flags, "--"+name+"="+f.Value.String())
+		}
+	}
+}
+
+// Run reads requests from r, one JSON object per line, and writes each
+// response to w as a line as soon as it is ready. It returns once every
+// request has finished; the error is only for reading r.
+func (r *Runner) Run(in io.Reader, w io.Writer) error {
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	emit := func(line []byte) {
+		mu.Lock()
+		defer mu.Unlock()
+		_, _ = w.Write(append(line, '\n'))
+	}
+
+	jobs := make(chan Request)
+	for i := 0; i < r.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range jobs {
+				emit(r.Do(req))
+			}
+		}()
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var req Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			emit(failure(nil, "invalid_request", fmt.Sprintf("line %d: %v", n, err)))
+			continue
+		}
+		jobs <- req
+	}
+	close(jobs)
+	wg.Wait()
+
+	return scanner.Err()
+}
+
+// Command finds the command cmdline would run, in a fresh command tree
+func (r *Runner) Command(cmdline []string) (*cobra.Command, error) {
+	cmd, _, err := r.newRoot().Find(cmdline)
+	return cmd, err
+}
+
+// Do runs one request and returns its response line, tagged with its id
+func (r *Runner) Do(req Request) []byte {
+	return tag(req.ID, r.Exec(req.Cmd))
+}
+
+// Exec runs one command line with a context of its own, so its output
+// settings, dry-run plan and audit record stay separate from commands
+// running alongside it, and returns the response envelope it printed.
+// Commands needing confirmation fail with confirmation_required rather
+// than prompting.
+func (r *Runner) Exec(cmdline []string) []byte {
+	if len(cmdline) == 0 {
+		return envelope("invalid_request", "cmd is empty")
+	}
+	if nested[cmdline[0]] {
+		return envelope("invalid_request", "pocket "+cmdline[0]+" can't be run from a request")
+	}
+
+	argv := append(append([]string(nil), r.flags...), cmdline...)
+	argv = forceJSON(argv)
+
+	var buf bytes.Buffer
+	// Nobody can answer a prompt for a request, even when this process
+	// was started from a terminal
+	ctx := policy.WithoutPrompts(NewContext(context.Background()))
+	ctx, inv := audit.Start(ctx, argv)
+	output.SetOutput(ctx, &buf)
+
+	root := r.newRoot()
+	root.SetArgs(argv)
+	root.SetIn(strings.NewReader(""))
+	root.SetOut(os.Stderr)
+	root.SetErr(os.Stderr)
+
+	cmd, err := execute(ctx, root)
+	if err = dryrun.Finish(ctx, err); err != nil && !output.IsPrinted(err) {
+		err = output.PrintErr(ctx, "command_failed", err, nil)
+	}
+	inv.Finish(ctx, cmd, err)
+
+	return lastResponse(buf.Bytes())
+}
+
+// execute runs root, turning a panic in the command into an error so only
+// its own request fails, not every request the process is running
+func execute(ctx context.Context, root *cobra.Command) (cmd *cobra.Command, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			fmt.Fprintf(os.Stderr, "panic: %v\n%s", p, debug.Stack())
+			err = fmt.Errorf("command panicked: %v", p)
+		}
+	}()
+	return root.ExecuteContextC(ctx)
+}
+
+// forceJSON makes the request print a JSON envelope whatever -o it asks
+// for; the last --output wins
+func forceJSON(argv []string) []string {
+	out := make([]string, 0, len(argv)+1)
+	for i, a := range argv {
+		if a == "--" {
+			out = append(out, "--output=json")
+			return append(out, argv[i:]...)
+		}
+		out = append(out, a)
+	}
+	return append(out, "--output=json")
+}
+
+// lastResponse picks the final JSON value a command printed. A command
+// that printed nothing, such as a group showing its help, succeeded.
+func lastResponse(printed []byte) []byte {
+	last := []byte(`{"success":true}`)
+	dec := json.NewDecoder(bytes.NewReader(printed))
+	for {
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			if !errors.Is(err, io.EOF) {
+				msg, _ := json.Marshal("unreadable output: " + err.Error())
+				return []byte(`{"success":false,"error":{"code":"command_failed","message":` + string(msg) + `}}`)
+			}
+			return last
+		}
+		last = v
+	}
+}
+
+// tag adds "id" to the front of a response envelope, keeping the rest as
+// the command printed it
+func tag(id json.RawMessage, resp []byte) []byte {
+	if len(id) == 0 {
+		id = json.RawMessage("null")
+	}
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, resp); err != nil || !bytes.HasPrefix(compact.Bytes(), []byte("{")) {
+		return failure(id, "command_failed", "response is not a JSON object")
+	}
+	body := bytes.TrimPrefix(compact.Bytes(), []byte("{"))
+	line := append([]byte(`{"id":`), id...)
+	if !bytes.Equal(body, []byte("}")) {
+		line = append(line, ',')
+	}
+	return append(line, body...)
+}
+
+func failure(id json.RawMessage, code, message string) []byte {
+	return tag(id, envelope(code, message))
+}
+
+func envelope(code, message string) []byte {
+	resp, _ := json.Marshal(output.Response{Error: &output.Error{Code: code, Message: message}})
+	return resp
+}
@@ -0,0 +1,176 @@
+package batch
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/testutil"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+func TestMain(m *testing.M) {
+	testutil.Main(m, nil)
+}
+
+// newTestRoot has the output flags of the real root and a command that
+// blocks until release is closed, so requests overlap. It prints from a
+// goroutine its own goroutine started, as commands fetching in parallel do.
+// panic and fail are commands that crash and that print an error.
+func newTestRoot(started *sync.WaitGroup, release chan struct{}) func() *cobra.Command {
+	return func() *cobra.Command {
+		var format string
+		var fields []string
+		root := &cobra.Command{
+			Use:           "pocket",
+			SilenceUsage:  true,
+			SilenceErrors: true,
+			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				output.SetFormat(ctx, format)
+				output.SetFields(ctx, fields)
+				return nil
+			},
+		}
+		root.PersistentFlags().StringVarP(&format, "output", "o", "json", "")
+		root.PersistentFlags().StringSliceVar(&fields, "fields", nil, "")
+
+		root.AddCommand(&cobra.Command{
+			Use: "wait [name]",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				started.Done()
+				<-release
+				done := make(chan error)
+				go func() {
+					go func() {
+						done <- output.Print(ctx, map[string]any{"name": args[0], "size": len(args[0])})
+					}()
+				}()
+				return <-done
+			},
+		})
+		root.AddCommand(&cobra.Command{
+			Use: "panic",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				var m map[string]int
+				m["x"]++
+				return nil
+			},
+		})
+		root.AddCommand(&cobra.Command{
+			Use: "fail",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				return output.PrintError(ctx, "boom", "it broke", nil)
+			},
+		})
+		return root
+	}
+}
+
+type result struct {
+	ID      any            `json:"id"`
+	Success bool           `json:"success"`
+	Data    map[string]any `json:"data"`
+	Error   *output.Error  `json:"error"`
+}
+
+func TestRunIsolatesConcurrentRequests(t *testing.T) {
+	var started sync.WaitGroup
+	started.Add(3)
+	release := make(chan struct{})
+	go func() {
+		// Only release once all three are running at the same time
+		started.Wait()
+		close(release)
+	}()
+
+	in := strings.Join([]string{
+		`{"id":1,"cmd":["wait","alpha","--fields","name"]}`,
+		`{"id":"two","cmd":["wait","beta","-o","table"]}`,
+		`{"id":3,"cmd":["wait","gamma","--fields","size"]}`,
+		`{"id":4,"cmd":["fail"]}`,
+		`not json`,
+		`{"id":5,"cmd":["batch","x"]}`,
+		`{"id":6,"cmd":[]}`,
+		`{"id":7,"cmd":["panic"]}`,
+	}, "\n")
+
+	var out strings.Builder
+	if err := NewRunner(newTestRoot(&started, release), 3).Run(strings.NewReader(in), &out); err != nil {
+		t.Fatal(err)
+	}
+
+	byID := map[string]result{}
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if !strings.HasPrefix(line, `{"id":`) {
+			t.Errorf("id is not first: %s", line)
+		}
+		var r result
+		if err := json.Unmarshal([]byte(line), &r); err != nil {
+			t.Fatalf("bad line %q: %v", line, err)
+		}
+		id, _ := json.Marshal(r.ID)
+		byID[string(id)] = r
+	}
+	if len(byID) != 8 {
+		t.Fatalf("got %d responses: %s", len(byID), out.String())
+	}
+
+	if r := byID["1"]; !r.Success || len(r.Data) != 1 || r.Data["name"] != "alpha" {
+		t.Errorf("request 1 = %+v", r)
+	}
+	// -o table is overridden, and no other request's --fields applies
+	if r := byID[`"two"`]; !r.Success || len(r.Data) != 2 || r.Data["name"] != "beta" {
+		t.Errorf("request two = %+v", r)
+	}
+	if r := byID["3"]; !r.Success || len(r.Data) != 1 || r.Data["size"] != float64(5) {
+		t.Errorf("request 3 = %+v", r)
+	}
+	if r := byID["4"]; r.Success || r.Error == nil || r.Error.Code != "boom" {
+		t.Errorf("request 4 = %+v", r)
+	}
+	// A panic fails its own request, not the ones alongside it
+	if r := byID["7"]; r.Success || r.Error == nil || r.Error.Code != "command_failed" {
+		t.Errorf("request 7 = %+v", r)
+	}
+	for _, id := range []string{"null", "5", "6"} {
+		if r := byID[id]; r.Success || r.Error == nil || r.Error.Code != "invalid_request" {
+			t.Errorf("request %s = %+v", id, r)
+		}
+	}
+}
+
+func TestForceJSON(t *testing.T) {
+	tests := []struct {
+		argv []string
+		want string
+	}{
+		{[]string{"dev", "npm", "info", "react"}, "dev npm info react --output=json"},
+		{[]string{"utility", "echo", "-o", "yaml", "--", "-o"}, "utility echo -o yaml --output=json -- -o"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(forceJSON(tt.argv), " "); got != tt.want {
+			t.Errorf("forceJSON(%v) = %q, want %q", tt.argv, got, tt.want)
+		}
+	}
+}
+
+func TestTag(t *testing.T) {
+	tests := []struct {
+		id, resp, want string
+	}{
+		{`7`, `{"success":true,"data":{"b":1,"a":2}}`, `{"id":7,"success":true,"data":{"b":1,"a":2}}`},
+		{``, "{\n  \"success\": true\n}\n", `{"id":null,"success":true}`},
+		{`"x"`, `{}`, `{"id":"x"}`},
+	}
+	for _, tt := range tests {
+		if got := string(tag(json.RawMessage(tt.id), []byte(tt.resp))); got != tt.want {
+			t.Errorf("tag(%s, %s) = %s, want %s", tt.id, tt.resp, got, tt.want)
+		}
+	}
+}
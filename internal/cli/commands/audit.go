@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/audit"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+func NewAuditCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Audit log of pocket invocations",
+		Long: `Read the append-only log of every pocket invocation: command, redacted arguments and flags, profile, exit status, error code, duration and the remote endpoints contacted.
+
+Auditing is on by default: every invocation, including ones made through batch, serve, mcp and run, is appended to audit.jsonl next to the config file. Set POCKET_AUDIT_LOG to another path to move the log, or to "off" to stop recording. Set POCKET_AUDIT_CHAIN=1 to hash-chain records so 'pocket audit verify' can prove they weren't altered.`,
+	}
+
+	cmd.AddCommand(newAuditTailCmd())
+	cmd.AddCommand(newAuditSearchCmd())
+	cmd.AddCommand(newAuditExportCmd())
+	cmd.AddCommand(newAuditVerifyCmd())
+
+	return cmd
+}
+
+// auditFilterFlags adds the filters shared by tail, search and export
+type auditFilterFlags struct {
+	integration string
+	since       string
+	until       string
+	failed      bool
+}
+
+func (f *auditFilterFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.integration, "integration", "i", "", "Only this integration or group (e.g. github, dev)")
+	cmd.Flags().StringVar(&f.since, "since", "", "Start of the time range: a date, RFC 3339 time or age (e.g. 2h, 7d)")
+	cmd.Flags().StringVar(&f.until, "until", "", "End of the time range, in the same forms as --since")
+	cmd.Flags().BoolVar(&f.failed, "failed", false, "Only invocations that failed")
+}
+
+func (f *auditFilterFlags) filter(text string) (audit.Filter, error) {
+	filter := audit.Filter{Integration: f.integration, Failed: f.failed, Text: text}
+	now := time.Now()
+	var err error
+	if filter.Since, err = parseAuditTime(f.since, now); err != nil {
+		return filter, fmt.Errorf("invalid --since: %w", err)
+	}
+	if filter.Until, err = parseAuditTime(f.until, now); err != nil {
+		return filter, fmt.Errorf("invalid --until: %w", err)
+	}
+	return filter, nil
+}
+
+// parseAuditTime accepts an age ("90m", "7d") counted back from now, a
+// date or an RFC 3339 time
+func parseAuditTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date, time or age", s)
+}
+
+// readAudit returns the matching records, keeping only the last limit
+// when limit > 0
+func readAudit(filter audit.Filter, limit int) ([]audit.Entry, error) {
+	var entries []audit.Entry
+	err := audit.Read(auditPath(), func(e audit.Entry) bool {
+		if filter.Match(e) {
+			entries = append(entries, e)
+			if limit > 0 && len(entries) > limit {
+				entries = entries[1:]
+			}
+		}
+		return true
+	})
+	return entries, err
+}
+
+func auditPath() string {
+	if p := audit.Path(); p != "" {
+		return p
+	}
+	// Auditing is off, but an existing log can still be read
+	return audit.DefaultPath()
+}
+
+func auditRecords(entries []audit.Entry) []audit.Record {
+	records := make([]audit.Record, len(entries))
+	for i, e := range entries {
+		records[i] = e.Record
+	}
+	return records
+}
+
+func newAuditTailCmd() *cobra.Command {
+	var flags auditFilterFlags
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "tail",
+		Short: "Show the most recent invocations",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			filter, err := flags.filter("")
+			if err != nil {
+				return output.PrintErr(ctx, "invalid_input", err, nil)
+			}
+			entries, err := readAudit(filter, limit)
+			if err != nil {
+				return output.PrintErr(ctx, "audit_failed", err, nil)
+			}
+			return output.Print(ctx, auditRecords(entries))
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().IntVarP(&limit, "limit", "l", 20, "Number of records")
+
+	return cmd
+}
+
+func newAuditSearchCmd() *cobra.Command {
+	var flags auditFilterFlags
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "search [text]",
+		Short: "Find invocations by text, integration and time range",
+		Long:  "Find invocations whose record contains text (case-insensitive; matches commands, arguments, flags, endpoints and error codes), filtered by integration and time range.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			text := ""
+			if len(args) == 1 {
+				text = args[0]
+			}
+			filter, err := flags.filter(text)
+			if err != nil {
+				return output.PrintErr(ctx, "invalid_input", err, nil)
+			}
+			entries, err := readAudit(filter, limit)
+			if err != nil {
+				return output.PrintErr(ctx, "audit_failed", err, nil)
+			}
+			return output.Print(ctx, auditRecords(entries))
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().IntVarP(&limit, "limit", "l", 100, "Maximum records, most recent last (0 for all)")
+
+	return cmd
+}
+
+func newAuditExportCmd() *cobra.Command {
+	var flags auditFilterFlags
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write matching records as JSON Lines, exactly as logged",
+		Long:  "Write matching records as JSON Lines, byte for byte as logged so hashes still verify, to stdout or --file.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			filter, err := flags.filter("")
+			if err != nil {
+				return output.PrintErr(ctx, "invalid_input", err, nil)
+			}
+			entries, err := readAudit(filter, 0)
+			if err != nil {
+				return output.PrintErr(ctx, "audit_failed", err, nil)
+			}
+
+			w := output.Writer(ctx)
+			if file != "" {
+				f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+				if err != nil {
+					return output.PrintErr(ctx, "write_failed", err, nil)
+				}
+				defer f.Close()
+				w = f
+			}
+			for _, e := range entries {
+				if _, err := w.Write(append(e.Raw, '\n')); err != nil {
+					return output.PrintErr(ctx, "write_failed", err, nil)
+				}
+			}
+			if file == "" {
+				return nil
+			}
+			return output.Print(ctx, map[string]any{
+				"status":  "ok",
+				"file":    file,
+				"records": len(entries),
+			})
+		},
+	}
+
+	flags.register(cmd)
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Write to this file instead of stdout")
+
+	return cmd
+}
+
+func newAuditVerifyCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "verify",
+		Short: "Check the hash chain for altered or removed records",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			v, err := audit.Verify(auditPath())
+			if err != nil {
+				return output.PrintErr(ctx, "audit_failed", err, nil)
+			}
+			if !v.Valid {
+				return output.PrintError(ctx, "audit_tampered", fmt.Sprintf("%d record(s) failed verification", len(v.Broken)), v)
+			}
+			return output.Print(ctx, v)
+		},
+	}
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/unstablemind/pocket/internal/common/audit"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+func TestParseAuditTime(t *testing.T) {
+	now := time.Date(2026, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"", time.Time{}},
+		{"90m", now.Add(-90 * time.Minute)},
+		{"7d", now.AddDate(0, 0, -7)},
+		{"2026-05-01T08:00:00Z", time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)},
+		{"2026-05-01", time.Date(2026, 5, 1, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseAuditTime(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseAuditTime(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := parseAuditTime("yesterday", now); err == nil {
+		t.Error("expected error for yesterday")
+	}
+}
+
+func TestAuditExportWritesToOutput(t *testing.T) {
+	log := `{"time":"2026-05-10T12:00:00Z","command":"dev npm info","profile":"default","exit":0,"duration_ms":5}` + "\n" +
+		`{"time":"2026-05-10T12:01:00Z","command":"dev npm search","profile":"default","exit":0,"duration_ms":7}` + "\n"
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := os.WriteFile(path, []byte(log), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(audit.PathEnv, path)
+
+	// Records go where the run prints, as under batch or serve, not stdout
+	var buf bytes.Buffer
+	ctx := output.NewContext(context.Background())
+	output.SetOutput(ctx, &buf)
+	cmd := NewAuditCmd()
+	cmd.SetArgs([]string{"export"})
+	if err := cmd.ExecuteContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != log {
+		t.Errorf("export = %q", buf.String())
+	}
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/cache"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+func NewCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "HTTP response cache",
+		Long:  `Inspect and clear the on-disk cache of API responses (see --no-cache and --cache-ttl).`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "stats",
+		Short: "Show cache size, fresh/stale counts and per-host entries",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			stats, err := cache.GetStats(ctx)
+			if err != nil {
+				return output.PrintErr(ctx, "cache_failed", err, nil)
+			}
+			return output.Print(ctx, stats)
+		},
+	})
+
+	var host string
+	clearCmd := &cobra.Command{
+		Use:   "clear",
+		Short: "Remove cached responses",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			removed, err := cache.Clear(host)
+			if err != nil {
+				return output.PrintErr(ctx, "cache_failed", err, nil)
+			}
+			return output.Print(ctx, map[string]any{
+				"status":  "ok",
+				"removed": removed,
+			})
+		},
+	}
+	clearCmd.Flags().StringVar(&host, "host", "", "Only clear responses from this API host")
+	cmd.AddCommand(clearCmd)
+
+	return cmd
+}
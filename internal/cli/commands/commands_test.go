@@ -0,0 +1,90 @@
+package commands
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+)
+
+func newGroupsRoot() *cobra.Command {
+	root := &cobra.Command{Use: "pocket"}
+	// As on the real root, so shorthand clashes with it show up here
+	root.PersistentFlags().StringP("output", "o", "json", "")
+	for _, newGroup := range []func() *cobra.Command{
+		NewSocialCmd, NewCommsCmd, NewDevCmd, NewProductivityCmd, NewNewsCmd,
+		NewKnowledgeCmd, NewUtilityCmd, NewSystemCmd, NewSecurityCmd, NewMarketingCmd,
+	} {
+		root.AddCommand(newGroup())
+	}
+	return root
+}
+
+// TestRegisteredCommandsHaveMetadata fails when a group mounts a command
+// that isn't in the registry, or a command has nothing to list it by
+func TestRegisteredCommandsHaveMetadata(t *testing.T) {
+	registered := map[string]registry.Integration{}
+	for _, i := range registry.All() {
+		registered[i.Group+" "+i.New().Name()] = i
+	}
+
+	root := newGroupsRoot()
+	mounted := 0
+	for _, group := range root.Commands() {
+		for _, c := range group.Commands() {
+			if _, ok := registered[group.Name()+" "+c.Name()]; !ok {
+				t.Errorf("pocket %s %s is mounted without registry metadata", group.Name(), c.Name())
+				continue
+			}
+			mounted++
+			for _, leaf := range runnable(c) {
+				if leaf.Short == "" {
+					t.Errorf("%s has no description", leaf.CommandPath())
+				}
+			}
+		}
+	}
+	if mounted != len(registered) {
+		t.Errorf("%d integrations registered, %d mounted", len(registered), mounted)
+	}
+
+	for _, i := range registry.All() {
+		for _, k := range i.Keys {
+			if !slices.Contains(config.Keys(), k.Key) {
+				t.Errorf("integration %s: %s is not a config key", i.ID, k.Key)
+			}
+			if k.Description == "" {
+				t.Errorf("integration %s: key %s has no description", i.ID, k.Key)
+			}
+		}
+	}
+}
+
+func TestCatalog(t *testing.T) {
+	root := newGroupsRoot()
+	root.AddCommand(NewConfigCmd())
+
+	groups := catalog(root)
+	if groups[0].Name != registry.Groups[0].ID || groups[len(groups)-1].Name != "config" {
+		t.Errorf("groups out of order: first %s, last %s", groups[0].Name, groups[len(groups)-1].Name)
+	}
+
+	var found *Cmd
+	for _, g := range groups {
+		for i, c := range g.Commands {
+			if c.Command == "pocket dev github issues" {
+				found = &g.Commands[i]
+			}
+		}
+	}
+	if found == nil {
+		t.Fatal("pocket dev github issues is not in the catalog")
+	}
+	if found.Desc != "List issues" || !strings.Contains(found.Flags, "-r repo") {
+		t.Errorf("github issues = %+v", *found)
+	}
+}
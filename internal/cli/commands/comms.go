@@ -3,13 +3,15 @@ package commands
 import (
 	"github.com/spf13/cobra"
 
-	"github.com/unstablemind/pocket/internal/communication/discord"
-	"github.com/unstablemind/pocket/internal/communication/email"
-	"github.com/unstablemind/pocket/internal/communication/notify"
-	"github.com/unstablemind/pocket/internal/communication/slack"
-	"github.com/unstablemind/pocket/internal/communication/telegram"
-	"github.com/unstablemind/pocket/internal/communication/twilio"
-	"github.com/unstablemind/pocket/internal/communication/webhook"
+	"github.com/unstablemind/pocket/internal/common/registry"
+
+	_ "github.com/unstablemind/pocket/internal/communication/discord"
+	_ "github.com/unstablemind/pocket/internal/communication/email"
+	_ "github.com/unstablemind/pocket/internal/communication/notify"
+	_ "github.com/unstablemind/pocket/internal/communication/slack"
+	_ "github.com/unstablemind/pocket/internal/communication/telegram"
+	_ "github.com/unstablemind/pocket/internal/communication/twilio"
+	_ "github.com/unstablemind/pocket/internal/communication/webhook"
 )
 
 func NewCommsCmd() *cobra.Command {
@@ -20,13 +22,7 @@ func NewCommsCmd() *cobra.Command {
 		Long:    `Interact with communication platforms: Email, Slack, Discord, Telegram, etc.`,
 	}
 
-	cmd.AddCommand(email.NewCmd())
-	cmd.AddCommand(slack.NewCmd())
-	cmd.AddCommand(discord.NewCmd())
-	cmd.AddCommand(telegram.NewCmd())
-	cmd.AddCommand(twilio.NewCmd())
-	cmd.AddCommand(webhook.NewCmd())
-	cmd.AddCommand(notify.NewCmd())
+	registry.Mount(cmd)
 
 	return cmd
 }
@@ -18,7 +18,8 @@ func NewConfigCmd() *cobra.Command {
 		Use:   "path",
 		Short: "Show config file path",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return output.Print(map[string]string{
+			ctx := cmd.Context()
+			return output.Print(ctx, map[string]string{
 				"path": config.Path(),
 			})
 		},
@@ -26,13 +27,15 @@ func NewConfigCmd() *cobra.Command {
 
 	cmd.AddCommand(&cobra.Command{
 		Use:   "list",
-		Short: "List all configuration",
+		Short: "List all config (redacted) with each value's source",
+		Long:  `List every key with its redacted value and where it came from (file, profile:<name> or env).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			cfg, err := config.Load()
+			ctx := cmd.Context()
+			entries, err := config.List(ctx)
 			if err != nil {
 				return err
 			}
-			return output.Print(cfg.Redacted())
+			return output.Print(ctx, entries)
 		},
 	})
 
@@ -41,10 +44,11 @@ func NewConfigCmd() *cobra.Command {
 		Short: "Set a configuration value",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := config.Set(args[0], args[1]); err != nil {
+			ctx := cmd.Context()
+			if err := config.Set(ctx, args[0], args[1]); err != nil {
 				return err
 			}
-			return output.Print(map[string]string{
+			return output.Print(ctx, map[string]string{
 				"status": "ok",
 				"key":    args[0],
 			})
@@ -57,11 +61,12 @@ func NewConfigCmd() *cobra.Command {
 		Short: "Get a configuration value",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			val, err := config.Get(args[0])
+			ctx := cmd.Context()
+			val, err := config.Get(ctx, args[0])
 			if err != nil {
 				return err
 			}
-			return output.Print(map[string]string{
+			return output.Print(ctx, map[string]string{
 				"key":   args[0],
 				"value": val,
 			})
@@ -69,5 +74,81 @@ func NewConfigCmd() *cobra.Command {
 	}
 	cmd.AddCommand(getCmd)
 
+	cmd.AddCommand(newProfileCmd())
+
+	return cmd
+}
+
+func newProfileCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage named config profiles",
+		Long: `Profiles hold separate sets of keys, e.g. a work and a personal GitHub token.
+Keys missing from a profile fall back to the default profile.
+Select one per command with --profile or POCKET_PROFILE, or persistently with 'use'.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List profiles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			profiles, err := config.ListProfiles(ctx)
+			if err != nil {
+				return err
+			}
+			return output.Print(ctx, profiles)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "use [name]",
+		Short: "Switch the active profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := config.UseProfile(args[0]); err != nil {
+				return err
+			}
+			return output.Print(ctx, map[string]string{
+				"status":  "ok",
+				"profile": args[0],
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "copy [from] [to]",
+		Short: "Create a profile from an existing one",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := config.CopyProfile(args[0], args[1]); err != nil {
+				return err
+			}
+			return output.Print(ctx, map[string]string{
+				"status":  "ok",
+				"from":    args[0],
+				"profile": args[1],
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "delete [name]",
+		Short: "Delete a profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := config.DeleteProfile(args[0]); err != nil {
+				return err
+			}
+			return output.Print(ctx, map[string]string{
+				"status":  "ok",
+				"profile": args[0],
+			})
+		},
+	})
+
 	return cmd
 }
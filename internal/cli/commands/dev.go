@@ -3,22 +3,24 @@ package commands
 import (
 	"github.com/spf13/cobra"
 
-	"github.com/unstablemind/pocket/internal/dev/cloudflare"
-	"github.com/unstablemind/pocket/internal/dev/database"
-	"github.com/unstablemind/pocket/internal/dev/dockerhub"
-	"github.com/unstablemind/pocket/internal/dev/gist"
-	"github.com/unstablemind/pocket/internal/dev/github"
-	"github.com/unstablemind/pocket/internal/dev/gitlab"
-	"github.com/unstablemind/pocket/internal/dev/jira"
-	"github.com/unstablemind/pocket/internal/dev/kubernetes"
-	"github.com/unstablemind/pocket/internal/dev/linear"
-	"github.com/unstablemind/pocket/internal/dev/npm"
-	"github.com/unstablemind/pocket/internal/dev/prometheus"
-	"github.com/unstablemind/pocket/internal/dev/pypi"
-	"github.com/unstablemind/pocket/internal/dev/redis"
-	"github.com/unstablemind/pocket/internal/dev/s3"
-	"github.com/unstablemind/pocket/internal/dev/sentry"
-	"github.com/unstablemind/pocket/internal/dev/vercel"
+	"github.com/unstablemind/pocket/internal/common/registry"
+
+	_ "github.com/unstablemind/pocket/internal/dev/cloudflare"
+	_ "github.com/unstablemind/pocket/internal/dev/database"
+	_ "github.com/unstablemind/pocket/internal/dev/dockerhub"
+	_ "github.com/unstablemind/pocket/internal/dev/gist"
+	_ "github.com/unstablemind/pocket/internal/dev/github"
+	_ "github.com/unstablemind/pocket/internal/dev/gitlab"
+	_ "github.com/unstablemind/pocket/internal/dev/jira"
+	_ "github.com/unstablemind/pocket/internal/dev/kubernetes"
+	_ "github.com/unstablemind/pocket/internal/dev/linear"
+	_ "github.com/unstablemind/pocket/internal/dev/npm"
+	_ "github.com/unstablemind/pocket/internal/dev/prometheus"
+	_ "github.com/unstablemind/pocket/internal/dev/pypi"
+	_ "github.com/unstablemind/pocket/internal/dev/redis"
+	_ "github.com/unstablemind/pocket/internal/dev/s3"
+	_ "github.com/unstablemind/pocket/internal/dev/sentry"
+	_ "github.com/unstablemind/pocket/internal/dev/vercel"
 )
 
 func NewDevCmd() *cobra.Command {
@@ -29,22 +31,7 @@ func NewDevCmd() *cobra.Command {
 		Long:    `Interact with developer tools: GitHub, GitLab, Jira, Cloudflare, Vercel, Docker Hub, etc.`,
 	}
 
-	cmd.AddCommand(github.NewCmd())
-	cmd.AddCommand(gitlab.NewCmd())
-	cmd.AddCommand(linear.NewCmd())
-	cmd.AddCommand(npm.NewCmd())
-	cmd.AddCommand(pypi.NewCmd())
-	cmd.AddCommand(jira.NewCmd())
-	cmd.AddCommand(cloudflare.NewCmd())
-	cmd.AddCommand(vercel.NewCmd())
-	cmd.AddCommand(dockerhub.NewCmd())
-	cmd.AddCommand(sentry.NewCmd())
-	cmd.AddCommand(redis.NewCmd())
-	cmd.AddCommand(prometheus.NewCmd())
-	cmd.AddCommand(kubernetes.NewCmd())
-	cmd.AddCommand(database.NewCmd())
-	cmd.AddCommand(s3.NewCmd())
-	cmd.AddCommand(gist.NewCmd())
+	registry.Mount(cmd)
 
 	return cmd
 }
@@ -1,33 +1,25 @@
 package commands
 
 import (
+	"context"
 	"encoding/json"
 	"os"
-	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/testutil"
 )
 
 // testConfigPath is set once in TestMain and reused by all tests.
 var testConfigPath string
 
 func TestMain(m *testing.M) {
-	dir, err := os.MkdirTemp("", "integ-test-*")
-	if err != nil {
-		panic(err)
-	}
-	testConfigPath = filepath.Join(dir, "config.json")
-	os.Setenv("POCKET_CONFIG", testConfigPath)
-
-	code := m.Run()
-
-	os.Unsetenv("POCKET_CONFIG")
-	os.RemoveAll(dir)
-	os.Exit(code)
+	testutil.Main(m, func() {
+		testConfigPath = os.Getenv("POCKET_CONFIG")
+	})
 }
 
 func writeTestConfig(t *testing.T, values map[string]string) {
@@ -47,21 +39,21 @@ func clearTestConfig(t *testing.T) {
 }
 
 // configKeysForIntegration returns the config keys needed for an integration
-// to be considered "ready", based on the getIntegrationStatus switch.
+// to be considered "ready", kept apart from the registry as a cross-check.
 var configKeysForIntegration = map[string][]string{
 	"github":       {"github_token"},
 	"gitlab":       {"gitlab_token"},
 	"linear":       {"linear_token"},
 	"twitter":      {"x_client_id"},
 	"reddit":       {"reddit_client_id"},
-	"mastodon":     {"mastodon_token"},
+	"mastodon":     {"mastodon_server", "mastodon_token"},
 	"youtube":      {"youtube_api_key"},
 	"email":        {"email_address", "email_password", "imap_server", "smtp_server"},
 	"slack":        {"slack_token"},
 	"discord":      {"discord_token"},
 	"telegram":     {"telegram_token"},
 	"twilio":       {"twilio_sid", "twilio_token", "twilio_phone"},
-	"calendar":     {"google_cred_path"},
+	"calendar":     {"google_client_id", "google_client_secret", "google_refresh_token"},
 	"notion":       {"notion_token"},
 	"todoist":      {"todoist_token"},
 	"newsapi":      {"newsapi_key"},
@@ -86,21 +78,21 @@ var configKeysForIntegration = map[string][]string{
 }
 
 // TestAllAuthIntegrationsHaveStatusCheck verifies that every integration with
-// AuthNeeded==true has a corresponding case in getIntegrationStatus that
-// returns "needs_setup" (not falling through to the default).
+// AuthNeeded==true returns "needs_setup" while its keys are unset.
 func TestAllAuthIntegrationsHaveStatusCheck(t *testing.T) {
+	ctx := context.Background()
 	clearTestConfig(t)
 	_, err := config.Load()
 	if err != nil {
 		t.Fatalf("load config: %v", err)
 	}
 
-	for _, integ := range allIntegrations {
+	for _, integ := range integrationList(ctx) {
 		if !integ.AuthNeeded {
 			continue
 		}
 
-		status := getIntegrationStatus(integ)
+		status := getIntegrationStatus(ctx, integ)
 		if status != "needs_setup" {
 			t.Errorf("integration %q: expected 'needs_setup' with empty config, got %q", integ.ID, status)
 		}
@@ -110,7 +102,8 @@ func TestAllAuthIntegrationsHaveStatusCheck(t *testing.T) {
 // TestAllAuthIntegrationsReturnReady verifies that when the correct config
 // keys are set, getIntegrationStatus returns "ready" for each auth integration.
 func TestAllAuthIntegrationsReturnReady(t *testing.T) {
-	for _, integ := range allIntegrations {
+	ctx := context.Background()
+	for _, integ := range integrationList(ctx) {
 		if !integ.AuthNeeded {
 			continue
 		}
@@ -133,7 +126,7 @@ func TestAllAuthIntegrationsReturnReady(t *testing.T) {
 			t.Fatalf("load config for %q: %v", integ.ID, err)
 		}
 
-		status := getIntegrationStatus(integ)
+		status := getIntegrationStatus(ctx, integ)
 		if status != "ready" {
 			t.Errorf("integration %q: expected 'ready' with keys %v set, got %q", integ.ID, keys, status)
 		}
@@ -145,18 +138,19 @@ func TestAllAuthIntegrationsReturnReady(t *testing.T) {
 // TestNoAuthIntegrationsReturnNoAuth verifies that integrations with
 // AuthNeeded==false return "no_auth" from getIntegrationStatus.
 func TestNoAuthIntegrationsReturnNoAuth(t *testing.T) {
+	ctx := context.Background()
 	clearTestConfig(t)
 	_, err := config.Load()
 	if err != nil {
 		t.Fatalf("load config: %v", err)
 	}
 
-	for _, integ := range allIntegrations {
+	for _, integ := range integrationList(ctx) {
 		if integ.AuthNeeded {
 			continue
 		}
 
-		status := getIntegrationStatus(integ)
+		status := getIntegrationStatus(ctx, integ)
 		if status != "no_auth" {
 			t.Errorf("integration %q (AuthNeeded=false): expected 'no_auth', got %q", integ.ID, status)
 		}
@@ -164,10 +158,10 @@ func TestNoAuthIntegrationsReturnNoAuth(t *testing.T) {
 }
 
 // TestAuthIntegrationsWithSetupCmdHaveServiceEntry verifies that every
-// integration referencing "pocket setup show X" has a corresponding entry
-// in the services map in setup.go.
+// integration referencing "pocket setup show X" has a setup entry.
 func TestAuthIntegrationsWithSetupCmdHaveServiceEntry(t *testing.T) {
-	for _, integ := range allIntegrations {
+	ctx := context.Background()
+	for _, integ := range integrationList(ctx) {
 		if integ.SetupCmd == "" {
 			continue
 		}
@@ -180,27 +174,28 @@ func TestAuthIntegrationsWithSetupCmdHaveServiceEntry(t *testing.T) {
 		}
 		serviceName := strings.TrimPrefix(integ.SetupCmd, prefix)
 
-		if _, ok := services[serviceName]; !ok {
-			t.Errorf("integration %q: SetupCmd references service %q, but it's not in the services map", integ.ID, serviceName)
+		if _, ok := lookupService(ctx, serviceName); !ok {
+			t.Errorf("integration %q: SetupCmd references service %q, which setup doesn't know", integ.ID, serviceName)
 		}
 	}
 }
 
 // TestSetupServiceKeysAreValidConfigKeys verifies that every KeyInfo.Key
-// in the services map can be round-tripped through config.Set/Get.
+// of a setup entry can be round-tripped through config.Set/Get.
 func TestSetupServiceKeysAreValidConfigKeys(t *testing.T) {
+	ctx := context.Background()
 	clearTestConfig(t)
 
-	for svcName, svc := range services {
+	for svcName, svc := range allServices(ctx) {
 		for _, keyInfo := range svc.Keys {
 			testVal := "test_" + keyInfo.Key
 
-			if err := config.Set(keyInfo.Key, testVal); err != nil {
+			if err := config.Set(ctx, keyInfo.Key, testVal); err != nil {
 				t.Errorf("service %q: config.Set(%q) failed: %v", svcName, keyInfo.Key, err)
 				continue
 			}
 
-			got, err := config.Get(keyInfo.Key)
+			got, err := config.Get(ctx, keyInfo.Key)
 			if err != nil {
 				t.Errorf("service %q: config.Get(%q) failed: %v", svcName, keyInfo.Key, err)
 				continue
@@ -252,6 +247,7 @@ func TestAllCommandGroupsRegistered(t *testing.T) {
 
 // TestFacebookAdsConfigKeys verifies the Facebook Ads config keys round-trip.
 func TestFacebookAdsConfigKeys(t *testing.T) {
+	ctx := context.Background()
 	clearTestConfig(t)
 
 	keys := map[string]string{
@@ -260,13 +256,13 @@ func TestFacebookAdsConfigKeys(t *testing.T) {
 	}
 
 	for k, v := range keys {
-		if err := config.Set(k, v); err != nil {
+		if err := config.Set(ctx, k, v); err != nil {
 			t.Fatalf("config.Set(%q) failed: %v", k, err)
 		}
 	}
 
 	for k, want := range keys {
-		got, err := config.Get(k)
+		got, err := config.Get(ctx, k)
 		if err != nil {
 			t.Fatalf("config.Get(%q) failed: %v", k, err)
 		}
@@ -280,8 +276,9 @@ func TestFacebookAdsConfigKeys(t *testing.T) {
 
 // TestIntegrationIDsAreUnique verifies no duplicate integration IDs exist.
 func TestIntegrationIDsAreUnique(t *testing.T) {
-	seen := make(map[string]bool, len(allIntegrations))
-	for _, integ := range allIntegrations {
+	ctx := context.Background()
+	seen := make(map[string]bool)
+	for _, integ := range integrationList(ctx) {
 		if seen[integ.ID] {
 			t.Errorf("duplicate integration ID: %q", integ.ID)
 		}
@@ -292,7 +289,8 @@ func TestIntegrationIDsAreUnique(t *testing.T) {
 // TestAllAuthIntegrationsHaveSetupCmd verifies that every auth-required
 // integration has a SetupCmd pointing to setup instructions.
 func TestAllAuthIntegrationsHaveSetupCmd(t *testing.T) {
-	for _, integ := range allIntegrations {
+	ctx := context.Background()
+	for _, integ := range integrationList(ctx) {
 		if !integ.AuthNeeded {
 			continue
 		}
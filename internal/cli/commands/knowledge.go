@@ -3,9 +3,11 @@ package commands
 import (
 	"github.com/spf13/cobra"
 
-	"github.com/unstablemind/pocket/internal/knowledge/dictionary"
-	"github.com/unstablemind/pocket/internal/knowledge/stackexchange"
-	"github.com/unstablemind/pocket/internal/knowledge/wikipedia"
+	"github.com/unstablemind/pocket/internal/common/registry"
+
+	_ "github.com/unstablemind/pocket/internal/knowledge/dictionary"
+	_ "github.com/unstablemind/pocket/internal/knowledge/stackexchange"
+	_ "github.com/unstablemind/pocket/internal/knowledge/wikipedia"
 )
 
 func NewKnowledgeCmd() *cobra.Command {
@@ -16,9 +18,7 @@ func NewKnowledgeCmd() *cobra.Command {
 		Long:    `Access knowledge bases: Wikipedia, dictionaries, etc.`,
 	}
 
-	cmd.AddCommand(wikipedia.NewCmd())
-	cmd.AddCommand(stackexchange.NewCmd())
-	cmd.AddCommand(dictionary.NewCmd())
+	registry.Mount(cmd)
 
 	return cmd
 }
@@ -3,9 +3,11 @@ package commands
 import (
 	"github.com/spf13/cobra"
 
-	amazonsp "github.com/unstablemind/pocket/internal/marketing/amazon-sp"
-	facebookads "github.com/unstablemind/pocket/internal/marketing/facebook-ads"
-	"github.com/unstablemind/pocket/internal/marketing/shopify"
+	"github.com/unstablemind/pocket/internal/common/registry"
+
+	_ "github.com/unstablemind/pocket/internal/marketing/amazon-sp"
+	_ "github.com/unstablemind/pocket/internal/marketing/facebook-ads"
+	_ "github.com/unstablemind/pocket/internal/marketing/shopify"
 )
 
 func NewMarketingCmd() *cobra.Command {
@@ -16,9 +18,7 @@ func NewMarketingCmd() *cobra.Command {
 		Long:    "Marketing tools: Facebook Ads, Amazon SP-API, and Shopify.",
 	}
 
-	cmd.AddCommand(facebookads.NewCmd())
-	cmd.AddCommand(amazonsp.NewCmd())
-	cmd.AddCommand(shopify.NewCmd())
+	registry.Mount(cmd)
 
 	return cmd
 }
@@ -3,9 +3,11 @@ package commands
 import (
 	"github.com/spf13/cobra"
 
-	"github.com/unstablemind/pocket/internal/news/feeds"
-	"github.com/unstablemind/pocket/internal/news/hackernews"
-	"github.com/unstablemind/pocket/internal/news/newsapi"
+	"github.com/unstablemind/pocket/internal/common/registry"
+
+	_ "github.com/unstablemind/pocket/internal/news/feeds"
+	_ "github.com/unstablemind/pocket/internal/news/hackernews"
+	_ "github.com/unstablemind/pocket/internal/news/newsapi"
 )
 
 func NewNewsCmd() *cobra.Command {
@@ -16,9 +18,7 @@ func NewNewsCmd() *cobra.Command {
 		Long:    `Fetch news and content: RSS feeds, Hacker News, NewsAPI, etc.`,
 	}
 
-	cmd.AddCommand(feeds.NewCmd())
-	cmd.AddCommand(hackernews.NewCmd())
-	cmd.AddCommand(newsapi.NewCmd())
+	registry.Mount(cmd)
 
 	return cmd
 }
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"context"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/plugin"
+)
+
+// pluginIntegrations describes the loaded plugins as integrations
+func pluginIntegrations(ctx context.Context) []Integration {
+	var all []Integration
+	for _, p := range plugin.All(ctx) {
+		if p.Error != "" {
+			continue
+		}
+		integ := Integration{
+			ID:          p.Name,
+			Name:        p.DisplayName(),
+			Group:       p.Group,
+			Description: p.Description,
+			Auth:        string(registry.AuthNone),
+			Commands:    p.CommandPaths(),
+			status:      p.Status,
+		}
+		for _, k := range p.Config {
+			if k.Required {
+				integ.AuthNeeded, integ.Auth = true, string(registry.AuthKey)
+			}
+		}
+		if len(p.Config) > 0 {
+			integ.SetupCmd = "pocket setup show " + p.Name
+		}
+		all = append(all, integ)
+	}
+	return all
+}
+
+// pluginService describes the config keys of a loaded plugin for setup
+func pluginService(p *plugin.Plugin) ServiceInfo {
+	svc := ServiceInfo{
+		Service:     p.Name,
+		Name:        p.DisplayName(),
+		SetupGuide:  p.SetupGuide,
+		TestCommand: p.TestCommand,
+	}
+	for _, k := range p.Config {
+		svc.Keys = append(svc.Keys, KeyInfo{Key: k.Key, Description: k.Desc, Required: k.Required, Example: k.Example})
+	}
+	return svc
+}
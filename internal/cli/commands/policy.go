@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/policy"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+func NewPolicyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "policy",
+		Short: "Command policy (allow, deny, confirm)",
+		Long:  `Inspect the policy that allows, denies or requires confirmation for commands by class (read, write, destructive), and approve gated commands.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "show",
+		Short: "Show the policy file and its rules",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			p, err := policy.Load()
+			if err != nil {
+				return output.PrintErr(ctx, "policy_invalid", err, map[string]string{"path": policy.Path()})
+			}
+			return output.Print(ctx, map[string]any{
+				"path":   policy.Path(),
+				"policy": p,
+			})
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "check -- [command...]",
+		Short: "Show how the policy treats a command line, without running it",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			target, targetArgs, err := resolveInvocation(cmd.Root(), args)
+			if err != nil {
+				return output.PrintErr(ctx, "invalid_command", err, nil)
+			}
+			p, err := policy.Load()
+			if err != nil {
+				return output.PrintErr(ctx, "policy_invalid", err, map[string]string{"path": policy.Path()})
+			}
+			return output.Print(ctx, p.Evaluate(target, targetArgs))
+		},
+	})
+
+	var ttl time.Duration
+	approveCmd := &cobra.Command{
+		Use:   "approve -- [command...]",
+		Short: "Approve a gated command line and print a confirmation token",
+		Long:  "Approve one exact command line (arguments included) and print a token to pass with --confirm or POCKET_CONFIRM. Must be run by a person in a terminal.",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			target, targetArgs, err := resolveInvocation(cmd.Root(), args)
+			if err != nil {
+				return output.PrintErr(ctx, "invalid_command", err, nil)
+			}
+			if !policy.Interactive(ctx) {
+				return output.PrintError(ctx, "not_interactive", "approve must be run by a person in a terminal", nil)
+			}
+
+			line := policy.Invocation(target, targetArgs)
+			if !policy.Ask(fmt.Sprintf("Approve pocket %s for %s?", line, ttl)) {
+				return output.PrintError(ctx, "not_approved", "approval declined", nil)
+			}
+			token, err := policy.Approve(policy.Fingerprint(target, targetArgs), ttl)
+			if err != nil {
+				return output.PrintErr(ctx, "approve_failed", err, nil)
+			}
+			return output.Print(ctx, map[string]string{
+				"command":    line,
+				"token":      token,
+				"expires_at": time.Now().Add(ttl).UTC().Format(time.RFC3339),
+			})
+		},
+	}
+	approveCmd.Flags().DurationVar(&ttl, "ttl", policy.DefaultTokenTTL, "How long the token stays valid")
+	cmd.AddCommand(approveCmd)
+
+	return cmd
+}
+
+// resolveInvocation finds the command a command line would run and parses
+// its flags, leaving the positional arguments
+func resolveInvocation(root *cobra.Command, words []string) (*cobra.Command, []string, error) {
+	if len(words) > 0 && words[0] == root.Name() {
+		words = words[1:]
+	}
+	target, rest, err := root.Find(words)
+	if err != nil {
+		return nil, nil, err
+	}
+	if target == root || !target.Runnable() {
+		return nil, nil, fmt.Errorf("not a runnable command: %v", words)
+	}
+	if err := target.ParseFlags(rest); err != nil {
+		return nil, nil, err
+	}
+	return target, target.Flags().Args(), nil
+}
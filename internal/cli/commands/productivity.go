@@ -3,14 +3,16 @@ package commands
 import (
 	"github.com/spf13/cobra"
 
-	"github.com/unstablemind/pocket/internal/productivity/calendar"
-	"github.com/unstablemind/pocket/internal/productivity/gdrive"
-	"github.com/unstablemind/pocket/internal/productivity/gsheets"
-	"github.com/unstablemind/pocket/internal/productivity/logseq"
-	"github.com/unstablemind/pocket/internal/productivity/notion"
-	"github.com/unstablemind/pocket/internal/productivity/obsidian"
-	"github.com/unstablemind/pocket/internal/productivity/todoist"
-	"github.com/unstablemind/pocket/internal/productivity/trello"
+	"github.com/unstablemind/pocket/internal/common/registry"
+
+	_ "github.com/unstablemind/pocket/internal/productivity/calendar"
+	_ "github.com/unstablemind/pocket/internal/productivity/gdrive"
+	_ "github.com/unstablemind/pocket/internal/productivity/gsheets"
+	_ "github.com/unstablemind/pocket/internal/productivity/logseq"
+	_ "github.com/unstablemind/pocket/internal/productivity/notion"
+	_ "github.com/unstablemind/pocket/internal/productivity/obsidian"
+	_ "github.com/unstablemind/pocket/internal/productivity/todoist"
+	_ "github.com/unstablemind/pocket/internal/productivity/trello"
 )
 
 func NewProductivityCmd() *cobra.Command {
@@ -21,14 +23,7 @@ func NewProductivityCmd() *cobra.Command {
 		Long:    `Interact with productivity tools: Calendar, Notion, Todoist, Trello, etc.`,
 	}
 
-	cmd.AddCommand(calendar.NewCmd())
-	cmd.AddCommand(logseq.NewCmd())
-	cmd.AddCommand(notion.NewCmd())
-	cmd.AddCommand(obsidian.NewCmd())
-	cmd.AddCommand(todoist.NewCmd())
-	cmd.AddCommand(trello.NewCmd())
-	cmd.AddCommand(gsheets.NewCmd())
-	cmd.AddCommand(gdrive.NewCmd())
+	registry.Mount(cmd)
 
 	return cmd
 }
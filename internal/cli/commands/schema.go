@@ -0,0 +1,191 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/schema"
+	"github.com/unstablemind/pocket/internal/dev/cloudflare"
+	"github.com/unstablemind/pocket/internal/dev/database"
+	"github.com/unstablemind/pocket/internal/dev/dockerhub"
+	"github.com/unstablemind/pocket/internal/dev/gist"
+	"github.com/unstablemind/pocket/internal/dev/github"
+	"github.com/unstablemind/pocket/internal/dev/jira"
+	"github.com/unstablemind/pocket/internal/dev/kubernetes"
+	"github.com/unstablemind/pocket/internal/dev/npm"
+	"github.com/unstablemind/pocket/internal/dev/prometheus"
+	"github.com/unstablemind/pocket/internal/dev/pypi"
+	"github.com/unstablemind/pocket/internal/dev/redis"
+	"github.com/unstablemind/pocket/internal/dev/s3"
+	"github.com/unstablemind/pocket/internal/dev/sentry"
+	"github.com/unstablemind/pocket/internal/dev/vercel"
+	"github.com/unstablemind/pocket/internal/knowledge/dictionary"
+	"github.com/unstablemind/pocket/internal/knowledge/stackexchange"
+	"github.com/unstablemind/pocket/internal/knowledge/wikipedia"
+	"github.com/unstablemind/pocket/internal/marketing/shopify"
+	"github.com/unstablemind/pocket/internal/news/feeds"
+	"github.com/unstablemind/pocket/internal/news/hackernews"
+	"github.com/unstablemind/pocket/internal/utility/currency"
+	"github.com/unstablemind/pocket/internal/utility/holidays"
+	"github.com/unstablemind/pocket/internal/utility/weather"
+)
+
+// CmdSchema is the machine-readable contract of a single command
+type CmdSchema struct {
+	Command string         `json:"cmd"`
+	Desc    string         `json:"desc"`
+	Input   *schema.Schema `json:"input"`
+	Output  *schema.Schema `json:"output,omitempty"`
+}
+
+// outputTypes maps command paths to a zero value of their Data payload.
+// Commands missing here still get an input schema; their output is omitted.
+var outputTypes = map[string]any{
+	// Dev
+	"pocket dev github repos":         []github.Repo{},
+	"pocket dev github repo":          github.Repo{},
+	"pocket dev github issues":        []github.Issue{},
+	"pocket dev github issue":         github.Issue{},
+	"pocket dev github prs":           []github.PR{},
+	"pocket dev github pr":            github.PR{},
+	"pocket dev github notifications": []github.Notification{},
+	"pocket dev npm search":           []npm.SearchResult{},
+	"pocket dev npm info":             npm.Package{},
+	"pocket dev pypi info":            pypi.Package{},
+	"pocket dev pypi versions":        []pypi.Version{},
+	"pocket dev db query":             database.QueryResult{},
+	"pocket dev db schema":            database.SchemaResult{},
+	"pocket dev db tables":            database.TablesResult{},
+	"pocket dev s3 buckets":           s3.BucketsResult{},
+	"pocket dev s3 ls":                s3.ListResult{},
+	"pocket dev s3 get":               s3.DownloadResult{},
+	"pocket dev s3 put":               s3.UploadResult{},
+	"pocket dev s3 presign":           s3.PresignResult{},
+	"pocket dev sentry projects":      []sentry.Project{},
+	"pocket dev sentry issues":        []sentry.Issue{},
+	"pocket dev sentry issue":         sentry.IssueDetail{},
+	"pocket dev sentry events":        []sentry.Event{},
+	"pocket dev jira issues":          []jira.Issue{},
+	"pocket dev jira issue":           jira.Issue{},
+	"pocket dev jira projects":        []jira.Project{},
+	"pocket dev dockerhub search":     []dockerhub.SearchResult{},
+	"pocket dev dockerhub image":      dockerhub.Image{},
+	"pocket dev dockerhub tags":       []dockerhub.Tag{},
+	"pocket dev dockerhub inspect":    dockerhub.Manifest{},
+	"pocket dev gist list":            []gist.Summary{},
+	"pocket dev gist get":             gist.Detail{},
+	"pocket dev gist create":          gist.Created{},
+	"pocket dev redis get":            redis.Value{},
+	"pocket dev redis set":            redis.SetResult{},
+	"pocket dev redis del":            redis.DelResult{},
+	"pocket dev redis keys":           redis.Keys{},
+	"pocket dev redis info":           redis.Info{},
+	"pocket dev prometheus query":     prometheus.QueryResult{},
+	"pocket dev prometheus range":     prometheus.RangeResult{},
+	"pocket dev prometheus alerts":    []prometheus.Alert{},
+	"pocket dev prometheus targets":   []prometheus.Target{},
+	"pocket dev vercel projects":      []vercel.Project{},
+	"pocket dev vercel project":       vercel.Project{},
+	"pocket dev vercel deployments":   []vercel.Deployment{},
+	"pocket dev vercel deployment":    vercel.Deployment{},
+	"pocket dev vercel domains":       []vercel.Domain{},
+	"pocket dev vercel env":           []vercel.EnvVar{},
+	"pocket dev cloudflare zones":     []cloudflare.Zone{},
+	"pocket dev cloudflare zone":      cloudflare.Zone{},
+	"pocket dev cloudflare dns":       []cloudflare.DNSRecord{},
+	"pocket dev cloudflare analytics": cloudflare.Analytics{},
+	"pocket dev kube pods":            []kubernetes.Pod{},
+	"pocket dev kube logs":            kubernetes.LogResult{},
+	"pocket dev kube deployments":     []kubernetes.Deployment{},
+	"pocket dev kube services":        []kubernetes.Service{},
+	"pocket dev kube describe":        kubernetes.DescribeResult{},
+
+	// News
+	"pocket news hackernews top":  []hackernews.Story{},
+	"pocket news hackernews new":  []hackernews.Story{},
+	"pocket news hackernews best": []hackernews.Story{},
+	"pocket news hackernews ask":  []hackernews.Story{},
+	"pocket news hackernews show": []hackernews.Story{},
+	"pocket news feeds fetch":     feeds.FeedInfo{},
+	"pocket news feeds list":      []feeds.SavedFeed{},
+	"pocket news feeds read":      feeds.FeedInfo{},
+
+	// Knowledge
+	"pocket knowledge wikipedia search":       []wikipedia.SearchResult{},
+	"pocket knowledge wikipedia summary":      wikipedia.Article{},
+	"pocket knowledge wikipedia article":      wikipedia.Article{},
+	"pocket knowledge dictionary define":      dictionary.Definition{},
+	"pocket knowledge stackexchange search":   []stackexchange.Question{},
+	"pocket knowledge stackexchange question": stackexchange.Question{},
+	"pocket knowledge stackexchange answers":  []stackexchange.Answer{},
+
+	// Marketing
+	"pocket marketing shopify shop":            shopify.Shop{},
+	"pocket marketing shopify orders":          []shopify.Order{},
+	"pocket marketing shopify order":           shopify.Order{},
+	"pocket marketing shopify products":        []shopify.Product{},
+	"pocket marketing shopify product":         shopify.Product{},
+	"pocket marketing shopify customers":       []shopify.Customer{},
+	"pocket marketing shopify customer-search": []shopify.Customer{},
+	"pocket marketing shopify inventory":       []shopify.InventoryLevel{},
+	"pocket marketing shopify inventory-set":   shopify.InventoryLevel{},
+
+	// Utility
+	"pocket utility weather now":        weather.Weather{},
+	"pocket utility weather forecast":   weather.Weather{},
+	"pocket utility currency rate":      currency.ExchangeRate{},
+	"pocket utility currency convert":   currency.Conversion{},
+	"pocket utility currency list":      []currency.Currency{},
+	"pocket utility holidays list":      holidays.HolidayList{},
+	"pocket utility holidays next":      holidays.UpcomingHolidays{},
+	"pocket utility holidays countries": []holidays.Country{},
+}
+
+// buildSchemas walks the live cobra tree below root and describes every
+// runnable command, optionally limited to one top-level group.
+func buildSchemas(root *cobra.Command, group string) []CmdSchema {
+	var result []CmdSchema
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, sub := range cmd.Commands() {
+			if sub.Hidden || sub.Name() == "help" || sub.Name() == "completion" {
+				continue
+			}
+			if sub.Runnable() {
+				result = append(result, newCmdSchema(sub))
+			}
+			walk(sub)
+		}
+	}
+
+	for _, top := range root.Commands() {
+		if group != "" && top.Name() != group {
+			continue
+		}
+		if top.Runnable() && group == "" {
+			result = append(result, newCmdSchema(top))
+		}
+		walk(top)
+	}
+
+	return result
+}
+
+func newCmdSchema(cmd *cobra.Command) CmdSchema {
+	path := cmd.CommandPath()
+
+	input := schema.Input(cmd)
+	input.Schema = schema.Draft
+
+	cs := CmdSchema{
+		Command: path,
+		Desc:    cmd.Short,
+		Input:   input,
+	}
+
+	if v, ok := outputTypes[path]; ok {
+		cs.Output = schema.FromValue(v)
+		cs.Output.Schema = schema.Draft
+	}
+
+	return cs
+}
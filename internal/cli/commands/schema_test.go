@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func newSchemaTestRoot() *cobra.Command {
+	root := &cobra.Command{Use: "pocket"}
+	root.AddCommand(NewCommandsCmd())
+	root.AddCommand(NewDevCmd())
+	root.AddCommand(NewNewsCmd())
+	root.AddCommand(NewKnowledgeCmd())
+	root.AddCommand(NewUtilityCmd())
+	root.AddCommand(NewMarketingCmd())
+	return root
+}
+
+// TestOutputTypesMatchCommands fails when an outputTypes entry points at a
+// command path that no longer exists in the cobra tree.
+func TestOutputTypesMatchCommands(t *testing.T) {
+	schemas := buildSchemas(newSchemaTestRoot(), "")
+
+	found := make(map[string]bool, len(schemas))
+	for _, s := range schemas {
+		found[s.Command] = true
+	}
+
+	for path := range outputTypes {
+		if !found[path] {
+			t.Errorf("outputTypes entry %q does not match any command", path)
+		}
+	}
+}
+
+func TestBuildSchemasGroupFilter(t *testing.T) {
+	schemas := buildSchemas(newSchemaTestRoot(), "dev")
+	if len(schemas) == 0 {
+		t.Fatal("expected dev schemas")
+	}
+
+	var issues *CmdSchema
+	for i := range schemas {
+		if schemas[i].Command == "pocket commands" {
+			t.Error("group filter should exclude top-level commands")
+		}
+		if schemas[i].Command == "pocket dev github issues" {
+			issues = &schemas[i]
+		}
+	}
+	if issues == nil {
+		t.Fatal("missing pocket dev github issues")
+	}
+
+	limit := issues.Input.Properties["limit"]
+	if limit == nil || limit.Type != "integer" || limit.Default != 20 {
+		t.Errorf("unexpected limit flag schema: %+v", limit)
+	}
+	if issues.Output == nil || issues.Output.Type != "array" || issues.Output.Items.Properties["number"] == nil {
+		t.Errorf("unexpected output schema: %+v", issues.Output)
+	}
+
+	if len(buildSchemas(newSchemaTestRoot(), "nope")) != 0 {
+		t.Error("expected no schemas for unknown group")
+	}
+}
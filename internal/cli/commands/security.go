@@ -3,10 +3,12 @@ package commands
 import (
 	"github.com/spf13/cobra"
 
-	"github.com/unstablemind/pocket/internal/security/crtsh"
-	"github.com/unstablemind/pocket/internal/security/hibp"
-	"github.com/unstablemind/pocket/internal/security/shodan"
-	"github.com/unstablemind/pocket/internal/security/virustotal"
+	"github.com/unstablemind/pocket/internal/common/registry"
+
+	_ "github.com/unstablemind/pocket/internal/security/crtsh"
+	_ "github.com/unstablemind/pocket/internal/security/hibp"
+	_ "github.com/unstablemind/pocket/internal/security/shodan"
+	_ "github.com/unstablemind/pocket/internal/security/virustotal"
 )
 
 func NewSecurityCmd() *cobra.Command {
@@ -17,10 +19,7 @@ func NewSecurityCmd() *cobra.Command {
 		Long:    "Security tools: VirusTotal, Shodan, Certificate Transparency, Have I Been Pwned.",
 	}
 
-	cmd.AddCommand(virustotal.NewCmd())
-	cmd.AddCommand(shodan.NewCmd())
-	cmd.AddCommand(crtsh.NewCmd())
-	cmd.AddCommand(hibp.NewCmd())
+	registry.Mount(cmd)
 
 	return cmd
 }
@@ -3,11 +3,13 @@ package commands
 import (
 	"github.com/spf13/cobra"
 
-	"github.com/unstablemind/pocket/internal/social/mastodon"
-	"github.com/unstablemind/pocket/internal/social/reddit"
-	"github.com/unstablemind/pocket/internal/social/spotify"
-	"github.com/unstablemind/pocket/internal/social/twitter"
-	"github.com/unstablemind/pocket/internal/social/youtube"
+	"github.com/unstablemind/pocket/internal/common/registry"
+
+	_ "github.com/unstablemind/pocket/internal/social/mastodon"
+	_ "github.com/unstablemind/pocket/internal/social/reddit"
+	_ "github.com/unstablemind/pocket/internal/social/spotify"
+	_ "github.com/unstablemind/pocket/internal/social/twitter"
+	_ "github.com/unstablemind/pocket/internal/social/youtube"
 )
 
 func NewSocialCmd() *cobra.Command {
@@ -18,11 +20,7 @@ func NewSocialCmd() *cobra.Command {
 		Long:    `Interact with social media platforms: Twitter/X, Reddit, Mastodon, YouTube, etc.`,
 	}
 
-	cmd.AddCommand(twitter.NewCmd())
-	cmd.AddCommand(reddit.NewCmd())
-	cmd.AddCommand(mastodon.NewCmd())
-	cmd.AddCommand(youtube.NewCmd())
-	cmd.AddCommand(spotify.NewCmd())
+	registry.Mount(cmd)
 
 	return cmd
 }
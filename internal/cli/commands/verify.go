@@ -0,0 +1,687 @@
+package commands
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/emersion/go-imap/client"
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/transport"
+	"github.com/unstablemind/pocket/internal/dev/gitlab"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// Live credential states reported by setup verify
+const (
+	verifyValid         = "valid"
+	verifyExpired       = "expired"
+	verifyInvalid       = "invalid"
+	verifyScope         = "insufficient_scope"
+	verifyNotConfigured = "not_configured"
+	verifyUnreachable   = "unreachable"
+	verifyUnverified    = "unverified" // keys are set but there is no live probe
+)
+
+// verifyTimeout bounds each probe
+const verifyTimeout = 15 * time.Second
+
+// VerifyResult is the outcome of one live credential probe
+type VerifyResult struct {
+	Service       string   `json:"service"`
+	Name          string   `json:"name"`
+	Status        string   `json:"status"`
+	Account       string   `json:"account,omitempty"`
+	Scopes        []string `json:"scopes,omitempty"`
+	MissingScopes []string `json:"missing_scopes,omitempty"`
+	ExpiresAt     string   `json:"expires_at,omitempty"`
+	Error         string   `json:"error,omitempty"`
+	LatencyMs     int64    `json:"latency_ms,omitempty"`
+}
+
+// probeURLs are the API roots probed; replaced in tests
+var probeURLs = map[string]string{
+	"github":     "https://api.github.com",
+	"slack":      "https://slack.com/api",
+	"telegram":   "https://api.telegram.org",
+	"discord":    "https://discord.com/api/v10",
+	"notion":     "https://api.notion.com/v1",
+	"linear":     "https://api.linear.app/graphql",
+	"todoist":    "https://api.todoist.com/api/v1",
+	"cloudflare": "https://api.cloudflare.com/client/v4",
+	"vercel":     "https://api.vercel.com",
+	"sentry":     "https://sentry.io/api/0",
+	"twitter":    "https://api.x.com/2",
+	"reddit":     "https://oauth.reddit.com",
+	"trello":     "https://api.trello.com/1",
+	"twilio":     "https://api.twilio.com/2010-04-01",
+}
+
+// probes run a lightweight authenticated request per service
+var probes = map[string]func(ctx context.Context, r *VerifyResult){
+	"github":     probeGitHub,
+	"gitlab":     probeGitLab,
+	"slack":      probeSlack,
+	"telegram":   probeTelegram,
+	"discord":    probeDiscord,
+	"email":      probeIMAP,
+	"notion":     probeNotion,
+	"linear":     probeLinear,
+	"todoist":    probeTodoist,
+	"cloudflare": probeCloudflare,
+	"vercel":     probeVercel,
+	"sentry":     probeSentry,
+	"mastodon":   probeMastodon,
+	"twitter":    probeTwitter,
+	"reddit":     probeReddit,
+	"jira":       probeJira,
+	"trello":     probeTrello,
+	"twilio":     probeTwilio,
+	"amazon-sp":  probeAmazonSP,
+}
+
+func newSetupVerifyCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "verify [service]",
+		Short: "Check credentials against each service's API",
+		Long:  "Run a lightweight authenticated request per service, in parallel, and report valid, expired, invalid or insufficient_scope. Without arguments every configured service is checked; --all also lists unconfigured ones.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if _, err := config.Load(); err != nil {
+				return output.PrintErr(ctx, "config_error", err, nil)
+			}
+
+			var names []string
+			if len(args) == 1 {
+				if _, ok := lookupService(ctx, args[0]); !ok {
+					return output.PrintError(ctx, "unknown_service", "Unknown service: "+args[0], nil)
+				}
+				names = []string{args[0]}
+			} else {
+				for name, svc := range allServices(ctx) {
+					if all || getServiceStatus(ctx, &svc).Status == statusReady {
+						names = append(names, name)
+					}
+				}
+			}
+
+			results := verifyServices(cmd.Context(), names)
+			if len(args) == 1 {
+				return output.Print(ctx, results[0])
+			}
+			return output.Print(ctx, results)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "Also report services that are not configured")
+
+	return cmd
+}
+
+// verifyServices probes the named services in parallel, sorted by name
+func verifyServices(ctx context.Context, names []string) []VerifyResult {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	results := make([]VerifyResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = verifyService(ctx, name)
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Service < results[j].Service
+	})
+	return results
+}
+
+func verifyService(ctx context.Context, name string) VerifyResult {
+	svc, _ := lookupService(ctx, name)
+	r := VerifyResult{Service: name, Name: svc.Name}
+
+	if status := getServiceStatus(ctx, &svc); status.Status != statusReady {
+		r.Status = verifyNotConfigured
+		r.Error = "run: pocket setup show " + name
+		return r
+	}
+
+	probe, ok := probes[name]
+	if !ok {
+		r.Status = verifyUnverified
+		return r
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+	start := time.Now()
+	probe(ctx, &r)
+	r.LatencyMs = time.Since(start).Milliseconds()
+	return r
+}
+
+// probeJSON sends req and decodes a JSON response into v, setting r's
+// status for auth failures. It reports whether the request succeeded.
+func probeJSON(req *http.Request, v any, r *VerifyResult) (*http.Response, bool) {
+	return probeJSONWith(transport.New(0), req, v, r)
+}
+
+// probeJSONWith is probeJSON through client, for services with their
+// own TLS settings
+func probeJSONWith(client *http.Client, req *http.Request, v any, r *VerifyResult) (*http.Response, bool) {
+	resp, err := client.Do(req)
+	if err != nil {
+		// Drop the URL, which can carry the token (Telegram, Trello)
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		r.Status = verifyUnreachable
+		r.Error = err.Error()
+		return nil, false
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized:
+		r.Status = verifyInvalid
+		r.Error = "credentials rejected (HTTP 401)"
+		return resp, false
+	case resp.StatusCode == http.StatusForbidden:
+		r.Status = verifyScope
+		r.Error = "access denied (HTTP 403)"
+		return resp, false
+	case resp.StatusCode >= 300:
+		r.Status = verifyUnreachable
+		r.Error = "unexpected HTTP " + fmt.Sprint(resp.StatusCode)
+		return resp, false
+	}
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			r.Status = verifyUnreachable
+			r.Error = "invalid response: " + err.Error()
+			return resp, false
+		}
+	}
+	r.Status = verifyValid
+	return resp, true
+}
+
+func newProbeRequest(ctx context.Context, method, target string) *http.Request {
+	req, _ := http.NewRequestWithContext(ctx, method, target, http.NoBody)
+	req.Header.Set("Accept", "application/json")
+	// Probes must see the live answer, never a cached one
+	req.Header.Set("Cache-Control", "no-cache")
+	return req
+}
+
+func configValue(ctx context.Context, key string) string {
+	v, _ := config.Get(ctx, key)
+	return v
+}
+
+// requireScopes marks r insufficient when granted lacks any of required
+func requireScopes(r *VerifyResult, granted, required []string) {
+	r.Scopes = granted
+	for _, s := range required {
+		if !slices.Contains(granted, s) {
+			r.MissingScopes = append(r.MissingScopes, s)
+		}
+	}
+	if len(r.MissingScopes) > 0 && r.Status == verifyValid {
+		r.Status = verifyScope
+	}
+}
+
+func splitScopes(header string) []string {
+	var scopes []string
+	for _, s := range strings.Split(header, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// setExpiry records a known token expiry and downgrades r if it has passed
+func setExpiry(r *VerifyResult, expiry time.Time) {
+	if expiry.IsZero() {
+		return
+	}
+	r.ExpiresAt = expiry.UTC().Format(time.RFC3339)
+	if time.Now().After(expiry) && (r.Status == verifyValid || r.Status == verifyInvalid) {
+		r.Status = verifyExpired
+	}
+}
+
+// parseExpiry accepts the date formats services use for token expiry
+func parseExpiry(s string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700", "2006-01-02"} {
+		if t, err := time.Parse(layout, strings.TrimSpace(s)); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func probeGitHub(ctx context.Context, r *VerifyResult) {
+	req := newProbeRequest(ctx, http.MethodGet, probeURLs["github"]+"/user")
+	req.Header.Set("Authorization", "Bearer "+configValue(ctx, "github_token"))
+
+	var user struct {
+		Login string `json:"login"`
+	}
+	resp, ok := probeJSON(req, &user, r)
+	if resp == nil {
+		return
+	}
+	if exp := resp.Header.Get("GitHub-Authentication-Token-Expiration"); exp != "" {
+		setExpiry(r, parseExpiry(exp))
+	}
+	if !ok {
+		return
+	}
+	r.Account = user.Login
+	// Fine-grained tokens don't report scopes
+	if h, present := resp.Header["X-Oauth-Scopes"]; present {
+		requireScopes(r, splitScopes(strings.Join(h, ",")), []string{"repo", "read:org", "notifications"})
+	}
+}
+
+func probeGitLab(ctx context.Context, r *VerifyResult) {
+	base := configValue(ctx, "gitlab_url")
+	if base == "" {
+		base = "https://gitlab.com"
+	}
+	token := configValue(ctx, "gitlab_token")
+	client, err := gitlab.HTTPClient(ctx)
+	if err != nil {
+		r.Status = verifyUnreachable
+		r.Error = err.Error()
+		return
+	}
+
+	req := newProbeRequest(ctx, http.MethodGet, strings.TrimRight(base, "/")+"/api/v4/personal_access_tokens/self")
+	req.Header.Set("PRIVATE-TOKEN", token)
+	var self struct {
+		Name      string   `json:"name"`
+		Scopes    []string `json:"scopes"`
+		ExpiresAt string   `json:"expires_at"`
+		Revoked   bool     `json:"revoked"`
+	}
+	if _, ok := probeJSONWith(client, req, &self, r); !ok {
+		return
+	}
+	if self.Revoked {
+		r.Status = verifyInvalid
+		r.Error = "token has been revoked"
+	}
+	requireScopes(r, self.Scopes, []string{"api"})
+	setExpiry(r, parseExpiry(self.ExpiresAt))
+
+	req = newProbeRequest(ctx, http.MethodGet, strings.TrimRight(base, "/")+"/api/v4/user")
+	req.Header.Set("PRIVATE-TOKEN", token)
+	var user struct {
+		Username string `json:"username"`
+	}
+	if resp, err := client.Do(req); err == nil {
+		_ = json.NewDecoder(resp.Body).Decode(&user)
+		resp.Body.Close()
+		r.Account = user.Username
+	}
+}
+
+func probeSlack(ctx context.Context, r *VerifyResult) {
+	req := newProbeRequest(ctx, http.MethodPost, probeURLs["slack"]+"/auth.test")
+	req.Header.Set("Authorization", "Bearer "+configValue(ctx, "slack_token"))
+
+	var res struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+		User  string `json:"user"`
+		Team  string `json:"team"`
+	}
+	resp, ok := probeJSON(req, &res, r)
+	if !ok {
+		return
+	}
+	if !res.OK {
+		r.Error = res.Error
+		switch res.Error {
+		case "token_expired", "token_revoked":
+			r.Status = verifyExpired
+		case "missing_scope", "not_allowed_token_type":
+			r.Status = verifyScope
+		default:
+			r.Status = verifyInvalid
+		}
+		return
+	}
+	r.Account = res.User + "@" + res.Team
+	if h := resp.Header.Get("X-OAuth-Scopes"); h != "" {
+		requireScopes(r, splitScopes(h), []string{"channels:read", "chat:write", "users:read"})
+	}
+}
+
+func probeTelegram(ctx context.Context, r *VerifyResult) {
+	req := newProbeRequest(ctx, http.MethodGet, probeURLs["telegram"]+"/bot"+configValue(ctx, "telegram_token")+"/getMe")
+
+	var res struct {
+		OK     bool `json:"ok"`
+		Result struct {
+			Username string `json:"username"`
+		} `json:"result"`
+	}
+	// Telegram answers 404 rather than 401 for malformed tokens
+	if resp, ok := probeJSON(req, &res, r); !ok {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			r.Status = verifyInvalid
+			r.Error = "token not recognized"
+		}
+		return
+	}
+	r.Account = "@" + res.Result.Username
+}
+
+func probeDiscord(ctx context.Context, r *VerifyResult) {
+	req := newProbeRequest(ctx, http.MethodGet, probeURLs["discord"]+"/users/@me")
+	req.Header.Set("Authorization", "Bot "+configValue(ctx, "discord_token"))
+
+	var user struct {
+		Username string `json:"username"`
+	}
+	if _, ok := probeJSON(req, &user, r); ok {
+		r.Account = user.Username
+	}
+}
+
+func probeIMAP(ctx context.Context, r *VerifyResult) {
+	port := configValue(ctx, "imap_port")
+	if port == "" {
+		port = "993"
+	}
+	server := configValue(ctx, "imap_server")
+	addr := configValue(ctx, "email_address")
+
+	dialer := &net.Dialer{}
+	if deadline, ok := ctx.Deadline(); ok {
+		dialer.Deadline = deadline
+	}
+	c, err := client.DialWithDialerTLS(dialer, net.JoinHostPort(server, port), &tls.Config{ServerName: server})
+	if err != nil {
+		r.Status = verifyUnreachable
+		r.Error = err.Error()
+		return
+	}
+	defer func() { _ = c.Logout() }()
+
+	if err := c.Login(addr, configValue(ctx, "email_password")); err != nil {
+		r.Status = verifyInvalid
+		r.Error = err.Error()
+		return
+	}
+	r.Status = verifyValid
+	r.Account = addr
+}
+
+func probeNotion(ctx context.Context, r *VerifyResult) {
+	req := newProbeRequest(ctx, http.MethodGet, probeURLs["notion"]+"/users/me")
+	req.Header.Set("Authorization", "Bearer "+configValue(ctx, "notion_token"))
+	req.Header.Set("Notion-Version", "2022-06-28")
+
+	var user struct {
+		Name string `json:"name"`
+	}
+	if _, ok := probeJSON(req, &user, r); ok {
+		r.Account = user.Name
+	}
+}
+
+func probeLinear(ctx context.Context, r *VerifyResult) {
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, probeURLs["linear"], strings.NewReader(`{"query":"{ viewer { email } }"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("Authorization", configValue(ctx, "linear_token"))
+
+	var res struct {
+		Data struct {
+			Viewer struct {
+				Email string `json:"email"`
+			} `json:"viewer"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	resp, ok := probeJSON(req, &res, r)
+	if resp != nil && resp.StatusCode == http.StatusBadRequest {
+		// Linear reports bad API keys as a GraphQL error with HTTP 400
+		r.Status = verifyInvalid
+		r.Error = "credentials rejected"
+		return
+	}
+	if !ok {
+		return
+	}
+	if len(res.Errors) > 0 {
+		r.Status = verifyInvalid
+		r.Error = res.Errors[0].Message
+		return
+	}
+	r.Account = res.Data.Viewer.Email
+}
+
+func probeTodoist(ctx context.Context, r *VerifyResult) {
+	req := newProbeRequest(ctx, http.MethodGet, probeURLs["todoist"]+"/projects?limit=1")
+	req.Header.Set("Authorization", "Bearer "+configValue(ctx, "todoist_token"))
+	probeJSON(req, nil, r)
+}
+
+func probeCloudflare(ctx context.Context, r *VerifyResult) {
+	req := newProbeRequest(ctx, http.MethodGet, probeURLs["cloudflare"]+"/user/tokens/verify")
+	req.Header.Set("Authorization", "Bearer "+configValue(ctx, "cloudflare_token"))
+
+	var res struct {
+		Result struct {
+			Status    string `json:"status"`
+			ExpiresOn string `json:"expires_on"`
+		} `json:"result"`
+	}
+	if _, ok := probeJSON(req, &res, r); !ok {
+		return
+	}
+	switch res.Result.Status {
+	case "active":
+	case "expired":
+		r.Status = verifyExpired
+	default:
+		r.Status = verifyInvalid
+		r.Error = "token status: " + res.Result.Status
+	}
+	setExpiry(r, parseExpiry(res.Result.ExpiresOn))
+}
+
+func probeVercel(ctx context.Context, r *VerifyResult) {
+	req := newProbeRequest(ctx, http.MethodGet, probeURLs["vercel"]+"/v2/user")
+	req.Header.Set("Authorization", "Bearer "+configValue(ctx, "vercel_token"))
+
+	var res struct {
+		User struct {
+			Username string `json:"username"`
+		} `json:"user"`
+	}
+	if _, ok := probeJSON(req, &res, r); ok {
+		r.Account = res.User.Username
+	}
+}
+
+func probeSentry(ctx context.Context, r *VerifyResult) {
+	req := newProbeRequest(ctx, http.MethodGet, probeURLs["sentry"]+"/")
+	req.Header.Set("Authorization", "Bearer "+configValue(ctx, "sentry_auth_token"))
+
+	var res struct {
+		User *struct {
+			Email string `json:"email"`
+		} `json:"user"`
+		Auth *struct {
+			Scopes []string `json:"scopes"`
+		} `json:"auth"`
+	}
+	if _, ok := probeJSON(req, &res, r); !ok {
+		return
+	}
+	// The API root answers anonymously, so a missing auth block means the
+	// token was not accepted
+	if res.Auth == nil {
+		r.Status = verifyInvalid
+		r.Error = "token not recognized"
+		return
+	}
+	if res.User != nil {
+		r.Account = res.User.Email
+	}
+	requireScopes(r, res.Auth.Scopes, []string{"project:read", "event:read"})
+}
+
+func probeMastodon(ctx context.Context, r *VerifyResult) {
+	server := configValue(ctx, "mastodon_server")
+	if !strings.HasPrefix(server, "http") {
+		server = "https://" + server
+	}
+	req := newProbeRequest(ctx, http.MethodGet, strings.TrimRight(server, "/")+"/api/v1/accounts/verify_credentials")
+	req.Header.Set("Authorization", "Bearer "+configValue(ctx, "mastodon_token"))
+
+	var acct struct {
+		Acct string `json:"acct"`
+	}
+	if _, ok := probeJSON(req, &acct, r); ok {
+		r.Account = "@" + acct.Acct
+	}
+}
+
+// probeOAuthUser checks a user OAuth token that the integration refreshes
+// itself, so an expired token is reported without calling the API
+func probeOAuthUser(ctx context.Context, r *VerifyResult, prefix, authCmd, target string, v any) bool {
+	token := configValue(ctx, prefix+"_access_token")
+	if token == "" {
+		r.Status = verifyInvalid
+		r.Error = "not authorized yet, run: " + authCmd
+		return false
+	}
+	expiry := parseExpiry(configValue(ctx, prefix+"_token_expiry"))
+	if !expiry.IsZero() && time.Now().After(expiry) {
+		setExpiry(r, expiry)
+		r.Status = verifyExpired
+		if configValue(ctx, prefix+"_refresh_token") != "" {
+			r.Error = "access token expired; it is refreshed on next use"
+		} else {
+			r.Error = "access token expired, run: " + authCmd
+		}
+		return false
+	}
+
+	req := newProbeRequest(ctx, http.MethodGet, target)
+	req.Header.Set("Authorization", "Bearer "+token)
+	_, ok := probeJSON(req, v, r)
+	setExpiry(r, expiry)
+	return ok
+}
+
+func probeTwitter(ctx context.Context, r *VerifyResult) {
+	var res struct {
+		Data struct {
+			Username string `json:"username"`
+		} `json:"data"`
+	}
+	if probeOAuthUser(ctx, r, "x", "pocket social twitter auth", probeURLs["twitter"]+"/users/me", &res) {
+		r.Account = "@" + res.Data.Username
+	}
+}
+
+func probeReddit(ctx context.Context, r *VerifyResult) {
+	var res struct {
+		Name string `json:"name"`
+	}
+	if probeOAuthUser(ctx, r, "reddit", "pocket social reddit auth", probeURLs["reddit"]+"/api/v1/me", &res) {
+		r.Account = "u/" + res.Name
+	}
+}
+
+func probeJira(ctx context.Context, r *VerifyResult) {
+	req := newProbeRequest(ctx, http.MethodGet, strings.TrimRight(configValue(ctx, "jira_url"), "/")+"/rest/api/3/myself")
+	auth := base64.StdEncoding.EncodeToString([]byte(configValue(ctx, "jira_email") + ":" + configValue(ctx, "jira_token")))
+	req.Header.Set("Authorization", "Basic "+auth)
+
+	var user struct {
+		EmailAddress string `json:"emailAddress"`
+	}
+	if _, ok := probeJSON(req, &user, r); ok {
+		r.Account = user.EmailAddress
+	}
+}
+
+func probeTrello(ctx context.Context, r *VerifyResult) {
+	q := url.Values{"key": {configValue(ctx, "trello_key")}, "token": {configValue(ctx, "trello_token")}, "fields": {"username"}}
+	req := newProbeRequest(ctx, http.MethodGet, probeURLs["trello"]+"/members/me?"+q.Encode())
+
+	var member struct {
+		Username string `json:"username"`
+	}
+	if _, ok := probeJSON(req, &member, r); ok {
+		r.Account = member.Username
+	}
+}
+
+func probeTwilio(ctx context.Context, r *VerifyResult) {
+	sid := configValue(ctx, "twilio_sid")
+	req := newProbeRequest(ctx, http.MethodGet, probeURLs["twilio"]+"/Accounts/"+url.PathEscape(sid)+".json")
+	req.SetBasicAuth(sid, configValue(ctx, "twilio_token"))
+
+	var acct struct {
+		FriendlyName string `json:"friendly_name"`
+		Status       string `json:"status"`
+	}
+	if _, ok := probeJSON(req, &acct, r); !ok {
+		return
+	}
+	r.Account = acct.FriendlyName
+	if acct.Status != "" && acct.Status != "active" {
+		r.Status = verifyInvalid
+		r.Error = "account " + acct.Status
+	}
+}
+
+// probeAmazonSP reports the cached access token's expiry; the refresh
+// token is only exercised by the integration itself
+func probeAmazonSP(ctx context.Context, r *VerifyResult) {
+	r.Status = verifyUnverified
+	expiry := parseExpiry(configValue(ctx, "amazon_sp_token_expiry"))
+	if expiry.IsZero() {
+		return
+	}
+	r.ExpiresAt = expiry.UTC().Format(time.RFC3339)
+	if time.Now().After(expiry) {
+		r.Error = "access token expired; it is refreshed on next use"
+	}
+}
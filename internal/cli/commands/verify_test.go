@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+// probeServer points a service's probe at handler
+func probeServer(t *testing.T, service string, handler http.HandlerFunc) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	old := probeURLs[service]
+	probeURLs[service] = srv.URL
+	t.Cleanup(func() {
+		srv.Close()
+		probeURLs[service] = old
+	})
+}
+
+func TestVerifyGitHub(t *testing.T) {
+	future := time.Now().Add(48 * time.Hour).UTC().Format("2006-01-02 15:04:05 MST")
+	past := time.Now().Add(-time.Hour).UTC().Format("2006-01-02 15:04:05 MST")
+
+	tests := []struct {
+		name        string
+		status      int
+		scopes      string
+		expiry      string
+		want        string
+		wantMissing []string
+	}{
+		{"valid", http.StatusOK, "repo, read:org, notifications", future, verifyValid, nil},
+		{"missing scope", http.StatusOK, "repo", "", verifyScope, []string{"read:org", "notifications"}},
+		{"rejected", http.StatusUnauthorized, "", "", verifyInvalid, nil},
+		{"expired", http.StatusUnauthorized, "", past, verifyExpired, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeTestConfig(t, map[string]string{"github_token": "ghp_test"})
+			probeServer(t, "github", func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/user" || r.Header.Get("Authorization") != "Bearer ghp_test" {
+					t.Errorf("unexpected request %s with %q", r.URL.Path, r.Header.Get("Authorization"))
+				}
+				if tt.scopes != "" {
+					w.Header().Set("X-OAuth-Scopes", tt.scopes)
+				}
+				if tt.expiry != "" {
+					w.Header().Set("GitHub-Authentication-Token-Expiration", tt.expiry)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"login":"octocat"}`))
+			})
+
+			r := verifyService(context.Background(), "github")
+			if r.Status != tt.want {
+				t.Errorf("expected %s, got %s (%s)", tt.want, r.Status, r.Error)
+			}
+			if !slices.Equal(r.MissingScopes, tt.wantMissing) {
+				t.Errorf("expected missing scopes %v, got %v", tt.wantMissing, r.MissingScopes)
+			}
+			if tt.expiry != "" && r.ExpiresAt == "" {
+				t.Error("expected expires_at from the token expiration header")
+			}
+		})
+	}
+}
+
+func TestVerifySlackErrors(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"ok":true,"user":"bot","team":"acme"}`, verifyValid},
+		{`{"ok":false,"error":"token_expired"}`, verifyExpired},
+		{`{"ok":false,"error":"missing_scope"}`, verifyScope},
+		{`{"ok":false,"error":"invalid_auth"}`, verifyInvalid},
+	}
+
+	for _, tt := range tests {
+		writeTestConfig(t, map[string]string{"slack_token": "xoxb-test"})
+		probeServer(t, "slack", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-OAuth-Scopes", "channels:read,chat:write,users:read")
+			_, _ = w.Write([]byte(tt.body))
+		})
+
+		r := verifyService(context.Background(), "slack")
+		if r.Status != tt.want {
+			t.Errorf("%s: expected %s, got %s", tt.body, tt.want, r.Status)
+		}
+		if tt.want == verifyValid && r.Account != "bot@acme" {
+			t.Errorf("expected account bot@acme, got %q", r.Account)
+		}
+	}
+}
+
+func TestVerifyTelegramUnknownToken(t *testing.T) {
+	writeTestConfig(t, map[string]string{"telegram_token": "123:bad"})
+	probeServer(t, "telegram", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	if r := verifyService(context.Background(), "telegram"); r.Status != verifyInvalid {
+		t.Errorf("expected invalid, got %s", r.Status)
+	}
+}
+
+func TestVerifyWithoutProbe(t *testing.T) {
+	writeTestConfig(t, map[string]string{"youtube_api_key": "key"})
+
+	results := verifyServices(context.Background(), []string{"youtube", "github"})
+	if results[0].Service != "github" || results[0].Status != verifyNotConfigured {
+		t.Errorf("expected github not_configured, got %+v", results[0])
+	}
+	if results[1].Status != verifyUnverified {
+		t.Errorf("expected youtube unverified, got %+v", results[1])
+	}
+}
+
+func TestProbesHaveServices(t *testing.T) {
+	ctx := context.Background()
+	for name := range probes {
+		if _, ok := lookupService(ctx, name); !ok {
+			t.Errorf("probe %q has no setup entry", name)
+		}
+	}
+}
+
+func TestFilterLiveDropsFailingCredentials(t *testing.T) {
+	writeTestConfig(t, map[string]string{"github_token": "revoked", "youtube_api_key": "key"})
+	probeServer(t, "github", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	integs := []Integration{
+		{ID: "github", Status: statusReady},
+		{ID: "youtube", Status: statusReady},
+		{ID: "wikipedia", Status: statusNoAuth},
+	}
+	got := filterLive(context.Background(), integs)
+
+	var ids []string
+	for _, integ := range got {
+		ids = append(ids, integ.ID)
+	}
+	if !slices.Equal(ids, []string{"youtube", "wikipedia"}) {
+		t.Errorf("expected github dropped, got %v", ids)
+	}
+	if got[0].Verified != verifyUnverified {
+		t.Errorf("expected youtube marked unverified, got %q", got[0].Verified)
+	}
+}
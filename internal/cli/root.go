@@ -1,33 +1,94 @@
 package cli
 
 import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/batch"
 	"github.com/unstablemind/pocket/internal/cli/commands"
+	"github.com/unstablemind/pocket/internal/common/audit"
+	"github.com/unstablemind/pocket/internal/common/cache"
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/policy"
+	"github.com/unstablemind/pocket/internal/mcp"
+	"github.com/unstablemind/pocket/internal/plugin"
+	"github.com/unstablemind/pocket/internal/recipe"
+	"github.com/unstablemind/pocket/internal/serve"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
-var (
-	outputFormat string
-	verbose      bool
-)
+// localGroups manage pocket's own files, which --dry-run doesn't cover
+var localGroups = []string{"config", "setup", "cache", "policy"}
+
+func init() {
+	// Run every parent's PersistentPreRunE, not just the nearest, so the
+	// root's setup and policy checks also cover groups with their own hooks
+	cobra.EnableTraverseRunHooks = true
+}
 
 func NewRootCmd() *cobra.Command {
+	// Each tree gets its own flag values, since batch runs several at once
+	var (
+		outputFormat string
+		verbose      bool
+		fields       []string
+		query        string
+		columns      []string
+		profile      string
+		noCache      bool
+		cacheTTL     time.Duration
+		confirm      string
+		dryRun       bool
+	)
+
 	root := &cobra.Command{
 		Use:   "pocket",
 		Short: "Universal CLI for LLM agents",
 		Long:  `Pocket is an all-in-one CLI tool designed for terminal agents to access social media, APIs, email, and more.`,
-		PersistentPreRun: func(cmd *cobra.Command, args []string) {
-			output.SetFormat(outputFormat)
-			output.SetVerbose(verbose)
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			output.SetFormat(ctx, outputFormat)
+			output.SetVerbose(ctx, verbose)
+			output.SetFields(ctx, fields)
+			output.SetColumns(ctx, columns)
+			config.SetProfile(ctx, profile)
+			cache.SetDisabled(ctx, noCache)
+			cache.SetTTL(ctx, cacheTTL)
+			if err := output.SetQuery(ctx, query); err != nil {
+				return output.PrintErr(ctx, "invalid_query", err, nil)
+			}
+			if dryRun {
+				cmdPath := policy.CommandPath(cmd)
+				writes := policy.ClassOf(cmd) != policy.ClassRead
+				group, _, _ := strings.Cut(cmdPath, " ")
+				if writes && slices.Contains(localGroups, group) {
+					return output.PrintError(ctx, "dry_run_unsupported", "--dry-run is not supported by "+cmdPath, nil)
+				}
+				dryrun.Begin(ctx, cmdPath, writes)
+			}
+			return policy.Enforce(cmd, args, confirm)
 		},
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
 
 	// Global flags
-	root.PersistentFlags().StringVarP(&outputFormat, "output", "o", "json", "Output format: json, text, table")
+	root.PersistentFlags().StringVarP(&outputFormat, "output", "o", "json", "Output format: json, text, table, yaml, csv, ndjson, markdown")
 	root.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Verbose output")
+	root.PersistentFlags().StringSliceVar(&fields, "fields", nil, "Only keep these fields in the output (comma-separated, dotted paths)")
+	root.PersistentFlags().StringSliceVar(&columns, "columns", nil, "Table columns to show, in order (comma-separated, dotted paths)")
+	root.PersistentFlags().StringVar(&profile, "profile", "", "Config profile to use (default: $POCKET_PROFILE or the active profile)")
+	root.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Bypass the on-disk response cache")
+	root.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", 0, "Cache lifetime for every API response, overriding per-integration defaults (e.g. 30s, 2h)")
+	root.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Show the requests a write command would send, with secrets redacted, instead of sending them")
+	root.PersistentFlags().StringVar(&confirm, "confirm", "", "Confirmation token from 'pocket policy approve' for commands the policy gates")
+	root.PersistentFlags().StringVar(&query, "query", "", "Filter output with a jq-like expression, e.g. '.[] | select(.score > 100) | {title, url}'")
 
 	// Register command groups
 	root.AddCommand(commands.NewCommandsCmd())
@@ -41,21 +102,33 @@ func NewRootCmd() *cobra.Command {
 	root.AddCommand(commands.NewKnowledgeCmd())
 	root.AddCommand(commands.NewUtilityCmd())
 	root.AddCommand(commands.NewConfigCmd())
+	root.AddCommand(commands.NewCacheCmd())
+	root.AddCommand(commands.NewPolicyCmd())
+	root.AddCommand(commands.NewAuditCmd())
 	root.AddCommand(commands.NewSystemCmd())
 	root.AddCommand(commands.NewSecurityCmd())
 	root.AddCommand(commands.NewMarketingCmd())
+	root.AddCommand(mcp.NewCmd(NewRootCmd))
+	root.AddCommand(batch.NewCmd(NewRootCmd))
+	root.AddCommand(serve.NewCmd(NewRootCmd))
+	root.AddCommand(recipe.NewCmd(NewRootCmd))
+	root.AddCommand(plugin.NewCmd())
+
+	// Last, so plugins can't take a built-in command's name
+	plugin.Mount(root)
 
 	return root
 }
 
 func Execute() error {
+	ctx, inv := audit.Start(batch.NewContext(context.Background()), os.Args[1:])
 	root := NewRootCmd()
-	if err := root.Execute(); err != nil {
-		// Only print if not already printed by the command
-		if !output.IsPrinted(err) {
-			_ = output.PrintError("command_failed", err.Error(), nil)
-		}
-		return err
+	cmd, err := root.ExecuteContextC(ctx)
+	err = dryrun.Finish(ctx, err)
+	// Only print if not already printed by the command
+	if err != nil && !output.IsPrinted(err) {
+		err = output.PrintErr(ctx, "command_failed", err, nil)
 	}
-	return nil
+	inv.Finish(ctx, cmd, err)
+	return err
 }
@@ -0,0 +1,267 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/unstablemind/pocket/internal/common/cassette"
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// Environment variables controlling the audit log
+const (
+	// PathEnv overrides the log location; "off" disables auditing
+	PathEnv = "POCKET_AUDIT_LOG"
+	// ChainEnv set to 1 starts hash-chaining records. Once the log holds a
+	// chained record, later records are always chained.
+	ChainEnv = "POCKET_AUDIT_CHAIN"
+)
+
+const redacted = "REDACTED"
+
+// maxEndpoints bounds the endpoints kept per record
+const maxEndpoints = 100
+
+// Record is one line of the audit log
+type Record struct {
+	Time       time.Time         `json:"time"`
+	Command    string            `json:"command"`
+	Args       []string          `json:"args,omitempty"`
+	Flags      map[string]string `json:"flags,omitempty"`
+	Profile    string            `json:"profile"`
+	Exit       int               `json:"exit"`
+	Error      string            `json:"error,omitempty"`
+	DurationMS int64             `json:"duration_ms"`
+	Endpoints  []Endpoint        `json:"endpoints,omitempty"`
+	PrevHash   string            `json:"prev_hash,omitempty"`
+	Hash       string            `json:"hash,omitempty"`
+}
+
+// Endpoint is a remote service the command talked to. HTTP endpoints
+// omit the query string; others are scheme://host:port.
+type Endpoint struct {
+	Method string `json:"method,omitempty"`
+	URL    string `json:"url"`
+	Status int    `json:"status,omitempty"`
+	Count  int    `json:"count"`
+}
+
+// Invocation collects what one command does until Finish logs it
+type Invocation struct {
+	argv      []string
+	start     time.Time
+	mu        sync.Mutex
+	endpoints []Endpoint
+}
+
+type invocationKey struct{}
+
+// Start begins auditing an invocation of argv (without the program name).
+// Endpoints are noted for requests made with the returned context, so an
+// invocation started inside another, as when `pocket mcp` runs a tool,
+// takes over from it.
+func Start(ctx context.Context, argv []string) (context.Context, *Invocation) {
+	inv := &Invocation{argv: argv, start: time.Now()}
+	return context.WithValue(ctx, invocationKey{}, inv), inv
+}
+
+// Contact notes a non-HTTP connection, e.g. Contact(ctx, "smtp", "mail.example.com:587")
+func Contact(ctx context.Context, scheme, addr string) {
+	note(ctx, "", scheme+"://"+cassette.Redact(ctx, addr), 0)
+}
+
+func note(ctx context.Context, method, url string, status int) {
+	inv, ok := ctx.Value(invocationKey{}).(*Invocation)
+	if !ok {
+		return
+	}
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	for i := range inv.endpoints {
+		e := &inv.endpoints[i]
+		if e.Method == method && e.URL == url {
+			e.Count++
+			e.Status = status
+			return
+		}
+	}
+	if len(inv.endpoints) < maxEndpoints {
+		inv.endpoints = append(inv.endpoints, Endpoint{Method: method, URL: url, Status: status, Count: 1})
+	}
+}
+
+// Transport notes every request that reaches the network
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// statusError is an error standing for a response, such as the shared
+// transport's rate limit and server errors
+type statusError interface {
+	HTTPStatus() int
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	u := *req.URL
+	u.User, u.RawQuery, u.Fragment = nil, "", ""
+	status := 0
+	var se statusError
+	switch {
+	case err == nil:
+		status = resp.StatusCode
+	case errors.As(err, &se):
+		status = se.HTTPStatus()
+	}
+	note(req.Context(), req.Method, cassette.Redact(req.Context(), u.String()), status)
+	return resp, err
+}
+
+// Finish writes the invocation's record. ctx is the context the command
+// ran with, cmd the command, nil if none was found, and err what it
+// returned. Failing to write the log is reported on stderr but never fails
+// the command.
+func (inv *Invocation) Finish(ctx context.Context, cmd *cobra.Command, err error) {
+	if cmd != nil && skip(cmd) {
+		return
+	}
+	path := Path()
+	if path == "" {
+		return
+	}
+
+	inv.mu.Lock()
+	rec := Record{
+		Time:       inv.start.UTC(),
+		Profile:    config.ProfileName(ctx),
+		DurationMS: time.Since(inv.start).Milliseconds(),
+		Endpoints:  inv.endpoints,
+	}
+	inv.mu.Unlock()
+	switch {
+	case cmd != nil && cmd.Flags().Parsed():
+		rec.Command = commandPath(cmd)
+		rec.Args, rec.Flags = arguments(ctx, cmd)
+	default:
+		// No command was found, or its flags didn't parse: keep what was typed
+		if cmd != nil {
+			rec.Command = commandPath(cmd)
+		}
+		rec.Args = redactArgv(ctx, inv.argv)
+	}
+	if err != nil {
+		rec.Exit = 1
+		rec.Error = output.ErrorCode(err)
+	}
+
+	if werr := Append(path, &rec); werr != nil {
+		fmt.Fprintf(os.Stderr, "warning: audit log: %v\n", werr)
+	}
+}
+
+// Path returns the log location: $POCKET_AUDIT_LOG, else DefaultPath. It
+// is empty when auditing is off.
+func Path() string {
+	p := os.Getenv(PathEnv)
+	switch strings.ToLower(p) {
+	case "off", "false", "0":
+		return ""
+	case "":
+		return DefaultPath()
+	}
+	return p
+}
+
+// DefaultPath is audit.jsonl next to the config file
+func DefaultPath() string {
+	return filepath.Join(filepath.Dir(config.Path()), "audit.jsonl")
+}
+
+// skip leaves out shell completion, which runs on every tab press
+func skip(cmd *cobra.Command) bool {
+	name := cmd.Name()
+	return name == cobra.ShellCompRequestCmd || name == cobra.ShellCompNoDescRequestCmd
+}
+
+// commandPath is cmd's path without the root name
+func commandPath(cmd *cobra.Command) string {
+	p := cmd.CommandPath()
+	if root := cmd.Root().Name(); strings.HasPrefix(p, root) {
+		p = strings.TrimSpace(strings.TrimPrefix(p, root))
+	}
+	return p
+}
+
+// valueArgs are commands whose last argument is a credential
+var valueArgs = map[string]bool{
+	"config set": true,
+	"setup set":  true,
+}
+
+// sensitiveFlag reports flags whose values are credentials
+func sensitiveFlag(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range []string{"token", "secret", "password", "apikey", "api-key", "confirm"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactArgv scrubs an unparsed command line, treating the word after a
+// credential flag as its value
+func redactArgv(ctx context.Context, argv []string) []string {
+	var out []string
+	for i := 0; i < len(argv); i++ {
+		a := cassette.Redact(ctx, argv[i])
+		if name, ok := strings.CutPrefix(a, "-"); ok && sensitiveFlag(name) {
+			if n, _, hasValue := strings.Cut(a, "="); hasValue {
+				a = n + "=" + redacted
+			} else if i+1 < len(argv) {
+				out = append(out, a)
+				a = redacted
+				i++
+			}
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// arguments returns cmd's positional arguments and the flags that were set,
+// with configured secrets and credential values redacted
+func arguments(ctx context.Context, cmd *cobra.Command) ([]string, map[string]string) {
+	var args []string
+	for _, a := range cmd.Flags().Args() {
+		args = append(args, cassette.Redact(ctx, a))
+	}
+	if valueArgs[commandPath(cmd)] && len(args) > 1 {
+		args[len(args)-1] = redacted
+	}
+
+	var flags map[string]string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if flags == nil {
+			flags = map[string]string{}
+		}
+		v := cassette.Redact(ctx, f.Value.String())
+		if sensitiveFlag(f.Name) {
+			v = redacted
+		}
+		flags[f.Name] = v
+	})
+	return args, flags
+}
@@ -0,0 +1,282 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the redactor and profile lookup away from the real config
+	dir, _ := os.MkdirTemp("", "audit")
+	os.Setenv("POCKET_CONFIG", filepath.Join(dir, "config.json"))
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func useLog(t *testing.T, chain bool) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	t.Setenv(PathEnv, path)
+	if chain {
+		t.Setenv(ChainEnv, "1")
+	} else {
+		t.Setenv(ChainEnv, "")
+	}
+	return path
+}
+
+func readAll(t *testing.T, path string) []Entry {
+	t.Helper()
+	var entries []Entry
+	if err := Read(path, func(e Entry) bool {
+		entries = append(entries, e)
+		return true
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return entries
+}
+
+func TestFinishRecordsInvocation(t *testing.T) {
+	ctx := context.Background()
+	path := useLog(t, false)
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	t.Cleanup(func() { output.SetOutput(ctx, nil) })
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: &Transport{Base: http.DefaultTransport}}
+
+	root := &cobra.Command{Use: "pocket", SilenceErrors: true, SilenceUsage: true}
+	dev := &cobra.Command{Use: "dev"}
+	var token, repo string
+	create := &cobra.Command{
+		Use: "issue-create",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			for i := 0; i < 2; i++ {
+				req, _ := http.NewRequestWithContext(ctx, http.MethodPost, srv.URL+"/repos/issues?access_token=abc", http.NoBody)
+				resp, err := client.Do(req)
+				if err != nil {
+					return err
+				}
+				resp.Body.Close()
+			}
+			return output.PrintError(ctx, "create_failed", "nope", nil)
+		},
+	}
+	create.Flags().StringVar(&token, "api-token", "", "")
+	create.Flags().StringVar(&repo, "repo", "", "")
+	dev.AddCommand(create)
+	root.AddCommand(dev)
+	root.SetArgs([]string{"dev", "issue-create", "--repo", "o/r", "--api-token", "s3cret", "Title"})
+
+	ctx, inv := Start(ctx, nil)
+	cmd, err := root.ExecuteContextC(ctx)
+	inv.Finish(ctx, cmd, err)
+
+	// Requests made without the invocation's context aren't noted
+	resp, err := client.Get(srv.URL + "/later")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	entries := readAll(t, path)
+	if len(entries) != 1 {
+		t.Fatalf("records = %d", len(entries))
+	}
+	rec := entries[0].Record
+	if rec.Command != "dev issue-create" || rec.Exit != 1 || rec.Error != "create_failed" || rec.Profile != "default" {
+		t.Errorf("record = %+v", rec)
+	}
+	if len(rec.Args) != 1 || rec.Args[0] != "Title" {
+		t.Errorf("args = %v", rec.Args)
+	}
+	if rec.Flags["repo"] != "o/r" || rec.Flags["api-token"] != redacted {
+		t.Errorf("flags = %v", rec.Flags)
+	}
+	if len(rec.Endpoints) != 1 {
+		t.Fatalf("endpoints = %+v", rec.Endpoints)
+	}
+	e := rec.Endpoints[0]
+	if e.Method != http.MethodPost || e.URL != srv.URL+"/repos/issues" || e.Status != http.StatusCreated || e.Count != 2 {
+		t.Errorf("endpoint = %+v", e)
+	}
+	if strings.Contains(string(entries[0].Raw), "s3cret") || strings.Contains(string(entries[0].Raw), "abc") {
+		t.Errorf("secret logged: %s", entries[0].Raw)
+	}
+}
+
+func TestFinishRedactsSetValues(t *testing.T) {
+	ctx := context.Background()
+	path := useLog(t, false)
+	root := &cobra.Command{Use: "pocket"}
+	cfg := &cobra.Command{Use: "config"}
+	set := &cobra.Command{Use: "set", Run: func(*cobra.Command, []string) {}}
+	cfg.AddCommand(set)
+	root.AddCommand(cfg)
+	root.SetArgs([]string{"config", "set", "github_token", "ghp_123"})
+
+	ctx, inv := Start(ctx, nil)
+	cmd, err := root.ExecuteContextC(ctx)
+	inv.Finish(ctx, cmd, err)
+
+	rec := readAll(t, path)[0].Record
+	if len(rec.Args) != 2 || rec.Args[0] != "github_token" || rec.Args[1] != redacted {
+		t.Errorf("args = %v", rec.Args)
+	}
+}
+
+func TestFinishUnknownCommand(t *testing.T) {
+	ctx := context.Background()
+	path := useLog(t, false)
+	root := &cobra.Command{Use: "pocket", SilenceErrors: true, SilenceUsage: true}
+	root.AddCommand(&cobra.Command{Use: "dev", Run: func(*cobra.Command, []string) {}})
+	argv := []string{"dve", "github", "--api-token", "s3cret", "--password=hunter2"}
+	root.SetArgs(argv)
+
+	ctx, inv := Start(ctx, argv)
+	cmd, err := root.ExecuteContextC(ctx)
+	if err == nil {
+		t.Fatal("expected unknown command error")
+	}
+	inv.Finish(ctx, cmd, err)
+
+	rec := readAll(t, path)[0].Record
+	if rec.Command != "" || strings.Join(rec.Args, " ") != "dve github --api-token REDACTED --password=REDACTED" || rec.Exit != 1 {
+		t.Errorf("record = %+v", rec)
+	}
+}
+
+func TestDisabled(t *testing.T) {
+	ctx := context.Background()
+	t.Setenv(PathEnv, "off")
+	if Path() != "" {
+		t.Fatalf("Path() = %q", Path())
+	}
+	// Finish must not write anywhere
+	ctx, inv := Start(ctx, nil)
+	inv.Finish(ctx, nil, nil)
+}
+
+func TestHashChain(t *testing.T) {
+	path := useLog(t, false)
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	add := func(cmd string) {
+		t.Helper()
+		if err := Append(path, &Record{Time: start, Command: cmd, Args: []string{"<a&b>"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	add("news hackernews top")
+	t.Setenv(ChainEnv, "1")
+	add("dev github issue-create")
+	t.Setenv(ChainEnv, "")
+	// Chaining sticks once started
+	add("comms slack send")
+	add("comms slack history")
+
+	entries := readAll(t, path)
+	if entries[0].Hash != "" || entries[1].Hash == "" || entries[1].PrevHash != "" {
+		t.Fatalf("chain start wrong: %+v", entries[:2])
+	}
+	if entries[2].PrevHash != entries[1].Hash || entries[3].PrevHash != entries[2].Hash {
+		t.Fatal("records not linked")
+	}
+
+	v, err := Verify(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !v.Valid || v.Records != 4 || v.Chained != 3 || v.Head != entries[3].Hash {
+		t.Fatalf("verification = %+v", v)
+	}
+
+	// Edit a record in place
+	data, _ := os.ReadFile(path)
+	tampered := strings.Replace(string(data), "comms slack send", "comms slack sent", 1)
+	if err := os.WriteFile(path, []byte(tampered), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v, err = Verify(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Valid || len(v.Broken) != 1 || v.Broken[0].Line != 3 {
+		t.Errorf("edit not caught: %+v", v)
+	}
+
+	// Remove a record from the middle
+	lines := strings.SplitAfter(string(data), "\n")
+	removed := strings.Join(append(lines[:2:2], lines[3:]...), "")
+	if err := os.WriteFile(path, []byte(removed), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	v, err = Verify(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Valid || len(v.Broken) != 1 || v.Broken[0].Line != 3 || !strings.Contains(v.Broken[0].Reason, "prev_hash") {
+		t.Errorf("removal not caught: %+v", v)
+	}
+}
+
+func TestLastLineSpansChunks(t *testing.T) {
+	path := useLog(t, true)
+	long := strings.Repeat("x", 20<<10)
+	for _, arg := range []string{long, "short", long} {
+		if err := Append(path, &Record{Command: "utility echo", Args: []string{arg}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if v, err := Verify(path); err != nil || !v.Valid || v.Chained != 3 {
+		t.Errorf("verification = %+v, %v", v, err)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	day := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	e := Entry{
+		Record: Record{Time: day.Add(time.Hour), Command: "dev github issues", Exit: 1, Error: "api_error"},
+		Raw:    []byte(`{"command":"dev github issues","error":"api_error"}`),
+	}
+	tests := []struct {
+		name   string
+		filter Filter
+		want   bool
+	}{
+		{"empty", Filter{}, true},
+		{"integration", Filter{Integration: "GitHub"}, true},
+		{"group", Filter{Integration: "dev"}, true},
+		{"subcommand is not an integration", Filter{Integration: "issues"}, false},
+		{"other integration", Filter{Integration: "gitlab"}, false},
+		{"in range", Filter{Since: day, Until: day.Add(2 * time.Hour)}, true},
+		{"before since", Filter{Since: day.Add(2 * time.Hour)}, false},
+		{"until is exclusive", Filter{Until: day.Add(time.Hour)}, false},
+		{"failed", Filter{Failed: true}, true},
+		{"text", Filter{Text: "API_ERROR"}, true},
+		{"missing text", Filter{Text: "slack"}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.Match(e); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
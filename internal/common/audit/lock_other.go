@@ -0,0 +1,12 @@
+//go:build !darwin && !linux
+
+package audit
+
+import "os"
+
+// lock is a no-op on this platform; O_APPEND still keeps lines whole
+func lock(f *os.File) error {
+	return nil
+}
+
+func unlock(f *os.File) {}
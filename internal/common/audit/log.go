@@ -0,0 +1,249 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Append adds rec to the log at path, chaining it to the previous record
+// when chaining is on
+func Append(path string, rec *Record) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Concurrent pocket processes must not interleave lines or fork the chain
+	if err := lock(f); err != nil {
+		return err
+	}
+	defer unlock(f)
+
+	last, err := lastLine(f)
+	if err != nil {
+		return err
+	}
+	var prev struct {
+		Hash string `json:"hash"`
+	}
+	if len(last) > 0 {
+		_ = json.Unmarshal(last, &prev)
+	}
+
+	rec.PrevHash, rec.Hash = "", ""
+	if prev.Hash != "" || os.Getenv(ChainEnv) == "1" {
+		rec.PrevHash = prev.Hash
+		if rec.Hash, err = hash(rec); err != nil {
+			return err
+		}
+	}
+
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// hash is the SHA-256 of rec's canonical JSON (sorted keys) without its
+// own hash. prev_hash is included, linking each record to the one before.
+func hash(rec any) (string, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return "", err
+	}
+	delete(fields, "hash")
+	canonical, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(canonical)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// lastLine returns the final complete line of f, reading backwards from
+// the end
+func lastLine(f *os.File) ([]byte, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	end := info.Size()
+	var tail []byte
+	const chunk = 8 << 10
+	for pos := end; pos > 0; {
+		n := int64(chunk)
+		if pos < n {
+			n = pos
+		}
+		pos -= n
+		buf := make([]byte, n)
+		if _, err := f.ReadAt(buf, pos); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		tail = append(buf, tail...)
+		trimmed := bytes.TrimRight(tail, "\n")
+		if i := bytes.LastIndexByte(trimmed, '\n'); i >= 0 {
+			return trimmed[i+1:], nil
+		}
+	}
+	return bytes.TrimRight(tail, "\n"), nil
+}
+
+// Entry is a record with its raw line and 1-based line number
+type Entry struct {
+	Record
+	Line int    `json:"-"`
+	Raw  []byte `json:"-"`
+}
+
+// Read calls fn for each record in the log at path, stopping early when fn
+// returns false. A missing log has no records.
+func Read(path string, fn func(Entry) bool) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			e := Entry{Line: n, Raw: line}
+			if jerr := json.Unmarshal(line, &e.Record); jerr != nil {
+				return fmt.Errorf("%s:%d: %w", path, n, jerr)
+			}
+			if !fn(e) {
+				return nil
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Filter selects records by integration, time range and text
+type Filter struct {
+	// Integration matches the command's group or integration, e.g. "dev"
+	// or "github"
+	Integration string
+	Since       time.Time
+	Until       time.Time
+	// Failed keeps only invocations that exited non-zero
+	Failed bool
+	// Text matches case-insensitively anywhere in the raw record
+	Text string
+}
+
+// Match reports whether e passes the filter
+func (f Filter) Match(e Entry) bool {
+	if f.Integration != "" {
+		words := strings.Fields(e.Command)
+		if len(words) > 2 {
+			words = words[:2]
+		}
+		found := false
+		for _, w := range words {
+			if strings.EqualFold(w, f.Integration) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && !e.Time.Before(f.Until) {
+		return false
+	}
+	if f.Failed && e.Exit == 0 {
+		return false
+	}
+	if f.Text != "" && !bytes.Contains(bytes.ToLower(e.Raw), []byte(strings.ToLower(f.Text))) {
+		return false
+	}
+	return true
+}
+
+// Verification is the result of checking the hash chain
+type Verification struct {
+	Path    string `json:"path"`
+	Records int    `json:"records"`
+	Chained int    `json:"chained"`
+	// Head is the last record's hash. Keep a copy elsewhere to detect
+	// records being removed from the end.
+	Head   string  `json:"head,omitempty"`
+	Valid  bool    `json:"valid"`
+	Broken []Break `json:"broken,omitempty"`
+}
+
+// Break is a record that fails verification
+type Break struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// Verify checks every chained record's hash and link to its predecessor.
+// Records before the chain starts are counted but can't be verified.
+func Verify(path string) (*Verification, error) {
+	v := &Verification{Path: path}
+	prev := ""
+	err := Read(path, func(e Entry) bool {
+		v.Records++
+		if e.Hash == "" {
+			if prev != "" {
+				v.Broken = append(v.Broken, Break{e.Line, "unchained record after the chain started"})
+			}
+			return true
+		}
+		v.Chained++
+		if e.PrevHash != prev {
+			v.Broken = append(v.Broken, Break{e.Line, "prev_hash does not match the previous record"})
+		}
+		// Hash the record as written, so fields this version doesn't know
+		// about are still covered
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(e.Raw, &fields); err != nil {
+			v.Broken = append(v.Broken, Break{e.Line, err.Error()})
+		} else if sum, err := hash(fields); err != nil || sum != e.Hash {
+			v.Broken = append(v.Broken, Break{e.Line, "hash does not match the record's contents"})
+		}
+		prev = e.Hash
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	v.Head = prev
+	v.Valid = len(v.Broken) == 0
+	return v, nil
+}
@@ -0,0 +1,232 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxBodySize is the largest response body written to the cache
+const maxBodySize = 5 << 20
+
+// StatusHeader is added to responses served or stored by the cache
+const StatusHeader = "X-Pocket-Cache"
+
+// DefaultTTLs are per-integration lifetimes keyed by API host. A leading
+// dot matches any subdomain. Hosts not listed are only cached when a TTL
+// is forced with --cache-ttl.
+var DefaultTTLs = map[string]time.Duration{
+	"hacker-news.firebaseio.com": 5 * time.Minute,
+	".wikipedia.org":             time.Hour,
+	"registry.npmjs.org":         time.Hour,
+	"pypi.org":                   time.Hour,
+	"hub.docker.com":             time.Hour,
+	"api.frankfurter.app":        time.Hour,
+	"wttr.in":                    10 * time.Minute,
+	"date.nager.at":              24 * time.Hour,
+	"api.dictionaryapi.dev":      24 * time.Hour,
+	"api.stackexchange.com":      time.Hour,
+	"api.coingecko.com":          time.Minute,
+}
+
+// settings are a command's --no-cache and --cache-ttl
+type settings struct {
+	sync.RWMutex
+	disabled bool
+	forceTTL time.Duration
+}
+
+type settingsKey struct{}
+
+// defaults are the settings of code running outside NewContext
+var defaults = &settings{}
+
+// NewContext returns a copy of ctx with cache settings of its own, which
+// apply to requests made with it
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, settingsKey{}, &settings{})
+}
+
+// from returns the settings carried by ctx
+func from(ctx context.Context) *settings {
+	if s, ok := ctx.Value(settingsKey{}).(*settings); ok {
+		return s
+	}
+	return defaults
+}
+
+// SetDisabled turns the cache off for the command run with ctx (--no-cache)
+func SetDisabled(ctx context.Context, d bool) {
+	s := from(ctx)
+	s.Lock()
+	defer s.Unlock()
+	s.disabled = d
+}
+
+// SetTTL overrides the per-host TTLs for the command run with ctx
+// (--cache-ttl); zero restores the defaults
+func SetTTL(ctx context.Context, ttl time.Duration) {
+	s := from(ctx)
+	s.Lock()
+	defer s.Unlock()
+	s.forceTTL = ttl
+}
+
+// Dir returns the cache directory: $POCKET_CACHE_DIR, else
+// $XDG_CACHE_HOME/pocket, else ~/.cache/pocket
+func Dir() string {
+	if p := os.Getenv("POCKET_CACHE_DIR"); p != "" {
+		return p
+	}
+	if p := os.Getenv("XDG_CACHE_HOME"); p != "" {
+		return filepath.Join(p, "pocket")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), "pocket-cache")
+	}
+	return filepath.Join(home, ".cache", "pocket")
+}
+
+func httpDir() string {
+	return filepath.Join(Dir(), "http")
+}
+
+// ttlFor returns how long responses from host stay fresh
+func ttlFor(ctx context.Context, host string) time.Duration {
+	s := from(ctx)
+	s.RLock()
+	forceTTL := s.forceTTL
+	s.RUnlock()
+	if forceTTL > 0 {
+		return forceTTL
+	}
+
+	host = strings.ToLower(host)
+	if h, _, ok := strings.Cut(host, ":"); ok {
+		host = h
+	}
+	if ttl, ok := DefaultTTLs[host]; ok {
+		return ttl
+	}
+	for pattern, ttl := range DefaultTTLs {
+		if strings.HasPrefix(pattern, ".") && strings.HasSuffix(host, pattern) {
+			return ttl
+		}
+	}
+	return 0
+}
+
+func isDisabled(ctx context.Context) bool {
+	s := from(ctx)
+	s.RLock()
+	defer s.RUnlock()
+	return s.disabled
+}
+
+// entry is one cached response on disk
+type entry struct {
+	URL          string      `json:"url"`
+	Host         string      `json:"host"`
+	Status       int         `json:"status"`
+	Header       http.Header `json:"header"`
+	Body         []byte      `json:"body"`
+	StoredAt     time.Time   `json:"stored_at"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"last_modified,omitempty"`
+}
+
+// fresh checks age against the current TTL, so --cache-ttl also applies
+// to entries stored earlier
+func (e *entry) fresh(now time.Time, ttl time.Duration) bool {
+	return now.Before(e.StoredAt.Add(ttl))
+}
+
+func (e *entry) revalidatable() bool {
+	return e.ETag != "" || e.LastModified != ""
+}
+
+func (e *entry) response(req *http.Request, status string) *http.Response {
+	h := e.Header.Clone()
+	h.Set(StatusHeader, status)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status)),
+		StatusCode:    e.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+// key identifies a request by its method, URL and every header, so
+// different accounts (Authorization, PRIVATE-TOKEN, API key headers) never
+// share entries, a diff asked for with Accept isn't served as JSON, and
+// whatever a response's Vary names is matched. Conditional headers are
+// left out, being the cache's own.
+func key(req *http.Request) string {
+	h := sha256.New()
+	h.Write([]byte(req.Method + " " + req.URL.String() + "\n"))
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		if name != "If-None-Match" && name != "If-Modified-Since" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(h, "%s: %q\n", name, req.Header[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func load(k string) (*entry, bool) {
+	data, err := os.ReadFile(filepath.Join(httpDir(), k+".json"))
+	if err != nil {
+		return nil, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	return &e, true
+}
+
+func store(k string, e *entry) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	d := httpDir()
+	if err := os.MkdirAll(d, 0o700); err != nil {
+		return
+	}
+	// Write then rename so concurrent readers never see a partial file
+	tmp, err := os.CreateTemp(d, k+".*.tmp")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.Write(data)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(d, k+".json")); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
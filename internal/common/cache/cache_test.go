@@ -0,0 +1,236 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// setup points the cache at a temp dir and gives the test server's host
+// a TTL
+func setup(t *testing.T, handler http.HandlerFunc) (*httptest.Server, *http.Client) {
+	t.Helper()
+	ctx := context.Background()
+	t.Setenv("POCKET_CACHE_DIR", t.TempDir())
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	u, _ := url.Parse(srv.URL)
+	DefaultTTLs[u.Hostname()] = time.Minute
+	t.Cleanup(func() {
+		delete(DefaultTTLs, u.Hostname())
+		SetDisabled(ctx, false)
+		SetTTL(ctx, 0)
+	})
+
+	return srv, &http.Client{Transport: &Transport{Base: http.DefaultTransport}}
+}
+
+func get(t *testing.T, client *http.Client, target, auth string) (string, string) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, target, nil)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body), resp.Header.Get(StatusHeader)
+}
+
+func TestCacheHit(t *testing.T) {
+	var calls atomic.Int32
+	srv, client := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.WriteString(w, "hello")
+	})
+
+	if body, status := get(t, client, srv.URL, ""); body != "hello" || status != "miss" {
+		t.Errorf("first request: got %q (%s)", body, status)
+	}
+	if body, status := get(t, client, srv.URL, ""); body != "hello" || status != "hit" {
+		t.Errorf("second request: got %q (%s)", body, status)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("expected 1 upstream call, got %d", calls.Load())
+	}
+}
+
+func TestRevalidatesWithETag(t *testing.T) {
+	ctx := context.Background()
+	var calls, notModified atomic.Int32
+	srv, client := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = io.WriteString(w, "body")
+	})
+
+	get(t, client, srv.URL, "")
+
+	// Expire the entry so the next request must revalidate
+	SetTTL(ctx, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	body, status := get(t, client, srv.URL, "")
+
+	if body != "body" || status != "revalidated" {
+		t.Errorf("expected revalidated body, got %q (%s)", body, status)
+	}
+	if calls.Load() != 2 || notModified.Load() != 1 {
+		t.Errorf("expected one conditional request, got %d calls, %d 304s", calls.Load(), notModified.Load())
+	}
+}
+
+func TestBypass(t *testing.T) {
+	ctx := context.Background()
+	var calls atomic.Int32
+	srv, client := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.WriteString(w, "ok")
+	})
+
+	SetDisabled(ctx, true)
+	get(t, client, srv.URL, "")
+	get(t, client, srv.URL, "")
+	if calls.Load() != 2 {
+		t.Errorf("--no-cache: expected 2 upstream calls, got %d", calls.Load())
+	}
+	SetDisabled(ctx, false)
+
+	calls.Store(0)
+	for i := 0; i < 2; i++ {
+		resp, err := client.Post(srv.URL, "text/plain", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	}
+	if calls.Load() != 2 {
+		t.Errorf("POST: expected 2 upstream calls, got %d", calls.Load())
+	}
+}
+
+func TestUncachedHostUnlessTTLForced(t *testing.T) {
+	ctx := context.Background()
+	var calls atomic.Int32
+	srv, client := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		_, _ = io.WriteString(w, "ok")
+	})
+	u, _ := url.Parse(srv.URL)
+	delete(DefaultTTLs, u.Hostname())
+
+	get(t, client, srv.URL, "")
+	get(t, client, srv.URL, "")
+	if calls.Load() != 2 {
+		t.Errorf("host without TTL: expected 2 upstream calls, got %d", calls.Load())
+	}
+
+	SetTTL(ctx, time.Hour)
+	get(t, client, srv.URL, "")
+	if _, status := get(t, client, srv.URL, ""); status != "hit" {
+		t.Errorf("forced TTL: expected hit, got %q", status)
+	}
+}
+
+func TestAuthorizationSeparatesEntries(t *testing.T) {
+	srv, client := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Header.Get("Authorization"))
+	})
+
+	get(t, client, srv.URL, "token a")
+	if body, status := get(t, client, srv.URL, "token b"); body != "token b" || status != "miss" {
+		t.Errorf("expected separate entry per token, got %q (%s)", body, status)
+	}
+}
+
+func TestHeadersSeparateEntries(t *testing.T) {
+	srv, client := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Vary", "Accept")
+		_, _ = io.WriteString(w, r.Header.Get("Accept")+" "+r.Header.Get("PRIVATE-TOKEN"))
+	})
+	fetch := func(header ...string) (string, string) {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/repos/o/r/pulls/5", nil)
+		for i := 0; i < len(header); i += 2 {
+			req.Header.Set(header[i], header[i+1])
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body), resp.Header.Get(StatusHeader)
+	}
+
+	// github pr and pr-diff fetch the same URL with different Accept headers
+	fetch("Accept", "application/vnd.github+json")
+	if body, status := fetch("Accept", "application/vnd.github.diff"); body != "application/vnd.github.diff " || status != "miss" {
+		t.Errorf("diff served from the JSON entry: %q (%s)", body, status)
+	}
+	if body, status := fetch("Accept", "application/vnd.github+json"); body != "application/vnd.github+json " || status != "hit" {
+		t.Errorf("JSON entry: %q (%s)", body, status)
+	}
+
+	// Credentials outside Authorization keep profiles apart too
+	fetch("PRIVATE-TOKEN", "work")
+	if body, status := fetch("PRIVATE-TOKEN", "personal"); body != " personal" || status != "miss" {
+		t.Errorf("token header shared an entry: %q (%s)", body, status)
+	}
+}
+
+func TestNoStoreResponse(t *testing.T) {
+	var calls atomic.Int32
+	srv, client := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = io.WriteString(w, "secret")
+	})
+
+	get(t, client, srv.URL, "")
+	get(t, client, srv.URL, "")
+	if calls.Load() != 2 {
+		t.Errorf("expected no-store responses to skip the cache, got %d calls", calls.Load())
+	}
+}
+
+func TestStatsAndClear(t *testing.T) {
+	ctx := context.Background()
+	srv, client := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.URL.Path)
+	})
+	get(t, client, srv.URL+"/a", "")
+	get(t, client, srv.URL+"/b", "")
+
+	stats, err := GetStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 2 || stats.Fresh != 2 || len(stats.Hosts) != 1 || stats.Bytes == 0 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	u, _ := url.Parse(srv.URL)
+	if n, _ := Clear("other.example"); n != 0 {
+		t.Errorf("expected nothing cleared for another host, got %d", n)
+	}
+	if n, _ := Clear(u.Host); n != 2 {
+		t.Errorf("expected 2 cleared, got %d", n)
+	}
+	if stats, _ := GetStats(ctx); stats.Entries != 0 {
+		t.Errorf("expected empty cache, got %d entries", stats.Entries)
+	}
+}
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stats summarizes the on-disk cache
+type Stats struct {
+	Dir     string      `json:"dir"`
+	Entries int         `json:"entries"`
+	Bytes   int64       `json:"bytes"`
+	Fresh   int         `json:"fresh"`
+	Stale   int         `json:"stale"`
+	Hosts   []HostStats `json:"hosts,omitempty"`
+}
+
+// HostStats is the share of the cache held by one API host
+type HostStats struct {
+	Host    string `json:"host"`
+	Entries int    `json:"entries"`
+	Bytes   int64  `json:"bytes"`
+	TTL     string `json:"ttl"`
+}
+
+// GetStats walks the cache directory, judging freshness by the TTLs in
+// effect for ctx
+func GetStats(ctx context.Context) (*Stats, error) {
+	stats := &Stats{Dir: Dir()}
+	hosts := map[string]*HostStats{}
+	now := time.Now()
+
+	err := walk(func(path string, info os.FileInfo, e *entry) error {
+		stats.Entries++
+		stats.Bytes += info.Size()
+
+		ttl := ttlFor(ctx, e.Host)
+		if e.fresh(now, ttl) {
+			stats.Fresh++
+		} else {
+			stats.Stale++
+		}
+
+		h, ok := hosts[e.Host]
+		if !ok {
+			h = &HostStats{Host: e.Host, TTL: ttl.String()}
+			hosts[e.Host] = h
+		}
+		h.Entries++
+		h.Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, h := range hosts {
+		stats.Hosts = append(stats.Hosts, *h)
+	}
+	sort.Slice(stats.Hosts, func(i, j int) bool {
+		return stats.Hosts[i].Host < stats.Hosts[j].Host
+	})
+	return stats, nil
+}
+
+// Clear removes cached responses, only those from host when it is set,
+// and returns how many were removed
+func Clear(host string) (int, error) {
+	removed := 0
+	err := walk(func(path string, info os.FileInfo, e *entry) error {
+		if host != "" && !strings.EqualFold(e.Host, host) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+// walk visits every readable entry; unreadable files are treated as
+// corrupt and removed
+func walk(fn func(path string, info os.FileInfo, e *entry) error) error {
+	files, err := os.ReadDir(httpDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	for _, f := range files {
+		if f.IsDir() || !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(httpDir(), f.Name())
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var e entry
+		if err := json.Unmarshal(data, &e); err != nil {
+			_ = os.Remove(path)
+			continue
+		}
+		if err := fn(path, info, &e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Transport serves GET responses from the on-disk cache while they are
+// fresh and revalidates stale ones with ETag / Last-Modified
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ttl := ttlFor(req.Context(), req.URL.Host)
+	if ttl <= 0 || !cacheable(req) {
+		return t.Base.RoundTrip(req)
+	}
+
+	k := key(req)
+	now := time.Now()
+	cached, ok := load(k)
+	if ok && cached.fresh(now, ttl) {
+		return cached.response(req, "hit"), nil
+	}
+
+	outgoing := req
+	if ok && cached.revalidatable() {
+		outgoing = req.Clone(req.Context())
+		if cached.ETag != "" {
+			outgoing.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			outgoing.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := t.Base.RoundTrip(outgoing)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && ok {
+		resp.Body.Close()
+		cached.StoredAt = now
+		store(k, cached)
+		return cached.response(req, "revalidated"), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || noStore(resp.Header) {
+		return resp, nil
+	}
+
+	// Buffer the body to store it, passing oversized bodies through untouched
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize+1))
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if len(body) > maxBodySize {
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), resp.Body), resp.Body}
+		return resp, nil
+	}
+	resp.Body.Close()
+
+	e := &entry{
+		URL:          req.URL.String(),
+		Host:         req.URL.Host,
+		Status:       resp.StatusCode,
+		Header:       resp.Header.Clone(),
+		Body:         body,
+		StoredAt:     now,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	store(k, e)
+
+	resp.Header.Set(StatusHeader, "miss")
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	resp.ContentLength = int64(len(body))
+	return resp, nil
+}
+
+func cacheable(req *http.Request) bool {
+	if isDisabled(req.Context()) || req.Method != http.MethodGet {
+		return false
+	}
+	if req.Header.Get("Range") != "" {
+		return false
+	}
+	cc := strings.ToLower(req.Header.Get("Cache-Control"))
+	return !strings.Contains(cc, "no-store") && !strings.Contains(cc, "no-cache")
+}
+
+// noStore reports a response that must not be stored, including one that
+// varies on something other than the request headers the key covers
+func noStore(h http.Header) bool {
+	return strings.Contains(strings.ToLower(h.Get("Cache-Control")), "no-store") || strings.TrimSpace(h.Get("Vary")) == "*"
+}
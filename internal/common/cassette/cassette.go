@@ -0,0 +1,312 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+)
+
+// Environment variables naming the cassette directory
+const (
+	RecordEnv = "POCKET_RECORD"
+	ReplayEnv = "POCKET_REPLAY"
+)
+
+// ErrorCode is the output error code for requests missing from a cassette
+const ErrorCode = "replay_miss"
+
+// loadSecrets supplies the values to scrub; replaced in tests
+var loadSecrets = config.Secrets
+
+var (
+	// redactors are built per profile on first use, once --profile is known
+	redactorsMu sync.Mutex
+	redactors   = map[string]*redactor{}
+
+	// played counts replays per interaction across every client in the
+	// process, so repeated requests step through their recordings
+	playedMu sync.Mutex
+	played   = map[string]int{}
+)
+
+// getRedactor returns the redactor for the profile ctx runs with
+func getRedactor(ctx context.Context) *redactor {
+	profile := config.ProfileName(ctx)
+	redactorsMu.Lock()
+	defer redactorsMu.Unlock()
+	r, ok := redactors[profile]
+	if !ok {
+		r = newRedactor(loadSecrets(ctx))
+		redactors[profile] = r
+	}
+	return r
+}
+
+// FromEnv wraps base in a replaying Transport when POCKET_REPLAY is set,
+// or a recording one when POCKET_RECORD is set
+func FromEnv(base http.RoundTripper) (*Transport, bool) {
+	if dir := os.Getenv(ReplayEnv); dir != "" {
+		return &Transport{Base: base, Dir: dir, Replay: true}, true
+	}
+	if dir := os.Getenv(RecordEnv); dir != "" {
+		return &Transport{Base: base, Dir: dir}, true
+	}
+	return nil, false
+}
+
+// Transport records HTTP exchanges to cassette files in Dir, with secrets
+// redacted, or replays them without touching the network
+type Transport struct {
+	Base   http.RoundTripper
+	Dir    string
+	Replay bool
+}
+
+// Interaction is one recorded exchange, stored as a JSON file
+type Interaction struct {
+	Request    Request   `json:"request"`
+	Response   Response  `json:"response"`
+	RecordedAt time.Time `json:"recorded_at"`
+}
+
+type Request struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+type Response struct {
+	Status     int         `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+// MissError reports a request with no recording in the cassette
+type MissError struct {
+	Method string
+	URL    string
+	Dir    string
+}
+
+func (e *MissError) Error() string {
+	return fmt.Sprintf("no recorded response for %s %s in %s", e.Method, e.URL, e.Dir)
+}
+
+// ErrorCode reports a miss as replay_miss in whichever command it surfaces
+func (e *MissError) ErrorCode() string { return ErrorCode }
+
+func (e *MissError) ErrorDetails() any { return nil }
+
+// Redact replaces the secrets configured for ctx's profile in s with
+// {{key}} placeholders
+func Redact(ctx context.Context, s string) string {
+	return getRedactor(ctx).string(s)
+}
+
+// RedactRequest returns req as it would be recorded: secrets replaced with
+// placeholders and credentials in headers, query parameters and bodies
+// scrubbed. The body is left in place for sending.
+func RedactRequest(req *http.Request) (Request, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return Request{}, err
+	}
+	r := getRedactor(req.Context())
+	rec := Request{Method: req.Method, URL: r.url(req.URL), Header: r.header(req.Header)}
+	rec.Body, rec.BodyBase64 = encodeBody(r.body(body))
+	return rec, nil
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec, err := RedactRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	name := fileName(req.URL.Host, &rec)
+
+	if t.Replay {
+		return t.replay(req, &rec, name)
+	}
+	return t.record(req, &rec, name)
+}
+
+func (t *Transport) record(req *http.Request, rec *Request, name string) (*http.Response, error) {
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+
+	r := getRedactor(req.Context())
+	header := r.header(resp.Header)
+	header.Del("Content-Length")
+	it := &Interaction{
+		Request:    *rec,
+		Response:   Response{Status: resp.StatusCode, Header: header},
+		RecordedAt: time.Now().UTC(),
+	}
+	it.Response.Body, it.Response.BodyBase64 = encodeBody(r.body(data))
+
+	if err := save(t.Dir, name, it); err != nil {
+		return nil, fmt.Errorf("recording %s: %w", rec.URL, err)
+	}
+	return resp, nil
+}
+
+func (t *Transport) replay(req *http.Request, rec *Request, name string) (*http.Response, error) {
+	playedMu.Lock()
+	played[t.Dir+"/"+name]++
+	n := played[t.Dir+"/"+name]
+	playedMu.Unlock()
+
+	it, err := load(t.Dir, name, n)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, &MissError{Method: rec.Method, URL: rec.URL, Dir: t.Dir}
+		}
+		return nil, err
+	}
+
+	body, err := decodeBody(it.Response.Body, it.Response.BodyBase64)
+	if err != nil {
+		return nil, err
+	}
+	header := it.Response.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", it.Response.Status, http.StatusText(it.Response.Status)),
+		StatusCode:    it.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// fileName identifies a request by host and a hash of its redacted method,
+// URL and body, so the same request matches whichever credentials are set
+func fileName(host string, rec *Request) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n%s%s", rec.Method, rec.URL, rec.Body, rec.BodyBase64)
+	safe := strings.Map(func(c rune) rune {
+		if c == ':' || c == '/' || c == '\\' {
+			return '_'
+		}
+		return c
+	}, host)
+	return safe + "_" + hex.EncodeToString(h.Sum(nil))[:12]
+}
+
+func interactionPath(dir, name string, n int) string {
+	return filepath.Join(dir, fmt.Sprintf("%s_%03d.json", name, n))
+}
+
+// save writes the next free sequence number, so repeated requests, even
+// from separate pocket runs, keep every response
+func save(dir, name string, it *Interaction) error {
+	data, err := json.MarshalIndent(it, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for n := 1; ; n++ {
+		f, err := os.OpenFile(interactionPath(dir, name, n), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, werr := f.Write(append(data, '\n'))
+		if cerr := f.Close(); werr == nil {
+			werr = cerr
+		}
+		return werr
+	}
+}
+
+// load returns the nth recording, or the last one once they run out
+func load(dir, name string, n int) (*Interaction, error) {
+	for i := n; i >= 1; i-- {
+		data, err := os.ReadFile(interactionPath(dir, name, i))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var it Interaction
+		if err := json.Unmarshal(data, &it); err != nil {
+			return nil, fmt.Errorf("invalid cassette %s: %w", interactionPath(dir, name, i), err)
+		}
+		return &it, nil
+	}
+	return nil, os.ErrNotExist
+}
+
+// readBody reads the request body, leaving it in place for sending
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		rc, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return io.ReadAll(rc)
+	}
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
+
+// encodeBody keeps text readable and base64-encodes binary bodies
+func encodeBody(b []byte) (string, string) {
+	if utf8.Valid(b) {
+		return string(b), ""
+	}
+	return "", base64.StdEncoding.EncodeToString(b)
+}
+
+func decodeBody(text, b64 string) ([]byte, error) {
+	if b64 != "" {
+		return base64.StdEncoding.DecodeString(b64)
+	}
+	return []byte(text), nil
+}
@@ -0,0 +1,190 @@
+package cassette
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+const testToken = "123456:secret-bot-token"
+
+// useSecrets makes the redactor scrub the given config secrets
+func useSecrets(t *testing.T, secrets map[string]string) {
+	t.Helper()
+	old := loadSecrets
+	loadSecrets = func(context.Context) map[string]string { return secrets }
+	resetRedactors()
+	t.Cleanup(func() {
+		loadSecrets = old
+		resetRedactors()
+		playedMu.Lock()
+		clear(played)
+		playedMu.Unlock()
+	})
+}
+
+func resetRedactors() {
+	redactorsMu.Lock()
+	defer redactorsMu.Unlock()
+	clear(redactors)
+}
+
+func fetch(t *testing.T, client *http.Client, target string) (string, error) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body), nil
+}
+
+func TestRecordThenReplay(t *testing.T) {
+	useSecrets(t, map[string]string{"telegram_token": testToken})
+	dir := t.TempDir()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Set-Cookie", "session=abc")
+		_, _ = io.WriteString(w, `{"ok":true,"n":`+strconv.Itoa(int(n))+`,"access_token":"issued"}`)
+	}))
+	target := srv.URL + "/bot" + testToken + "/getMe?api_key=k1&limit=5"
+
+	recorder := &http.Client{Transport: &Transport{Base: http.DefaultTransport, Dir: dir}}
+	first, err := fetch(t, recorder, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(first, `"access_token":"issued"`) {
+		t.Errorf("recording should not alter the live response, got %s", first)
+	}
+	if _, err := fetch(t, recorder, target); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if len(files) != 2 {
+		t.Fatalf("expected 2 cassette files, got %d", len(files))
+	}
+	for _, f := range files {
+		data, _ := os.ReadFile(f)
+		for _, leak := range []string{testToken, "k1", "session=abc", "issued"} {
+			if bytes.Contains(data, []byte(leak)) {
+				t.Errorf("%s leaks %q", filepath.Base(f), leak)
+			}
+		}
+		if !bytes.Contains(data, []byte("{{telegram_token}}")) {
+			t.Errorf("%s should name the redacted secret", filepath.Base(f))
+		}
+	}
+
+	// Replay steps through the recordings, then repeats the last one
+	player := &http.Client{Transport: &Transport{Dir: dir, Replay: true}}
+	for i, want := range []string{`"n":1`, `"n":2`, `"n":2`} {
+		body, err := fetch(t, player, target)
+		if err != nil {
+			t.Fatalf("replay %d: %v", i, err)
+		}
+		if !strings.Contains(body, want) {
+			t.Errorf("replay %d: expected %s, got %s", i, want, body)
+		}
+	}
+	if calls.Load() != 2 {
+		t.Errorf("replay should not touch the network, got %d calls", calls.Load())
+	}
+}
+
+func TestReplayMatchesAcrossCredentials(t *testing.T) {
+	dir := t.TempDir()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "recorded")
+	}))
+	defer srv.Close()
+
+	useSecrets(t, map[string]string{"github_token": "recorder-token"})
+	recorder := &http.Client{Transport: &Transport{Base: http.DefaultTransport, Dir: dir}}
+	if _, err := fetch(t, recorder, srv.URL+"/user?token=recorder-token"); err != nil {
+		t.Fatal(err)
+	}
+
+	useSecrets(t, map[string]string{"github_token": "someone-else"})
+	player := &http.Client{Transport: &Transport{Dir: dir, Replay: true}}
+	body, err := fetch(t, player, srv.URL+"/user?token=someone-else")
+	if err != nil || body != "recorded" {
+		t.Errorf("expected the recording to match a different token, got %q, %v", body, err)
+	}
+}
+
+func TestReplayMiss(t *testing.T) {
+	ctx := context.Background()
+	useSecrets(t, nil)
+	player := &http.Client{Transport: &Transport{Dir: t.TempDir(), Replay: true}}
+
+	_, err := fetch(t, player, "https://api.example.com/missing")
+	var miss *MissError
+	if !errors.As(err, &miss) {
+		t.Fatalf("expected MissError, got %v", err)
+	}
+
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	defer output.SetOutput(ctx, nil)
+	_ = output.PrintErr(ctx, "fetch_failed", fmt.Errorf("request failed: %w", err), nil)
+
+	var resp output.Response
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid error output: %v", err)
+	}
+	if resp.Error.Code != ErrorCode {
+		t.Errorf("expected %s code, got %s", ErrorCode, resp.Error.Code)
+	}
+}
+
+func TestRedactor(t *testing.T) {
+	r := newRedactor(map[string]string{"slack_token": "xoxb-1/2+3", "pushover_user": "abc"})
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"raw", r.string("token=xoxb-1/2+3"), "token={{slack_token}}"},
+		{"query escaped", r.string("token=xoxb-1%2F2%2B3"), "token={{slack_token}}"},
+		{"short values kept", r.string("user=abc"), "user=abc"},
+		{"json fields", string(r.body([]byte(`{"refresh_token": "r1", "name": "x"}`))), `{"refresh_token": "REDACTED", "name": "x"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, tt.got)
+			}
+		})
+	}
+
+	h := r.header(http.Header{
+		"Authorization": {"Bearer xoxb-1/2+3"},
+		"X-Api-Key":     {"unknown"},
+		"Accept":        {"application/json"},
+	})
+	if h.Get("Authorization") != "Bearer {{slack_token}}" || h.Get("X-Api-Key") != redacted || h.Get("Accept") != "application/json" {
+		t.Errorf("unexpected headers: %v", h)
+	}
+}
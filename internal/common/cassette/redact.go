@@ -0,0 +1,116 @@
+package cassette
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// redacted replaces sensitive values that are not known config secrets
+const redacted = "REDACTED"
+
+// sensitiveParams are query parameters whose values never reach a cassette
+var sensitiveParams = map[string]bool{
+	"key": true, "api_key": true, "apikey": true, "appid": true,
+	"token": true, "access_token": true, "refresh_token": true,
+	"client_secret": true, "secret": true, "password": true,
+	"sig": true, "signature": true,
+}
+
+// jsonSecretField matches token-bearing JSON fields, e.g. in OAuth responses
+var jsonSecretField = regexp.MustCompile(`("(?:access_token|refresh_token|id_token|client_secret|password)"\s*:\s*)"[^"]*"`)
+
+func sensitiveHeader(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "authorization", "proxy-authorization", "cookie", "set-cookie":
+		return true
+	}
+	for _, s := range []string{"token", "secret", "api-key", "apikey", "password"} {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+type secret struct {
+	key, value string
+}
+
+// redactor scrubs config secrets, wherever they appear, and well-known
+// credential fields from recorded exchanges
+type redactor struct {
+	secrets []secret
+}
+
+func newRedactor(secrets map[string]string) *redactor {
+	r := &redactor{}
+	for key, val := range secrets {
+		// Very short values would mangle unrelated text
+		if len(val) >= 6 {
+			r.secrets = append(r.secrets, secret{key, val})
+		}
+	}
+	// Longest first, so a secret containing another is replaced whole
+	sort.Slice(r.secrets, func(i, j int) bool {
+		if len(r.secrets[i].value) != len(r.secrets[j].value) {
+			return len(r.secrets[i].value) > len(r.secrets[j].value)
+		}
+		return r.secrets[i].key < r.secrets[j].key
+	})
+	return r
+}
+
+// string replaces secret values, raw or URL-escaped, with {{key}}
+func (r *redactor) string(s string) string {
+	for _, sec := range r.secrets {
+		placeholder := "{{" + sec.key + "}}"
+		for _, v := range []string{sec.value, url.QueryEscape(sec.value), url.PathEscape(sec.value)} {
+			s = strings.ReplaceAll(s, v, placeholder)
+		}
+	}
+	return s
+}
+
+func (r *redactor) url(u *url.URL) string {
+	c := *u
+	c.User = nil
+	if q := c.Query(); len(q) > 0 {
+		changed := false
+		for name := range q {
+			if sensitiveParams[strings.ToLower(name)] {
+				q.Set(name, redacted)
+				changed = true
+			}
+		}
+		if changed {
+			c.RawQuery = q.Encode()
+		}
+	}
+	return r.string(c.String())
+}
+
+func (r *redactor) header(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for name, vals := range h {
+		for _, v := range vals {
+			scrubbed := r.string(v)
+			if sensitiveHeader(name) && scrubbed == v {
+				scrubbed = redacted
+			}
+			out[name] = append(out[name], scrubbed)
+		}
+	}
+	return out
+}
+
+func (r *redactor) body(b []byte) []byte {
+	if len(b) == 0 {
+		return b
+	}
+	s := jsonSecretField.ReplaceAllString(r.string(string(b)), `${1}"`+redacted+`"`)
+	return []byte(s)
+}
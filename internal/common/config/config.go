@@ -1,9 +1,11 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"strings"
@@ -13,6 +15,9 @@ import (
 var (
 	configPath string
 	configOnce sync.Once
+	// writeMu serializes load-modify-save updates, so commands running
+	// together in one process don't undo each other's changes
+	writeMu sync.Mutex
 )
 
 // Config holds all configuration
@@ -50,6 +55,9 @@ type Config struct {
 	GitHubToken     string `json:"github_token,omitempty"`
 	GitLabToken     string `json:"gitlab_token,omitempty"`
 	GitLabURL       string `json:"gitlab_url,omitempty"`
+	GitLabCACert    string `json:"gitlab_ca_cert,omitempty"`
+	GitLabCert      string `json:"gitlab_client_cert,omitempty"`
+	GitLabKey       string `json:"gitlab_client_key,omitempty"`
 	LinearToken     string `json:"linear_token,omitempty"`
 	JiraURL         string `json:"jira_url,omitempty"`
 	JiraEmail       string `json:"jira_email,omitempty"`
@@ -121,6 +129,25 @@ type Config struct {
 	// Marketing - Shopify
 	ShopifyStore string `json:"shopify_store,omitempty"`
 	ShopifyToken string `json:"shopify_token,omitempty"`
+
+	// Secret storage
+	SecretBackend      string `json:"secret_backend,omitempty"`
+	SecretFile         string `json:"secret_file,omitempty"`
+	SecretKeyFile      string `json:"secret_key_file,omitempty"`
+	SecretCommand      string `json:"secret_command,omitempty"`
+	SecretStoreCommand string `json:"secret_store_command,omitempty"`
+
+	// Plugins holds the values of keys declared by plugins (see RegisterKey)
+	Plugins map[string]string `json:"plugins,omitempty"`
+
+	// Profiles hold named sets of keys layered over the ones above
+	ActiveProfile string             `json:"active_profile,omitempty"`
+	Profiles      map[string]*Config `json:"profiles,omitempty"`
+
+	// kept holds the backend secrets Load had nowhere to put, those of
+	// profiles missing from the file or of keys no installed plugin
+	// declares, so Save writes them back
+	kept map[string]string
 }
 
 // Path returns the config file path
@@ -158,6 +185,10 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config: %w", err)
 	}
 
+	if err := loadSecrets(&cfg); err != nil {
+		return nil, err
+	}
+
 	return &cfg, nil
 }
 
@@ -171,7 +202,14 @@ func Save(cfg *Config) error {
 		return fmt.Errorf("failed to create config dir: %w", err)
 	}
 
-	data, err := json.MarshalIndent(cfg, "", "  ")
+	// Secrets go to the configured backend; only the rest is written here
+	plain := *cfg
+	plain.Plugins = maps.Clone(cfg.Plugins)
+	if err := storeSecrets(&plain); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(&plain, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -183,17 +221,53 @@ func Save(cfg *Config) error {
 	return nil
 }
 
-// Set sets a config value by key
-//
-//nolint:gocyclo // complex but clear sequential logic
-func Set(key, value string) error {
+// Set sets a config value by key in the active profile
+func Set(ctx context.Context, key, value string) error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
 	cfg, err := Load()
 	if err != nil {
 		return err
 	}
 
 	key = normalizeKey(key)
+	target := cfg
+	if name := cfg.activeProfile(ctx); name != DefaultProfile && !isGlobalKey(key) {
+		if err := validateProfileName(name); err != nil {
+			return err
+		}
+		if cfg.Profiles == nil {
+			cfg.Profiles = map[string]*Config{}
+		}
+		if cfg.Profiles[name] == nil {
+			cfg.Profiles[name] = &Config{}
+		}
+		target = cfg.Profiles[name]
+	}
+
+	if err := target.set(key, value); err != nil {
+		return err
+	}
 
+	return Save(cfg)
+}
+
+// Get gets a config value by key, falling back from the active profile to
+// the default one
+func Get(ctx context.Context, key string) (string, error) {
+	cfg, err := Resolve(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.get(normalizeKey(key))
+}
+
+// set assigns a normalized key on the struct
+//
+//nolint:gocyclo // complex but clear sequential logic
+func (cfg *Config) set(key, value string) error {
 	switch key {
 	case "x_client_id":
 		cfg.XClientID = value
@@ -247,6 +321,12 @@ func Set(key, value string) error {
 		cfg.GitLabToken = value
 	case "gitlab_url":
 		cfg.GitLabURL = value
+	case "gitlab_ca_cert":
+		cfg.GitLabCACert = value
+	case "gitlab_client_cert":
+		cfg.GitLabCert = value
+	case "gitlab_client_key":
+		cfg.GitLabKey = value
 	case "linear_token":
 		cfg.LinearToken = value
 	case "jira_url":
@@ -341,24 +421,30 @@ func Set(key, value string) error {
 		cfg.ShopifyStore = value
 	case "shopify_token":
 		cfg.ShopifyToken = value
+	case "secret_backend":
+		cfg.SecretBackend = value
+	case "secret_file":
+		cfg.SecretFile = value
+	case "secret_key_file":
+		cfg.SecretKeyFile = value
+	case "secret_command":
+		cfg.SecretCommand = value
+	case "secret_store_command":
+		cfg.SecretStoreCommand = value
 	default:
-		return fmt.Errorf("unknown config key: %s", key)
+		if !pluginKeys[key] {
+			return fmt.Errorf("unknown config key: %s", key)
+		}
+		cfg.setPlugin(key, value)
 	}
 
-	return Save(cfg)
+	return nil
 }
 
-// Get gets a config value by key
+// get reads a normalized key from the struct
 //
 //nolint:gocyclo // complex but clear sequential logic
-func Get(key string) (string, error) {
-	cfg, err := Load()
-	if err != nil {
-		return "", err
-	}
-
-	key = normalizeKey(key)
-
+func (cfg *Config) get(key string) (string, error) {
 	switch key {
 	case "x_client_id":
 		return cfg.XClientID, nil
@@ -412,6 +498,12 @@ func Get(key string) (string, error) {
 		return cfg.GitLabToken, nil
 	case "gitlab_url":
 		return cfg.GitLabURL, nil
+	case "gitlab_ca_cert":
+		return cfg.GitLabCACert, nil
+	case "gitlab_client_cert":
+		return cfg.GitLabCert, nil
+	case "gitlab_client_key":
+		return cfg.GitLabKey, nil
 	case "linear_token":
 		return cfg.LinearToken, nil
 	case "jira_url":
@@ -506,23 +598,37 @@ func Get(key string) (string, error) {
 		return cfg.ShopifyStore, nil
 	case "shopify_token":
 		return cfg.ShopifyToken, nil
+	case "secret_backend":
+		return cfg.SecretBackend, nil
+	case "secret_file":
+		return cfg.SecretFile, nil
+	case "secret_key_file":
+		return cfg.SecretKeyFile, nil
+	case "secret_command":
+		return cfg.SecretCommand, nil
+	case "secret_store_command":
+		return cfg.SecretStoreCommand, nil
 	default:
-		return "", fmt.Errorf("unknown config key: %s", key)
+		if !pluginKeys[key] {
+			return "", fmt.Errorf("unknown config key: %s", key)
+		}
+		return cfg.Plugins[key], nil
 	}
 }
 
-// Redacted returns config with sensitive values masked
-func (c *Config) Redacted() map[string]string {
-	redact := func(s string) string {
-		if s == "" {
-			return "(not set)"
-		}
-		if len(s) <= 8 {
-			return "****"
-		}
-		return s[:4] + "****" + s[len(s)-4:]
+// redact masks a sensitive value, keeping a short prefix and suffix
+func redact(s string) string {
+	if s == "" {
+		return "(not set)"
+	}
+	if len(s) <= 8 {
+		return "****"
 	}
+	return s[:4] + "****" + s[len(s)-4:]
+}
 
+// Redacted returns config with sensitive values masked
+func (c *Config) Redacted() map[string]string {
 	return map[string]string{
 		"x_client_id":             redact(c.XClientID),
 		"x_access_token":          redact(c.XAccessToken),
@@ -550,6 +656,9 @@ func (c *Config) Redacted() map[string]string {
 		"github_token":            redact(c.GitHubToken),
 		"gitlab_token":            redact(c.GitLabToken),
 		"gitlab_url":              c.GitLabURL,
+		"gitlab_ca_cert":          c.GitLabCACert,
+		"gitlab_client_cert":      c.GitLabCert,
+		"gitlab_client_key":       c.GitLabKey,
 		"linear_token":            redact(c.LinearToken),
 		"jira_url":                c.JiraURL,
 		"jira_email":              c.JiraEmail,
@@ -597,17 +706,22 @@ func (c *Config) Redacted() map[string]string {
 		"amazon_sp_token_expiry":  c.AmazonSPTokenExpiry,
 		"shopify_store":           c.ShopifyStore,
 		"shopify_token":           redact(c.ShopifyToken),
+		"secret_backend":          c.SecretBackend,
+		"secret_file":             c.SecretFile,
+		"secret_key_file":         c.SecretKeyFile,
+		"secret_command":          c.SecretCommand,
+		"secret_store_command":    c.SecretStoreCommand,
 	}
 }
 
 // MustGet gets a config value or returns an error if not set
-func MustGet(key string) (string, error) {
-	val, err := Get(key)
+func MustGet(ctx context.Context, key string) (string, error) {
+	val, err := Get(ctx, key)
 	if err != nil {
 		return "", err
 	}
 	if val == "" {
-		return "", errors.New("config key not set: " + key + " (use: pocket config set " + key + " <value>)")
+		return "", errors.New("config key not set: " + key + " (use: pocket config set " + key + " <value>, or set " + EnvVar(key) + ")")
 	}
 	return val, nil
 }
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"sync"
@@ -127,6 +128,7 @@ func TestSaveFilePermissions(t *testing.T) {
 }
 
 func TestSetAndGet(t *testing.T) {
+	ctx := context.Background()
 	setupTempConfig(t)
 
 	tests := []struct {
@@ -153,6 +155,9 @@ func TestSetAndGet(t *testing.T) {
 		{"reddit_refresh_token", "reddit_rt"},
 		{"reddit_token_expiry", "2025-01-01"},
 		{"gitlab_url", "https://gitlab.example.com"},
+		{"gitlab_ca_cert", "/etc/ssl/corp-ca.pem"},
+		{"gitlab_client_cert", "/etc/ssl/me.pem"},
+		{"gitlab_client_key", "/etc/ssl/me.key"},
 		{"google_client_id", "gc_id"},
 		{"google_client_secret", "gc_secret"},
 		{"google_refresh_token", "gc_rt"},
@@ -187,12 +192,12 @@ func TestSetAndGet(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		if err := Set(tt.key, tt.value); err != nil {
+		if err := Set(ctx, tt.key, tt.value); err != nil {
 			t.Errorf("Set(%s) failed: %v", tt.key, err)
 			continue
 		}
 
-		got, err := Get(tt.key)
+		got, err := Get(ctx, tt.key)
 		if err != nil {
 			t.Errorf("Get(%s) failed: %v", tt.key, err)
 			continue
@@ -204,38 +209,42 @@ func TestSetAndGet(t *testing.T) {
 }
 
 func TestGetUnknownKey(t *testing.T) {
+	ctx := context.Background()
 	setupTempConfig(t)
 
-	_, err := Get("nonexistent_key")
+	_, err := Get(ctx, "nonexistent_key")
 	if err == nil {
 		t.Error("expected error for unknown key")
 	}
 }
 
 func TestSetUnknownKey(t *testing.T) {
+	ctx := context.Background()
 	setupTempConfig(t)
 
-	err := Set("nonexistent_key", "value")
+	err := Set(ctx, "nonexistent_key", "value")
 	if err == nil {
 		t.Error("expected error for unknown key")
 	}
 }
 
 func TestMustGetEmpty(t *testing.T) {
+	ctx := context.Background()
 	setupTempConfig(t)
 
-	_, err := MustGet("github_token")
+	_, err := MustGet(ctx, "github_token")
 	if err == nil {
 		t.Error("expected error for unset key")
 	}
 }
 
 func TestMustGetSet(t *testing.T) {
+	ctx := context.Background()
 	setupTempConfig(t)
 
-	_ = Set("github_token", "test_value")
+	_ = Set(ctx, "github_token", "test_value")
 
-	val, err := MustGet("github_token")
+	val, err := MustGet(ctx, "github_token")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -265,15 +274,16 @@ func TestNormalizeKey(t *testing.T) {
 }
 
 func TestSetWithNormalizedKey(t *testing.T) {
+	ctx := context.Background()
 	setupTempConfig(t)
 
 	// Set with hyphenated key
-	if err := Set("github-token", "normalized_test"); err != nil {
+	if err := Set(ctx, "github-token", "normalized_test"); err != nil {
 		t.Fatalf("Set with hyphen failed: %v", err)
 	}
 
 	// Get with underscored key
-	val, err := Get("github_token")
+	val, err := Get(ctx, "github_token")
 	if err != nil {
 		t.Fatalf("Get failed: %v", err)
 	}
@@ -330,25 +340,27 @@ func TestLoadInvalidJSON(t *testing.T) {
 }
 
 func TestSetOverwritesExisting(t *testing.T) {
+	ctx := context.Background()
 	setupTempConfig(t)
 
-	_ = Set("github_token", "first")
-	_ = Set("github_token", "second")
+	_ = Set(ctx, "github_token", "first")
+	_ = Set(ctx, "github_token", "second")
 
-	val, _ := Get("github_token")
+	val, _ := Get(ctx, "github_token")
 	if val != "second" {
 		t.Errorf("expected 'second', got %s", val)
 	}
 }
 
 func TestSetPreservesOtherKeys(t *testing.T) {
+	ctx := context.Background()
 	setupTempConfig(t)
 
-	_ = Set("github_token", "gh_value")
-	_ = Set("slack_token", "slack_value")
+	_ = Set(ctx, "github_token", "gh_value")
+	_ = Set(ctx, "slack_token", "slack_value")
 
-	gh, _ := Get("github_token")
-	sl, _ := Get("slack_token")
+	gh, _ := Get(ctx, "github_token")
+	sl, _ := Get(ctx, "slack_token")
 
 	if gh != "gh_value" {
 		t.Errorf("github_token was overwritten: %s", gh)
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// EnvPrefix prefixes the environment override of every config key, so
+// github_token can be supplied as POCKET_GITHUB_TOKEN
+const EnvPrefix = "POCKET_"
+
+// Where a resolved config value came from
+const (
+	SourceFile    = "file"
+	SourceProfile = "profile"
+	SourceEnv     = "env"
+)
+
+// Entry is one config value as seen by the active profile
+type Entry struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Env    string `json:"env"`
+}
+
+// EnvVar returns the environment variable that overrides key
+func EnvVar(key string) string {
+	return EnvPrefix + strings.ToUpper(normalizeKey(key))
+}
+
+func lookupEnv(key string) (string, bool) {
+	val := os.Getenv(EnvVar(key))
+	return val, val != ""
+}
+
+// applyEnv overrides every key that has a non-empty POCKET_<KEY> variable
+func (cfg *Config) applyEnv() {
+	for _, key := range keys {
+		if val, ok := lookupEnv(key); ok {
+			_ = cfg.set(key, val)
+		}
+	}
+}
+
+// List returns every key with its redacted value and source: the config
+// file, the active profile or the environment
+func List(ctx context.Context) ([]Entry, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	name := cfg.activeProfile(ctx)
+	var profile *Config
+	if name != DefaultProfile {
+		if profile = cfg.Profiles[name]; profile == nil {
+			return nil, unknownProfile(name)
+		}
+	}
+
+	entries := make([]Entry, 0, len(keys))
+	for _, key := range keys {
+		val, _ := cfg.get(key)
+		source := ""
+		if val != "" {
+			source = SourceFile
+		}
+		if profile != nil && !isGlobalKey(key) {
+			if pv, _ := profile.get(key); pv != "" {
+				val, source = pv, SourceProfile+":"+name
+			}
+		}
+		if ev, ok := lookupEnv(key); ok {
+			val, source = ev, SourceEnv
+		}
+
+		if IsSecret(key) || val == "" {
+			val = redact(val)
+		}
+		entries = append(entries, Entry{Key: key, Value: val, Source: source, Env: EnvVar(key)})
+	}
+	return entries, nil
+}
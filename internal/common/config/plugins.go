@@ -0,0 +1,45 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// pluginKeys are the keys registered by plugins
+var pluginKeys = map[string]bool{}
+
+var validKey = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// RegisterKey adds a config key declared by a plugin, so it can be set,
+// read, overridden from the environment and kept per profile like the
+// built-in keys. Secret keys go through the secret backend. Keys must be
+// registered before any command runs; registering a key twice, or one that
+// is built in, does nothing.
+func RegisterKey(key string, secret bool) error {
+	key = normalizeKey(key)
+	if !validKey.MatchString(key) {
+		return fmt.Errorf("invalid config key: %q (lowercase letters, digits and underscores)", key)
+	}
+	if slices.Contains(keys, key) {
+		return nil
+	}
+
+	pluginKeys[key] = true
+	keys = append(keys, key)
+	if secret {
+		secretKeys = append(secretKeys, key)
+	}
+	return nil
+}
+
+func (cfg *Config) setPlugin(key, value string) {
+	if value == "" {
+		delete(cfg.Plugins, key)
+		return
+	}
+	if cfg.Plugins == nil {
+		cfg.Plugins = map[string]string{}
+	}
+	cfg.Plugins[key] = value
+}
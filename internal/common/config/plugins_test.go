@@ -0,0 +1,61 @@
+package config
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestRegisterKey(t *testing.T) {
+	ctx := context.Background()
+	setupTempConfig(t)
+	savedKeys, savedSecrets := keys, secretKeys
+	t.Cleanup(func() {
+		keys, secretKeys = savedKeys, savedSecrets
+		delete(pluginKeys, "acme_token")
+	})
+
+	for _, key := range []string{"", "Has Space", "1abc"} {
+		if err := RegisterKey(key, false); err == nil {
+			t.Errorf("RegisterKey(%q) should fail", key)
+		}
+	}
+	if err := RegisterKey("github_token", false); err != nil {
+		t.Errorf("built-in key: %v", err)
+	}
+	if err := RegisterKey("acme-token", true); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterKey("acme_token", true); err != nil {
+		t.Fatalf("registering twice: %v", err)
+	}
+	if n := len(keys) - len(savedKeys); n != 1 {
+		t.Errorf("%d keys added, want 1", n)
+	}
+	if !IsSecret("acme_token") {
+		t.Error("acme_token should be secret")
+	}
+
+	if err := Set(ctx, "acme_token", "personal"); err != nil {
+		t.Fatal(err)
+	}
+	useProfile(t, "work")
+	if err := Set(ctx, "acme_token", "work"); err != nil {
+		t.Fatal(err)
+	}
+	if val, _ := Get(ctx, "acme_token"); val != "work" {
+		t.Errorf("got %q, want work", val)
+	}
+	SetProfile(ctx, "")
+	if val, _ := Get(ctx, "acme_token"); val != "personal" {
+		t.Errorf("default profile should be untouched, got %q", val)
+	}
+
+	t.Setenv(EnvVar("acme_token"), "from-env")
+	if val, _ := Get(ctx, "acme_token"); val != "from-env" {
+		t.Errorf("env override: got %q", val)
+	}
+	if !slices.Contains(Keys(), "acme_token") {
+		t.Error("Keys() should list acme_token")
+	}
+}
@@ -0,0 +1,278 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// DefaultProfile names the top-level keys of the config file
+const DefaultProfile = "default"
+
+// ProfileEnv selects the active profile when --profile is not given
+const ProfileEnv = "POCKET_PROFILE"
+
+type profileKey struct{}
+
+// defaultOverride is the --profile of code running outside NewContext
+var defaultOverride = new(string)
+
+// NewContext returns a copy of ctx with a --profile override of its own, so
+// commands running alongside each other can use different profiles
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, profileKey{}, new(string))
+}
+
+// profileOverride returns the --profile override carried by ctx
+func profileOverride(ctx context.Context) *string {
+	if p, ok := ctx.Value(profileKey{}).(*string); ok {
+		return p
+	}
+	return defaultOverride
+}
+
+// Profile summarizes one named profile
+type Profile struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+	Keys   int    `json:"keys"`
+}
+
+// keys lists every settable config key, in struct order
+var keys = configKeys()
+
+func configKeys() []string {
+	var result []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if _, err := (&Config{}).get(name); err == nil {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// Keys returns every settable config key
+func Keys() []string {
+	return append([]string(nil), keys...)
+}
+
+// isGlobalKey reports keys that configure the file itself and are never
+// stored per profile
+func isGlobalKey(key string) bool {
+	return strings.HasPrefix(key, "secret_")
+}
+
+// SetProfile overrides the active profile for the command run with ctx
+// ("" to clear)
+func SetProfile(ctx context.Context, name string) {
+	*profileOverride(ctx) = name
+}
+
+// activeProfile resolves --profile, then POCKET_PROFILE, then the profile
+// chosen with `pocket config profile use`
+func (cfg *Config) activeProfile(ctx context.Context) string {
+	name := *profileOverride(ctx)
+	if name == "" {
+		name = os.Getenv(ProfileEnv)
+	}
+	if name == "" {
+		name = cfg.ActiveProfile
+	}
+	if name == "" {
+		name = DefaultProfile
+	}
+	return name
+}
+
+// ProfileName returns the active profile's name. It reads only the config
+// file, never the secret backend.
+func ProfileName(ctx context.Context) string {
+	var cfg Config
+	if data, err := os.ReadFile(Path()); err == nil {
+		_ = json.Unmarshal(data, &cfg)
+	}
+	return cfg.activeProfile(ctx)
+}
+
+// resolve returns the config seen by the active profile: environment
+// overrides, then its own values, then the default profile
+func (cfg *Config) resolve(ctx context.Context) (*Config, error) {
+	merged := *cfg
+	merged.Plugins = maps.Clone(cfg.Plugins)
+
+	if name := cfg.activeProfile(ctx); name != DefaultProfile {
+		p, ok := cfg.Profiles[name]
+		if !ok {
+			return nil, unknownProfile(name)
+		}
+		for _, key := range keys {
+			if pluginKeys[key] {
+				continue
+			}
+			if val, _ := p.get(key); val != "" {
+				_ = merged.set(key, val)
+			}
+		}
+		for key, val := range p.Plugins {
+			if val != "" {
+				merged.setPlugin(key, val)
+			}
+		}
+	}
+
+	merged.applyEnv()
+	return &merged, nil
+}
+
+func unknownProfile(name string) error {
+	return fmt.Errorf("unknown profile: %s (see: pocket config profile list)", name)
+}
+
+// Resolve loads the config as seen by the active profile
+func Resolve(ctx context.Context) (*Config, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+	return cfg.resolve(ctx)
+}
+
+// ListProfiles returns the default profile followed by named profiles
+func ListProfiles(ctx context.Context) ([]Profile, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, err
+	}
+
+	active := cfg.activeProfile(ctx)
+	result := []Profile{{Name: DefaultProfile, Active: active == DefaultProfile, Keys: countKeys(cfg)}}
+
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		result = append(result, Profile{Name: name, Active: active == name, Keys: countKeys(cfg.Profiles[name])})
+	}
+	return result, nil
+}
+
+// UseProfile makes name the profile used when neither --profile nor
+// POCKET_PROFILE is set
+func UseProfile(name string) error {
+	writeMu.Lock()
+	defer writeMu.Unlock()
+
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+
+	if name == DefaultProfile {
+		cfg.ActiveProfile = ""
+		return Save(cfg)
+	}
+	if _, ok := cfg.Profiles[name]; !ok {
+		return fmt.Errorf("unknown profile: %s (create it with: pocket config profile copy default %s)", name, name)
+	}
+	cfg.ActiveProfile = name
+	return Save(cfg)
+}
+
+// CopyProfile creates dst with all values of src
+func CopyProfile(src, dst string) error {
+	if err := validateProfileName(dst); err != nil {
+		return err
+	}
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+	if _, exists := cfg.Profiles[dst]; exists {
+		return fmt.Errorf("profile already exists: %s", dst)
+	}
+
+	from := cfg
+	if src != DefaultProfile {
+		p, ok := cfg.Profiles[src]
+		if !ok {
+			return fmt.Errorf("unknown profile: %s", src)
+		}
+		from = p
+	}
+
+	// Plugin values are copied whole, including those of plugins that are
+	// not installed right now
+	p := &Config{Plugins: maps.Clone(from.Plugins)}
+	for _, key := range keys {
+		if isGlobalKey(key) || pluginKeys[key] {
+			continue
+		}
+		val, _ := from.get(key)
+		_ = p.set(key, val)
+	}
+
+	if cfg.Profiles == nil {
+		cfg.Profiles = map[string]*Config{}
+	}
+	cfg.Profiles[dst] = p
+	return Save(cfg)
+}
+
+// DeleteProfile removes a named profile
+func DeleteProfile(name string) error {
+	if name == DefaultProfile {
+		return errors.New("the default profile cannot be deleted")
+	}
+
+	writeMu.Lock()
+	defer writeMu.Unlock()
+	cfg, err := Load()
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Profiles[name]; !ok {
+		return fmt.Errorf("unknown profile: %s", name)
+	}
+
+	delete(cfg.Profiles, name)
+	if cfg.ActiveProfile == name {
+		cfg.ActiveProfile = ""
+	}
+	return Save(cfg)
+}
+
+func validateProfileName(name string) error {
+	if name == "" || name == DefaultProfile {
+		return fmt.Errorf("invalid profile name: %q", name)
+	}
+	if strings.ContainsAny(name, "./\\ ") {
+		return fmt.Errorf("invalid profile name: %q (no dots, slashes or spaces)", name)
+	}
+	return nil
+}
+
+func countKeys(cfg *Config) int {
+	n := len(cfg.Plugins)
+	for _, key := range keys {
+		if isGlobalKey(key) || pluginKeys[key] {
+			continue
+		}
+		if val, _ := cfg.get(key); val != "" {
+			n++
+		}
+	}
+	return n
+}
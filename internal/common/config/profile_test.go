@@ -0,0 +1,242 @@
+package config
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+)
+
+func useProfile(t *testing.T, name string) {
+	t.Helper()
+	ctx := context.Background()
+	SetProfile(ctx, name)
+	t.Cleanup(func() { SetProfile(ctx, "") })
+}
+
+func TestProfileFallsBackToDefault(t *testing.T) {
+	ctx := context.Background()
+	setupTempConfig(t)
+
+	if err := Set(ctx, "github_token", "ghp_personal"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(ctx, "sentry_org", "acme"); err != nil {
+		t.Fatal(err)
+	}
+
+	useProfile(t, "work")
+	if err := Set(ctx, "github_token", "ghp_work"); err != nil {
+		t.Fatalf("set in profile failed: %v", err)
+	}
+
+	val, _ := Get(ctx, "github_token")
+	if val != "ghp_work" {
+		t.Errorf("expected profile value ghp_work, got %q", val)
+	}
+	val, _ = Get(ctx, "sentry_org")
+	if val != "acme" {
+		t.Errorf("expected fallback to default acme, got %q", val)
+	}
+
+	SetProfile(ctx, "")
+	val, _ = Get(ctx, "github_token")
+	if val != "ghp_personal" {
+		t.Errorf("default profile should be untouched, got %q", val)
+	}
+}
+
+func TestProfileFromEnv(t *testing.T) {
+	ctx := context.Background()
+	setupTempConfig(t)
+
+	if err := CopyProfile(DefaultProfile, "staging"); err != nil {
+		t.Fatal(err)
+	}
+	useProfile(t, "staging")
+	if err := Set(ctx, "prometheus_url", "https://staging.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	SetProfile(ctx, "")
+
+	t.Setenv(ProfileEnv, "staging")
+	val, _ := Get(ctx, "prometheus_url")
+	if val != "https://staging.example.com" {
+		t.Errorf("expected staging url, got %q", val)
+	}
+
+	t.Setenv(ProfileEnv, "missing")
+	if _, err := Get(ctx, "prometheus_url"); err == nil || !strings.Contains(err.Error(), "unknown profile") {
+		t.Errorf("expected unknown profile error, got %v", err)
+	}
+}
+
+func TestUseCopyDeleteProfile(t *testing.T) {
+	ctx := context.Background()
+	setupTempConfig(t)
+
+	if err := Set(ctx, "shopify_store", "main-store"); err != nil {
+		t.Fatal(err)
+	}
+	if err := UseProfile("eu"); err == nil {
+		t.Error("expected error using a profile that does not exist")
+	}
+	if err := CopyProfile(DefaultProfile, "eu"); err != nil {
+		t.Fatalf("copy failed: %v", err)
+	}
+	if err := CopyProfile(DefaultProfile, "eu"); err == nil {
+		t.Error("expected error copying onto an existing profile")
+	}
+	if err := UseProfile("eu"); err != nil {
+		t.Fatalf("use failed: %v", err)
+	}
+	if err := Set(ctx, "shopify_store", "eu-store"); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := ListProfiles(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 2 || profiles[0].Name != DefaultProfile || profiles[1].Name != "eu" {
+		t.Fatalf("unexpected profiles: %+v", profiles)
+	}
+	if profiles[0].Active || !profiles[1].Active {
+		t.Errorf("expected eu active: %+v", profiles)
+	}
+
+	val, _ := Get(ctx, "shopify_store")
+	if val != "eu-store" {
+		t.Errorf("expected eu-store, got %q", val)
+	}
+
+	if err := DeleteProfile(DefaultProfile); err == nil {
+		t.Error("expected error deleting default profile")
+	}
+	if err := DeleteProfile("eu"); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+
+	val, _ = Get(ctx, "shopify_store")
+	if val != "main-store" {
+		t.Errorf("deleting the active profile should fall back to default, got %q", val)
+	}
+}
+
+func TestProfileSecretsStayEncrypted(t *testing.T) {
+	ctx := context.Background()
+	tmpPath := setupTempConfig(t)
+	keyFile := writeKeyFile(t)
+
+	if err := Save(&Config{SecretBackend: BackendAge, SecretKeyFile: keyFile}); err != nil {
+		t.Fatal(err)
+	}
+	useProfile(t, "work")
+	if err := Set(ctx, "github_token", "ghp_work_secret"); err != nil {
+		t.Fatal(err)
+	}
+	// Backend settings are global even when a profile is active
+	if err := Set(ctx, "secret_key_file", keyFile); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := os.ReadFile(tmpPath)
+	if strings.Contains(string(data), "ghp_work_secret") {
+		t.Error("profile secret written to config.json")
+	}
+
+	ageCache.path = ""
+	val, err := Get(ctx, "github_token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != "ghp_work_secret" {
+		t.Errorf("expected ghp_work_secret, got %q", val)
+	}
+
+	cfg, _ := Load()
+	if cfg.Profiles["work"].SecretKeyFile != "" {
+		t.Error("secret_key_file should not be stored per profile")
+	}
+}
+
+func TestProfilePluginKeys(t *testing.T) {
+	ctx := context.Background()
+	setupTempConfig(t)
+	savedKeys := keys
+	t.Cleanup(func() {
+		keys = savedKeys
+		delete(pluginKeys, "acme_region")
+	})
+	if err := RegisterKey("acme_region", false); err != nil {
+		t.Fatal(err)
+	}
+
+	// other_key belongs to a plugin that is not installed
+	err := Save(&Config{
+		Plugins:  map[string]string{"acme_region": "us", "other_key": "x"},
+		Profiles: map[string]*Config{"eu": {Plugins: map[string]string{"acme_region": "eu"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	useProfile(t, "eu")
+	if val, _ := Get(ctx, "acme_region"); val != "eu" {
+		t.Errorf("expected eu, got %q", val)
+	}
+	cfg, err := Resolve(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Plugins["other_key"] != "x" {
+		t.Errorf("expected fallback to default for other_key: %v", cfg.Plugins)
+	}
+
+	if err := CopyProfile(DefaultProfile, "all"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CopyProfile("eu", "eu2"); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _ = Load()
+	if got := cfg.Profiles["all"].Plugins; got["acme_region"] != "us" || got["other_key"] != "x" {
+		t.Errorf("copy of default: %v", got)
+	}
+	if got := cfg.Profiles["eu2"].Plugins; len(got) != 1 || got["acme_region"] != "eu" {
+		t.Errorf("copy of eu: %v", got)
+	}
+
+	profiles, err := ListProfiles(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profiles[0].Keys != 2 {
+		t.Errorf("default should count 2 keys: %+v", profiles[0])
+	}
+}
+
+func TestInvalidProfileName(t *testing.T) {
+	setupTempConfig(t)
+
+	for _, name := range []string{"", DefaultProfile, "a.b", "with space"} {
+		if err := CopyProfile(DefaultProfile, name); err == nil {
+			t.Errorf("expected error for profile name %q", name)
+		}
+	}
+}
+
+func TestKeysMatchSwitch(t *testing.T) {
+	for _, key := range Keys() {
+		if err := (&Config{}).set(key, "x"); err != nil {
+			t.Errorf("key %s is listed but cannot be set: %v", key, err)
+		}
+	}
+	for _, key := range []string{"active_profile", "profiles"} {
+		for _, k := range Keys() {
+			if k == key {
+				t.Errorf("%s should not be a settable key", key)
+			}
+		}
+	}
+}
@@ -0,0 +1,458 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"filippo.io/age"
+)
+
+// Secret backends selectable with the secret_backend key
+const (
+	BackendAge     = "age"
+	BackendCommand = "command"
+)
+
+// PassphraseEnv unlocks the age backend when no secret_key_file is set
+const PassphraseEnv = "POCKET_SECRETS_PASSPHRASE"
+
+// scryptWorkFactor is the age scrypt cost used for passphrase-encrypted files
+var scryptWorkFactor = 18
+
+// secretKeys are the config keys kept out of config.json when a secret
+// backend is configured. They match the values masked by Redacted.
+var secretKeys = []string{
+	"x_client_id", "x_access_token", "x_refresh_token",
+	"reddit_client_id", "reddit_access_token", "reddit_refresh_token",
+	"mastodon_token", "youtube_api_key",
+	"slack_token", "discord_token", "telegram_token", "twilio_sid", "twilio_token",
+	"email_password",
+	"github_token", "gitlab_token", "linear_token", "jira_token", "vercel_token",
+	"cloudflare_token", "sentry_auth_token", "redis_password", "prometheus_token",
+	"notion_token", "todoist_token", "trello_key", "trello_token",
+	"google_api_key", "google_client_id", "google_client_secret", "google_refresh_token",
+	"virustotal_api_key",
+	"spotify_client_id", "spotify_client_secret",
+	"newsapi_key", "alphavantage_key",
+	"pushover_token", "pushover_user",
+	"facebook_ads_token",
+	"amazon_sp_client_id", "amazon_sp_client_secret", "amazon_sp_refresh_token", "amazon_sp_access_token",
+	"shopify_token",
+}
+
+// IsSecret reports whether key is stored through the secret backend
+func IsSecret(key string) bool {
+	key = normalizeKey(key)
+	for _, k := range secretKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Secrets returns the active profile's non-empty secret values keyed by
+// config key, for scrubbing them from logs and recordings
+func Secrets(ctx context.Context) map[string]string {
+	cfg, err := Resolve(ctx)
+	if err != nil {
+		return nil
+	}
+	secrets := map[string]string{}
+	for _, key := range secretKeys {
+		if val, _ := cfg.get(key); val != "" {
+			secrets[key] = val
+		}
+	}
+	return secrets
+}
+
+// SecretBackend stores the secret config values outside config.json
+type SecretBackend interface {
+	Load() (map[string]string, error)
+	Store(secrets map[string]string) error
+}
+
+// secretBackend returns the backend configured in cfg, or nil for plaintext.
+// POCKET_SECRET_* variables override the file settings.
+func secretBackend(file *Config) (SecretBackend, error) {
+	cfg := &Config{
+		SecretBackend:      file.SecretBackend,
+		SecretFile:         file.SecretFile,
+		SecretKeyFile:      file.SecretKeyFile,
+		SecretCommand:      file.SecretCommand,
+		SecretStoreCommand: file.SecretStoreCommand,
+	}
+	cfg.applyEnv()
+
+	switch cfg.SecretBackend {
+	case "":
+		return nil, nil
+	case BackendAge:
+		path := cfg.SecretFile
+		if path == "" {
+			path = filepath.Join(filepath.Dir(Path()), "secrets.age")
+		}
+		return &ageBackend{path: path, keyFile: cfg.SecretKeyFile}, nil
+	case BackendCommand:
+		if cfg.SecretCommand == "" {
+			return nil, errors.New("secret_backend is command but secret_command is not set")
+		}
+		return &commandBackend{load: cfg.SecretCommand, store: cfg.SecretStoreCommand}, nil
+	default:
+		return nil, fmt.Errorf("unknown secret_backend: %s (use %s or %s)", cfg.SecretBackend, BackendAge, BackendCommand)
+	}
+}
+
+// loadSecrets fills the secret fields of cfg and its profiles from the
+// backend. Profile secrets are stored as "<profile>.<key>".
+func loadSecrets(cfg *Config) error {
+	backend, err := secretBackend(cfg)
+	if err != nil || backend == nil {
+		return err
+	}
+
+	secrets, err := backend.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load secrets: %w", err)
+	}
+
+	for name, val := range secrets {
+		target, key := secretTarget(cfg, name)
+		if target == nil {
+			if cfg.kept == nil {
+				cfg.kept = map[string]string{}
+			}
+			cfg.kept[name] = val
+			continue
+		}
+		if err := target.set(key, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// secretTarget returns the config and key a backend entry belongs to, or
+// nil when its profile does not exist or its key is not a secret
+func secretTarget(cfg *Config, name string) (*Config, string) {
+	target, key := cfg, name
+	if profile, k, ok := strings.Cut(name, "."); ok {
+		target, key = cfg.Profiles[profile], k
+	}
+	if target == nil || !IsSecret(key) {
+		return nil, ""
+	}
+	return target, key
+}
+
+// storeSecrets moves the secret fields of cfg and its profiles into the
+// backend, leaving cfg with only the values that belong in config.json
+func storeSecrets(cfg *Config) error {
+	backend, err := secretBackend(cfg)
+	if err != nil || backend == nil {
+		return err
+	}
+
+	// Entries Load could not place go back as they were, unless their
+	// profile has been created since
+	secrets := make(map[string]string)
+	for name, val := range cfg.kept {
+		if target, _ := secretTarget(cfg, name); target == nil {
+			secrets[name] = val
+		}
+	}
+	if err := takeSecrets(cfg, "", secrets); err != nil {
+		return err
+	}
+	if len(cfg.Profiles) > 0 {
+		// Copy profiles so the caller's config keeps its secrets
+		profiles := make(map[string]*Config, len(cfg.Profiles))
+		for name, p := range cfg.Profiles {
+			plain := *p
+			plain.Plugins = maps.Clone(p.Plugins)
+			if err := takeSecrets(&plain, name+".", secrets); err != nil {
+				return err
+			}
+			profiles[name] = &plain
+		}
+		cfg.Profiles = profiles
+	}
+
+	if err := backend.Store(secrets); err != nil {
+		return fmt.Errorf("failed to store secrets: %w", err)
+	}
+	return nil
+}
+
+func takeSecrets(cfg *Config, prefix string, secrets map[string]string) error {
+	for _, key := range secretKeys {
+		val, err := cfg.get(key)
+		if err != nil {
+			return err
+		}
+		if val != "" {
+			secrets[prefix+key] = val
+		}
+		if err := cfg.set(key, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ageBackend keeps secrets in an age-encrypted JSON file, unlocked by an age
+// identity file or by a passphrase from POCKET_SECRETS_PASSPHRASE
+type ageBackend struct {
+	path    string
+	keyFile string
+}
+
+// ageCache avoids re-running scrypt on every config.Get in one process
+var ageCache struct {
+	sync.Mutex
+	path    string
+	modTime time.Time
+	size    int64
+	secrets map[string]string
+}
+
+func (b *ageBackend) Load() (map[string]string, error) {
+	info, err := os.Stat(b.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+
+	ageCache.Lock()
+	defer ageCache.Unlock()
+	if ageCache.path == b.path && ageCache.modTime.Equal(info.ModTime()) && ageCache.size == info.Size() {
+		return maps.Clone(ageCache.secrets), nil
+	}
+
+	identities, err := b.identities()
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(b.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := age.Decrypt(f, identities...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decrypt %s: %w", b.path, err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", b.path, err)
+	}
+
+	ageCache.path, ageCache.modTime, ageCache.size = b.path, info.ModTime(), info.Size()
+	ageCache.secrets = maps.Clone(secrets)
+	return secrets, nil
+}
+
+func (b *ageBackend) Store(secrets map[string]string) error {
+	recipient, err := b.recipient()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipient)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(b.path), 0o700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(b.path, buf.Bytes(), 0o600); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(b.path); err == nil {
+		ageCache.Lock()
+		ageCache.path, ageCache.modTime, ageCache.size = b.path, info.ModTime(), info.Size()
+		ageCache.secrets = maps.Clone(secrets)
+		ageCache.Unlock()
+	}
+	return nil
+}
+
+func (b *ageBackend) identities() ([]age.Identity, error) {
+	if b.keyFile != "" {
+		f, err := os.Open(b.keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read secret_key_file: %w", err)
+		}
+		defer f.Close()
+		return age.ParseIdentities(f)
+	}
+
+	passphrase := os.Getenv(PassphraseEnv)
+	if passphrase == "" {
+		return nil, fmt.Errorf("secrets are locked: set %s or secret_key_file", PassphraseEnv)
+	}
+	id, err := age.NewScryptIdentity(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return []age.Identity{id}, nil
+}
+
+func (b *ageBackend) recipient() (age.Recipient, error) {
+	if b.keyFile == "" {
+		passphrase := os.Getenv(PassphraseEnv)
+		if passphrase == "" {
+			return nil, fmt.Errorf("secrets are locked: set %s or secret_key_file", PassphraseEnv)
+		}
+		r, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return nil, err
+		}
+		r.SetWorkFactor(scryptWorkFactor)
+		return r, nil
+	}
+
+	identities, err := b.identities()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range identities {
+		if x, ok := id.(*age.X25519Identity); ok {
+			return x.Recipient(), nil
+		}
+	}
+	return nil, fmt.Errorf("secret_key_file %s has no X25519 identity (generate one with age-keygen)", b.keyFile)
+}
+
+// commandBackend delegates to external tools such as pass or sops. The load
+// command prints a JSON object of secrets; the store command reads one on stdin.
+type commandBackend struct {
+	load  string
+	store string
+}
+
+// commandCache holds what secret_command printed, so the command runs once
+// per process rather than on every config read. Secrets the process stores
+// itself are kept too, and Reload drops the copy.
+var commandCache struct {
+	sync.Mutex
+	command string
+	secrets map[string]string
+}
+
+// Reload makes the next config read run secret_command again
+func Reload() {
+	commandCache.Lock()
+	defer commandCache.Unlock()
+	commandCache.command, commandCache.secrets = "", nil
+}
+
+func (b *commandBackend) Load() (map[string]string, error) {
+	commandCache.Lock()
+	defer commandCache.Unlock()
+	if commandCache.secrets != nil && commandCache.command == b.load {
+		return maps.Clone(commandCache.secrets), nil
+	}
+
+	out, err := runShell(b.load, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := map[string]string{}
+	if len(bytes.TrimSpace(out)) > 0 {
+		if err := json.Unmarshal(out, &secrets); err != nil {
+			return nil, fmt.Errorf("secret_command must print a JSON object: %w", err)
+		}
+	}
+	commandCache.command, commandCache.secrets = b.load, maps.Clone(secrets)
+	return secrets, nil
+}
+
+func (b *commandBackend) Store(secrets map[string]string) error {
+	if b.store == "" {
+		// Without a store command the backend is read-only, which is fine as
+		// long as nothing secret changed
+		current, err := b.Load()
+		if err != nil {
+			return err
+		}
+		if maps.Equal(current, secrets) {
+			return nil
+		}
+		return errors.New("secret_store_command is not set, so secrets cannot be changed")
+	}
+
+	data, err := json.MarshalIndent(secrets, "", "  ")
+	if err != nil {
+		return err
+	}
+	if _, err := runShell(b.store, data); err != nil {
+		return err
+	}
+
+	commandCache.Lock()
+	defer commandCache.Unlock()
+	commandCache.command, commandCache.secrets = b.load, maps.Clone(secrets)
+	return nil
+}
+
+func runShell(command string, stdin []byte) ([]byte, error) {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command)
+	} else {
+		cmd = exec.Command("sh", "-c", command)
+	}
+	if stdin != nil {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("%s: %s", strings.Fields(command)[0], msg)
+	}
+	return out, nil
+}
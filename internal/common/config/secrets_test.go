@@ -0,0 +1,280 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+)
+
+func writeKeyFile(t *testing.T) string {
+	t.Helper()
+	id, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatalf("keygen failed: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "key.txt")
+	if err := os.WriteFile(path, []byte(id.String()+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestAgeBackendKeyFile(t *testing.T) {
+	ctx := context.Background()
+	tmpPath := setupTempConfig(t)
+	keyFile := writeKeyFile(t)
+
+	if err := Set(ctx, "github_token", "ghp_plaintext_first"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(ctx, "secret_key_file", keyFile); err != nil {
+		t.Fatal(err)
+	}
+	// Switching the backend on migrates existing secrets out of config.json
+	if err := Set(ctx, "secret_backend", BackendAge); err != nil {
+		t.Fatalf("enable backend failed: %v", err)
+	}
+	if err := Set(ctx, "slack_token", "xoxb-secret"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(ctx, "jira_url", "https://jira.example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(tmpPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"ghp_plaintext_first", "xoxb-secret"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("config.json still contains %q", secret)
+		}
+	}
+	if !strings.Contains(string(data), "jira.example.com") {
+		t.Error("non-secret values should stay in config.json")
+	}
+
+	enc, err := os.ReadFile(filepath.Join(filepath.Dir(tmpPath), "secrets.age"))
+	if err != nil {
+		t.Fatalf("expected secrets.age: %v", err)
+	}
+	if strings.Contains(string(enc), "xoxb-secret") {
+		t.Error("secrets.age is not encrypted")
+	}
+
+	for key, want := range map[string]string{
+		"github_token": "ghp_plaintext_first",
+		"slack_token":  "xoxb-secret",
+		"jira_url":     "https://jira.example.com",
+	} {
+		got, err := Get(ctx, key)
+		if err != nil {
+			t.Fatalf("get %s failed: %v", key, err)
+		}
+		if got != want {
+			t.Errorf("%s: expected %q, got %q", key, want, got)
+		}
+	}
+}
+
+func TestAgeBackendPassphrase(t *testing.T) {
+	ctx := context.Background()
+	tmpPath := setupTempConfig(t)
+	oldFactor := scryptWorkFactor
+	scryptWorkFactor = 10
+	t.Cleanup(func() { scryptWorkFactor = oldFactor })
+
+	secretFile := filepath.Join(filepath.Dir(tmpPath), "vault.age")
+	t.Setenv(PassphraseEnv, "correct horse")
+
+	if err := Save(&Config{SecretBackend: BackendAge, SecretFile: secretFile, TelegramToken: "123:abc"}); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	val, err := Get(ctx, "telegram_token")
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if val != "123:abc" {
+		t.Errorf("expected 123:abc, got %q", val)
+	}
+
+	// A fresh process with the wrong passphrase cannot decrypt
+	ageCache.path = ""
+	t.Setenv(PassphraseEnv, "wrong")
+	if _, err := Load(); err == nil {
+		t.Error("expected decrypt error with wrong passphrase")
+	}
+
+	ageCache.path = ""
+	t.Setenv(PassphraseEnv, "")
+	_, err = Load()
+	if err == nil || !strings.Contains(err.Error(), PassphraseEnv) {
+		t.Errorf("expected locked error naming %s, got %v", PassphraseEnv, err)
+	}
+}
+
+func TestCommandBackend(t *testing.T) {
+	ctx := context.Background()
+	tmpPath := setupTempConfig(t)
+	store := filepath.Join(filepath.Dir(tmpPath), "store.json")
+
+	cfg := &Config{
+		SecretBackend:      BackendCommand,
+		SecretCommand:      "cat " + store + " 2>/dev/null || true",
+		SecretStoreCommand: "cat > " + store,
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	if err := Set(ctx, "github_token", "ghp_from_command"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Set(ctx, "sentry_auth_token", "sntrys_new"); err != nil {
+		t.Fatalf("set through command failed: %v", err)
+	}
+
+	data, _ := os.ReadFile(store)
+	if !strings.Contains(string(data), "sntrys_new") {
+		t.Errorf("store command did not receive secrets: %s", data)
+	}
+
+	val, err := Get(ctx, "github_token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val != "ghp_from_command" {
+		t.Errorf("expected ghp_from_command, got %q", val)
+	}
+}
+
+func TestUnplacedSecretsKept(t *testing.T) {
+	ctx := context.Background()
+	tmpPath := setupTempConfig(t)
+	store := filepath.Join(filepath.Dir(tmpPath), "store.json")
+	t.Cleanup(Reload)
+
+	cfg := &Config{
+		SecretBackend:      BackendCommand,
+		SecretCommand:      "cat " + store + " 2>/dev/null || true",
+		SecretStoreCommand: "cat > " + store,
+	}
+	if err := Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	// A profile missing from config.json and a plugin that is not installed
+	secrets := `{"github_token":"ghp_default","gone.github_token":"ghp_gone","acme_token":"acme"}`
+	if err := os.WriteFile(store, []byte(secrets), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	Reload()
+
+	if err := Set(ctx, "sentry_auth_token", "sntrys_new"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(store)
+	for _, want := range []string{"sntrys_new", "ghp_default", "ghp_gone", "acme"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("store lost %s: %s", want, data)
+		}
+	}
+
+	// Once the profile exists its own values win
+	if err := CopyProfile(DefaultProfile, "gone"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ = os.ReadFile(store)
+	if strings.Contains(string(data), "ghp_gone") || !strings.Contains(string(data), "acme") {
+		t.Errorf("unexpected store after creating the profile: %s", data)
+	}
+}
+
+func TestCommandBackendReadOnly(t *testing.T) {
+	ctx := context.Background()
+	tmpPath := setupTempConfig(t)
+	store := filepath.Join(filepath.Dir(tmpPath), "store.json")
+	if err := os.WriteFile(store, []byte(`{"github_token":"ghp_ro"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tmpPath, []byte(`{"secret_backend":"command","secret_command":"cat `+store+`"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Non-secret changes still work against a read-only backend
+	if err := Set(ctx, "sentry_org", "acme"); err != nil {
+		t.Fatalf("non-secret set failed: %v", err)
+	}
+	if err := Set(ctx, "github_token", "ghp_changed"); err == nil {
+		t.Error("expected error changing a secret without secret_store_command")
+	}
+}
+
+func TestCommandBackendRunsOnce(t *testing.T) {
+	ctx := context.Background()
+	tmpPath := setupTempConfig(t)
+	dir := filepath.Dir(tmpPath)
+	store, runs := filepath.Join(dir, "store.json"), filepath.Join(dir, "runs")
+	if err := os.WriteFile(store, []byte(`{"github_token":"ghp_warm"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	file := `{"secret_backend":"command","secret_command":"echo >> ` + runs + `; cat ` + store + `","secret_store_command":"cat > ` + store + `"}`
+	if err := os.WriteFile(tmpPath, []byte(file), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(Reload)
+	countRuns := func() int {
+		data, _ := os.ReadFile(runs)
+		return strings.Count(string(data), "\n")
+	}
+
+	for range 3 {
+		if v, _ := Get(ctx, "github_token"); v != "ghp_warm" {
+			t.Fatalf("github_token = %q", v)
+		}
+	}
+	if n := countRuns(); n != 1 {
+		t.Errorf("secret_command ran %d times, want 1", n)
+	}
+
+	// What this process stores is kept without running the command again
+	if err := Set(ctx, "github_token", "ghp_stored"); err != nil {
+		t.Fatal(err)
+	}
+	before := countRuns()
+	if v, _ := Get(ctx, "github_token"); v != "ghp_stored" || countRuns() != before {
+		t.Errorf("after Set: github_token = %q, %d more runs", v, countRuns()-before)
+	}
+
+	// Changes made elsewhere show up after Reload
+	if err := os.WriteFile(store, []byte(`{"github_token":"ghp_outside"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	Reload()
+	if v, _ := Get(ctx, "github_token"); v != "ghp_outside" {
+		t.Errorf("after Reload: github_token = %q", v)
+	}
+}
+
+func TestUnknownSecretBackend(t *testing.T) {
+	tmpPath := setupTempConfig(t)
+	if err := os.WriteFile(tmpPath, []byte(`{"secret_backend":"vault"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+}
+
+func TestIsSecret(t *testing.T) {
+	if !IsSecret("github-token") {
+		t.Error("github-token should be secret")
+	}
+	if IsSecret("jira_url") {
+		t.Error("jira_url should not be secret")
+	}
+}
@@ -0,0 +1,245 @@
+package dryrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/unstablemind/pocket/internal/common/cassette"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// ErrorCode marks an action that was planned instead of performed
+const ErrorCode = "dry_run"
+
+// ErrSkipped is returned in place of performing a planned action
+var ErrSkipped error = skippedError{}
+
+// skippedError reports ErrSkipped as dry_run in whichever command it
+// surfaces
+type skippedError struct{}
+
+func (skippedError) Error() string     { return "dry run: not sent" }
+func (skippedError) ErrorCode() string { return ErrorCode }
+func (skippedError) ErrorDetails() any { return nil }
+
+// Request is one action a command would have performed. HTTP requests
+// fill Method, URL and Headers; other protocols name a Target (server,
+// database, file) and the Command sent to it.
+type Request struct {
+	Protocol string      `json:"protocol"`
+	Method   string      `json:"method,omitempty"`
+	URL      string      `json:"url,omitempty"`
+	Headers  http.Header `json:"headers,omitempty"`
+	Target   string      `json:"target,omitempty"`
+	Command  string      `json:"command,omitempty"`
+	// Body is embedded as JSON when it parses as JSON, else as text
+	Body any `json:"body,omitempty"`
+}
+
+// Plan is printed in place of a dry-run command's output
+type Plan struct {
+	DryRun   bool      `json:"dry_run"`
+	Command  string    `json:"command"`
+	Requests []Request `json:"requests"`
+}
+
+// runState is dry-run mode for one command run
+type runState struct {
+	sync.Mutex
+	enabled bool
+	writes  bool
+	command string
+	planned []Request
+	held    bytes.Buffer
+	heldErr bytes.Buffer
+	out     io.Writer
+	errOut  io.Writer
+}
+
+type stateKey struct{}
+
+// defaultState is dry-run mode for code running outside NewContext
+var defaultState = &runState{}
+
+// NewContext returns a copy of ctx with a dry-run state of its own, so
+// commands running alongside each other plan separately
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stateKey{}, &runState{})
+}
+
+// stateOf returns the dry-run state carried by ctx
+func stateOf(ctx context.Context) *runState {
+	if s, ok := ctx.Value(stateKey{}).(*runState); ok {
+		return s
+	}
+	return defaultState
+}
+
+// Begin turns dry-run on for command, run with ctx. writes says whether
+// the command changes anything remotely; when it doesn't, its HTTP
+// requests are sent even if they are POSTs, and only local side effects
+// are planned. Output and errors printed until Finish are held back, since
+// a command whose write was skipped reports it as a failure.
+func Begin(ctx context.Context, command string, writes bool) {
+	state := stateOf(ctx)
+	state.Lock()
+	defer state.Unlock()
+	state.enabled, state.writes, state.command, state.planned = true, writes, command, nil
+	state.held.Reset()
+	state.heldErr.Reset()
+	state.out, state.errOut = output.Writer(ctx), output.ErrorWriter(ctx)
+	output.SetOutput(ctx, &state.held)
+	output.SetErrorOutput(ctx, &state.heldErr)
+}
+
+// Enabled reports whether writes are being planned instead of performed
+func Enabled(ctx context.Context) bool {
+	state := stateOf(ctx)
+	state.Lock()
+	defer state.Unlock()
+	return state.enabled
+}
+
+// Record adds r to the plan, scrubbing configured secrets, and returns
+// ErrSkipped so the caller stops before acting
+func Record(ctx context.Context, r Request) error {
+	r.Target = cassette.Redact(ctx, r.Target)
+	r.Command = cassette.Redact(ctx, r.Command)
+	if s, ok := r.Body.(string); ok {
+		r.Body = body(cassette.Redact(ctx, s), "")
+	}
+
+	state := stateOf(ctx)
+	state.Lock()
+	defer state.Unlock()
+	state.planned = append(state.planned, r)
+	return ErrSkipped
+}
+
+// Exec records running a local program for --dry-run and returns ErrSkipped
+func Exec(ctx context.Context, args ...string) error {
+	return Record(ctx, Request{Protocol: "exec", Command: Join(args)})
+}
+
+// AppleScript records running script with osascript for --dry-run and
+// returns ErrSkipped
+func AppleScript(ctx context.Context, script string) error {
+	return Record(ctx, Request{Protocol: "applescript", Command: "osascript", Body: script})
+}
+
+// Finish ends dry-run mode. If anything was planned it prints the plan and
+// returns nil; otherwise it releases the held output and returns err.
+func Finish(ctx context.Context, err error) error {
+	state := stateOf(ctx)
+	state.Lock()
+	if !state.enabled {
+		state.Unlock()
+		return err
+	}
+	plan := Plan{DryRun: true, Command: state.command, Requests: state.planned}
+	held := append([]byte(nil), state.held.Bytes()...)
+	heldErr := append([]byte(nil), state.heldErr.Bytes()...)
+	output.SetOutput(ctx, state.out)
+	output.SetErrorOutput(ctx, state.errOut)
+	state.enabled, state.writes, state.command, state.planned = false, false, "", nil
+	state.held.Reset()
+	state.heldErr.Reset()
+	state.Unlock()
+
+	if len(plan.Requests) == 0 {
+		_, _ = output.Writer(ctx).Write(held)
+		_, _ = output.ErrorWriter(ctx).Write(heldErr)
+		return err
+	}
+	return output.Print(ctx, plan)
+}
+
+// Join formats a command's words for display, quoting any that contain
+// spaces, quotes or control characters
+func Join(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		if w == "" || strings.ContainsFunc(w, func(r rune) bool {
+			return unicode.IsSpace(r) || unicode.IsControl(r) || r == '"' || r == '\''
+		}) {
+			w = strconv.Quote(w)
+		}
+		quoted[i] = w
+	}
+	return strings.Join(quoted, " ")
+}
+
+func body(text, b64 string) any {
+	switch {
+	case b64 != "":
+		return map[string]string{"base64": b64}
+	case text == "":
+		return nil
+	case json.Valid([]byte(text)):
+		return json.RawMessage(text)
+	}
+	return text
+}
+
+type readOnlyKey struct{}
+
+// ReadOnly marks requests made with ctx as reads, so they are sent even in
+// dry-run mode. Use it for POSTs that don't change anything, such as OAuth
+// token refreshes and GraphQL queries.
+func ReadOnly(ctx context.Context) context.Context {
+	return context.WithValue(ctx, readOnlyKey{}, true)
+}
+
+func isReadOnly(req *http.Request) bool {
+	state := stateOf(req.Context())
+	state.Lock()
+	writes := state.writes
+	state.Unlock()
+	if !writes {
+		return true
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	ro, _ := req.Context().Value(readOnlyKey{}).(bool)
+	return ro
+}
+
+// Transport plans mutating requests instead of sending them while dry-run
+// is on, and passes everything else to Base
+type Transport struct {
+	Base http.RoundTripper
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !Enabled(req.Context()) || isReadOnly(req) {
+		return t.Base.RoundTrip(req)
+	}
+	if req.Body != nil {
+		defer req.Body.Close()
+	}
+
+	rec, err := cassette.RedactRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	state := stateOf(req.Context())
+	state.Lock()
+	state.planned = append(state.planned, Request{
+		Protocol: "http",
+		Method:   rec.Method,
+		URL:      rec.URL,
+		Headers:  rec.Header,
+		Body:     body(rec.Body, rec.BodyBase64),
+	})
+	state.Unlock()
+	return nil, ErrSkipped
+}
@@ -0,0 +1,206 @@
+package dryrun
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+func TestMain(m *testing.M) {
+	// Keep the redactor away from the real config
+	dir, _ := os.MkdirTemp("", "dryrun")
+	os.Setenv("POCKET_CONFIG", filepath.Join(dir, "config.json"))
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+type planResponse struct {
+	Success bool `json:"success"`
+	Data    Plan `json:"data"`
+	Error   *struct {
+		Code string `json:"code"`
+	} `json:"error"`
+}
+
+// run executes fn in dry-run mode and returns what was printed
+func run(t *testing.T, writes bool, fn func() error) (string, error) {
+	t.Helper()
+	ctx := context.Background()
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	t.Cleanup(func() { output.SetOutput(ctx, nil) })
+
+	Begin(ctx, "test command", writes)
+	err := Finish(ctx, fn())
+	return buf.String(), err
+}
+
+func server(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func TestTransportPlansWrites(t *testing.T) {
+	ctx := context.Background()
+	srv, hits := server(t)
+	client := &http.Client{Transport: &Transport{Base: http.DefaultTransport}}
+
+	out, err := run(t, true, func() error {
+		// Reads still go through
+		resp, err := client.Get(srv.URL + "/lookup")
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+
+		req, _ := http.NewRequest(http.MethodPost, srv.URL+"/send?api_key=k123", strings.NewReader(`{"text":"hi","password":"p"}`))
+		req.Header.Set("Authorization", "Bearer abc")
+		req.Header.Set("Content-Type", "application/json")
+		_, err = client.Do(req)
+		if !errors.Is(err, ErrSkipped) {
+			t.Errorf("expected ErrSkipped, got %v", err)
+		}
+		return output.PrintError(ctx, "send_failed", err.Error(), nil)
+	})
+	if err != nil {
+		t.Fatalf("Finish returned %v", err)
+	}
+	if hits.Load() != 1 {
+		t.Errorf("server hit %d times, want 1 (the GET)", hits.Load())
+	}
+
+	var resp planResponse
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("output is not one JSON document: %v\n%s", err, out)
+	}
+	if !resp.Success || !resp.Data.DryRun || len(resp.Data.Requests) != 1 {
+		t.Fatalf("unexpected plan: %s", out)
+	}
+	r := resp.Data.Requests[0]
+	if r.Protocol != "http" || r.Method != http.MethodPost || !strings.HasSuffix(r.URL, "/send?api_key=REDACTED") {
+		t.Errorf("request = %+v", r)
+	}
+	if got := r.Headers.Get("Authorization"); got != "REDACTED" {
+		t.Errorf("Authorization = %q", got)
+	}
+	body, _ := json.Marshal(r.Body)
+	if string(body) != `{"password":"REDACTED","text":"hi"}` {
+		t.Errorf("body = %s", body)
+	}
+}
+
+func TestReadOnlyRequestsAreSent(t *testing.T) {
+	srv, hits := server(t)
+	client := &http.Client{Transport: &Transport{Base: http.DefaultTransport}}
+
+	// A POST marked read-only in a write command
+	_, err := run(t, true, func() error {
+		req, _ := http.NewRequestWithContext(ReadOnly(context.Background()), http.MethodPost, srv.URL+"/graphql", strings.NewReader(`{"query":"{ me }"}`))
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Any POST in a read command
+	_, err = run(t, false, func() error {
+		resp, err := client.Post(srv.URL+"/search", "application/json", strings.NewReader(`{}`))
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hits.Load() != 2 {
+		t.Errorf("server hit %d times, want 2", hits.Load())
+	}
+}
+
+func TestFinishWithoutPlanReleasesOutput(t *testing.T) {
+	ctx := context.Background()
+	failure := errors.New("boom")
+	out, err := run(t, true, func() error {
+		_ = output.Print(ctx, map[string]string{"status": "ok"})
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("err = %v, want the command's error", err)
+	}
+	if !strings.Contains(out, `"status":"ok"`) {
+		t.Errorf("held output not released: %q", out)
+	}
+	if Enabled(ctx) {
+		t.Error("still enabled after Finish")
+	}
+
+	// Outside dry-run mode Finish is a no-op
+	if err := Finish(ctx, failure); !errors.Is(err, failure) {
+		t.Errorf("Finish outside dry-run = %v", err)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	ctx := context.Background()
+	out, err := run(t, true, func() error {
+		if err := Exec(ctx, "aws", "s3", "cp", "my file.txt", "s3://b/"); !errors.Is(err, ErrSkipped) {
+			t.Errorf("Exec returned %v", err)
+		}
+		return Record(ctx, Request{Protocol: "file", Target: "/tmp/feeds.json", Command: "write", Body: `[{"a":1}]`})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp planResponse
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data.Requests) != 2 {
+		t.Fatalf("requests = %+v", resp.Data.Requests)
+	}
+	if got := resp.Data.Requests[0].Command; got != `aws s3 cp "my file.txt" s3://b/` {
+		t.Errorf("command = %s", got)
+	}
+	body, _ := json.Marshal(resp.Data.Requests[1].Body)
+	if string(body) != `[{"a":1}]` {
+		t.Errorf("JSON body not embedded: %s", body)
+	}
+}
+
+func TestSkippedErrorCode(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	t.Cleanup(func() { output.SetOutput(ctx, nil) })
+
+	_ = output.PrintErr(ctx, "send_failed", &url.Error{Op: "Post", URL: "https://x", Err: ErrSkipped}, nil)
+	var resp planResponse
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil || resp.Error == nil || resp.Error.Code != ErrorCode {
+		t.Errorf("got %s", buf.String())
+	}
+}
@@ -0,0 +1,49 @@
+// Package jsonrpc holds the JSON-RPC 2.0 framing shared by pocket mcp and
+// pocket serve
+package jsonrpc
+
+import "encoding/json"
+
+// Version is the jsonrpc member of every request and response
+const Version = "2.0"
+
+// Error codes defined by JSON-RPC 2.0
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+)
+
+// Request is a call, or a notification when it has no ID
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// Response answers a call with either a result or an error
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is the error member of a failed call's response
+type Error struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Result returns the response to call id with result v
+func Result(id json.RawMessage, v any) *Response {
+	return &Response{JSONRPC: Version, ID: id, Result: v}
+}
+
+// ErrorResponse returns the response to call id failing with code and msg. Use a
+// null id when the call's own couldn't be read.
+func ErrorResponse(id json.RawMessage, code int, msg string) *Response {
+	return &Response{JSONRPC: Version, ID: id, Error: &Error{Code: code, Message: msg}}
+}
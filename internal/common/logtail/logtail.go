@@ -0,0 +1,23 @@
+// Package logtail trims CI job logs to their last lines, as the GitHub and
+// GitLab log commands return them
+package logtail
+
+import "strings"
+
+// Tail joins the last n lines, dropping whole lines from the front to fit
+// in maxBytes, and reports whether anything was left out
+func Tail(lines []string, n, maxBytes int) (string, bool) {
+	truncated := false
+	if n = max(n, 0); len(lines) > n {
+		lines, truncated = lines[len(lines)-n:], true
+	}
+	size := 0
+	for i := len(lines) - 1; i >= 0; i-- {
+		size += len(lines[i]) + 1
+		if size > maxBytes {
+			lines, truncated = lines[i+1:], true
+			break
+		}
+	}
+	return strings.Join(lines, "\n"), truncated
+}
@@ -0,0 +1,19 @@
+package logtail
+
+import "testing"
+
+func TestTail(t *testing.T) {
+	lines := []string{"aaaa", "bbbb", "cccc"}
+	if got, cut := Tail(lines, 5, 100); got != "aaaa\nbbbb\ncccc" || cut {
+		t.Errorf("Tail() = %q, %v", got, cut)
+	}
+	if got, cut := Tail(lines, 2, 100); got != "bbbb\ncccc" || !cut {
+		t.Errorf("Tail() of 2 = %q, %v", got, cut)
+	}
+	if got, cut := Tail(lines, 5, 10); got != "bbbb\ncccc" || !cut {
+		t.Errorf("Tail() within 10 bytes = %q, %v", got, cut)
+	}
+	if got, cut := Tail(lines, -1, 100); got != "" || !cut {
+		t.Errorf("Tail() of -1 = %q, %v", got, cut)
+	}
+}
@@ -0,0 +1,209 @@
+// Package paginate gives list commands --limit, --all, --page-size and
+// --cursor over whatever paging their API uses. Commands opt in with
+// AddFlags and Run; those that don't return a single page.
+package paginate
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// Options holds a list command's --limit, --all, --page-size and --cursor
+type Options struct {
+	Limit    int
+	All      bool
+	PageSize int
+	Cursor   string
+	// MaxPageSize is the most items the API returns per request
+	MaxPageSize int
+
+	// pageSize is the --page-size flag, to tell 0 given from the default
+	pageSize *pflag.Flag
+}
+
+// AddFlags registers --limit (with the command's default), --all,
+// --page-size and --cursor. maxPageSize is the API's page size cap.
+func AddFlags(cmd *cobra.Command, o *Options, limit, maxPageSize int, noun string) {
+	o.MaxPageSize = maxPageSize
+	cmd.Flags().IntVarP(&o.Limit, "limit", "l", limit, "Number of "+noun)
+	cmd.Flags().BoolVar(&o.All, "all", false, "Fetch every page, ignoring --limit")
+	cmd.Flags().IntVar(&o.PageSize, "page-size", 0, fmt.Sprintf("Items per request (default: --limit, at most %d)", maxPageSize))
+	cmd.Flags().StringVar(&o.Cursor, "cursor", "", "Continue from the next_cursor of an earlier response")
+	o.pageSize = cmd.Flags().Lookup("page-size")
+}
+
+// validate rejects a negative --limit and a --page-size below 1
+func (o Options) validate() error {
+	if o.Limit < 0 {
+		return fmt.Errorf("--limit can't be negative, got %d", o.Limit)
+	}
+	if o.PageSize < 0 || (o.pageSize != nil && o.pageSize.Changed && o.PageSize < 1) {
+		return fmt.Errorf("--page-size must be at least 1, got %d", o.PageSize)
+	}
+	return nil
+}
+
+// Page is one response from the API. Next is the API's cursor for the
+// following page (a URL, token or offset), empty on the last page.
+type Page[T any] struct {
+	Items []T
+	Next  string
+}
+
+// Fetcher requests the page at cursor ("" for the first) with up to size
+// items
+type Fetcher[T any] func(cursor string, size int) (Page[T], error)
+
+// position is what a pocket cursor encodes: the API cursor of a page, the
+// page size it was fetched with, and how many of its items were already
+// returned. Keeping the size means page-number and offset APIs land on the
+// same page when resuming.
+type position struct {
+	Cursor string `json:"c,omitempty"`
+	Size   int    `json:"n,omitempty"`
+	Skip   int    `json:"s,omitempty"`
+}
+
+func (p position) encode() string {
+	data, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(cursor string) (position, error) {
+	var p position
+	if cursor == "" {
+		return p, nil
+	}
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		err = json.Unmarshal(data, &p)
+	}
+	if err != nil || p.Skip < 0 || p.Size < 0 {
+		return p, errors.New("invalid cursor: pass the next_cursor of an earlier response")
+	}
+	return p, nil
+}
+
+// size picks the page size: --page-size, else the resumed cursor's, else
+// just enough for --limit, capped at the API maximum
+func (o Options) size(p position) int {
+	n := o.PageSize
+	if n <= 0 {
+		n = p.Size
+	}
+	if n <= 0 && !o.All {
+		n = o.Limit
+	}
+	if n <= 0 || (o.MaxPageSize > 0 && n > o.MaxPageSize) {
+		n = o.MaxPageSize
+	}
+	return n
+}
+
+// Run fetches pages until --limit items have been printed, or every page
+// with --all, and prints them as one list with the cursor for the rest.
+// Errors from fetch are returned as they are, for the caller to report.
+func Run[T any](ctx context.Context, o Options, fetch Fetcher[T]) error {
+	if err := o.validate(); err != nil {
+		return output.PrintErr(ctx, "invalid_input", err, nil)
+	}
+	pos, err := decode(o.Cursor)
+	if err != nil {
+		return output.PrintErr(ctx, "invalid_cursor", err, nil)
+	}
+	size := o.size(pos)
+	remaining := o.Limit
+	list := output.NewList(ctx)
+
+	for {
+		page, err := fetch(pos.Cursor, size)
+		if err != nil {
+			return err
+		}
+		items := page.Items
+		if pos.Skip < len(items) {
+			items = items[pos.Skip:]
+		} else {
+			items = nil
+		}
+
+		next := ""
+		done := page.Next == "" || page.Next == pos.Cursor
+		if !done {
+			next = position{Cursor: page.Next, Size: size}.encode()
+		}
+		if !o.All && len(items) >= remaining {
+			if len(items) > remaining {
+				// Stop mid-page; the cursor picks up at the first item left out
+				next = position{Cursor: pos.Cursor, Size: size, Skip: pos.Skip + remaining}.encode()
+			}
+			items, done = items[:remaining], true
+		}
+
+		for _, item := range items {
+			if err := list.Add(item); err != nil {
+				return err
+			}
+		}
+		remaining -= len(items)
+		if done {
+			return list.Close(next)
+		}
+		pos = position{Cursor: page.Next}
+	}
+}
+
+// NextLink returns the rel="next" URL of an RFC 8288 Link header, as sent
+// by GitHub, Shopify and Sentry. Sentry always sends a next link and marks
+// the last page with results="false".
+func NextLink(h http.Header) string {
+	for _, header := range h.Values("Link") {
+		for _, link := range strings.Split(header, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+			next, results := false, true
+			for _, param := range parts[1:] {
+				name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				value = strings.Trim(value, `"`)
+				switch strings.ToLower(name) {
+				case "rel":
+					next = next || strings.Contains(" "+value+" ", " next ")
+				case "results":
+					results = value != "false"
+				}
+			}
+			if next && results {
+				return target
+			}
+		}
+	}
+	return ""
+}
+
+// LinkURL parses a link-style cursor and checks that it points at the same
+// host as base, so a doctored cursor can't send credentials elsewhere
+func LinkURL(cursor, base string) (*url.URL, error) {
+	u, err := url.Parse(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor: %w", err)
+	}
+	b, err := url.Parse(base)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != b.Scheme || u.Host != b.Host {
+		return nil, fmt.Errorf("invalid cursor: %s is not on %s", u.Host, b.Host)
+	}
+	return u, nil
+}
@@ -0,0 +1,210 @@
+package paginate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// offsetAPI serves 0..total-1 with offset cursors, recording the requests
+type offsetAPI struct {
+	total    int
+	requests []string
+}
+
+func (a *offsetAPI) fetch(cursor string, size int) (Page[int], error) {
+	a.requests = append(a.requests, cursor+"/"+strconv.Itoa(size))
+	start, _ := strconv.Atoi(cursor)
+	var page Page[int]
+	for i := start; i < start+size && i < a.total; i++ {
+		page.Items = append(page.Items, i)
+	}
+	if end := start + size; end < a.total {
+		page.Next = strconv.Itoa(end)
+	}
+	return page, nil
+}
+
+type response struct {
+	Data       []int  `json:"data"`
+	NextCursor string `json:"next_cursor"`
+}
+
+func run(t *testing.T, o Options, api *offsetAPI) response {
+	t.Helper()
+	ctx := context.Background()
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	defer output.SetOutput(ctx, nil)
+	if err := Run(ctx, o, api.fetch); err != nil {
+		t.Fatal(err)
+	}
+	var resp response
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatalf("%v: %s", err, buf.String())
+	}
+	return resp
+}
+
+func TestRunLimit(t *testing.T) {
+	api := &offsetAPI{total: 10}
+	resp := run(t, Options{Limit: 3, MaxPageSize: 100}, api)
+	if len(resp.Data) != 3 || resp.NextCursor == "" {
+		t.Fatalf("response = %+v", resp)
+	}
+	if strings.Join(api.requests, ",") != "/3" {
+		t.Errorf("requests = %v", api.requests)
+	}
+
+	// The cursor continues where the first call stopped
+	api.requests = nil
+	resp = run(t, Options{Limit: 5, Cursor: resp.NextCursor, MaxPageSize: 100}, api)
+	if len(resp.Data) != 5 || resp.Data[0] != 3 || resp.Data[4] != 7 {
+		t.Errorf("second page = %v", resp.Data)
+	}
+	// The page size of the first call is kept
+	if strings.Join(api.requests, ",") != "3/3,6/3" {
+		t.Errorf("requests = %v", api.requests)
+	}
+}
+
+func TestRunStopsMidPage(t *testing.T) {
+	api := &offsetAPI{total: 10}
+	resp := run(t, Options{Limit: 5, PageSize: 4, MaxPageSize: 100}, api)
+	if len(resp.Data) != 5 {
+		t.Fatalf("data = %v", resp.Data)
+	}
+
+	// Resuming refetches the second page and skips what was returned
+	api.requests = nil
+	resp = run(t, Options{Limit: 10, Cursor: resp.NextCursor, MaxPageSize: 100}, api)
+	if len(resp.Data) != 5 || resp.Data[0] != 5 || resp.NextCursor != "" {
+		t.Errorf("response = %+v", resp)
+	}
+	if strings.Join(api.requests, ",") != "4/4,8/4" {
+		t.Errorf("requests = %v", api.requests)
+	}
+}
+
+func TestRunAll(t *testing.T) {
+	api := &offsetAPI{total: 250}
+	resp := run(t, Options{Limit: 20, All: true, MaxPageSize: 100}, api)
+	if len(resp.Data) != 250 || resp.NextCursor != "" {
+		t.Fatalf("got %d items, cursor %q", len(resp.Data), resp.NextCursor)
+	}
+	if strings.Join(api.requests, ",") != "/100,100/100,200/100" {
+		t.Errorf("requests = %v", api.requests)
+	}
+}
+
+func TestRunStreamsNDJSON(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	output.SetFormat(ctx, "ndjson")
+	defer func() {
+		output.SetOutput(ctx, nil)
+		output.SetFormat(ctx, "json")
+	}()
+
+	api := &offsetAPI{total: 5}
+	pages := 0
+	err := Run(ctx, Options{Limit: 4, PageSize: 2}, func(cursor string, size int) (Page[int], error) {
+		// Each page is written before the next is requested
+		if lines := strings.Count(buf.String(), "\n"); lines != pages*2 {
+			t.Errorf("before page %d: %d lines written", pages, lines)
+		}
+		pages++
+		return api.fetch(cursor, size)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 || lines[0] != "0" || !strings.HasPrefix(lines[4], `{"next_cursor":`) {
+		t.Errorf("lines = %q", lines)
+	}
+}
+
+func TestRunInvalidCursor(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	defer output.SetOutput(ctx, nil)
+	err := Run(ctx, Options{Limit: 1, Cursor: "not a cursor"}, (&offsetAPI{}).fetch)
+	if output.ErrorCode(err) != "invalid_cursor" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRunInvalidLimits(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	defer output.SetOutput(ctx, nil)
+
+	for _, args := range [][]string{{"-l", "-1"}, {"--page-size", "0"}, {"--page-size", "-5"}} {
+		var o Options
+		cmd := &cobra.Command{Use: "list"}
+		AddFlags(cmd, &o, 20, 100, "items")
+		if err := cmd.ParseFlags(args); err != nil {
+			t.Fatal(err)
+		}
+		api := &offsetAPI{total: 10}
+		if err := Run(ctx, o, api.fetch); output.ErrorCode(err) != "invalid_input" || len(api.requests) != 0 {
+			t.Errorf("%v: err = %v after %d requests", args, err, len(api.requests))
+		}
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{"none", "", ""},
+		{
+			"github",
+			`<https://api.github.com/user/repos?page=3>; rel="next", <https://api.github.com/user/repos?page=5>; rel="last"`,
+			"https://api.github.com/user/repos?page=3",
+		},
+		{"last page", `<https://api.github.com/user/repos?page=1>; rel="prev"`, ""},
+		{
+			"sentry",
+			`<https://sentry.io/api/0/x/?cursor=0:0:1>; rel="previous"; results="false"; cursor="0:0:1", ` +
+				`<https://sentry.io/api/0/x/?cursor=0:100:0>; rel="next"; results="true"; cursor="0:100:0"`,
+			"https://sentry.io/api/0/x/?cursor=0:100:0",
+		},
+		{"sentry last page", `<https://sentry.io/api/0/x/?cursor=0:200:0>; rel="next"; results="false"`, ""},
+	}
+	for _, tt := range tests {
+		h := http.Header{}
+		if tt.header != "" {
+			h.Set("Link", tt.header)
+		}
+		if got := NextLink(h); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLinkURL(t *testing.T) {
+	base := "https://api.github.com"
+	if _, err := LinkURL("https://api.github.com/user/repos?page=2", base); err != nil {
+		t.Errorf("same host rejected: %v", err)
+	}
+	for _, cursor := range []string{"https://evil.example/user/repos", "http://api.github.com/user/repos"} {
+		if _, err := LinkURL(cursor, base); err == nil {
+			t.Errorf("%s accepted", cursor)
+		}
+	}
+}
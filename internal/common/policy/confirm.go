@@ -0,0 +1,233 @@
+package policy
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// ConfirmEnv supplies a confirmation token, like --confirm
+const ConfirmEnv = "POCKET_CONFIRM"
+
+// DefaultTokenTTL is how long an approval token stays valid
+const DefaultTokenTTL = 15 * time.Minute
+
+// Prompt streams; replaced in tests
+var (
+	stdin  io.Reader = os.Stdin
+	stderr io.Writer = os.Stderr
+	isTerm           = func() bool { return isTerminal(os.Stdin) && isTerminal(os.Stderr) }
+)
+
+type noPromptsKey struct{}
+
+// WithoutPrompts marks ctx as a run no one can answer prompts for, such as
+// a request to pocket batch or serve, even when the process has a terminal
+func WithoutPrompts(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noPromptsKey{}, true)
+}
+
+func isTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// Enforce applies the policy before cmd runs. It returns a printed error
+// when the command is denied or needs a confirmation that wasn't given.
+func Enforce(cmd *cobra.Command, args []string, token string) error {
+	ctx := cmd.Context()
+	if Exempt(cmd) {
+		return nil
+	}
+	p, err := Load()
+	if err != nil {
+		return output.PrintErr(ctx, "policy_invalid", err, map[string]any{
+			"path": Path(),
+			"hint": "Fix the file or run: pocket policy show",
+		})
+	}
+
+	d := p.Evaluate(cmd, args)
+	switch d.Action {
+	case ActionAllow:
+		return nil
+	case ActionDeny:
+		msg := fmt.Sprintf("%s is denied by policy", d.Command)
+		if d.Reason != "" {
+			msg += ": " + d.Reason
+		}
+		return output.PrintError(ctx, "policy_denied", msg, d)
+	}
+
+	// A dry run only shows what would be sent, which is what a person
+	// needs to see before approving
+	if dryrun.Enabled(ctx) {
+		return nil
+	}
+	if token == "" {
+		token = os.Getenv(ConfirmEnv)
+	}
+	if token != "" {
+		if err := checkToken(token, Fingerprint(cmd, args), time.Now()); err != nil {
+			return output.PrintErr(ctx, "confirmation_invalid", err, d)
+		}
+		return nil
+	}
+
+	if Interactive(ctx) && prompt(d, cmd, args) {
+		return nil
+	}
+	return output.PrintError(ctx, "confirmation_required", fmt.Sprintf("%s (%s) needs confirmation", d.Command, d.Class), map[string]any{
+		"command": d.Command,
+		"class":   d.Class,
+		"rule":    d.Rule,
+		"reason":  d.Reason,
+		"hint":    "Ask the user to run: pocket policy approve -- " + Invocation(cmd, args) + ", then retry with --confirm <token>",
+	})
+}
+
+func prompt(d Decision, cmd *cobra.Command, args []string) bool {
+	q := fmt.Sprintf("pocket %s is a %s command", Invocation(cmd, args), d.Class)
+	if d.Reason != "" {
+		q += fmt.Sprintf(" (%s)", d.Reason)
+	}
+	return Ask(q + ". Run it?")
+}
+
+// Interactive reports whether a person can answer prompts for the run ctx
+// belongs to
+func Interactive(ctx context.Context) bool {
+	return ctx.Value(noPromptsKey{}) == nil && isTerm()
+}
+
+// Ask prints a yes/no question on stderr and reads the answer from stdin
+func Ask(question string) bool {
+	fmt.Fprint(stderr, question+" [y/N] ")
+	line, _ := bufio.NewReader(stdin).ReadString('\n')
+	answer := strings.ToLower(strings.TrimSpace(line))
+	return answer == "y" || answer == "yes"
+}
+
+// Fingerprint identifies an invocation: the command path, positional
+// arguments and the command's own flags that were set. Global flags such
+// as --output don't change it.
+func Fingerprint(cmd *cobra.Command, args []string) string {
+	flags := setFlags(cmd)
+	sort.Strings(flags)
+
+	parts := append([]string{CommandPath(cmd)}, args...)
+	parts = append(parts, flags...)
+	return strings.Join(parts, "\x00")
+}
+
+// Invocation rebuilds the command line for hints and prompts
+func Invocation(cmd *cobra.Command, args []string) string {
+	return shellJoin(invocation(cmd, args))
+}
+
+func invocation(cmd *cobra.Command, args []string) []string {
+	words := append(strings.Fields(CommandPath(cmd)), args...)
+	return append(words, setFlags(cmd)...)
+}
+
+// setFlags lists the command's own flags that were set as --name=value.
+// LocalFlags is rebuilt on each call and doesn't record which flags were
+// parsed, so check Changed rather than using Visit.
+func setFlags(cmd *cobra.Command) []string {
+	var flags []string
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			flags = append(flags, "--"+f.Name+"="+f.Value.String())
+		}
+	})
+	return flags
+}
+
+func shellJoin(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		if w == "" || strings.ContainsAny(w, " \t\n'\"\\$`#&|;<>()*?[]{}~!") {
+			w = "'" + strings.ReplaceAll(w, "'", `'\''`) + "'"
+		}
+		quoted[i] = w
+	}
+	return strings.Join(quoted, " ")
+}
+
+// Approve returns a token confirming one invocation until ttl passes
+func Approve(fingerprint string, ttl time.Duration) (string, error) {
+	key, err := signingKey(true)
+	if err != nil {
+		return "", err
+	}
+	expiry := time.Now().Add(ttl).Unix()
+	return strconv.FormatInt(expiry, 36) + "-" + sign(key, fingerprint, expiry), nil
+}
+
+func checkToken(token, fingerprint string, now time.Time) error {
+	exp, sig, ok := strings.Cut(token, "-")
+	expiry, err := strconv.ParseInt(exp, 36, 64)
+	if !ok || err != nil {
+		return errors.New("malformed confirmation token")
+	}
+	key, err := signingKey(false)
+	if err != nil {
+		return errors.New("no approvals have been issued on this machine")
+	}
+	if !hmac.Equal([]byte(sig), []byte(sign(key, fingerprint, expiry))) {
+		return errors.New("confirmation token does not match this command and arguments")
+	}
+	if now.Unix() > expiry {
+		return errors.New("confirmation token expired")
+	}
+	return nil
+}
+
+func sign(key []byte, fingerprint string, expiry int64) string {
+	mac := hmac.New(sha256.New, key)
+	fmt.Fprintf(mac, "%s\x00%d", fingerprint, expiry)
+	return hex.EncodeToString(mac.Sum(nil))[:20]
+}
+
+// signingKey reads policy.key next to the policy file, creating it when
+// asked
+func signingKey(create bool) ([]byte, error) {
+	keyPath := filepath.Join(filepath.Dir(Path()), "policy.key")
+	data, err := os.ReadFile(keyPath)
+	if err == nil {
+		return hex.DecodeString(strings.TrimSpace(string(data)))
+	}
+	if !create || !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(keyPath), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(keyPath, []byte(hex.EncodeToString(key)+"\n"), 0o600); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
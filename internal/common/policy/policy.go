@@ -0,0 +1,359 @@
+package policy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+)
+
+// Command classes
+const (
+	ClassRead        = "read"
+	ClassWrite       = "write"
+	ClassDestructive = "destructive"
+)
+
+// Actions a policy can take
+const (
+	ActionAllow   = "allow"
+	ActionDeny    = "deny"
+	ActionConfirm = "confirm"
+)
+
+// PathEnv overrides the policy file location
+const PathEnv = "POCKET_POLICY"
+
+// Policy is the parsed policy.yaml
+type Policy struct {
+	// Defaults is the action per class when no rule matches; unset classes
+	// are allowed
+	Defaults map[string]string `yaml:"defaults" json:"defaults,omitempty"`
+	// Classes reclassifies commands, keyed by command pattern
+	Classes map[string]string `yaml:"classes" json:"classes,omitempty"`
+	// Rules are checked in order; the first match decides
+	Rules []Rule `yaml:"rules" json:"rules,omitempty"`
+}
+
+// Rule matches commands by path pattern, class and arguments
+type Rule struct {
+	Command string              `yaml:"command" json:"command,omitempty"`
+	Class   string              `yaml:"class" json:"class,omitempty"`
+	Args    map[string]Patterns `yaml:"args" json:"args,omitempty"`
+	Action  string              `yaml:"action" json:"action"`
+	Reason  string              `yaml:"reason" json:"reason,omitempty"`
+}
+
+// Patterns accepts a single glob or a list of globs
+type Patterns []string
+
+func (p *Patterns) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*p = Patterns{node.Value}
+		return nil
+	}
+	var list []string
+	if err := node.Decode(&list); err != nil {
+		return err
+	}
+	*p = list
+	return nil
+}
+
+// Decision is the outcome of evaluating the policy for one invocation
+type Decision struct {
+	Command string `json:"command"`
+	Class   string `json:"class"`
+	Action  string `json:"action"`
+	Rule    int    `json:"rule,omitempty"` // 1-based; 0 means the class default
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Path returns the policy file location: $POCKET_POLICY, else policy.yaml
+// next to the config file
+func Path() string {
+	if p := os.Getenv(PathEnv); p != "" {
+		return p
+	}
+	return filepath.Join(filepath.Dir(config.Path()), "policy.yaml")
+}
+
+// Load reads the policy file. A missing file yields an empty policy that
+// allows everything.
+func Load() (*Policy, error) {
+	data, err := os.ReadFile(Path())
+	if errors.Is(err, os.ErrNotExist) {
+		return &Policy{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var p Policy
+	if err := yaml.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", Path(), err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid policy %s: %w", Path(), err)
+	}
+	return &p, nil
+}
+
+func (p *Policy) validate() error {
+	for class, action := range p.Defaults {
+		if !validClass(class) {
+			return fmt.Errorf("defaults: unknown class %q", class)
+		}
+		if !validAction(action) {
+			return fmt.Errorf("defaults.%s: unknown action %q", class, action)
+		}
+	}
+	for pattern, class := range p.Classes {
+		if !validClass(class) {
+			return fmt.Errorf("classes.%s: unknown class %q", pattern, class)
+		}
+	}
+	for i, r := range p.Rules {
+		if !validAction(r.Action) {
+			return fmt.Errorf("rule %d: unknown action %q (allow, deny or confirm)", i+1, r.Action)
+		}
+		if r.Class != "" && !validClass(r.Class) {
+			return fmt.Errorf("rule %d: unknown class %q", i+1, r.Class)
+		}
+	}
+	return nil
+}
+
+func validClass(c string) bool {
+	return c == ClassRead || c == ClassWrite || c == ClassDestructive
+}
+
+func validAction(a string) bool {
+	return a == ActionAllow || a == ActionDeny || a == ActionConfirm
+}
+
+// exempt commands inspect pocket itself and are never gated, so a bad
+// policy can always be examined and fixed
+var exempt = []string{"help", "completion", "commands", "policy", "__complete", "__completeNoDesc"}
+
+// CommandPath is cmd's path without the root name, e.g. "comms slack send"
+func CommandPath(cmd *cobra.Command) string {
+	p := cmd.CommandPath()
+	if root := cmd.Root().Name(); strings.HasPrefix(p, root) {
+		p = strings.TrimSpace(strings.TrimPrefix(p, root))
+	}
+	return p
+}
+
+// Exempt reports whether the command is never gated
+func Exempt(cmd *cobra.Command) bool {
+	first, _, _ := strings.Cut(CommandPath(cmd), " ")
+	for _, e := range exempt {
+		if first == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Evaluate decides what to do with cmd invoked with args
+func (p *Policy) Evaluate(cmd *cobra.Command, args []string) Decision {
+	cmdPath := CommandPath(cmd)
+	d := Decision{Command: cmdPath, Class: p.classify(cmdPath)}
+
+	for i, r := range p.Rules {
+		if r.Command != "" && !matchCommand(r.Command, cmdPath) {
+			continue
+		}
+		if r.Class != "" && r.Class != d.Class {
+			continue
+		}
+		if !matchArgs(r.Args, cmd, args) {
+			continue
+		}
+		d.Action, d.Rule, d.Reason = r.Action, i+1, r.Reason
+		return d
+	}
+
+	d.Action = p.Defaults[d.Class]
+	if d.Action == "" {
+		d.Action = ActionAllow
+	}
+	return d
+}
+
+// ClassOf returns cmd's class under the active policy, falling back to the
+// built-in classes when the policy can't be loaded
+func ClassOf(cmd *cobra.Command) string {
+	p, err := Load()
+	if err != nil {
+		return Classify(CommandPath(cmd))
+	}
+	return p.classify(CommandPath(cmd))
+}
+
+func (p *Policy) classify(cmdPath string) string {
+	if class, ok := p.Classes[cmdPath]; ok {
+		return class
+	}
+	// Longer patterns are more specific and win
+	patterns := make([]string, 0, len(p.Classes))
+	for pattern := range p.Classes {
+		patterns = append(patterns, pattern)
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		wi, wj := len(strings.Fields(patterns[i])), len(strings.Fields(patterns[j]))
+		if wi != wj {
+			return wi > wj
+		}
+		return patterns[i] < patterns[j]
+	})
+	for _, pattern := range patterns {
+		if matchCommand(pattern, cmdPath) {
+			return p.Classes[pattern]
+		}
+	}
+	return Classify(cmdPath)
+}
+
+// builtinClasses fixes commands the verb heuristic gets wrong
+var builtinClasses = map[string]string{
+	"comms webhook":           ClassWrite,
+	"comms notify":            ClassWrite,
+	"dev github graphql":      ClassWrite,
+	"social twitter auth":     ClassWrite,
+	"social reddit auth":      ClassWrite,
+	"utility geocode forward": ClassRead,
+	"utility speedtest":       ClassRead,
+}
+
+// verbs maps command name words to the class they imply
+var verbs = map[string]string{
+	"delete": ClassDestructive, "del": ClassDestructive, "remove": ClassDestructive,
+	"trash": ClassDestructive, "purge": ClassDestructive, "clear": ClassDestructive,
+	"cancel": ClassDestructive, "drop": ClassDestructive,
+
+	"add": ClassWrite, "append": ClassWrite, "create": ClassWrite, "update": ClassWrite,
+	"set": ClassWrite, "put": ClassWrite, "write": ClassWrite, "upload": ClassWrite,
+	"send": ClassWrite, "post": ClassWrite, "reply": ClassWrite, "forward": ClassWrite,
+	"dm": ClassWrite, "comment": ClassWrite, "complete": ClassWrite, "close": ClassWrite,
+	"transition": ClassWrite, "tag": ClassWrite, "untag": ClassWrite, "copy": ClassWrite,
+	"use": ClassWrite, "download": ClassWrite, "shorten": ClassWrite, "open": ClassWrite,
+	"reveal": ClassWrite, "auth": ClassWrite, "merge": ClassWrite, "label": ClassWrite,
+	"assign": ClassWrite, "approve": ClassWrite, "review": ClassWrite, "move": ClassWrite,
+	"rerun": ClassWrite, "mark-read": ClassWrite,
+}
+
+// Classify returns the built-in class of a command path: overrides first,
+// then the most severe verb in the command's name, else read
+func Classify(cmdPath string) string {
+	words := strings.Fields(cmdPath)
+	for i := len(words); i > 0; i-- {
+		if class, ok := builtinClasses[strings.Join(words[:i], " ")]; ok {
+			return class
+		}
+	}
+	if len(words) == 0 {
+		return ClassRead
+	}
+
+	name := words[len(words)-1]
+	if class, ok := verbs[name]; ok {
+		return class
+	}
+	class := ClassRead
+	for _, part := range strings.Split(name, "-") {
+		switch verbs[part] {
+		case ClassDestructive:
+			return ClassDestructive
+		case ClassWrite:
+			class = ClassWrite
+		}
+	}
+	return class
+}
+
+// matchCommand matches a space-separated pattern against a command path.
+// "*" matches one word, a trailing "**" any number of words, and other
+// words are globs. A leading "pocket" is ignored.
+func matchCommand(pattern, cmdPath string) bool {
+	pw := strings.Fields(pattern)
+	if len(pw) > 0 && pw[0] == "pocket" {
+		pw = pw[1:]
+	}
+	cw := strings.Fields(cmdPath)
+
+	for i, p := range pw {
+		if p == "**" && i == len(pw)-1 {
+			return true
+		}
+		if i >= len(cw) {
+			return false
+		}
+		if ok, _ := path.Match(p, cw[i]); !ok {
+			return false
+		}
+	}
+	return len(pw) == len(cw)
+}
+
+// matchArgs checks each matcher against a positional argument ("0", "1",
+// ...) or a flag, by name. Every value of a list flag must match; an empty
+// list is matched as "".
+func matchArgs(matchers map[string]Patterns, cmd *cobra.Command, args []string) bool {
+	for name, patterns := range matchers {
+		values, ok := argValues(name, cmd, args)
+		if !ok {
+			return false
+		}
+		for _, v := range values {
+			if !matchAny(patterns, v) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func argValues(name string, cmd *cobra.Command, args []string) ([]string, bool) {
+	if idx, err := strconv.Atoi(name); err == nil {
+		if idx < 0 || idx >= len(args) {
+			return nil, false
+		}
+		return []string{args[idx]}, true
+	}
+
+	f := cmd.Flags().Lookup(strings.TrimLeft(name, "-"))
+	if f == nil {
+		return nil, false
+	}
+	if sv, ok := f.Value.(pflag.SliceValue); ok {
+		if values := sv.GetSlice(); len(values) > 0 {
+			return values, true
+		}
+		return []string{""}, true
+	}
+	return []string{f.Value.String()}, true
+}
+
+func matchAny(patterns []string, v string) bool {
+	for _, p := range patterns {
+		if p == v {
+			return true
+		}
+		if ok, _ := path.Match(p, v); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,315 @@
+package policy
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// testTree builds a small command tree shaped like pocket's
+func testTree() *cobra.Command {
+	root := &cobra.Command{Use: "pocket"}
+	comms := &cobra.Command{Use: "comms"}
+	slack := &cobra.Command{Use: "slack"}
+	send := &cobra.Command{Use: "send", RunE: func(*cobra.Command, []string) error { return nil }}
+	send.Flags().StringP("channel", "c", "", "")
+	send.Flags().StringSlice("attach", nil, "")
+	history := &cobra.Command{Use: "history", RunE: func(*cobra.Command, []string) error { return nil }}
+	slack.AddCommand(send, history)
+	comms.AddCommand(slack)
+
+	gmail := &cobra.Command{Use: "gmail"}
+	gmail.AddCommand(&cobra.Command{Use: "trash", RunE: func(*cobra.Command, []string) error { return nil }})
+	comms.AddCommand(gmail)
+	root.AddCommand(comms)
+	return root
+}
+
+// find resolves a command line and parses its flags
+func find(t *testing.T, line string) (*cobra.Command, []string) {
+	t.Helper()
+	cmd, rest, err := testTree().Find(strings.Fields(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.ParseFlags(rest); err != nil {
+		t.Fatal(err)
+	}
+	// As when the command is executed
+	cmd.SetContext(context.Background())
+	return cmd, cmd.Flags().Args()
+}
+
+func usePolicy(t *testing.T, yaml string) {
+	t.Helper()
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "policy.yaml")
+	if yaml != "" {
+		if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(PathEnv, path)
+	t.Setenv(ConfirmEnv, "")
+
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	t.Cleanup(func() { output.SetOutput(ctx, nil) })
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"comms slack send", ClassWrite},
+		{"comms slack history", ClassRead},
+		{"comms gmail trash", ClassDestructive},
+		{"productivity todoist complete", ClassWrite},
+		{"dev github issue-create", ClassWrite},
+		{"dev cloudflare purge-cache", ClassDestructive},
+		{"comms webhook", ClassWrite},
+		{"comms notify pushover", ClassWrite},
+		{"dev db query", ClassRead},
+		{"utility geocode forward", ClassRead},
+		{"utility speedtest upload", ClassRead},
+		{"news hackernews top", ClassRead},
+		{"", ClassRead},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.path); got != tt.want {
+			t.Errorf("Classify(%q) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestMatchCommand(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"comms slack send", "comms slack send", true},
+		{"pocket comms slack send", "comms slack send", true},
+		{"comms * send", "comms slack send", true},
+		{"comms **", "comms slack send", true},
+		{"comms **", "comms", true},
+		{"comms s*", "comms slack", true},
+		{"comms slack", "comms slack send", false},
+		{"comms slack send", "comms slack", false},
+		{"dev **", "comms slack send", false},
+	}
+	for _, tt := range tests {
+		if got := matchCommand(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchCommand(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	p := &Policy{
+		Defaults: map[string]string{ClassWrite: ActionConfirm, ClassDestructive: ActionDeny},
+		Classes:  map[string]string{"comms slack *": ClassRead},
+		Rules: []Rule{
+			{Command: "comms slack send", Args: map[string]Patterns{"channel": {"#bots", "bots"}}, Action: ActionAllow},
+			{Command: "comms slack send", Args: map[string]Patterns{"attach": {"*.txt"}}, Action: ActionAllow},
+			{Command: "comms slack send", Action: ActionDeny, Reason: "bots only"},
+			{Command: "comms gmail *", Args: map[string]Patterns{"0": {"draft-*"}}, Action: ActionAllow},
+		},
+	}
+
+	tests := []struct {
+		line   string
+		action string
+		class  string
+		rule   int
+	}{
+		{"comms slack send -c bots hi", ActionAllow, ClassRead, 1},
+		{"comms slack send -c general hi", ActionDeny, ClassRead, 3},
+		{"comms slack send --attach a.txt,b.txt hi", ActionAllow, ClassRead, 2},
+		{"comms slack send --attach a.txt,b.png hi", ActionDeny, ClassRead, 3},
+		{"comms slack history", ActionAllow, ClassRead, 0},
+		{"comms gmail trash draft-1", ActionAllow, ClassDestructive, 4},
+		{"comms gmail trash msg-1", ActionDeny, ClassDestructive, 0},
+		{"comms gmail trash", ActionDeny, ClassDestructive, 0},
+	}
+	for _, tt := range tests {
+		cmd, args := find(t, tt.line)
+		d := p.Evaluate(cmd, args)
+		if d.Action != tt.action || d.Class != tt.class || d.Rule != tt.rule {
+			t.Errorf("%s: got %s/%s rule %d, want %s/%s rule %d", tt.line, d.Action, d.Class, d.Rule, tt.action, tt.class, tt.rule)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	usePolicy(t, "")
+	p, err := Load()
+	if err != nil {
+		t.Fatalf("missing file should load: %v", err)
+	}
+	cmd, args := find(t, "comms gmail trash x")
+	if d := p.Evaluate(cmd, args); d.Action != ActionAllow {
+		t.Errorf("empty policy should allow, got %s", d.Action)
+	}
+
+	usePolicy(t, `
+defaults: {destructive: deny}
+rules:
+  - command: comms slack send
+    args: {channel: bots}
+    action: allow
+`)
+	p, err = Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Rules) != 1 || len(p.Rules[0].Args["channel"]) != 1 {
+		t.Errorf("scalar pattern not parsed: %+v", p.Rules)
+	}
+
+	for _, bad := range []string{
+		"defaults: {write: maybe}",
+		"defaults: {risky: deny}",
+		"classes: {'comms *': dangerous}",
+		"rules: [{command: x, action: block}]",
+		"rules: [{command: x, class: risky, action: deny}]",
+		"rules: [",
+	} {
+		usePolicy(t, bad)
+		if _, err := Load(); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestEnforce(t *testing.T) {
+	usePolicy(t, `
+defaults: {write: confirm, destructive: deny}
+rules:
+  - {command: comms slack send, args: {channel: bots}, action: allow}
+`)
+	old := isTerm
+	isTerm = func() bool { return false }
+	t.Cleanup(func() { isTerm = old })
+
+	code := func(err error) string {
+		if err == nil {
+			return ""
+		}
+		c, _, _ := strings.Cut(err.Error(), ":")
+		return c
+	}
+
+	cmd, args := find(t, "comms slack history")
+	if err := Enforce(cmd, args, ""); err != nil {
+		t.Errorf("read: %v", err)
+	}
+	cmd, args = find(t, "comms slack send -c bots hi")
+	if err := Enforce(cmd, args, ""); err != nil {
+		t.Errorf("allowed rule: %v", err)
+	}
+	cmd, args = find(t, "comms gmail trash 1")
+	if got := code(Enforce(cmd, args, "")); got != "policy_denied" {
+		t.Errorf("destructive: got %q", got)
+	}
+
+	cmd, args = find(t, "comms slack send -c general hi")
+	if got := code(Enforce(cmd, args, "")); got != "confirmation_required" {
+		t.Errorf("no token: got %q", got)
+	}
+
+	token, err := Approve(Fingerprint(cmd, args), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Enforce(cmd, args, token); err != nil {
+		t.Errorf("approved: %v", err)
+	}
+	t.Setenv(ConfirmEnv, token)
+	if err := Enforce(cmd, args, ""); err != nil {
+		t.Errorf("approved via env: %v", err)
+	}
+	t.Setenv(ConfirmEnv, "")
+
+	other, otherArgs := find(t, "comms slack send -c general bye")
+	if got := code(Enforce(other, otherArgs, token)); got != "confirmation_invalid" {
+		t.Errorf("token for other args: got %q", got)
+	}
+	other, otherArgs = find(t, "comms slack send -c random hi")
+	if got := code(Enforce(other, otherArgs, token)); got != "confirmation_invalid" {
+		t.Errorf("token for other flags: got %q", got)
+	}
+	if got := code(Enforce(cmd, args, "garbage")); got != "confirmation_invalid" {
+		t.Errorf("malformed token: got %q", got)
+	}
+
+	// Interactive prompt
+	isTerm = func() bool { return true }
+	var prompted bytes.Buffer
+	stdin, stderr = strings.NewReader("y\n"), &prompted
+	t.Cleanup(func() { stdin, stderr = os.Stdin, os.Stderr })
+	if err := Enforce(cmd, args, ""); err != nil {
+		t.Errorf("prompt yes: %v", err)
+	}
+	if !strings.Contains(prompted.String(), "pocket comms slack send hi --channel=general") {
+		t.Errorf("prompt = %q", prompted.String())
+	}
+	stdin = strings.NewReader("n\n")
+	if got := code(Enforce(cmd, args, "")); got != "confirmation_required" {
+		t.Errorf("prompt no: got %q", got)
+	}
+
+	// A batch or serve request never prompts, even from a terminal
+	prompted.Reset()
+	stdin = strings.NewReader("y\n")
+	cmd.SetContext(WithoutPrompts(cmd.Context()))
+	if got := code(Enforce(cmd, args, "")); got != "confirmation_required" || prompted.Len() != 0 {
+		t.Errorf("without prompts: got %q after %q", got, prompted.String())
+	}
+}
+
+func TestCheckTokenExpiry(t *testing.T) {
+	usePolicy(t, "")
+	token, err := Approve("comms slack send\x00hi", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkToken(token, "comms slack send\x00hi", time.Now()); err != nil {
+		t.Errorf("fresh token: %v", err)
+	}
+	if err := checkToken(token, "comms slack send\x00hi", time.Now().Add(2*time.Minute)); err == nil || !strings.Contains(err.Error(), "expired") {
+		t.Errorf("expired token: %v", err)
+	}
+
+	info, err := os.Stat(filepath.Join(filepath.Dir(Path()), "policy.key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("key mode = %v", info.Mode().Perm())
+	}
+}
+
+func TestExempt(t *testing.T) {
+	root := testTree()
+	pol := &cobra.Command{Use: "policy"}
+	show := &cobra.Command{Use: "show"}
+	pol.AddCommand(show)
+	root.AddCommand(pol)
+	if !Exempt(show) {
+		t.Error("policy show should be exempt")
+	}
+	cmd, _ := find(t, "comms slack send")
+	if Exempt(cmd) {
+		t.Error("comms slack send should not be exempt")
+	}
+}
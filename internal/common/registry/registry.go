@@ -0,0 +1,187 @@
+// Package registry holds every built-in integration. Each integration
+// package registers itself from init, and the command groups, the command
+// catalog, the integrations list and setup are all derived from it.
+package registry
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+)
+
+// Auth is what an integration needs before it can be used
+type Auth string
+
+const (
+	// AuthNone is for public APIs and local tools
+	AuthNone Auth = "none"
+	// AuthKey is for API keys, tokens or connection settings in the config
+	AuthKey Auth = "key"
+	// AuthOAuth is for an OAuth client that is authorised once
+	AuthOAuth Auth = "oauth"
+)
+
+// Statuses reported for an integration
+const (
+	StatusReady      = "ready"
+	StatusNeedsSetup = "needs_setup"
+	StatusNoAuth     = "no_auth"
+)
+
+// Group is a top-level command group integrations are mounted under
+type Group struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Desc string `json:"desc"`
+}
+
+// Groups lists the integration groups in display order
+var Groups = []Group{
+	{ID: "social", Name: "Social", Desc: "Social media platforms"},
+	{ID: "comms", Name: "Comms", Desc: "Email and messaging"},
+	{ID: "dev", Name: "Dev", Desc: "Developer tools and package registries"},
+	{ID: "productivity", Name: "Productivity", Desc: "Calendar, tasks, notes"},
+	{ID: "news", Name: "News", Desc: "News feeds and articles"},
+	{ID: "knowledge", Name: "Knowledge", Desc: "Research and reference"},
+	{ID: "utility", Name: "Utility", Desc: "Weather, tools"},
+	{ID: "system", Name: "System", Desc: "macOS system integrations"},
+	{ID: "security", Name: "Security", Desc: "Security scanning and threat intelligence"},
+	{ID: "marketing", Name: "Marketing", Desc: "Ad platforms and marketing tools"},
+}
+
+// Key is a config key an integration reads
+type Key struct {
+	Key         string
+	Description string
+	Required    bool
+	Example     string
+}
+
+// Integration describes a built-in integration
+type Integration struct {
+	// ID names the integration in integrations and setup
+	ID          string
+	Name        string
+	Group       string
+	Description string
+	Auth        Auth
+	Keys        []Key
+	SetupGuide  string
+	TestCommand string
+	// New builds the integration's command, mounted under Group
+	New func() *cobra.Command
+}
+
+var (
+	mu           sync.Mutex
+	integrations []Integration
+)
+
+// Register adds an integration. It is called from init and panics on
+// incomplete or duplicate entries, which are programming errors.
+func Register(i Integration) {
+	if err := i.validate(); err != nil {
+		panic("registry: " + err.Error())
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, existing := range integrations {
+		if existing.ID == i.ID {
+			panic("registry: integration " + i.ID + " registered twice")
+		}
+	}
+	integrations = append(integrations, i)
+}
+
+func (i *Integration) validate() error {
+	switch {
+	case i.ID == "":
+		return fmt.Errorf("integration without an ID")
+	case i.Name == "" || i.Description == "":
+		return fmt.Errorf("integration %s needs a name and description", i.ID)
+	case !slices.ContainsFunc(Groups, func(g Group) bool { return g.ID == i.Group }):
+		return fmt.Errorf("integration %s has unknown group %q", i.ID, i.Group)
+	case i.New == nil:
+		return fmt.Errorf("integration %s has no command", i.ID)
+	}
+
+	if i.Auth == "" {
+		i.Auth = AuthNone
+	}
+	required := slices.ContainsFunc(i.Keys, func(k Key) bool { return k.Required })
+	if i.Auth != AuthNone && !required {
+		return fmt.Errorf("integration %s needs auth but has no required keys", i.ID)
+	}
+	if len(i.Keys) > 0 && i.SetupGuide == "" {
+		return fmt.Errorf("integration %s has config keys but no setup guide", i.ID)
+	}
+	return nil
+}
+
+// All returns every registered integration, by group in display order,
+// then by ID
+func All() []Integration {
+	mu.Lock()
+	all := slices.Clone(integrations)
+	mu.Unlock()
+
+	order := func(group string) int {
+		return slices.IndexFunc(Groups, func(g Group) bool { return g.ID == group })
+	}
+	slices.SortFunc(all, func(a, b Integration) int {
+		if d := order(a.Group) - order(b.Group); d != 0 {
+			return d
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return all
+}
+
+// InGroup returns the integrations mounted under group, by ID
+func InGroup(group string) []Integration {
+	var result []Integration
+	for _, i := range All() {
+		if i.Group == group {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// Lookup returns the integration with id
+func Lookup(id string) (Integration, bool) {
+	for _, i := range All() {
+		if i.ID == id {
+			return i, true
+		}
+	}
+	return Integration{}, false
+}
+
+// Mount adds every integration in the group named by cmd to it
+func Mount(cmd *cobra.Command) {
+	for _, i := range InGroup(cmd.Name()) {
+		cmd.AddCommand(i.New())
+	}
+}
+
+// Status is no_auth for integrations without auth, ready once every
+// required key is set and needs_setup otherwise
+func (i Integration) Status(ctx context.Context) string {
+	if i.Auth == AuthNone {
+		return StatusNoAuth
+	}
+	for _, k := range i.Keys {
+		if v, _ := config.Get(ctx, k.Key); k.Required && v == "" {
+			return StatusNeedsSetup
+		}
+	}
+	return StatusReady
+}
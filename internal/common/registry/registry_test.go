@@ -0,0 +1,40 @@
+package registry
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestValidate(t *testing.T) {
+	newCmd := func() *cobra.Command { return &cobra.Command{Use: "x"} }
+	base := func() Integration {
+		return Integration{ID: "x", Name: "X", Group: "dev", Description: "Does x", New: newCmd}
+	}
+	token := []Key{{Key: "x_token", Description: "Token", Required: true}}
+
+	tests := []struct {
+		name string
+		edit func(*Integration)
+		ok   bool
+	}{
+		{"minimal", func(i *Integration) {}, true},
+		{"no id", func(i *Integration) { i.ID = "" }, false},
+		{"no description", func(i *Integration) { i.Description = "" }, false},
+		{"unknown group", func(i *Integration) { i.Group = "plugins" }, false},
+		{"no command", func(i *Integration) { i.New = nil }, false},
+		{"auth without keys", func(i *Integration) { i.Auth = AuthKey }, false},
+		{"keys without guide", func(i *Integration) { i.Auth, i.Keys = AuthKey, token }, false},
+		{"key auth", func(i *Integration) { i.Auth, i.Keys, i.SetupGuide = AuthKey, token, "Get a token" }, true},
+	}
+	for _, tt := range tests {
+		i := base()
+		tt.edit(&i)
+		if err := i.validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: validate() = %v", tt.name, err)
+		}
+		if tt.name == "minimal" && i.Auth != AuthNone {
+			t.Errorf("minimal: Auth = %q, want %q", i.Auth, AuthNone)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package schema
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Draft is the JSON Schema dialect emitted by this package
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// ArgsProperty is the input property that carries positional arguments
+const ArgsProperty = "args"
+
+// maxArgsProbe is how many positional args are probed when inferring arity
+const maxArgsProbe = 8
+
+// Schema is the JSON Schema subset used to describe command inputs and outputs
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Default              any                `json:"default,omitempty"`
+}
+
+// Input describes a command's positional args and local flags as an object schema
+func Input(cmd *cobra.Command) *Schema {
+	s := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{},
+	}
+
+	if args := Args(cmd); args != nil {
+		s.Properties[ArgsProperty] = args
+		if args.MinItems != nil && *args.MinItems > 0 {
+			s.Required = append(s.Required, ArgsProperty)
+		}
+	}
+
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Name == "help" {
+			return
+		}
+		s.Properties[f.Name] = Flag(f)
+	})
+
+	return s
+}
+
+// Args describes a command's positional arguments, or nil if it takes none.
+// Arity is inferred by probing the command's cobra.PositionalArgs validator.
+func Args(cmd *cobra.Command) *Schema {
+	usage := strings.TrimSpace(strings.TrimPrefix(cmd.Use, cmd.Name()))
+	if usage == "" {
+		return nil
+	}
+
+	s := &Schema{
+		Type:        "array",
+		Description: "Positional arguments: " + usage,
+		Items:       &Schema{Type: "string"},
+	}
+
+	if cmd.Args == nil {
+		return s
+	}
+
+	minArgs, maxArgs := -1, -1
+	for n := 0; n <= maxArgsProbe; n++ {
+		if cmd.Args(cmd, make([]string, n)) == nil {
+			if minArgs < 0 {
+				minArgs = n
+			}
+			maxArgs = n
+		}
+	}
+	if minArgs > 0 {
+		s.MinItems = &minArgs
+	}
+	if maxArgs >= 0 && maxArgs < maxArgsProbe {
+		s.MaxItems = &maxArgs
+	}
+
+	return s
+}
+
+// Flag describes a single flag using its pflag value type and default
+func Flag(f *pflag.Flag) *Schema {
+	s := &Schema{Description: f.Usage}
+
+	switch f.Value.Type() {
+	case "bool":
+		s.Type = "boolean"
+		if f.DefValue == "true" {
+			s.Default = true
+		}
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "count":
+		s.Type = "integer"
+		if n, err := strconv.Atoi(f.DefValue); err == nil && n != 0 {
+			s.Default = n
+		}
+	case "float32", "float64":
+		s.Type = "number"
+		if n, err := strconv.ParseFloat(f.DefValue, 64); err == nil && n != 0 {
+			s.Default = n
+		}
+	case "stringSlice", "stringArray", "intSlice", "int64Slice", "uintSlice", "float64Slice", "boolSlice":
+		s.Type = "array"
+		s.Items = &Schema{Type: "string"}
+	case "duration":
+		s.Type = "string"
+		s.Format = "duration"
+		if f.DefValue != "0s" {
+			s.Default = f.DefValue
+		}
+	default:
+		s.Type = "string"
+		if f.DefValue != "" && f.DefValue != "[]" {
+			s.Default = f.DefValue
+		}
+	}
+
+	return s
+}
+
+// FromValue describes the JSON encoding of v's type
+func FromValue(v any) *Schema {
+	if v == nil {
+		return &Schema{}
+	}
+	return FromType(reflect.TypeOf(v))
+}
+
+// FromType describes the JSON encoding of t, following encoding/json rules
+func FromType(t reflect.Type) *Schema {
+	return fromType(t, map[reflect.Type]bool{})
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func fromType(t reflect.Type, seen map[reflect.Type]bool) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: fromType(t.Elem(), seen)}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: fromType(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return &Schema{Type: "object"}
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+		addFields(s, t, seen)
+		return s
+	default:
+		// interface{} and anything else can hold any JSON value
+		return &Schema{}
+	}
+}
+
+func addFields(s *Schema, t reflect.Type, seen map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				addFields(s, ft, seen)
+				continue
+			}
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = fromType(f.Type, seen)
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type inner struct {
+	Name string `json:"name"`
+}
+
+type sample struct {
+	ID       int               `json:"id"`
+	Title    string            `json:"title,omitempty"`
+	Tags     []string          `json:"tags"`
+	Meta     map[string]any    `json:"meta,omitempty"`
+	Created  time.Time         `json:"created"`
+	Owner    *inner            `json:"owner,omitempty"`
+	Children []sample          `json:"children,omitempty"`
+	Counts   map[string]int    `json:"counts"`
+	Skipped  string            `json:"-"`
+	Raw      []byte            `json:"raw,omitempty"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	inner
+}
+
+func TestFromTypeStruct(t *testing.T) {
+	s := FromValue(sample{})
+
+	if s.Type != "object" {
+		t.Fatalf("expected object, got %q", s.Type)
+	}
+
+	expected := map[string]string{
+		"id":       "integer",
+		"title":    "string",
+		"tags":     "array",
+		"meta":     "object",
+		"created":  "string",
+		"owner":    "object",
+		"children": "array",
+		"counts":   "object",
+		"raw":      "string",
+		"name":     "string",
+	}
+	for name, typ := range expected {
+		prop, ok := s.Properties[name]
+		if !ok {
+			t.Errorf("missing property %q", name)
+			continue
+		}
+		if prop.Type != typ {
+			t.Errorf("property %q: expected %q, got %q", name, typ, prop.Type)
+		}
+	}
+
+	for _, name := range []string{"Skipped", "-"} {
+		if _, ok := s.Properties[name]; ok {
+			t.Errorf("unexpected property %q", name)
+		}
+	}
+
+	if s.Properties["created"].Format != "date-time" {
+		t.Error("expected date-time format for time.Time")
+	}
+	if s.Properties["children"].Items.Type != "object" {
+		t.Error("expected recursive type to terminate as object")
+	}
+
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	if !required["id"] || !required["tags"] || required["title"] {
+		t.Errorf("unexpected required list: %v", s.Required)
+	}
+}
+
+func TestFromTypeSlice(t *testing.T) {
+	s := FromType(reflect.TypeOf([]inner{}))
+	if s.Type != "array" || s.Items == nil || s.Items.Type != "object" {
+		t.Errorf("unexpected schema: %+v", s)
+	}
+	if FromValue(nil).Type != "" {
+		t.Error("expected empty schema for nil")
+	}
+}
+
+func TestInput(t *testing.T) {
+	var limit int
+	var state string
+	var all bool
+	cmd := &cobra.Command{
+		Use:  "issue [owner/repo] [number]",
+		Args: cobra.ExactArgs(2),
+		Run:  func(cmd *cobra.Command, args []string) {},
+	}
+	cmd.Flags().IntVarP(&limit, "limit", "l", 20, "Number of issues")
+	cmd.Flags().StringVarP(&state, "state", "s", "open", "State")
+	cmd.Flags().BoolVarP(&all, "all", "a", false, "All")
+
+	s := Input(cmd)
+
+	args := s.Properties[ArgsProperty]
+	if args == nil {
+		t.Fatal("expected args property")
+	}
+	if args.MinItems == nil || *args.MinItems != 2 || args.MaxItems == nil || *args.MaxItems != 2 {
+		t.Errorf("expected exactly 2 args, got min=%v max=%v", args.MinItems, args.MaxItems)
+	}
+	if len(s.Required) != 1 || s.Required[0] != ArgsProperty {
+		t.Errorf("expected args to be required, got %v", s.Required)
+	}
+
+	if p := s.Properties["limit"]; p.Type != "integer" || p.Default != 20 {
+		t.Errorf("unexpected limit schema: %+v", p)
+	}
+	if p := s.Properties["state"]; p.Type != "string" || p.Default != "open" {
+		t.Errorf("unexpected state schema: %+v", p)
+	}
+	if p := s.Properties["all"]; p.Type != "boolean" || p.Default != nil {
+		t.Errorf("unexpected all schema: %+v", p)
+	}
+}
+
+func TestArgsArity(t *testing.T) {
+	tests := []struct {
+		name    string
+		use     string
+		args    cobra.PositionalArgs
+		wantNil bool
+		min     int
+		max     int
+	}{
+		{"no args", "list", nil, true, 0, 0},
+		{"range", "set [service] [key] [value]", cobra.RangeArgs(2, 3), false, 2, 3},
+		{"minimum", "del [key...]", cobra.MinimumNArgs(1), false, 1, -1},
+		{"optional", "list [name]", cobra.MaximumNArgs(1), false, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Args(&cobra.Command{Use: tt.use, Args: tt.args})
+			if tt.wantNil {
+				if s != nil {
+					t.Errorf("expected nil, got %+v", s)
+				}
+				return
+			}
+			gotMin := 0
+			if s.MinItems != nil {
+				gotMin = *s.MinItems
+			}
+			gotMax := -1
+			if s.MaxItems != nil {
+				gotMax = *s.MaxItems
+			}
+			if gotMin != tt.min || gotMax != tt.max {
+				t.Errorf("expected min=%d max=%d, got min=%d max=%d", tt.min, tt.max, gotMin, gotMax)
+			}
+		})
+	}
+}
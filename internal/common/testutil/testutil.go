@@ -0,0 +1,112 @@
+// Package testutil holds fixtures shared by package tests
+package testutil
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// Main runs a package's tests with the config file, response cache and
+// audit log kept out of the developer's home: config and cache live in a
+// temp dir removed afterwards, and auditing is off. setup, if not nil,
+// runs before the tests, e.g. to config.Set credentials.
+func Main(m *testing.M, setup func()) {
+	dir, err := os.MkdirTemp("", "pocket-test-*")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("POCKET_CONFIG", filepath.Join(dir, "config.json"))
+	os.Setenv("POCKET_CACHE_DIR", filepath.Join(dir, "cache"))
+	os.Setenv("POCKET_AUDIT_LOG", "off")
+	if setup != nil {
+		setup()
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// Call is a request a FakeAPI server received
+type Call struct {
+	Method string
+	// Path is escaped, with the server's prefix cut off
+	Path  string
+	Query url.Values
+	Body  map[string]any
+}
+
+// FakeAPI starts a server for routes keyed by "METHOD /escaped/path",
+// where paths are matched with prefix (such as "/api/v4") cut off. It
+// returns the server's URL and the calls it receives, recorded before
+// their route runs.
+func FakeAPI(t *testing.T, prefix string, routes map[string]http.HandlerFunc) (string, *[]Call) {
+	t.Helper()
+	var calls []Call
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.EscapedPath(), prefix)
+		call := Call{Method: r.Method, Path: path, Query: r.URL.Query()}
+		_ = json.NewDecoder(r.Body).Decode(&call.Body)
+		calls = append(calls, call)
+
+		route, ok := routes[r.Method+" "+path]
+		if !ok {
+			t.Errorf("unexpected %s %s", r.Method, path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		route(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, &calls
+}
+
+// Reply is a route answering with status and body as JSON, or no body
+// when it is nil
+func Reply(status int, body any) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if body != nil {
+			json.NewEncoder(w).Encode(body)
+		}
+	}
+}
+
+// Text is a route answering 200 with body as it is
+func Text(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(body))
+	}
+}
+
+// Run runs cmd with args and decodes the response envelope it prints
+func Run(t *testing.T, cmd *cobra.Command, args ...string) output.Response {
+	t.Helper()
+	var buf bytes.Buffer
+	ctx := output.NewContext(context.Background())
+	output.SetOutput(ctx, &buf)
+	output.SetErrorOutput(ctx, &buf)
+
+	cmd.SetArgs(args)
+	cmd.SilenceErrors, cmd.SilenceUsage = true, true
+	_ = cmd.ExecuteContext(ctx)
+
+	var resp output.Response
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatalf("%v: %s", err, buf.String())
+	}
+	return resp
+}
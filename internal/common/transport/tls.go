@@ -0,0 +1,64 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// TLSConfig builds the TLS settings for a self-hosted server: caFile is a
+// PEM bundle trusted on top of the system roots, and certFile/keyFile a
+// PEM client certificate and its key. keyFile may be empty when the key
+// is in certFile. It returns nil when all are empty.
+func TLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	if caFile == "" && certFile == "" && keyFile == "" {
+		return nil, nil
+	}
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no PEM certificates in CA bundle %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if certFile == "" && keyFile != "" {
+		return nil, errors.New("client key set without a client certificate")
+	}
+	if certFile != "" {
+		if keyFile == "" {
+			keyFile = certFile
+		}
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
+
+// NewTLS is New for servers that need cfg, from TLSConfig; a nil cfg
+// gives the same client as New
+func NewTLS(timeout time.Duration, cfg *tls.Config) *http.Client {
+	if cfg == nil {
+		return New(timeout)
+	}
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.TLSClientConfig = cfg
+	return &http.Client{Timeout: timeout, Transport: outer(&Transport{Base: base}, true)}
+}
@@ -0,0 +1,106 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writePEM writes blocks of the given type to a file in dir
+func writePEM(t *testing.T, dir, name, kind string, blocks ...[]byte) string {
+	t.Helper()
+	var data []byte
+	for _, b := range blocks {
+		data = append(data, pem.EncodeToMemory(&pem.Block{Type: kind, Bytes: b})...)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestNewTLS(t *testing.T) {
+	t.Setenv("POCKET_AUDIT_LOG", "off")
+	recordSleeps(t)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+
+	// The server's own certificate doubles as the CA and the client cert
+	dir := t.TempDir()
+	serverCert := srv.TLS.Certificates[0]
+	key, err := x509.MarshalPKCS8PrivateKey(serverCert.PrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caFile := writePEM(t, dir, "ca.pem", "CERTIFICATE", serverCert.Certificate[0])
+	keyFile := writePEM(t, dir, "key.pem", "PRIVATE KEY", key)
+
+	get := func(client *http.Client) (int, error) {
+		req, _ := http.NewRequest("GET", srv.URL, http.NoBody)
+		req.Header.Set("Cache-Control", "no-cache")
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+
+	if _, err := get(NewTLS(0, nil)); err == nil {
+		t.Error("the system roots should not trust the test server")
+	}
+
+	cfg, err := TLSConfig(caFile, "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, err := get(NewTLS(0, cfg)); err != nil || status != http.StatusUnauthorized {
+		t.Errorf("with CA only: %d, %v", status, err)
+	}
+
+	cfg, err = TLSConfig(caFile, caFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, err := get(NewTLS(0, cfg)); err != nil || status != http.StatusOK {
+		t.Errorf("with client certificate: %d, %v", status, err)
+	}
+}
+
+func TestTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	notPEM := filepath.Join(dir, "ca.pem")
+	os.WriteFile(notPEM, []byte("not a certificate"), 0o600)
+
+	if cfg, err := TLSConfig("", "", ""); cfg != nil || err != nil {
+		t.Errorf("no files: %v, %v", cfg, err)
+	}
+	for name, files := range map[string][3]string{
+		"missing CA":       {filepath.Join(dir, "missing.pem"), "", ""},
+		"CA without PEM":   {notPEM, "", ""},
+		"key without cert": {"", "", notPEM},
+		"bad client cert":  {"", notPEM, notPEM},
+	} {
+		if _, err := TLSConfig(files[0], files[1], files[2]); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/unstablemind/pocket/internal/common/audit"
+	"github.com/unstablemind/pocket/internal/common/cache"
+	"github.com/unstablemind/pocket/internal/common/cassette"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+)
+
+// Retry and concurrency defaults for the shared transport
+const (
+	DefaultMaxRetries = 3
+	DefaultBaseDelay  = 500 * time.Millisecond
+	// DefaultMaxDelay caps a single wait; hosts asking for longer fail fast
+	// with a RateLimitError instead of stalling the command
+	DefaultMaxDelay = 30 * time.Second
+	DefaultPerHost  = 4
+)
+
+// Output error codes for exhausted rate limits, requests that got no
+// response and server errors
+const (
+	ErrorCode        = "rate_limited"
+	NetworkErrorCode = "network_error"
+	ServerErrorCode  = "server_error"
+)
+
+// maxServerMessage bounds how much of a server error's body is kept
+const maxServerMessage = 512
+
+// Default is shared by every client from New, so per-host limits apply
+// across integrations talking to the same API
+var Default = &Transport{}
+
+// sleep waits for d or until ctx is done; replaced in tests
+var sleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// New returns an HTTP client using the shared retrying transport behind
+// the on-disk response cache
+func New(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: outer(Default, true)}
+}
+
+// Wrap routes an existing client (e.g. one with a custom CheckRedirect)
+// through the shared retry policy
+func Wrap(c *http.Client) *http.Client {
+	switch c.Transport.(type) {
+	case *Transport, *cassette.Transport, *dryrun.Transport:
+	default:
+		c.Transport = outer(&Transport{Base: c.Transport}, false)
+	}
+	return c
+}
+
+// outer adds the cassette recorder or player when POCKET_RECORD or
+// POCKET_REPLAY is set, bypassing the cache so cassettes see every
+// exchange, and the cache otherwise. The dry-run planner sits in front of
+// both, so skipped writes are never recorded or sent. The audit log only
+// sees requests that leave the cache and cassette.
+func outer(rt http.RoundTripper, cached bool) http.RoundTripper {
+	rt = &audit.Transport{Base: rt}
+	if c, ok := cassette.FromEnv(rt); ok {
+		return &dryrun.Transport{Base: c}
+	}
+	if cached {
+		rt = &cache.Transport{Base: rt}
+	}
+	return &dryrun.Transport{Base: rt}
+}
+
+// Transport retries rate-limited and transiently failing requests with
+// exponential backoff, honoring Retry-After and X-RateLimit-Reset, and
+// bounds the number of in-flight requests per host. A 5xx left once
+// retries are spent is returned as a ServerError. Zero fields use the
+// package defaults.
+type Transport struct {
+	Base       http.RoundTripper
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	PerHost    int
+}
+
+// RateLimitError reports a host that kept rate limiting the request
+type RateLimitError struct {
+	Host       string
+	StatusCode int
+	RetryAfter time.Duration
+	Reset      time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	msg := fmt.Sprintf("rate limited by %s (HTTP %d)", e.Host, e.StatusCode)
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %s", e.RetryAfter.Round(time.Second))
+	}
+	return msg
+}
+
+// ErrorCode reports the error as rate_limited in whichever command it
+// surfaces
+func (e *RateLimitError) ErrorCode() string { return ErrorCode }
+
+// HTTPStatus is the status the host answered with
+func (e *RateLimitError) HTTPStatus() int { return e.StatusCode }
+
+// ErrorDetails is the structured payload shown with the rate_limited error
+func (e *RateLimitError) ErrorDetails() any {
+	d := map[string]any{
+		"host":   e.Host,
+		"status": e.StatusCode,
+	}
+	if e.RetryAfter > 0 {
+		d["retry_after_seconds"] = int(e.RetryAfter.Round(time.Second).Seconds())
+	}
+	if !e.Reset.IsZero() {
+		d["reset_at"] = e.Reset.UTC().Format(time.RFC3339)
+	}
+	return d
+}
+
+// NetworkError reports a request that got no response, once any retries
+// are spent: a refused connection, a failed DNS lookup or a reset
+type NetworkError struct {
+	Host string
+	Err  error
+}
+
+func (e *NetworkError) Error() string { return e.Err.Error() }
+
+func (e *NetworkError) Unwrap() error { return e.Err }
+
+// ErrorCode reports the error as network_error in whichever command it
+// surfaces
+func (e *NetworkError) ErrorCode() string { return NetworkErrorCode }
+
+func (e *NetworkError) ErrorDetails() any { return map[string]any{"host": e.Host} }
+
+// ServerError reports a host that answered with a 5xx status, once any
+// retries are spent. Message is the start of the response body.
+type ServerError struct {
+	Host       string
+	StatusCode int
+	Message    string
+}
+
+func (e *ServerError) Error() string {
+	msg := fmt.Sprintf("server error from %s (HTTP %d)", e.Host, e.StatusCode)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// ErrorCode reports the error as server_error in whichever command it
+// surfaces
+func (e *ServerError) ErrorCode() string { return ServerErrorCode }
+
+func (e *ServerError) ErrorDetails() any {
+	return map[string]any{"host": e.Host, "status": e.StatusCode}
+}
+
+// HTTPStatus is the status the host answered with
+func (e *ServerError) HTTPStatus() int { return e.StatusCode }
+
+// AsServerError extracts a ServerError from err, if any
+func AsServerError(err error) (*ServerError, bool) {
+	var e *ServerError
+	ok := errors.As(err, &e)
+	return e, ok
+}
+
+func newServerError(req *http.Request, resp *http.Response) *ServerError {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxServerMessage))
+	drain(resp)
+	return &ServerError{Host: req.URL.Host, StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	release, err := acquire(ctx, req.URL.Host, t.perHost())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	for attempt := 0; ; attempt++ {
+		try := req
+		if attempt > 0 {
+			if try, err = rewind(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.base().RoundTrip(try)
+		wait, rateErr, retry := t.next(req, resp, err, attempt)
+		if !retry {
+			if rateErr != nil {
+				drain(resp)
+				return nil, rateErr
+			}
+			if err != nil && ctx.Err() == nil {
+				err = &NetworkError{Host: req.URL.Host, Err: err}
+			}
+			if err == nil && resp.StatusCode >= http.StatusInternalServerError {
+				return nil, newServerError(req, resp)
+			}
+			return resp, err
+		}
+
+		drain(resp)
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// next decides whether to retry and how long to wait first
+func (t *Transport) next(req *http.Request, resp *http.Response, err error, attempt int) (time.Duration, *RateLimitError, bool) {
+	canRetry := attempt < t.maxRetries() && rewindable(req)
+
+	if err != nil {
+		if req.Context().Err() != nil || !canRetry || !idempotent(req) {
+			return 0, nil, false
+		}
+		wait := t.backoff(attempt)
+		return wait, nil, t.fits(req, wait)
+	}
+
+	if isRateLimited(resp) {
+		wait, reset := headerWait(resp.Header, time.Now())
+		rateErr := &RateLimitError{Host: req.URL.Host, StatusCode: resp.StatusCode, RetryAfter: wait, Reset: reset}
+		if wait <= 0 {
+			wait = t.backoff(attempt)
+		}
+		if canRetry && t.fits(req, wait) {
+			return wait, nil, true
+		}
+		return 0, rateErr, false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if !canRetry || !idempotent(req) {
+			return 0, nil, false
+		}
+		wait, _ := headerWait(resp.Header, time.Now())
+		if wait <= 0 {
+			wait = t.backoff(attempt)
+		}
+		return wait, nil, t.fits(req, wait)
+	}
+
+	return 0, nil, false
+}
+
+// fits rejects waits longer than MaxDelay or the request's deadline
+func (t *Transport) fits(req *http.Request, wait time.Duration) bool {
+	if wait > t.maxDelay() {
+		return false
+	}
+	if deadline, ok := req.Context().Deadline(); ok && time.Until(deadline) < wait {
+		return false
+	}
+	return true
+}
+
+// backoff is exponential with jitter: half the delay is fixed, half random
+func (t *Transport) backoff(attempt int) time.Duration {
+	d := t.baseDelay() << attempt
+	if d <= 0 || d > t.maxDelay() {
+		d = t.maxDelay()
+	}
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) maxRetries() int {
+	if t.MaxRetries > 0 {
+		return t.MaxRetries
+	}
+	return DefaultMaxRetries
+}
+
+func (t *Transport) baseDelay() time.Duration {
+	if t.BaseDelay > 0 {
+		return t.BaseDelay
+	}
+	return DefaultBaseDelay
+}
+
+func (t *Transport) maxDelay() time.Duration {
+	if t.MaxDelay > 0 {
+		return t.MaxDelay
+	}
+	return DefaultMaxDelay
+}
+
+func (t *Transport) perHost() int {
+	if t.PerHost > 0 {
+		return t.PerHost
+	}
+	return DefaultPerHost
+}
+
+// isRateLimited covers 429 and GitHub-style 403s with an exhausted quota
+func isRateLimited(resp *http.Response) bool {
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0"
+}
+
+// headerWait reads Retry-After (seconds or HTTP date), then
+// X-RateLimit-Reset / RateLimit-Reset (unix time or seconds from now)
+func headerWait(h http.Header, now time.Time) (time.Duration, time.Time) {
+	if v := strings.TrimSpace(h.Get("Retry-After")); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return max(time.Duration(secs)*time.Second, 0), now.Add(time.Duration(secs) * time.Second)
+		}
+		if at, err := http.ParseTime(v); err == nil {
+			return max(at.Sub(now), 0), at
+		}
+	}
+
+	for _, name := range []string{"X-RateLimit-Reset", "RateLimit-Reset"} {
+		v := strings.TrimSpace(h.Get(name))
+		if v == "" {
+			continue
+		}
+		n, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			continue
+		}
+		// Large values are unix timestamps (GitHub); small ones are a
+		// number of seconds (Reddit, the IETF RateLimit draft)
+		var at time.Time
+		if n > 1e9 {
+			at = time.Unix(int64(n), 0)
+		} else {
+			at = now.Add(time.Duration(n * float64(time.Second)))
+		}
+		return max(at.Sub(now), 0), at
+	}
+
+	return 0, time.Time{}
+}
+
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+func rewindable(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+func rewind(req *http.Request) (*http.Request, error) {
+	r := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		r.Body = body
+	}
+	return r, nil
+}
+
+func drain(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+}
+
+var (
+	hostsMu sync.Mutex
+	hosts   = map[string]chan struct{}{}
+)
+
+// acquire takes one of the per-host slots shared by every Transport
+func acquire(ctx context.Context, host string, limit int) (func(), error) {
+	hostsMu.Lock()
+	sem, ok := hosts[host]
+	if !ok {
+		sem = make(chan struct{}, limit)
+		hosts[host] = sem
+	}
+	hostsMu.Unlock()
+
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// AsRateLimit extracts a RateLimitError from err, if any
+func AsRateLimit(err error) (*RateLimitError, bool) {
+	var e *RateLimitError
+	ok := errors.As(err, &e)
+	return e, ok
+}
@@ -0,0 +1,272 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// recordSleeps replaces sleep with a recorder so tests never wait
+func recordSleeps(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var waits []time.Duration
+	old := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	t.Cleanup(func() { sleep = old })
+	return &waits
+}
+
+func TestRetriesOn429WithRetryAfter(t *testing.T) {
+	waits := recordSleeps(t)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer srv.Close()
+
+	resp, err := New(0).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if string(body) != "ok" || calls.Load() != 3 {
+		t.Errorf("expected success after 3 calls, got %q after %d", body, calls.Load())
+	}
+	if len(*waits) != 2 || (*waits)[0] != 2*time.Second {
+		t.Errorf("expected two 2s waits, got %v", *waits)
+	}
+}
+
+func TestRateLimitErrorWhenExhausted(t *testing.T) {
+	ctx := context.Background()
+	recordSleeps(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Transport{MaxRetries: 2}}
+	_, err := client.Get(srv.URL)
+	rateErr, ok := AsRateLimit(err)
+	if !ok {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+	if rateErr.StatusCode != http.StatusTooManyRequests || rateErr.RetryAfter != time.Second {
+		t.Errorf("unexpected error fields: %+v", rateErr)
+	}
+
+	// The structured code survives integrations wrapping the message
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	defer output.SetOutput(ctx, nil)
+	_ = output.PrintErr(ctx, "fetch_failed", fmt.Errorf("request failed: %w", err), nil)
+
+	var resp output.Response
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid error output: %v", err)
+	}
+	if resp.Error.Code != ErrorCode {
+		t.Errorf("expected %s code, got %s", ErrorCode, resp.Error.Code)
+	}
+	if d, _ := resp.Error.Details.(map[string]any); d["retry_after_seconds"] != float64(1) {
+		t.Errorf("expected retry_after_seconds in details, got %v", resp.Error.Details)
+	}
+}
+
+func TestNetworkErrorAfterRetries(t *testing.T) {
+	ctx := context.Background()
+	waits := recordSleeps(t)
+	srv := httptest.NewServer(http.NotFoundHandler())
+	addr := srv.URL
+	srv.Close()
+
+	client := &http.Client{Transport: &Transport{MaxRetries: 2}}
+	_, err := client.Get(addr)
+	var netErr *NetworkError
+	if !errors.As(err, &netErr) || len(*waits) != 2 {
+		t.Fatalf("expected NetworkError after 2 retries, got %v with waits %v", err, *waits)
+	}
+
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	defer output.SetOutput(ctx, nil)
+	_ = output.PrintErr(ctx, "fetch_failed", err, nil)
+	var resp output.Response
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil || resp.Error.Code != NetworkErrorCode {
+		t.Errorf("expected %s, got %s", NetworkErrorCode, buf.String())
+	}
+}
+
+func TestGitHubStyleResetTooFarFailsFast(t *testing.T) {
+	waits := recordSleeps(t)
+	reset := time.Now().Add(time.Hour).Unix()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset, 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	_, err := New(0).Get(srv.URL)
+	rateErr, ok := AsRateLimit(err)
+	if !ok {
+		t.Fatalf("expected RateLimitError, got %v", err)
+	}
+	if calls.Load() != 1 || len(*waits) != 0 {
+		t.Errorf("expected no retry for an hour-long reset, got %d calls, waits %v", calls.Load(), *waits)
+	}
+	if rateErr.Reset.Unix() != reset {
+		t.Errorf("expected reset %d, got %d", reset, rateErr.Reset.Unix())
+	}
+}
+
+func TestRetriesServerErrorsOnlyWhenIdempotent(t *testing.T) {
+	recordSleeps(t)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+		w.Write([]byte("upstream down\n"))
+	}))
+	defer srv.Close()
+
+	client := New(0)
+	_, err := client.Get(srv.URL)
+	serverErr, ok := AsServerError(err)
+	if !ok || serverErr.StatusCode != http.StatusBadGateway || serverErr.Message != "upstream down" || calls.Load() != int32(DefaultMaxRetries+1) {
+		t.Errorf("GET: expected %d calls ending in a 502 ServerError, got %d (%v)", DefaultMaxRetries+1, calls.Load(), err)
+	}
+
+	calls.Store(0)
+	_, err = client.Post(srv.URL, "text/plain", strings.NewReader("x"))
+	if _, ok := AsServerError(err); !ok || calls.Load() != 1 {
+		t.Errorf("POST should not be retried on 502, got %d calls (%v)", calls.Load(), err)
+	}
+}
+
+func TestRetryResendsBody(t *testing.T) {
+	recordSleeps(t)
+	var bodies []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(b))
+		n := len(bodies)
+		mu.Unlock()
+		if n == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	resp, err := New(0).Post(srv.URL, "text/plain", strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if len(bodies) != 2 || bodies[1] != "payload" {
+		t.Errorf("expected body resent on retry, got %q", bodies)
+	}
+}
+
+func TestPerHostConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		inFlight.Add(-1)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &Transport{PerHost: 2}}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if resp, err := client.Get(srv.URL); err == nil {
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak.Load() > 2 {
+		t.Errorf("expected at most 2 concurrent requests, saw %d", peak.Load())
+	}
+}
+
+func TestHeaderWait(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    time.Duration
+	}{
+		{"retry-after seconds", map[string]string{"Retry-After": "30"}, 30 * time.Second},
+		{"retry-after date", map[string]string{"Retry-After": now.Add(time.Minute).Format(http.TimeFormat)}, time.Minute},
+		{"reset epoch", map[string]string{"X-RateLimit-Reset": strconv.FormatInt(now.Add(90*time.Second).Unix(), 10)}, 90 * time.Second},
+		{"reset seconds", map[string]string{"X-Ratelimit-Reset": "12.5"}, 12500 * time.Millisecond},
+		{"reset in the past", map[string]string{"X-RateLimit-Reset": strconv.FormatInt(now.Add(-time.Minute).Unix(), 10)}, 0},
+		{"none", map[string]string{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			for k, v := range tt.headers {
+				h.Set(k, v)
+			}
+			got, _ := headerWait(h, now)
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBackoffBounds(t *testing.T) {
+	tr := &Transport{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	for attempt := 0; attempt < 10; attempt++ {
+		d := tr.backoff(attempt)
+		full := min(100*time.Millisecond<<attempt, time.Second)
+		if d < full/2 || d > full {
+			t.Errorf("attempt %d: backoff %v outside [%v, %v]", attempt, d, full/2, full)
+		}
+	}
+}
@@ -13,12 +13,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://discord.com/api/v10"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Guild is LLM-friendly guild output
 type Guild struct {
@@ -68,6 +70,22 @@ type DMChannel struct {
 	Recipient string `json:"recipient,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "discord",
+		Name:        "Discord",
+		Group:       "comms",
+		Description: "Servers (guilds), channels, messages, and DMs in Discord",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "discord_token", Description: "Bot token", Required: true},
+		},
+		SetupGuide:  "1. Go to https://discord.com/developers/applications\n2. Create application, then create Bot\n3. Copy the bot token\n4. Run: pocket config set discord_token <token>",
+		TestCommand: "pocket comms discord guilds",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "discord",
@@ -89,13 +107,14 @@ func newGuildsCmd() *cobra.Command {
 		Use:   "guilds",
 		Short: "List guilds/servers the bot is in",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
 
 			reqURL := fmt.Sprintf("%s/users/@me/guilds", baseURL)
-			data, err := doRequest("GET", reqURL, token, nil)
+			data, err := doRequest(ctx, "GET", reqURL, token, nil)
 			if err != nil {
 				return err
 			}
@@ -109,7 +128,7 @@ func newGuildsCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &guilds); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			result := make([]Guild, 0, len(guilds))
@@ -123,7 +142,7 @@ func newGuildsCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -138,14 +157,15 @@ func newChannelsCmd() *cobra.Command {
 		Short: "List channels in a guild",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
 
 			guildID := args[0]
 			reqURL := fmt.Sprintf("%s/guilds/%s/channels", baseURL, guildID)
-			data, err := doRequest("GET", reqURL, token, nil)
+			data, err := doRequest(ctx, "GET", reqURL, token, nil)
 			if err != nil {
 				return err
 			}
@@ -161,7 +181,7 @@ func newChannelsCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &channels); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			// Filter by type if specified
@@ -196,7 +216,7 @@ func newChannelsCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -213,7 +233,8 @@ func newMessagesCmd() *cobra.Command {
 		Short: "Get recent messages from a channel",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -228,7 +249,7 @@ func newMessagesCmd() *cobra.Command {
 
 			channelID := args[0]
 			reqURL := fmt.Sprintf("%s/channels/%s/messages?limit=%d", baseURL, channelID, limit)
-			data, err := doRequest("GET", reqURL, token, nil)
+			data, err := doRequest(ctx, "GET", reqURL, token, nil)
 			if err != nil {
 				return err
 			}
@@ -252,7 +273,7 @@ func newMessagesCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &messages); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			result := make([]Message, 0, len(messages))
@@ -280,7 +301,7 @@ func newMessagesCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -297,20 +318,21 @@ func newSendCmd() *cobra.Command {
 		Short: "Send a message to a channel",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
 
 			if channelID == "" {
-				return output.PrintError("missing_channel", "Channel ID is required (use --channel)", map[string]any{
+				return output.PrintError(ctx, "missing_channel", "Channel ID is required (use --channel)", map[string]any{
 					"hint": "Use 'pocket discord channels <guild-id>' to list available channels",
 				})
 			}
 
 			content := args[0]
 			if len(content) > 2000 {
-				return output.PrintError("message_too_long", "Message exceeds 2000 character limit", map[string]any{
+				return output.PrintError(ctx, "message_too_long", "Message exceeds 2000 character limit", map[string]any{
 					"length": len(content),
 					"max":    2000,
 				})
@@ -318,7 +340,7 @@ func newSendCmd() *cobra.Command {
 
 			reqURL := fmt.Sprintf("%s/channels/%s/messages", baseURL, channelID)
 			payload := map[string]string{"content": content}
-			data, err := doRequest("POST", reqURL, token, payload)
+			data, err := doRequest(ctx, "POST", reqURL, token, payload)
 			if err != nil {
 				return err
 			}
@@ -331,10 +353,10 @@ func newSendCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
-			return output.Print(SentMessage{
+			return output.Print(ctx, SentMessage{
 				ID:        resp.ID,
 				ChannelID: resp.ChannelID,
 				Content:   resp.Content,
@@ -355,7 +377,8 @@ func newDMCmd() *cobra.Command {
 		Short: "Send a direct message to a user",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -364,7 +387,7 @@ func newDMCmd() *cobra.Command {
 			content := args[1]
 
 			if len(content) > 2000 {
-				return output.PrintError("message_too_long", "Message exceeds 2000 character limit", map[string]any{
+				return output.PrintError(ctx, "message_too_long", "Message exceeds 2000 character limit", map[string]any{
 					"length": len(content),
 					"max":    2000,
 				})
@@ -373,7 +396,7 @@ func newDMCmd() *cobra.Command {
 			// First, create/get the DM channel
 			dmURL := fmt.Sprintf("%s/users/@me/channels", baseURL)
 			dmPayload := map[string]string{"recipient_id": userID}
-			dmData, err := doRequest("POST", dmURL, token, dmPayload)
+			dmData, err := doRequest(ctx, "POST", dmURL, token, dmPayload)
 			if err != nil {
 				return err
 			}
@@ -384,13 +407,13 @@ func newDMCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(dmData, &dmChannel); err != nil {
-				return output.PrintError("parse_failed", "Failed to create DM channel: "+err.Error(), nil)
+				return output.PrintError(ctx, "parse_failed", "Failed to create DM channel: "+err.Error(), nil)
 			}
 
 			// Now send the message to the DM channel
 			msgURL := fmt.Sprintf("%s/channels/%s/messages", baseURL, dmChannel.ID)
 			msgPayload := map[string]string{"content": content}
-			msgData, err := doRequest("POST", msgURL, token, msgPayload)
+			msgData, err := doRequest(ctx, "POST", msgURL, token, msgPayload)
 			if err != nil {
 				return err
 			}
@@ -403,10 +426,10 @@ func newDMCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(msgData, &resp); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"status":     "sent",
 				"message_id": resp.ID,
 				"channel_id": resp.ChannelID,
@@ -420,10 +443,10 @@ func newDMCmd() *cobra.Command {
 	return cmd
 }
 
-func getToken() (string, error) {
-	token, err := config.Get("discord_token")
+func getToken(ctx context.Context) (string, error) {
+	token, err := config.Get(ctx, "discord_token")
 	if err != nil || token == "" {
-		return "", output.PrintError("setup_required", "Discord bot token not configured", map[string]any{
+		return "", output.PrintError(ctx, "setup_required", "Discord bot token not configured", map[string]any{
 			"missing":   []string{"discord_token"},
 			"setup_cmd": "pocket setup show discord",
 			"hint":      "Run 'pocket setup show discord' for setup instructions",
@@ -432,22 +455,22 @@ func getToken() (string, error) {
 	return token, nil
 }
 
-func doRequest(method, reqURL, token string, payload any) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+func doRequest(ctx context.Context, method, reqURL, token string, payload any) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
 	var bodyReader io.Reader
 	if payload != nil {
 		jsonData, err := json.Marshal(payload)
 		if err != nil {
-			return nil, output.PrintError("request_failed", "Failed to encode payload: "+err.Error(), nil)
+			return nil, output.PrintError(ctx, "request_failed", "Failed to encode payload: "+err.Error(), nil)
 		}
 		bodyReader = bytes.NewReader(jsonData)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, reqURL, bodyReader)
 	if err != nil {
-		return nil, output.PrintError("request_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "request_failed", err, nil)
 	}
 
 	req.Header.Set("Authorization", "Bot "+token)
@@ -458,13 +481,13 @@ func doRequest(method, reqURL, token string, payload any) ([]byte, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("request_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "request_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, output.PrintError("read_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "read_failed", err, nil)
 	}
 
 	// Handle rate limiting
@@ -476,7 +499,7 @@ func doRequest(method, reqURL, token string, payload any) ([]byte, error) {
 		}
 		_ = json.Unmarshal(data, &rateLimitResp)
 
-		return nil, output.PrintError("rate_limited", "Discord rate limit exceeded", map[string]any{
+		return nil, output.PrintError(ctx, "rate_limited", "Discord rate limit exceeded", map[string]any{
 			"retry_after_seconds": rateLimitResp.RetryAfter,
 			"global":              rateLimitResp.Global,
 			"hint":                fmt.Sprintf("Wait %.1f seconds before retrying", rateLimitResp.RetryAfter),
@@ -485,7 +508,7 @@ func doRequest(method, reqURL, token string, payload any) ([]byte, error) {
 
 	// Handle authentication errors
 	if resp.StatusCode == 401 {
-		return nil, output.PrintError("auth_failed", "Invalid Discord bot token", map[string]any{
+		return nil, output.PrintError(ctx, "auth_failed", "Invalid Discord bot token", map[string]any{
 			"hint": "Check your discord_token configuration. Ensure you're using a Bot token, not a user token.",
 		})
 	}
@@ -498,7 +521,7 @@ func doRequest(method, reqURL, token string, payload any) ([]byte, error) {
 		}
 		_ = json.Unmarshal(data, &errResp)
 
-		return nil, output.PrintError("forbidden", "Access denied: "+errResp.Message, map[string]any{
+		return nil, output.PrintError(ctx, "forbidden", "Access denied: "+errResp.Message, map[string]any{
 			"discord_code": errResp.Code,
 			"hint":         "The bot may lack required permissions for this action",
 		})
@@ -512,7 +535,7 @@ func doRequest(method, reqURL, token string, payload any) ([]byte, error) {
 		}
 		_ = json.Unmarshal(data, &errResp)
 
-		return nil, output.PrintError("not_found", errResp.Message, map[string]any{
+		return nil, output.PrintError(ctx, "not_found", errResp.Message, map[string]any{
 			"discord_code": errResp.Code,
 		})
 	}
@@ -524,12 +547,12 @@ func doRequest(method, reqURL, token string, payload any) ([]byte, error) {
 			Code    int    `json:"code"`
 		}
 		if json.Unmarshal(data, &errResp) == nil && errResp.Message != "" {
-			return nil, output.PrintError("api_error", errResp.Message, map[string]any{
+			return nil, output.PrintError(ctx, "api_error", errResp.Message, map[string]any{
 				"status":       resp.StatusCode,
 				"discord_code": errResp.Code,
 			})
 		}
-		return nil, output.PrintError("api_error", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status), nil)
+		return nil, output.PrintError(ctx, "api_error", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, resp.Status), nil)
 	}
 
 	return data, nil
@@ -1,6 +1,7 @@
 package discord
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -44,6 +45,7 @@ func TestNewCmd(t *testing.T) {
 }
 
 func TestGuildsCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("Authorization") != "Bot test-token" {
 			t.Errorf("expected Authorization 'Bot test-token', got %q", r.Header.Get("Authorization"))
@@ -70,7 +72,7 @@ func TestGuildsCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("discord_token", "test-token")
+	config.Set(ctx, "discord_token", "test-token")
 
 	cmd := newGuildsCmd()
 	err := cmd.Execute()
@@ -80,7 +82,8 @@ func TestGuildsCmd(t *testing.T) {
 }
 
 func TestGuildsCmd_NoToken(t *testing.T) {
-	config.Set("discord_token", "")
+	ctx := context.Background()
+	config.Set(ctx, "discord_token", "")
 
 	cmd := newGuildsCmd()
 	err := cmd.Execute()
@@ -90,6 +93,7 @@ func TestGuildsCmd_NoToken(t *testing.T) {
 }
 
 func TestChannelsCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.Contains(r.URL.Path, "/guilds/") || !strings.Contains(r.URL.Path, "/channels") {
 			t.Errorf("expected path to contain '/guilds/.../channels', got %q", r.URL.Path)
@@ -121,7 +125,7 @@ func TestChannelsCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("discord_token", "test-token")
+	config.Set(ctx, "discord_token", "test-token")
 
 	cmd := newChannelsCmd()
 	cmd.SetArgs([]string{"123456789"})
@@ -133,6 +137,7 @@ func TestChannelsCmd(t *testing.T) {
 }
 
 func TestChannelsCmd_WithTypeFilter(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := []map[string]any{
 			{"id": "1", "name": "text", "type": 0, "position": 0},
@@ -146,7 +151,7 @@ func TestChannelsCmd_WithTypeFilter(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("discord_token", "test-token")
+	config.Set(ctx, "discord_token", "test-token")
 
 	cmd := newChannelsCmd()
 	cmd.SetArgs([]string{"123456789", "--type", "text"})
@@ -158,6 +163,7 @@ func TestChannelsCmd_WithTypeFilter(t *testing.T) {
 }
 
 func TestMessagesCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.Contains(r.URL.Path, "/channels/") || !strings.Contains(r.URL.Path, "/messages") {
 			t.Errorf("expected path to contain '/channels/.../messages', got %q", r.URL.Path)
@@ -186,7 +192,7 @@ func TestMessagesCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("discord_token", "test-token")
+	config.Set(ctx, "discord_token", "test-token")
 
 	cmd := newMessagesCmd()
 	cmd.SetArgs([]string{"987654321", "--limit", "50"})
@@ -198,6 +204,7 @@ func TestMessagesCmd(t *testing.T) {
 }
 
 func TestMessagesCmd_LimitClamping(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		json.NewEncoder(w).Encode([]map[string]any{})
 	}))
@@ -207,7 +214,7 @@ func TestMessagesCmd_LimitClamping(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("discord_token", "test-token")
+	config.Set(ctx, "discord_token", "test-token")
 
 	// Test limit > 100 (should be clamped to 100)
 	cmd := newMessagesCmd()
@@ -220,6 +227,7 @@ func TestMessagesCmd_LimitClamping(t *testing.T) {
 }
 
 func TestSendCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Errorf("expected POST, got %s", r.Method)
@@ -250,7 +258,7 @@ func TestSendCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("discord_token", "test-token")
+	config.Set(ctx, "discord_token", "test-token")
 
 	cmd := newSendCmd()
 	cmd.SetArgs([]string{"test message", "--channel", "987654321"})
@@ -262,7 +270,8 @@ func TestSendCmd(t *testing.T) {
 }
 
 func TestSendCmd_MessageTooLong(t *testing.T) {
-	config.Set("discord_token", "test-token")
+	ctx := context.Background()
+	config.Set(ctx, "discord_token", "test-token")
 
 	longMessage := strings.Repeat("a", 2001)
 
@@ -276,7 +285,8 @@ func TestSendCmd_MessageTooLong(t *testing.T) {
 }
 
 func TestSendCmd_NoChannel(t *testing.T) {
-	config.Set("discord_token", "test-token")
+	ctx := context.Background()
+	config.Set(ctx, "discord_token", "test-token")
 
 	cmd := newSendCmd()
 	cmd.SetArgs([]string{"test message"})
@@ -288,6 +298,7 @@ func TestSendCmd_NoChannel(t *testing.T) {
 }
 
 func TestDMCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
 		if strings.Contains(path, "/users/@me/channels") {
@@ -314,7 +325,7 @@ func TestDMCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("discord_token", "test-token")
+	config.Set(ctx, "discord_token", "test-token")
 
 	cmd := newDMCmd()
 	cmd.SetArgs([]string{"222222222", "DM message"})
@@ -326,7 +337,8 @@ func TestDMCmd(t *testing.T) {
 }
 
 func TestDMCmd_MessageTooLong(t *testing.T) {
-	config.Set("discord_token", "test-token")
+	ctx := context.Background()
+	config.Set(ctx, "discord_token", "test-token")
 
 	longMessage := strings.Repeat("a", 2001)
 
@@ -340,6 +352,7 @@ func TestDMCmd_MessageTooLong(t *testing.T) {
 }
 
 func TestDoRequest_RateLimit(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusTooManyRequests)
 		resp := map[string]any{
@@ -355,13 +368,14 @@ func TestDoRequest_RateLimit(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest("GET", srv.URL+"/test", "test-token", nil)
+	_, err := doRequest(ctx, "GET", srv.URL+"/test", "test-token", nil)
 	if err == nil {
 		t.Fatal("expected rate limit error, got nil")
 	}
 }
 
 func TestDoRequest_Unauthorized(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]any{"message": "Invalid token"})
@@ -372,13 +386,14 @@ func TestDoRequest_Unauthorized(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest("GET", srv.URL+"/test", "bad-token", nil)
+	_, err := doRequest(ctx, "GET", srv.URL+"/test", "bad-token", nil)
 	if err == nil {
 		t.Fatal("expected unauthorized error, got nil")
 	}
 }
 
 func TestDoRequest_Forbidden(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusForbidden)
 		resp := map[string]any{
@@ -393,13 +408,14 @@ func TestDoRequest_Forbidden(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest("GET", srv.URL+"/test", "test-token", nil)
+	_, err := doRequest(ctx, "GET", srv.URL+"/test", "test-token", nil)
 	if err == nil {
 		t.Fatal("expected forbidden error, got nil")
 	}
 }
 
 func TestDoRequest_NotFound(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		resp := map[string]any{
@@ -414,7 +430,7 @@ func TestDoRequest_NotFound(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest("GET", srv.URL+"/test", "test-token", nil)
+	_, err := doRequest(ctx, "GET", srv.URL+"/test", "test-token", nil)
 	if err == nil {
 		t.Fatal("expected not found error, got nil")
 	}
@@ -2,6 +2,7 @@ package email
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io"
@@ -19,7 +20,10 @@ import (
 	"github.com/emersion/go-imap/client"
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/audit"
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -43,6 +47,27 @@ type Mailbox struct {
 	Unread   uint32 `json:"unread"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "email",
+		Name:        "Email (IMAP/SMTP)",
+		Group:       "comms",
+		Description: "Read, search, send, and reply to emails via IMAP/SMTP (Gmail, Outlook, Yahoo, etc.)",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "email_address", Description: "Your email address", Required: true, Example: "you@gmail.com"},
+			{Key: "email_password", Description: "App password (not regular password)", Required: true, Example: "xxxx xxxx xxxx xxxx"},
+			{Key: "imap_server", Description: "IMAP server hostname", Required: true, Example: "imap.gmail.com"},
+			{Key: "smtp_server", Description: "SMTP server hostname", Required: true, Example: "smtp.gmail.com"},
+			{Key: "imap_port", Description: "IMAP port (default: 993)", Example: "993"},
+			{Key: "smtp_port", Description: "SMTP port (default: 587)", Example: "587"},
+		},
+		SetupGuide:  "For Gmail:\n1. Enable 2-Factor Authentication at https://myaccount.google.com/security\n2. Go to https://myaccount.google.com/apppasswords\n3. Create an app password (select 'Mail' and your device)\n4. Run these commands:\n   pocket config set email_address your@gmail.com\n   pocket config set email_password \"xxxx xxxx xxxx xxxx\"\n   pocket config set imap_server imap.gmail.com\n   pocket config set smtp_server smtp.gmail.com\n\nFor Outlook/Hotmail:\n   pocket config set imap_server outlook.office365.com\n   pocket config set smtp_server smtp.office365.com\n\nFor Yahoo:\n   pocket config set imap_server imap.mail.yahoo.com\n   pocket config set smtp_server smtp.mail.yahoo.com",
+		TestCommand: "pocket comms email list -l 1",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "email",
@@ -65,7 +90,8 @@ func newMailboxesCmd() *cobra.Command {
 		Use:   "mailboxes",
 		Short: "List mailboxes/folders",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := connectIMAP()
+			ctx := cmd.Context()
+			c, err := connectIMAP(ctx)
 			if err != nil {
 				return err
 			}
@@ -92,10 +118,10 @@ func newMailboxesCmd() *cobra.Command {
 			}
 
 			if err := <-done; err != nil {
-				return output.PrintError("list_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "list_failed", err, nil)
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -110,7 +136,8 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List emails",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := connectIMAP()
+			ctx := cmd.Context()
+			c, err := connectIMAP(ctx)
 			if err != nil {
 				return err
 			}
@@ -118,11 +145,11 @@ func newListCmd() *cobra.Command {
 
 			mbox, err := c.Select(mailbox, true)
 			if err != nil {
-				return output.PrintError("mailbox_error", err.Error(), nil)
+				return output.PrintErr(ctx, "mailbox_error", err, nil)
 			}
 
 			if mbox.Messages == 0 {
-				return output.Print([]Email{})
+				return output.Print(ctx, []Email{})
 			}
 
 			// Get the last N messages
@@ -178,13 +205,13 @@ func newListCmd() *cobra.Command {
 			}
 
 			if err := <-done; err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			// Reverse to show newest first
 			slices.Reverse(emails)
 
-			return output.Print(emails)
+			return output.Print(ctx, emails)
 		},
 	}
 
@@ -202,10 +229,11 @@ func newReadCmd() *cobra.Command {
 		Short: "Read an email by UID",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			var uid uint32
 			_, _ = fmt.Sscanf(args[0], "%d", &uid)
 
-			c, err := connectIMAP()
+			c, err := connectIMAP(ctx)
 			if err != nil {
 				return err
 			}
@@ -213,7 +241,7 @@ func newReadCmd() *cobra.Command {
 
 			_, err = c.Select(mailbox, true)
 			if err != nil {
-				return output.PrintError("mailbox_error", err.Error(), nil)
+				return output.PrintErr(ctx, "mailbox_error", err, nil)
 			}
 
 			seqSet := new(imap.SeqSet)
@@ -234,11 +262,11 @@ func newReadCmd() *cobra.Command {
 				msg = m
 			}
 			if err := <-done; err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			if msg == nil {
-				return output.PrintError("not_found", "Email not found: "+args[0], nil)
+				return output.PrintError(ctx, "not_found", "Email not found: "+args[0], nil)
 			}
 
 			// Parse body
@@ -281,7 +309,7 @@ func newReadCmd() *cobra.Command {
 				Unread:  unread,
 			}
 
-			return output.Print(email)
+			return output.Print(ctx, email)
 		},
 	}
 
@@ -301,14 +329,15 @@ func newSendCmd() *cobra.Command {
 		Short: "Send an email",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkEmailConfig(); err != nil {
+			ctx := cmd.Context()
+			if err := checkEmailConfig(ctx); err != nil {
 				return err
 			}
 
-			emailAddr, _ := config.Get("email_address")
-			password, _ := config.Get("email_password")
-			smtpServer, _ := config.Get("smtp_server")
-			smtpPort, _ := config.Get("smtp_port")
+			emailAddr, _ := config.Get(ctx, "email_address")
+			password, _ := config.Get(ctx, "email_password")
+			smtpServer, _ := config.Get(ctx, "smtp_server")
+			smtpPort, _ := config.Get(ctx, "smtp_port")
 			if smtpPort == "" {
 				smtpPort = "587"
 			}
@@ -339,6 +368,11 @@ func newSendCmd() *cobra.Command {
 				}
 			}
 
+			if dryrun.Enabled(ctx) {
+				return planMail(ctx, addr, emailAddr, recipients, msg.Bytes())
+			}
+			audit.Contact(ctx, "smtp", addr)
+
 			// Try TLS first (port 587), then SSL (port 465)
 			var sendErr error
 			switch smtpPort {
@@ -349,37 +383,37 @@ func newSendCmd() *cobra.Command {
 				tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: smtpServer}
 				conn, err := tls.Dial("tcp", addr, tlsConfig)
 				if err != nil {
-					return output.PrintError("connect_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "connect_failed", err, nil)
 				}
 				defer conn.Close()
 
 				c, err := smtp.NewClient(conn, smtpServer)
 				if err != nil {
-					return output.PrintError("connect_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "connect_failed", err, nil)
 				}
 
 				if err = c.Auth(auth); err != nil {
-					return output.PrintError("auth_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "auth_failed", err, nil)
 				}
 				if err = c.Mail(emailAddr); err != nil {
-					return output.PrintError("send_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "send_failed", err, nil)
 				}
 				for _, rcpt := range recipients {
 					if err = c.Rcpt(rcpt); err != nil {
-						return output.PrintError("send_failed", err.Error(), nil)
+						return output.PrintErr(ctx, "send_failed", err, nil)
 					}
 				}
 				w, err := c.Data()
 				if err != nil {
-					return output.PrintError("send_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "send_failed", err, nil)
 				}
 				_, err = w.Write(msg.Bytes())
 				if err != nil {
-					return output.PrintError("send_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "send_failed", err, nil)
 				}
 				err = w.Close()
 				if err != nil {
-					return output.PrintError("send_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "send_failed", err, nil)
 				}
 				_ = c.Quit()
 			default:
@@ -387,14 +421,14 @@ func newSendCmd() *cobra.Command {
 			}
 
 			if sendErr != nil {
-				return output.PrintError("send_failed", sendErr.Error(), map[string]any{
+				return output.PrintErr(ctx, "send_failed", sendErr, map[string]any{
 					"smtp_server": smtpServer,
 					"smtp_port":   smtpPort,
 					"hint":        "For Gmail, ensure 'Less secure app access' or use App Password. Check smtp_server and smtp_port settings.",
 				})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"status":  "sent",
 				"to":      to,
 				"cc":      cc,
@@ -422,7 +456,8 @@ func newReplyCmd() *cobra.Command {
 		Short: "Reply to an email",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkEmailConfig(); err != nil {
+			ctx := cmd.Context()
+			if err := checkEmailConfig(ctx); err != nil {
 				return err
 			}
 
@@ -431,7 +466,7 @@ func newReplyCmd() *cobra.Command {
 			replyBody := args[1]
 
 			// First, fetch the original email to get reply details
-			c, err := connectIMAP()
+			c, err := connectIMAP(ctx)
 			if err != nil {
 				return err
 			}
@@ -439,7 +474,7 @@ func newReplyCmd() *cobra.Command {
 
 			_, err = c.Select(mailbox, true)
 			if err != nil {
-				return output.PrintError("mailbox_error", err.Error(), nil)
+				return output.PrintErr(ctx, "mailbox_error", err, nil)
 			}
 
 			seqSet := new(imap.SeqSet)
@@ -458,11 +493,11 @@ func newReplyCmd() *cobra.Command {
 				msg = m
 			}
 			if err := <-done; err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			if msg == nil || msg.Envelope == nil {
-				return output.PrintError("not_found", "Email not found: "+args[0], nil)
+				return output.PrintError(ctx, "not_found", "Email not found: "+args[0], nil)
 			}
 
 			// Determine reply recipient
@@ -473,7 +508,7 @@ func newReplyCmd() *cobra.Command {
 			case len(msg.Envelope.From) > 0:
 				replyTo = formatEmailOnly(msg.Envelope.From[0])
 			default:
-				return output.PrintError("no_sender", "Cannot determine sender to reply to", nil)
+				return output.PrintError(ctx, "no_sender", "Cannot determine sender to reply to", nil)
 			}
 
 			// Build CC list for reply-all
@@ -500,10 +535,10 @@ func newReplyCmd() *cobra.Command {
 			}
 
 			// Now send the reply using SMTP
-			emailAddr, _ := config.Get("email_address")
-			password, _ := config.Get("email_password")
-			smtpServer, _ := config.Get("smtp_server")
-			smtpPort, _ := config.Get("smtp_port")
+			emailAddr, _ := config.Get(ctx, "email_address")
+			password, _ := config.Get(ctx, "email_password")
+			smtpServer, _ := config.Get(ctx, "smtp_server")
+			smtpPort, _ := config.Get(ctx, "smtp_port")
 			if smtpPort == "" {
 				smtpPort = "587"
 			}
@@ -529,6 +564,11 @@ func newReplyCmd() *cobra.Command {
 			recipients := []string{replyTo}
 			recipients = append(recipients, ccList...)
 
+			if dryrun.Enabled(ctx) {
+				return planMail(ctx, addr, emailAddr, recipients, msgBuf.Bytes())
+			}
+			audit.Contact(ctx, "smtp", addr)
+
 			var sendErr error
 			switch smtpPort {
 			case "587":
@@ -537,36 +577,36 @@ func newReplyCmd() *cobra.Command {
 				tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: smtpServer}
 				conn, err := tls.Dial("tcp", addr, tlsConfig)
 				if err != nil {
-					return output.PrintError("connect_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "connect_failed", err, nil)
 				}
 				defer conn.Close()
 
 				smtpClient, err := smtp.NewClient(conn, smtpServer)
 				if err != nil {
-					return output.PrintError("connect_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "connect_failed", err, nil)
 				}
 
 				if err = smtpClient.Auth(auth); err != nil {
-					return output.PrintError("auth_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "auth_failed", err, nil)
 				}
 				if err = smtpClient.Mail(emailAddr); err != nil {
-					return output.PrintError("send_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "send_failed", err, nil)
 				}
 				for _, rcpt := range recipients {
 					if err = smtpClient.Rcpt(rcpt); err != nil {
-						return output.PrintError("send_failed", err.Error(), nil)
+						return output.PrintErr(ctx, "send_failed", err, nil)
 					}
 				}
 				w, err := smtpClient.Data()
 				if err != nil {
-					return output.PrintError("send_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "send_failed", err, nil)
 				}
 				_, err = w.Write(msgBuf.Bytes())
 				if err != nil {
-					return output.PrintError("send_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "send_failed", err, nil)
 				}
 				if err = w.Close(); err != nil {
-					return output.PrintError("send_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "send_failed", err, nil)
 				}
 				_ = smtpClient.Quit()
 			default:
@@ -574,10 +614,10 @@ func newReplyCmd() *cobra.Command {
 			}
 
 			if sendErr != nil {
-				return output.PrintError("send_failed", sendErr.Error(), nil)
+				return output.PrintErr(ctx, "send_failed", sendErr, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"status":       "sent",
 				"reply_to":     replyTo,
 				"cc":           ccList,
@@ -603,9 +643,10 @@ func newSearchCmd() *cobra.Command {
 		Long:  "Search emails. Query can be text to search in subject/body, or IMAP search criteria.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := args[0]
 
-			c, err := connectIMAP()
+			c, err := connectIMAP(ctx)
 			if err != nil {
 				return err
 			}
@@ -613,7 +654,7 @@ func newSearchCmd() *cobra.Command {
 
 			_, err = c.Select(mailbox, true)
 			if err != nil {
-				return output.PrintError("mailbox_error", err.Error(), nil)
+				return output.PrintErr(ctx, "mailbox_error", err, nil)
 			}
 
 			// Build search criteria - search in subject and body
@@ -627,11 +668,11 @@ func newSearchCmd() *cobra.Command {
 
 			uids, err := c.Search(criteria)
 			if err != nil {
-				return output.PrintError("search_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "search_failed", err, nil)
 			}
 
 			if len(uids) == 0 {
-				return output.Print([]Email{})
+				return output.Print(ctx, []Email{})
 			}
 
 			// Limit results
@@ -684,13 +725,13 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := <-done; err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			// Reverse to show newest first
 			slices.Reverse(emails)
 
-			return output.Print(emails)
+			return output.Print(ctx, emails)
 		},
 	}
 
@@ -700,20 +741,35 @@ func newSearchCmd() *cobra.Command {
 	return cmd
 }
 
+// planMail records the SMTP envelope and message for --dry-run
+func planMail(ctx context.Context, addr, from string, recipients []string, msg []byte) error {
+	lines := []string{"MAIL FROM:<" + from + ">"}
+	for _, rcpt := range recipients {
+		lines = append(lines, "RCPT TO:<"+rcpt+">")
+	}
+	lines = append(lines, "DATA")
+	return dryrun.Record(ctx, dryrun.Request{
+		Protocol: "smtp",
+		Target:   addr,
+		Command:  strings.Join(lines, "\n"),
+		Body:     string(msg),
+	})
+}
+
 // checkEmailConfig validates all required email credentials are set
-func checkEmailConfig() error {
+func checkEmailConfig(ctx context.Context) error {
 	required := []string{"email_address", "email_password", "imap_server", "smtp_server"}
 	var missing []string
 
 	for _, key := range required {
-		val, _ := config.Get(key)
+		val, _ := config.Get(ctx, key)
 		if val == "" {
 			missing = append(missing, key)
 		}
 	}
 
 	if len(missing) > 0 {
-		return output.PrintError("setup_required", "Email not configured", map[string]any{
+		return output.PrintError(ctx, "setup_required", "Email not configured", map[string]any{
 			"missing":   missing,
 			"setup_cmd": "pocket setup show email",
 			"hint":      "Run 'pocket setup show email' for setup instructions",
@@ -722,25 +778,26 @@ func checkEmailConfig() error {
 	return nil
 }
 
-func connectIMAP() (*client.Client, error) {
-	if err := checkEmailConfig(); err != nil {
+func connectIMAP(ctx context.Context) (*client.Client, error) {
+	if err := checkEmailConfig(ctx); err != nil {
 		return nil, err
 	}
 
-	imapServer, _ := config.Get("imap_server")
-	imapPort, _ := config.Get("imap_port")
+	imapServer, _ := config.Get(ctx, "imap_server")
+	imapPort, _ := config.Get(ctx, "imap_port")
 	if imapPort == "" {
 		imapPort = "993"
 	}
-	emailAddr, _ := config.Get("email_address")
-	password, _ := config.Get("email_password")
+	emailAddr, _ := config.Get(ctx, "email_address")
+	password, _ := config.Get(ctx, "email_password")
 
 	addr := fmt.Sprintf("%s:%s", imapServer, imapPort)
 
 	// Connect with TLS
+	audit.Contact(ctx, "imaps", addr)
 	c, err := client.DialTLS(addr, nil)
 	if err != nil {
-		return nil, output.PrintError("connect_failed", err.Error(), map[string]any{
+		return nil, output.PrintErr(ctx, "connect_failed", err, map[string]any{
 			"server": imapServer,
 			"port":   imapPort,
 			"hint":   "Check imap_server and imap_port settings",
@@ -750,7 +807,7 @@ func connectIMAP() (*client.Client, error) {
 	// Login
 	if err := c.Login(emailAddr, password); err != nil {
 		_ = c.Logout()
-		return nil, output.PrintError("auth_failed", "Login failed - check credentials", map[string]any{
+		return nil, output.PrintError(ctx, "auth_failed", "Login failed - check credentials", map[string]any{
 			"email": emailAddr,
 			"hint":  "For Gmail, use an App Password (not your regular password). Go to: https://myaccount.google.com/apppasswords",
 		})
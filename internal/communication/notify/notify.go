@@ -12,10 +12,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // NtfyResponse represents the response from ntfy.sh
 type NtfyResponse struct {
@@ -32,6 +34,22 @@ type PushoverResponse struct {
 	Request string `json:"request"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "notify",
+		Name:        "Push Notifications",
+		Group:       "comms",
+		Description: "Send push notifications via ntfy.sh (no auth) or Pushover (auth)",
+		Keys: []registry.Key{
+			{Key: "pushover_token", Description: "Application API Token", Example: "azGDORePK8gMaC0QOYAMyEEuzJnyUi"},
+			{Key: "pushover_user", Description: "User Key", Example: "uQiRzpo4DXghDmr9QzzfQu27cmVRsG"},
+		},
+		SetupGuide:  "1. Sign up at https://pushover.net/\n2. Install Pushover app on your phone (iOS/Android) or desktop\n3. Your User Key is shown on the dashboard after login\n4. Create an Application at https://pushover.net/apps/build\n5. Copy the Application API Token\n6. Run:\n   pocket config set pushover_token <your-app-token>\n   pocket config set pushover_user <your-user-key>\n\nNote: Pushover has a one-time $5 purchase for each platform.\nAfter setup, use: pocket comms notify pushover \"Your message\"",
+		TestCommand: "pocket comms notify pushover 'Test notification from Pocket CLI'",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "notify",
@@ -65,10 +83,11 @@ Examples:
   pocket notify ntfy updates "New release" --tags "tada,release"`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			topic := args[0]
 			message := args[1]
 
-			return sendNtfy(topic, message, title, priority, tags)
+			return sendNtfy(ctx, topic, message, title, priority, tags)
 		},
 	}
 
@@ -99,11 +118,12 @@ Examples:
   pocket notify pushover "Alarm" --sound siren --priority 2`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			message := args[0]
 
-			token, err := config.MustGet("pushover_token")
+			token, err := config.MustGet(ctx, "pushover_token")
 			if err != nil {
-				return output.PrintError("config_missing", "Pushover API token not configured", map[string]any{
+				return output.PrintError(ctx, "config_missing", "Pushover API token not configured", map[string]any{
 					"key":       "pushover_token",
 					"hint":      "Run: pocket config set pushover_token <your-api-token>",
 					"get_key":   "https://pushover.net/apps/build",
@@ -111,9 +131,9 @@ Examples:
 				})
 			}
 
-			user, err := config.MustGet("pushover_user")
+			user, err := config.MustGet(ctx, "pushover_user")
 			if err != nil {
-				return output.PrintError("config_missing", "Pushover user key not configured", map[string]any{
+				return output.PrintError(ctx, "config_missing", "Pushover user key not configured", map[string]any{
 					"key":       "pushover_user",
 					"hint":      "Run: pocket config set pushover_user <your-user-key>",
 					"get_key":   "https://pushover.net/",
@@ -121,7 +141,7 @@ Examples:
 				})
 			}
 
-			return sendPushover(token, user, message, title, priority, sound)
+			return sendPushover(ctx, token, user, message, title, priority, sound)
 		},
 	}
 
@@ -132,23 +152,23 @@ Examples:
 	return cmd
 }
 
-func sendNtfy(topic, message, title string, priority int, tags string) error {
+func sendNtfy(ctx context.Context, topic, message, title string, priority int, tags string) error {
 	url := fmt.Sprintf("https://ntfy.sh/%s", topic)
 
 	// Validate priority
 	if priority < 1 || priority > 5 {
-		return output.PrintError("invalid_priority", "Priority must be between 1 and 5", map[string]any{
+		return output.PrintError(ctx, "invalid_priority", "Priority must be between 1 and 5", map[string]any{
 			"provided": priority,
 			"valid":    "1 (min), 2 (low), 3 (default), 4 (high), 5 (max)",
 		})
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBufferString(message))
 	if err != nil {
-		return output.PrintError("request_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "request_failed", err, nil)
 	}
 
 	// Set headers
@@ -164,7 +184,7 @@ func sendNtfy(topic, message, title string, priority int, tags string) error {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return output.PrintError("send_failed", err.Error(), map[string]any{
+		return output.PrintErr(ctx, "send_failed", err, map[string]any{
 			"topic": topic,
 			"hint":  "Check your internet connection",
 		})
@@ -174,7 +194,7 @@ func sendNtfy(topic, message, title string, priority int, tags string) error {
 	body, _ := io.ReadAll(resp.Body)
 
 	if resp.StatusCode != http.StatusOK {
-		return output.PrintError("send_failed", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), map[string]any{
+		return output.PrintError(ctx, "send_failed", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), map[string]any{
 			"topic":       topic,
 			"status_code": resp.StatusCode,
 		})
@@ -183,7 +203,7 @@ func sendNtfy(topic, message, title string, priority int, tags string) error {
 	var ntfyResp NtfyResponse
 	if err := json.Unmarshal(body, &ntfyResp); err != nil {
 		// Even if we can't parse the response, the notification was sent
-		return output.Print(map[string]any{
+		return output.Print(ctx, map[string]any{
 			"status":   "sent",
 			"service":  "ntfy",
 			"topic":    topic,
@@ -194,7 +214,7 @@ func sendNtfy(topic, message, title string, priority int, tags string) error {
 		})
 	}
 
-	return output.Print(map[string]any{
+	return output.Print(ctx, map[string]any{
 		"status":   "sent",
 		"service":  "ntfy",
 		"id":       ntfyResp.ID,
@@ -207,12 +227,12 @@ func sendNtfy(topic, message, title string, priority int, tags string) error {
 	})
 }
 
-func sendPushover(token, user, message, title string, priority int, sound string) error {
+func sendPushover(ctx context.Context, token, user, message, title string, priority int, sound string) error {
 	url := "https://api.pushover.net/1/messages.json"
 
 	// Validate priority
 	if priority < -2 || priority > 2 {
-		return output.PrintError("invalid_priority", "Priority must be between -2 and 2", map[string]any{
+		return output.PrintError(ctx, "invalid_priority", "Priority must be between -2 and 2", map[string]any{
 			"provided": priority,
 			"valid":    "-2 (lowest), -1 (low), 0 (normal), 1 (high), 2 (emergency)",
 		})
@@ -244,22 +264,22 @@ func sendPushover(token, user, message, title string, priority int, sound string
 	// Encode as JSON
 	jsonData, err := json.Marshal(data)
 	if err != nil {
-		return output.PrintError("encode_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "encode_failed", err, nil)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return output.PrintError("request_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "request_failed", err, nil)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return output.PrintError("send_failed", err.Error(), map[string]any{
+		return output.PrintErr(ctx, "send_failed", err, map[string]any{
 			"hint": "Check your internet connection",
 		})
 	}
@@ -270,7 +290,7 @@ func sendPushover(token, user, message, title string, priority int, sound string
 	if resp.StatusCode != http.StatusOK {
 		var errResp map[string]any
 		_ = json.Unmarshal(body, &errResp)
-		return output.PrintError("send_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), map[string]any{
+		return output.PrintError(ctx, "send_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), map[string]any{
 			"status_code": resp.StatusCode,
 			"response":    errResp,
 			"hint":        "Check your pushover_token and pushover_user configuration",
@@ -279,7 +299,7 @@ func sendPushover(token, user, message, title string, priority int, sound string
 
 	var pushResp PushoverResponse
 	if err := json.Unmarshal(body, &pushResp); err != nil {
-		return output.Print(map[string]any{
+		return output.Print(ctx, map[string]any{
 			"status":   "sent",
 			"service":  "pushover",
 			"message":  message,
@@ -289,7 +309,7 @@ func sendPushover(token, user, message, title string, priority int, sound string
 		})
 	}
 
-	return output.Print(map[string]any{
+	return output.Print(ctx, map[string]any{
 		"status":     "sent",
 		"service":    "pushover",
 		"request_id": pushResp.Request,
@@ -1,6 +1,7 @@
 package notify
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -117,12 +118,13 @@ func TestNtfyCmd_InvalidPriority(t *testing.T) {
 }
 
 func TestSendNtfy_InvalidPriority(t *testing.T) {
-	err := sendNtfy("test", "message", "", 0, "")
+	ctx := context.Background()
+	err := sendNtfy(ctx, "test", "message", "", 0, "")
 	if err == nil {
 		t.Fatal("expected error for priority < 1, got nil")
 	}
 
-	err = sendNtfy("test", "message", "", 6, "")
+	err = sendNtfy(ctx, "test", "message", "", 6, "")
 	if err == nil {
 		t.Fatal("expected error for priority > 5, got nil")
 	}
@@ -157,6 +159,7 @@ func TestSendNtfy_ErrorResponse(t *testing.T) {
 }
 
 func TestPushoverCmd_Success(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Errorf("expected POST, got %s", r.Method)
@@ -191,8 +194,8 @@ func TestPushoverCmd_Success(t *testing.T) {
 	defer srv.Close()
 
 	// sendPushover has hardcoded URL, but we can test the command setup
-	config.Set("pushover_token", "test-token")
-	config.Set("pushover_user", "test-user")
+	config.Set(ctx, "pushover_token", "test-token")
+	config.Set(ctx, "pushover_user", "test-user")
 
 	cmd := newPushoverCmd()
 	cmd.SetArgs([]string{"test message"})
@@ -201,8 +204,9 @@ func TestPushoverCmd_Success(t *testing.T) {
 }
 
 func TestPushoverCmd_NoToken(t *testing.T) {
-	config.Set("pushover_token", "")
-	config.Set("pushover_user", "test-user")
+	ctx := context.Background()
+	config.Set(ctx, "pushover_token", "")
+	config.Set(ctx, "pushover_user", "test-user")
 
 	cmd := newPushoverCmd()
 	cmd.SetArgs([]string{"test message"})
@@ -214,8 +218,9 @@ func TestPushoverCmd_NoToken(t *testing.T) {
 }
 
 func TestPushoverCmd_NoUser(t *testing.T) {
-	config.Set("pushover_token", "test-token")
-	config.Set("pushover_user", "")
+	ctx := context.Background()
+	config.Set(ctx, "pushover_token", "test-token")
+	config.Set(ctx, "pushover_user", "")
 
 	cmd := newPushoverCmd()
 	cmd.SetArgs([]string{"test message"})
@@ -227,6 +232,7 @@ func TestPushoverCmd_NoUser(t *testing.T) {
 }
 
 func TestPushoverCmd_WithOptions(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var body map[string]string
 		json.NewDecoder(r.Body).Decode(&body)
@@ -248,8 +254,8 @@ func TestPushoverCmd_WithOptions(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	config.Set("pushover_token", "test-token")
-	config.Set("pushover_user", "test-user")
+	config.Set(ctx, "pushover_token", "test-token")
+	config.Set(ctx, "pushover_user", "test-user")
 
 	cmd := newPushoverCmd()
 	cmd.SetArgs([]string{"test", "--title", "Alert", "--priority", "1", "--sound", "siren"})
@@ -258,12 +264,13 @@ func TestPushoverCmd_WithOptions(t *testing.T) {
 }
 
 func TestSendPushover_InvalidPriority(t *testing.T) {
-	err := sendPushover("token", "user", "msg", "", -3, "")
+	ctx := context.Background()
+	err := sendPushover(ctx, "token", "user", "msg", "", -3, "")
 	if err == nil {
 		t.Fatal("expected error for priority < -2, got nil")
 	}
 
-	err = sendPushover("token", "user", "msg", "", 3, "")
+	err = sendPushover(ctx, "token", "user", "msg", "", 3, "")
 	if err == nil {
 		t.Fatal("expected error for priority > 2, got nil")
 	}
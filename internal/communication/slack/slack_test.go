@@ -1,6 +1,7 @@
 package slack
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -39,6 +40,7 @@ func TestNewCmd(t *testing.T) {
 }
 
 func TestChannelsCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("Authorization") != "Bearer test-token" {
 			t.Errorf("expected Authorization 'Bearer test-token', got %q", r.Header.Get("Authorization"))
@@ -71,7 +73,7 @@ func TestChannelsCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("slack_token", "test-token")
+	config.Set(ctx, "slack_token", "test-token")
 
 	cmd := newChannelsCmd()
 	cmd.SetArgs([]string{"--limit", "50"})
@@ -83,7 +85,8 @@ func TestChannelsCmd(t *testing.T) {
 }
 
 func TestChannelsCmd_NoToken(t *testing.T) {
-	config.Set("slack_token", "")
+	ctx := context.Background()
+	config.Set(ctx, "slack_token", "")
 
 	cmd := newChannelsCmd()
 	err := cmd.Execute()
@@ -93,6 +96,7 @@ func TestChannelsCmd_NoToken(t *testing.T) {
 }
 
 func TestMessagesCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
 		if strings.Contains(path, "conversations.history") {
@@ -126,7 +130,7 @@ func TestMessagesCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("slack_token", "test-token")
+	config.Set(ctx, "slack_token", "test-token")
 
 	cmd := newMessagesCmd()
 	cmd.SetArgs([]string{"C123456", "--limit", "10"})
@@ -138,6 +142,7 @@ func TestMessagesCmd(t *testing.T) {
 }
 
 func TestSendCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Errorf("expected POST, got %s", r.Method)
@@ -172,7 +177,7 @@ func TestSendCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("slack_token", "test-token")
+	config.Set(ctx, "slack_token", "test-token")
 
 	cmd := newSendCmd()
 	cmd.SetArgs([]string{"test message", "--channel", "C123456"})
@@ -184,6 +189,7 @@ func TestSendCmd(t *testing.T) {
 }
 
 func TestSendCmd_WithThread(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var body map[string]string
 		json.NewDecoder(r.Body).Decode(&body)
@@ -206,7 +212,7 @@ func TestSendCmd_WithThread(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("slack_token", "test-token")
+	config.Set(ctx, "slack_token", "test-token")
 
 	cmd := newSendCmd()
 	cmd.SetArgs([]string{"reply", "--channel", "C123456", "--thread", "1234567890.111111"})
@@ -218,6 +224,7 @@ func TestSendCmd_WithThread(t *testing.T) {
 }
 
 func TestUsersCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.Contains(r.URL.Path, "users.list") {
 			t.Errorf("expected path to contain 'users.list', got %q", r.URL.Path)
@@ -251,7 +258,7 @@ func TestUsersCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("slack_token", "test-token")
+	config.Set(ctx, "slack_token", "test-token")
 
 	cmd := newUsersCmd()
 	cmd.SetArgs([]string{"--limit", "50"})
@@ -263,6 +270,7 @@ func TestUsersCmd(t *testing.T) {
 }
 
 func TestDMCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		path := r.URL.Path
 		if strings.Contains(path, "conversations.open") {
@@ -287,7 +295,7 @@ func TestDMCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("slack_token", "test-token")
+	config.Set(ctx, "slack_token", "test-token")
 
 	cmd := newDMCmd()
 	cmd.SetArgs([]string{"U123456", "DM message"})
@@ -299,6 +307,7 @@ func TestDMCmd(t *testing.T) {
 }
 
 func TestSearchCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.Contains(r.URL.Path, "search.messages") {
 			t.Errorf("expected path to contain 'search.messages', got %q", r.URL.Path)
@@ -333,7 +342,7 @@ func TestSearchCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("slack_token", "test-token")
+	config.Set(ctx, "slack_token", "test-token")
 
 	cmd := newSearchCmd()
 	cmd.SetArgs([]string{"test query", "--limit", "10"})
@@ -345,6 +354,7 @@ func TestSearchCmd(t *testing.T) {
 }
 
 func TestSlackGet_RateLimit(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Retry-After", "30")
 		w.WriteHeader(http.StatusTooManyRequests)
@@ -358,13 +368,14 @@ func TestSlackGet_RateLimit(t *testing.T) {
 
 	params := url.Values{}
 	var result map[string]any
-	err := slackGet("test-token", "test.method", params, &result)
+	err := slackGet(ctx, "test-token", "test.method", params, &result)
 	if err == nil {
 		t.Fatal("expected rate limit error, got nil")
 	}
 }
 
 func TestSlackPost_Error(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -380,7 +391,7 @@ func TestSlackPost_Error(t *testing.T) {
 
 	body := map[string]string{"test": "data"}
 	var result map[string]any
-	err := slackPost("test-token", "test.method", body, &result)
+	err := slackPost(ctx, "test-token", "test.method", body, &result)
 	if err == nil {
 		t.Fatal("expected error for 400 response, got nil")
 	}
@@ -12,12 +12,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://api.telegram.org/bot"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // BotInfo is LLM-friendly bot information
 type BotInfo struct {
@@ -129,6 +131,22 @@ type apiUpdate struct {
 	Message  *apiMessage `json:"message,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "telegram",
+		Name:        "Telegram",
+		Group:       "comms",
+		Description: "Chats, messages, and forwarding via Telegram bot",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "telegram_token", Description: "Bot token from @BotFather", Required: true, Example: "123456:ABC-DEF1234ghIkl-zyx57W2v1u123ew11"},
+		},
+		SetupGuide:  "1. Message @BotFather on Telegram\n2. Send /newbot and follow instructions\n3. Copy the token provided\n4. Run: pocket config set telegram_token <token>",
+		TestCommand: "pocket comms telegram chats",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "telegram",
@@ -150,24 +168,25 @@ func newMeCmd() *cobra.Command {
 		Use:   "me",
 		Short: "Get bot info",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
 
-			resp, err := callAPI(token, "getMe", nil)
+			resp, err := callAPI(ctx, token, "getMe", nil)
 			if err != nil {
 				return err
 			}
 
 			var user apiUser
 			if err := json.Unmarshal(resp.Result, &user); err != nil {
-				return output.PrintError("parse_failed", "Failed to parse bot info", map[string]any{
+				return output.PrintError(ctx, "parse_failed", "Failed to parse bot info", map[string]any{
 					"error": err.Error(),
 				})
 			}
 
-			return output.Print(BotInfo{
+			return output.Print(ctx, BotInfo{
 				ID:                      user.ID,
 				IsBot:                   user.IsBot,
 				FirstName:               user.FirstName,
@@ -190,7 +209,8 @@ func newUpdatesCmd() *cobra.Command {
 		Use:   "updates",
 		Short: "Get recent updates/messages sent to the bot",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -202,14 +222,14 @@ func newUpdatesCmd() *cobra.Command {
 				params["offset"] = offset
 			}
 
-			resp, err := callAPI(token, "getUpdates", params)
+			resp, err := callAPI(ctx, token, "getUpdates", params)
 			if err != nil {
 				return err
 			}
 
 			var apiUpdates []apiUpdate
 			if err := json.Unmarshal(resp.Result, &apiUpdates); err != nil {
-				return output.PrintError("parse_failed", "Failed to parse updates", map[string]any{
+				return output.PrintError(ctx, "parse_failed", "Failed to parse updates", map[string]any{
 					"error": err.Error(),
 				})
 			}
@@ -225,7 +245,7 @@ func newUpdatesCmd() *cobra.Command {
 				updates = append(updates, update)
 			}
 
-			return output.Print(updates)
+			return output.Print(ctx, updates)
 		},
 	}
 
@@ -245,13 +265,14 @@ func newSendCmd() *cobra.Command {
 		Short: "Send a message to a chat",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
 
 			if chatID == "" {
-				return output.PrintError("missing_chat", "Chat ID is required", map[string]any{
+				return output.PrintError(ctx, "missing_chat", "Chat ID is required", map[string]any{
 					"hint": "Use --chat flag to specify the chat ID. Get chat IDs from 'pocket telegram chats'",
 				})
 			}
@@ -268,19 +289,19 @@ func newSendCmd() *cobra.Command {
 				params["disable_notification"] = true
 			}
 
-			resp, err := callAPI(token, "sendMessage", params)
+			resp, err := callAPI(ctx, token, "sendMessage", params)
 			if err != nil {
 				return err
 			}
 
 			var msg apiMessage
 			if err := json.Unmarshal(resp.Result, &msg); err != nil {
-				return output.PrintError("parse_failed", "Failed to parse sent message", map[string]any{
+				return output.PrintError(ctx, "parse_failed", "Failed to parse sent message", map[string]any{
 					"error": err.Error(),
 				})
 			}
 
-			return output.Print(SendResult{
+			return output.Print(ctx, SendResult{
 				MessageID: msg.MessageID,
 				ChatID:    msg.Chat.ID,
 				Date:      formatUnixTime(msg.Date),
@@ -305,7 +326,8 @@ func newChatsCmd() *cobra.Command {
 		Short: "List recent chats from updates",
 		Long:  "Lists unique chats that have sent messages to the bot. Uses getUpdates to extract chat information.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -314,14 +336,14 @@ func newChatsCmd() *cobra.Command {
 				"limit": 100, // Get max updates to find more chats
 			}
 
-			resp, err := callAPI(token, "getUpdates", params)
+			resp, err := callAPI(ctx, token, "getUpdates", params)
 			if err != nil {
 				return err
 			}
 
 			var apiUpdates []apiUpdate
 			if err := json.Unmarshal(resp.Result, &apiUpdates); err != nil {
-				return output.PrintError("parse_failed", "Failed to parse updates", map[string]any{
+				return output.PrintError(ctx, "parse_failed", "Failed to parse updates", map[string]any{
 					"error": err.Error(),
 				})
 			}
@@ -348,7 +370,7 @@ func newChatsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(chats)
+			return output.Print(ctx, chats)
 		},
 	}
 
@@ -365,7 +387,8 @@ func newForwardCmd() *cobra.Command {
 		Short: "Forward a message from one chat to another",
 		Args:  cobra.ExactArgs(3),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -373,7 +396,7 @@ func newForwardCmd() *cobra.Command {
 			fromChatID := args[0]
 			messageID, err := strconv.ParseInt(args[1], 10, 64)
 			if err != nil {
-				return output.PrintError("invalid_message_id", "Message ID must be a number", map[string]any{
+				return output.PrintError(ctx, "invalid_message_id", "Message ID must be a number", map[string]any{
 					"provided": args[1],
 				})
 			}
@@ -389,14 +412,14 @@ func newForwardCmd() *cobra.Command {
 				params["disable_notification"] = true
 			}
 
-			resp, err := callAPI(token, "forwardMessage", params)
+			resp, err := callAPI(ctx, token, "forwardMessage", params)
 			if err != nil {
 				return err
 			}
 
 			var msg apiMessage
 			if err := json.Unmarshal(resp.Result, &msg); err != nil {
-				return output.PrintError("parse_failed", "Failed to parse forwarded message", map[string]any{
+				return output.PrintError(ctx, "parse_failed", "Failed to parse forwarded message", map[string]any{
 					"error": err.Error(),
 				})
 			}
@@ -405,7 +428,7 @@ func newForwardCmd() *cobra.Command {
 			fromID, _ := strconv.ParseInt(fromChatID, 10, 64)
 			toID, _ := strconv.ParseInt(toChatID, 10, 64)
 
-			return output.Print(ForwardResult{
+			return output.Print(ctx, ForwardResult{
 				MessageID:         msg.MessageID,
 				FromChatID:        fromID,
 				ToChatID:          toID,
@@ -421,10 +444,10 @@ func newForwardCmd() *cobra.Command {
 }
 
 // getToken retrieves the Telegram token from config
-func getToken() (string, error) {
-	token, err := config.MustGet("telegram_token")
+func getToken(ctx context.Context) (string, error) {
+	token, err := config.MustGet(ctx, "telegram_token")
 	if err != nil {
-		return "", output.PrintError("token_missing", "Telegram bot token not configured", map[string]any{
+		return "", output.PrintError(ctx, "token_missing", "Telegram bot token not configured", map[string]any{
 			"setup_cmd": "pocket config set telegram_token <your-bot-token>",
 			"hint":      "Create a bot via @BotFather on Telegram to get a token",
 			"docs":      "https://core.telegram.org/bots#how-do-i-create-a-bot",
@@ -434,8 +457,8 @@ func getToken() (string, error) {
 }
 
 // callAPI makes a request to the Telegram Bot API
-func callAPI(token, method string, params map[string]any) (*apiResponse, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func callAPI(ctx context.Context, token, method string, params map[string]any) (*apiResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	url := baseURL + token + "/" + method
@@ -446,7 +469,7 @@ func callAPI(token, method string, params map[string]any) (*apiResponse, error)
 	if params != nil {
 		reqBody, err = json.Marshal(params)
 		if err != nil {
-			return nil, output.PrintError("request_failed", "Failed to encode request", map[string]any{
+			return nil, output.PrintError(ctx, "request_failed", "Failed to encode request", map[string]any{
 				"error": err.Error(),
 			})
 		}
@@ -454,7 +477,7 @@ func callAPI(token, method string, params map[string]any) (*apiResponse, error)
 
 	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(reqBody))
 	if err != nil {
-		return nil, output.PrintError("request_failed", "Failed to create request", map[string]any{
+		return nil, output.PrintError(ctx, "request_failed", "Failed to create request", map[string]any{
 			"error": err.Error(),
 		})
 	}
@@ -463,7 +486,7 @@ func callAPI(token, method string, params map[string]any) (*apiResponse, error)
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("request_failed", "Failed to connect to Telegram API", map[string]any{
+		return nil, output.PrintError(ctx, "request_failed", "Failed to connect to Telegram API", map[string]any{
 			"error": err.Error(),
 			"hint":  "Check your internet connection",
 		})
@@ -472,7 +495,7 @@ func callAPI(token, method string, params map[string]any) (*apiResponse, error)
 
 	var apiResp apiResponse
 	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
-		return nil, output.PrintError("parse_failed", "Failed to parse API response", map[string]any{
+		return nil, output.PrintError(ctx, "parse_failed", "Failed to parse API response", map[string]any{
 			"error":       err.Error(),
 			"status_code": resp.StatusCode,
 		})
@@ -481,7 +504,7 @@ func callAPI(token, method string, params map[string]any) (*apiResponse, error)
 	if !apiResp.OK {
 		// Handle rate limiting
 		if apiResp.ErrorCode == 429 && apiResp.Parameters != nil && apiResp.Parameters.RetryAfter > 0 {
-			return nil, output.PrintError("rate_limited", "Rate limited by Telegram", map[string]any{
+			return nil, output.PrintError(ctx, "rate_limited", "Rate limited by Telegram", map[string]any{
 				"retry_after_seconds": apiResp.Parameters.RetryAfter,
 				"hint":                fmt.Sprintf("Wait %d seconds before retrying", apiResp.Parameters.RetryAfter),
 			})
@@ -489,7 +512,7 @@ func callAPI(token, method string, params map[string]any) (*apiResponse, error)
 
 		// Handle unauthorized (bad token)
 		if apiResp.ErrorCode == 401 {
-			return nil, output.PrintError("unauthorized", "Invalid or expired bot token", map[string]any{
+			return nil, output.PrintError(ctx, "unauthorized", "Invalid or expired bot token", map[string]any{
 				"description": apiResp.Description,
 				"hint":        "Check your telegram_token is correct. Get a new token from @BotFather if needed.",
 			})
@@ -498,13 +521,13 @@ func callAPI(token, method string, params map[string]any) (*apiResponse, error)
 		// Handle chat not found
 		if apiResp.ErrorCode == 400 && (apiResp.Description == "Bad Request: chat not found" ||
 			apiResp.Description == "Bad Request: CHAT_ID_INVALID") {
-			return nil, output.PrintError("chat_not_found", "Chat not found", map[string]any{
+			return nil, output.PrintError(ctx, "chat_not_found", "Chat not found", map[string]any{
 				"description": apiResp.Description,
 				"hint":        "The chat ID may be wrong, or the bot hasn't been added to this chat yet",
 			})
 		}
 
-		return nil, output.PrintError("api_error", apiResp.Description, map[string]any{
+		return nil, output.PrintError(ctx, "api_error", apiResp.Description, map[string]any{
 			"error_code": apiResp.ErrorCode,
 		})
 	}
@@ -1,6 +1,7 @@
 package telegram
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -44,6 +45,7 @@ func TestNewCmd(t *testing.T) {
 }
 
 func TestMeCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Errorf("expected POST, got %s", r.Method)
@@ -73,7 +75,7 @@ func TestMeCmd(t *testing.T) {
 	baseURL = srv.URL + "/"
 	defer func() { baseURL = oldURL }()
 
-	config.Set("telegram_token", "test-token")
+	config.Set(ctx, "telegram_token", "test-token")
 
 	cmd := newMeCmd()
 	err := cmd.Execute()
@@ -83,7 +85,8 @@ func TestMeCmd(t *testing.T) {
 }
 
 func TestMeCmd_NoToken(t *testing.T) {
-	config.Set("telegram_token", "")
+	ctx := context.Background()
+	config.Set(ctx, "telegram_token", "")
 
 	cmd := newMeCmd()
 	err := cmd.Execute()
@@ -93,6 +96,7 @@ func TestMeCmd_NoToken(t *testing.T) {
 }
 
 func TestUpdatesCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.Contains(r.URL.Path, "/getUpdates") {
 			t.Errorf("expected path to contain '/getUpdates', got %q", r.URL.Path)
@@ -121,7 +125,7 @@ func TestUpdatesCmd(t *testing.T) {
 	baseURL = srv.URL + "/"
 	defer func() { baseURL = oldURL }()
 
-	config.Set("telegram_token", "test-token")
+	config.Set(ctx, "telegram_token", "test-token")
 
 	cmd := newUpdatesCmd()
 	cmd.SetArgs([]string{"--limit", "50"})
@@ -133,6 +137,7 @@ func TestUpdatesCmd(t *testing.T) {
 }
 
 func TestUpdatesCmd_WithOffset(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var body map[string]any
 		json.NewDecoder(r.Body).Decode(&body)
@@ -153,7 +158,7 @@ func TestUpdatesCmd_WithOffset(t *testing.T) {
 	baseURL = srv.URL + "/"
 	defer func() { baseURL = oldURL }()
 
-	config.Set("telegram_token", "test-token")
+	config.Set(ctx, "telegram_token", "test-token")
 
 	cmd := newUpdatesCmd()
 	cmd.SetArgs([]string{"--offset", "100"})
@@ -165,6 +170,7 @@ func TestUpdatesCmd_WithOffset(t *testing.T) {
 }
 
 func TestSendCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Errorf("expected POST, got %s", r.Method)
@@ -202,7 +208,7 @@ func TestSendCmd(t *testing.T) {
 	baseURL = srv.URL + "/"
 	defer func() { baseURL = oldURL }()
 
-	config.Set("telegram_token", "test-token")
+	config.Set(ctx, "telegram_token", "test-token")
 
 	cmd := newSendCmd()
 	cmd.SetArgs([]string{"test message", "--chat", "123456"})
@@ -214,6 +220,7 @@ func TestSendCmd(t *testing.T) {
 }
 
 func TestSendCmd_WithParseMode(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var body map[string]any
 		json.NewDecoder(r.Body).Decode(&body)
@@ -239,7 +246,7 @@ func TestSendCmd_WithParseMode(t *testing.T) {
 	baseURL = srv.URL + "/"
 	defer func() { baseURL = oldURL }()
 
-	config.Set("telegram_token", "test-token")
+	config.Set(ctx, "telegram_token", "test-token")
 
 	cmd := newSendCmd()
 	cmd.SetArgs([]string{"*bold*", "--chat", "123456", "--parse", "Markdown"})
@@ -251,6 +258,7 @@ func TestSendCmd_WithParseMode(t *testing.T) {
 }
 
 func TestSendCmd_Silent(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var body map[string]any
 		json.NewDecoder(r.Body).Decode(&body)
@@ -276,7 +284,7 @@ func TestSendCmd_Silent(t *testing.T) {
 	baseURL = srv.URL + "/"
 	defer func() { baseURL = oldURL }()
 
-	config.Set("telegram_token", "test-token")
+	config.Set(ctx, "telegram_token", "test-token")
 
 	cmd := newSendCmd()
 	cmd.SetArgs([]string{"silent", "--chat", "123456", "--silent"})
@@ -288,7 +296,8 @@ func TestSendCmd_Silent(t *testing.T) {
 }
 
 func TestSendCmd_NoChat(t *testing.T) {
-	config.Set("telegram_token", "test-token")
+	ctx := context.Background()
+	config.Set(ctx, "telegram_token", "test-token")
 
 	cmd := newSendCmd()
 	cmd.SetArgs([]string{"test message"})
@@ -300,6 +309,7 @@ func TestSendCmd_NoChat(t *testing.T) {
 }
 
 func TestChatsCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := apiResponse{
 			OK: true,
@@ -341,7 +351,7 @@ func TestChatsCmd(t *testing.T) {
 	baseURL = srv.URL + "/"
 	defer func() { baseURL = oldURL }()
 
-	config.Set("telegram_token", "test-token")
+	config.Set(ctx, "telegram_token", "test-token")
 
 	cmd := newChatsCmd()
 	cmd.SetArgs([]string{"--limit", "10"})
@@ -353,6 +363,7 @@ func TestChatsCmd(t *testing.T) {
 }
 
 func TestForwardCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.Contains(r.URL.Path, "/forwardMessage") {
 			t.Errorf("expected path to contain '/forwardMessage', got %q", r.URL.Path)
@@ -389,7 +400,7 @@ func TestForwardCmd(t *testing.T) {
 	baseURL = srv.URL + "/"
 	defer func() { baseURL = oldURL }()
 
-	config.Set("telegram_token", "test-token")
+	config.Set(ctx, "telegram_token", "test-token")
 
 	cmd := newForwardCmd()
 	cmd.SetArgs([]string{"111", "999", "222"})
@@ -401,7 +412,8 @@ func TestForwardCmd(t *testing.T) {
 }
 
 func TestForwardCmd_InvalidMessageID(t *testing.T) {
-	config.Set("telegram_token", "test-token")
+	ctx := context.Background()
+	config.Set(ctx, "telegram_token", "test-token")
 
 	cmd := newForwardCmd()
 	cmd.SetArgs([]string{"111", "invalid", "222"})
@@ -413,6 +425,7 @@ func TestForwardCmd_InvalidMessageID(t *testing.T) {
 }
 
 func TestCallAPI_RateLimit(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := apiResponse{
 			OK:          false,
@@ -428,13 +441,14 @@ func TestCallAPI_RateLimit(t *testing.T) {
 	baseURL = srv.URL + "/"
 	defer func() { baseURL = oldURL }()
 
-	_, err := callAPI("test-token", "getMe", nil)
+	_, err := callAPI(ctx, "test-token", "getMe", nil)
 	if err == nil {
 		t.Fatal("expected rate limit error, got nil")
 	}
 }
 
 func TestCallAPI_Unauthorized(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := apiResponse{
 			OK:          false,
@@ -449,13 +463,14 @@ func TestCallAPI_Unauthorized(t *testing.T) {
 	baseURL = srv.URL + "/"
 	defer func() { baseURL = oldURL }()
 
-	_, err := callAPI("bad-token", "getMe", nil)
+	_, err := callAPI(ctx, "bad-token", "getMe", nil)
 	if err == nil {
 		t.Fatal("expected unauthorized error, got nil")
 	}
 }
 
 func TestCallAPI_ChatNotFound(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := apiResponse{
 			OK:          false,
@@ -470,7 +485,7 @@ func TestCallAPI_ChatNotFound(t *testing.T) {
 	baseURL = srv.URL + "/"
 	defer func() { baseURL = oldURL }()
 
-	_, err := callAPI("test-token", "sendMessage", map[string]any{"chat_id": "999"})
+	_, err := callAPI(ctx, "test-token", "sendMessage", map[string]any{"chat_id": "999"})
 	if err == nil {
 		t.Fatal("expected chat not found error, got nil")
 	}
@@ -14,10 +14,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 var baseURL = "https://api.twilio.com/2010-04-01/Accounts"
 
@@ -101,6 +103,24 @@ type twilioAPIError struct {
 	Status   int    `json:"status"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "twilio",
+		Name:        "Twilio (SMS)",
+		Group:       "comms",
+		Description: "Send and manage SMS messages via Twilio",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "twilio_sid", Description: "Account SID", Required: true, Example: "ACxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx"},
+			{Key: "twilio_token", Description: "Auth Token", Required: true},
+			{Key: "twilio_phone", Description: "Your Twilio phone number", Required: true, Example: "+15551234567"},
+		},
+		SetupGuide:  "1. Sign up at https://www.twilio.com/try-twilio\n2. Go to Console Dashboard: https://console.twilio.com/\n3. Copy your Account SID and Auth Token from the dashboard\n4. Get a phone number from the Phone Numbers section\n5. Run these commands:\n   pocket config set twilio_sid <your-account-sid>\n   pocket config set twilio_token <your-auth-token>\n   pocket config set twilio_phone <your-twilio-phone-number>\n\nNote: Phone numbers must be in E.164 format (e.g., +15551234567)\nFree trial accounts can only send to verified phone numbers.",
+		TestCommand: "pocket comms twilio account",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "twilio",
@@ -125,7 +145,8 @@ func newSendCmd() *cobra.Command {
 		Short: "Send an SMS message",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			sid, token, phone, err := getTwilioConfig()
+			ctx := cmd.Context()
+			sid, token, phone, err := getTwilioConfig(ctx)
 			if err != nil {
 				return err
 			}
@@ -139,13 +160,13 @@ func newSendCmd() *cobra.Command {
 			data.Set("Body", body)
 
 			// Make API request
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			apiURL := fmt.Sprintf("%s/%s/Messages.json", baseURL, sid)
 			req, err := http.NewRequestWithContext(ctx, "POST", apiURL, strings.NewReader(data.Encode()))
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -153,29 +174,29 @@ func newSendCmd() *cobra.Command {
 
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
 			respBody, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("read_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "read_failed", err, nil)
 			}
 
 			if resp.StatusCode >= 400 {
 				var apiErr twilioAPIError
 				if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Message != "" {
-					return output.PrintError("twilio_error", apiErr.Message, map[string]any{
+					return output.PrintError(ctx, "twilio_error", apiErr.Message, map[string]any{
 						"code":      apiErr.Code,
 						"more_info": apiErr.MoreInfo,
 					})
 				}
-				return output.PrintError("api_error", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(respBody)), nil)
+				return output.PrintError(ctx, "api_error", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(respBody)), nil)
 			}
 
 			var msg twilioAPIMessage
 			if err := json.Unmarshal(respBody, &msg); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			result := SendResult{
@@ -187,7 +208,7 @@ func newSendCmd() *cobra.Command {
 				DateSent: formatDate(msg.DateCreated),
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -206,7 +227,8 @@ func newMessagesCmd() *cobra.Command {
 		Use:   "messages",
 		Short: "List recent messages",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			sid, token, _, err := getTwilioConfig()
+			ctx := cmd.Context()
+			sid, token, _, err := getTwilioConfig(ctx)
 			if err != nil {
 				return err
 			}
@@ -216,47 +238,47 @@ func newMessagesCmd() *cobra.Command {
 			if direction != "" {
 				// Twilio uses "inbound" and "outbound-api" / "outbound-call" / "outbound-reply"
 				if direction == "inbound" {
-					apiURL += "&To=" + url.QueryEscape(getConfigPhone())
+					apiURL += "&To=" + url.QueryEscape(getConfigPhone(ctx))
 				} else if direction == "outbound" {
-					apiURL += "&From=" + url.QueryEscape(getConfigPhone())
+					apiURL += "&From=" + url.QueryEscape(getConfigPhone(ctx))
 				}
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			req, err := http.NewRequestWithContext(ctx, "GET", apiURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			req.Header.Set("Authorization", "Basic "+basicAuth(sid, token))
 
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
 			body, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("read_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "read_failed", err, nil)
 			}
 
 			if resp.StatusCode >= 400 {
 				var apiErr twilioAPIError
 				if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
-					return output.PrintError("twilio_error", apiErr.Message, map[string]any{
+					return output.PrintError(ctx, "twilio_error", apiErr.Message, map[string]any{
 						"code":      apiErr.Code,
 						"more_info": apiErr.MoreInfo,
 					})
 				}
-				return output.PrintError("api_error", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), nil)
+				return output.PrintError(ctx, "api_error", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), nil)
 			}
 
 			var apiResp twilioAPIMessagesResponse
 			if err := json.Unmarshal(body, &apiResp); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			messages := make([]Message, 0, len(apiResp.Messages))
@@ -285,7 +307,7 @@ func newMessagesCmd() *cobra.Command {
 				messages = append(messages, msg)
 			}
 
-			return output.Print(messages)
+			return output.Print(ctx, messages)
 		},
 	}
 
@@ -301,49 +323,50 @@ func newMessageCmd() *cobra.Command {
 		Short: "Get message details by SID",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			sid, token, _, err := getTwilioConfig()
+			ctx := cmd.Context()
+			sid, token, _, err := getTwilioConfig(ctx)
 			if err != nil {
 				return err
 			}
 
 			messageSID := args[0]
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			apiURL := fmt.Sprintf("%s/%s/Messages/%s.json", baseURL, sid, messageSID)
 			req, err := http.NewRequestWithContext(ctx, "GET", apiURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			req.Header.Set("Authorization", "Basic "+basicAuth(sid, token))
 
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
 			body, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("read_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "read_failed", err, nil)
 			}
 
 			if resp.StatusCode >= 400 {
 				var apiErr twilioAPIError
 				if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
-					return output.PrintError("twilio_error", apiErr.Message, map[string]any{
+					return output.PrintError(ctx, "twilio_error", apiErr.Message, map[string]any{
 						"code":      apiErr.Code,
 						"more_info": apiErr.MoreInfo,
 					})
 				}
-				return output.PrintError("api_error", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), nil)
+				return output.PrintError(ctx, "api_error", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), nil)
 			}
 
 			var m twilioAPIMessage
 			if err := json.Unmarshal(body, &m); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			msg := Message{
@@ -367,7 +390,7 @@ func newMessageCmd() *cobra.Command {
 				msg.ErrorMsg = *m.ErrorMessage
 			}
 
-			return output.Print(msg)
+			return output.Print(ctx, msg)
 		},
 	}
 
@@ -379,47 +402,48 @@ func newAccountCmd() *cobra.Command {
 		Use:   "account",
 		Short: "Get account information",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			sid, token, phone, err := getTwilioConfig()
+			ctx := cmd.Context()
+			sid, token, phone, err := getTwilioConfig(ctx)
 			if err != nil {
 				return err
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			apiURL := fmt.Sprintf("%s/%s.json", baseURL, sid)
 			req, err := http.NewRequestWithContext(ctx, "GET", apiURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			req.Header.Set("Authorization", "Basic "+basicAuth(sid, token))
 
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
 			body, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("read_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "read_failed", err, nil)
 			}
 
 			if resp.StatusCode >= 400 {
 				var apiErr twilioAPIError
 				if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
-					return output.PrintError("twilio_error", apiErr.Message, map[string]any{
+					return output.PrintError(ctx, "twilio_error", apiErr.Message, map[string]any{
 						"code":      apiErr.Code,
 						"more_info": apiErr.MoreInfo,
 					})
 				}
-				return output.PrintError("api_error", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), nil)
+				return output.PrintError(ctx, "api_error", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), nil)
 			}
 
 			var apiAccount twilioAPIAccount
 			if err := json.Unmarshal(body, &apiAccount); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			result := map[string]any{
@@ -431,7 +455,7 @@ func newAccountCmd() *cobra.Command {
 				"twilio_phone":  phone,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -439,10 +463,10 @@ func newAccountCmd() *cobra.Command {
 }
 
 // getTwilioConfig retrieves and validates Twilio credentials
-func getTwilioConfig() (sid, token, phone string, err error) {
-	sid, _ = config.Get("twilio_sid")
-	token, _ = config.Get("twilio_token")
-	phone, _ = config.Get("twilio_phone")
+func getTwilioConfig(ctx context.Context) (sid, token, phone string, err error) {
+	sid, _ = config.Get(ctx, "twilio_sid")
+	token, _ = config.Get(ctx, "twilio_token")
+	phone, _ = config.Get(ctx, "twilio_phone")
 
 	var missing []string
 	if sid == "" {
@@ -456,7 +480,7 @@ func getTwilioConfig() (sid, token, phone string, err error) {
 	}
 
 	if len(missing) > 0 {
-		return "", "", "", output.PrintError("setup_required", "Twilio not configured", map[string]any{
+		return "", "", "", output.PrintError(ctx, "setup_required", "Twilio not configured", map[string]any{
 			"missing":   missing,
 			"setup_cmd": "pocket setup show twilio",
 			"hint":      "Run 'pocket setup show twilio' for setup instructions",
@@ -467,8 +491,8 @@ func getTwilioConfig() (sid, token, phone string, err error) {
 }
 
 // getConfigPhone returns the configured Twilio phone number (for filtering)
-func getConfigPhone() string {
-	phone, _ := config.Get("twilio_phone")
+func getConfigPhone(ctx context.Context) string {
+	phone, _ := config.Get(ctx, "twilio_phone")
 	return phone
 }
 
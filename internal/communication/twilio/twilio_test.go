@@ -1,6 +1,7 @@
 package twilio
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -44,6 +45,7 @@ func TestNewCmd(t *testing.T) {
 }
 
 func TestSendCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Errorf("expected POST, got %s", r.Method)
@@ -86,9 +88,9 @@ func TestSendCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("twilio_sid", "test-sid")
-	config.Set("twilio_token", "test-token")
-	config.Set("twilio_phone", "+15551234567")
+	config.Set(ctx, "twilio_sid", "test-sid")
+	config.Set(ctx, "twilio_token", "test-token")
+	config.Set(ctx, "twilio_phone", "+15551234567")
 
 	cmd := newSendCmd()
 	cmd.SetArgs([]string{"test message", "--to", "+15559876543"})
@@ -100,9 +102,10 @@ func TestSendCmd(t *testing.T) {
 }
 
 func TestSendCmd_NoConfig(t *testing.T) {
-	config.Set("twilio_sid", "")
-	config.Set("twilio_token", "")
-	config.Set("twilio_phone", "")
+	ctx := context.Background()
+	config.Set(ctx, "twilio_sid", "")
+	config.Set(ctx, "twilio_token", "")
+	config.Set(ctx, "twilio_phone", "")
 
 	cmd := newSendCmd()
 	cmd.SetArgs([]string{"test", "--to", "+15551234567"})
@@ -114,6 +117,7 @@ func TestSendCmd_NoConfig(t *testing.T) {
 }
 
 func TestSendCmd_ErrorResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		resp := twilioAPIError{
@@ -130,9 +134,9 @@ func TestSendCmd_ErrorResponse(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("twilio_sid", "test-sid")
-	config.Set("twilio_token", "test-token")
-	config.Set("twilio_phone", "+15551234567")
+	config.Set(ctx, "twilio_sid", "test-sid")
+	config.Set(ctx, "twilio_token", "test-token")
+	config.Set(ctx, "twilio_phone", "+15551234567")
 
 	cmd := newSendCmd()
 	cmd.SetArgs([]string{"test", "--to", "invalid"})
@@ -144,6 +148,7 @@ func TestSendCmd_ErrorResponse(t *testing.T) {
 }
 
 func TestMessagesCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "GET" {
 			t.Errorf("expected GET, got %s", r.Method)
@@ -176,9 +181,9 @@ func TestMessagesCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("twilio_sid", "test-sid")
-	config.Set("twilio_token", "test-token")
-	config.Set("twilio_phone", "+15551234567")
+	config.Set(ctx, "twilio_sid", "test-sid")
+	config.Set(ctx, "twilio_token", "test-token")
+	config.Set(ctx, "twilio_phone", "+15551234567")
 
 	cmd := newMessagesCmd()
 	cmd.SetArgs([]string{"--limit", "20"})
@@ -190,6 +195,7 @@ func TestMessagesCmd(t *testing.T) {
 }
 
 func TestMessagesCmd_WithDirection(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		query := r.URL.RawQuery
 		if !strings.Contains(query, "From=") && !strings.Contains(query, "To=") {
@@ -208,9 +214,9 @@ func TestMessagesCmd_WithDirection(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("twilio_sid", "test-sid")
-	config.Set("twilio_token", "test-token")
-	config.Set("twilio_phone", "+15551234567")
+	config.Set(ctx, "twilio_sid", "test-sid")
+	config.Set(ctx, "twilio_token", "test-token")
+	config.Set(ctx, "twilio_phone", "+15551234567")
 
 	cmd := newMessagesCmd()
 	cmd.SetArgs([]string{"--direction", "outbound"})
@@ -222,6 +228,7 @@ func TestMessagesCmd_WithDirection(t *testing.T) {
 }
 
 func TestMessageCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "GET" {
 			t.Errorf("expected GET, got %s", r.Method)
@@ -249,9 +256,9 @@ func TestMessageCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("twilio_sid", "test-sid")
-	config.Set("twilio_token", "test-token")
-	config.Set("twilio_phone", "+15551234567")
+	config.Set(ctx, "twilio_sid", "test-sid")
+	config.Set(ctx, "twilio_token", "test-token")
+	config.Set(ctx, "twilio_phone", "+15551234567")
 
 	cmd := newMessageCmd()
 	cmd.SetArgs([]string{"SM123"})
@@ -263,6 +270,7 @@ func TestMessageCmd(t *testing.T) {
 }
 
 func TestAccountCmd(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "GET" {
 			t.Errorf("expected GET, got %s", r.Method)
@@ -283,9 +291,9 @@ func TestAccountCmd(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("twilio_sid", "test-sid")
-	config.Set("twilio_token", "test-token")
-	config.Set("twilio_phone", "+15551234567")
+	config.Set(ctx, "twilio_sid", "test-sid")
+	config.Set(ctx, "twilio_token", "test-token")
+	config.Set(ctx, "twilio_phone", "+15551234567")
 
 	cmd := newAccountCmd()
 	err := cmd.Execute()
@@ -295,11 +303,12 @@ func TestAccountCmd(t *testing.T) {
 }
 
 func TestGetTwilioConfig_Complete(t *testing.T) {
-	config.Set("twilio_sid", "AC123")
-	config.Set("twilio_token", "token123")
-	config.Set("twilio_phone", "+15551234567")
+	ctx := context.Background()
+	config.Set(ctx, "twilio_sid", "AC123")
+	config.Set(ctx, "twilio_token", "token123")
+	config.Set(ctx, "twilio_phone", "+15551234567")
 
-	sid, token, phone, err := getTwilioConfig()
+	sid, token, phone, err := getTwilioConfig(ctx)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -318,33 +327,36 @@ func TestGetTwilioConfig_Complete(t *testing.T) {
 }
 
 func TestGetTwilioConfig_MissingSID(t *testing.T) {
-	config.Set("twilio_sid", "")
-	config.Set("twilio_token", "token123")
-	config.Set("twilio_phone", "+15551234567")
+	ctx := context.Background()
+	config.Set(ctx, "twilio_sid", "")
+	config.Set(ctx, "twilio_token", "token123")
+	config.Set(ctx, "twilio_phone", "+15551234567")
 
-	_, _, _, err := getTwilioConfig()
+	_, _, _, err := getTwilioConfig(ctx)
 	if err == nil {
 		t.Fatal("expected error for missing SID, got nil")
 	}
 }
 
 func TestGetTwilioConfig_MissingToken(t *testing.T) {
-	config.Set("twilio_sid", "AC123")
-	config.Set("twilio_token", "")
-	config.Set("twilio_phone", "+15551234567")
+	ctx := context.Background()
+	config.Set(ctx, "twilio_sid", "AC123")
+	config.Set(ctx, "twilio_token", "")
+	config.Set(ctx, "twilio_phone", "+15551234567")
 
-	_, _, _, err := getTwilioConfig()
+	_, _, _, err := getTwilioConfig(ctx)
 	if err == nil {
 		t.Fatal("expected error for missing token, got nil")
 	}
 }
 
 func TestGetTwilioConfig_MissingPhone(t *testing.T) {
-	config.Set("twilio_sid", "AC123")
-	config.Set("twilio_token", "token123")
-	config.Set("twilio_phone", "")
+	ctx := context.Background()
+	config.Set(ctx, "twilio_sid", "AC123")
+	config.Set(ctx, "twilio_token", "token123")
+	config.Set(ctx, "twilio_phone", "")
 
-	_, _, _, err := getTwilioConfig()
+	_, _, _, err := getTwilioConfig(ctx)
 	if err == nil {
 		t.Fatal("expected error for missing phone, got nil")
 	}
@@ -408,6 +420,7 @@ func TestMessage_WithPriceAndError(t *testing.T) {
 }
 
 func TestMessagesCmd_ErrorResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
 		resp := twilioAPIError{
@@ -424,9 +437,9 @@ func TestMessagesCmd_ErrorResponse(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	config.Set("twilio_sid", "bad-sid")
-	config.Set("twilio_token", "bad-token")
-	config.Set("twilio_phone", "+15551234567")
+	config.Set(ctx, "twilio_sid", "bad-sid")
+	config.Set(ctx, "twilio_token", "bad-token")
+	config.Set(ctx, "twilio_phone", "+15551234567")
 
 	cmd := newMessagesCmd()
 	err := cmd.Execute()
@@ -436,15 +449,16 @@ func TestMessagesCmd_ErrorResponse(t *testing.T) {
 }
 
 func TestGetConfigPhone(t *testing.T) {
-	config.Set("twilio_phone", "+15551234567")
+	ctx := context.Background()
+	config.Set(ctx, "twilio_phone", "+15551234567")
 
-	phone := getConfigPhone()
+	phone := getConfigPhone(ctx)
 	if phone != "+15551234567" {
 		t.Errorf("expected phone '+15551234567', got %q", phone)
 	}
 
-	config.Set("twilio_phone", "")
-	phone = getConfigPhone()
+	config.Set(ctx, "twilio_phone", "")
+	phone = getConfigPhone(ctx)
 	if phone != "" {
 		t.Errorf("expected empty phone, got %q", phone)
 	}
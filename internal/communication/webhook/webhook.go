@@ -12,10 +12,12 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 const methodGet = "GET"
 
@@ -42,6 +44,16 @@ type DiscordMessage struct {
 	AvatarURL string `json:"avatar_url,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "webhook",
+		Name:        "Webhooks",
+		Group:       "comms",
+		Description: "Send data to webhooks (generic, Slack, Discord)",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "webhook",
@@ -68,6 +80,7 @@ func newSendCmd() *cobra.Command {
 		Long:  `Send a request with JSON data to any webhook URL. The data should be valid JSON.`,
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			url := args[0]
 			data := args[1]
 
@@ -80,7 +93,7 @@ func newSendCmd() *cobra.Command {
 				"DELETE":  true,
 			}
 			if !validMethods[method] {
-				return output.PrintError("invalid_method", "Method must be GET, POST, PUT, or DELETE", map[string]any{
+				return output.PrintError(ctx, "invalid_method", "Method must be GET, POST, PUT, or DELETE", map[string]any{
 					"provided": method,
 				})
 			}
@@ -89,7 +102,7 @@ func newSendCmd() *cobra.Command {
 			if method != methodGet && contentType == "application/json" {
 				var js json.RawMessage
 				if err := json.Unmarshal([]byte(data), &js); err != nil {
-					return output.PrintError("invalid_json", "Data is not valid JSON", map[string]any{
+					return output.PrintError(ctx, "invalid_json", "Data is not valid JSON", map[string]any{
 						"error": err.Error(),
 						"data":  data,
 					})
@@ -102,12 +115,12 @@ func newSendCmd() *cobra.Command {
 				body = bytes.NewBufferString(data)
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			req, err := http.NewRequestWithContext(ctx, method, url, body)
 			if err != nil {
-				return output.PrintError("request_error", "Failed to create request", map[string]any{
+				return output.PrintError(ctx, "request_error", "Failed to create request", map[string]any{
 					"error": err.Error(),
 				})
 			}
@@ -121,7 +134,7 @@ func newSendCmd() *cobra.Command {
 			for _, h := range headers {
 				parts := strings.SplitN(h, ":", 2)
 				if len(parts) != 2 {
-					return output.PrintError("invalid_header", "Header must be in format 'Key: Value'", map[string]any{
+					return output.PrintError(ctx, "invalid_header", "Header must be in format 'Key: Value'", map[string]any{
 						"header": h,
 					})
 				}
@@ -133,7 +146,7 @@ func newSendCmd() *cobra.Command {
 			// Send request
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				return output.PrintError("request_failed", "Failed to send request", map[string]any{
+				return output.PrintError(ctx, "request_failed", "Failed to send request", map[string]any{
 					"error": err.Error(),
 					"url":   url,
 				})
@@ -143,7 +156,7 @@ func newSendCmd() *cobra.Command {
 			// Read response body
 			respBody, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("read_error", "Failed to read response", map[string]any{
+				return output.PrintError(ctx, "read_error", "Failed to read response", map[string]any{
 					"error": err.Error(),
 				})
 			}
@@ -154,7 +167,7 @@ func newSendCmd() *cobra.Command {
 				status = "error"
 			}
 
-			return output.Print(Response{
+			return output.Print(ctx, Response{
 				Status:     status,
 				StatusCode: resp.StatusCode,
 				URL:        url,
@@ -181,6 +194,7 @@ func newSlackCmd() *cobra.Command {
 		Long:  `Send a message to a Slack channel via incoming webhook URL.`,
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			webhookURL := args[0]
 			message := args[1]
 
@@ -205,18 +219,18 @@ func newSlackCmd() *cobra.Command {
 			// Marshal payload
 			jsonData, err := json.Marshal(payload)
 			if err != nil {
-				return output.PrintError("marshal_error", "Failed to create message payload", map[string]any{
+				return output.PrintError(ctx, "marshal_error", "Failed to create message payload", map[string]any{
 					"error": err.Error(),
 				})
 			}
 
 			// Send request
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer(jsonData))
 			if err != nil {
-				return output.PrintError("request_error", "Failed to create request", map[string]any{
+				return output.PrintError(ctx, "request_error", "Failed to create request", map[string]any{
 					"error": err.Error(),
 				})
 			}
@@ -224,7 +238,7 @@ func newSlackCmd() *cobra.Command {
 
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				return output.PrintError("request_failed", "Failed to send message to Slack", map[string]any{
+				return output.PrintError(ctx, "request_failed", "Failed to send message to Slack", map[string]any{
 					"error": err.Error(),
 					"url":   webhookURL,
 				})
@@ -234,7 +248,7 @@ func newSlackCmd() *cobra.Command {
 			// Read response
 			respBody, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("read_error", "Failed to read response", map[string]any{
+				return output.PrintError(ctx, "read_error", "Failed to read response", map[string]any{
 					"error": err.Error(),
 				})
 			}
@@ -242,13 +256,13 @@ func newSlackCmd() *cobra.Command {
 			// Slack returns "ok" on success
 			respStr := string(respBody)
 			if resp.StatusCode >= 400 || respStr != "ok" {
-				return output.PrintError("slack_error", "Slack webhook returned error", map[string]any{
+				return output.PrintError(ctx, "slack_error", "Slack webhook returned error", map[string]any{
 					"status_code": resp.StatusCode,
 					"response":    respStr,
 				})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"status":      "sent",
 				"platform":    "slack",
 				"message":     message,
@@ -275,12 +289,13 @@ func newDiscordCmd() *cobra.Command {
 		Long:  `Send a message to a Discord channel via webhook URL.`,
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			webhookURL := args[0]
 			message := args[1]
 
 			// Validate message length (Discord limit is 2000 characters)
 			if len(message) > 2000 {
-				return output.PrintError("message_too_long", "Discord messages must be 2000 characters or less", map[string]any{
+				return output.PrintError(ctx, "message_too_long", "Discord messages must be 2000 characters or less", map[string]any{
 					"length": len(message),
 					"limit":  2000,
 				})
@@ -300,18 +315,18 @@ func newDiscordCmd() *cobra.Command {
 			// Marshal payload
 			jsonData, err := json.Marshal(payload)
 			if err != nil {
-				return output.PrintError("marshal_error", "Failed to create message payload", map[string]any{
+				return output.PrintError(ctx, "marshal_error", "Failed to create message payload", map[string]any{
 					"error": err.Error(),
 				})
 			}
 
 			// Send request
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer(jsonData))
 			if err != nil {
-				return output.PrintError("request_error", "Failed to create request", map[string]any{
+				return output.PrintError(ctx, "request_error", "Failed to create request", map[string]any{
 					"error": err.Error(),
 				})
 			}
@@ -319,7 +334,7 @@ func newDiscordCmd() *cobra.Command {
 
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				return output.PrintError("request_failed", "Failed to send message to Discord", map[string]any{
+				return output.PrintError(ctx, "request_failed", "Failed to send message to Discord", map[string]any{
 					"error": err.Error(),
 					"url":   webhookURL,
 				})
@@ -329,7 +344,7 @@ func newDiscordCmd() *cobra.Command {
 			// Read response
 			respBody, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("read_error", "Failed to read response", map[string]any{
+				return output.PrintError(ctx, "read_error", "Failed to read response", map[string]any{
 					"error": err.Error(),
 				})
 			}
@@ -338,18 +353,18 @@ func newDiscordCmd() *cobra.Command {
 			if resp.StatusCode >= 400 {
 				var errResp map[string]any
 				if err := json.Unmarshal(respBody, &errResp); err == nil {
-					return output.PrintError("discord_error", fmt.Sprintf("Discord webhook returned error: %v", errResp["message"]), map[string]any{
+					return output.PrintError(ctx, "discord_error", fmt.Sprintf("Discord webhook returned error: %v", errResp["message"]), map[string]any{
 						"status_code": resp.StatusCode,
 						"response":    errResp,
 					})
 				}
-				return output.PrintError("discord_error", "Discord webhook returned error", map[string]any{
+				return output.PrintError(ctx, "discord_error", "Discord webhook returned error", map[string]any{
 					"status_code": resp.StatusCode,
 					"response":    string(respBody),
 				})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"status":      "sent",
 				"platform":    "discord",
 				"message":     message,
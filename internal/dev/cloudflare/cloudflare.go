@@ -12,12 +12,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://api.cloudflare.com/client/v4"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Zone is LLM-friendly zone output
 type Zone struct {
@@ -99,6 +101,22 @@ type PurgeResult struct {
 	ID string `json:"id"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "cloudflare",
+		Name:        "Cloudflare",
+		Group:       "dev",
+		Description: "DNS, zones, cache purge, and analytics via Cloudflare",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "cloudflare_token", Description: "API token with Zone permissions", Required: true},
+		},
+		SetupGuide:  "1. Go to https://dash.cloudflare.com/profile/api-tokens\n2. Click \"Create Token\"\n3. Use template \"Edit zone DNS\" or create custom with:\n   - Zone:DNS:Edit, Zone:Zone:Read permissions\n4. Copy the token\n5. Run: pocket config set cloudflare_token <your-token>",
+		TestCommand: "pocket dev cloudflare zones",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "cloudflare",
@@ -124,7 +142,8 @@ func newZonesCmd() *cobra.Command {
 		Use:   "zones",
 		Short: "List all zones/domains",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -138,20 +157,20 @@ func newZonesCmd() *cobra.Command {
 			}
 
 			var resp cfResponse
-			if err := cfGet(token, url, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := cfGet(ctx, token, url, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			if !resp.Success {
-				return output.PrintError("api_error", formatErrors(resp.Errors), nil)
+				return output.PrintError(ctx, "api_error", formatErrors(resp.Errors), nil)
 			}
 
 			zones, err := parseZones(resp.Result)
 			if err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
-			return output.Print(zones)
+			return output.Print(ctx, zones)
 		},
 	}
 
@@ -168,7 +187,8 @@ func newZoneCmd() *cobra.Command {
 		Short: "Get zone details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -176,20 +196,20 @@ func newZoneCmd() *cobra.Command {
 			url := fmt.Sprintf("%s/zones/%s", baseURL, args[0])
 
 			var resp cfResponse
-			if err := cfGet(token, url, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := cfGet(ctx, token, url, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			if !resp.Success {
-				return output.PrintError("api_error", formatErrors(resp.Errors), nil)
+				return output.PrintError(ctx, "api_error", formatErrors(resp.Errors), nil)
 			}
 
 			zone, err := parseZone(resp.Result)
 			if err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
-			return output.Print(zone)
+			return output.Print(ctx, zone)
 		},
 	}
 
@@ -206,7 +226,8 @@ func newDNSCmd() *cobra.Command {
 		Short: "List DNS records for a zone",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -220,20 +241,20 @@ func newDNSCmd() *cobra.Command {
 			}
 
 			var resp cfResponse
-			if err := cfGet(token, url, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := cfGet(ctx, token, url, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			if !resp.Success {
-				return output.PrintError("api_error", formatErrors(resp.Errors), nil)
+				return output.PrintError(ctx, "api_error", formatErrors(resp.Errors), nil)
 			}
 
 			records, err := parseDNSRecords(resp.Result)
 			if err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
-			return output.Print(records)
+			return output.Print(ctx, records)
 		},
 	}
 
@@ -256,7 +277,8 @@ func newPurgeCmd() *cobra.Command {
 		Short: "Purge cache for a zone",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -276,24 +298,24 @@ func newPurgeCmd() *cobra.Command {
 			case len(prefixes) > 0:
 				body = map[string]any{"prefixes": prefixes}
 			default:
-				return output.PrintError("missing_option", "Specify --all, --urls, --tags, --hosts, or --prefixes", nil)
+				return output.PrintError(ctx, "missing_option", "Specify --all, --urls, --tags, --hosts, or --prefixes", nil)
 			}
 
 			var resp cfResponse
-			if err := cfPost(token, url, body, &resp); err != nil {
-				return output.PrintError("purge_failed", err.Error(), nil)
+			if err := cfPost(ctx, token, url, body, &resp); err != nil {
+				return output.PrintErr(ctx, "purge_failed", err, nil)
 			}
 
 			if !resp.Success {
-				return output.PrintError("api_error", formatErrors(resp.Errors), nil)
+				return output.PrintError(ctx, "api_error", formatErrors(resp.Errors), nil)
 			}
 
 			result, err := parsePurgeResult(resp.Result)
 			if err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"message": "Cache purge initiated",
 				"id":      result.ID,
 			})
@@ -317,7 +339,8 @@ func newAnalyticsCmd() *cobra.Command {
 		Short: "Get zone analytics",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -331,20 +354,20 @@ func newAnalyticsCmd() *cobra.Command {
 				baseURL, args[0], since, until)
 
 			var resp cfResponse
-			if err := cfGet(token, url, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := cfGet(ctx, token, url, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			if !resp.Success {
-				return output.PrintError("api_error", formatErrors(resp.Errors), nil)
+				return output.PrintError(ctx, "api_error", formatErrors(resp.Errors), nil)
 			}
 
 			analytics, err := parseAnalytics(resp.Result, since, until)
 			if err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
-			return output.Print(analytics)
+			return output.Print(ctx, analytics)
 		},
 	}
 
@@ -366,13 +389,13 @@ type cfError struct {
 	Message string `json:"message"`
 }
 
-func getToken() (string, error) {
-	token, err := config.Get("cloudflare_token")
+func getToken(ctx context.Context) (string, error) {
+	token, err := config.Get(ctx, "cloudflare_token")
 	if err != nil {
 		return "", err
 	}
 	if token == "" {
-		return "", output.PrintError("missing_config", "Cloudflare token not configured", map[string]string{
+		return "", output.PrintError(ctx, "missing_config", "Cloudflare token not configured", map[string]string{
 			"setup":       "Run: pocket config set cloudflare_token <your-api-token>",
 			"docs":        "Create an API token at: https://dash.cloudflare.com/profile/api-tokens",
 			"permissions": "Required permissions depend on commands: Zone:Read for zones/dns, Cache Purge for purge, Analytics:Read for analytics",
@@ -381,8 +404,8 @@ func getToken() (string, error) {
 	return token, nil
 }
 
-func cfGet(token, url string, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func cfGet(ctx context.Context, token, url string, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, http.NoBody)
@@ -411,8 +434,8 @@ func cfGet(token, url string, result any) error {
 	return json.NewDecoder(resp.Body).Decode(result)
 }
 
-func cfPost(token, url string, body, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func cfPost(ctx context.Context, token, url string, body, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	jsonBody, err := json.Marshal(body)
@@ -1,6 +1,7 @@
 package cloudflare
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -112,6 +113,7 @@ func TestParseTime(t *testing.T) {
 }
 
 func TestCfGetSuccess(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("Authorization") != "Bearer test-token" {
 			t.Error("expected Authorization header")
@@ -129,7 +131,7 @@ func TestCfGetSuccess(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	var result cfResponse
-	err := cfGet("test-token", srv.URL+"/zones", &result)
+	err := cfGet(ctx, "test-token", srv.URL+"/zones", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -150,6 +152,7 @@ func TestCfGetError(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
 			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.statusCode)
 				resp := cfResponse{
@@ -163,7 +166,7 @@ func TestCfGetError(t *testing.T) {
 			defer srv.Close()
 
 			var result cfResponse
-			err := cfGet("test-token", srv.URL+"/test", &result)
+			err := cfGet(ctx, "test-token", srv.URL+"/test", &result)
 			if err == nil {
 				t.Error("expected error, got nil")
 			}
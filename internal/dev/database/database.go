@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
@@ -9,6 +10,7 @@ import (
 	_ "github.com/mattn/go-sqlite3" // sqlite3 driver registration
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -56,6 +58,16 @@ type TableInfo struct {
 	Type string `json:"type"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "database",
+		Name:        "Database (SQLite)",
+		Group:       "dev",
+		Description: "Query, inspect schema, and list tables in SQLite databases",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the database command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -79,14 +91,15 @@ func newQueryCmd() *cobra.Command {
 		Short: "Execute a SELECT query and return results as JSON",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			dbPath := args[0]
 			sqlQuery := args[1]
 
-			if err := validateDBPath(dbPath); err != nil {
+			if err := validateDBPath(ctx, dbPath); err != nil {
 				return err
 			}
 
-			if err := validateReadOnly(sqlQuery); err != nil {
+			if err := validateReadOnly(ctx, sqlQuery); err != nil {
 				return err
 			}
 
@@ -98,19 +111,19 @@ func newQueryCmd() *cobra.Command {
 
 			db, err := sql.Open("sqlite3", dbPath+"?mode=ro")
 			if err != nil {
-				return output.PrintError("db_open_failed", fmt.Sprintf("Failed to open database: %s", err.Error()), nil)
+				return output.PrintError(ctx, "db_open_failed", fmt.Sprintf("Failed to open database: %s", err.Error()), nil)
 			}
 			defer db.Close()
 
 			rows, err := db.Query(sqlQuery)
 			if err != nil {
-				return output.PrintError("query_failed", fmt.Sprintf("Query failed: %s", err.Error()), nil)
+				return output.PrintError(ctx, "query_failed", fmt.Sprintf("Query failed: %s", err.Error()), nil)
 			}
 			defer rows.Close()
 
 			columns, err := rows.Columns()
 			if err != nil {
-				return output.PrintError("query_failed", fmt.Sprintf("Failed to get columns: %s", err.Error()), nil)
+				return output.PrintError(ctx, "query_failed", fmt.Sprintf("Failed to get columns: %s", err.Error()), nil)
 			}
 
 			var resultRows []map[string]interface{}
@@ -123,7 +136,7 @@ func newQueryCmd() *cobra.Command {
 				}
 
 				if err := rows.Scan(scanArgs...); err != nil {
-					return output.PrintError("scan_failed", fmt.Sprintf("Failed to scan row: %s", err.Error()), nil)
+					return output.PrintError(ctx, "scan_failed", fmt.Sprintf("Failed to scan row: %s", err.Error()), nil)
 				}
 
 				row := make(map[string]interface{})
@@ -138,7 +151,7 @@ func newQueryCmd() *cobra.Command {
 			}
 
 			if err := rows.Err(); err != nil {
-				return output.PrintError("query_failed", fmt.Sprintf("Row iteration error: %s", err.Error()), nil)
+				return output.PrintError(ctx, "query_failed", fmt.Sprintf("Row iteration error: %s", err.Error()), nil)
 			}
 
 			if resultRows == nil {
@@ -153,7 +166,7 @@ func newQueryCmd() *cobra.Command {
 				RowCount: len(resultRows),
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -168,22 +181,23 @@ func newSchemaCmd() *cobra.Command {
 		Short: "List all tables and their columns",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			dbPath := args[0]
 
-			if err := validateDBPath(dbPath); err != nil {
+			if err := validateDBPath(ctx, dbPath); err != nil {
 				return err
 			}
 
 			db, err := sql.Open("sqlite3", dbPath+"?mode=ro")
 			if err != nil {
-				return output.PrintError("db_open_failed", fmt.Sprintf("Failed to open database: %s", err.Error()), nil)
+				return output.PrintError(ctx, "db_open_failed", fmt.Sprintf("Failed to open database: %s", err.Error()), nil)
 			}
 			defer db.Close()
 
 			// Get all table names
 			tableRows, err := db.Query("SELECT name FROM sqlite_master WHERE type='table' ORDER BY name")
 			if err != nil {
-				return output.PrintError("query_failed", fmt.Sprintf("Failed to list tables: %s", err.Error()), nil)
+				return output.PrintError(ctx, "query_failed", fmt.Sprintf("Failed to list tables: %s", err.Error()), nil)
 			}
 			defer tableRows.Close()
 
@@ -191,12 +205,12 @@ func newSchemaCmd() *cobra.Command {
 			for tableRows.Next() {
 				var name string
 				if err := tableRows.Scan(&name); err != nil {
-					return output.PrintError("scan_failed", fmt.Sprintf("Failed to scan table name: %s", err.Error()), nil)
+					return output.PrintError(ctx, "scan_failed", fmt.Sprintf("Failed to scan table name: %s", err.Error()), nil)
 				}
 				tableNames = append(tableNames, name)
 			}
 			if err := tableRows.Err(); err != nil {
-				return output.PrintError("query_failed", fmt.Sprintf("Table iteration error: %s", err.Error()), nil)
+				return output.PrintError(ctx, "query_failed", fmt.Sprintf("Table iteration error: %s", err.Error()), nil)
 			}
 
 			var tables []Table
@@ -207,7 +221,7 @@ func newSchemaCmd() *cobra.Command {
 				pragmaQuery := fmt.Sprintf("PRAGMA table_info(\"%s\")", strings.ReplaceAll(tableName, "\"", "\"\"")) //nolint:gocritic // SQL syntax requires this format
 				colRows, err := db.Query(pragmaQuery)
 				if err != nil {
-					return output.PrintError("query_failed", fmt.Sprintf("Failed to get columns for %s: %s", tableName, err.Error()), nil)
+					return output.PrintError(ctx, "query_failed", fmt.Sprintf("Failed to get columns for %s: %s", tableName, err.Error()), nil)
 				}
 				defer colRows.Close() //nolint:gocritic // rows must be closed after each query iteration
 
@@ -218,7 +232,7 @@ func newSchemaCmd() *cobra.Command {
 					var dfltValue sql.NullString
 
 					if err := colRows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
-						return output.PrintError("scan_failed", fmt.Sprintf("Failed to scan column info: %s", err.Error()), nil)
+						return output.PrintError(ctx, "scan_failed", fmt.Sprintf("Failed to scan column info: %s", err.Error()), nil)
 					}
 
 					col := Column{
@@ -235,7 +249,7 @@ func newSchemaCmd() *cobra.Command {
 					table.Columns = append(table.Columns, col)
 				}
 				if err := colRows.Err(); err != nil {
-					return output.PrintError("query_failed", fmt.Sprintf("Column iteration error: %s", err.Error()), nil)
+					return output.PrintError(ctx, "query_failed", fmt.Sprintf("Column iteration error: %s", err.Error()), nil)
 				}
 
 				// Get row count
@@ -253,7 +267,7 @@ func newSchemaCmd() *cobra.Command {
 				Tables:   tables,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -266,21 +280,22 @@ func newTablesCmd() *cobra.Command {
 		Short: "Quick list of tables and views",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			dbPath := args[0]
 
-			if err := validateDBPath(dbPath); err != nil {
+			if err := validateDBPath(ctx, dbPath); err != nil {
 				return err
 			}
 
 			db, err := sql.Open("sqlite3", dbPath+"?mode=ro")
 			if err != nil {
-				return output.PrintError("db_open_failed", fmt.Sprintf("Failed to open database: %s", err.Error()), nil)
+				return output.PrintError(ctx, "db_open_failed", fmt.Sprintf("Failed to open database: %s", err.Error()), nil)
 			}
 			defer db.Close()
 
 			rows, err := db.Query("SELECT name, type FROM sqlite_master WHERE type IN ('table','view') ORDER BY name")
 			if err != nil {
-				return output.PrintError("query_failed", fmt.Sprintf("Failed to list tables: %s", err.Error()), nil)
+				return output.PrintError(ctx, "query_failed", fmt.Sprintf("Failed to list tables: %s", err.Error()), nil)
 			}
 			defer rows.Close()
 
@@ -288,7 +303,7 @@ func newTablesCmd() *cobra.Command {
 			for rows.Next() {
 				var name, tableType string
 				if err := rows.Scan(&name, &tableType); err != nil {
-					return output.PrintError("scan_failed", fmt.Sprintf("Failed to scan table info: %s", err.Error()), nil)
+					return output.PrintError(ctx, "scan_failed", fmt.Sprintf("Failed to scan table info: %s", err.Error()), nil)
 				}
 				tables = append(tables, TableInfo{
 					Name: name,
@@ -296,7 +311,7 @@ func newTablesCmd() *cobra.Command {
 				})
 			}
 			if err := rows.Err(); err != nil {
-				return output.PrintError("query_failed", fmt.Sprintf("Table iteration error: %s", err.Error()), nil)
+				return output.PrintError(ctx, "query_failed", fmt.Sprintf("Table iteration error: %s", err.Error()), nil)
 			}
 
 			if tables == nil {
@@ -309,21 +324,21 @@ func newTablesCmd() *cobra.Command {
 				Count:    len(tables),
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
 	return cmd
 }
 
-func validateDBPath(dbPath string) error {
+func validateDBPath(ctx context.Context, dbPath string) error {
 	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
-		return output.PrintError("file_not_found", fmt.Sprintf("Database file not found: %s", dbPath), nil)
+		return output.PrintError(ctx, "file_not_found", fmt.Sprintf("Database file not found: %s", dbPath), nil)
 	}
 	return nil
 }
 
-func validateReadOnly(query string) error {
+func validateReadOnly(ctx context.Context, query string) error {
 	// Normalize whitespace and convert to uppercase for checking
 	normalized := strings.TrimSpace(strings.ToUpper(query))
 
@@ -346,7 +361,7 @@ func validateReadOnly(query string) error {
 	}
 
 	if !allowed {
-		return output.PrintError("read_only", "Only SELECT, PRAGMA, and EXPLAIN statements are allowed", map[string]interface{}{
+		return output.PrintError(ctx, "read_only", "Only SELECT, PRAGMA, and EXPLAIN statements are allowed", map[string]interface{}{
 			"hint": "This tool is read-only. INSERT, UPDATE, DELETE, DROP, CREATE, and ALTER are not permitted.",
 		})
 	}
@@ -360,7 +375,7 @@ func validateReadOnly(query string) error {
 	}
 	for _, d := range dangerous {
 		if strings.Contains(normalized, d) {
-			return output.PrintError("read_only", fmt.Sprintf("Statement contains disallowed operation: %s", d), map[string]interface{}{
+			return output.PrintError(ctx, "read_only", fmt.Sprintf("Statement contains disallowed operation: %s", d), map[string]interface{}{
 				"hint": "This tool is read-only. Modification statements are not permitted.",
 			})
 		}
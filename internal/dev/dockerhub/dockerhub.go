@@ -1,7 +1,6 @@
 package dockerhub
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,12 +10,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://hub.docker.com/v2"
 
-var client = &http.Client{Timeout: 10 * time.Second}
+var client = transport.New(10 * time.Second)
 
 // Image is LLM-friendly image info
 type Image struct {
@@ -61,6 +62,16 @@ type Manifest struct {
 	Platforms    []string `json:"platforms,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "dockerhub",
+		Name:        "Docker Hub",
+		Group:       "dev",
+		Description: "Search Docker images, get tags, and inspect manifests",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "dockerhub",
@@ -84,16 +95,17 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search Docker images",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := url.QueryEscape(args[0])
 			reqURL := fmt.Sprintf("%s/search/repositories/?query=%s&page_size=%d", baseURL, query, limit)
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, http.NoBody)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			resp, err := client.Do(req)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
@@ -108,7 +120,7 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			results := make([]SearchResult, 0, len(data.Results))
@@ -122,7 +134,7 @@ func newSearchCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(results)
+			return output.Print(ctx, results)
 		},
 	}
 
@@ -138,27 +150,28 @@ func newImageCmd() *cobra.Command {
 		Long:  "Get image details (e.g., library/nginx or username/image)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			name := normalizeImageName(args[0])
 			parts := strings.SplitN(name, "/", 2)
 			if len(parts) != 2 {
-				return output.PrintError("invalid_name", "Image name must be in format namespace/image", nil)
+				return output.PrintError(ctx, "invalid_name", "Image name must be in format namespace/image", nil)
 			}
 
 			namespace, repo := parts[0], parts[1]
 			reqURL := fmt.Sprintf("%s/repositories/%s/%s/", baseURL, namespace, repo)
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, http.NoBody)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			resp, err := client.Do(req)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode == 404 {
-				return output.PrintError("not_found", "Image not found: "+name, nil)
+				return output.PrintError(ctx, "not_found", "Image not found: "+name, nil)
 			}
 
 			var data struct {
@@ -175,7 +188,7 @@ func newImageCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			img := Image{
@@ -189,7 +202,7 @@ func newImageCmd() *cobra.Command {
 				Updated:     parseTimeAgo(data.LastUpdated),
 			}
 
-			return output.Print(img)
+			return output.Print(ctx, img)
 		},
 	}
 
@@ -204,27 +217,28 @@ func newTagsCmd() *cobra.Command {
 		Short: "List tags for an image",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			name := normalizeImageName(args[0])
 			parts := strings.SplitN(name, "/", 2)
 			if len(parts) != 2 {
-				return output.PrintError("invalid_name", "Image name must be in format namespace/image", nil)
+				return output.PrintError(ctx, "invalid_name", "Image name must be in format namespace/image", nil)
 			}
 
 			namespace, repo := parts[0], parts[1]
 			reqURL := fmt.Sprintf("%s/repositories/%s/%s/tags/?page_size=%d&ordering=last_updated", baseURL, namespace, repo, limit)
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, http.NoBody)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			resp, err := client.Do(req)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode == 404 {
-				return output.PrintError("not_found", "Image not found: "+name, nil)
+				return output.PrintError(ctx, "not_found", "Image not found: "+name, nil)
 			}
 
 			var data struct {
@@ -241,7 +255,7 @@ func newTagsCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			tags := make([]Tag, 0, len(data.Results))
@@ -260,7 +274,7 @@ func newTagsCmd() *cobra.Command {
 				tags = append(tags, tag)
 			}
 
-			return output.Print(tags)
+			return output.Print(ctx, tags)
 		},
 	}
 
@@ -275,30 +289,31 @@ func newInspectCmd() *cobra.Command {
 		Short: "Get detailed image manifest info",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			nameTag := args[0]
 			name, tag := parseNameTag(nameTag)
 			name = normalizeImageName(name)
 
 			parts := strings.SplitN(name, "/", 2)
 			if len(parts) != 2 {
-				return output.PrintError("invalid_name", "Image name must be in format namespace/image[:tag]", nil)
+				return output.PrintError(ctx, "invalid_name", "Image name must be in format namespace/image[:tag]", nil)
 			}
 
 			namespace, repo := parts[0], parts[1]
 			reqURL := fmt.Sprintf("%s/repositories/%s/%s/tags/%s", baseURL, namespace, repo, tag)
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, http.NoBody)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			resp, err := client.Do(req)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode == 404 {
-				return output.PrintError("not_found", fmt.Sprintf("Image not found: %s:%s", name, tag), nil)
+				return output.PrintError(ctx, "not_found", fmt.Sprintf("Image not found: %s:%s", name, tag), nil)
 			}
 
 			var data struct {
@@ -316,7 +331,7 @@ func newInspectCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			manifest := Manifest{
@@ -347,7 +362,7 @@ func newInspectCmd() *cobra.Command {
 			}
 			manifest.Platforms = platforms
 
-			return output.Print(manifest)
+			return output.Print(ctx, manifest)
 		},
 	}
 
@@ -14,10 +14,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 var baseURL = "https://api.github.com"
 
@@ -58,6 +60,16 @@ type Created struct {
 	Public      bool   `json:"public"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "gist",
+		Name:        "GitHub Gists",
+		Group:       "dev",
+		Description: "Create, list, and read GitHub Gists",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the gist command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -80,7 +92,8 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List your gists",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.MustGet("github_token")
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
 			if err != nil {
 				return err
 			}
@@ -88,8 +101,8 @@ func newListCmd() *cobra.Command {
 			reqURL := fmt.Sprintf("%s/gists?per_page=%d", baseURL, limit)
 
 			var data []map[string]any
-			if err := ghGet(token, reqURL, &data); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := ghGet(ctx, token, reqURL, &data); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			results := make([]Summary, 0, len(data))
@@ -97,7 +110,7 @@ func newListCmd() *cobra.Command {
 				results = append(results, toSummary(g))
 			}
 
-			return output.Print(results)
+			return output.Print(ctx, results)
 		},
 	}
 
@@ -112,7 +125,8 @@ func newGetCmd() *cobra.Command {
 		Short: "Get gist details by ID",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.MustGet("github_token")
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
 			if err != nil {
 				return err
 			}
@@ -120,11 +134,11 @@ func newGetCmd() *cobra.Command {
 			reqURL := fmt.Sprintf("%s/gists/%s", baseURL, args[0])
 
 			var data map[string]any
-			if err := ghGet(token, reqURL, &data); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := ghGet(ctx, token, reqURL, &data); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
-			return output.Print(toDetail(data))
+			return output.Print(ctx, toDetail(data))
 		},
 	}
 
@@ -140,7 +154,8 @@ func newCreateCmd() *cobra.Command {
 		Use:   "create [content]",
 		Short: "Create a new gist (also accepts stdin)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.MustGet("github_token")
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
 			if err != nil {
 				return err
 			}
@@ -155,17 +170,17 @@ func newCreateCmd() *cobra.Command {
 				if (stat.Mode() & os.ModeCharDevice) == 0 {
 					data, err := io.ReadAll(os.Stdin)
 					if err != nil {
-						return output.PrintError("read_failed", "Failed to read stdin: "+err.Error(), nil)
+						return output.PrintError(ctx, "read_failed", "Failed to read stdin: "+err.Error(), nil)
 					}
 					content = string(data)
 				}
 			}
 
 			if strings.TrimSpace(content) == "" {
-				return output.PrintError("missing_content", "Content is required (pass as argument or pipe via stdin)", nil)
+				return output.PrintError(ctx, "missing_content", "Content is required (pass as argument or pipe via stdin)", nil)
 			}
 
-			return createGist(token, content, desc, filename, public)
+			return createGist(ctx, token, content, desc, filename, public)
 		},
 	}
 
@@ -176,8 +191,8 @@ func newCreateCmd() *cobra.Command {
 	return cmd
 }
 
-func createGist(token, content, desc, filename string, public bool) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func createGist(ctx context.Context, token, content, desc, filename string, public bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	body := map[string]any{
@@ -192,14 +207,14 @@ func createGist(token, content, desc, filename string, public bool) error {
 
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
-		return output.PrintError("encode_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "encode_failed", err, nil)
 	}
 
 	reqURL := fmt.Sprintf("%s/gists", baseURL)
 
 	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewReader(jsonBody))
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -210,7 +225,7 @@ func createGist(token, content, desc, filename string, public bool) error {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
@@ -218,15 +233,15 @@ func createGist(token, content, desc, filename string, public bool) error {
 		var errResp map[string]any
 		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil {
 			if msg, ok := errResp["message"].(string); ok && msg != "" {
-				return output.PrintError("create_failed", msg, nil)
+				return output.PrintError(ctx, "create_failed", msg, nil)
 			}
 		}
-		return output.PrintError("create_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return output.PrintError(ctx, "create_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	var data map[string]any
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return output.PrintError("parse_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "parse_failed", err, nil)
 	}
 
 	result := Created{
@@ -236,11 +251,11 @@ func createGist(token, content, desc, filename string, public bool) error {
 		Public:      getBool(data, "public"),
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
 
-func ghGet(token, reqURL string, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func ghGet(ctx context.Context, token, reqURL string, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
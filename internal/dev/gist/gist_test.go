@@ -1,6 +1,7 @@
 package gist
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -92,6 +93,7 @@ func TestGetBool(t *testing.T) {
 }
 
 func TestGhGetSuccess(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("Authorization") != "Bearer test-token" {
 			t.Error("expected Authorization header")
@@ -122,7 +124,7 @@ func TestGhGetSuccess(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	var result []map[string]any
-	err := ghGet("test-token", srv.URL+"/gists", &result)
+	err := ghGet(ctx, "test-token", srv.URL+"/gists", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -143,6 +145,7 @@ func TestGhGetError(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
 			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.statusCode)
 				json.NewEncoder(w).Encode(map[string]any{"message": "Error"})
@@ -150,7 +153,7 @@ func TestGhGetError(t *testing.T) {
 			defer srv.Close()
 
 			var result []map[string]any
-			err := ghGet("test-token", srv.URL+"/test", &result)
+			err := ghGet(ctx, "test-token", srv.URL+"/test", &result)
 			if err == nil {
 				t.Error("expected error, got nil")
 			}
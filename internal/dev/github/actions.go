@@ -0,0 +1,486 @@
+package github
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/logtail"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// maxLogArchive is the largest log zip run-logs downloads
+const maxLogArchive = 64 << 20
+
+// maxLogBytes caps each log run-logs returns, after --lines
+const maxLogBytes = 16 << 10
+
+var (
+	// logTimestamp prefixes every line of an Actions log
+	logTimestamp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?Z ?`)
+	ansiEscape   = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+)
+
+// Run is a workflow run
+type Run struct {
+	ID         int    `json:"id"`
+	Workflow   string `json:"workflow"`
+	Title      string `json:"title"`
+	Branch     string `json:"branch"`
+	Event      string `json:"event"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+	SHA        string `json:"sha"`
+	Actor      string `json:"actor,omitempty"`
+	Attempt    int    `json:"attempt,omitempty"`
+	Age        string `json:"age"`
+	URL        string `json:"url"`
+}
+
+// RunDetail is a run with its jobs
+type RunDetail struct {
+	Run
+	Jobs []Job `json:"jobs"`
+}
+
+// Job is one job of a run
+type Job struct {
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Status     string    `json:"status"`
+	Conclusion string    `json:"conclusion,omitempty"`
+	Duration   string    `json:"duration,omitempty"`
+	URL        string    `json:"url"`
+	Steps      []JobStep `json:"steps,omitempty"`
+}
+
+// JobStep is one step of a job
+type JobStep struct {
+	Number     int    `json:"number"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+}
+
+// StepLog is the end of a failed step's log, or of a job's when the
+// archive has no log for the step
+type StepLog struct {
+	Job        string `json:"job"`
+	Step       string `json:"step,omitempty"`
+	Number     int    `json:"number,omitempty"`
+	Conclusion string `json:"conclusion,omitempty"`
+	// Lines counts the lines of the whole log
+	Lines     int    `json:"lines"`
+	Log       string `json:"log"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+func newRunsCmd() *cobra.Command {
+	var repo string
+	var branch string
+	var status string
+	var workflow string
+	var page paginate.Options
+
+	cmd := &cobra.Command{
+		Use:   "runs",
+		Short: "List GitHub Actions workflow runs",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			if repo == "" {
+				return output.PrintError(ctx, "missing_repo", "Repository required for runs (use -r owner/repo)", nil)
+			}
+			if err := checkRepo(ctx, repo); err != nil {
+				return err
+			}
+
+			runsURL := fmt.Sprintf("%s/repos/%s/actions/runs", baseURL, repo)
+			if workflow != "" {
+				runsURL = fmt.Sprintf("%s/repos/%s/actions/workflows/%s/runs", baseURL, repo, url.PathEscape(workflow))
+			}
+			q := url.Values{}
+			if branch != "" {
+				q.Set("branch", branch)
+			}
+			if status != "" {
+				q.Set("status", status)
+			}
+			if len(q) > 0 {
+				runsURL += "?" + q.Encode()
+			}
+
+			return ghListIn(ctx, token, runsURL, "workflow_runs", page, func(r map[string]any) (Run, bool) {
+				return toRun(r), true
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&repo, "repo", "r", "", "Repository (owner/name) - required")
+	cmd.Flags().StringVarP(&branch, "branch", "b", "", "Filter by branch")
+	cmd.Flags().StringVarP(&status, "status", "s", "", "Filter by status or conclusion: queued, in_progress, completed, success, failure, ...")
+	cmd.Flags().StringVarP(&workflow, "workflow", "w", "", "Filter by workflow file name (ci.yml) or ID")
+	paginate.AddFlags(cmd, &page, 20, 100, "runs")
+
+	return cmd
+}
+
+func newRunCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "run [owner/repo] [id]",
+		Short: "Get a workflow run with its jobs and steps",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			runURL, err := itemURL(ctx, args[0], "actions/runs", args[1])
+			if err != nil {
+				return err
+			}
+
+			var run map[string]any
+			if err := ghGet(ctx, token, runURL, &run); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+			jobs, err := runJobs(ctx, token, runURL)
+			if err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+			return output.Print(ctx, RunDetail{Run: toRun(run), Jobs: jobs})
+		},
+	}
+
+	return cmd
+}
+
+func newRunLogsCmd() *cobra.Command {
+	var job string
+	var lines int
+
+	cmd := &cobra.Command{
+		Use:   "run-logs [owner/repo] [id]",
+		Short: "Get the end of the logs of a run's failed steps",
+		Long:  "Download a workflow run's log archive and return the last --lines of each failed step's log, with timestamps and color codes removed. With --job, the jobs whose names contain it are read whether they failed or not.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			runURL, err := itemURL(ctx, args[0], "actions/runs", args[1])
+			if err != nil {
+				return err
+			}
+			if lines < 1 {
+				return output.PrintError(ctx, "invalid_input", "--lines must be positive", nil)
+			}
+
+			jobs, err := runJobs(ctx, token, runURL)
+			if err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+			var picked []Job
+			for _, j := range jobs {
+				match := failed(j.Conclusion)
+				if job != "" {
+					match = strings.Contains(strings.ToLower(j.Name), strings.ToLower(job))
+				}
+				if match {
+					picked = append(picked, j)
+				}
+			}
+			if len(picked) == 0 {
+				if job != "" {
+					return output.PrintError(ctx, "not_found", "No job of run "+args[1]+" matches "+job, nil)
+				}
+				return output.PrintError(ctx, "not_found", "Run "+args[1]+" has no failed jobs", map[string]any{
+					"hint": "Read a job's log with --job",
+				})
+			}
+
+			archive, err := ghGetRaw(ctx, token, runURL+"/logs", "application/vnd.github+json", maxLogArchive+1)
+			if err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+			if len(archive) > maxLogArchive {
+				return output.PrintError(ctx, "logs_too_large", fmt.Sprintf("Log archive is over %d MB", maxLogArchive>>20), nil)
+			}
+			zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+			if err != nil {
+				return output.PrintError(ctx, "parse_failed", "Invalid log archive: "+err.Error(), nil)
+			}
+
+			result := make([]StepLog, 0, len(picked))
+			for _, j := range picked {
+				log, err := jobLog(zr, j, lines)
+				if err != nil {
+					return output.PrintErr(ctx, "parse_failed", err, nil)
+				}
+				result = append(result, log)
+			}
+			return output.Print(ctx, result)
+		},
+	}
+
+	cmd.Flags().StringVarP(&job, "job", "j", "", "Read the jobs whose names contain this, failed or not")
+	cmd.Flags().IntVarP(&lines, "lines", "n", 80, "Lines to keep from the end of each log")
+
+	return cmd
+}
+
+func newRerunCmd() *cobra.Command {
+	var failedOnly bool
+	var debug bool
+
+	cmd := &cobra.Command{
+		Use:   "rerun [owner/repo] [id]",
+		Short: "Re-run a workflow run",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			runURL, err := itemURL(ctx, args[0], "actions/runs", args[1])
+			if err != nil {
+				return err
+			}
+
+			endpoint := runURL + "/rerun"
+			if failedOnly {
+				endpoint = runURL + "/rerun-failed-jobs"
+			}
+			var body any
+			if debug {
+				body = map[string]any{"enable_debug_logging": true}
+			}
+			if err := ghSend(ctx, token, "POST", endpoint, body, nil); err != nil {
+				return printAPIError(ctx, "rerun_failed", err)
+			}
+			return printRun(ctx, token, runURL)
+		},
+	}
+
+	cmd.Flags().BoolVar(&failedOnly, "failed", false, "Only re-run failed jobs and the jobs that depend on them")
+	cmd.Flags().BoolVar(&debug, "debug", false, "Enable debug logging for the re-run")
+
+	return cmd
+}
+
+func newCancelCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "cancel [owner/repo] [id]",
+		Short: "Cancel a workflow run",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			runURL, err := itemURL(ctx, args[0], "actions/runs", args[1])
+			if err != nil {
+				return err
+			}
+
+			endpoint := runURL + "/cancel"
+			if force {
+				endpoint = runURL + "/force-cancel"
+			}
+			if err := ghSend(ctx, token, "POST", endpoint, nil, nil); err != nil {
+				return printAPIError(ctx, "cancel_failed", err)
+			}
+			return printRun(ctx, token, runURL)
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Cancel even if the workflow's always() steps would keep it running")
+
+	return cmd
+}
+
+// printRun reads back and prints the run a write changed
+func printRun(ctx context.Context, token, runURL string) error {
+	var run map[string]any
+	if err := ghFresh(ctx, token, runURL, &run); err != nil {
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
+	}
+	return output.Print(ctx, toRun(run))
+}
+
+// runJobs returns the jobs of a run's latest attempt
+func runJobs(ctx context.Context, token, runURL string) ([]Job, error) {
+	raw, err := ghAll(ctx, token, runURL+"/jobs?per_page=100", "jobs", maxListItems)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]Job, 0, len(raw))
+	for _, j := range raw {
+		jobs = append(jobs, toJob(j))
+	}
+	return jobs, nil
+}
+
+// jobLog returns the end of the log of j's first failed step. Archives
+// keep a file per step in a folder named after the job, and one per job
+// at the top; when the step's file is missing, the job's log is read up
+// to its last error.
+func jobLog(zr *zip.Reader, j Job, lines int) (StepLog, error) {
+	result := StepLog{Job: j.Name}
+	var step *JobStep
+	for i := range j.Steps {
+		if j.Steps[i].Conclusion == "failure" {
+			step = &j.Steps[i]
+			break
+		}
+	}
+	if step != nil {
+		result.Step, result.Number, result.Conclusion = step.Name, step.Number, step.Conclusion
+	}
+
+	var stepFile, jobFile *zip.File
+	for _, f := range zr.File {
+		dir, base := path.Split(f.Name)
+		if dir == "" {
+			// Job logs are named <index>_<job>.txt
+			if _, name, ok := strings.Cut(strings.TrimSuffix(base, ".txt"), "_"); ok && sameName(name, j.Name) {
+				jobFile = f
+			}
+			continue
+		}
+		if step != nil && sameName(strings.TrimSuffix(dir, "/"), j.Name) && strings.HasPrefix(base, strconv.Itoa(step.Number)+"_") {
+			stepFile = f
+		}
+	}
+
+	file, toError := stepFile, false
+	if file == nil {
+		file, toError = jobFile, true
+	}
+	if file == nil {
+		return result, fmt.Errorf("log archive has no log for job %s", j.Name)
+	}
+	rc, err := file.Open()
+	if err != nil {
+		return result, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return result, err
+	}
+
+	all := cleanLog(string(data))
+	result.Lines = len(all)
+	if toError {
+		// Cleanup steps follow the failure; the last error is what broke
+		for i := len(all) - 1; i >= 0; i-- {
+			if strings.Contains(all[i], "##[error]") {
+				all = all[:i+1]
+				break
+			}
+		}
+	}
+	result.Log, result.Truncated = logtail.Tail(all, lines, maxLogBytes)
+	return result, nil
+}
+
+// cleanLog splits a log into lines without timestamps or color codes
+func cleanLog(s string) []string {
+	s = strings.TrimPrefix(s, "\ufeff")
+	lines := strings.Split(strings.TrimRight(s, "\r\n"), "\n")
+	for i, line := range lines {
+		line = logTimestamp.ReplaceAllString(strings.TrimRight(line, "\r"), "")
+		lines[i] = ansiEscape.ReplaceAllString(line, "")
+	}
+	return lines
+}
+
+// sameName compares a job name with a file name made from it, which may
+// have had characters such as / and : dropped or replaced
+func sameName(file, job string) bool {
+	norm := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				return unicode.ToLower(r)
+			}
+			return -1
+		}, s)
+	}
+	return norm(file) == norm(job)
+}
+
+func toRun(r map[string]any) Run {
+	run := Run{
+		ID:         getInt(r, "id"),
+		Workflow:   getString(r, "name"),
+		Title:      truncate(getString(r, "display_title"), 120),
+		Branch:     getString(r, "head_branch"),
+		Event:      getString(r, "event"),
+		Status:     getString(r, "status"),
+		Conclusion: getString(r, "conclusion"),
+		Attempt:    getInt(r, "run_attempt"),
+		Age:        parseTimeAgo(getString(r, "created_at")),
+		URL:        getString(r, "html_url"),
+	}
+	if sha := getString(r, "head_sha"); len(sha) > 7 {
+		run.SHA = sha[:7]
+	} else {
+		run.SHA = sha
+	}
+	if actor, ok := r["triggering_actor"].(map[string]any); ok {
+		run.Actor = getString(actor, "login")
+	}
+	return run
+}
+
+func toJob(j map[string]any) Job {
+	job := Job{
+		ID:         getInt(j, "id"),
+		Name:       getString(j, "name"),
+		Status:     getString(j, "status"),
+		Conclusion: getString(j, "conclusion"),
+		URL:        getString(j, "html_url"),
+	}
+	started, err1 := time.Parse(time.RFC3339, getString(j, "started_at"))
+	completed, err2 := time.Parse(time.RFC3339, getString(j, "completed_at"))
+	if err1 == nil && err2 == nil && !completed.Before(started) {
+		job.Duration = completed.Sub(started).String()
+	}
+
+	steps, _ := j["steps"].([]any)
+	for _, s := range steps {
+		if step, ok := s.(map[string]any); ok {
+			job.Steps = append(job.Steps, JobStep{
+				Number:     getInt(step, "number"),
+				Name:       getString(step, "name"),
+				Status:     getString(step, "status"),
+				Conclusion: getString(step, "conclusion"),
+			})
+		}
+	}
+	return job
+}
@@ -0,0 +1,137 @@
+package github
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// logArchive zips files the way Actions lays out a run's logs
+func logArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func testLog(n int, last string) string {
+	var b strings.Builder
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&b, "2024-05-01T10:00:%02d.1234567Z \x1b[36mline %d\x1b[0m\n", i%60, i)
+	}
+	b.WriteString("2024-05-01T10:01:00.0000000Z " + last + "\n")
+	return b.String()
+}
+
+var testJob = Job{
+	Name:       "test / unit",
+	Conclusion: "failure",
+	Steps: []JobStep{
+		{Number: 1, Name: "Set up job", Conclusion: "success"},
+		{Number: 4, Name: "Run go test", Conclusion: "failure"},
+		{Number: 5, Name: "Post checkout", Conclusion: "success"},
+	},
+}
+
+func TestJobLog(t *testing.T) {
+	archive := logArchive(t, map[string]string{
+		"0_test  unit.txt":             testLog(10, "cleanup done"),
+		"test  unit/1_Set up job.txt":  testLog(3, "ready"),
+		"test  unit/4_Run go test.txt": testLog(200, "##[error]Process completed with exit code 1."),
+	})
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	log, err := jobLog(zr, testJob, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "line 197\nline 198\nline 199\nline 200\n##[error]Process completed with exit code 1."
+	if log.Step != "Run go test" || log.Number != 4 || log.Lines != 201 || !log.Truncated || log.Log != want {
+		t.Errorf("jobLog() = %+v", log)
+	}
+}
+
+func TestJobLogFallsBackToJob(t *testing.T) {
+	// Without step files, the job's log is read up to its last error
+	jobFile := testLog(4, "##[error]boom") + "2024-05-01T10:02:00Z Post job cleanup.\n"
+	archive := logArchive(t, map[string]string{"0_test  unit.txt": jobFile})
+	zr, _ := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+
+	log, err := jobLog(zr, testJob, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if log.Log != "line 4\n##[error]boom" || log.Lines != 6 {
+		t.Errorf("jobLog() = %+v", log)
+	}
+
+	if _, err := jobLog(zr, Job{Name: "lint"}, 2); err == nil {
+		t.Error("a job missing from the archive should fail")
+	}
+}
+
+func TestRunLogsCmd(t *testing.T) {
+	archive := logArchive(t, map[string]string{
+		"test  unit/4_Run go test.txt": testLog(3, "##[error]FAIL"),
+	})
+	jobs := map[string]any{"jobs": []any{
+		map[string]any{"name": "lint", "conclusion": "success"},
+		map[string]any{"name": "test / unit", "conclusion": "failure", "steps": []any{
+			map[string]any{"number": float64(4), "name": "Run go test", "conclusion": "failure"},
+		}},
+	}}
+	fakeAPI(t, map[string]http.HandlerFunc{
+		"GET /repos/o/r/actions/runs/77/jobs": reply(200, jobs),
+		"GET /repos/o/r/actions/runs/77/logs": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write(archive)
+		},
+	})
+
+	resp := run(t, "run-logs", "o/r", "77")
+	list, _ := resp.Data.([]any)
+	if !resp.Success || len(list) != 1 {
+		t.Fatalf("run-logs: %+v", resp)
+	}
+	if log, _ := list[0].(map[string]any); log["job"] != "test / unit" || !strings.HasSuffix(log["log"].(string), "line 3\n##[error]FAIL") {
+		t.Errorf("log = %+v", log)
+	}
+
+	if resp := run(t, "run-logs", "o/r", "77", "--job", "lint"); resp.Success {
+		t.Error("lint has no log in the archive")
+	}
+}
+
+func TestRerunAndCancel(t *testing.T) {
+	calls := fakeAPI(t, map[string]http.HandlerFunc{
+		"POST /repos/o/r/actions/runs/77/rerun-failed-jobs": reply(201, nil),
+		"POST /repos/o/r/actions/runs/77/cancel":            reply(409, map[string]any{"message": "Cannot cancel a workflow run that is completed."}),
+		"GET /repos/o/r/actions/runs/77":                    reply(200, map[string]any{"id": float64(77), "status": "queued", "head_sha": "0123456789abcdef"}),
+	})
+
+	resp := run(t, "rerun", "o/r", "77", "--failed")
+	if data, _ := resp.Data.(map[string]any); !resp.Success || data["status"] != "queued" || data["sha"] != "0123456" {
+		t.Errorf("rerun: %+v", resp)
+	}
+
+	resp = run(t, "cancel", "o/r", "77")
+	if resp.Success || resp.Error.Code != "cancel_failed" || len(*calls) != 3 {
+		t.Errorf("cancel: %+v, calls %+v", resp, *calls)
+	}
+}
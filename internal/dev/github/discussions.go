@@ -0,0 +1,311 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// Discussion is a repository discussion, with its comments when read
+type Discussion struct {
+	Number       int                 `json:"number"`
+	Title        string              `json:"title"`
+	Author       string              `json:"author"`
+	Category     string              `json:"category"`
+	Answered     bool                `json:"answered,omitempty"`
+	Closed       bool                `json:"closed,omitempty"`
+	CommentCount int                 `json:"comment_count"`
+	Age          string              `json:"age"`
+	URL          string              `json:"url"`
+	Body         string              `json:"body,omitempty"`
+	Comments     []DiscussionComment `json:"comments,omitempty"`
+}
+
+// DiscussionComment is a top-level comment or a reply. Its ID is what
+// discussions comment --reply-to takes.
+type DiscussionComment struct {
+	ID      string              `json:"id"`
+	Author  string              `json:"author"`
+	Body    string              `json:"body"`
+	Answer  bool                `json:"answer,omitempty"`
+	Age     string              `json:"age"`
+	URL     string              `json:"url"`
+	Replies []DiscussionComment `json:"replies,omitempty"`
+}
+
+const discussionsQuery = `query($owner: String!, $name: String!, $first: Int!, $after: String, $category: ID) {
+  repository(owner: $owner, name: $name) {
+    discussions(first: $first, after: $after, categoryId: $category, orderBy: {field: UPDATED_AT, direction: DESC}) {
+      pageInfo { hasNextPage endCursor }
+      nodes {
+        number title url createdAt closed isAnswered
+        author { login }
+        category { name }
+        comments { totalCount }
+      }
+    }
+  }
+}`
+
+const discussionCategoriesQuery = `query($owner: String!, $name: String!) {
+  repository(owner: $owner, name: $name) {
+    discussionCategories(first: 50) { nodes { id name slug } }
+  }
+}`
+
+const discussionQuery = `query($owner: String!, $name: String!, $number: Int!, $comments: Int!) {
+  repository(owner: $owner, name: $name) {
+    discussion(number: $number) {
+      id number title body url createdAt closed isAnswered
+      author { login }
+      category { name }
+      comments(first: $comments) {
+        totalCount
+        nodes {
+          id body url createdAt isAnswer
+          author { login }
+          replies(first: 50) { nodes { id body url createdAt author { login } } }
+        }
+      }
+    }
+  }
+}`
+
+const discussionIDQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    discussion(number: $number) { id }
+  }
+}`
+
+const addDiscussionCommentMutation = `mutation($discussion: ID!, $body: String!, $replyTo: ID) {
+  addDiscussionComment(input: {discussionId: $discussion, body: $body, replyToId: $replyTo}) {
+    comment { id body url createdAt isAnswer author { login } }
+  }
+}`
+
+func newDiscussionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "discussions",
+		Short: "List, read and comment on repository discussions",
+	}
+
+	cmd.AddCommand(newDiscussionsListCmd())
+	cmd.AddCommand(newDiscussionsReadCmd())
+	cmd.AddCommand(newDiscussionsCommentCmd())
+
+	return cmd
+}
+
+func newDiscussionsListCmd() *cobra.Command {
+	var category string
+	var page paginate.Options
+
+	cmd := &cobra.Command{
+		Use:   "list [owner/repo]",
+		Short: "List discussions, most recently active first",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			if err := checkRepo(ctx, args[0]); err != nil {
+				return err
+			}
+			owner, name, _ := strings.Cut(args[0], "/")
+
+			vars := map[string]any{"owner": owner, "name": name}
+			if category != "" {
+				id, err := discussionCategory(ctx, token, owner, name, category)
+				if err != nil {
+					return err
+				}
+				vars["category"] = id
+			}
+
+			path := []string{"repository", "discussions"}
+			return ghListGraphQL(ctx, token, discussionsQuery, vars, path, "Repository "+args[0], page, toDiscussion)
+		},
+	}
+
+	cmd.Flags().StringVarP(&category, "category", "c", "", "Only this category (name or slug)")
+	paginate.AddFlags(cmd, &page, 20, 100, "discussions")
+
+	return cmd
+}
+
+func newDiscussionsReadCmd() *cobra.Command {
+	var limit int
+
+	cmd := &cobra.Command{
+		Use:   "read [owner/repo] [number]",
+		Short: "Read a discussion with its comments and replies",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			owner, name, number, err := discussionArgs(ctx, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			limit = max(1, min(limit, 100))
+
+			var data map[string]any
+			vars := map[string]any{"owner": owner, "name": name, "number": number, "comments": limit}
+			if err := ghGraphQL(ctx, token, discussionQuery, vars, &data); err != nil {
+				return printAPIError(ctx, "fetch_failed", err)
+			}
+			d := dig(data, "repository", "discussion")
+			if d == nil {
+				return printAPIError(ctx, "fetch_failed", notFound(fmt.Sprintf("Discussion %s#%d", args[0], number)))
+			}
+
+			discussion := toDiscussion(d)
+			discussion.Body = getString(d, "body")
+			comments, _ := dig(d, "comments")["nodes"].([]any)
+			for _, c := range comments {
+				if comment, ok := c.(map[string]any); ok {
+					discussion.Comments = append(discussion.Comments, toDiscussionComment(comment))
+				}
+			}
+			return output.Print(ctx, discussion)
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", 30, "Comments to include (max 100)")
+
+	return cmd
+}
+
+func newDiscussionsCommentCmd() *cobra.Command {
+	var replyTo string
+
+	cmd := &cobra.Command{
+		Use:   "comment [owner/repo] [number] [body]",
+		Short: "Comment on a discussion (body - reads stdin)",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			owner, name, number, err := discussionArgs(ctx, args[0], args[1])
+			if err != nil {
+				return err
+			}
+			text, err := readBody(ctx, cmd.InOrStdin(), args[2])
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(text) == "" {
+				return output.PrintError(ctx, "invalid_input", "Comment body is empty", nil)
+			}
+
+			var data map[string]any
+			if err := ghGraphQL(ctx, token, discussionIDQuery, map[string]any{"owner": owner, "name": name, "number": number}, &data); err != nil {
+				return printAPIError(ctx, "fetch_failed", err)
+			}
+			id := getString(dig(data, "repository", "discussion"), "id")
+			if id == "" {
+				return printAPIError(ctx, "fetch_failed", notFound(fmt.Sprintf("Discussion %s#%d", args[0], number)))
+			}
+
+			vars := map[string]any{"discussion": id, "body": text}
+			if replyTo != "" {
+				vars["replyTo"] = replyTo
+			}
+			var result map[string]any
+			if err := ghGraphQL(ctx, token, addDiscussionCommentMutation, vars, &result); err != nil {
+				return printAPIError(ctx, "comment_failed", err)
+			}
+			return output.Print(ctx, toDiscussionComment(dig(result, "addDiscussionComment", "comment")))
+		},
+	}
+
+	cmd.Flags().StringVar(&replyTo, "reply-to", "", "ID of the top-level comment to reply to")
+
+	return cmd
+}
+
+// discussionCategory returns the ID of the category named by its name or
+// slug
+func discussionCategory(ctx context.Context, token, owner, name, category string) (string, error) {
+	var data map[string]any
+	if err := ghGraphQL(ctx, token, discussionCategoriesQuery, map[string]any{"owner": owner, "name": name}, &data); err != nil {
+		return "", printAPIError(ctx, "fetch_failed", err)
+	}
+	repo := dig(data, "repository")
+	if repo == nil {
+		return "", printAPIError(ctx, "fetch_failed", notFound("Repository "+owner+"/"+name))
+	}
+
+	nodes, _ := dig(repo, "discussionCategories")["nodes"].([]any)
+	var names []string
+	for _, n := range nodes {
+		c, _ := n.(map[string]any)
+		if strings.EqualFold(getString(c, "name"), category) || getString(c, "slug") == category {
+			return getString(c, "id"), nil
+		}
+		names = append(names, getString(c, "name"))
+	}
+	return "", output.PrintError(ctx, "invalid_input", fmt.Sprintf("Repository has no discussion category %q", category), map[string]any{
+		"categories": names,
+	})
+}
+
+// discussionArgs splits owner/repo and parses the discussion number
+func discussionArgs(ctx context.Context, repo, num string) (owner, name string, number int, err error) {
+	if err := checkRepo(ctx, repo); err != nil {
+		return "", "", 0, err
+	}
+	number, convErr := strconv.Atoi(num)
+	if convErr != nil || number <= 0 {
+		return "", "", 0, output.PrintError(ctx, "invalid_input", "Invalid number: "+num, nil)
+	}
+	owner, name, _ = strings.Cut(repo, "/")
+	return owner, name, number, nil
+}
+
+func toDiscussion(d map[string]any) Discussion {
+	return Discussion{
+		Number:       getInt(d, "number"),
+		Title:        getString(d, "title"),
+		Author:       login(d, "author"),
+		Category:     getString(dig(d, "category"), "name"),
+		Answered:     getBool(d, "isAnswered"),
+		Closed:       getBool(d, "closed"),
+		CommentCount: getInt(dig(d, "comments"), "totalCount"),
+		Age:          parseTimeAgo(getString(d, "createdAt")),
+		URL:          getString(d, "url"),
+	}
+}
+
+func toDiscussionComment(c map[string]any) DiscussionComment {
+	comment := DiscussionComment{
+		ID:     getString(c, "id"),
+		Author: login(c, "author"),
+		Body:   getString(c, "body"),
+		Answer: getBool(c, "isAnswer"),
+		Age:    parseTimeAgo(getString(c, "createdAt")),
+		URL:    getString(c, "url"),
+	}
+	replies, _ := dig(c, "replies")["nodes"].([]any)
+	for _, r := range replies {
+		if reply, ok := r.(map[string]any); ok {
+			comment.Replies = append(comment.Replies, toDiscussionComment(reply))
+		}
+	}
+	return comment
+}
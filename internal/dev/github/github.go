@@ -1,22 +1,31 @@
 package github
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://api.github.com"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Repo is LLM-friendly repo output
 type Repo struct {
@@ -33,15 +42,16 @@ type Repo struct {
 
 // Issue is LLM-friendly issue output
 type Issue struct {
-	Number int      `json:"number"`
-	Title  string   `json:"title"`
-	State  string   `json:"state"`
-	Author string   `json:"author"`
-	Labels []string `json:"labels,omitempty"`
-	Age    string   `json:"age"`
-	URL    string   `json:"url"`
-	IsPR   bool     `json:"is_pr,omitempty"`
-	Body   string   `json:"body,omitempty"`
+	Number    int      `json:"number"`
+	Title     string   `json:"title"`
+	State     string   `json:"state"`
+	Author    string   `json:"author"`
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+	Age       string   `json:"age"`
+	URL       string   `json:"url"`
+	IsPR      bool     `json:"is_pr,omitempty"`
+	Body      string   `json:"body,omitempty"`
 }
 
 // PR is LLM-friendly PR output
@@ -51,7 +61,9 @@ type PR struct {
 	State     string   `json:"state"`
 	Author    string   `json:"author"`
 	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
 	Draft     bool     `json:"draft,omitempty"`
+	Merged    bool     `json:"merged,omitempty"`
 	Mergeable string   `json:"mergeable,omitempty"`
 	Age       string   `json:"age"`
 	URL       string   `json:"url"`
@@ -70,6 +82,22 @@ type Notification struct {
 	URL     string `json:"url"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "github",
+		Name:        "GitHub",
+		Group:       "dev",
+		Description: "Repos, issues, PRs, Actions runs, Projects, Discussions, notifications, and search on GitHub, plus commenting, labeling, reviewing, merging and raw GraphQL",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "github_token", Description: "Personal access token with repo, read:org, notifications scopes (plus project for Projects)", Required: true, Example: "ghp_xxxxxxxxxxxxxxxxxxxx"},
+		},
+		SetupGuide:  "1. Go to https://github.com/settings/tokens\n2. Click 'Generate new token (classic)'\n3. Select scopes: repo, read:org, notifications (add read:project or project to use Projects)\n4. Generate and copy the token\n5. Run: pocket config set github_token <your-token>",
+		TestCommand: "pocket dev github repos -l 1",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "github",
@@ -83,14 +111,26 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(newIssueCmd())
 	cmd.AddCommand(newPRsCmd())
 	cmd.AddCommand(newPRCmd())
+	cmd.AddCommand(newPRFilesCmd())
+	cmd.AddCommand(newPRDiffCmd())
+	cmd.AddCommand(newPRChecksCmd())
+	cmd.AddCommand(newPRReviewsCmd())
+	cmd.AddCommand(newRunsCmd())
+	cmd.AddCommand(newRunCmd())
+	cmd.AddCommand(newRunLogsCmd())
+	cmd.AddCommand(newRerunCmd())
+	cmd.AddCommand(newCancelCmd())
+	cmd.AddCommand(newProjectCmd())
+	cmd.AddCommand(newDiscussionsCmd())
 	cmd.AddCommand(newNotificationsCmd())
 	cmd.AddCommand(newSearchCmd())
+	cmd.AddCommand(newGraphQLCmd())
 
 	return cmd
 }
 
 func newReposCmd() *cobra.Command {
-	var limit int
+	var page paginate.Options
 	var sort string
 	var user string
 
@@ -98,31 +138,24 @@ func newReposCmd() *cobra.Command {
 		Use:   "repos",
 		Short: "List repositories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.MustGet("github_token")
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
 			if err != nil {
 				return err
 			}
 
-			url := fmt.Sprintf("%s/user/repos?per_page=%d&sort=%s", baseURL, limit, sort)
+			url := fmt.Sprintf("%s/user/repos?sort=%s", baseURL, sort)
 			if user != "" {
-				url = fmt.Sprintf("%s/users/%s/repos?per_page=%d&sort=%s", baseURL, user, limit, sort)
-			}
-
-			var repos []map[string]any
-			if err := ghGet(token, url, &repos); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
-			}
-
-			result := make([]Repo, 0, len(repos))
-			for _, r := range repos {
-				result = append(result, toRepo(r))
+				url = fmt.Sprintf("%s/users/%s/repos?sort=%s", baseURL, user, sort)
 			}
 
-			return output.Print(result)
+			return ghList(ctx, token, url, page, func(r map[string]any) (Repo, bool) {
+				return toRepo(r), true
+			})
 		},
 	}
 
-	cmd.Flags().IntVarP(&limit, "limit", "l", 20, "Number of repos")
+	paginate.AddFlags(cmd, &page, 20, 100, "repos")
 	cmd.Flags().StringVarP(&sort, "sort", "s", "updated", "Sort: updated, created, pushed, full_name")
 	cmd.Flags().StringVarP(&user, "user", "u", "", "User (default: authenticated user)")
 
@@ -135,7 +168,8 @@ func newRepoCmd() *cobra.Command {
 		Short: "Get repository details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.MustGet("github_token")
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
 			if err != nil {
 				return err
 			}
@@ -143,11 +177,11 @@ func newRepoCmd() *cobra.Command {
 			url := fmt.Sprintf("%s/repos/%s", baseURL, args[0])
 
 			var repo map[string]any
-			if err := ghGet(token, url, &repo); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := ghGet(ctx, token, url, &repo); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
-			return output.Print(toRepo(repo))
+			return output.Print(ctx, toRepo(repo))
 		},
 	}
 
@@ -157,49 +191,43 @@ func newRepoCmd() *cobra.Command {
 func newIssuesCmd() *cobra.Command {
 	var repo string
 	var state string
-	var limit int
+	var page paginate.Options
 	var labels string
 
 	cmd := &cobra.Command{
 		Use:   "issues",
 		Short: "List issues",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.MustGet("github_token")
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
 			if err != nil {
 				return err
 			}
 
 			var url string
 			if repo != "" {
-				url = fmt.Sprintf("%s/repos/%s/issues?state=%s&per_page=%d", baseURL, repo, state, limit)
+				url = fmt.Sprintf("%s/repos/%s/issues?state=%s", baseURL, repo, state)
 			} else {
-				url = fmt.Sprintf("%s/issues?state=%s&per_page=%d&filter=all", baseURL, state, limit)
+				url = fmt.Sprintf("%s/issues?state=%s&filter=all", baseURL, state)
 			}
 
 			if labels != "" {
 				url += "&labels=" + labels
 			}
 
-			var issues []map[string]any
-			if err := ghGet(token, url, &issues); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
-			}
-
-			result := make([]Issue, 0, len(issues))
-			for _, i := range issues {
+			return ghList(ctx, token, url, page, func(i map[string]any) (Issue, bool) {
 				// Skip PRs when listing issues (GitHub API returns both)
-				if _, ok := i["pull_request"]; !ok {
-					result = append(result, toIssue(i, false))
+				if _, ok := i["pull_request"]; ok {
+					return Issue{}, false
 				}
-			}
-
-			return output.Print(result)
+				return toIssue(i, false), true
+			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&repo, "repo", "r", "", "Repository (owner/name)")
 	cmd.Flags().StringVarP(&state, "state", "s", "open", "State: open, closed, all")
-	cmd.Flags().IntVarP(&limit, "limit", "l", 20, "Number of issues")
+	paginate.AddFlags(cmd, &page, 20, 100, "issues")
 	cmd.Flags().StringVar(&labels, "labels", "", "Filter by labels (comma-separated)")
 
 	return cmd
@@ -211,7 +239,8 @@ func newIssueCmd() *cobra.Command {
 		Short: "Get issue details",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.MustGet("github_token")
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
 			if err != nil {
 				return err
 			}
@@ -219,55 +248,54 @@ func newIssueCmd() *cobra.Command {
 			url := fmt.Sprintf("%s/repos/%s/issues/%s", baseURL, args[0], args[1])
 
 			var issue map[string]any
-			if err := ghGet(token, url, &issue); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := ghGet(ctx, token, url, &issue); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
-			return output.Print(toIssue(issue, true))
+			return output.Print(ctx, toIssue(issue, true))
 		},
 	}
 
+	cmd.AddCommand(newIssueCreateCmd())
+	cmd.AddCommand(newIssueCommentCmd())
+	cmd.AddCommand(newIssueCloseCmd())
+	cmd.AddCommand(newIssueLabelCmd())
+	cmd.AddCommand(newIssueAssignCmd())
+
 	return cmd
 }
 
 func newPRsCmd() *cobra.Command {
 	var repo string
 	var state string
-	var limit int
+	var page paginate.Options
 
 	cmd := &cobra.Command{
 		Use:     "prs",
 		Aliases: []string{"pulls"},
 		Short:   "List pull requests",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.MustGet("github_token")
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
 			if err != nil {
 				return err
 			}
 
 			if repo == "" {
-				return output.PrintError("missing_repo", "Repository required for PRs (use -r owner/repo)", nil)
+				return output.PrintError(ctx, "missing_repo", "Repository required for PRs (use -r owner/repo)", nil)
 			}
 
-			url := fmt.Sprintf("%s/repos/%s/pulls?state=%s&per_page=%d", baseURL, repo, state, limit)
+			url := fmt.Sprintf("%s/repos/%s/pulls?state=%s", baseURL, repo, state)
 
-			var prs []map[string]any
-			if err := ghGet(token, url, &prs); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
-			}
-
-			result := make([]PR, 0, len(prs))
-			for _, p := range prs {
-				result = append(result, toPR(p, false))
-			}
-
-			return output.Print(result)
+			return ghList(ctx, token, url, page, func(p map[string]any) (PR, bool) {
+				return toPR(p, false), true
+			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&repo, "repo", "r", "", "Repository (owner/name) - required")
 	cmd.Flags().StringVarP(&state, "state", "s", "open", "State: open, closed, all")
-	cmd.Flags().IntVarP(&limit, "limit", "l", 20, "Number of PRs")
+	paginate.AddFlags(cmd, &page, 20, 100, "PRs")
 
 	return cmd
 }
@@ -278,7 +306,8 @@ func newPRCmd() *cobra.Command {
 		Short: "Get PR details",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.MustGet("github_token")
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
 			if err != nil {
 				return err
 			}
@@ -286,14 +315,18 @@ func newPRCmd() *cobra.Command {
 			url := fmt.Sprintf("%s/repos/%s/pulls/%s", baseURL, args[0], args[1])
 
 			var pr map[string]any
-			if err := ghGet(token, url, &pr); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := ghGet(ctx, token, url, &pr); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
-			return output.Print(toPR(pr, true))
+			return output.Print(ctx, toPR(pr, true))
 		},
 	}
 
+	cmd.AddCommand(newPRCommentCmd())
+	cmd.AddCommand(newPRReviewCmd())
+	cmd.AddCommand(newPRMergeCmd())
+
 	return cmd
 }
 
@@ -306,7 +339,8 @@ func newNotificationsCmd() *cobra.Command {
 		Aliases: []string{"notifs"},
 		Short:   "List notifications",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.MustGet("github_token")
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
 			if err != nil {
 				return err
 			}
@@ -317,8 +351,8 @@ func newNotificationsCmd() *cobra.Command {
 			}
 
 			var notifs []map[string]any
-			if err := ghGet(token, url, &notifs); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := ghGet(ctx, token, url, &notifs); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			result := make([]Notification, 0, len(notifs))
@@ -326,13 +360,15 @@ func newNotificationsCmd() *cobra.Command {
 				result = append(result, toNotification(n))
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
 	cmd.Flags().IntVarP(&limit, "limit", "l", 30, "Number of notifications")
 	cmd.Flags().BoolVarP(&all, "all", "a", false, "Include read notifications")
 
+	cmd.AddCommand(newMarkReadCmd())
+
 	return cmd
 }
 
@@ -345,7 +381,8 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search GitHub",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := config.MustGet("github_token")
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
 			if err != nil {
 				return err
 			}
@@ -354,8 +391,8 @@ func newSearchCmd() *cobra.Command {
 			url := fmt.Sprintf("%s/search/%s?q=%s&per_page=%d", baseURL, searchType, query, limit)
 
 			var result map[string]any
-			if err := ghGet(token, url, &result); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := ghGet(ctx, token, url, &result); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			items, _ := result["items"].([]any)
@@ -369,7 +406,7 @@ func newSearchCmd() *cobra.Command {
 						repos = append(repos, toRepo(r))
 					}
 				}
-				return output.Print(map[string]any{"total": count, "items": repos})
+				return output.Print(ctx, map[string]any{"total": count, "items": repos})
 
 			case "issues":
 				issues := make([]Issue, 0, len(items))
@@ -378,10 +415,10 @@ func newSearchCmd() *cobra.Command {
 						issues = append(issues, toIssue(i, false))
 					}
 				}
-				return output.Print(map[string]any{"total": count, "items": issues})
+				return output.Print(ctx, map[string]any{"total": count, "items": issues})
 
 			default:
-				return output.Print(map[string]any{"total": count, "items": items})
+				return output.Print(ctx, map[string]any{"total": count, "items": items})
 			}
 		},
 	}
@@ -392,36 +429,291 @@ func newSearchCmd() *cobra.Command {
 	return cmd
 }
 
-func ghGet(token, url string, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+// ghList prints a list endpoint page by page, following its Link headers.
+// convert maps each item and drops those it returns false for.
+func ghList[T any](ctx context.Context, token, first string, opts paginate.Options, convert func(map[string]any) (T, bool)) error {
+	return ghListIn(ctx, token, first, "", opts, convert)
+}
+
+// ghListIn is ghList for endpoints that wrap each page's items in an
+// object, under key
+func ghListIn[T any](ctx context.Context, token, first, key string, opts paginate.Options, convert func(map[string]any) (T, bool)) error {
+	err := paginate.Run(ctx, opts, func(cursor string, size int) (paginate.Page[T], error) {
+		u, err := url.Parse(first)
+		if cursor != "" {
+			u, err = paginate.LinkURL(cursor, baseURL)
+		}
+		if err != nil {
+			return paginate.Page[T]{}, err
+		}
+		q := u.Query()
+		q.Set("per_page", strconv.Itoa(size))
+		u.RawQuery = q.Encode()
+
+		raw, next, err := ghGetItems(ctx, token, u.String(), key)
+		if err != nil {
+			return paginate.Page[T]{}, err
+		}
+		items := make([]T, 0, len(raw))
+		for _, r := range raw {
+			if item, ok := convert(r); ok {
+				items = append(items, item)
+			}
+		}
+		return paginate.Page[T]{Items: items, Next: next}, nil
+	})
+	if err != nil && !output.IsPrinted(err) {
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
+	}
+	return err
+}
+
+func ghGet(ctx context.Context, token, url string, result any) error {
+	_, err := ghGetPage(ctx, token, url, result)
+	return err
+}
+
+// ghGetItems reads one page of a list endpoint, returning its items and
+// the next page's URL. key names the list in pages that wrap it in an
+// object.
+func ghGetItems(ctx context.Context, token, url, key string) ([]map[string]any, string, error) {
+	var items []map[string]any
+	if key == "" {
+		next, err := ghGetPage(ctx, token, url, &items)
+		return items, next, err
+	}
+
+	var wrapped map[string]json.RawMessage
+	next, err := ghGetPage(ctx, token, url, &wrapped)
+	if err == nil && wrapped[key] != nil {
+		err = json.Unmarshal(wrapped[key], &items)
+	}
+	return items, next, err
+}
+
+// ghGetPage is ghGet for list endpoints, also returning the next page's URL
+func ghGetPage(ctx context.Context, token, url string, result any) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "GET", url, http.NoBody)
+	req, err := newRequest(ctx, token, "GET", url, nil)
+	if err != nil {
+		return "", err
+	}
+	header, err := ghDo(req, result)
+	if err != nil {
+		return "", err
+	}
+	return paginate.NextLink(header), nil
+}
+
+// ghFresh is ghGet past the response cache, for reading back what a write
+// just changed
+func ghFresh(ctx context.Context, token, url string, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	req, err := newRequest(ctx, token, "GET", url, nil)
 	if err != nil {
 		return err
 	}
+	req.Header.Set("Cache-Control", "no-cache")
+	_, err = ghDo(req, result)
+	return err
+}
+
+// ghGetRaw reads up to max bytes of a response in the media type accept,
+// such as a diff
+func ghGetRaw(ctx context.Context, token, url, accept string, max int64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := newRequest(ctx, token, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", accept)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return nil, parseError(resp)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, max))
+}
+
+// ghSend sends body as JSON with method, decoding the response into result
+// unless it is nil or the response has no content
+func ghSend(ctx context.Context, token, method, url string, body, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	req, err := newRequest(ctx, token, method, url, data)
+	if err != nil {
+		return err
+	}
+	_, err = ghDo(req, result)
+	return err
+}
+
+func newRequest(ctx context.Context, token, method, url string, body []byte) (*http.Request, error) {
+	var reader io.Reader = http.NoBody
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, err
+	}
 
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
 
+// ghDo sends req and decodes its JSON response into result, returning the
+// response headers. Failures are *apiError.
+func ghDo(req *http.Request, result any) (http.Header, error) {
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		var errResp map[string]any
-		if err := json.NewDecoder(resp.Body).Decode(&errResp); err == nil {
-			if msg, _ := errResp["message"].(string); msg != "" {
-				return fmt.Errorf("%s", msg)
+		return nil, parseError(resp)
+	}
+	if result == nil || resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusResetContent {
+		return resp.Header, nil
+	}
+	return resp.Header, json.NewDecoder(resp.Body).Decode(result)
+}
+
+// apiError is an error response from the GitHub API
+type apiError struct {
+	Status  int
+	Message string
+	// Errors are the field errors of a 422
+	Errors []string
+	// TokenScopes and AcceptedScopes come from the X-OAuth-Scopes and
+	// X-Accepted-OAuth-Scopes headers of classic tokens' responses
+	TokenScopes    []string
+	AcceptedScopes []string
+}
+
+func (e *apiError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = fmt.Sprintf("HTTP %d: %s", e.Status, http.StatusText(e.Status))
+	}
+	if len(e.Errors) > 0 {
+		msg += ": " + strings.Join(e.Errors, "; ")
+	}
+	return msg
+}
+
+// missingScopes returns the scopes the endpoint accepts when the token has
+// none of them
+func (e *apiError) missingScopes() []string {
+	if e.Status != http.StatusForbidden || len(e.AcceptedScopes) == 0 || e.TokenScopes == nil {
+		return nil
+	}
+	for _, s := range e.AcceptedScopes {
+		if slices.Contains(e.TokenScopes, s) {
+			return nil
+		}
+	}
+	return e.AcceptedScopes
+}
+
+func parseError(resp *http.Response) error {
+	e := &apiError{
+		Status:         resp.StatusCode,
+		AcceptedScopes: splitScopes(resp.Header.Get("X-Accepted-OAuth-Scopes")),
+	}
+	if _, ok := resp.Header["X-Oauth-Scopes"]; ok {
+		e.TokenScopes = append([]string{}, splitScopes(resp.Header.Get("X-OAuth-Scopes"))...)
+	}
+
+	var body struct {
+		Message string `json:"message"`
+		// Entries are objects naming a field, or plain strings
+		Errors []any `json:"errors"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&body) == nil {
+		e.Message = body.Message
+		for _, item := range body.Errors {
+			switch v := item.(type) {
+			case string:
+				e.Errors = append(e.Errors, v)
+			case map[string]any:
+				if msg := getString(v, "message"); msg != "" {
+					e.Errors = append(e.Errors, msg)
+				} else {
+					e.Errors = append(e.Errors, strings.TrimPrefix(getString(v, "field")+" "+getString(v, "code"), " "))
+				}
 			}
 		}
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
 	}
+	return e
+}
 
-	return json.NewDecoder(resp.Body).Decode(result)
+func splitScopes(header string) []string {
+	var scopes []string
+	for _, s := range strings.Split(header, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// printAPIError prints a failed call: missing token scopes and other
+// 403s, 422 validation errors and GraphQL errors get their own codes with
+// what to fix; anything else is printed with code
+func printAPIError(ctx context.Context, code string, err error) error {
+	var gqlErr *graphqlError
+	if errors.As(err, &gqlErr) {
+		return gqlErr.print(ctx, code)
+	}
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		return output.PrintErr(ctx, code, err, nil)
+	}
+
+	switch {
+	case apiErr.missingScopes() != nil:
+		return output.PrintError(ctx, "insufficient_scope", fmt.Sprintf("GitHub token needs the %s scope", strings.Join(apiErr.AcceptedScopes, " or ")), map[string]any{
+			"token_scopes":    apiErr.TokenScopes,
+			"accepted_scopes": apiErr.AcceptedScopes,
+			"setup":           "Add the scope at https://github.com/settings/tokens, then: pocket config set github_token <your-token>",
+		})
+	case apiErr.Status == http.StatusForbidden:
+		return output.PrintErr(ctx, "forbidden", apiErr, map[string]any{
+			"hint": "Fine-grained tokens need write access to the repository's issues or pull requests",
+		})
+	case apiErr.Status == http.StatusUnprocessableEntity:
+		return output.PrintErr(ctx, "validation_failed", apiErr, map[string]any{
+			"errors": apiErr.Errors,
+		})
+	default:
+		return output.PrintErr(ctx, code, apiErr, nil)
+	}
 }
 
 func toRepo(r map[string]any) Repo {
@@ -466,6 +758,8 @@ func toIssue(i map[string]any, includeBody bool) Issue {
 		}
 	}
 
+	issue.Assignees = logins(i["assignees"])
+
 	if created := getString(i, "created_at"); created != "" {
 		issue.Age = parseTimeAgo(created)
 	}
@@ -489,6 +783,7 @@ func toPR(p map[string]any, includeBody bool) PR {
 		Title:  getString(p, "title"),
 		State:  getString(p, "state"),
 		Draft:  getBool(p, "draft"),
+		Merged: getBool(p, "merged"),
 		URL:    getString(p, "html_url"),
 	}
 
@@ -504,6 +799,8 @@ func toPR(p map[string]any, includeBody bool) PR {
 		}
 	}
 
+	pr.Assignees = logins(p["assignees"])
+
 	if created := getString(p, "created_at"); created != "" {
 		pr.Age = parseTimeAgo(created)
 	}
@@ -552,6 +849,18 @@ func toNotification(n map[string]any) Notification {
 	return notif
 }
 
+// logins returns the logins of a list of users
+func logins(users any) []string {
+	list, _ := users.([]any)
+	var result []string
+	for _, u := range list {
+		if user, ok := u.(map[string]any); ok {
+			result = append(result, getString(user, "login"))
+		}
+	}
+	return result
+}
+
 func getString(m map[string]any, key string) string {
 	if v, ok := m[key].(string); ok {
 		return v
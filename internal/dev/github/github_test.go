@@ -1,11 +1,17 @@
 package github
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/testutil"
+	"github.com/unstablemind/pocket/pkg/output"
 )
 
 func TestNewCmd(t *testing.T) {
@@ -18,8 +24,8 @@ func TestNewCmd(t *testing.T) {
 		subs[s.Use] = true
 	}
 	// Check that we have the expected number of subcommands
-	if len(subs) != 8 {
-		t.Errorf("expected 8 subcommands, got %d: %v", len(subs), subs)
+	if len(subs) != 20 {
+		t.Errorf("expected 20 subcommands, got %d: %v", len(subs), subs)
 	}
 	// Check key subcommands exist
 	for _, name := range []string{"repos", "issues", "prs", "notifications"} {
@@ -138,6 +144,7 @@ func TestTruncate(t *testing.T) {
 }
 
 func TestGhGetSuccess(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("Authorization") != "Bearer test-token" {
 			t.Errorf("expected Authorization header with Bearer token")
@@ -152,7 +159,7 @@ func TestGhGetSuccess(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	var result map[string]any
-	err := ghGet("test-token", srv.URL+"/test", &result)
+	err := ghGet(ctx, "test-token", srv.URL+"/test", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -174,6 +181,7 @@ func TestGhGetError(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
 			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.statusCode)
 				json.NewEncoder(w).Encode(tt.response)
@@ -181,7 +189,7 @@ func TestGhGetError(t *testing.T) {
 			defer srv.Close()
 
 			var result map[string]any
-			err := ghGet("test-token", srv.URL+"/test", &result)
+			err := ghGet(ctx, "test-token", srv.URL+"/test", &result)
 			if err == nil {
 				t.Error("expected error, got nil")
 			}
@@ -273,3 +281,193 @@ func TestToPR(t *testing.T) {
 		t.Errorf("expected mergeable 'yes', got %q", pr.Mergeable)
 	}
 }
+
+func TestMain(m *testing.M) {
+	testutil.Main(m, func() {
+		if err := config.Set(context.Background(), "github_token", "test-token"); err != nil {
+			panic(err)
+		}
+	})
+}
+
+// apiCall is a request the fake API received
+type apiCall = testutil.Call
+
+// fakeAPI points baseURL at a server for routes keyed by "METHOD /path"
+// and records every call
+func fakeAPI(t *testing.T, routes map[string]http.HandlerFunc) *[]apiCall {
+	t.Helper()
+	url, calls := testutil.FakeAPI(t, "", routes)
+	oldURL := baseURL
+	baseURL = url
+	t.Cleanup(func() { baseURL = oldURL })
+	return calls
+}
+
+var reply = testutil.Reply
+
+// run runs a github command and decodes its response envelope
+func run(t *testing.T, args ...string) output.Response {
+	t.Helper()
+	return testutil.Run(t, NewCmd(), args...)
+}
+
+var testIssue = map[string]any{
+	"number": float64(7), "title": "Crash", "state": "open",
+	"user":      map[string]any{"login": "alice"},
+	"assignees": []any{map[string]any{"login": "bob"}},
+	"labels":    []any{map[string]any{"name": "bug"}},
+}
+
+func TestIssueWrites(t *testing.T) {
+	calls := fakeAPI(t, map[string]http.HandlerFunc{
+		"POST /repos/o/r/issues":                  reply(201, testIssue),
+		"POST /repos/o/r/issues/7/comments":       reply(201, map[string]any{"id": float64(1)}),
+		"GET /repos/o/r/issues/7":                 reply(200, testIssue),
+		"PATCH /repos/o/r/issues/7":               reply(200, map[string]any{"number": float64(7), "state": "closed"}),
+		"POST /repos/o/r/issues/7/labels":         reply(200, []any{}),
+		"DELETE /repos/o/r/issues/7/labels/a%20b": reply(200, []any{}),
+		"POST /repos/o/r/issues/7/assignees":      reply(201, testIssue),
+	})
+
+	resp := run(t, "issue", "create", "o/r", "Crash", "-b", "It crashes", "--label", "bug,p1")
+	if !resp.Success || (*calls)[0].Body["title"] != "Crash" || len((*calls)[0].Body["labels"].([]any)) != 2 {
+		t.Errorf("create: %+v, calls %+v", resp, *calls)
+	}
+
+	// "-" reads the body from the command's input
+	*calls = nil
+	cmd := NewCmd()
+	cmd.SetIn(strings.NewReader("Seen on main"))
+	if resp = testutil.Run(t, cmd, "issue", "comment", "o/r", "7", "-"); !resp.Success || (*calls)[0].Body["body"] != "Seen on main" {
+		t.Errorf("comment -: %+v, calls %+v", resp, *calls)
+	}
+
+	*calls = nil
+	resp = run(t, "issue", "close", "o/r", "7", "--reason", "not_planned", "-c", "Duplicate")
+	if len(*calls) != 2 || (*calls)[0].Body["body"] != "Duplicate" || (*calls)[1].Body["state_reason"] != "not_planned" {
+		t.Errorf("close calls = %+v", *calls)
+	}
+	if data, _ := resp.Data.(map[string]any); data["state"] != "closed" {
+		t.Errorf("close: %+v", resp)
+	}
+
+	*calls = nil
+	run(t, "issue", "label", "o/r", "7", "a b", "--remove")
+	if len(*calls) != 2 || (*calls)[1].Method != "GET" {
+		t.Errorf("label --remove calls = %+v", *calls)
+	}
+
+	// carol isn't in the returned assignees, so GitHub skipped her
+	resp = run(t, "issue", "assign", "o/r", "7", "bob", "carol")
+	if resp.Success || resp.Error.Code != "assign_failed" || !strings.Contains(resp.Error.Message, "carol") {
+		t.Errorf("assign: %+v", resp)
+	}
+
+	resp = run(t, "issue", "comment", "o/r", "x", "hi")
+	if resp.Success || resp.Error.Code != "invalid_input" {
+		t.Errorf("bad number: %+v", resp)
+	}
+}
+
+func TestPRWrites(t *testing.T) {
+	pr := map[string]any{"number": float64(9), "state": "closed", "merged": true}
+	calls := fakeAPI(t, map[string]http.HandlerFunc{
+		"POST /repos/o/r/issues/9/comments": reply(201, map[string]any{}),
+		"POST /repos/o/r/pulls/9/reviews":   reply(200, map[string]any{}),
+		"PUT /repos/o/r/pulls/9/merge":      reply(200, map[string]any{"merged": true}),
+		"GET /repos/o/r/pulls/9":            reply(200, pr),
+	})
+
+	resp := run(t, "pr", "review", "o/r", "9", "request-changes")
+	if resp.Success || resp.Error.Code != "invalid_input" || len(*calls) != 0 {
+		t.Errorf("request-changes without a body: %+v", resp)
+	}
+
+	resp = run(t, "pr", "review", "o/r", "9", "approve")
+	if !resp.Success || (*calls)[0].Body["event"] != "APPROVE" {
+		t.Errorf("approve: %+v, calls %+v", resp, *calls)
+	}
+
+	*calls = nil
+	resp = run(t, "pr", "merge", "o/r", "9", "-m", "squash", "--sha", "abc")
+	data, _ := resp.Data.(map[string]any)
+	if !resp.Success || data["merged"] != true || (*calls)[0].Body["merge_method"] != "squash" || (*calls)[0].Body["sha"] != "abc" {
+		t.Errorf("merge: %+v, calls %+v", resp, *calls)
+	}
+
+	*calls = nil
+	run(t, "pr", "comment", "o/r", "9", "LGTM")
+	if len(*calls) != 2 || (*calls)[0].Path != "/repos/o/r/issues/9/comments" {
+		t.Errorf("comment calls = %+v", *calls)
+	}
+}
+
+func TestMarkRead(t *testing.T) {
+	calls := fakeAPI(t, map[string]http.HandlerFunc{
+		"PATCH /notifications/threads/42": reply(205, nil),
+		"GET /notifications/threads/42":   reply(200, map[string]any{"id": "42", "unread": false}),
+		"PUT /notifications":              reply(205, nil),
+	})
+
+	resp := run(t, "notifications", "mark-read", "42")
+	if list, _ := resp.Data.([]any); !resp.Success || len(list) != 1 {
+		t.Errorf("mark-read 42: %+v", resp)
+	}
+	if resp := run(t, "notifications", "mark-read", "--all"); !resp.Success || (*calls)[2].Body["last_read_at"] == nil {
+		t.Errorf("mark-read --all: %+v, calls %+v", resp, *calls)
+	}
+	if resp := run(t, "notifications", "mark-read"); resp.Success {
+		t.Error("mark-read needs IDs or --all")
+	}
+}
+
+func TestWriteErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		header   map[string]string
+		body     map[string]any
+		wantCode string
+		wantMsg  string
+	}{
+		{
+			"insufficient scope", 403,
+			map[string]string{"X-OAuth-Scopes": "read:org, notifications", "X-Accepted-OAuth-Scopes": "repo"},
+			map[string]any{"message": "Resource not accessible by personal access token"},
+			"insufficient_scope", "repo scope",
+		},
+		{
+			"forbidden", 403,
+			map[string]string{"X-OAuth-Scopes": "repo", "X-Accepted-OAuth-Scopes": "repo"},
+			map[string]any{"message": "Must have admin rights to Repository."},
+			"forbidden", "admin rights",
+		},
+		{
+			"validation", 422, nil,
+			map[string]any{"message": "Validation Failed", "errors": []any{
+				map[string]any{"resource": "Issue", "field": "title", "code": "missing_field"},
+				"Can not request changes on your own pull request",
+			}},
+			"validation_failed", "title missing_field; Can not request changes",
+		},
+		{"other", 500, nil, map[string]any{}, "server_error", "HTTP 500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeAPI(t, map[string]http.HandlerFunc{
+				"POST /repos/o/r/issues": func(w http.ResponseWriter, r *http.Request) {
+					for k, v := range tt.header {
+						w.Header().Set(k, v)
+					}
+					reply(tt.status, tt.body)(w, r)
+				},
+			})
+			resp := run(t, "issue", "create", "o/r", "title")
+			if resp.Success || resp.Error.Code != tt.wantCode || !strings.Contains(resp.Error.Message, tt.wantMsg) {
+				t.Errorf("got %+v", resp.Error)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+var (
+	// graphqlMutation matches a mutation at the start of the document or
+	// after an earlier definition's closing brace
+	graphqlMutation = regexp.MustCompile(`(^|\})\s*mutation\b`)
+	graphqlComment  = regexp.MustCompile(`#[^\n]*`)
+)
+
+// graphqlError is the errors list of a GraphQL response
+type graphqlError struct {
+	Errors []graphqlErrorItem
+	// Data is whatever the query returned despite the errors
+	Data json.RawMessage
+}
+
+type graphqlErrorItem struct {
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty"`
+}
+
+func (e *graphqlError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, item := range e.Errors {
+		msgs[i] = item.Message
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// print reports the errors under a code for their type, or code
+func (e *graphqlError) print(ctx context.Context, code string) error {
+	details := map[string]any{"errors": e.Errors}
+	if len(e.Data) > 0 && string(e.Data) != "null" {
+		details["data"] = e.Data
+	}
+	switch e.Errors[0].Type {
+	case "INSUFFICIENT_SCOPES":
+		details["setup"] = "Add the scope at https://github.com/settings/tokens, then: pocket config set github_token <your-token>"
+		return output.PrintErr(ctx, "insufficient_scope", e, details)
+	case "NOT_FOUND":
+		return output.PrintErr(ctx, "not_found", e, details)
+	case "FORBIDDEN":
+		return output.PrintErr(ctx, "forbidden", e, details)
+	}
+	return output.PrintErr(ctx, code, e, details)
+}
+
+// notFound is the error for an object a query came back without
+func notFound(what string) error {
+	return &graphqlError{Errors: []graphqlErrorItem{{Type: "NOT_FOUND", Message: what + " not found"}}}
+}
+
+func newGraphQLCmd() *cobra.Command {
+	var vars []string
+	var varsJSON string
+
+	cmd := &cobra.Command{
+		Use:   "graphql [query]",
+		Short: "Run a GitHub GraphQL query or mutation",
+		Long:  "Run a query or mutation against the GitHub GraphQL API and print its data. The query is read from stdin when it is \"-\". Variables are set with --var name=value, where values that parse as JSON (numbers, booleans, objects) are passed as such, or all at once with --vars '{...}'. Queries run under --dry-run; mutations are planned.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			query, err := readBody(ctx, cmd.InOrStdin(), args[0])
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(query) == "" {
+				return output.PrintError(ctx, "invalid_input", "Query is empty", nil)
+			}
+
+			variables := map[string]any{}
+			if varsJSON != "" {
+				if err := json.Unmarshal([]byte(varsJSON), &variables); err != nil {
+					return output.PrintError(ctx, "invalid_input", "--vars must be a JSON object: "+err.Error(), nil)
+				}
+			}
+			for _, kv := range vars {
+				name, value, ok := strings.Cut(kv, "=")
+				if !ok || name == "" {
+					return output.PrintError(ctx, "invalid_input", "--var takes name=value, got "+kv, nil)
+				}
+				var v any
+				if json.Unmarshal([]byte(value), &v) != nil {
+					v = value
+				}
+				variables[name] = v
+			}
+
+			var data json.RawMessage
+			if err := ghGraphQL(ctx, token, query, variables, &data); err != nil {
+				return printAPIError(ctx, "query_failed", err)
+			}
+			return output.Print(ctx, data)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Variable as name=value (repeatable)")
+	cmd.Flags().StringVar(&varsJSON, "vars", "", "Variables as a JSON object")
+
+	return cmd
+}
+
+// ghGraphQL runs a GraphQL document and decodes its data into result.
+// Queries are marked read-only so they still run under --dry-run.
+func ghGraphQL(ctx context.Context, token, query string, variables map[string]any, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+	if !isMutation(query) {
+		ctx = dryrun.ReadOnly(ctx)
+	}
+
+	payload := map[string]any{"query": query}
+	if len(variables) > 0 {
+		payload["variables"] = variables
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	req, err := newRequest(ctx, token, "POST", baseURL+"/graphql", body)
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Data   json.RawMessage    `json:"data"`
+		Errors []graphqlErrorItem `json:"errors"`
+	}
+	if _, err := ghDo(req, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		return &graphqlError{Errors: resp.Errors, Data: resp.Data}
+	}
+	if result == nil || len(resp.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, result)
+}
+
+// isMutation reports whether a GraphQL document holds a mutation
+func isMutation(query string) bool {
+	return graphqlMutation.MatchString(graphqlComment.ReplaceAllString(query, ""))
+}
+
+// ghListGraphQL prints a GraphQL connection page by page. query takes
+// $first and $after besides vars, and path leads from its data to the
+// connection; what names the object holding it when that is missing.
+func ghListGraphQL[T any](ctx context.Context, token, query string, vars map[string]any, path []string, what string, opts paginate.Options, convert func(map[string]any) T) error {
+	err := paginate.Run(ctx, opts, func(cursor string, size int) (paginate.Page[T], error) {
+		v := maps.Clone(vars)
+		v["first"] = size
+		if cursor != "" {
+			v["after"] = cursor
+		}
+		var data map[string]any
+		if err := ghGraphQL(ctx, token, query, v, &data); err != nil {
+			return paginate.Page[T]{}, err
+		}
+		conn := dig(data, path...)
+		if conn == nil {
+			return paginate.Page[T]{}, notFound(what)
+		}
+
+		nodes, _ := conn["nodes"].([]any)
+		items := make([]T, 0, len(nodes))
+		for _, n := range nodes {
+			if node, ok := n.(map[string]any); ok {
+				items = append(items, convert(node))
+			}
+		}
+		next := ""
+		if info := dig(conn, "pageInfo"); getBool(info, "hasNextPage") {
+			next = getString(info, "endCursor")
+		}
+		return paginate.Page[T]{Items: items, Next: next}, nil
+	})
+	if err != nil && !output.IsPrinted(err) {
+		return printAPIError(ctx, "fetch_failed", err)
+	}
+	return err
+}
+
+// dig follows keys through nested objects, returning nil where one is
+// missing
+func dig(m map[string]any, keys ...string) map[string]any {
+	for _, k := range keys {
+		next, ok := m[k].(map[string]any)
+		if !ok {
+			return nil
+		}
+		m = next
+	}
+	return m
+}
+
+// login returns the login of the user under key
+func login(m map[string]any, key string) string {
+	return getString(dig(m, key), "login")
+}
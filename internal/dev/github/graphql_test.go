@@ -0,0 +1,159 @@
+package github
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeGraphQL answers each POST /graphql with the reply whose key the
+// query contains, and records the calls
+func fakeGraphQL(t *testing.T, replies map[string]any) *[]apiCall {
+	t.Helper()
+	var calls *[]apiCall
+	calls = fakeAPI(t, map[string]http.HandlerFunc{
+		"POST /graphql": func(w http.ResponseWriter, r *http.Request) {
+			query, _ := (*calls)[len(*calls)-1].Body["query"].(string)
+			for name, body := range replies {
+				if strings.Contains(query, name) {
+					reply(200, body)(w, r)
+					return
+				}
+			}
+			t.Errorf("unexpected query %s", query)
+			reply(200, map[string]any{"data": nil})(w, r)
+		},
+	})
+	return calls
+}
+
+func TestIsMutation(t *testing.T) {
+	tests := map[string]bool{
+		`{ viewer { login } }`:                        false,
+		`query { viewer { login } }`:                  false,
+		`query Mutation { viewer { login } }`:         false,
+		"# mutation here\nquery { viewer { login } }": false,
+		"mutation($id: ID!) { closeIssue(input: {issueId: $id}) { clientMutationId } }": true,
+		"  # note\n  mutation { x }":                            true,
+		"fragment F on User { login }\nmutation { x { ...F } }": true,
+	}
+	for query, want := range tests {
+		if got := isMutation(query); got != want {
+			t.Errorf("isMutation(%q) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestGraphQLCmd(t *testing.T) {
+	calls := fakeGraphQL(t, map[string]any{
+		"viewer": map[string]any{"data": map[string]any{"viewer": map[string]any{"login": "me"}}},
+		"repository": map[string]any{
+			"data":   map[string]any{"repository": nil},
+			"errors": []any{map[string]any{"type": "NOT_FOUND", "message": "Could not resolve to a Repository with the name 'o/missing'.", "path": []any{"repository"}}},
+		},
+		"projectV2": map[string]any{
+			"errors": []any{map[string]any{"type": "INSUFFICIENT_SCOPES", "message": "Your token has not been granted the required scopes."}},
+		},
+	})
+
+	resp := run(t, "graphql", "query($n: Int, $flag: Boolean, $s: String) { viewer { login } }",
+		"--var", "n=3", "--var", "flag=true", "--var", "s=abc", "--vars", `{"extra": [1]}`)
+	if data, _ := resp.Data.(map[string]any); !resp.Success || data["viewer"] == nil {
+		t.Errorf("graphql: %+v", resp)
+	}
+	vars, _ := (*calls)[0].Body["variables"].(map[string]any)
+	if vars["n"] != float64(3) || vars["flag"] != true || vars["s"] != "abc" || vars["extra"] == nil {
+		t.Errorf("variables = %+v", vars)
+	}
+
+	if resp := run(t, "graphql", `{ repository(owner: "o", name: "missing") { id } }`); resp.Success || resp.Error.Code != "not_found" {
+		t.Errorf("missing repo: %+v", resp)
+	}
+	if resp := run(t, "graphql", `{ organization(login: "o") { projectV2(number: 1) { id } } }`); resp.Success || resp.Error.Code != "insufficient_scope" {
+		t.Errorf("missing scope: %+v", resp)
+	}
+	if resp := run(t, "graphql", "{}", "--var", "novalue"); resp.Success || resp.Error.Code != "invalid_input" {
+		t.Errorf("bad --var: %+v", resp)
+	}
+}
+
+func TestProjectSetField(t *testing.T) {
+	fields := map[string]any{"data": map[string]any{"repositoryOwner": map[string]any{"projectV2": map[string]any{
+		"id": "PVT_1",
+		"fields": map[string]any{"nodes": []any{
+			map[string]any{"id": "F_title", "name": "Title", "dataType": "TITLE"},
+			map[string]any{"id": "F_status", "name": "Status", "dataType": "SINGLE_SELECT", "options": []any{
+				map[string]any{"id": "opt_todo", "name": "Todo"},
+				map[string]any{"id": "opt_done", "name": "Done"},
+			}},
+			map[string]any{"id": "F_points", "name": "Points", "dataType": "NUMBER"},
+			map[string]any{},
+		}},
+	}}}}
+	item := map[string]any{
+		"id": "PVTI_9", "type": "ISSUE",
+		"content": map[string]any{"number": float64(4), "title": "Fix it", "issueState": "OPEN", "repository": map[string]any{"nameWithOwner": "o/r"}},
+		"fieldValues": map[string]any{"nodes": []any{
+			map[string]any{"text": "Fix it", "field": map[string]any{"name": "Title"}},
+			map[string]any{"name": "Done", "field": map[string]any{"name": "Status"}},
+			map[string]any{},
+		}},
+	}
+	calls := fakeGraphQL(t, map[string]any{
+		"updateProjectV2ItemFieldValue": map[string]any{"data": map[string]any{"updateProjectV2ItemFieldValue": map[string]any{"projectV2Item": item}}},
+		"fields(first":                  fields,
+	})
+
+	resp := run(t, "project", "move", "acme", "3", "PVTI_9", "done")
+	data, _ := resp.Data.(map[string]any)
+	if !resp.Success || data["state"] != "open" || data["repo"] != "o/r" {
+		t.Fatalf("move: %+v", resp)
+	}
+	if f, _ := data["fields"].(map[string]any); len(f) != 1 || f["Status"] != "Done" {
+		t.Errorf("fields = %+v", data["fields"])
+	}
+	vars, _ := (*calls)[1].Body["variables"].(map[string]any)
+	if vars["project"] != "PVT_1" || vars["field"] != "F_status" || vars["value"].(map[string]any)["singleSelectOptionId"] != "opt_done" {
+		t.Errorf("mutation variables = %+v", vars)
+	}
+
+	if resp := run(t, "project", "set-field", "acme", "3", "PVTI_9", "Points", "3.5"); !resp.Success {
+		t.Errorf("set-field: %+v", resp)
+	}
+	for _, args := range [][]string{
+		{"move", "acme", "3", "PVTI_9", "Blocked"},
+		{"set-field", "acme", "3", "PVTI_9", "Points", "many"},
+		{"set-field", "acme", "3", "PVTI_9", "Title", "New"},
+		{"set-field", "acme", "3", "PVTI_9", "Owner", "me"},
+	} {
+		if resp := run(t, append([]string{"project"}, args...)...); resp.Success || resp.Error.Code != "invalid_input" {
+			t.Errorf("%v: %+v", args, resp)
+		}
+	}
+}
+
+func TestDiscussionsRead(t *testing.T) {
+	fakeGraphQL(t, map[string]any{
+		"discussion(number": map[string]any{"data": map[string]any{"repository": map[string]any{"discussion": map[string]any{
+			"number": float64(7), "title": "Ideas", "body": "Thoughts?", "isAnswered": true,
+			"author":   map[string]any{"login": "asker"},
+			"category": map[string]any{"name": "Q&A"},
+			"comments": map[string]any{"totalCount": float64(1), "nodes": []any{
+				map[string]any{"id": "DC_1", "body": "This", "isAnswer": true, "author": map[string]any{"login": "helper"},
+					"replies": map[string]any{"nodes": []any{
+						map[string]any{"id": "DC_2", "body": "Thanks", "author": map[string]any{"login": "asker"}},
+					}}},
+			}},
+		}}}},
+	})
+
+	resp := run(t, "discussions", "read", "o/r", "7")
+	data, _ := resp.Data.(map[string]any)
+	comments, _ := data["comments"].([]any)
+	if !resp.Success || data["category"] != "Q&A" || data["comment_count"] != float64(1) || len(comments) != 1 {
+		t.Fatalf("read: %+v", resp)
+	}
+	if c := comments[0].(map[string]any); c["answer"] != true || len(c["replies"].([]any)) != 1 {
+		t.Errorf("comment = %+v", c)
+	}
+}
@@ -0,0 +1,363 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// ProjectItem is an issue, pull request or draft on a project board
+type ProjectItem struct {
+	ID     string         `json:"id"`
+	Type   string         `json:"type"`
+	Number int            `json:"number,omitempty"`
+	Title  string         `json:"title"`
+	State  string         `json:"state,omitempty"`
+	Repo   string         `json:"repo,omitempty"`
+	URL    string         `json:"url,omitempty"`
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// projectField is a project's field with the choices it accepts
+type projectField struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	DataType string `json:"dataType"`
+	Options  []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"options"`
+	Configuration struct {
+		Iterations []struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		} `json:"iterations"`
+	} `json:"configuration"`
+}
+
+// projectItemFields selects what toProjectItem reads. Issue and PR states
+// are aliased since their enum types differ.
+const projectItemFields = `
+  id
+  type
+  content {
+    ... on Issue { number title issueState: state url repository { nameWithOwner } }
+    ... on PullRequest { number title prState: state url repository { nameWithOwner } }
+    ... on DraftIssue { title }
+  }
+  fieldValues(first: 30) {
+    nodes {
+      ... on ProjectV2ItemFieldTextValue { text field { ... on ProjectV2FieldCommon { name } } }
+      ... on ProjectV2ItemFieldNumberValue { number field { ... on ProjectV2FieldCommon { name } } }
+      ... on ProjectV2ItemFieldDateValue { date field { ... on ProjectV2FieldCommon { name } } }
+      ... on ProjectV2ItemFieldSingleSelectValue { name field { ... on ProjectV2FieldCommon { name } } }
+      ... on ProjectV2ItemFieldIterationValue { title field { ... on ProjectV2FieldCommon { name } } }
+    }
+  }`
+
+const projectItemsQuery = `query($owner: String!, $number: Int!, $first: Int!, $after: String) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        items(first: $first, after: $after) {
+          pageInfo { hasNextPage endCursor }
+          nodes {` + projectItemFields + `
+          }
+        }
+      }
+    }
+  }
+}`
+
+const projectFieldsQuery = `query($owner: String!, $number: Int!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id
+        fields(first: 50) {
+          nodes {
+            ... on ProjectV2FieldCommon { id name dataType }
+            ... on ProjectV2SingleSelectField { options { id name } }
+            ... on ProjectV2IterationField { configuration { iterations { id title } } }
+          }
+        }
+      }
+    }
+  }
+}`
+
+const updateFieldMutation = `mutation($project: ID!, $item: ID!, $field: ID!, $value: ProjectV2FieldValue!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: $value}) {
+    projectV2Item {` + projectItemFields + `
+    }
+  }
+}`
+
+const clearFieldMutation = `mutation($project: ID!, $item: ID!, $field: ID!) {
+  clearProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field}) {
+    projectV2Item {` + projectItemFields + `
+    }
+  }
+}`
+
+var isoDate = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+
+func newProjectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "project",
+		Short: "Read and update Projects (v2) boards",
+		Long:  "Read and update the items of a user's or organization's Projects (v2) board. Projects are named by owner login and number, as in github.com/orgs/<owner>/projects/<number>. The token needs the read:project scope, or project to make changes.",
+	}
+
+	cmd.AddCommand(newProjectItemsCmd())
+	cmd.AddCommand(newProjectMoveCmd())
+	cmd.AddCommand(newProjectSetFieldCmd())
+
+	return cmd
+}
+
+func newProjectItemsCmd() *cobra.Command {
+	var page paginate.Options
+
+	cmd := &cobra.Command{
+		Use:   "items [owner] [number]",
+		Short: "List a project's items with their field values",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			number, err := projectNumber(ctx, args[1])
+			if err != nil {
+				return err
+			}
+
+			vars := map[string]any{"owner": args[0], "number": number}
+			path := []string{"repositoryOwner", "projectV2", "items"}
+			return ghListGraphQL(ctx, token, projectItemsQuery, vars, path, projectName(args[0], number), page, toProjectItem)
+		},
+	}
+
+	paginate.AddFlags(cmd, &page, 30, 100, "items")
+
+	return cmd
+}
+
+func newProjectMoveCmd() *cobra.Command {
+	var field string
+
+	cmd := &cobra.Command{
+		Use:   "move [owner] [number] [item-id] [status]",
+		Short: "Move a project item to another status column",
+		Long:  "Set a project item's Status, the single-select field a board's columns come from. Use --field for boards grouped by another field.",
+		Args:  cobra.ExactArgs(4),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return setProjectField(ctx, args[0], args[1], args[2], field, args[3], false)
+		},
+	}
+
+	cmd.Flags().StringVar(&field, "field", "Status", "Single-select field the board is grouped by")
+
+	return cmd
+}
+
+func newProjectSetFieldCmd() *cobra.Command {
+	var clear bool
+
+	cmd := &cobra.Command{
+		Use:   "set-field [owner] [number] [item-id] [field] [value]",
+		Short: "Set a field on a project item",
+		Long:  "Set a text, number, date (YYYY-MM-DD), single-select or iteration field on a project item. Options and iterations are given by name, and --clear empties the field instead.",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if clear {
+				return cobra.ExactArgs(4)(cmd, args)
+			}
+			return cobra.ExactArgs(5)(cmd, args)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			value := ""
+			if !clear {
+				value = args[4]
+			}
+			return setProjectField(ctx, args[0], args[1], args[2], args[3], value, clear)
+		},
+	}
+
+	cmd.Flags().BoolVar(&clear, "clear", false, "Clear the field instead of setting it")
+
+	return cmd
+}
+
+// setProjectField looks up the project's fields, sets or clears the
+// named one on the item and prints the item
+func setProjectField(ctx context.Context, owner, num, itemID, fieldName, value string, clear bool) error {
+	token, err := config.MustGet(ctx, "github_token")
+	if err != nil {
+		return err
+	}
+	number, err := projectNumber(ctx, num)
+	if err != nil {
+		return err
+	}
+
+	var data struct {
+		Owner *struct {
+			Project *struct {
+				ID     string `json:"id"`
+				Fields struct {
+					Nodes []projectField `json:"nodes"`
+				} `json:"fields"`
+			} `json:"projectV2"`
+		} `json:"repositoryOwner"`
+	}
+	if err := ghGraphQL(ctx, token, projectFieldsQuery, map[string]any{"owner": owner, "number": number}, &data); err != nil {
+		return printAPIError(ctx, "fetch_failed", err)
+	}
+	if data.Owner == nil || data.Owner.Project == nil {
+		return printAPIError(ctx, "fetch_failed", notFound(projectName(owner, number)))
+	}
+	project := data.Owner.Project
+
+	var field *projectField
+	var names []string
+	for i, f := range project.Fields.Nodes {
+		// Fields outside ProjectV2FieldCommon come back empty
+		if f.ID == "" {
+			continue
+		}
+		if strings.EqualFold(f.Name, fieldName) {
+			field = &project.Fields.Nodes[i]
+		}
+		names = append(names, f.Name)
+	}
+	if field == nil {
+		return output.PrintError(ctx, "invalid_input", fmt.Sprintf("Project has no field %q", fieldName), map[string]any{
+			"fields": names,
+		})
+	}
+
+	vars := map[string]any{"project": project.ID, "item": itemID, "field": field.ID}
+	mutation := clearFieldMutation
+	if !clear {
+		v, err := fieldValue(ctx, *field, value)
+		if err != nil {
+			return err
+		}
+		vars["value"] = v
+		mutation = updateFieldMutation
+	}
+
+	var result map[string]any
+	if err := ghGraphQL(ctx, token, mutation, vars, &result); err != nil {
+		return printAPIError(ctx, "update_failed", err)
+	}
+	for _, payload := range result {
+		if p, ok := payload.(map[string]any); ok {
+			return output.Print(ctx, toProjectItem(dig(p, "projectV2Item")))
+		}
+	}
+	return output.PrintError(ctx, "update_failed", "GitHub returned no item", nil)
+}
+
+// fieldValue builds the ProjectV2FieldValue setting field to value
+func fieldValue(ctx context.Context, field projectField, value string) (map[string]any, error) {
+	switch field.DataType {
+	case "TEXT":
+		return map[string]any{"text": value}, nil
+	case "NUMBER":
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, output.PrintError(ctx, "invalid_input", fmt.Sprintf("%s takes a number, got %s", field.Name, value), nil)
+		}
+		return map[string]any{"number": n}, nil
+	case "DATE":
+		if !isoDate.MatchString(value) {
+			return nil, output.PrintError(ctx, "invalid_input", fmt.Sprintf("%s takes a date as YYYY-MM-DD, got %s", field.Name, value), nil)
+		}
+		return map[string]any{"date": value}, nil
+	case "SINGLE_SELECT":
+		names := make([]string, len(field.Options))
+		for i, opt := range field.Options {
+			if strings.EqualFold(opt.Name, value) {
+				return map[string]any{"singleSelectOptionId": opt.ID}, nil
+			}
+			names[i] = opt.Name
+		}
+		return nil, output.PrintError(ctx, "invalid_input", fmt.Sprintf("%s has no option %q", field.Name, value), map[string]any{
+			"options": names,
+		})
+	case "ITERATION":
+		titles := make([]string, len(field.Configuration.Iterations))
+		for i, it := range field.Configuration.Iterations {
+			if strings.EqualFold(it.Title, value) {
+				return map[string]any{"iterationId": it.ID}, nil
+			}
+			titles[i] = it.Title
+		}
+		return nil, output.PrintError(ctx, "invalid_input", fmt.Sprintf("%s has no iteration %q", field.Name, value), map[string]any{
+			"iterations": titles,
+		})
+	default:
+		return nil, output.PrintError(ctx, "invalid_input", fmt.Sprintf("%s is a %s field, which can't be set on a project item", field.Name, strings.ToLower(field.DataType)), nil)
+	}
+}
+
+func projectNumber(ctx context.Context, s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, output.PrintError(ctx, "invalid_input", "Invalid project number: "+s, nil)
+	}
+	return n, nil
+}
+
+func projectName(owner string, number int) string {
+	return fmt.Sprintf("Project %s/%d", owner, number)
+}
+
+func toProjectItem(n map[string]any) ProjectItem {
+	content := dig(n, "content")
+	item := ProjectItem{
+		ID:     getString(n, "id"),
+		Type:   strings.ToLower(getString(n, "type")),
+		Number: getInt(content, "number"),
+		Title:  getString(content, "title"),
+		State:  strings.ToLower(getString(content, "issueState") + getString(content, "prState")),
+		Repo:   getString(dig(content, "repository"), "nameWithOwner"),
+		URL:    getString(content, "url"),
+	}
+
+	values, _ := dig(n, "fieldValues")["nodes"].([]any)
+	for _, v := range values {
+		value, ok := v.(map[string]any)
+		if !ok {
+			continue
+		}
+		name := getString(dig(value, "field"), "name")
+		if name == "" || name == "Title" {
+			continue
+		}
+		for _, key := range []string{"text", "number", "date", "name", "title"} {
+			if x, ok := value[key]; ok && x != nil {
+				if item.Fields == nil {
+					item.Fields = map[string]any{}
+				}
+				item.Fields[name] = x
+				break
+			}
+		}
+	}
+
+	return item
+}
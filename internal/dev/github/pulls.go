@@ -0,0 +1,486 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// maxDiffSize is the most of a PR's diff that is downloaded
+const maxDiffSize = 20 << 20
+
+// maxListItems caps the lists pr-checks and pr-reviews read in full
+const maxListItems = 1000
+
+// PRFile is a file changed by a PR
+type PRFile struct {
+	File      string `json:"file"`
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	From      string `json:"from,omitempty"`
+}
+
+// DiffFile is one file's part of a PR's unified diff
+type DiffFile struct {
+	File      string `json:"file"`
+	Diff      string `json:"diff"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+// Checks is the CI state of a PR's head commit
+type Checks struct {
+	SHA    string  `json:"sha"`
+	State  string  `json:"state"`
+	Checks []Check `json:"checks"`
+}
+
+// Check is a check run or a commit status
+type Check struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion,omitempty"`
+	Summary    string `json:"summary,omitempty"`
+	URL        string `json:"url,omitempty"`
+}
+
+// Reviews are a PR's reviews and inline comment threads
+type Reviews struct {
+	Reviews []Review `json:"reviews"`
+	Threads []Thread `json:"threads"`
+}
+
+// Review is a submitted review
+type Review struct {
+	Author string `json:"author"`
+	State  string `json:"state"`
+	Body   string `json:"body,omitempty"`
+	Age    string `json:"age,omitempty"`
+}
+
+// Thread is an inline comment and its replies, anchored to a file line
+type Thread struct {
+	File      string `json:"file"`
+	Line      int    `json:"line,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
+	Side      string `json:"side,omitempty"`
+	// Outdated threads are on lines later commits changed; Line is then
+	// where the comment was made
+	Outdated bool            `json:"outdated,omitempty"`
+	Hunk     string          `json:"hunk,omitempty"`
+	Comments []ThreadComment `json:"comments"`
+	URL      string          `json:"url"`
+}
+
+// ThreadComment is one comment of a thread
+type ThreadComment struct {
+	Author string `json:"author"`
+	Body   string `json:"body"`
+	Age    string `json:"age,omitempty"`
+}
+
+func newPRFilesCmd() *cobra.Command {
+	var page paginate.Options
+
+	cmd := &cobra.Command{
+		Use:   "pr-files [owner/repo] [number]",
+		Short: "List the files a PR changes",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			prURL, err := itemURL(ctx, args[0], "pulls", args[1])
+			if err != nil {
+				return err
+			}
+
+			return ghList(ctx, token, prURL+"/files", page, func(f map[string]any) (PRFile, bool) {
+				return PRFile{
+					File:      getString(f, "filename"),
+					Status:    getString(f, "status"),
+					Additions: getInt(f, "additions"),
+					Deletions: getInt(f, "deletions"),
+					From:      getString(f, "previous_filename"),
+				}, true
+			})
+		},
+	}
+
+	paginate.AddFlags(cmd, &page, 100, 100, "files")
+
+	return cmd
+}
+
+func newPRDiffCmd() *cobra.Command {
+	var limit int
+	var maxBytes int
+	var cursor string
+
+	cmd := &cobra.Command{
+		Use:   "pr-diff [owner/repo] [number]",
+		Short: "Get a PR's unified diff, file by file",
+		Long:  "Get a PR's unified diff split by file. A page holds up to --limit files and --max-bytes of diff; a file too big for a page on its own is cut short and marked truncated. Continue with the next_cursor of the response.",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			prURL, err := itemURL(ctx, args[0], "pulls", args[1])
+			if err != nil {
+				return err
+			}
+			if limit < 1 || maxBytes < 1 {
+				return output.PrintError(ctx, "invalid_input", "--limit and --max-bytes must be positive", nil)
+			}
+			// The cursor is the index of the page's first file
+			start := 0
+			if cursor != "" {
+				if start, err = strconv.Atoi(cursor); err != nil || start < 0 {
+					return output.PrintError(ctx, "invalid_cursor", "invalid cursor: pass the next_cursor of an earlier response", nil)
+				}
+			}
+
+			diff, err := ghGetRaw(ctx, token, prURL, "application/vnd.github.diff", maxDiffSize)
+			if err != nil {
+				var apiErr *apiError
+				if errors.As(err, &apiErr) && apiErr.Status == http.StatusNotAcceptable {
+					return output.PrintErr(ctx, "diff_too_large", apiErr, map[string]any{
+						"hint": "List the changed files with pr-files instead",
+					})
+				}
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+
+			files, next := diffPage(splitDiff(string(diff)), start, limit, maxBytes)
+			return output.PrintPage(ctx, files, next)
+		},
+	}
+
+	cmd.Flags().IntVarP(&limit, "limit", "l", 20, "Number of files")
+	cmd.Flags().IntVar(&maxBytes, "max-bytes", 60000, "Most bytes of diff per page")
+	cmd.Flags().StringVar(&cursor, "cursor", "", "Continue from the next_cursor of an earlier response")
+
+	return cmd
+}
+
+func newPRChecksCmd() *cobra.Command {
+	var failing bool
+
+	cmd := &cobra.Command{
+		Use:   "pr-checks [owner/repo] [number]",
+		Short: "Get the check runs and statuses of a PR's head commit",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			prURL, err := itemURL(ctx, args[0], "pulls", args[1])
+			if err != nil {
+				return err
+			}
+
+			var pr map[string]any
+			if err := ghGet(ctx, token, prURL, &pr); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+			head, _ := pr["head"].(map[string]any)
+			sha := getString(head, "sha")
+			commitURL := fmt.Sprintf("%s/repos/%s/commits/%s", baseURL, args[0], sha)
+
+			runs, err := ghAll(ctx, token, commitURL+"/check-runs?per_page=100", "check_runs", maxListItems)
+			if err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+			var combined map[string]any
+			if err := ghGet(ctx, token, commitURL+"/status", &combined); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+
+			checks := make([]Check, 0, len(runs))
+			for _, r := range runs {
+				checks = append(checks, toCheckRun(r))
+			}
+			statuses, _ := combined["statuses"].([]any)
+			for _, s := range statuses {
+				if status, ok := s.(map[string]any); ok {
+					checks = append(checks, toStatus(status))
+				}
+			}
+
+			result := Checks{SHA: sha, State: checksState(checks), Checks: checks}
+			if failing {
+				result.Checks = make([]Check, 0, len(checks))
+				for _, c := range checks {
+					if failed(c.Conclusion) {
+						result.Checks = append(result.Checks, c)
+					}
+				}
+			}
+			return output.Print(ctx, result)
+		},
+	}
+
+	cmd.Flags().BoolVar(&failing, "failing", false, "Only list failed checks")
+
+	return cmd
+}
+
+func newPRReviewsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pr-reviews [owner/repo] [number]",
+		Short: "Get a PR's reviews and inline comment threads",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			prURL, err := itemURL(ctx, args[0], "pulls", args[1])
+			if err != nil {
+				return err
+			}
+
+			reviews, err := ghAll(ctx, token, prURL+"/reviews?per_page=100", "", maxListItems)
+			if err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+			comments, err := ghAll(ctx, token, prURL+"/comments?per_page=100", "", maxListItems)
+			if err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+
+			result := Reviews{Reviews: make([]Review, 0, len(reviews)), Threads: toThreads(comments)}
+			for _, r := range reviews {
+				// Replying to a thread creates an empty COMMENTED review
+				if getString(r, "state") == "COMMENTED" && getString(r, "body") == "" {
+					continue
+				}
+				review := Review{
+					State: getString(r, "state"),
+					Body:  truncate(getString(r, "body"), 500),
+					Age:   parseTimeAgo(getString(r, "submitted_at")),
+				}
+				if user, ok := r["user"].(map[string]any); ok {
+					review.Author = getString(user, "login")
+				}
+				result.Reviews = append(result.Reviews, review)
+			}
+			return output.Print(ctx, result)
+		},
+	}
+
+	return cmd
+}
+
+// ghAll reads every page of a list endpoint, up to max items. key names
+// the list in responses that wrap it in an object.
+func ghAll(ctx context.Context, token, first, key string, max int) ([]map[string]any, error) {
+	var all []map[string]any
+	for next := first; next != "" && len(all) < max; {
+		page, n, err := ghGetItems(ctx, token, next, key)
+		if err != nil {
+			return nil, err
+		}
+		all, next = append(all, page...), n
+	}
+	if len(all) > max {
+		all = all[:max]
+	}
+	return all, nil
+}
+
+// splitDiff cuts a unified diff into its files
+func splitDiff(diff string) []DiffFile {
+	var files []DiffFile
+	var b strings.Builder
+	flush := func() {
+		if b.Len() > 0 {
+			files = append(files, DiffFile{File: diffPath(b.String()), Diff: b.String()})
+			b.Reset()
+		}
+	}
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			flush()
+		}
+		if b.Len() > 0 || strings.HasPrefix(line, "diff --git ") {
+			b.WriteString(line)
+		}
+	}
+	flush()
+	return files
+}
+
+// diffPath returns the file a diff is for, from its +++ line, or its ---
+// line for deletions
+func diffPath(diff string) string {
+	var from string
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++ b/"):
+			return strings.TrimPrefix(line, "+++ b/")
+		case strings.HasPrefix(line, "--- a/"):
+			from = strings.TrimPrefix(line, "--- a/")
+		case strings.HasPrefix(line, "@@"):
+			return from
+		}
+	}
+	if from != "" {
+		return from
+	}
+	// Renames and binary files have no ---/+++ lines
+	header, _, _ := strings.Cut(diff, "\n")
+	if _, b, ok := strings.Cut(header, " b/"); ok {
+		return b
+	}
+	return header
+}
+
+// diffPage returns the files from start that fit in a page of limit files
+// and maxBytes, and the cursor of the rest. A first file bigger than
+// maxBytes is cut at a line boundary.
+func diffPage(files []DiffFile, start, limit, maxBytes int) ([]DiffFile, string) {
+	page := []DiffFile{}
+	size := 0
+	i := start
+	for ; i < len(files) && len(page) < limit; i++ {
+		f := files[i]
+		if size+len(f.Diff) > maxBytes {
+			if len(page) > 0 {
+				break
+			}
+			cut := strings.LastIndex(f.Diff[:maxBytes], "\n") + 1
+			if cut == 0 {
+				cut = maxBytes
+			}
+			f.Diff, f.Truncated = f.Diff[:cut], true
+		}
+		size += len(f.Diff)
+		page = append(page, f)
+	}
+	if i < len(files) {
+		return page, strconv.Itoa(i)
+	}
+	return page, ""
+}
+
+func toCheckRun(r map[string]any) Check {
+	c := Check{
+		Name:       getString(r, "name"),
+		Kind:       "check_run",
+		Status:     getString(r, "status"),
+		Conclusion: getString(r, "conclusion"),
+		URL:        getString(r, "html_url"),
+	}
+	if out, ok := r["output"].(map[string]any); ok {
+		c.Summary = truncate(getString(out, "title"), 200)
+	}
+	return c
+}
+
+func toStatus(s map[string]any) Check {
+	// Statuses have no separate conclusion: pending is the only
+	// unfinished state
+	c := Check{
+		Name:    getString(s, "context"),
+		Kind:    "status",
+		Status:  "completed",
+		Summary: truncate(getString(s, "description"), 200),
+		URL:     getString(s, "target_url"),
+	}
+	if state := getString(s, "state"); state == "pending" {
+		c.Status = "pending"
+	} else {
+		c.Conclusion = state
+	}
+	return c
+}
+
+// failed reports whether a check or job concluded unsuccessfully
+func failed(conclusion string) bool {
+	switch conclusion {
+	case "failure", "error", "timed_out", "cancelled", "action_required", "startup_failure":
+		return true
+	}
+	return false
+}
+
+// checksState sums up checks: failure if any failed, else pending if any
+// is still running, else success
+func checksState(checks []Check) string {
+	state := "success"
+	for _, c := range checks {
+		if failed(c.Conclusion) {
+			return "failure"
+		}
+		if c.Status != "completed" {
+			state = "pending"
+		}
+	}
+	return state
+}
+
+// toThreads groups review comments into threads by the comment they reply
+// to, in the order the threads were started
+func toThreads(comments []map[string]any) []Thread {
+	threads := []Thread{}
+	byID := map[int]int{}
+	for _, c := range comments {
+		comment := ThreadComment{
+			Body: truncate(getString(c, "body"), 1000),
+			Age:  parseTimeAgo(getString(c, "created_at")),
+		}
+		if user, ok := c["user"].(map[string]any); ok {
+			comment.Author = getString(user, "login")
+		}
+
+		if i, ok := byID[getInt(c, "in_reply_to_id")]; ok {
+			threads[i].Comments = append(threads[i].Comments, comment)
+			continue
+		}
+		t := Thread{
+			File:      getString(c, "path"),
+			Line:      getInt(c, "line"),
+			StartLine: getInt(c, "start_line"),
+			Side:      getString(c, "side"),
+			Hunk:      lastLines(getString(c, "diff_hunk"), 6),
+			Comments:  []ThreadComment{comment},
+			URL:       getString(c, "html_url"),
+		}
+		if _, ok := c["line"].(float64); !ok {
+			t.Outdated = true
+			t.Line = getInt(c, "original_line")
+			t.StartLine = getInt(c, "original_start_line")
+		}
+		byID[getInt(c, "id")] = len(threads)
+		threads = append(threads, t)
+	}
+	return threads
+}
+
+// lastLines returns the last n lines of s
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
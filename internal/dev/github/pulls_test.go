@@ -0,0 +1,122 @@
+package github
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+const testDiff = `diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,4 @@
+ package main
++// added
+ func main() {}
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-gone
+diff --git a/a.png b/b.png
+similarity index 100%
+rename from a.png
+rename to b.png
+`
+
+func TestSplitDiff(t *testing.T) {
+	files := splitDiff(testDiff)
+	if len(files) != 3 {
+		t.Fatalf("got %d files: %+v", len(files), files)
+	}
+	for i, want := range []string{"main.go", "old.txt", "b.png"} {
+		if files[i].File != want || !strings.HasPrefix(files[i].Diff, "diff --git ") {
+			t.Errorf("file %d = %+v, want %s", i, files[i], want)
+		}
+	}
+	if strings.Join([]string{files[0].Diff, files[1].Diff, files[2].Diff}, "") != testDiff {
+		t.Error("the files should add up to the diff")
+	}
+}
+
+func TestDiffPage(t *testing.T) {
+	files := splitDiff(testDiff)
+	first := len(files[0].Diff)
+
+	// The second file doesn't fit with the first
+	page, next := diffPage(files, 0, 10, first+10)
+	if len(page) != 1 || page[0].Truncated || next != "1" {
+		t.Errorf("page = %+v, next %q", page, next)
+	}
+
+	// A lone file too big for the page is cut at a line end
+	page, next = diffPage(files, 0, 10, 40)
+	if len(page) != 1 || !page[0].Truncated || len(page[0].Diff) > 40 || !strings.HasSuffix(page[0].Diff, "\n") || next != "1" {
+		t.Errorf("page = %+v, next %q", page, next)
+	}
+
+	page, next = diffPage(files, 1, 1, 1<<20)
+	if len(page) != 1 || page[0].File != "old.txt" || next != "2" {
+		t.Errorf("page = %+v, next %q", page, next)
+	}
+	if page, next = diffPage(files, 2, 10, 1<<20); len(page) != 1 || next != "" {
+		t.Errorf("last page = %+v, next %q", page, next)
+	}
+}
+
+func TestPRDiffCmd(t *testing.T) {
+	fakeAPI(t, map[string]http.HandlerFunc{
+		"GET /repos/o/r/pulls/5": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(testDiff))
+		},
+	})
+	resp := run(t, "pr-diff", "o/r", "5", "-l", "2")
+	if list, _ := resp.Data.([]any); !resp.Success || len(list) != 2 || resp.NextCursor != "2" {
+		t.Errorf("pr-diff: %+v", resp)
+	}
+	if resp := run(t, "pr-diff", "o/r", "5", "--cursor", "2"); resp.NextCursor != "" {
+		t.Errorf("last page: %+v", resp)
+	}
+}
+
+func TestChecksState(t *testing.T) {
+	tests := []struct {
+		checks []Check
+		want   string
+	}{
+		{nil, "success"},
+		{[]Check{{Status: "completed", Conclusion: "success"}, {Status: "completed", Conclusion: "skipped"}}, "success"},
+		{[]Check{{Status: "completed", Conclusion: "success"}, {Status: "in_progress"}}, "pending"},
+		{[]Check{{Status: "in_progress"}, {Status: "completed", Conclusion: "timed_out"}}, "failure"},
+		{[]Check{toStatus(map[string]any{"context": "ci/lint", "state": "error"})}, "failure"},
+		{[]Check{toStatus(map[string]any{"context": "ci/lint", "state": "pending"})}, "pending"},
+	}
+	for i, tt := range tests {
+		if got := checksState(tt.checks); got != tt.want {
+			t.Errorf("%d: checksState() = %q, want %q", i, got, tt.want)
+		}
+	}
+}
+
+func TestToThreads(t *testing.T) {
+	user := map[string]any{"login": "rev"}
+	comments := []map[string]any{
+		{"id": float64(1), "path": "a.go", "line": float64(10), "side": "RIGHT", "body": "Why?", "user": user,
+			"diff_hunk": "@@ -1,8 +1,8 @@\n1\n2\n3\n4\n5\n6\n7"},
+		{"id": float64(2), "path": "b.go", "line": nil, "original_line": float64(3), "body": "Old", "user": user},
+		{"id": float64(3), "in_reply_to_id": float64(1), "body": "Because", "user": map[string]any{"login": "author"}},
+	}
+
+	threads := toThreads(comments)
+	if len(threads) != 2 {
+		t.Fatalf("got %d threads: %+v", len(threads), threads)
+	}
+	if th := threads[0]; th.File != "a.go" || th.Line != 10 || len(th.Comments) != 2 || th.Comments[1].Author != "author" || th.Hunk != "2\n3\n4\n5\n6\n7" {
+		t.Errorf("thread 0 = %+v", th)
+	}
+	if th := threads[1]; !th.Outdated || th.Line != 3 {
+		t.Errorf("thread 1 = %+v", th)
+	}
+}
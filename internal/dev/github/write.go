@@ -0,0 +1,481 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// reviewEvents maps pr review's actions to the API's review events
+var reviewEvents = map[string]string{
+	"approve":         "APPROVE",
+	"request-changes": "REQUEST_CHANGES",
+	"comment":         "COMMENT",
+}
+
+func newIssueCreateCmd() *cobra.Command {
+	var body string
+	var labels []string
+	var assignees []string
+
+	cmd := &cobra.Command{
+		Use:   "create [owner/repo] [title]",
+		Short: "Create an issue",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			if err := checkRepo(ctx, args[0]); err != nil {
+				return err
+			}
+			text, err := readBody(ctx, cmd.InOrStdin(), body)
+			if err != nil {
+				return err
+			}
+
+			req := map[string]any{"title": args[1]}
+			if text != "" {
+				req["body"] = text
+			}
+			if len(labels) > 0 {
+				req["labels"] = labels
+			}
+			if len(assignees) > 0 {
+				req["assignees"] = assignees
+			}
+
+			var issue map[string]any
+			if err := ghSend(ctx, token, "POST", fmt.Sprintf("%s/repos/%s/issues", baseURL, args[0]), req, &issue); err != nil {
+				return printAPIError(ctx, "create_failed", err)
+			}
+			return output.Print(ctx, toIssue(issue, true))
+		},
+	}
+
+	cmd.Flags().StringVarP(&body, "body", "b", "", "Issue body (markdown; - reads stdin)")
+	cmd.Flags().StringSliceVar(&labels, "label", nil, "Labels to add (comma-separated or repeated)")
+	cmd.Flags().StringSliceVar(&assignees, "assignee", nil, "Users to assign (comma-separated or repeated)")
+
+	return cmd
+}
+
+func newIssueCommentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "comment [owner/repo] [number] [body]",
+		Short: "Comment on an issue (body - reads stdin)",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			issueURL, err := itemURL(ctx, args[0], "issues", args[1])
+			if err != nil {
+				return err
+			}
+			text, err := readBody(ctx, cmd.InOrStdin(), args[2])
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(text) == "" {
+				return output.PrintError(ctx, "invalid_input", "Comment body is empty", nil)
+			}
+
+			if err := comment(ctx, token, issueURL, text); err != nil {
+				return printAPIError(ctx, "comment_failed", err)
+			}
+
+			var issue map[string]any
+			if err := ghFresh(ctx, token, issueURL, &issue); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+			return output.Print(ctx, toIssue(issue, true))
+		},
+	}
+
+	return cmd
+}
+
+func newIssueCloseCmd() *cobra.Command {
+	var reason string
+	var note string
+
+	cmd := &cobra.Command{
+		Use:   "close [owner/repo] [number]",
+		Short: "Close an issue",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			issueURL, err := itemURL(ctx, args[0], "issues", args[1])
+			if err != nil {
+				return err
+			}
+			if reason != "completed" && reason != "not_planned" {
+				return output.PrintError(ctx, "invalid_input", "--reason must be completed or not_planned, got "+reason, nil)
+			}
+
+			if note != "" {
+				text, err := readBody(ctx, cmd.InOrStdin(), note)
+				if err != nil {
+					return err
+				}
+				if strings.TrimSpace(text) == "" {
+					return output.PrintError(ctx, "invalid_input", "Comment body is empty", nil)
+				}
+				if err := comment(ctx, token, issueURL, text); err != nil {
+					return printAPIError(ctx, "comment_failed", err)
+				}
+			}
+
+			var issue map[string]any
+			req := map[string]any{"state": "closed", "state_reason": reason}
+			if err := ghSend(ctx, token, "PATCH", issueURL, req, &issue); err != nil {
+				return printAPIError(ctx, "close_failed", err)
+			}
+			return output.Print(ctx, toIssue(issue, true))
+		},
+	}
+
+	cmd.Flags().StringVar(&reason, "reason", "completed", "Why: completed, not_planned")
+	cmd.Flags().StringVarP(&note, "comment", "c", "", "Comment to post before closing (- reads stdin)")
+
+	return cmd
+}
+
+func newIssueLabelCmd() *cobra.Command {
+	var remove bool
+
+	cmd := &cobra.Command{
+		Use:   "label [owner/repo] [number] [label...]",
+		Short: "Add labels to an issue or PR",
+		Args:  cobra.MinimumNArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			issueURL, err := itemURL(ctx, args[0], "issues", args[1])
+			if err != nil {
+				return err
+			}
+
+			labels := args[2:]
+			if remove {
+				// The API removes one label per request
+				for _, l := range labels {
+					if err := ghSend(ctx, token, "DELETE", issueURL+"/labels/"+url.PathEscape(l), nil, nil); err != nil {
+						return printAPIError(ctx, "label_failed", err)
+					}
+				}
+			} else if err := ghSend(ctx, token, "POST", issueURL+"/labels", map[string]any{"labels": labels}, nil); err != nil {
+				return printAPIError(ctx, "label_failed", err)
+			}
+
+			var issue map[string]any
+			if err := ghFresh(ctx, token, issueURL, &issue); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+			return output.Print(ctx, toIssue(issue, true))
+		},
+	}
+
+	cmd.Flags().BoolVar(&remove, "remove", false, "Remove the labels instead")
+
+	return cmd
+}
+
+func newIssueAssignCmd() *cobra.Command {
+	var remove bool
+
+	cmd := &cobra.Command{
+		Use:   "assign [owner/repo] [number] [user...]",
+		Short: "Assign users to an issue or PR",
+		Args:  cobra.MinimumNArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			issueURL, err := itemURL(ctx, args[0], "issues", args[1])
+			if err != nil {
+				return err
+			}
+
+			users := args[2:]
+			method := "POST"
+			if remove {
+				method = "DELETE"
+			}
+			var raw map[string]any
+			if err := ghSend(ctx, token, method, issueURL+"/assignees", map[string]any{"assignees": users}, &raw); err != nil {
+				return printAPIError(ctx, "assign_failed", err)
+			}
+
+			issue := toIssue(raw, true)
+			if !remove {
+				// GitHub silently skips users who can't be assigned
+				var skipped []string
+				for _, u := range users {
+					if !slices.ContainsFunc(issue.Assignees, func(a string) bool { return strings.EqualFold(a, u) }) {
+						skipped = append(skipped, u)
+					}
+				}
+				if len(skipped) > 0 {
+					return output.PrintError(ctx, "assign_failed", "GitHub did not assign "+strings.Join(skipped, ", ")+": assignees need access to the repository", map[string]any{
+						"skipped": skipped,
+						"issue":   issue,
+					})
+				}
+			}
+			return output.Print(ctx, issue)
+		},
+	}
+
+	cmd.Flags().BoolVar(&remove, "remove", false, "Unassign the users instead")
+
+	return cmd
+}
+
+func newPRCommentCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "comment [owner/repo] [number] [body]",
+		Short: "Comment on a PR's conversation (body - reads stdin)",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			prURL, err := itemURL(ctx, args[0], "pulls", args[1])
+			if err != nil {
+				return err
+			}
+			text, err := readBody(ctx, cmd.InOrStdin(), args[2])
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(text) == "" {
+				return output.PrintError(ctx, "invalid_input", "Comment body is empty", nil)
+			}
+
+			// PR conversation comments live on the PR's issue
+			if err := comment(ctx, token, fmt.Sprintf("%s/repos/%s/issues/%s", baseURL, args[0], args[1]), text); err != nil {
+				return printAPIError(ctx, "comment_failed", err)
+			}
+			return printPR(ctx, token, prURL)
+		},
+	}
+
+	return cmd
+}
+
+func newPRReviewCmd() *cobra.Command {
+	var body string
+
+	cmd := &cobra.Command{
+		Use:   "review [owner/repo] [number] [approve|request-changes|comment]",
+		Short: "Approve, request changes on, or comment on a PR",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			prURL, err := itemURL(ctx, args[0], "pulls", args[1])
+			if err != nil {
+				return err
+			}
+			event, ok := reviewEvents[args[2]]
+			if !ok {
+				return output.PrintError(ctx, "invalid_input", "Review must be approve, request-changes or comment, got "+args[2], nil)
+			}
+			text, err := readBody(ctx, cmd.InOrStdin(), body)
+			if err != nil {
+				return err
+			}
+			if text == "" && event != "APPROVE" {
+				return output.PrintError(ctx, "invalid_input", args[2]+" needs a review body (use -b)", nil)
+			}
+
+			req := map[string]any{"event": event}
+			if text != "" {
+				req["body"] = text
+			}
+			if err := ghSend(ctx, token, "POST", prURL+"/reviews", req, nil); err != nil {
+				return printAPIError(ctx, "review_failed", err)
+			}
+			return printPR(ctx, token, prURL)
+		},
+	}
+
+	cmd.Flags().StringVarP(&body, "body", "b", "", "Review body (markdown; - reads stdin)")
+
+	return cmd
+}
+
+func newPRMergeCmd() *cobra.Command {
+	var method string
+	var title string
+	var message string
+	var sha string
+
+	cmd := &cobra.Command{
+		Use:   "merge [owner/repo] [number]",
+		Short: "Merge a PR",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			prURL, err := itemURL(ctx, args[0], "pulls", args[1])
+			if err != nil {
+				return err
+			}
+			if method != "merge" && method != "squash" && method != "rebase" {
+				return output.PrintError(ctx, "invalid_input", "--method must be merge, squash or rebase, got "+method, nil)
+			}
+
+			req := map[string]any{"merge_method": method}
+			if title != "" {
+				req["commit_title"] = title
+			}
+			if message != "" {
+				req["commit_message"] = message
+			}
+			if sha != "" {
+				req["sha"] = sha
+			}
+			// 405 (not mergeable) and 409 (head moved past --sha) carry
+			// GitHub's reason in the message
+			if err := ghSend(ctx, token, "PUT", prURL+"/merge", req, nil); err != nil {
+				return printAPIError(ctx, "merge_failed", err)
+			}
+			return printPR(ctx, token, prURL)
+		},
+	}
+
+	cmd.Flags().StringVarP(&method, "method", "m", "merge", "Merge method: merge, squash, rebase")
+	cmd.Flags().StringVar(&title, "title", "", "Commit title")
+	cmd.Flags().StringVar(&message, "message", "", "Commit message")
+	cmd.Flags().StringVar(&sha, "sha", "", "Only merge if the PR's head is still this commit")
+
+	return cmd
+}
+
+func newMarkReadCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "mark-read [thread-id...]",
+		Short: "Mark notifications as read",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			token, err := config.MustGet(ctx, "github_token")
+			if err != nil {
+				return err
+			}
+			if all == (len(args) > 0) {
+				return output.PrintError(ctx, "invalid_input", "Give notification thread IDs or --all", nil)
+			}
+
+			if all {
+				now := time.Now().UTC().Format(time.RFC3339)
+				if err := ghSend(ctx, token, "PUT", baseURL+"/notifications", map[string]any{"last_read_at": now}, nil); err != nil {
+					return printAPIError(ctx, "mark_read_failed", err)
+				}
+				return output.Print(ctx, map[string]any{"marked_read": "all", "last_read_at": now})
+			}
+
+			result := make([]Notification, 0, len(args))
+			for _, id := range args {
+				if _, err := strconv.ParseUint(id, 10, 64); err != nil {
+					return output.PrintError(ctx, "invalid_input", "Invalid thread ID: "+id, nil)
+				}
+				threadURL := fmt.Sprintf("%s/notifications/threads/%s", baseURL, id)
+				if err := ghSend(ctx, token, "PATCH", threadURL, nil, nil); err != nil {
+					return printAPIError(ctx, "mark_read_failed", err)
+				}
+				var thread map[string]any
+				if err := ghFresh(ctx, token, threadURL, &thread); err != nil {
+					return output.PrintErr(ctx, "fetch_failed", err, nil)
+				}
+				result = append(result, toNotification(thread))
+			}
+			return output.Print(ctx, result)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Mark every notification as read")
+
+	return cmd
+}
+
+// comment posts a comment on the issue or PR at issueURL
+func comment(ctx context.Context, token, issueURL, body string) error {
+	return ghSend(ctx, token, "POST", issueURL+"/comments", map[string]any{"body": body}, nil)
+}
+
+// printPR reads back and prints the PR a write changed
+func printPR(ctx context.Context, token, prURL string) error {
+	var pr map[string]any
+	if err := ghFresh(ctx, token, prURL, &pr); err != nil {
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
+	}
+	return output.Print(ctx, toPR(pr, true))
+}
+
+// itemURL checks an [owner/repo] [number] pair and returns the API URL of
+// the issue or pull request it names
+func itemURL(ctx context.Context, repo, kind, number string) (string, error) {
+	if err := checkRepo(ctx, repo); err != nil {
+		return "", err
+	}
+	if n, err := strconv.Atoi(number); err != nil || n <= 0 {
+		return "", output.PrintError(ctx, "invalid_input", "Invalid number: "+number, nil)
+	}
+	return fmt.Sprintf("%s/repos/%s/%s/%s", baseURL, repo, kind, number), nil
+}
+
+func checkRepo(ctx context.Context, repo string) error {
+	owner, name, ok := strings.Cut(repo, "/")
+	if !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+		return output.PrintError(ctx, "invalid_input", "Repository must be owner/name, got "+repo, nil)
+	}
+	return nil
+}
+
+// readBody returns s, or all of in (the command's stdin) when s is "-"
+func readBody(ctx context.Context, in io.Reader, s string) (string, error) {
+	if s != "-" {
+		return s, nil
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", output.PrintError(ctx, "read_failed", "Failed to read stdin: "+err.Error(), nil)
+	}
+	return string(data), nil
+}
@@ -1,10 +1,18 @@
 package gitlab
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/testutil"
+	"github.com/unstablemind/pocket/pkg/output"
 )
 
 func TestNewCmd(t *testing.T) {
@@ -16,7 +24,7 @@ func TestNewCmd(t *testing.T) {
 	for _, s := range cmd.Commands() {
 		subs[s.Use] = true
 	}
-	for _, name := range []string{"projects", "issues", "mrs", "user"} {
+	for _, name := range []string{"projects", "issues", "mrs", "user", "pipelines", "job-log [id]", "mr [iid]", "mr-approve [iid]"} {
 		if !subs[name] {
 			t.Errorf("missing subcommand %q", name)
 		}
@@ -38,6 +46,7 @@ func TestGitLabClientDoRequest(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
 			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				if r.Header.Get("PRIVATE-TOKEN") == "" {
 					t.Error("expected PRIVATE-TOKEN header")
@@ -53,7 +62,7 @@ func TestGitLabClientDoRequest(t *testing.T) {
 				httpClient: &http.Client{},
 			}
 
-			body, err := client.doRequest("/api/v4/test")
+			body, err := client.doRequest(ctx, "/api/v4/test")
 			if tt.expectErr && err == nil {
 				t.Error("expected error, got nil")
 			}
@@ -68,6 +77,7 @@ func TestGitLabClientDoRequest(t *testing.T) {
 }
 
 func TestProjectsParsing(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		projects := []map[string]any{
 			{
@@ -94,7 +104,7 @@ func TestProjectsParsing(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	body, err := client.doRequest("/api/v4/projects")
+	body, err := client.doRequest(ctx, "/api/v4/projects")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -117,6 +127,7 @@ func TestProjectsParsing(t *testing.T) {
 }
 
 func TestIssuesParsing(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		issues := []map[string]any{
 			{
@@ -146,7 +157,7 @@ func TestIssuesParsing(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	body, err := client.doRequest("/api/v4/issues")
+	body, err := client.doRequest(ctx, "/api/v4/issues")
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -170,3 +181,116 @@ func TestIssuesParsing(t *testing.T) {
 		t.Errorf("expected 2 labels, got %d", len(issues[0].Labels))
 	}
 }
+
+func TestMain(m *testing.M) {
+	testutil.Main(m, func() {
+		if err := config.Set(context.Background(), "gitlab_token", "test-token"); err != nil {
+			panic(err)
+		}
+	})
+}
+
+// fakeAPI points gitlab_url at a server for routes keyed by
+// "METHOD /escaped/path" under /api/v4, and records every call
+func fakeAPI(t *testing.T, routes map[string]http.HandlerFunc) *[]testutil.Call {
+	t.Helper()
+	ctx := context.Background()
+	url, calls := testutil.FakeAPI(t, "/api/v4", routes)
+	if err := config.Set(ctx, "gitlab_url", url); err != nil {
+		t.Fatal(err)
+	}
+	return calls
+}
+
+// run runs a gitlab command and decodes its response envelope
+func run(t *testing.T, args ...string) output.Response {
+	t.Helper()
+	return testutil.Run(t, NewCmd(), args...)
+}
+
+func TestListPaging(t *testing.T) {
+	calls := fakeAPI(t, map[string]http.HandlerFunc{
+		"GET /projects/g%2Fp/issues": func(w http.ResponseWriter, r *http.Request) {
+			page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+			if page < 3 {
+				w.Header().Set("X-Next-Page", strconv.Itoa(page+1))
+			}
+			testutil.Reply(200, []any{
+				map[string]any{"iid": float64(2*page - 1)},
+				map[string]any{"iid": float64(2 * page)},
+			})(w, r)
+		},
+	})
+
+	resp := run(t, "issues", "-p", "g/p", "-l", "3", "--page-size", "2")
+	if list, _ := resp.Data.([]any); !resp.Success || len(list) != 3 || resp.NextCursor == "" {
+		t.Fatalf("first 3: %+v", resp)
+	}
+	if c := (*calls)[1]; c.Query.Get("page") != "2" || c.Query.Get("per_page") != "2" || c.Query.Get("state") != "opened" {
+		t.Errorf("second request query = %v", c.Query)
+	}
+
+	resp = run(t, "issues", "-p", "g/p", "--cursor", resp.NextCursor, "--all")
+	list, _ := resp.Data.([]any)
+	if !resp.Success || len(list) != 3 || resp.NextCursor != "" {
+		t.Fatalf("rest: %+v", resp)
+	}
+	if first := list[0].(map[string]any); first["iid"] != float64(4) {
+		t.Errorf("resumed at %+v", first)
+	}
+}
+
+func TestWrites(t *testing.T) {
+	calls := fakeAPI(t, map[string]http.HandlerFunc{
+		"POST /projects/g%2Fp/issues": testutil.Reply(200, map[string]any{"iid": float64(3), "title": "Flaky test", "state": "opened",
+			"author": map[string]any{"username": "me"}}),
+		"POST /projects/g%2Fp/merge_requests/5/notes": testutil.Reply(200, map[string]any{"id": float64(90), "body": "LGTM",
+			"author": map[string]any{"username": "me"}}),
+		"POST /projects/g%2Fp/merge_requests/5/approve": testutil.Reply(200, map[string]any{"iid": float64(5), "approvals_left": float64(0),
+			"approved_by": []any{map[string]any{"user": map[string]any{"username": "me"}}}}),
+	})
+
+	resp := run(t, "issue-create", "Flaky test", "-p", "g/p", "-d", "Fails on main", "--label", "ci,bug")
+	if data, _ := resp.Data.(map[string]any); !resp.Success || data["iid"] != float64(3) {
+		t.Errorf("issue-create: %+v", resp)
+	}
+	if body := (*calls)[0].Body; body["description"] != "Fails on main" || body["labels"] != "ci,bug" {
+		t.Errorf("issue-create body = %+v", body)
+	}
+
+	if resp := run(t, "mr-comment", "5", "LGTM", "-p", "g/p"); !resp.Success || (*calls)[1].Body["body"] != "LGTM" {
+		t.Errorf("mr-comment: %+v", resp)
+	}
+
+	// "-" reads the body from the command's input
+	cmd := NewCmd()
+	cmd.SetIn(strings.NewReader("LGTM"))
+	if resp := testutil.Run(t, cmd, "mr-comment", "5", "-", "-p", "g/p"); !resp.Success || (*calls)[2].Body["body"] != "LGTM" {
+		t.Errorf("mr-comment -: %+v", resp)
+	}
+
+	resp = run(t, "mr-approve", "5", "-p", "g/p", "--sha", "abc123")
+	if data, _ := resp.Data.(map[string]any); !resp.Success || data["approved_by"].([]any)[0] != "me" {
+		t.Errorf("mr-approve: %+v", resp)
+	}
+	if (*calls)[3].Body["sha"] != "abc123" {
+		t.Errorf("mr-approve body = %+v", (*calls)[3].Body)
+	}
+
+	if resp := run(t, "mr-approve", "5"); resp.Success || resp.Error.Code != "missing_project" {
+		t.Errorf("without -p: %+v", resp)
+	}
+}
+
+func TestTLSSettings(t *testing.T) {
+	ctx := context.Background()
+	fakeAPI(t, map[string]http.HandlerFunc{})
+	t.Cleanup(func() { config.Set(ctx, "gitlab_ca_cert", "") })
+
+	if err := config.Set(ctx, "gitlab_ca_cert", filepath.Join(t.TempDir(), "missing.pem")); err != nil {
+		t.Fatal(err)
+	}
+	if resp := run(t, "user"); resp.Success || resp.Error.Code != "config_error" {
+		t.Errorf("bad CA bundle: %+v", resp)
+	}
+}
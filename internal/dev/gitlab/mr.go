@@ -0,0 +1,230 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+type mrDiff struct {
+	OldPath     string `json:"old_path"`
+	NewPath     string `json:"new_path"`
+	NewFile     bool   `json:"new_file"`
+	RenamedFile bool   `json:"renamed_file"`
+	DeletedFile bool   `json:"deleted_file"`
+	Diff        string `json:"diff"`
+}
+
+type discussion struct {
+	ID             string `json:"id"`
+	IndividualNote bool   `json:"individual_note"`
+	Notes          []struct {
+		ID        int    `json:"id"`
+		Body      string `json:"body"`
+		System    bool   `json:"system"`
+		Resolved  bool   `json:"resolved"`
+		CreatedAt string `json:"created_at"`
+		Author    struct {
+			Username string `json:"username"`
+		} `json:"author"`
+		Position *struct {
+			NewPath string `json:"new_path"`
+			OldPath string `json:"old_path"`
+			NewLine int    `json:"new_line"`
+			OldLine int    `json:"old_line"`
+		} `json:"position"`
+	} `json:"notes"`
+}
+
+func newMRCmd() *cobra.Command {
+	var project string
+	var maxDiff int
+
+	cmd := &cobra.Command{
+		Use:     "mr [iid]",
+		Aliases: []string{"merge-request"},
+		Short:   "Get a merge request with its changes and discussions",
+		Long:    "Get a merge request with the diff of each changed file and its discussion threads. Diffs longer than --max-diff bytes are cut at a line end; --max-diff 0 lists the files only.",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := requireProject(ctx, project); err != nil {
+				return err
+			}
+			client, err := newGitLabClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			base := fmt.Sprintf("/projects/%s/merge_requests/%s", url.PathEscape(project), url.PathEscape(args[0]))
+			body, err := client.doRequest(ctx, base)
+			if err != nil {
+				return output.PrintErr(ctx, "request_failed", err, nil)
+			}
+			var mr struct {
+				ID                  int      `json:"id"`
+				IID                 int      `json:"iid"`
+				Title               string   `json:"title"`
+				Description         string   `json:"description"`
+				State               string   `json:"state"`
+				WebURL              string   `json:"web_url"`
+				SourceBranch        string   `json:"source_branch"`
+				TargetBranch        string   `json:"target_branch"`
+				SHA                 string   `json:"sha"`
+				Labels              []string `json:"labels"`
+				Draft               bool     `json:"draft"`
+				DetailedMergeStatus string   `json:"detailed_merge_status"`
+				HasConflicts        bool     `json:"has_conflicts"`
+				Author              struct {
+					Username string `json:"username"`
+				} `json:"author"`
+				HeadPipeline *struct {
+					ID     int    `json:"id"`
+					Status string `json:"status"`
+				} `json:"head_pipeline"`
+				CreatedAt string `json:"created_at"`
+				UpdatedAt string `json:"updated_at"`
+			}
+			if err := json.Unmarshal(body, &mr); err != nil {
+				return output.PrintErr(ctx, "parse_error", err, nil)
+			}
+
+			body, err = client.doRequest(ctx, base+"/diffs?per_page=100")
+			if err != nil {
+				return output.PrintErr(ctx, "request_failed", err, nil)
+			}
+			var diffs []mrDiff
+			if err := json.Unmarshal(body, &diffs); err != nil {
+				return output.PrintErr(ctx, "parse_error", err, nil)
+			}
+
+			body, err = client.doRequest(ctx, base+"/discussions?per_page=100")
+			if err != nil {
+				return output.PrintErr(ctx, "request_failed", err, nil)
+			}
+			var discussions []discussion
+			if err := json.Unmarshal(body, &discussions); err != nil {
+				return output.PrintErr(ctx, "parse_error", err, nil)
+			}
+
+			result := map[string]any{
+				"id":            mr.ID,
+				"iid":           mr.IID,
+				"title":         mr.Title,
+				"description":   mr.Description,
+				"state":         mr.State,
+				"url":           mr.WebURL,
+				"source_branch": mr.SourceBranch,
+				"target_branch": mr.TargetBranch,
+				"sha":           shortSHA(mr.SHA),
+				"labels":        mr.Labels,
+				"author":        mr.Author.Username,
+				"draft":         mr.Draft,
+				"merge_status":  mr.DetailedMergeStatus,
+				"has_conflicts": mr.HasConflicts,
+				"created_at":    mr.CreatedAt,
+				"updated_at":    mr.UpdatedAt,
+				"changes":       toChanges(diffs, maxDiff),
+				"discussions":   toDiscussions(discussions),
+			}
+			if mr.HeadPipeline != nil {
+				result["pipeline"] = map[string]any{"id": mr.HeadPipeline.ID, "status": mr.HeadPipeline.Status}
+			}
+
+			return output.Print(ctx, result)
+		},
+	}
+
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Project ID or path - required")
+	cmd.Flags().IntVar(&maxDiff, "max-diff", 20000, "Bytes of diff to keep per file (0 lists files only)")
+
+	return cmd
+}
+
+// toChanges summarizes changed files, keeping up to maxDiff bytes of each
+// diff
+func toChanges(diffs []mrDiff, maxDiff int) []map[string]any {
+	changes := make([]map[string]any, len(diffs))
+	for i := range diffs {
+		d := &diffs[i]
+		change := map[string]any{"file": d.NewPath}
+		switch {
+		case d.NewFile:
+			change["status"] = "added"
+		case d.DeletedFile:
+			change["status"] = "deleted"
+			change["file"] = d.OldPath
+		case d.RenamedFile:
+			change["status"] = "renamed"
+			change["old_file"] = d.OldPath
+		default:
+			change["status"] = "modified"
+		}
+		if maxDiff > 0 {
+			diff, cut := cutDiff(d.Diff, maxDiff)
+			change["diff"] = diff
+			if cut {
+				change["truncated"] = true
+			}
+		}
+		changes[i] = change
+	}
+	return changes
+}
+
+// cutDiff shortens diff to at most n bytes, ending at a line end when one
+// fits
+func cutDiff(diff string, n int) (string, bool) {
+	if len(diff) <= n {
+		return diff, false
+	}
+	cut := diff[:n]
+	if i := strings.LastIndexByte(cut, '\n'); i >= 0 {
+		cut = cut[:i+1]
+	}
+	return cut, true
+}
+
+// toDiscussions keeps the threads people wrote, dropping system notes
+// such as "added 1 commit"
+func toDiscussions(discussions []discussion) []map[string]any {
+	result := []map[string]any{}
+	for i := range discussions {
+		d := &discussions[i]
+		var notes []map[string]any
+		thread := map[string]any{"id": d.ID}
+		for _, n := range d.Notes {
+			if n.System {
+				continue
+			}
+			if len(notes) == 0 {
+				if n.Position != nil {
+					thread["file"], thread["line"] = n.Position.NewPath, n.Position.NewLine
+					if n.Position.NewLine == 0 {
+						thread["file"], thread["line"] = n.Position.OldPath, n.Position.OldLine
+					}
+				}
+				if !d.IndividualNote {
+					thread["resolved"] = n.Resolved
+				}
+			}
+			notes = append(notes, map[string]any{
+				"id":         n.ID,
+				"author":     n.Author.Username,
+				"body":       n.Body,
+				"created_at": n.CreatedAt,
+			})
+		}
+		if len(notes) == 0 {
+			continue
+		}
+		thread["notes"] = notes
+		result = append(result, thread)
+	}
+	return result
+}
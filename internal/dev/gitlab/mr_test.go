@@ -0,0 +1,69 @@
+package gitlab
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/unstablemind/pocket/internal/common/testutil"
+)
+
+func TestCutDiff(t *testing.T) {
+	diff := "@@ -1,2 +1,2 @@\n-a\n+b\n"
+	if got, cut := cutDiff(diff, 100); got != diff || cut {
+		t.Errorf("cutDiff() = %q, %v", got, cut)
+	}
+	if got, cut := cutDiff(diff, 19); got != "@@ -1,2 +1,2 @@\n-a\n" || !cut {
+		t.Errorf("cutDiff() at 19 = %q, %v", got, cut)
+	}
+}
+
+func TestMRCmd(t *testing.T) {
+	position := map[string]any{"new_path": "main.go", "old_path": "main.go", "new_line": float64(12)}
+	fakeAPI(t, map[string]http.HandlerFunc{
+		"GET /projects/g%2Fp/merge_requests/5": testutil.Reply(200, map[string]any{
+			"iid": float64(5), "title": "Add cache", "state": "opened", "sha": "0123456789abcdef",
+			"author":        map[string]any{"username": "dev"},
+			"head_pipeline": map[string]any{"id": float64(77), "status": "success"},
+		}),
+		"GET /projects/g%2Fp/merge_requests/5/diffs": testutil.Reply(200, []any{
+			map[string]any{"old_path": "main.go", "new_path": "main.go", "diff": "@@ -1 +1 @@\n-a\n+b\n"},
+			map[string]any{"old_path": "old.go", "new_path": "new.go", "renamed_file": true, "diff": ""},
+			map[string]any{"old_path": "gone.go", "new_path": "gone.go", "deleted_file": true, "diff": strings.Repeat("-x\n", 100)},
+		}),
+		"GET /projects/g%2Fp/merge_requests/5/discussions": testutil.Reply(200, []any{
+			map[string]any{"id": "d1", "individual_note": true, "notes": []any{
+				map[string]any{"id": float64(1), "body": "added 1 commit", "system": true},
+			}},
+			map[string]any{"id": "d2", "notes": []any{
+				map[string]any{"id": float64(2), "body": "Why?", "resolved": true, "author": map[string]any{"username": "rev"}, "position": position},
+				map[string]any{"id": float64(3), "body": "Speed", "author": map[string]any{"username": "dev"}, "position": position},
+			}},
+		}),
+	})
+
+	resp := run(t, "mr", "5", "-p", "g/p", "--max-diff", "20")
+	data, _ := resp.Data.(map[string]any)
+	if !resp.Success || data["sha"] != "01234567" || data["pipeline"].(map[string]any)["status"] != "success" {
+		t.Fatalf("mr: %+v", resp)
+	}
+
+	changes, _ := data["changes"].([]any)
+	if len(changes) != 3 {
+		t.Fatalf("changes = %+v", changes)
+	}
+	if c := changes[1].(map[string]any); c["status"] != "renamed" || c["old_file"] != "old.go" {
+		t.Errorf("renamed change = %+v", c)
+	}
+	if c := changes[2].(map[string]any); c["status"] != "deleted" || c["truncated"] != true || len(c["diff"].(string)) > 20 {
+		t.Errorf("deleted change = %+v", c)
+	}
+
+	discussions, _ := data["discussions"].([]any)
+	if len(discussions) != 1 {
+		t.Fatalf("discussions = %+v", discussions)
+	}
+	if d := discussions[0].(map[string]any); d["file"] != "main.go" || d["line"] != float64(12) || d["resolved"] != true || len(d["notes"].([]any)) != 2 {
+		t.Errorf("discussion = %+v", d)
+	}
+}
@@ -0,0 +1,274 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/logtail"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// maxLogBytes bounds the log job-log prints, whatever --lines asks for
+const maxLogBytes = 16 << 10
+
+var (
+	ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+	// logSection matches the collapsible section markers GitLab runners
+	// write, as in "section_start:1700000000:step_script\r"
+	logSection = regexp.MustCompile(`section_(?:start|end):\d+:[^\r\n]*\r`)
+)
+
+type pipeline struct {
+	ID         int     `json:"id"`
+	IID        int     `json:"iid"`
+	Status     string  `json:"status"`
+	Ref        string  `json:"ref"`
+	SHA        string  `json:"sha"`
+	Source     string  `json:"source"`
+	WebURL     string  `json:"web_url"`
+	Duration   float64 `json:"duration"`
+	StartedAt  string  `json:"started_at"`
+	FinishedAt string  `json:"finished_at"`
+	CreatedAt  string  `json:"created_at"`
+	UpdatedAt  string  `json:"updated_at"`
+	User       struct {
+		Username string `json:"username"`
+	} `json:"user"`
+}
+
+type job struct {
+	ID            int     `json:"id"`
+	Name          string  `json:"name"`
+	Stage         string  `json:"stage"`
+	Status        string  `json:"status"`
+	Duration      float64 `json:"duration"`
+	AllowFailure  bool    `json:"allow_failure"`
+	FailureReason string  `json:"failure_reason"`
+	WebURL        string  `json:"web_url"`
+}
+
+func newPipelinesCmd() *cobra.Command {
+	var project string
+	var status string
+	var ref string
+	var page paginate.Options
+
+	cmd := &cobra.Command{
+		Use:   "pipelines",
+		Short: "List CI pipelines, newest first",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := requireProject(ctx, project); err != nil {
+				return err
+			}
+			client, err := newGitLabClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/pipelines?order_by=id&sort=desc", url.PathEscape(project))
+			if status != "" {
+				endpoint += "&status=" + url.QueryEscape(status)
+			}
+			if ref != "" {
+				endpoint += "&ref=" + url.QueryEscape(ref)
+			}
+
+			return list(ctx, client, endpoint, page, func(p *pipeline) map[string]any {
+				return map[string]any{
+					"id":         p.ID,
+					"iid":        p.IID,
+					"status":     p.Status,
+					"ref":        p.Ref,
+					"sha":        shortSHA(p.SHA),
+					"source":     p.Source,
+					"url":        p.WebURL,
+					"created_at": p.CreatedAt,
+					"updated_at": p.UpdatedAt,
+				}
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Project ID or path - required")
+	cmd.Flags().StringVarP(&status, "status", "s", "", "Filter by status: running, pending, success, failed, canceled, skipped, manual")
+	cmd.Flags().StringVarP(&ref, "ref", "r", "", "Filter by branch or tag")
+	paginate.AddFlags(cmd, &page, 20, 100, "pipelines")
+
+	return cmd
+}
+
+func newPipelineCmd() *cobra.Command {
+	var project string
+
+	cmd := &cobra.Command{
+		Use:   "pipeline [id]",
+		Short: "Get a pipeline with its jobs",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := requireProject(ctx, project); err != nil {
+				return err
+			}
+			client, err := newGitLabClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			base := fmt.Sprintf("/projects/%s/pipelines/%s", url.PathEscape(project), url.PathEscape(args[0]))
+			body, err := client.doRequest(ctx, base)
+			if err != nil {
+				return output.PrintErr(ctx, "request_failed", err, nil)
+			}
+			var p pipeline
+			if err := json.Unmarshal(body, &p); err != nil {
+				return output.PrintErr(ctx, "parse_error", err, nil)
+			}
+
+			body, err = client.doRequest(ctx, base+"/jobs?per_page=100")
+			if err != nil {
+				return output.PrintErr(ctx, "request_failed", err, nil)
+			}
+			var jobs []job
+			if err := json.Unmarshal(body, &jobs); err != nil {
+				return output.PrintErr(ctx, "parse_error", err, nil)
+			}
+
+			jobList := make([]map[string]any, len(jobs))
+			for i := range jobs {
+				j := &jobs[i]
+				jobList[i] = map[string]any{
+					"id":             j.ID,
+					"name":           j.Name,
+					"stage":          j.Stage,
+					"status":         j.Status,
+					"duration":       math.Round(j.Duration),
+					"allow_failure":  j.AllowFailure,
+					"failure_reason": j.FailureReason,
+					"url":            j.WebURL,
+				}
+			}
+
+			return output.Print(ctx, map[string]any{
+				"id":          p.ID,
+				"iid":         p.IID,
+				"status":      p.Status,
+				"ref":         p.Ref,
+				"sha":         shortSHA(p.SHA),
+				"source":      p.Source,
+				"user":        p.User.Username,
+				"duration":    p.Duration,
+				"url":         p.WebURL,
+				"started_at":  p.StartedAt,
+				"finished_at": p.FinishedAt,
+				"jobs":        jobList,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Project ID or path - required")
+
+	return cmd
+}
+
+func newJobLogCmd() *cobra.Command {
+	var project string
+	var lines int
+
+	cmd := &cobra.Command{
+		Use:   "job-log [id]",
+		Short: "Get the end of a CI job's log",
+		Long:  "Print the last lines of a job's log, with colors and section markers stripped. Job IDs are listed by pipeline [id].",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := requireProject(ctx, project); err != nil {
+				return err
+			}
+			if lines < 1 {
+				return output.PrintError(ctx, "invalid_input", "--lines must be positive", nil)
+			}
+			client, err := newGitLabClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			base := fmt.Sprintf("/projects/%s/jobs/%s", url.PathEscape(project), url.PathEscape(args[0]))
+			body, err := client.doRequest(ctx, base)
+			if err != nil {
+				return output.PrintErr(ctx, "request_failed", err, nil)
+			}
+			var j job
+			if err := json.Unmarshal(body, &j); err != nil {
+				return output.PrintErr(ctx, "parse_error", err, nil)
+			}
+
+			trace, err := client.doRequest(ctx, base+"/trace")
+			if err != nil {
+				return output.PrintErr(ctx, "request_failed", err, nil)
+			}
+			logLines := cleanLog(string(trace))
+			log, truncated := logtail.Tail(logLines, lines, maxLogBytes)
+
+			return output.Print(ctx, map[string]any{
+				"id":             j.ID,
+				"name":           j.Name,
+				"stage":          j.Stage,
+				"status":         j.Status,
+				"failure_reason": j.FailureReason,
+				"url":            j.WebURL,
+				"lines":          len(logLines),
+				"truncated":      truncated,
+				"log":            log,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Project ID or path - required")
+	cmd.Flags().IntVarP(&lines, "lines", "n", 100, "Lines to keep from the end of the log")
+
+	return cmd
+}
+
+// cleanLog splits a job trace into lines, dropping color codes, section
+// markers and whatever a carriage return overwrote
+func cleanLog(trace string) []string {
+	trace = logSection.ReplaceAllString(trace, "")
+	trace = ansiEscape.ReplaceAllString(trace, "")
+	trace = strings.TrimRight(trace, "\r\n")
+	if trace == "" {
+		return nil
+	}
+
+	lines := strings.Split(trace, "\n")
+	for i, line := range lines {
+		line = strings.TrimRight(line, "\r")
+		if j := strings.LastIndexByte(line, '\r'); j >= 0 {
+			line = line[j+1:]
+		}
+		lines[i] = line
+	}
+	return lines
+}
+
+func requireProject(ctx context.Context, project string) error {
+	if project == "" {
+		return output.PrintError(ctx, "missing_project", "Project required (use -p group/project)", nil)
+	}
+	return nil
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 8 {
+		return sha[:8]
+	}
+	return sha
+}
@@ -0,0 +1,67 @@
+package gitlab
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/unstablemind/pocket/internal/common/testutil"
+)
+
+func TestCleanLog(t *testing.T) {
+	trace := "\x1b[0KRunning with gitlab-runner 16.5.0\n" +
+		"section_start:1700000000:step_script\r\x1b[0K\x1b[0K\x1b[36;1mExecuting \"step_script\"\x1b[0;m\n" +
+		"Downloading 10%\rDownloading 100%\r\n" +
+		"\x1b[31;1mERROR: Job failed: exit code 1\x1b[0;m\n" +
+		"section_end:1700000042:step_script\r\x1b[0K\n"
+
+	got := cleanLog(trace)
+	want := []string{
+		"Running with gitlab-runner 16.5.0",
+		"Executing \"step_script\"",
+		"Downloading 100%",
+		"ERROR: Job failed: exit code 1",
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("cleanLog() = %q", got)
+	}
+	if cleanLog("") != nil {
+		t.Error("an empty trace has no lines")
+	}
+}
+
+func TestPipelineCmds(t *testing.T) {
+	calls := fakeAPI(t, map[string]http.HandlerFunc{
+		"GET /projects/g%2Fp/pipelines": testutil.Reply(200, []any{
+			map[string]any{"id": float64(77), "status": "failed", "ref": "main", "sha": "0123456789abcdef"},
+		}),
+		"GET /projects/g%2Fp/pipelines/77": testutil.Reply(200, map[string]any{"id": float64(77), "status": "failed", "user": map[string]any{"username": "me"}}),
+		"GET /projects/g%2Fp/pipelines/77/jobs": testutil.Reply(200, []any{
+			map[string]any{"id": float64(901), "name": "test", "stage": "test", "status": "failed", "duration": 61.7, "failure_reason": "script_failure"},
+		}),
+		"GET /projects/g%2Fp/jobs/901":       testutil.Reply(200, map[string]any{"id": float64(901), "name": "test", "status": "failed"}),
+		"GET /projects/g%2Fp/jobs/901/trace": testutil.Text("ok 1\nok 2\nFAIL 3\n"),
+	})
+
+	resp := run(t, "pipelines", "-p", "g/p", "-s", "failed", "-r", "main")
+	if list, _ := resp.Data.([]any); !resp.Success || len(list) != 1 || list[0].(map[string]any)["sha"] != "01234567" {
+		t.Errorf("pipelines: %+v", resp)
+	}
+	if c := (*calls)[0]; c.Path != "/projects/g%2Fp/pipelines" || c.Query.Get("status") != "failed" {
+		t.Errorf("pipelines called %s", c.Path)
+	}
+
+	resp = run(t, "pipeline", "77", "-p", "g/p")
+	data, _ := resp.Data.(map[string]any)
+	if jobs, _ := data["jobs"].([]any); !resp.Success || data["user"] != "me" || len(jobs) != 1 || jobs[0].(map[string]any)["duration"] != float64(62) {
+		t.Errorf("pipeline: %+v", resp)
+	}
+
+	resp = run(t, "job-log", "901", "-p", "g/p", "-n", "2")
+	if data, _ := resp.Data.(map[string]any); !resp.Success || data["log"] != "ok 2\nFAIL 3" || data["lines"] != float64(3) || data["truncated"] != true {
+		t.Errorf("job-log: %+v", resp)
+	}
+	if resp = run(t, "job-log", "901", "-p", "g/p", "-n", "-1"); resp.Success || resp.Error.Code != "invalid_input" {
+		t.Errorf("job-log -n -1: %+v", resp)
+	}
+}
@@ -0,0 +1,221 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+func newIssueCreateCmd() *cobra.Command {
+	var project string
+	var description string
+	var labels []string
+	var confidential bool
+
+	cmd := &cobra.Command{
+		Use:   "issue-create [title]",
+		Short: "Create an issue",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := requireProject(ctx, project); err != nil {
+				return err
+			}
+			client, err := newGitLabClient(ctx)
+			if err != nil {
+				return err
+			}
+			text, err := readBody(ctx, cmd.InOrStdin(), description)
+			if err != nil {
+				return err
+			}
+
+			req := map[string]any{"title": args[0]}
+			if text != "" {
+				req["description"] = text
+			}
+			if len(labels) > 0 {
+				req["labels"] = strings.Join(labels, ",")
+			}
+			if confidential {
+				req["confidential"] = true
+			}
+
+			body, err := client.send(ctx, "POST", fmt.Sprintf("/projects/%s/issues", url.PathEscape(project)), req)
+			if err != nil {
+				return output.PrintErr(ctx, "create_failed", err, nil)
+			}
+
+			var issue struct {
+				ID     int      `json:"id"`
+				IID    int      `json:"iid"`
+				Title  string   `json:"title"`
+				State  string   `json:"state"`
+				WebURL string   `json:"web_url"`
+				Labels []string `json:"labels"`
+				Author struct {
+					Username string `json:"username"`
+				} `json:"author"`
+				CreatedAt string `json:"created_at"`
+			}
+			if err := json.Unmarshal(body, &issue); err != nil {
+				return output.PrintErr(ctx, "parse_error", err, nil)
+			}
+
+			return output.Print(ctx, map[string]any{
+				"id":         issue.ID,
+				"iid":        issue.IID,
+				"title":      issue.Title,
+				"state":      issue.State,
+				"url":        issue.WebURL,
+				"labels":     issue.Labels,
+				"author":     issue.Author.Username,
+				"created_at": issue.CreatedAt,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Project ID or path - required")
+	cmd.Flags().StringVarP(&description, "description", "d", "", "Issue description (markdown; - reads stdin)")
+	cmd.Flags().StringSliceVar(&labels, "label", nil, "Labels to add (comma-separated or repeated)")
+	cmd.Flags().BoolVar(&confidential, "confidential", false, "Only visible to project members")
+
+	return cmd
+}
+
+func newMRCommentCmd() *cobra.Command {
+	var project string
+
+	cmd := &cobra.Command{
+		Use:   "mr-comment [iid] [body]",
+		Short: "Comment on a merge request (body - reads stdin)",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := requireProject(ctx, project); err != nil {
+				return err
+			}
+			client, err := newGitLabClient(ctx)
+			if err != nil {
+				return err
+			}
+			text, err := readBody(ctx, cmd.InOrStdin(), args[1])
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(text) == "" {
+				return output.PrintError(ctx, "invalid_input", "Comment body is empty", nil)
+			}
+
+			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%s/notes", url.PathEscape(project), url.PathEscape(args[0]))
+			body, err := client.send(ctx, "POST", endpoint, map[string]any{"body": text})
+			if err != nil {
+				return output.PrintErr(ctx, "comment_failed", err, nil)
+			}
+
+			var note struct {
+				ID     int    `json:"id"`
+				Body   string `json:"body"`
+				Author struct {
+					Username string `json:"username"`
+				} `json:"author"`
+				CreatedAt string `json:"created_at"`
+			}
+			if err := json.Unmarshal(body, &note); err != nil {
+				return output.PrintErr(ctx, "parse_error", err, nil)
+			}
+
+			return output.Print(ctx, map[string]any{
+				"id":         note.ID,
+				"mr":         args[0],
+				"author":     note.Author.Username,
+				"body":       note.Body,
+				"created_at": note.CreatedAt,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Project ID or path - required")
+
+	return cmd
+}
+
+func newMRApproveCmd() *cobra.Command {
+	var project string
+	var sha string
+
+	cmd := &cobra.Command{
+		Use:   "mr-approve [iid]",
+		Short: "Approve a merge request",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if err := requireProject(ctx, project); err != nil {
+				return err
+			}
+			client, err := newGitLabClient(ctx)
+			if err != nil {
+				return err
+			}
+
+			var req map[string]any
+			if sha != "" {
+				req = map[string]any{"sha": sha}
+			}
+			endpoint := fmt.Sprintf("/projects/%s/merge_requests/%s/approve", url.PathEscape(project), url.PathEscape(args[0]))
+			body, err := client.send(ctx, "POST", endpoint, req)
+			if err != nil {
+				return output.PrintErr(ctx, "approve_failed", err, nil)
+			}
+
+			var approval struct {
+				IID           int `json:"iid"`
+				ApprovalsLeft int `json:"approvals_left"`
+				ApprovedBy    []struct {
+					User struct {
+						Username string `json:"username"`
+					} `json:"user"`
+				} `json:"approved_by"`
+			}
+			if err := json.Unmarshal(body, &approval); err != nil {
+				return output.PrintErr(ctx, "parse_error", err, nil)
+			}
+
+			approvedBy := make([]string, len(approval.ApprovedBy))
+			for i, a := range approval.ApprovedBy {
+				approvedBy[i] = a.User.Username
+			}
+
+			return output.Print(ctx, map[string]any{
+				"iid":            approval.IID,
+				"approved":       true,
+				"approved_by":    approvedBy,
+				"approvals_left": approval.ApprovalsLeft,
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&project, "project", "p", "", "Project ID or path - required")
+	cmd.Flags().StringVar(&sha, "sha", "", "Only approve if this is still the head commit")
+
+	return cmd
+}
+
+// readBody returns s, or all of in (the command's stdin) when s is "-"
+func readBody(ctx context.Context, in io.Reader, s string) (string, error) {
+	if s != "-" {
+		return s, nil
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", output.PrintError(ctx, "read_failed", "Failed to read stdin: "+err.Error(), nil)
+	}
+	return string(data), nil
+}
@@ -8,16 +8,20 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Issue is LLM-friendly issue output
 type Issue struct {
@@ -67,6 +71,24 @@ type TransitionResult struct {
 	URL        string `json:"url"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "jira",
+		Name:        "Jira",
+		Group:       "dev",
+		Description: "Issues, projects, and sprint management with Atlassian Jira",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "jira_url", Description: "Jira instance URL", Required: true, Example: "https://mycompany.atlassian.net"},
+			{Key: "jira_email", Description: "Your Atlassian email", Required: true, Example: "you@company.com"},
+			{Key: "jira_token", Description: "API token", Required: true},
+		},
+		SetupGuide:  "1. Go to https://id.atlassian.com/manage-profile/security/api-tokens\n2. Click \"Create API token\"\n3. Give it a label and create\n4. Copy the token\n5. Run these commands:\n   pocket config set jira_url https://yourcompany.atlassian.net\n   pocket config set jira_email your@email.com\n   pocket config set jira_token <your-api-token>",
+		TestCommand: "pocket dev jira projects",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "jira",
@@ -86,13 +108,14 @@ func NewCmd() *cobra.Command {
 func newIssuesCmd() *cobra.Command {
 	var project string
 	var status string
-	var limit int
+	var page paginate.Options
 
 	cmd := &cobra.Command{
 		Use:   "issues",
 		Short: "List issues assigned to me or in a project",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			baseURL, email, token, err := getCredentials()
+			ctx := cmd.Context()
+			baseURL, email, token, err := getCredentials(ctx)
 			if err != nil {
 				return err
 			}
@@ -107,28 +130,41 @@ func newIssuesCmd() *cobra.Command {
 			}
 			jql += " ORDER BY updated DESC"
 
-			apiURL := fmt.Sprintf("%s/rest/api/3/search?jql=%s&maxResults=%d", baseURL, url.QueryEscape(jql), limit)
+			err = paginate.Run(ctx, page, func(cursor string, size int) (paginate.Page[Issue], error) {
+				// The cursor is the offset of the page's first issue
+				startAt, _ := strconv.Atoi(cursor)
+				apiURL := fmt.Sprintf("%s/rest/api/3/search?jql=%s&startAt=%d&maxResults=%d", baseURL, url.QueryEscape(jql), startAt, size)
 
-			var result map[string]any
-			if err := jiraGet(email, token, apiURL, &result); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
-			}
+				var result map[string]any
+				if err := jiraGet(ctx, email, token, apiURL, &result); err != nil {
+					return paginate.Page[Issue]{}, err
+				}
 
-			issues, _ := result["issues"].([]any)
-			output := make([]Issue, 0, len(issues))
-			for _, i := range issues {
-				if issue, ok := i.(map[string]any); ok {
-					output = append(output, toIssue(baseURL, issue, false))
+				issues, _ := result["issues"].([]any)
+				items := make([]Issue, 0, len(issues))
+				for _, i := range issues {
+					if issue, ok := i.(map[string]any); ok {
+						items = append(items, toIssue(baseURL, issue, false))
+					}
 				}
-			}
 
-			return outputPrint(output)
+				next := ""
+				total, _ := result["total"].(float64)
+				if end := startAt + len(issues); len(issues) > 0 && end < int(total) {
+					next = strconv.Itoa(end)
+				}
+				return paginate.Page[Issue]{Items: items, Next: next}, nil
+			})
+			if err != nil && !output.IsPrinted(err) {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
+			}
+			return err
 		},
 	}
 
 	cmd.Flags().StringVarP(&project, "project", "p", "", "Filter by project key (e.g., PROJ)")
 	cmd.Flags().StringVarP(&status, "status", "s", "", "Filter by status (e.g., \"In Progress\")")
-	cmd.Flags().IntVarP(&limit, "limit", "l", 20, "Number of issues")
+	paginate.AddFlags(cmd, &page, 20, 100, "issues")
 
 	return cmd
 }
@@ -139,7 +175,8 @@ func newIssueCmd() *cobra.Command {
 		Short: "Get issue details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			baseURL, email, token, err := getCredentials()
+			ctx := cmd.Context()
+			baseURL, email, token, err := getCredentials(ctx)
 			if err != nil {
 				return err
 			}
@@ -147,11 +184,11 @@ func newIssueCmd() *cobra.Command {
 			apiURL := fmt.Sprintf("%s/rest/api/3/issue/%s", baseURL, args[0])
 
 			var issue map[string]any
-			if err := jiraGet(email, token, apiURL, &issue); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := jiraGet(ctx, email, token, apiURL, &issue); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
-			return outputPrint(toIssue(baseURL, issue, true))
+			return outputPrint(ctx, toIssue(baseURL, issue, true))
 		},
 	}
 
@@ -163,7 +200,8 @@ func newProjectsCmd() *cobra.Command {
 		Use:   "projects",
 		Short: "List accessible projects",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			baseURL, email, token, err := getCredentials()
+			ctx := cmd.Context()
+			baseURL, email, token, err := getCredentials(ctx)
 			if err != nil {
 				return err
 			}
@@ -171,8 +209,8 @@ func newProjectsCmd() *cobra.Command {
 			apiURL := fmt.Sprintf("%s/rest/api/3/project", baseURL)
 
 			var projects []map[string]any
-			if err := jiraGet(email, token, apiURL, &projects); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := jiraGet(ctx, email, token, apiURL, &projects); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			result := make([]Project, 0, len(projects))
@@ -180,7 +218,7 @@ func newProjectsCmd() *cobra.Command {
 				result = append(result, toProject(baseURL, p))
 			}
 
-			return outputPrint(result)
+			return outputPrint(ctx, result)
 		},
 	}
 
@@ -197,13 +235,14 @@ func newCreateCmd() *cobra.Command {
 		Short: "Create a new issue",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			baseURL, email, token, err := getCredentials()
+			ctx := cmd.Context()
+			baseURL, email, token, err := getCredentials(ctx)
 			if err != nil {
 				return err
 			}
 
 			if project == "" {
-				return output.PrintError("missing_project", "Project key required (use -p PROJECT)", nil)
+				return output.PrintError(ctx, "missing_project", "Project key required (use -p PROJECT)", nil)
 			}
 
 			if issueType == "" {
@@ -245,12 +284,12 @@ func newCreateCmd() *cobra.Command {
 			apiURL := fmt.Sprintf("%s/rest/api/3/issue", baseURL)
 
 			var result map[string]any
-			if err := jiraPost(email, token, apiURL, body, &result); err != nil {
-				return output.PrintError("create_failed", err.Error(), nil)
+			if err := jiraPost(ctx, email, token, apiURL, body, &result); err != nil {
+				return output.PrintErr(ctx, "create_failed", err, nil)
 			}
 
 			key := getString(result, "key")
-			return outputPrint(CreateResult{
+			return outputPrint(ctx, CreateResult{
 				Key: key,
 				URL: fmt.Sprintf("%s/browse/%s", baseURL, key),
 			})
@@ -271,7 +310,8 @@ func newTransitionCmd() *cobra.Command {
 		Short: "Change issue status",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			baseURL, email, token, err := getCredentials()
+			ctx := cmd.Context()
+			baseURL, email, token, err := getCredentials(ctx)
 			if err != nil {
 				return err
 			}
@@ -282,8 +322,8 @@ func newTransitionCmd() *cobra.Command {
 			// First, get current issue to capture from_status
 			issueURL := fmt.Sprintf("%s/rest/api/3/issue/%s", baseURL, issueKey)
 			var issue map[string]any
-			if err := jiraGet(email, token, issueURL, &issue); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := jiraGet(ctx, email, token, issueURL, &issue); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			fromStatus := ""
@@ -297,8 +337,8 @@ func newTransitionCmd() *cobra.Command {
 			transitionsURL := fmt.Sprintf("%s/rest/api/3/issue/%s/transitions", baseURL, issueKey)
 
 			var transResult map[string]any
-			if err := jiraGet(email, token, transitionsURL, &transResult); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := jiraGet(ctx, email, token, transitionsURL, &transResult); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			transitions, _ := transResult["transitions"].([]any)
@@ -330,7 +370,7 @@ func newTransitionCmd() *cobra.Command {
 						}
 					}
 				}
-				return output.PrintError("invalid_transition", fmt.Sprintf("Cannot transition to '%s'. Available: %s", targetStatus, strings.Join(available, ", ")), nil)
+				return output.PrintError(ctx, "invalid_transition", fmt.Sprintf("Cannot transition to '%s'. Available: %s", targetStatus, strings.Join(available, ", ")), nil)
 			}
 
 			// Perform transition
@@ -340,11 +380,11 @@ func newTransitionCmd() *cobra.Command {
 				},
 			}
 
-			if err := jiraPost(email, token, transitionsURL, body, nil); err != nil {
-				return output.PrintError("transition_failed", err.Error(), nil)
+			if err := jiraPost(ctx, email, token, transitionsURL, body, nil); err != nil {
+				return output.PrintErr(ctx, "transition_failed", err, nil)
 			}
 
-			return outputPrint(TransitionResult{
+			return outputPrint(ctx, TransitionResult{
 				Key:        issueKey,
 				FromStatus: fromStatus,
 				ToStatus:   toStatus,
@@ -356,35 +396,35 @@ func newTransitionCmd() *cobra.Command {
 	return cmd
 }
 
-func getCredentials() (baseURL, email, token string, err error) {
-	baseURL, err = config.Get("jira_url")
+func getCredentials(ctx context.Context) (baseURL, email, token string, err error) {
+	baseURL, err = config.Get(ctx, "jira_url")
 	if err != nil {
 		return "", "", "", err
 	}
 	if baseURL == "" {
-		return "", "", "", output.PrintError("missing_config", "Jira URL not configured", map[string]string{
+		return "", "", "", output.PrintError(ctx, "missing_config", "Jira URL not configured", map[string]string{
 			"setup": "Run: pocket config set jira_url https://your-domain.atlassian.net",
 		})
 	}
 	// Remove trailing slash if present
 	baseURL = strings.TrimSuffix(baseURL, "/")
 
-	email, err = config.Get("jira_email")
+	email, err = config.Get(ctx, "jira_email")
 	if err != nil {
 		return "", "", "", err
 	}
 	if email == "" {
-		return "", "", "", output.PrintError("missing_config", "Jira email not configured", map[string]string{
+		return "", "", "", output.PrintError(ctx, "missing_config", "Jira email not configured", map[string]string{
 			"setup": "Run: pocket config set jira_email your-email@example.com",
 		})
 	}
 
-	token, err = config.Get("jira_token")
+	token, err = config.Get(ctx, "jira_token")
 	if err != nil {
 		return "", "", "", err
 	}
 	if token == "" {
-		return "", "", "", output.PrintError("missing_config", "Jira API token not configured", map[string]string{
+		return "", "", "", output.PrintError(ctx, "missing_config", "Jira API token not configured", map[string]string{
 			"setup": "Run: pocket config set jira_token YOUR_API_TOKEN",
 			"docs":  "Create token at: https://id.atlassian.com/manage-profile/security/api-tokens",
 		})
@@ -393,8 +433,8 @@ func getCredentials() (baseURL, email, token string, err error) {
 	return baseURL, email, token, nil
 }
 
-func jiraGet(email, token, apiURL string, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func jiraGet(ctx context.Context, email, token, apiURL string, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, http.NoBody)
@@ -417,8 +457,8 @@ func jiraGet(email, token, apiURL string, result any) error {
 	return json.NewDecoder(resp.Body).Decode(result)
 }
 
-func jiraPost(email, token, apiURL string, body, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func jiraPost(ctx context.Context, email, token, apiURL string, body, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	jsonBody, err := json.Marshal(body)
@@ -642,6 +682,6 @@ func truncate(s string, maxLen int) string {
 }
 
 // outputPrint is a wrapper to avoid shadowing the output package
-func outputPrint(data any) error {
-	return output.Print(data)
+func outputPrint(ctx context.Context, data any) error {
+	return output.Print(ctx, data)
 }
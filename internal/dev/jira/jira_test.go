@@ -1,6 +1,7 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -81,6 +82,7 @@ func TestTruncate(t *testing.T) {
 }
 
 func TestJiraGetSuccess(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		auth := r.Header.Get("Authorization")
 		if !strings.HasPrefix(auth, "Basic ") {
@@ -96,7 +98,7 @@ func TestJiraGetSuccess(t *testing.T) {
 	defer srv.Close()
 
 	var result map[string]any
-	err := jiraGet("test@example.com", "test-token", srv.URL+"/rest/api/3/issue/PROJ-123", &result)
+	err := jiraGet(ctx, "test@example.com", "test-token", srv.URL+"/rest/api/3/issue/PROJ-123", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -117,6 +119,7 @@ func TestJiraGetError(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
 			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.statusCode)
 				json.NewEncoder(w).Encode(map[string]any{
@@ -126,7 +129,7 @@ func TestJiraGetError(t *testing.T) {
 			defer srv.Close()
 
 			var result map[string]any
-			err := jiraGet("test@example.com", "test-token", srv.URL+"/test", &result)
+			err := jiraGet(ctx, "test@example.com", "test-token", srv.URL+"/test", &result)
 			if err == nil {
 				t.Error("expected error, got nil")
 			}
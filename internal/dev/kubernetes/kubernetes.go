@@ -12,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -63,6 +64,16 @@ type DescribeResult struct {
 	Output    string `json:"output"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "kubernetes",
+		Name:        "Kubernetes",
+		Group:       "dev",
+		Description: "Pods, logs, deployments, services, and resource descriptions via kubectl",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the kubernetes parent command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -80,10 +91,10 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func kubectlAvailable() error {
+func kubectlAvailable(ctx context.Context) error {
 	_, err := exec.LookPath("kubectl")
 	if err != nil {
-		return output.PrintError("kubectl_not_found", "kubectl is not installed or not in PATH", nil)
+		return output.PrintError(ctx, "kubectl_not_found", "kubectl is not installed or not in PATH", nil)
 	}
 	return nil
 }
@@ -129,11 +140,12 @@ func newPodsCmd() *cobra.Command {
 		Use:   "pods",
 		Short: "List Kubernetes pods",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := kubectlAvailable(); err != nil {
+			ctx := cmd.Context()
+			if err := kubectlAvailable(ctx); err != nil {
 				return err
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			kubectlArgs := []string{"get", "pods", "-o", "json"}
@@ -145,12 +157,12 @@ func newPodsCmd() *cobra.Command {
 
 			out, err := runKubectl(ctx, kubectlArgs...)
 			if err != nil {
-				return output.PrintError("kubectl_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "kubectl_failed", err, nil)
 			}
 
 			var podList map[string]any
 			if err := json.Unmarshal(out, &podList); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse kubectl output: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse kubectl output: %s", err.Error()), nil)
 			}
 
 			items, _ := podList["items"].([]any)
@@ -211,7 +223,7 @@ func newPodsCmd() *cobra.Command {
 				pods = append(pods, pod)
 			}
 
-			return output.Print(pods)
+			return output.Print(ctx, pods)
 		},
 	}
 
@@ -231,11 +243,12 @@ func newLogsCmd() *cobra.Command {
 		Short: "Get logs from a Kubernetes pod",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := kubectlAvailable(); err != nil {
+			ctx := cmd.Context()
+			if err := kubectlAvailable(ctx); err != nil {
 				return err
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			podName := args[0]
@@ -246,7 +259,7 @@ func newLogsCmd() *cobra.Command {
 
 			out, err := runKubectl(ctx, kubectlArgs...)
 			if err != nil {
-				return output.PrintError("kubectl_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "kubectl_failed", err, nil)
 			}
 
 			rawLines := strings.Split(string(out), "\n")
@@ -258,7 +271,7 @@ func newLogsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(LogResult{
+			return output.Print(ctx, LogResult{
 				Pod:       podName,
 				Namespace: namespace,
 				Lines:     lines,
@@ -283,11 +296,12 @@ func newDeploymentsCmd() *cobra.Command {
 		Aliases: []string{"deploy"},
 		Short:   "List Kubernetes deployments",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := kubectlAvailable(); err != nil {
+			ctx := cmd.Context()
+			if err := kubectlAvailable(ctx); err != nil {
 				return err
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			kubectlArgs := []string{"get", "deployments", "-o", "json"}
@@ -299,12 +313,12 @@ func newDeploymentsCmd() *cobra.Command {
 
 			out, err := runKubectl(ctx, kubectlArgs...)
 			if err != nil {
-				return output.PrintError("kubectl_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "kubectl_failed", err, nil)
 			}
 
 			var depList map[string]any
 			if err := json.Unmarshal(out, &depList); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse kubectl output: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse kubectl output: %s", err.Error()), nil)
 			}
 
 			items, _ := depList["items"].([]any)
@@ -341,7 +355,7 @@ func newDeploymentsCmd() *cobra.Command {
 				deployments = append(deployments, dep)
 			}
 
-			return output.Print(deployments)
+			return output.Print(ctx, deployments)
 		},
 	}
 
@@ -361,11 +375,12 @@ func newServicesCmd() *cobra.Command {
 		Aliases: []string{"svc"},
 		Short:   "List Kubernetes services",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := kubectlAvailable(); err != nil {
+			ctx := cmd.Context()
+			if err := kubectlAvailable(ctx); err != nil {
 				return err
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			kubectlArgs := []string{"get", "services", "-o", "json"}
@@ -377,12 +392,12 @@ func newServicesCmd() *cobra.Command {
 
 			out, err := runKubectl(ctx, kubectlArgs...)
 			if err != nil {
-				return output.PrintError("kubectl_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "kubectl_failed", err, nil)
 			}
 
 			var svcList map[string]any
 			if err := json.Unmarshal(out, &svcList); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse kubectl output: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse kubectl output: %s", err.Error()), nil)
 			}
 
 			items, _ := svcList["items"].([]any)
@@ -458,7 +473,7 @@ func newServicesCmd() *cobra.Command {
 				services = append(services, svc)
 			}
 
-			return output.Print(services)
+			return output.Print(ctx, services)
 		},
 	}
 
@@ -476,11 +491,12 @@ func newDescribeCmd() *cobra.Command {
 		Short: "Describe a Kubernetes resource",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := kubectlAvailable(); err != nil {
+			ctx := cmd.Context()
+			if err := kubectlAvailable(ctx); err != nil {
 				return err
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			resource := args[0]
@@ -488,10 +504,10 @@ func newDescribeCmd() *cobra.Command {
 
 			out, err := runKubectl(ctx, "describe", resource, name, "-n", namespace)
 			if err != nil {
-				return output.PrintError("kubectl_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "kubectl_failed", err, nil)
 			}
 
-			return output.Print(DescribeResult{
+			return output.Print(ctx, DescribeResult{
 				Resource:  resource,
 				Name:      name,
 				Namespace: namespace,
@@ -7,16 +7,36 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 const graphqlURL = "https://api.linear.app/graphql"
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "linear",
+		Name:        "Linear",
+		Group:       "dev",
+		Description: "Issues and project management with Linear",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "linear_token", Description: "Personal API key", Required: true, Example: "lin_api_xxxx"},
+		},
+		SetupGuide:  "1. Go to https://linear.app/settings/api\n2. Create a personal API key\n3. Copy the key\n4. Run: pocket config set linear_token <token>",
+		TestCommand: "pocket dev linear teams",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "linear",
@@ -37,20 +57,20 @@ type linearClient struct {
 	httpClient *http.Client
 }
 
-func newLinearClient() (*linearClient, error) {
-	token, err := config.MustGet("linear_token")
+func newLinearClient(ctx context.Context) (*linearClient, error) {
+	token, err := config.MustGet(ctx, "linear_token")
 	if err != nil {
 		return nil, err
 	}
 
 	return &linearClient{
 		token:      token,
-		httpClient: &http.Client{},
+		httpClient: transport.New(0),
 	}, nil
 }
 
-func (c *linearClient) doQuery(query string, variables map[string]any) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (c *linearClient) doQuery(ctx context.Context, query string, variables map[string]any) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	payload := map[string]any{
@@ -65,6 +85,11 @@ func (c *linearClient) doQuery(query string, variables map[string]any) ([]byte,
 		return nil, err
 	}
 
+	// Only mutations change anything; queries still run under --dry-run
+	if !strings.HasPrefix(strings.TrimSpace(query), "mutation") {
+		ctx = dryrun.ReadOnly(ctx)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, "POST", graphqlURL, bytes.NewBuffer(jsonBody))
 	if err != nil {
 		return nil, err
@@ -111,7 +136,8 @@ func newIssuesCmd() *cobra.Command {
 		Use:   "issues",
 		Short: "List issues",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newLinearClient()
+			ctx := cmd.Context()
+			client, err := newLinearClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -154,9 +180,9 @@ func newIssuesCmd() *cobra.Command {
 				variables["filter"] = filter
 			}
 
-			body, err := client.doQuery(query, variables)
+			body, err := client.doQuery(ctx, query, variables)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -188,7 +214,7 @@ func newIssuesCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			issues := make([]map[string]any, len(result.Data.Issues.Nodes))
@@ -212,7 +238,7 @@ func newIssuesCmd() *cobra.Command {
 				issues[i] = item
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"count":  len(issues),
 				"issues": issues,
 			})
@@ -232,7 +258,8 @@ func newTeamsCmd() *cobra.Command {
 		Use:   "teams",
 		Short: "List teams",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newLinearClient()
+			ctx := cmd.Context()
+			client, err := newLinearClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -252,9 +279,9 @@ func newTeamsCmd() *cobra.Command {
 				}
 			`
 
-			body, err := client.doQuery(query, nil)
+			body, err := client.doQuery(ctx, query, nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -273,7 +300,7 @@ func newTeamsCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			teams := make([]map[string]any, len(result.Data.Teams.Nodes))
@@ -288,7 +315,7 @@ func newTeamsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"count": len(teams),
 				"teams": teams,
 			})
@@ -308,7 +335,8 @@ func newCreateCmd() *cobra.Command {
 		Short: "Create an issue",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newLinearClient()
+			ctx := cmd.Context()
+			client, err := newLinearClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -322,9 +350,9 @@ func newCreateCmd() *cobra.Command {
 				}
 			`
 
-			teamBody, err := client.doQuery(teamQuery, map[string]any{"key": team})
+			teamBody, err := client.doQuery(ctx, teamQuery, map[string]any{"key": team})
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var teamResult struct {
@@ -338,7 +366,7 @@ func newCreateCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(teamBody, &teamResult); err != nil || len(teamResult.Data.Teams.Nodes) == 0 {
-				return output.PrintError("team_not_found", fmt.Sprintf("Team '%s' not found", team), nil)
+				return output.PrintError(ctx, "team_not_found", fmt.Sprintf("Team '%s' not found", team), nil)
 			}
 
 			teamID := teamResult.Data.Teams.Nodes[0].ID
@@ -366,9 +394,9 @@ func newCreateCmd() *cobra.Command {
 				input["priority"] = priority
 			}
 
-			body, err := client.doQuery(createQuery, map[string]any{"input": input})
+			body, err := client.doQuery(ctx, createQuery, map[string]any{"input": input})
 			if err != nil {
-				return output.PrintError("create_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "create_failed", err, nil)
 			}
 
 			var result struct {
@@ -385,11 +413,11 @@ func newCreateCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			issue := result.Data.IssueCreate.Issue
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"id":         issue.ID,
 				"identifier": issue.Identifier,
 				"title":      issue.Title,
@@ -412,7 +440,8 @@ func newMeCmd() *cobra.Command {
 		Use:   "me",
 		Short: "Get current user info",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newLinearClient()
+			ctx := cmd.Context()
+			client, err := newLinearClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -431,9 +460,9 @@ func newMeCmd() *cobra.Command {
 				}
 			`
 
-			body, err := client.doQuery(query, nil)
+			body, err := client.doQuery(ctx, query, nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -455,11 +484,11 @@ func newMeCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			user := result.Data.Viewer
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"id":              user.ID,
 				"name":            user.Name,
 				"email":           user.Email,
@@ -1,7 +1,6 @@
 package npm
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -11,12 +10,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://registry.npmjs.org"
 
-var client = &http.Client{Timeout: 10 * time.Second}
+var client = transport.New(10 * time.Second)
 
 // Package is LLM-friendly package info
 type Package struct {
@@ -42,6 +43,16 @@ type SearchResult struct {
 	Score       float64 `json:"score,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "npm",
+		Name:        "npm Registry",
+		Group:       "dev",
+		Description: "Search npm packages, get info, versions, and dependencies",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "npm",
@@ -64,16 +75,17 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search npm packages",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := url.QueryEscape(args[0])
 			reqURL := fmt.Sprintf("https://registry.npmjs.org/-/v1/search?text=%s&size=%d", query, limit)
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, http.NoBody)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			resp, err := client.Do(req)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
@@ -94,7 +106,7 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			results := make([]SearchResult, 0, len(data.Objects))
@@ -108,7 +120,7 @@ func newSearchCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(results)
+			return output.Print(ctx, results)
 		},
 	}
 
@@ -123,21 +135,22 @@ func newInfoCmd() *cobra.Command {
 		Short: "Get package info",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			pkgName := args[0]
 			reqURL := fmt.Sprintf("%s/%s", baseURL, url.PathEscape(pkgName))
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, http.NoBody)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			resp, err := client.Do(req)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode == 404 {
-				return output.PrintError("not_found", "Package not found: "+pkgName, nil)
+				return output.PrintError(ctx, "not_found", "Package not found: "+pkgName, nil)
 			}
 
 			var data struct {
@@ -162,7 +175,7 @@ func newInfoCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			pkg := Package{
@@ -190,7 +203,7 @@ func newInfoCmd() *cobra.Command {
 				pkg.Dependencies = len(latest.Dependencies)
 			}
 
-			return output.Print(pkg)
+			return output.Print(ctx, pkg)
 		},
 	}
 
@@ -205,21 +218,22 @@ func newVersionsCmd() *cobra.Command {
 		Short: "List package versions",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			pkgName := args[0]
 			reqURL := fmt.Sprintf("%s/%s", baseURL, url.PathEscape(pkgName))
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, http.NoBody)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			resp, err := client.Do(req)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode == 404 {
-				return output.PrintError("not_found", "Package not found: "+pkgName, nil)
+				return output.PrintError(ctx, "not_found", "Package not found: "+pkgName, nil)
 			}
 
 			var data struct {
@@ -228,7 +242,7 @@ func newVersionsCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			type Version struct {
@@ -268,7 +282,7 @@ func newVersionsCmd() *cobra.Command {
 				versions[i], versions[j] = versions[j], versions[i]
 			}
 
-			return output.Print(versions)
+			return output.Print(ctx, versions)
 		},
 	}
 
@@ -285,21 +299,22 @@ func newDepsCmd() *cobra.Command {
 		Short: "List package dependencies",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			pkgName := args[0]
 			reqURL := fmt.Sprintf("%s/%s/latest", baseURL, url.PathEscape(pkgName))
 
-			req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, http.NoBody)
+			req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			resp, err := client.Do(req)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode == 404 {
-				return output.PrintError("not_found", "Package not found: "+pkgName, nil)
+				return output.PrintError(ctx, "not_found", "Package not found: "+pkgName, nil)
 			}
 
 			var data struct {
@@ -308,7 +323,7 @@ func newDepsCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			type Dep struct {
@@ -329,7 +344,7 @@ func newDepsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(deps)
+			return output.Print(ctx, deps)
 		},
 	}
 
@@ -11,10 +11,12 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 const statusSuccess = "success"
 
@@ -71,6 +73,23 @@ type Target struct {
 	ScrapeURL      string `json:"scrape_url"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "prometheus",
+		Name:        "Prometheus",
+		Group:       "dev",
+		Description: "PromQL queries, alerts, and scrape targets from Prometheus",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "prometheus_url", Description: "Prometheus server URL", Required: true, Example: "http://localhost:9090"},
+			{Key: "prometheus_token", Description: "Bearer token for auth (optional)"},
+		},
+		SetupGuide:  "1. Get your Prometheus server URL (e.g., http://localhost:9090)\n2. Run: pocket config set prometheus_url <your-prometheus-url>\n\nOptional - If authentication is required:\n   pocket config set prometheus_token <your-bearer-token>\n\nNote: Requires Prometheus HTTP API access (default port 9090).",
+		TestCommand: "pocket dev prometheus targets",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the prometheus parent command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -87,16 +106,16 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func getBaseURL() string {
-	u, err := config.Get("prometheus_url")
+func getBaseURL(ctx context.Context) string {
+	u, err := config.Get(ctx, "prometheus_url")
 	if err != nil || u == "" {
 		return "http://localhost:9090"
 	}
 	return u
 }
 
-func getToken() string {
-	t, err := config.Get("prometheus_token")
+func getToken(ctx context.Context) string {
+	t, err := config.Get(ctx, "prometheus_token")
 	if err != nil || t == "" {
 		return ""
 	}
@@ -109,8 +128,9 @@ func newQueryCmd() *cobra.Command {
 		Short: "Run an instant PromQL query",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			promql := args[0]
-			baseURL := getBaseURL()
+			baseURL := getBaseURL(ctx)
 
 			apiURL := fmt.Sprintf("%s/api/v1/query?query=%s&time=%d",
 				baseURL,
@@ -119,14 +139,14 @@ func newQueryCmd() *cobra.Command {
 			)
 
 			var raw map[string]any
-			if err := promGet(apiURL, &raw); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := promGet(ctx, apiURL, &raw); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			status := getString(raw, "status")
 			if status != statusSuccess {
 				errMsg := getString(raw, "error")
-				return output.PrintError("query_failed", errMsg, nil)
+				return output.PrintError(ctx, "query_failed", errMsg, nil)
 			}
 
 			data, _ := raw["data"].(map[string]any)
@@ -153,7 +173,7 @@ func newQueryCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(QueryResult{
+			return output.Print(ctx, QueryResult{
 				Query:      promql,
 				ResultType: resultType,
 				Results:    results,
@@ -174,8 +194,9 @@ func newRangeCmd() *cobra.Command {
 		Short: "Run a range PromQL query",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			promql := args[0]
-			baseURL := getBaseURL()
+			baseURL := getBaseURL(ctx)
 
 			now := time.Now()
 
@@ -186,7 +207,7 @@ func newRangeCmd() *cobra.Command {
 				var err error
 				startTime, err = time.Parse(time.RFC3339, startStr)
 				if err != nil {
-					return output.PrintError("invalid_start", fmt.Sprintf("Invalid start time: %s (use RFC3339 format)", startStr), nil)
+					return output.PrintError(ctx, "invalid_start", fmt.Sprintf("Invalid start time: %s (use RFC3339 format)", startStr), nil)
 				}
 			}
 
@@ -197,7 +218,7 @@ func newRangeCmd() *cobra.Command {
 				var err error
 				endTime, err = time.Parse(time.RFC3339, endStr)
 				if err != nil {
-					return output.PrintError("invalid_end", fmt.Sprintf("Invalid end time: %s (use RFC3339 format)", endStr), nil)
+					return output.PrintError(ctx, "invalid_end", fmt.Sprintf("Invalid end time: %s (use RFC3339 format)", endStr), nil)
 				}
 			}
 
@@ -210,14 +231,14 @@ func newRangeCmd() *cobra.Command {
 			)
 
 			var raw map[string]any
-			if err := promGet(apiURL, &raw); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := promGet(ctx, apiURL, &raw); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			status := getString(raw, "status")
 			if status != statusSuccess {
 				errMsg := getString(raw, "error")
-				return output.PrintError("query_failed", errMsg, nil)
+				return output.PrintError(ctx, "query_failed", errMsg, nil)
 			}
 
 			data, _ := raw["data"].(map[string]any)
@@ -250,7 +271,7 @@ func newRangeCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(RangeResult{
+			return output.Print(ctx, RangeResult{
 				Query:      promql,
 				ResultType: resultType,
 				Results:    results,
@@ -272,19 +293,20 @@ func newAlertsCmd() *cobra.Command {
 		Use:   "alerts",
 		Short: "List Prometheus alerts",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			baseURL := getBaseURL()
+			ctx := cmd.Context()
+			baseURL := getBaseURL(ctx)
 
 			apiURL := fmt.Sprintf("%s/api/v1/alerts", baseURL)
 
 			var raw map[string]any
-			if err := promGet(apiURL, &raw); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := promGet(ctx, apiURL, &raw); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			status := getString(raw, "status")
 			if status != statusSuccess {
 				errMsg := getString(raw, "error")
-				return output.PrintError("query_failed", errMsg, nil)
+				return output.PrintError(ctx, "query_failed", errMsg, nil)
 			}
 
 			data, _ := raw["data"].(map[string]any)
@@ -311,7 +333,7 @@ func newAlertsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(alerts)
+			return output.Print(ctx, alerts)
 		},
 	}
 
@@ -327,7 +349,8 @@ func newTargetsCmd() *cobra.Command {
 		Use:   "targets",
 		Short: "List Prometheus scrape targets",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			baseURL := getBaseURL()
+			ctx := cmd.Context()
+			baseURL := getBaseURL(ctx)
 
 			apiURL := fmt.Sprintf("%s/api/v1/targets", baseURL)
 			if state != "" {
@@ -335,14 +358,14 @@ func newTargetsCmd() *cobra.Command {
 			}
 
 			var raw map[string]any
-			if err := promGet(apiURL, &raw); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := promGet(ctx, apiURL, &raw); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			status := getString(raw, "status")
 			if status != statusSuccess {
 				errMsg := getString(raw, "error")
-				return output.PrintError("query_failed", errMsg, nil)
+				return output.PrintError(ctx, "query_failed", errMsg, nil)
 			}
 
 			data, _ := raw["data"].(map[string]any)
@@ -366,7 +389,7 @@ func newTargetsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(targets)
+			return output.Print(ctx, targets)
 		},
 	}
 
@@ -375,8 +398,8 @@ func newTargetsCmd() *cobra.Command {
 	return cmd
 }
 
-func promGet(apiURL string, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func promGet(ctx context.Context, apiURL string, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, http.NoBody)
@@ -386,7 +409,7 @@ func promGet(apiURL string, result any) error {
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
 
-	token := getToken()
+	token := getToken(ctx)
 	if token != "" {
 		req.Header.Set("Authorization", "Bearer "+token)
 	}
@@ -1,6 +1,7 @@
 package prometheus
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -85,6 +86,7 @@ func TestToStringMap(t *testing.T) {
 }
 
 func TestPromGetSuccess(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := map[string]any{
 			"status": "success",
@@ -98,7 +100,7 @@ func TestPromGetSuccess(t *testing.T) {
 	defer srv.Close()
 
 	var result map[string]any
-	err := promGet(srv.URL+"/api/v1/query?query=up", &result)
+	err := promGet(ctx, srv.URL+"/api/v1/query?query=up", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -119,6 +121,7 @@ func TestPromGetError(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
 			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.statusCode)
 				json.NewEncoder(w).Encode(map[string]any{"error": "Error"})
@@ -126,7 +129,7 @@ func TestPromGetError(t *testing.T) {
 			defer srv.Close()
 
 			var result map[string]any
-			err := promGet(srv.URL+"/test", &result)
+			err := promGet(ctx, srv.URL+"/test", &result)
 			if err == nil {
 				t.Error("expected error, got nil")
 			}
@@ -135,6 +138,7 @@ func TestPromGetError(t *testing.T) {
 }
 
 func TestPrometheusQueryResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := map[string]any{
 			"status": "success",
@@ -160,7 +164,7 @@ func TestPrometheusQueryResponse(t *testing.T) {
 	defer srv.Close()
 
 	var result map[string]any
-	err := promGet(srv.URL+"/api/v1/query", &result)
+	err := promGet(ctx, srv.URL+"/api/v1/query", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -178,6 +182,7 @@ func TestPrometheusQueryResponse(t *testing.T) {
 }
 
 func TestPrometheusAlertsResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := map[string]any{
 			"status": "success",
@@ -203,7 +208,7 @@ func TestPrometheusAlertsResponse(t *testing.T) {
 	defer srv.Close()
 
 	var result map[string]any
-	err := promGet(srv.URL+"/api/v1/alerts", &result)
+	err := promGet(ctx, srv.URL+"/api/v1/alerts", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -221,6 +226,7 @@ func TestPrometheusAlertsResponse(t *testing.T) {
 }
 
 func TestPrometheusTargetsResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		resp := map[string]any{
 			"status": "success",
@@ -244,7 +250,7 @@ func TestPrometheusTargetsResponse(t *testing.T) {
 	defer srv.Close()
 
 	var result map[string]any
-	err := promGet(srv.URL+"/api/v1/targets", &result)
+	err := promGet(ctx, srv.URL+"/api/v1/targets", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -11,12 +11,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://pypi.org"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Package is LLM-friendly package info
 type Package struct {
@@ -47,6 +49,16 @@ type Version struct {
 	Python   string `json:"python,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "pypi",
+		Name:        "PyPI Registry",
+		Group:       "dev",
+		Description: "Search Python packages, get info, versions, and dependencies",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "pypi",
@@ -71,6 +83,7 @@ func newSearchCmd() *cobra.Command {
 		Long:  "Search PyPI packages. Note: Uses PyPI simple search which may be limited.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			// PyPI doesn't have a great search API, so we'll use a workaround
 			// Search via the warehouse API (unofficial but works)
 			query := url.QueryEscape(args[0])
@@ -82,8 +95,8 @@ func newSearchCmd() *cobra.Command {
 
 			// Try direct package lookup
 			var pkg pypiResponse
-			if err := pypiGet(fmt.Sprintf("%s/pypi/%s/json", baseURL, args[0]), &pkg); err == nil {
-				return output.Print([]SearchResult{{
+			if err := pypiGet(ctx, fmt.Sprintf("%s/pypi/%s/json", baseURL, args[0]), &pkg); err == nil {
+				return output.Print(ctx, []SearchResult{{
 					Name:    pkg.Info.Name,
 					Version: pkg.Info.Version,
 					Summary: truncate(pkg.Info.Summary, 100),
@@ -91,7 +104,7 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			// If direct lookup fails, return a helpful message
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"note":    "PyPI search API is limited. Try exact package name or use: pip search",
 				"suggest": fmt.Sprintf("pocket dev pypi info %s", args[0]),
 				"web":     reqURL,
@@ -110,12 +123,13 @@ func newInfoCmd() *cobra.Command {
 		Short: "Get package info",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			pkgName := strings.ToLower(args[0])
 			reqURL := fmt.Sprintf("%s/pypi/%s/json", baseURL, url.PathEscape(pkgName))
 
 			var data pypiResponse
-			if err := pypiGet(reqURL, &data); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := pypiGet(ctx, reqURL, &data); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			pkg := Package{
@@ -169,7 +183,7 @@ func newInfoCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(pkg)
+			return output.Print(ctx, pkg)
 		},
 	}
 
@@ -184,12 +198,13 @@ func newVersionsCmd() *cobra.Command {
 		Short: "List package versions",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			pkgName := strings.ToLower(args[0])
 			reqURL := fmt.Sprintf("%s/pypi/%s/json", baseURL, url.PathEscape(pkgName))
 
 			var data pypiResponse
-			if err := pypiGet(reqURL, &data); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := pypiGet(ctx, reqURL, &data); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			// Collect versions with release info
@@ -230,7 +245,7 @@ func newVersionsCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -245,12 +260,13 @@ func newDepsCmd() *cobra.Command {
 		Short: "List package dependencies",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			pkgName := strings.ToLower(args[0])
 			reqURL := fmt.Sprintf("%s/pypi/%s/json", baseURL, url.PathEscape(pkgName))
 
 			var data pypiResponse
-			if err := pypiGet(reqURL, &data); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := pypiGet(ctx, reqURL, &data); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			type Dep struct {
@@ -301,7 +317,7 @@ func newDepsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(deps)
+			return output.Print(ctx, deps)
 		},
 	}
 
@@ -327,8 +343,8 @@ type pypiResponse struct {
 	} `json:"releases"`
 }
 
-func pypiGet(url string, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func pypiGet(ctx context.Context, url string, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, http.NoBody)
@@ -1,6 +1,7 @@
 package pypi
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -68,6 +69,7 @@ func TestTimeAgo(t *testing.T) {
 }
 
 func TestPypiGetSuccess(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		data := map[string]any{
 			"info": map[string]any{
@@ -85,7 +87,7 @@ func TestPypiGetSuccess(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	var result pypiResponse
-	err := pypiGet(srv.URL+"/pypi/requests/json", &result)
+	err := pypiGet(ctx, srv.URL+"/pypi/requests/json", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -105,13 +107,14 @@ func TestPypiGetError(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
 			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.statusCode)
 			}))
 			defer srv.Close()
 
 			var result pypiResponse
-			err := pypiGet(srv.URL+"/test", &result)
+			err := pypiGet(ctx, srv.URL+"/test", &result)
 			if err == nil {
 				t.Error("expected error, got nil")
 			}
@@ -120,6 +123,7 @@ func TestPypiGetError(t *testing.T) {
 }
 
 func TestPypiInfoResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		data := map[string]any{
 			"info": map[string]any{
@@ -153,7 +157,7 @@ func TestPypiInfoResponse(t *testing.T) {
 	defer srv.Close()
 
 	var result pypiResponse
-	err := pypiGet(srv.URL+"/pypi/flask/json", &result)
+	err := pypiGet(ctx, srv.URL+"/pypi/flask/json", &result)
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
@@ -12,6 +12,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -63,6 +65,23 @@ type PresignResult struct {
 	ExpiresIn int    `json:"expires_in"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "s3",
+		Name:        "AWS S3",
+		Group:       "dev",
+		Description: "List buckets, browse objects, upload/download, and generate presigned URLs",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "aws_profile", Description: "AWS CLI profile name (from ~/.aws/credentials)", Required: true, Example: "default"},
+			{Key: "aws_region", Description: "AWS region", Required: true, Example: "us-east-1"},
+		},
+		SetupGuide:  "1. Install AWS CLI: https://aws.amazon.com/cli/\n2. Run: aws configure --profile <profile-name>\n3. Enter your Access Key ID, Secret Access Key, and region\n4. Run:\n   pocket config set aws_profile <profile-name>\n   pocket config set aws_region <region>\n\nNote: Uses the AWS SDK credential chain. IAM permissions needed: s3:ListBucket, s3:GetObject, s3:PutObject.",
+		TestCommand: "pocket dev s3 buckets",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the S3 command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -80,25 +99,25 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func checkAWSCLI() error {
+func checkAWSCLI(ctx context.Context) error {
 	_, err := exec.LookPath("aws")
 	if err != nil {
-		return output.PrintError("aws_not_found", "AWS CLI is not installed or not in PATH", map[string]any{
+		return output.PrintError(ctx, "aws_not_found", "AWS CLI is not installed or not in PATH", map[string]any{
 			"hint": "Install from https://aws.amazon.com/cli/ or via: brew install awscli",
 		})
 	}
 	return nil
 }
 
-func getAWSArgs() []string {
+func getAWSArgs(ctx context.Context) []string {
 	var extraArgs []string
 
-	profile, err := config.Get("aws_profile")
+	profile, err := config.Get(ctx, "aws_profile")
 	if err == nil && profile != "" {
 		extraArgs = append(extraArgs, "--profile", profile)
 	}
 
-	region, err := config.Get("aws_region")
+	region, err := config.Get(ctx, "aws_region")
 	if err == nil && region != "" {
 		extraArgs = append(extraArgs, "--region", region)
 	}
@@ -106,12 +125,12 @@ func getAWSArgs() []string {
 	return extraArgs
 }
 
-func runAWS(args ...string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func runAWS(ctx context.Context, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	allArgs := append([]string{}, args...)
-	allArgs = append(allArgs, getAWSArgs()...)
+	allArgs = append(allArgs, getAWSArgs(ctx)...)
 	cmd := exec.CommandContext(ctx, "aws", allArgs...)
 
 	out, err := cmd.Output()
@@ -134,13 +153,14 @@ func newBucketsCmd() *cobra.Command {
 		Use:   "buckets",
 		Short: "List S3 buckets",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAWSCLI(); err != nil {
+			ctx := cmd.Context()
+			if err := checkAWSCLI(ctx); err != nil {
 				return err
 			}
 
-			data, err := runAWS("s3api", "list-buckets", "--output", "json")
+			data, err := runAWS(ctx, "s3api", "list-buckets", "--output", "json")
 			if err != nil {
-				return output.PrintError("aws_error", err.Error(), nil)
+				return output.PrintErr(ctx, "aws_error", err, nil)
 			}
 
 			var resp struct {
@@ -151,7 +171,7 @@ func newBucketsCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse AWS response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse AWS response: %s", err.Error()), nil)
 			}
 
 			buckets := make([]Bucket, 0, len(resp.Buckets))
@@ -167,7 +187,7 @@ func newBucketsCmd() *cobra.Command {
 				Count:   len(buckets),
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -183,7 +203,8 @@ func newLsCmd() *cobra.Command {
 		Long:  `List objects. Path format: "s3://bucket/prefix/"`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAWSCLI(); err != nil {
+			ctx := cmd.Context()
+			if err := checkAWSCLI(ctx); err != nil {
 				return err
 			}
 
@@ -193,9 +214,9 @@ func newLsCmd() *cobra.Command {
 				awsArgs = append(awsArgs, "--recursive")
 			}
 
-			data, err := runAWS(awsArgs...)
+			data, err := runAWS(ctx, awsArgs...)
 			if err != nil {
-				return output.PrintError("aws_error", err.Error(), nil)
+				return output.PrintErr(ctx, "aws_error", err, nil)
 			}
 
 			objects := parseLsOutput(string(data))
@@ -206,7 +227,7 @@ func newLsCmd() *cobra.Command {
 				Count:   len(objects),
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -221,16 +242,17 @@ func newGetCmd() *cobra.Command {
 		Short: "Download file from S3",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAWSCLI(); err != nil {
+			ctx := cmd.Context()
+			if err := checkAWSCLI(ctx); err != nil {
 				return err
 			}
 
 			s3Path := args[0]
 			localPath := args[1]
 
-			_, err := runAWS("s3", "cp", s3Path, localPath)
+			_, err := runAWS(ctx, "s3", "cp", s3Path, localPath)
 			if err != nil {
-				return output.PrintError("download_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "download_failed", err, nil)
 			}
 
 			result := DownloadResult{
@@ -239,7 +261,7 @@ func newGetCmd() *cobra.Command {
 				OK:          true,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -252,16 +274,20 @@ func newPutCmd() *cobra.Command {
 		Short: "Upload file to S3",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAWSCLI(); err != nil {
-				return err
-			}
-
+			ctx := cmd.Context()
 			localPath := args[0]
 			s3Path := args[1]
 
-			_, err := runAWS("s3", "cp", localPath, s3Path)
+			if dryrun.Enabled(ctx) {
+				return dryrun.Exec(ctx, append([]string{"aws", "s3", "cp", localPath, s3Path}, getAWSArgs(ctx)...)...)
+			}
+			if err := checkAWSCLI(ctx); err != nil {
+				return err
+			}
+
+			_, err := runAWS(ctx, "s3", "cp", localPath, s3Path)
 			if err != nil {
-				return output.PrintError("upload_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "upload_failed", err, nil)
 			}
 
 			result := UploadResult{
@@ -270,7 +296,7 @@ func newPutCmd() *cobra.Command {
 				OK:          true,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -285,15 +311,16 @@ func newPresignCmd() *cobra.Command {
 		Short: "Generate a presigned URL for an S3 object",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkAWSCLI(); err != nil {
+			ctx := cmd.Context()
+			if err := checkAWSCLI(ctx); err != nil {
 				return err
 			}
 
 			s3Path := args[0]
 
-			data, err := runAWS("s3", "presign", s3Path, "--expires-in", fmt.Sprintf("%d", expires))
+			data, err := runAWS(ctx, "s3", "presign", s3Path, "--expires-in", fmt.Sprintf("%d", expires))
 			if err != nil {
-				return output.PrintError("presign_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "presign_failed", err, nil)
 			}
 
 			presignedURL := strings.TrimSpace(string(data))
@@ -304,7 +331,7 @@ func newPresignCmd() *cobra.Command {
 				ExpiresIn: expires,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
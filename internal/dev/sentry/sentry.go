@@ -6,17 +6,21 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://sentry.io/api/0"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Project is LLM-friendly Sentry project output
 type Project struct {
@@ -76,6 +80,23 @@ type EventTag struct {
 	Value string `json:"value"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "sentry",
+		Name:        "Sentry",
+		Group:       "dev",
+		Description: "Error tracking: projects, issues, and events from Sentry",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "sentry_auth_token", Description: "Auth token with project:read, event:read scopes", Required: true},
+			{Key: "sentry_org", Description: "Organization slug (optional, used as default)", Example: "my-org"},
+		},
+		SetupGuide:  "1. Go to https://sentry.io/settings/account/api/auth-tokens/\n2. Click \"Create New Token\"\n3. Select scopes: project:read, event:read, org:read\n4. Copy the token\n5. Run: pocket config set sentry_auth_token <your-token>\n\nOptional - Set default org:\n   pocket config set sentry_org <your-org-slug>",
+		TestCommand: "pocket dev sentry projects",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the sentry parent command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -92,24 +113,24 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func getToken() (string, error) {
-	token, err := config.Get("sentry_auth_token")
+func getToken(ctx context.Context) (string, error) {
+	token, err := config.Get(ctx, "sentry_auth_token")
 	if err != nil {
 		return "", err
 	}
 	if token == "" {
-		return "", output.PrintError("missing_config", "Sentry auth token not configured", map[string]string{
+		return "", output.PrintError(ctx, "missing_config", "Sentry auth token not configured", map[string]string{
 			"setup": "Run: pocket config set sentry_auth_token YOUR_TOKEN",
 		})
 	}
 	return token, nil
 }
 
-func getOrg(flagOrg string) string {
+func getOrg(ctx context.Context, flagOrg string) string {
 	if flagOrg != "" {
 		return flagOrg
 	}
-	org, err := config.Get("sentry_org")
+	org, err := config.Get(ctx, "sentry_org")
 	if err == nil && org != "" {
 		return org
 	}
@@ -124,7 +145,8 @@ func newProjectsCmd() *cobra.Command {
 		Use:   "projects",
 		Short: "List Sentry projects",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -132,11 +154,11 @@ func newProjectsCmd() *cobra.Command {
 			apiURL := fmt.Sprintf("%s/projects/", baseURL)
 
 			var raw []map[string]any
-			if err := sentryGet(token, apiURL, &raw); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := sentryGet(ctx, token, apiURL, &raw); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
-			orgSlug := getOrg(org)
+			orgSlug := getOrg(ctx, org)
 
 			result := make([]Project, 0, limit)
 			for _, p := range raw {
@@ -163,18 +185,18 @@ func newProjectsCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
 	cmd.Flags().IntVarP(&limit, "limit", "l", 10, "Number of projects to show")
-	cmd.Flags().StringVarP(&org, "org", "o", "", "Filter by organization slug")
+	cmd.Flags().StringVar(&org, "org", "", "Filter by organization slug")
 
 	return cmd
 }
 
 func newIssuesCmd() *cobra.Command {
-	var limit int
+	var page paginate.Options
 	var query string
 	var org string
 
@@ -183,45 +205,58 @@ func newIssuesCmd() *cobra.Command {
 		Short: "List issues for a Sentry project",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
 
-			orgSlug := getOrg(org)
+			orgSlug := getOrg(ctx, org)
 			if orgSlug == "" {
-				return output.PrintError("missing_org", "Organization slug required (use --org or set sentry_org in config)", map[string]string{
+				return output.PrintError(ctx, "missing_org", "Organization slug required (use --org or set sentry_org in config)", map[string]string{
 					"setup": "Run: pocket config set sentry_org YOUR_ORG_SLUG",
 				})
 			}
 
 			projectSlug := args[0]
-			apiURL := fmt.Sprintf("%s/projects/%s/%s/issues/", baseURL, url.PathEscape(orgSlug), url.PathEscape(projectSlug))
-
-			if query != "" {
-				apiURL += "?query=" + url.QueryEscape(query)
-			}
+			first := fmt.Sprintf("%s/projects/%s/%s/issues/", baseURL, url.PathEscape(orgSlug), url.PathEscape(projectSlug))
 
-			var raw []map[string]any
-			if err := sentryGet(token, apiURL, &raw); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
-			}
+			err = paginate.Run(ctx, page, func(cursor string, size int) (paginate.Page[Issue], error) {
+				u, err := url.Parse(first)
+				if cursor != "" {
+					u, err = paginate.LinkURL(cursor, baseURL)
+				}
+				if err != nil {
+					return paginate.Page[Issue]{}, err
+				}
+				q := u.Query()
+				q.Set("limit", strconv.Itoa(size))
+				if query != "" && cursor == "" {
+					q.Set("query", query)
+				}
+				u.RawQuery = q.Encode()
 
-			result := make([]Issue, 0, limit)
-			for _, i := range raw {
-				if len(result) >= limit {
-					break
+				var raw []map[string]any
+				next, err := sentryGetPage(ctx, token, u.String(), &raw)
+				if err != nil {
+					return paginate.Page[Issue]{}, err
 				}
-				result = append(result, toIssue(i))
+				items := make([]Issue, 0, len(raw))
+				for _, i := range raw {
+					items = append(items, toIssue(i))
+				}
+				return paginate.Page[Issue]{Items: items, Next: next}, nil
+			})
+			if err != nil && !output.IsPrinted(err) {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
-
-			return output.Print(result)
+			return err
 		},
 	}
 
-	cmd.Flags().IntVarP(&limit, "limit", "l", 10, "Number of issues to show")
+	paginate.AddFlags(cmd, &page, 10, 100, "issues to show")
 	cmd.Flags().StringVarP(&query, "query", "q", "", "Search query")
-	cmd.Flags().StringVarP(&org, "org", "o", "", "Organization slug (falls back to config)")
+	cmd.Flags().StringVar(&org, "org", "", "Organization slug (falls back to config)")
 
 	return cmd
 }
@@ -232,7 +267,8 @@ func newIssueCmd() *cobra.Command {
 		Short: "Get Sentry issue details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -241,8 +277,8 @@ func newIssueCmd() *cobra.Command {
 			apiURL := fmt.Sprintf("%s/issues/%s/", baseURL, url.PathEscape(issueID))
 
 			var raw map[string]any
-			if err := sentryGet(token, apiURL, &raw); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := sentryGet(ctx, token, apiURL, &raw); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			detail := IssueDetail{
@@ -269,7 +305,7 @@ func newIssueCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(detail)
+			return output.Print(ctx, detail)
 		},
 	}
 
@@ -284,7 +320,8 @@ func newEventsCmd() *cobra.Command {
 		Short: "List events for a Sentry issue",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -293,8 +330,8 @@ func newEventsCmd() *cobra.Command {
 			apiURL := fmt.Sprintf("%s/issues/%s/events/", baseURL, url.PathEscape(issueID))
 
 			var raw []map[string]any
-			if err := sentryGet(token, apiURL, &raw); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := sentryGet(ctx, token, apiURL, &raw); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			result := make([]Event, 0, limit)
@@ -326,7 +363,7 @@ func newEventsCmd() *cobra.Command {
 				result = append(result, event)
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -335,13 +372,20 @@ func newEventsCmd() *cobra.Command {
 	return cmd
 }
 
-func sentryGet(token, apiURL string, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func sentryGet(ctx context.Context, token, apiURL string, result any) error {
+	_, err := sentryGetPage(ctx, token, apiURL, result)
+	return err
+}
+
+// sentryGetPage is sentryGet for list endpoints, also returning the next
+// page's URL from the Link header
+func sentryGetPage(ctx context.Context, token, apiURL string, result any) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, http.NoBody)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -349,7 +393,7 @@ func sentryGet(token, apiURL string, result any) error {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer resp.Body.Close()
 
@@ -357,13 +401,13 @@ func sentryGet(token, apiURL string, result any) error {
 		var errResp map[string]any
 		if decErr := json.NewDecoder(resp.Body).Decode(&errResp); decErr == nil {
 			if detail := getString(errResp, "detail"); detail != "" {
-				return fmt.Errorf("%s", detail)
+				return "", fmt.Errorf("%s", detail)
 			}
 		}
-		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		return "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
 	}
 
-	return json.NewDecoder(resp.Body).Decode(result)
+	return paginate.NextLink(resp.Header), json.NewDecoder(resp.Body).Decode(result)
 }
 
 func toIssue(i map[string]any) Issue {
@@ -1,6 +1,7 @@
 package sentry
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -60,6 +61,7 @@ func TestGetInt(t *testing.T) {
 }
 
 func TestSentryGetSuccess(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("Authorization") != "Bearer test-token" {
 			t.Error("expected Authorization header")
@@ -83,7 +85,7 @@ func TestSentryGetSuccess(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	var result []map[string]any
-	err := sentryGet("test-token", srv.URL+"/projects/", &result)
+	err := sentryGet(ctx, "test-token", srv.URL+"/projects/", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -104,6 +106,7 @@ func TestSentryGetError(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
 			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.statusCode)
 				json.NewEncoder(w).Encode(map[string]any{"detail": "Error"})
@@ -111,7 +114,7 @@ func TestSentryGetError(t *testing.T) {
 			defer srv.Close()
 
 			var result map[string]any
-			err := sentryGet("test-token", srv.URL+"/test", &result)
+			err := sentryGet(ctx, "test-token", srv.URL+"/test", &result)
 			if err == nil {
 				t.Error("expected error, got nil")
 			}
@@ -149,6 +152,7 @@ func TestToIssue(t *testing.T) {
 }
 
 func TestSentryProjectsResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		projects := []map[string]any{
 			{
@@ -179,7 +183,7 @@ func TestSentryProjectsResponse(t *testing.T) {
 	defer srv.Close()
 
 	var result []map[string]any
-	err := sentryGet("test-token", srv.URL+"/projects/", &result)
+	err := sentryGet(ctx, "test-token", srv.URL+"/projects/", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -193,6 +197,7 @@ func TestSentryProjectsResponse(t *testing.T) {
 }
 
 func TestSentryIssuesResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		issues := []map[string]any{
 			{
@@ -213,7 +218,7 @@ func TestSentryIssuesResponse(t *testing.T) {
 	defer srv.Close()
 
 	var result []map[string]any
-	err := sentryGet("test-token", srv.URL+"/issues/", &result)
+	err := sentryGet(ctx, "test-token", srv.URL+"/issues/", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -10,12 +10,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://api.vercel.com"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Project is LLM-friendly project output
 type Project struct {
@@ -52,6 +54,22 @@ type EnvVar struct {
 	Targets []string `json:"targets,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "vercel",
+		Name:        "Vercel",
+		Group:       "dev",
+		Description: "Projects, deployments, domains, and environment variables on Vercel",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "vercel_token", Description: "Personal access token", Required: true},
+		},
+		SetupGuide:  "1. Go to https://vercel.com/account/tokens\n2. Click \"Create\"\n3. Give it a name and set expiration\n4. Copy the token\n5. Run: pocket config set vercel_token <your-token>",
+		TestCommand: "pocket dev vercel projects",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "vercel",
@@ -71,15 +89,15 @@ func NewCmd() *cobra.Command {
 
 // vcList fetches a list endpoint, extracts the array at responseKey, and maps
 // each element through convert.
-func vcList[T any](apiURL, responseKey string, convert func(map[string]any) T) error {
-	token, err := getToken()
+func vcList[T any](ctx context.Context, apiURL, responseKey string, convert func(map[string]any) T) error {
+	token, err := getToken(ctx)
 	if err != nil {
 		return err
 	}
 
 	var resp map[string]any
-	if err := vcGet(token, apiURL, &resp); err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+	if err := vcGet(ctx, token, apiURL, &resp); err != nil {
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	items, _ := resp[responseKey].([]any)
@@ -90,7 +108,7 @@ func vcList[T any](apiURL, responseKey string, convert func(map[string]any) T) e
 		}
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
 
 func newProjectsCmd() *cobra.Command {
@@ -100,8 +118,9 @@ func newProjectsCmd() *cobra.Command {
 		Use:   "projects",
 		Short: "List all projects",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			url := fmt.Sprintf("%s/v9/projects?limit=%d", baseURL, limit)
-			return vcList(url, "projects", toProject)
+			return vcList(ctx, url, "projects", toProject)
 		},
 	}
 
@@ -116,7 +135,8 @@ func newProjectCmd() *cobra.Command {
 		Short: "Get project details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -124,11 +144,11 @@ func newProjectCmd() *cobra.Command {
 			url := fmt.Sprintf("%s/v9/projects/%s", baseURL, args[0])
 
 			var proj map[string]any
-			if err := vcGet(token, url, &proj); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := vcGet(ctx, token, url, &proj); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
-			return output.Print(toProject(proj))
+			return output.Print(ctx, toProject(proj))
 		},
 	}
 
@@ -143,7 +163,8 @@ func newDeploymentsCmd() *cobra.Command {
 		Short: "List deployments for a project",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -151,8 +172,8 @@ func newDeploymentsCmd() *cobra.Command {
 			url := fmt.Sprintf("%s/v6/deployments?projectId=%s&limit=%d", baseURL, args[0], limit)
 
 			var resp map[string]any
-			if err := vcGet(token, url, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := vcGet(ctx, token, url, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			deployments, _ := resp["deployments"].([]any)
@@ -163,7 +184,7 @@ func newDeploymentsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -178,7 +199,8 @@ func newDeploymentCmd() *cobra.Command {
 		Short: "Get deployment details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -186,11 +208,11 @@ func newDeploymentCmd() *cobra.Command {
 			url := fmt.Sprintf("%s/v13/deployments/%s", baseURL, args[0])
 
 			var dep map[string]any
-			if err := vcGet(token, url, &dep); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := vcGet(ctx, token, url, &dep); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
-			return output.Print(toDeployment(dep))
+			return output.Print(ctx, toDeployment(dep))
 		},
 	}
 
@@ -204,8 +226,9 @@ func newDomainsCmd() *cobra.Command {
 		Use:   "domains",
 		Short: "List all domains",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			url := fmt.Sprintf("%s/v5/domains?limit=%d", baseURL, limit)
-			return vcList(url, "domains", toDomain)
+			return vcList(ctx, url, "domains", toDomain)
 		},
 	}
 
@@ -220,7 +243,8 @@ func newEnvCmd() *cobra.Command {
 		Short: "List environment variables for a project",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -228,8 +252,8 @@ func newEnvCmd() *cobra.Command {
 			url := fmt.Sprintf("%s/v10/projects/%s/env", baseURL, args[0])
 
 			var resp map[string]any
-			if err := vcGet(token, url, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := vcGet(ctx, token, url, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			envs, _ := resp["envs"].([]any)
@@ -240,28 +264,28 @@ func newEnvCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
 	return cmd
 }
 
-func getToken() (string, error) {
-	token, err := config.Get("vercel_token")
+func getToken(ctx context.Context) (string, error) {
+	token, err := config.Get(ctx, "vercel_token")
 	if err != nil {
-		return "", output.PrintError("config_error", err.Error(), nil)
+		return "", output.PrintErr(ctx, "config_error", err, nil)
 	}
 	if token == "" {
-		return "", output.PrintError("missing_config", "vercel_token not configured", map[string]string{
+		return "", output.PrintError(ctx, "missing_config", "vercel_token not configured", map[string]string{
 			"setup": "Get your token from https://vercel.com/account/tokens then run: pocket config set vercel_token <token>",
 		})
 	}
 	return token, nil
 }
 
-func vcGet(token, url string, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func vcGet(ctx context.Context, token, url string, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", url, http.NoBody)
@@ -1,6 +1,7 @@
 package vercel
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -97,6 +98,7 @@ func TestTimeAgo(t *testing.T) {
 }
 
 func TestVcGetSuccess(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("Authorization") != "Bearer test-token" {
 			t.Error("expected Authorization header")
@@ -110,7 +112,7 @@ func TestVcGetSuccess(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	var result map[string]any
-	err := vcGet("test-token", srv.URL+"/v9/projects/my-project", &result)
+	err := vcGet(ctx, "test-token", srv.URL+"/v9/projects/my-project", &result)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -131,6 +133,7 @@ func TestVcGetError(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
 			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				w.WriteHeader(tt.statusCode)
 				json.NewEncoder(w).Encode(map[string]any{
@@ -140,7 +143,7 @@ func TestVcGetError(t *testing.T) {
 			defer srv.Close()
 
 			var result map[string]any
-			err := vcGet("test-token", srv.URL+"/test", &result)
+			err := vcGet(ctx, "test-token", srv.URL+"/test", &result)
 			if err == nil {
 				t.Error("expected error, got nil")
 			}
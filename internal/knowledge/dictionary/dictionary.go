@@ -11,12 +11,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://api.dictionaryapi.dev/api/v2/entries/en"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Definition is LLM-friendly definition output
 type Definition struct {
@@ -41,6 +43,16 @@ type Def struct {
 	Example    string `json:"example,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "dictionary",
+		Name:        "Dictionary",
+		Group:       "knowledge",
+		Description: "Word definitions, synonyms, antonyms, and pronunciations",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "dictionary",
@@ -63,15 +75,16 @@ func newDefineCmd() *cobra.Command {
 		Short: "Get word definition",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			word := strings.ToLower(args[0])
 
-			entries, err := fetchWord(word)
+			entries, err := fetchWord(ctx, word)
 			if err != nil {
 				return err
 			}
 
 			if len(entries) == 0 {
-				return output.PrintError("not_found", "Word not found: "+word, nil)
+				return output.PrintError(ctx, "not_found", "Word not found: "+word, nil)
 			}
 
 			entry := entries[0]
@@ -132,7 +145,7 @@ func newDefineCmd() *cobra.Command {
 				def.Meanings = append(def.Meanings, meaning)
 			}
 
-			return output.Print(def)
+			return output.Print(ctx, def)
 		},
 	}
 
@@ -143,14 +156,14 @@ func newDefineCmd() *cobra.Command {
 
 // collectWordRelations fetches a word and collects either synonyms or antonyms
 // from all meanings and definitions.  relType must be "synonyms" or "antonyms".
-func collectWordRelations(word, relType string) error {
-	entries, err := fetchWord(word)
+func collectWordRelations(ctx context.Context, word, relType string) error {
+	entries, err := fetchWord(ctx, word)
 	if err != nil {
 		return err
 	}
 
 	if len(entries) == 0 {
-		return output.PrintError("not_found", "Word not found: "+word, nil)
+		return output.PrintError(ctx, "not_found", "Word not found: "+word, nil)
 	}
 
 	useSynonyms := relType == "synonyms"
@@ -191,10 +204,10 @@ func collectWordRelations(word, relType string) error {
 	}
 
 	if len(result) == 0 {
-		return output.PrintError("not_found", "No "+relType+" found for: "+word, nil)
+		return output.PrintError(ctx, "not_found", "No "+relType+" found for: "+word, nil)
 	}
 
-	return output.Print(map[string]any{
+	return output.Print(ctx, map[string]any{
 		"word":  word,
 		relType: result,
 	})
@@ -207,7 +220,8 @@ func newSynonymsCmd() *cobra.Command {
 		Short:   "Get synonyms for a word",
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return collectWordRelations(strings.ToLower(args[0]), "synonyms")
+			ctx := cmd.Context()
+			return collectWordRelations(ctx, strings.ToLower(args[0]), "synonyms")
 		},
 	}
 
@@ -221,7 +235,8 @@ func newAntonymsCmd() *cobra.Command {
 		Short:   "Get antonyms for a word",
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return collectWordRelations(strings.ToLower(args[0]), "antonyms")
+			ctx := cmd.Context()
+			return collectWordRelations(ctx, strings.ToLower(args[0]), "antonyms")
 		},
 	}
 
@@ -249,36 +264,36 @@ type apiEntry struct {
 	} `json:"meanings"`
 }
 
-func fetchWord(word string) ([]apiEntry, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func fetchWord(ctx context.Context, word string) ([]apiEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	reqURL := fmt.Sprintf("%s/%s", baseURL, url.PathEscape(word))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 404 {
-		return nil, output.PrintError("not_found", "Word not found: "+word, nil)
+		return nil, output.PrintError(ctx, "not_found", "Word not found: "+word, nil)
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	var entries []apiEntry
 	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
-		return nil, output.PrintError("parse_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "parse_failed", err, nil)
 	}
 
 	return entries, nil
@@ -14,13 +14,15 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://api.stackexchange.com/2.3"
 
 var (
-	httpClient = &http.Client{Timeout: 30 * time.Second}
+	httpClient = transport.New(30 * time.Second)
 	htmlTagRe  = regexp.MustCompile(`<[^>]*>`)
 )
 
@@ -49,6 +51,16 @@ type Answer struct {
 	Body     string `json:"body"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "stackexchange",
+		Name:        "StackOverflow",
+		Group:       "knowledge",
+		Description: "Search programming Q&A from StackOverflow and StackExchange sites",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "stackexchange",
@@ -74,6 +86,7 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search questions",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := args[0]
 
 			params := url.Values{
@@ -90,8 +103,8 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			var resp seResponse
-			if err := seGet("/search/advanced", params, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := seGet(ctx, "/search/advanced", params, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			questions := make([]Question, 0, len(resp.Items))
@@ -99,7 +112,7 @@ func newSearchCmd() *cobra.Command {
 				questions = append(questions, toQuestion(&resp.Items[i], false))
 			}
 
-			return output.Print(questions)
+			return output.Print(ctx, questions)
 		},
 	}
 
@@ -119,6 +132,7 @@ func newQuestionCmd() *cobra.Command {
 		Short: "Get question details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			id := args[0]
 
 			params := url.Values{
@@ -127,15 +141,15 @@ func newQuestionCmd() *cobra.Command {
 			}
 
 			var resp seResponse
-			if err := seGet(fmt.Sprintf("/questions/%s", id), params, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := seGet(ctx, fmt.Sprintf("/questions/%s", id), params, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			if len(resp.Items) == 0 {
-				return output.PrintError("not_found", "Question not found", nil)
+				return output.PrintError(ctx, "not_found", "Question not found", nil)
 			}
 
-			return output.Print(toQuestion(&resp.Items[0], true))
+			return output.Print(ctx, toQuestion(&resp.Items[0], true))
 		},
 	}
 
@@ -154,6 +168,7 @@ func newAnswersCmd() *cobra.Command {
 		Short: "Get answers for a question",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			id := args[0]
 
 			params := url.Values{
@@ -165,8 +180,8 @@ func newAnswersCmd() *cobra.Command {
 			}
 
 			var resp seResponse
-			if err := seGet(fmt.Sprintf("/questions/%s/answers", id), params, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := seGet(ctx, fmt.Sprintf("/questions/%s/answers", id), params, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			answers := make([]Answer, 0, len(resp.Items))
@@ -174,7 +189,7 @@ func newAnswersCmd() *cobra.Command {
 				answers = append(answers, toAnswer(&resp.Items[i]))
 			}
 
-			return output.Print(answers)
+			return output.Print(ctx, answers)
 		},
 	}
 
@@ -207,8 +222,8 @@ type seItem struct {
 	} `json:"owner"`
 }
 
-func seGet(endpoint string, params url.Values, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func seGet(ctx context.Context, endpoint string, params url.Values, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	reqURL := baseURL + endpoint + "?" + params.Encode()
@@ -11,12 +11,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://en.wikipedia.org/w/api.php"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Article is LLM-friendly article output
 type Article struct {
@@ -32,6 +34,16 @@ type SearchResult struct {
 	URL     string `json:"url"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "wikipedia",
+		Name:        "Wikipedia",
+		Group:       "knowledge",
+		Description: "Search and read Wikipedia articles",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "wikipedia",
@@ -54,6 +66,7 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search Wikipedia",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := args[0]
 
 			params := url.Values{
@@ -74,8 +87,8 @@ func newSearchCmd() *cobra.Command {
 				} `json:"query"`
 			}
 
-			if err := wikiGet(params, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := wikiGet(ctx, params, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			results := make([]SearchResult, 0, len(resp.Query.Search))
@@ -87,7 +100,7 @@ func newSearchCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(results)
+			return output.Print(ctx, results)
 		},
 	}
 
@@ -104,6 +117,7 @@ func newSummaryCmd() *cobra.Command {
 		Short: "Get article summary",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			title := args[0]
 
 			params := url.Values{
@@ -127,23 +141,23 @@ func newSummaryCmd() *cobra.Command {
 				} `json:"query"`
 			}
 
-			if err := wikiGet(params, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := wikiGet(ctx, params, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			for _, page := range resp.Query.Pages {
 				if page.PageID == 0 {
-					return output.PrintError("not_found", "Article not found: "+title, nil)
+					return output.PrintError(ctx, "not_found", "Article not found: "+title, nil)
 				}
 
-				return output.Print(Article{
+				return output.Print(ctx, Article{
 					Title:   page.Title,
 					Extract: page.Extract,
 					URL:     fmt.Sprintf("https://en.wikipedia.org/wiki/%s", url.PathEscape(strings.ReplaceAll(page.Title, " ", "_"))),
 				})
 			}
 
-			return output.PrintError("not_found", "Article not found: "+title, nil)
+			return output.PrintError(ctx, "not_found", "Article not found: "+title, nil)
 		},
 	}
 
@@ -160,6 +174,7 @@ func newArticleCmd() *cobra.Command {
 		Short: "Get full article extract",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			title := args[0]
 
 			params := url.Values{
@@ -182,23 +197,23 @@ func newArticleCmd() *cobra.Command {
 				} `json:"query"`
 			}
 
-			if err := wikiGet(params, &resp); err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+			if err := wikiGet(ctx, params, &resp); err != nil {
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			for _, page := range resp.Query.Pages {
 				if page.PageID == 0 {
-					return output.PrintError("not_found", "Article not found: "+title, nil)
+					return output.PrintError(ctx, "not_found", "Article not found: "+title, nil)
 				}
 
-				return output.Print(Article{
+				return output.Print(ctx, Article{
 					Title:   page.Title,
 					Extract: page.Extract,
 					URL:     fmt.Sprintf("https://en.wikipedia.org/wiki/%s", url.PathEscape(strings.ReplaceAll(page.Title, " ", "_"))),
 				})
 			}
 
-			return output.PrintError("not_found", "Article not found: "+title, nil)
+			return output.PrintError(ctx, "not_found", "Article not found: "+title, nil)
 		},
 	}
 
@@ -207,8 +222,8 @@ func newArticleCmd() *cobra.Command {
 	return cmd
 }
 
-func wikiGet(params url.Values, result any) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func wikiGet(ctx context.Context, params url.Values, result any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	reqURL := baseURL + "?" + params.Encode()
@@ -12,6 +12,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -19,7 +22,7 @@ var baseURL = "https://sellingpartnerapi-na.amazon.com"
 
 var tokenURL = "https://api.amazon.com/auth/o2/token" //nolint:gosec // OAuth endpoint URL, not a credential
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 var regionBaseURLs = map[string]string{
 	"na": "https://sellingpartnerapi-na.amazon.com",
@@ -90,6 +93,26 @@ type ReportInfo struct {
 	CreatedTime    string `json:"created_time"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "amazon-sp",
+		Name:        "Amazon Selling Partner",
+		Group:       "marketing",
+		Description: "Manage Amazon seller orders, inventory, and reports via SP-API",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "amazon_sp_client_id", Description: "LWA Client ID from Seller Central app", Required: true},
+			{Key: "amazon_sp_client_secret", Description: "LWA Client Secret", Required: true},
+			{Key: "amazon_sp_refresh_token", Description: "LWA Refresh Token (from app authorization)", Required: true},
+			{Key: "amazon_sp_seller_id", Description: "Your Amazon Seller ID", Required: true, Example: "A1B2C3D4E5F6G7"},
+			{Key: "amazon_sp_region", Description: "Region: na, eu, or fe (default: na)", Example: "na"},
+		},
+		SetupGuide:  "1. Register as a developer at https://sellercentral.amazon.com/apps/develop\n2. Create a new SP-API application\n3. Select the APIs you need (Orders, Inventory, Reports, etc.)\n4. Note your LWA Client ID and Client Secret\n5. Self-authorize the app to get a Refresh Token\n6. Find your Seller ID in Seller Central > Settings > Account Info\n7. Run:\n   pocket config set amazon_sp_client_id <your-client-id>\n   pocket config set amazon_sp_client_secret <your-client-secret>\n   pocket config set amazon_sp_refresh_token <your-refresh-token>\n   pocket config set amazon_sp_seller_id <your-seller-id>\n\nOptional - Set region (default: na):\n   pocket config set amazon_sp_region eu",
+		TestCommand: "pocket marketing amazon-sp orders -l 1",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the amazon-sp parent command.
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -108,50 +131,50 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func newClient() (*spClient, error) {
-	clientID, err := config.Get("amazon_sp_client_id")
+func newClient(ctx context.Context) (*spClient, error) {
+	clientID, err := config.Get(ctx, "amazon_sp_client_id")
 	if err != nil {
 		return nil, err
 	}
 	if clientID == "" {
-		return nil, output.PrintError("missing_config", "Amazon SP-API client ID not configured", map[string]string{
+		return nil, output.PrintError(ctx, "missing_config", "Amazon SP-API client ID not configured", map[string]string{
 			"setup": "Run: pocket setup show amazon-sp",
 		})
 	}
 
-	clientSecret, err := config.Get("amazon_sp_client_secret")
+	clientSecret, err := config.Get(ctx, "amazon_sp_client_secret")
 	if err != nil {
 		return nil, err
 	}
 	if clientSecret == "" {
-		return nil, output.PrintError("missing_config", "Amazon SP-API client secret not configured", map[string]string{
+		return nil, output.PrintError(ctx, "missing_config", "Amazon SP-API client secret not configured", map[string]string{
 			"setup": "Run: pocket setup show amazon-sp",
 		})
 	}
 
-	refreshToken, err := config.Get("amazon_sp_refresh_token")
+	refreshToken, err := config.Get(ctx, "amazon_sp_refresh_token")
 	if err != nil {
 		return nil, err
 	}
 	if refreshToken == "" {
-		return nil, output.PrintError("missing_config", "Amazon SP-API refresh token not configured", map[string]string{
+		return nil, output.PrintError(ctx, "missing_config", "Amazon SP-API refresh token not configured", map[string]string{
 			"setup": "Run: pocket setup show amazon-sp",
 		})
 	}
 
-	sellerID, err := config.Get("amazon_sp_seller_id")
+	sellerID, err := config.Get(ctx, "amazon_sp_seller_id")
 	if err != nil {
 		return nil, err
 	}
 	if sellerID == "" {
-		return nil, output.PrintError("missing_config", "Amazon SP-API seller ID not configured", map[string]string{
+		return nil, output.PrintError(ctx, "missing_config", "Amazon SP-API seller ID not configured", map[string]string{
 			"setup": "Run: pocket setup show amazon-sp",
 		})
 	}
 
 	// Resolve region-specific base URL without mutating package-level var
 	resolvedURL := baseURL
-	region, _ := config.Get("amazon_sp_region")
+	region, _ := config.Get(ctx, "amazon_sp_region")
 	if u, ok := regionBaseURLs[region]; ok {
 		resolvedURL = u
 	}
@@ -165,8 +188,8 @@ func newClient() (*spClient, error) {
 	}
 
 	// Load cached token
-	cachedToken, _ := config.Get("amazon_sp_access_token")
-	expiryStr, _ := config.Get("amazon_sp_token_expiry")
+	cachedToken, _ := config.Get(ctx, "amazon_sp_access_token")
+	expiryStr, _ := config.Get(ctx, "amazon_sp_token_expiry")
 	if cachedToken != "" && expiryStr != "" {
 		expiry, parseErr := time.Parse(time.RFC3339, expiryStr)
 		if parseErr == nil {
@@ -178,7 +201,7 @@ func newClient() (*spClient, error) {
 	return c, nil
 }
 
-func (c *spClient) ensureAccessToken() error {
+func (c *spClient) ensureAccessToken(ctx context.Context) error {
 	if c.accessToken != "" && time.Now().Before(c.tokenExpiry) {
 		return nil
 	}
@@ -190,10 +213,10 @@ func (c *spClient) ensureAccessToken() error {
 		"client_secret": {c.clientSecret},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, bytes.NewBufferString(data.Encode()))
+	req, err := http.NewRequestWithContext(dryrun.ReadOnly(ctx), "POST", tokenURL, bytes.NewBufferString(data.Encode()))
 	if err != nil {
 		return err
 	}
@@ -221,14 +244,14 @@ func (c *spClient) ensureAccessToken() error {
 	c.tokenExpiry = time.Now().Add(time.Duration(tokenResp.ExpiresIn-60) * time.Second)
 
 	// Cache token
-	_ = config.Set("amazon_sp_access_token", c.accessToken)
-	_ = config.Set("amazon_sp_token_expiry", c.tokenExpiry.Format(time.RFC3339))
+	_ = config.Set(ctx, "amazon_sp_access_token", c.accessToken)
+	_ = config.Set(ctx, "amazon_sp_token_expiry", c.tokenExpiry.Format(time.RFC3339))
 
 	return nil
 }
 
-func (c *spClient) doGet(endpoint string, params url.Values) (map[string]any, error) {
-	if err := c.ensureAccessToken(); err != nil {
+func (c *spClient) doGet(ctx context.Context, endpoint string, params url.Values) (map[string]any, error) {
+	if err := c.ensureAccessToken(ctx); err != nil {
 		return nil, err
 	}
 
@@ -238,7 +261,7 @@ func (c *spClient) doGet(endpoint string, params url.Values) (map[string]any, er
 
 	reqURL := fmt.Sprintf("%s%s?%s", c.apiBaseURL, endpoint, params.Encode())
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
@@ -272,8 +295,8 @@ func (c *spClient) doGet(endpoint string, params url.Values) (map[string]any, er
 	return result, nil
 }
 
-func (c *spClient) doPost(endpoint string, payload any) (map[string]any, error) {
-	if err := c.ensureAccessToken(); err != nil {
+func (c *spClient) doPost(ctx context.Context, endpoint string, payload any) (map[string]any, error) {
+	if err := c.ensureAccessToken(ctx); err != nil {
 		return nil, err
 	}
 
@@ -284,7 +307,7 @@ func (c *spClient) doPost(endpoint string, payload any) (map[string]any, error)
 
 	reqURL := fmt.Sprintf("%s%s", c.apiBaseURL, endpoint)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewReader(body))
@@ -344,7 +367,8 @@ func newOrdersCmd() *cobra.Command {
 		Use:   "orders",
 		Short: "List orders",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -364,13 +388,13 @@ func newOrdersCmd() *cobra.Command {
 				params.Set("MaxResultsPerPage", fmt.Sprintf("%d", limit))
 			}
 
-			raw, err := c.doGet("/orders/v0/orders", params)
+			raw, err := c.doGet(ctx, "/orders/v0/orders", params)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			orders := extractOrders(raw)
-			return output.Print(orders)
+			return output.Print(ctx, orders)
 		},
 	}
 
@@ -389,23 +413,24 @@ func newOrderCmd() *cobra.Command {
 		Short: "Get order details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			raw, err := c.doGet("/orders/v0/orders/"+args[0], nil)
+			raw, err := c.doGet(ctx, "/orders/v0/orders/"+args[0], nil)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			payload, _ := raw["payload"].(map[string]any)
 			if payload == nil {
-				return output.Print(raw)
+				return output.Print(ctx, raw)
 			}
 
 			order := mapOrder(payload)
-			return output.Print(order)
+			return output.Print(ctx, order)
 		},
 	}
 }
@@ -416,23 +441,24 @@ func newOrderItemsCmd() *cobra.Command {
 		Short: "Get items for an order",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			raw, err := c.doGet("/orders/v0/orders/"+args[0]+"/orderItems", nil)
+			raw, err := c.doGet(ctx, "/orders/v0/orders/"+args[0]+"/orderItems", nil)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			payload, _ := raw["payload"].(map[string]any)
 			if payload == nil {
-				return output.Print(raw)
+				return output.Print(ctx, raw)
 			}
 
 			items := extractOrderItems(payload)
-			return output.Print(items)
+			return output.Print(ctx, items)
 		},
 	}
 }
@@ -447,7 +473,8 @@ func newInventoryCmd() *cobra.Command {
 		Use:   "inventory",
 		Short: "List FBA inventory summaries",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -464,13 +491,13 @@ func newInventoryCmd() *cobra.Command {
 				params.Set("maxResults", fmt.Sprintf("%d", limit))
 			}
 
-			raw, err := c.doGet("/fba/inventory/v1/summaries", params)
+			raw, err := c.doGet(ctx, "/fba/inventory/v1/summaries", params)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			summaries := extractInventory(raw)
-			return output.Print(summaries)
+			return output.Print(ctx, summaries)
 		},
 	}
 
@@ -490,13 +517,14 @@ func newReportCreateCmd() *cobra.Command {
 		Use:   "report-create",
 		Short: "Create a report request",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			if reportType == "" {
-				return output.PrintError("missing_flag", "--type is required (e.g., GET_FLAT_FILE_ALL_ORDERS_DATA_BY_ORDER_DATE_GENERAL)", nil)
+				return output.PrintError(ctx, "missing_flag", "--type is required (e.g., GET_FLAT_FILE_ALL_ORDERS_DATA_BY_ORDER_DATE_GENERAL)", nil)
 			}
 
 			payload := map[string]any{
@@ -510,12 +538,12 @@ func newReportCreateCmd() *cobra.Command {
 				payload["dataEndTime"] = endTime
 			}
 
-			raw, err := c.doPost("/reports/2021-06-30/reports", payload)
+			raw, err := c.doPost(ctx, "/reports/2021-06-30/reports", payload)
 			if err != nil {
-				return output.PrintError("create_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "create_failed", err, nil)
 			}
 
-			return output.Print(map[string]string{
+			return output.Print(ctx, map[string]string{
 				"report_id": getString(raw, "reportId"),
 				"status":    "created",
 			})
@@ -536,14 +564,15 @@ func newReportStatusCmd() *cobra.Command {
 		Short: "Get report processing status",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			raw, err := c.doGet("/reports/2021-06-30/reports/"+args[0], nil)
+			raw, err := c.doGet(ctx, "/reports/2021-06-30/reports/"+args[0], nil)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			report := ReportInfo{
@@ -554,7 +583,7 @@ func newReportStatusCmd() *cobra.Command {
 				CreatedTime:    getString(raw, "createdTime"),
 			}
 
-			return output.Print(report)
+			return output.Print(ctx, report)
 		},
 	}
 }
@@ -1,6 +1,7 @@
 package amazonsp
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -80,6 +81,7 @@ func TestNewCmdSubcommands(t *testing.T) {
 }
 
 func TestEnsureAccessToken(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Errorf("method = %s, want POST", r.Method)
@@ -119,7 +121,7 @@ func TestEnsureAccessToken(t *testing.T) {
 		sellerID:     "test_seller",
 	}
 
-	if err := c.ensureAccessToken(); err != nil {
+	if err := c.ensureAccessToken(ctx); err != nil {
 		t.Fatalf("ensureAccessToken failed: %v", err)
 	}
 
@@ -133,6 +135,7 @@ func TestEnsureAccessToken(t *testing.T) {
 }
 
 func TestEnsureAccessTokenCached(t *testing.T) {
+	ctx := context.Background()
 	c := &spClient{
 		clientID:     "test_cid",
 		clientSecret: "test_secret",
@@ -142,7 +145,7 @@ func TestEnsureAccessTokenCached(t *testing.T) {
 		tokenExpiry:  time.Now().Add(1 * time.Hour),
 	}
 
-	if err := c.ensureAccessToken(); err != nil {
+	if err := c.ensureAccessToken(ctx); err != nil {
 		t.Fatalf("ensureAccessToken failed: %v", err)
 	}
 
@@ -152,6 +155,7 @@ func TestEnsureAccessTokenCached(t *testing.T) {
 }
 
 func TestDoGetSuccess(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := r.Header.Get("x-amz-access-token")
 		if token != "test_access_token" {
@@ -178,7 +182,7 @@ func TestDoGetSuccess(t *testing.T) {
 		apiBaseURL:  ts.URL,
 	}
 
-	result, err := c.doGet("/orders/v0/orders", nil)
+	result, err := c.doGet(ctx, "/orders/v0/orders", nil)
 	if err != nil {
 		t.Fatalf("doGet failed: %v", err)
 	}
@@ -190,6 +194,7 @@ func TestDoGetSuccess(t *testing.T) {
 }
 
 func TestDoGetHTTPError(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(400)
 		_ = json.NewEncoder(w).Encode(map[string]any{
@@ -209,7 +214,7 @@ func TestDoGetHTTPError(t *testing.T) {
 		apiBaseURL:  ts.URL,
 	}
 
-	_, err := c.doGet("/orders/v0/orders", nil)
+	_, err := c.doGet(ctx, "/orders/v0/orders", nil)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -223,6 +228,7 @@ func TestDoGetHTTPError(t *testing.T) {
 }
 
 func TestDoPostSuccess(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := r.Header.Get("x-amz-access-token")
 		if token != "post_token" {
@@ -252,7 +258,7 @@ func TestDoPostSuccess(t *testing.T) {
 		apiBaseURL:  ts.URL,
 	}
 
-	result, err := c.doPost("/reports/2021-06-30/reports", map[string]any{
+	result, err := c.doPost(ctx, "/reports/2021-06-30/reports", map[string]any{
 		"reportType":     "GET_FLAT_FILE_ALL_ORDERS_DATA_BY_ORDER_DATE_GENERAL",
 		"marketplaceIds": []string{"ATVPDKIKX0DER"},
 	})
@@ -287,8 +293,9 @@ func TestNewClientMissingKeys(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
 			writeTestConfig(t, tt.config)
-			_, err := newClient()
+			_, err := newClient(ctx)
 			if err == nil {
 				t.Fatalf("expected error for %s", tt.name)
 			}
@@ -395,6 +402,7 @@ func TestParseHTTPError(t *testing.T) {
 }
 
 func TestAmazonSPConfigKeys(t *testing.T) {
+	ctx := context.Background()
 	clearTestConfig(t)
 
 	keys := map[string]string{
@@ -408,13 +416,13 @@ func TestAmazonSPConfigKeys(t *testing.T) {
 	}
 
 	for k, v := range keys {
-		if err := config.Set(k, v); err != nil {
+		if err := config.Set(ctx, k, v); err != nil {
 			t.Fatalf("config.Set(%q) failed: %v", k, err)
 		}
 	}
 
 	for k, want := range keys {
-		got, err := config.Get(k)
+		got, err := config.Get(ctx, k)
 		if err != nil {
 			t.Fatalf("config.Get(%q) failed: %v", k, err)
 		}
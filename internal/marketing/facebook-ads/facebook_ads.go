@@ -13,12 +13,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://graph.facebook.com/v24.0"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // fbClient holds credentials for Meta Marketing API calls.
 type fbClient struct {
@@ -94,6 +96,23 @@ type Insight struct {
 	Actions     []any  `json:"actions,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "facebook-ads",
+		Name:        "Facebook Ads (Meta)",
+		Group:       "marketing",
+		Description: "Manage Facebook/Meta ad campaigns, ad sets, ads, and view performance insights",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "facebook_ads_token", Description: "Long-lived access token from Meta Business Manager", Required: true},
+			{Key: "facebook_ads_account_id", Description: "Ad account ID (numeric, without act_ prefix)", Required: true, Example: "123456789"},
+		},
+		SetupGuide:  "1. Go to https://business.facebook.com/settings/\n2. Navigate to Users > System Users\n3. Create a system user (or use existing) with 'Admin' role\n4. Click \"Generate New Token\" for the system user\n5. Select your app, then add permissions:\n   - ads_management, ads_read, business_management\n6. Generate the token (this is a long-lived token)\n7. Find your Ad Account ID in Business Settings > Accounts > Ad Accounts\n8. Run:\n   pocket config set facebook_ads_token <your-token>\n   pocket config set facebook_ads_account_id <your-account-id>\n\nNote: Use OUTCOME-based objectives for campaigns (e.g. OUTCOME_TRAFFIC, OUTCOME_SALES).\nLegacy objectives cause 400 errors on API v24.0+.",
+		TestCommand: "pocket marketing facebook-ads account",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the facebook-ads parent command.
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -114,23 +133,23 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func newClient() (*fbClient, error) {
-	token, err := config.Get("facebook_ads_token")
+func newClient(ctx context.Context) (*fbClient, error) {
+	token, err := config.Get(ctx, "facebook_ads_token")
 	if err != nil {
 		return nil, err
 	}
 	if token == "" {
-		return nil, output.PrintError("missing_config", "Facebook Ads token not configured", map[string]string{
+		return nil, output.PrintError(ctx, "missing_config", "Facebook Ads token not configured", map[string]string{
 			"setup": "Run: pocket setup show facebook-ads",
 		})
 	}
 
-	accountID, err := config.Get("facebook_ads_account_id")
+	accountID, err := config.Get(ctx, "facebook_ads_account_id")
 	if err != nil {
 		return nil, err
 	}
 	if accountID == "" {
-		return nil, output.PrintError("missing_config", "Facebook Ads account ID not configured", map[string]string{
+		return nil, output.PrintError(ctx, "missing_config", "Facebook Ads account ID not configured", map[string]string{
 			"setup": "Run: pocket config set facebook_ads_account_id YOUR_AD_ACCOUNT_ID",
 		})
 	}
@@ -145,14 +164,14 @@ func (c *fbClient) actID() string {
 	return "act_" + c.accountID
 }
 
-func (c *fbClient) doGet(endpoint string, params url.Values) (map[string]any, error) {
+func (c *fbClient) doGet(ctx context.Context, endpoint string, params url.Values) (map[string]any, error) {
 	if params == nil {
 		params = url.Values{}
 	}
 
 	reqURL := fmt.Sprintf("%s/%s?%s", baseURL, endpoint, params.Encode())
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
@@ -186,7 +205,7 @@ func (c *fbClient) doGet(endpoint string, params url.Values) (map[string]any, er
 	return result, nil
 }
 
-func (c *fbClient) doPost(endpoint string, payload map[string]string) (map[string]any, error) {
+func (c *fbClient) doPost(ctx context.Context, endpoint string, payload map[string]string) (map[string]any, error) {
 	form := url.Values{}
 	for k, v := range payload {
 		form.Set(k, v)
@@ -194,7 +213,7 @@ func (c *fbClient) doPost(endpoint string, payload map[string]string) (map[strin
 
 	reqURL := fmt.Sprintf("%s/%s", baseURL, endpoint)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "POST", reqURL, bytes.NewBufferString(form.Encode()))
@@ -258,7 +277,8 @@ func newAccountCmd() *cobra.Command {
 		Use:   "account",
 		Short: "Get ad account details",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -266,9 +286,9 @@ func newAccountCmd() *cobra.Command {
 			params := url.Values{}
 			params.Set("fields", "id,name,account_id,account_status,currency,timezone_id,timezone_name,amount_spent,balance,spend_cap,business_name,business_country_code")
 
-			raw, err := c.doGet(c.actID(), params)
+			raw, err := c.doGet(ctx, c.actID(), params)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			acct := Account{
@@ -286,7 +306,7 @@ func newAccountCmd() *cobra.Command {
 				BusinessCountry: getString(raw, "business_country_code"),
 			}
 
-			return output.Print(acct)
+			return output.Print(ctx, acct)
 		},
 	}
 }
@@ -301,7 +321,8 @@ func newCampaignsCmd() *cobra.Command {
 		Use:   "campaigns",
 		Short: "List campaigns",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -313,9 +334,9 @@ func newCampaignsCmd() *cobra.Command {
 				params.Set("effective_status", fmt.Sprintf(`["%s"]`, strings.ToUpper(status))) //nolint:gocritic // Facebook API requires this JSON array format
 			}
 
-			raw, err := c.doGet(c.actID()+"/campaigns", params)
+			raw, err := c.doGet(ctx, c.actID()+"/campaigns", params)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			data := getDataArray(raw)
@@ -338,7 +359,7 @@ func newCampaignsCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -356,16 +377,17 @@ func newCampaignCreateCmd() *cobra.Command {
 		Use:   "campaign-create",
 		Short: "Create a new campaign",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			if name == "" {
-				return output.PrintError("missing_flag", "--name is required", nil)
+				return output.PrintError(ctx, "missing_flag", "--name is required", nil)
 			}
 			if objective == "" {
-				return output.PrintError("missing_flag", "--objective is required (OUTCOME_AWARENESS, OUTCOME_TRAFFIC, OUTCOME_ENGAGEMENT, OUTCOME_LEADS, OUTCOME_APP_PROMOTION, OUTCOME_SALES)", nil)
+				return output.PrintError(ctx, "missing_flag", "--objective is required (OUTCOME_AWARENESS, OUTCOME_TRAFFIC, OUTCOME_ENGAGEMENT, OUTCOME_LEADS, OUTCOME_APP_PROMOTION, OUTCOME_SALES)", nil)
 			}
 
 			payload := map[string]string{
@@ -381,12 +403,12 @@ func newCampaignCreateCmd() *cobra.Command {
 			catJSON, _ := json.Marshal(specialCategories)
 			payload["special_ad_categories"] = string(catJSON)
 
-			raw, err := c.doPost(c.actID()+"/campaigns", payload)
+			raw, err := c.doPost(ctx, c.actID()+"/campaigns", payload)
 			if err != nil {
-				return output.PrintError("create_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "create_failed", err, nil)
 			}
 
-			return output.Print(map[string]string{
+			return output.Print(ctx, map[string]string{
 				"id":     getString(raw, "id"),
 				"status": "created",
 			})
@@ -410,7 +432,8 @@ func newCampaignUpdateCmd() *cobra.Command {
 		Short: "Update a campaign",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -427,16 +450,16 @@ func newCampaignUpdateCmd() *cobra.Command {
 			}
 
 			if len(payload) == 0 {
-				return output.PrintError("no_changes", "Provide at least one flag: --name, --status, or --daily-budget", nil)
+				return output.PrintError(ctx, "no_changes", "Provide at least one flag: --name, --status, or --daily-budget", nil)
 			}
 
-			raw, err := c.doPost(args[0], payload)
+			raw, err := c.doPost(ctx, args[0], payload)
 			if err != nil {
-				return output.PrintError("update_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "update_failed", err, nil)
 			}
 
 			success := getBool(raw, "success")
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"id":      args[0],
 				"success": success,
 			})
@@ -460,7 +483,8 @@ func newAdSetsCmd() *cobra.Command {
 		Use:   "adsets",
 		Short: "List ad sets",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -477,9 +501,9 @@ func newAdSetsCmd() *cobra.Command {
 				endpoint = campaignID + "/adsets"
 			}
 
-			raw, err := c.doGet(endpoint, params)
+			raw, err := c.doGet(ctx, endpoint, params)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			data := getDataArray(raw)
@@ -503,7 +527,7 @@ func newAdSetsCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -521,22 +545,23 @@ func newAdSetCreateCmd() *cobra.Command {
 		Use:   "adset-create",
 		Short: "Create a new ad set",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			if name == "" {
-				return output.PrintError("missing_flag", "--name is required", nil)
+				return output.PrintError(ctx, "missing_flag", "--name is required", nil)
 			}
 			if campaignID == "" {
-				return output.PrintError("missing_flag", "--campaign-id is required", nil)
+				return output.PrintError(ctx, "missing_flag", "--campaign-id is required", nil)
 			}
 			if billingEvent == "" {
-				return output.PrintError("missing_flag", "--billing-event is required (IMPRESSIONS, LINK_CLICKS, etc.)", nil)
+				return output.PrintError(ctx, "missing_flag", "--billing-event is required (IMPRESSIONS, LINK_CLICKS, etc.)", nil)
 			}
 			if optimizationGoal == "" {
-				return output.PrintError("missing_flag", "--optimization-goal is required (REACH, LINK_CLICKS, IMPRESSIONS, etc.)", nil)
+				return output.PrintError(ctx, "missing_flag", "--optimization-goal is required (REACH, LINK_CLICKS, IMPRESSIONS, etc.)", nil)
 			}
 
 			payload := map[string]string{
@@ -547,12 +572,12 @@ func newAdSetCreateCmd() *cobra.Command {
 				"status":            strings.ToUpper(status),
 			}
 
-			raw, err := c.doPost(c.actID()+"/adsets", payload)
+			raw, err := c.doPost(ctx, c.actID()+"/adsets", payload)
 			if err != nil {
-				return output.PrintError("create_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "create_failed", err, nil)
 			}
 
-			return output.Print(map[string]string{
+			return output.Print(ctx, map[string]string{
 				"id":     getString(raw, "id"),
 				"status": "created",
 			})
@@ -578,7 +603,8 @@ func newAdsCmd() *cobra.Command {
 		Use:   "ads",
 		Short: "List ads",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -595,9 +621,9 @@ func newAdsCmd() *cobra.Command {
 				endpoint = adsetID + "/ads"
 			}
 
-			raw, err := c.doGet(endpoint, params)
+			raw, err := c.doGet(ctx, endpoint, params)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			data := getDataArray(raw)
@@ -618,7 +644,7 @@ func newAdsCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -638,7 +664,8 @@ func newInsightsCmd() *cobra.Command {
 		Use:   "insights",
 		Short: "Get ad performance insights",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -661,9 +688,9 @@ func newInsightsCmd() *cobra.Command {
 				params.Set("level", level)
 			}
 
-			raw, err := c.doGet(endpoint, params)
+			raw, err := c.doGet(ctx, endpoint, params)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			data := getDataArray(raw)
@@ -690,7 +717,7 @@ func newInsightsCmd() *cobra.Command {
 				result = append(result, insight)
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
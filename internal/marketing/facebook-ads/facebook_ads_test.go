@@ -1,6 +1,7 @@
 package facebookads
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -190,6 +191,7 @@ func TestNewCmdSubcommands(t *testing.T) {
 }
 
 func TestDoGetSuccess(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		auth := r.Header.Get("Authorization")
 		if auth != "Bearer test_token_123" {
@@ -213,7 +215,7 @@ func TestDoGetSuccess(t *testing.T) {
 	defer func() { baseURL = origURL }()
 
 	c := &fbClient{token: "test_token_123", accountID: "act_999"}
-	result, err := c.doGet("act_999/campaigns", nil)
+	result, err := c.doGet(ctx, "act_999/campaigns", nil)
 	if err != nil {
 		t.Fatalf("doGet failed: %v", err)
 	}
@@ -230,6 +232,7 @@ func TestDoGetSuccess(t *testing.T) {
 }
 
 func TestDoGetAPIError(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(400)
 		_ = json.NewEncoder(w).Encode(map[string]any{
@@ -247,7 +250,7 @@ func TestDoGetAPIError(t *testing.T) {
 	defer func() { baseURL = origURL }()
 
 	c := &fbClient{token: "bad_token", accountID: "act_999"}
-	_, err := c.doGet("act_999/campaigns", nil)
+	_, err := c.doGet(ctx, "act_999/campaigns", nil)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -261,6 +264,7 @@ func TestDoGetAPIError(t *testing.T) {
 }
 
 func TestDoGetHTTPError(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(500)
 		_, _ = w.Write([]byte("internal error"))
@@ -272,7 +276,7 @@ func TestDoGetHTTPError(t *testing.T) {
 	defer func() { baseURL = origURL }()
 
 	c := &fbClient{token: "tok", accountID: "act_1"}
-	_, err := c.doGet("endpoint", nil)
+	_, err := c.doGet(ctx, "endpoint", nil)
 	if err == nil {
 		t.Fatal("expected error for 500 response")
 	}
@@ -283,6 +287,7 @@ func TestDoGetHTTPError(t *testing.T) {
 }
 
 func TestDoPostSuccess(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		auth := r.Header.Get("Authorization")
 		if auth != "Bearer post_token" {
@@ -311,7 +316,7 @@ func TestDoPostSuccess(t *testing.T) {
 	defer func() { baseURL = origURL }()
 
 	c := &fbClient{token: "post_token", accountID: "act_1"}
-	result, err := c.doPost("act_1/campaigns", map[string]string{"name": "Test"})
+	result, err := c.doPost(ctx, "act_1/campaigns", map[string]string{"name": "Test"})
 	if err != nil {
 		t.Fatalf("doPost failed: %v", err)
 	}
@@ -322,6 +327,7 @@ func TestDoPostSuccess(t *testing.T) {
 }
 
 func TestDoPostHTTPError(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(401)
 		_, _ = w.Write([]byte("unauthorized"))
@@ -333,7 +339,7 @@ func TestDoPostHTTPError(t *testing.T) {
 	defer func() { baseURL = origURL }()
 
 	c := &fbClient{token: "bad", accountID: "act_1"}
-	_, err := c.doPost("endpoint", map[string]string{"k": "v"})
+	_, err := c.doPost(ctx, "endpoint", map[string]string{"k": "v"})
 	if err == nil {
 		t.Fatal("expected error for 401 response")
 	}
@@ -344,34 +350,37 @@ func TestDoPostHTTPError(t *testing.T) {
 }
 
 func TestNewClientMissingToken(t *testing.T) {
+	ctx := context.Background()
 	writeTestConfig(t, map[string]string{
 		"facebook_ads_account_id": "123",
 	})
 
-	_, err := newClient()
+	_, err := newClient(ctx)
 	if err == nil {
 		t.Fatal("expected error for missing token")
 	}
 }
 
 func TestNewClientMissingAccountID(t *testing.T) {
+	ctx := context.Background()
 	writeTestConfig(t, map[string]string{
 		"facebook_ads_token": "test_token",
 	})
 
-	_, err := newClient()
+	_, err := newClient(ctx)
 	if err == nil {
 		t.Fatal("expected error for missing account ID")
 	}
 }
 
 func TestNewClientSuccess(t *testing.T) {
+	ctx := context.Background()
 	writeTestConfig(t, map[string]string{
 		"facebook_ads_token":      "valid_token",
 		"facebook_ads_account_id": "12345",
 	})
 
-	c, err := newClient()
+	c, err := newClient(ctx)
 	if err != nil {
 		t.Fatalf("expected success, got error: %v", err)
 	}
@@ -384,6 +393,7 @@ func TestNewClientSuccess(t *testing.T) {
 }
 
 func TestFacebookAdsConfigKeys(t *testing.T) {
+	ctx := context.Background()
 	clearTestConfig(t)
 
 	// Verify round-trip through config.Set/Get
@@ -393,13 +403,13 @@ func TestFacebookAdsConfigKeys(t *testing.T) {
 	}
 
 	for k, v := range keys {
-		if err := config.Set(k, v); err != nil {
+		if err := config.Set(ctx, k, v); err != nil {
 			t.Fatalf("config.Set(%q) failed: %v", k, err)
 		}
 	}
 
 	for k, want := range keys {
-		got, err := config.Get(k)
+		got, err := config.Get(ctx, k)
 		if err != nil {
 			t.Fatalf("config.Get(%q) failed: %v", k, err)
 		}
@@ -7,11 +7,15 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -19,7 +23,7 @@ var apiVersion = "2026-01"
 
 var baseURL = "" // computed from store name; empty for test override
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // shopClient holds credentials for Shopify Admin API calls.
 type shopClient struct {
@@ -90,6 +94,23 @@ type InventoryLevel struct {
 	UpdatedAt       string `json:"updated_at"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "shopify",
+		Name:        "Shopify",
+		Group:       "marketing",
+		Description: "Manage Shopify store: orders, products, customers, and inventory",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "shopify_store", Description: "Your Shopify store name (the xxx in xxx.myshopify.com)", Required: true, Example: "my-store"},
+			{Key: "shopify_token", Description: "Admin API access token", Required: true, Example: "shpat_xxxxxxxxxxxx"},
+		},
+		SetupGuide:  "1. Go to your Shopify admin: https://YOUR-STORE.myshopify.com/admin\n2. Navigate to Settings > Apps and sales channels > Develop apps\n3. Click \"Create an app\" and name it\n4. Configure Admin API scopes:\n   - read_orders, read_products, read_customers, read_inventory\n   - write_inventory (if you need inventory-set)\n5. Install the app and copy the Admin API access token\n6. Run:\n   pocket config set shopify_store <your-store-name>\n   pocket config set shopify_token <your-access-token>\n\nNote: The store name is the subdomain part of your .myshopify.com URL.",
+		TestCommand: "pocket marketing shopify shop",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the shopify parent command.
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -111,23 +132,23 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func newClient() (*shopClient, error) {
-	store, err := config.Get("shopify_store")
+func newClient(ctx context.Context) (*shopClient, error) {
+	store, err := config.Get(ctx, "shopify_store")
 	if err != nil {
 		return nil, err
 	}
 	if store == "" {
-		return nil, output.PrintError("missing_config", "Shopify store not configured", map[string]string{
+		return nil, output.PrintError(ctx, "missing_config", "Shopify store not configured", map[string]string{
 			"setup": "Run: pocket setup show shopify",
 		})
 	}
 
-	token, err := config.Get("shopify_token")
+	token, err := config.Get(ctx, "shopify_token")
 	if err != nil {
 		return nil, err
 	}
 	if token == "" {
-		return nil, output.PrintError("missing_config", "Shopify access token not configured", map[string]string{
+		return nil, output.PrintError(ctx, "missing_config", "Shopify access token not configured", map[string]string{
 			"setup": "Run: pocket setup show shopify",
 		})
 	}
@@ -141,26 +162,58 @@ func newClient() (*shopClient, error) {
 	return &shopClient{store: store, token: token, apiBaseURL: resolvedURL}, nil
 }
 
-func (c *shopClient) doGet(endpoint string, params url.Values) (map[string]any, error) {
+func (c *shopClient) doGet(ctx context.Context, endpoint string, params url.Values) (map[string]any, error) {
 	if params == nil {
 		params = url.Values{}
 	}
 
-	reqURL := fmt.Sprintf("%s/%s?%s", c.apiBaseURL, endpoint, params.Encode())
+	result, _, err := c.getURL(ctx, fmt.Sprintf("%s/%s?%s", c.apiBaseURL, endpoint, params.Encode()))
+	return result, err
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// list prints a list endpoint page by page. The page_info cursor in
+// Shopify's Link header stands in for every filter but limit, so params
+// only apply to the first page.
+func list[T any](ctx context.Context, c *shopClient, endpoint string, params url.Values, opts paginate.Options, extract func(map[string]any) []T) error {
+	err := paginate.Run(ctx, opts, func(cursor string, size int) (paginate.Page[T], error) {
+		u, err := url.Parse(fmt.Sprintf("%s/%s?%s", c.apiBaseURL, endpoint, params.Encode()))
+		if cursor != "" {
+			u, err = paginate.LinkURL(cursor, c.apiBaseURL)
+		}
+		if err != nil {
+			return paginate.Page[T]{}, err
+		}
+		q := u.Query()
+		q.Set("limit", strconv.Itoa(size))
+		u.RawQuery = q.Encode()
+
+		raw, next, err := c.getURL(ctx, u.String())
+		if err != nil {
+			return paginate.Page[T]{}, err
+		}
+		return paginate.Page[T]{Items: extract(raw), Next: next}, nil
+	})
+	if err != nil && !output.IsPrinted(err) {
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
+	}
+	return err
+}
+
+// getURL fetches reqURL and returns the decoded body and next page link
+func (c *shopClient) getURL(ctx context.Context, reqURL string) (map[string]any, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	req.Header.Set("X-Shopify-Access-Token", c.token)
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer resp.Body.Close()
 
@@ -168,21 +221,21 @@ func (c *shopClient) doGet(endpoint string, params url.Values) (map[string]any,
 		var errResp map[string]any
 		if decErr := json.NewDecoder(resp.Body).Decode(&errResp); decErr == nil {
 			if apiErr := parseShopifyError(errResp, resp.StatusCode); apiErr != nil {
-				return nil, apiErr
+				return nil, "", apiErr
 			}
 		}
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
+		return nil, "", fmt.Errorf("HTTP %d: %s", resp.StatusCode, resp.Status)
 	}
 
 	var result map[string]any
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return nil, "", fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return result, nil
+	return result, paginate.NextLink(resp.Header), nil
 }
 
-func (c *shopClient) doMutationRequest(method, endpoint string, payload any) (map[string]any, error) {
+func (c *shopClient) doMutationRequest(ctx context.Context, method, endpoint string, payload any) (map[string]any, error) {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return nil, err
@@ -190,7 +243,7 @@ func (c *shopClient) doMutationRequest(method, endpoint string, payload any) (ma
 
 	reqURL := fmt.Sprintf("%s/%s", c.apiBaseURL, endpoint)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, method, reqURL, bytes.NewReader(body))
@@ -225,18 +278,18 @@ func (c *shopClient) doMutationRequest(method, endpoint string, payload any) (ma
 	return result, nil
 }
 
-func (c *shopClient) doPost(endpoint string, payload any) (map[string]any, error) {
-	return c.doMutationRequest("POST", endpoint, payload)
+func (c *shopClient) doPost(ctx context.Context, endpoint string, payload any) (map[string]any, error) {
+	return c.doMutationRequest(ctx, "POST", endpoint, payload)
 }
 
-func (c *shopClient) doPut(endpoint string, payload any) (map[string]any, error) {
-	return c.doMutationRequest("PUT", endpoint, payload)
+func (c *shopClient) doPut(ctx context.Context, endpoint string, payload any) (map[string]any, error) {
+	return c.doMutationRequest(ctx, "PUT", endpoint, payload)
 }
 
-func (c *shopClient) doDelete(endpoint string) error {
+func (c *shopClient) doDelete(ctx context.Context, endpoint string) error {
 	reqURL := fmt.Sprintf("%s/%s", c.apiBaseURL, endpoint)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "DELETE", reqURL, http.NoBody)
@@ -284,19 +337,20 @@ func newShopCmd() *cobra.Command {
 		Use:   "shop",
 		Short: "Get store info",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			raw, err := c.doGet("shop.json", nil)
+			raw, err := c.doGet(ctx, "shop.json", nil)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			shopData, _ := raw["shop"].(map[string]any)
 			if shopData == nil {
-				return output.Print(raw)
+				return output.Print(ctx, raw)
 			}
 
 			shop := Shop{
@@ -312,7 +366,7 @@ func newShopCmd() *cobra.Command {
 				CreatedAt:    getString(shopData, "created_at"),
 			}
 
-			return output.Print(shop)
+			return output.Print(ctx, shop)
 		},
 	}
 }
@@ -321,20 +375,20 @@ func newShopCmd() *cobra.Command {
 
 //nolint:dupl // structurally similar but different parameters and extraction logic
 func newOrdersCmd() *cobra.Command {
-	var limit int
+	var page paginate.Options
 	var status, since, financial string
 
 	cmd := &cobra.Command{
 		Use:   "orders",
 		Short: "List orders",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			params := url.Values{}
-			params.Set("limit", fmt.Sprintf("%d", limit))
 			if status != "" {
 				params.Set("status", status)
 			}
@@ -345,17 +399,11 @@ func newOrdersCmd() *cobra.Command {
 				params.Set("financial_status", financial)
 			}
 
-			raw, err := c.doGet("orders.json", params)
-			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
-			}
-
-			orders := extractOrders(raw)
-			return output.Print(orders)
+			return list(ctx, c, "orders.json", params, page, extractOrders)
 		},
 	}
 
-	cmd.Flags().IntVarP(&limit, "limit", "l", 50, "Number of orders to return")
+	paginate.AddFlags(cmd, &page, 50, 250, "orders to return")
 	cmd.Flags().StringVar(&status, "status", "", "Filter by status: open, closed, canceled, any")
 	cmd.Flags().StringVar(&since, "since", "", "Created after date (ISO 8601)")
 	cmd.Flags().StringVar(&financial, "financial", "", "Filter: paid, pending, refunded, etc.")
@@ -369,23 +417,24 @@ func newOrderCmd() *cobra.Command {
 		Short: "Get order details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			raw, err := c.doGet("orders/"+args[0]+".json", nil)
+			raw, err := c.doGet(ctx, "orders/"+args[0]+".json", nil)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			orderData, _ := raw["order"].(map[string]any)
 			if orderData == nil {
-				return output.Print(raw)
+				return output.Print(ctx, raw)
 			}
 
 			order := mapOrder(orderData)
-			return output.Print(order)
+			return output.Print(ctx, order)
 		},
 	}
 }
@@ -394,20 +443,20 @@ func newOrderCmd() *cobra.Command {
 
 //nolint:dupl // structurally similar but different parameters and extraction logic
 func newProductsCmd() *cobra.Command {
-	var limit int
+	var page paginate.Options
 	var status, vendor, collection string
 
 	cmd := &cobra.Command{
 		Use:   "products",
 		Short: "List products",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			params := url.Values{}
-			params.Set("limit", fmt.Sprintf("%d", limit))
 			if status != "" {
 				params.Set("status", status)
 			}
@@ -418,17 +467,11 @@ func newProductsCmd() *cobra.Command {
 				params.Set("collection_id", collection)
 			}
 
-			raw, err := c.doGet("products.json", params)
-			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
-			}
-
-			products := extractProducts(raw)
-			return output.Print(products)
+			return list(ctx, c, "products.json", params, page, extractProducts)
 		},
 	}
 
-	cmd.Flags().IntVarP(&limit, "limit", "l", 50, "Number of products to return")
+	paginate.AddFlags(cmd, &page, 50, 250, "products to return")
 	cmd.Flags().StringVar(&status, "status", "", "Filter by status: active, archived, draft")
 	cmd.Flags().StringVar(&vendor, "vendor", "", "Filter by vendor name")
 	cmd.Flags().StringVar(&collection, "collection", "", "Filter by collection ID")
@@ -442,23 +485,24 @@ func newProductCmd() *cobra.Command {
 		Short: "Get product details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			raw, err := c.doGet("products/"+args[0]+".json", nil)
+			raw, err := c.doGet(ctx, "products/"+args[0]+".json", nil)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			prodData, _ := raw["product"].(map[string]any)
 			if prodData == nil {
-				return output.Print(raw)
+				return output.Print(ctx, raw)
 			}
 
 			product := mapProduct(prodData)
-			return output.Print(product)
+			return output.Print(ctx, product)
 		},
 	}
 }
@@ -466,31 +510,25 @@ func newProductCmd() *cobra.Command {
 // --- customers ---
 
 func newCustomersCmd() *cobra.Command {
-	var limit int
+	var page paginate.Options
 
 	cmd := &cobra.Command{
 		Use:   "customers",
 		Short: "List customers",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			params := url.Values{}
-			params.Set("limit", fmt.Sprintf("%d", limit))
 
-			raw, err := c.doGet("customers.json", params)
-			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
-			}
-
-			customers := extractCustomers(raw)
-			return output.Print(customers)
+			return list(ctx, c, "customers.json", params, page, extractCustomers)
 		},
 	}
 
-	cmd.Flags().IntVarP(&limit, "limit", "l", 50, "Number of customers to return")
+	paginate.AddFlags(cmd, &page, 50, 250, "customers to return")
 
 	return cmd
 }
@@ -503,7 +541,8 @@ func newCustomerSearchCmd() *cobra.Command {
 		Short: "Search customers",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -512,13 +551,13 @@ func newCustomerSearchCmd() *cobra.Command {
 			params.Set("query", args[0])
 			params.Set("limit", fmt.Sprintf("%d", limit))
 
-			raw, err := c.doGet("customers/search.json", params)
+			raw, err := c.doGet(ctx, "customers/search.json", params)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			customers := extractCustomers(raw)
-			return output.Print(customers)
+			return output.Print(ctx, customers)
 		},
 	}
 
@@ -537,26 +576,27 @@ func newInventoryCmd() *cobra.Command {
 		Use:   "inventory",
 		Short: "List inventory levels at a location",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			if locationID == "" {
-				return output.PrintError("missing_flag", "--location is required", nil)
+				return output.PrintError(ctx, "missing_flag", "--location is required", nil)
 			}
 
 			params := url.Values{}
 			params.Set("location_ids", locationID)
 			params.Set("limit", fmt.Sprintf("%d", limit))
 
-			raw, err := c.doGet("inventory_levels.json", params)
+			raw, err := c.doGet(ctx, "inventory_levels.json", params)
 			if err != nil {
-				return output.PrintError("fetch_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "fetch_failed", err, nil)
 			}
 
 			levels := extractInventoryLevels(raw)
-			return output.Print(levels)
+			return output.Print(ctx, levels)
 		},
 	}
 
@@ -574,19 +614,20 @@ func newInventorySetCmd() *cobra.Command {
 		Use:   "inventory-set",
 		Short: "Set inventory level for an item at a location",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			c, err := newClient()
+			ctx := cmd.Context()
+			c, err := newClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			if itemID == "" {
-				return output.PrintError("missing_flag", "--item is required (inventory_item_id)", nil)
+				return output.PrintError(ctx, "missing_flag", "--item is required (inventory_item_id)", nil)
 			}
 			if locationID == "" {
-				return output.PrintError("missing_flag", "--location is required (location_id)", nil)
+				return output.PrintError(ctx, "missing_flag", "--location is required (location_id)", nil)
 			}
 			if !cmd.Flags().Changed("available") {
-				return output.PrintError("missing_flag", "--available is required", nil)
+				return output.PrintError(ctx, "missing_flag", "--available is required", nil)
 			}
 
 			payload := map[string]any{
@@ -595,17 +636,17 @@ func newInventorySetCmd() *cobra.Command {
 				"available":         available,
 			}
 
-			raw, err := c.doPost("inventory_levels/set.json", payload)
+			raw, err := c.doPost(ctx, "inventory_levels/set.json", payload)
 			if err != nil {
-				return output.PrintError("set_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "set_failed", err, nil)
 			}
 
 			level, _ := raw["inventory_level"].(map[string]any)
 			if level == nil {
-				return output.Print(raw)
+				return output.Print(ctx, raw)
 			}
 
-			return output.Print(InventoryLevel{
+			return output.Print(ctx, InventoryLevel{
 				InventoryItemID: getInt64(level, "inventory_item_id"),
 				LocationID:      getInt64(level, "location_id"),
 				Available:       getInt(level, "available"),
@@ -1,6 +1,7 @@
 package shopify
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
@@ -82,6 +83,7 @@ func TestNewCmdSubcommands(t *testing.T) {
 }
 
 func TestDoGetSuccess(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := r.Header.Get("X-Shopify-Access-Token")
 		if token != "test_shop_token" {
@@ -102,7 +104,7 @@ func TestDoGetSuccess(t *testing.T) {
 	defer ts.Close()
 
 	c := &shopClient{store: "test-store", token: "test_shop_token", apiBaseURL: ts.URL}
-	result, err := c.doGet("shop.json", nil)
+	result, err := c.doGet(ctx, "shop.json", nil)
 	if err != nil {
 		t.Fatalf("doGet failed: %v", err)
 	}
@@ -117,6 +119,7 @@ func TestDoGetSuccess(t *testing.T) {
 }
 
 func TestDoGetHTTPError(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(401)
 		_ = json.NewEncoder(w).Encode(map[string]any{
@@ -126,7 +129,7 @@ func TestDoGetHTTPError(t *testing.T) {
 	defer ts.Close()
 
 	c := &shopClient{store: "test", token: "bad_token", apiBaseURL: ts.URL}
-	_, err := c.doGet("shop.json", nil)
+	_, err := c.doGet(ctx, "shop.json", nil)
 	if err == nil {
 		t.Fatal("expected error, got nil")
 	}
@@ -137,6 +140,7 @@ func TestDoGetHTTPError(t *testing.T) {
 }
 
 func TestDoPostSuccess(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := r.Header.Get("X-Shopify-Access-Token")
 		if token != "post_token" {
@@ -167,7 +171,7 @@ func TestDoPostSuccess(t *testing.T) {
 	defer ts.Close()
 
 	c := &shopClient{store: "test", token: "post_token", apiBaseURL: ts.URL}
-	result, err := c.doPost("inventory_levels/set.json", map[string]any{
+	result, err := c.doPost(ctx, "inventory_levels/set.json", map[string]any{
 		"inventory_item_id": 808950810,
 		"location_id":       905684977,
 		"available":         42,
@@ -183,6 +187,7 @@ func TestDoPostSuccess(t *testing.T) {
 }
 
 func TestDoPutSuccess(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := r.Header.Get("X-Shopify-Access-Token")
 		if token != "put_token" {
@@ -207,7 +212,7 @@ func TestDoPutSuccess(t *testing.T) {
 	defer ts.Close()
 
 	c := &shopClient{store: "test", token: "put_token", apiBaseURL: ts.URL}
-	result, err := c.doPut("products/123.json", map[string]any{
+	result, err := c.doPut(ctx, "products/123.json", map[string]any{
 		"product": map[string]any{"title": "Updated Product"},
 	})
 	if err != nil {
@@ -224,6 +229,7 @@ func TestDoPutSuccess(t *testing.T) {
 }
 
 func TestDoDeleteSuccess(t *testing.T) {
+	ctx := context.Background()
 	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		token := r.Header.Get("X-Shopify-Access-Token")
 		if token != "del_token" {
@@ -239,13 +245,14 @@ func TestDoDeleteSuccess(t *testing.T) {
 	defer ts.Close()
 
 	c := &shopClient{store: "test", token: "del_token", apiBaseURL: ts.URL}
-	err := c.doDelete("products/123.json")
+	err := c.doDelete(ctx, "products/123.json")
 	if err != nil {
 		t.Fatalf("doDelete failed: %v", err)
 	}
 }
 
 func TestNewClientMissingStore(t *testing.T) {
+	ctx := context.Background()
 	// Reset baseURL so newClient will try to compute it
 	origURL := baseURL
 	baseURL = ""
@@ -255,13 +262,14 @@ func TestNewClientMissingStore(t *testing.T) {
 		"shopify_token": "tok",
 	})
 
-	_, err := newClient()
+	_, err := newClient(ctx)
 	if err == nil {
 		t.Fatal("expected error for missing store")
 	}
 }
 
 func TestNewClientMissingToken(t *testing.T) {
+	ctx := context.Background()
 	origURL := baseURL
 	baseURL = ""
 	defer func() { baseURL = origURL }()
@@ -270,7 +278,7 @@ func TestNewClientMissingToken(t *testing.T) {
 		"shopify_store": "my-store",
 	})
 
-	_, err := newClient()
+	_, err := newClient(ctx)
 	if err == nil {
 		t.Fatal("expected error for missing token")
 	}
@@ -350,6 +358,7 @@ func TestParseShopifyError(t *testing.T) {
 }
 
 func TestShopifyConfigKeys(t *testing.T) {
+	ctx := context.Background()
 	clearTestConfig(t)
 
 	keys := map[string]string{
@@ -358,13 +367,13 @@ func TestShopifyConfigKeys(t *testing.T) {
 	}
 
 	for k, v := range keys {
-		if err := config.Set(k, v); err != nil {
+		if err := config.Set(ctx, k, v); err != nil {
 			t.Fatalf("config.Set(%q) failed: %v", k, err)
 		}
 	}
 
 	for k, want := range keys {
-		got, err := config.Get(k)
+		got, err := config.Get(ctx, k)
 		if err != nil {
 			t.Fatalf("config.Get(%q) failed: %v", k, err)
 		}
@@ -0,0 +1,241 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/batch"
+	"github.com/unstablemind/pocket/internal/common/jsonrpc"
+	"github.com/unstablemind/pocket/internal/common/schema"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+const protocolVersion = "2024-11-05"
+
+// Content is a single block of tool output
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// CallResult is the result of a tools/call request
+type CallResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError"`
+}
+
+// Server exposes a cobra command tree as MCP tools
+type Server struct {
+	runner *batch.Runner
+	tools  []Tool
+	byName map[string]Tool
+}
+
+// NewServer builds the tool list from a fresh root command
+func NewServer(newRoot func() *cobra.Command) *Server {
+	s := &Server{
+		runner: batch.NewRunner(newRoot, 1),
+		tools:  buildTools(newRoot()),
+		byName: make(map[string]Tool),
+	}
+	for _, t := range s.tools {
+		s.byName[t.Name] = t
+	}
+	return s
+}
+
+func NewCmd(newRoot func() *cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Model Context Protocol server",
+		Long:  `Expose every pocket command as a typed MCP tool so agents can discover and call integrations directly.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "serve",
+		Short: "Serve all commands as MCP tools over stdio",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// stdout carries the protocol, so anything else a command
+			// writes there directly is diverted to stderr
+			proto := os.Stdout
+			os.Stdout = os.Stderr
+			defer func() { os.Stdout = proto }()
+
+			return NewServer(newRoot).Serve(os.Stdin, proto)
+		},
+	})
+
+	return cmd
+}
+
+// Serve reads newline-delimited JSON-RPC requests from r and writes responses to w
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(w)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var req jsonrpc.Request
+		if err := json.Unmarshal(line, &req); err != nil {
+			if err := enc.Encode(jsonrpc.ErrorResponse(json.RawMessage("null"), jsonrpc.CodeParseError, err.Error())); err != nil {
+				return err
+			}
+			continue
+		}
+
+		resp := s.handle(&req)
+		if resp == nil {
+			continue
+		}
+		if err := enc.Encode(resp); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+func (s *Server) handle(req *jsonrpc.Request) *jsonrpc.Response {
+	// Notifications carry no id and never get a response
+	if len(req.ID) == 0 {
+		return nil
+	}
+	if req.JSONRPC != jsonrpc.Version {
+		return jsonrpc.ErrorResponse(req.ID, jsonrpc.CodeInvalidRequest, "jsonrpc must be 2.0")
+	}
+
+	switch req.Method {
+	case "initialize":
+		var params struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		_ = json.Unmarshal(req.Params, &params)
+		version := params.ProtocolVersion
+		if version == "" {
+			version = protocolVersion
+		}
+		return jsonrpc.Result(req.ID, map[string]any{
+			"protocolVersion": version,
+			"capabilities": map[string]any{
+				"tools": map[string]any{"listChanged": false},
+			},
+			"serverInfo": map[string]any{"name": "pocket", "version": "1.0.0"},
+		})
+
+	case "ping":
+		return jsonrpc.Result(req.ID, map[string]any{})
+
+	case "tools/list":
+		return jsonrpc.Result(req.ID, map[string]any{"tools": s.tools})
+
+	case "tools/call":
+		var params struct {
+			Name      string         `json:"name"`
+			Arguments map[string]any `json:"arguments"`
+		}
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return jsonrpc.ErrorResponse(req.ID, jsonrpc.CodeInvalidParams, err.Error())
+		}
+		tool, ok := s.byName[params.Name]
+		if !ok {
+			return jsonrpc.ErrorResponse(req.ID, jsonrpc.CodeInvalidParams, "unknown tool: "+params.Name)
+		}
+		argv, err := toArgv(tool, params.Arguments)
+		if err != nil {
+			return jsonrpc.ErrorResponse(req.ID, jsonrpc.CodeInvalidParams, err.Error())
+		}
+		return jsonrpc.Result(req.ID, s.run(argv))
+
+	default:
+		return jsonrpc.ErrorResponse(req.ID, jsonrpc.CodeMethodNotFound, "method not found: "+req.Method)
+	}
+}
+
+// run executes argv in this process like a batch request, and wraps the
+// response envelope it printed as tool output
+func (s *Server) run(argv []string) CallResult {
+	text := string(s.runner.Exec(argv))
+	var resp output.Response
+	isError := json.Unmarshal([]byte(text), &resp) != nil || !resp.Success
+
+	return CallResult{
+		Content: []Content{{Type: "text", Text: text}},
+		IsError: isError,
+	}
+}
+
+// toArgv converts tool arguments into a command line for the tool's command
+func toArgv(tool Tool, arguments map[string]any) ([]string, error) {
+	argv := append([]string{}, tool.path...)
+
+	keys := make([]string, 0, len(arguments))
+	for k := range arguments {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var positional []string
+	for _, k := range keys {
+		v := arguments[k]
+		if k == schema.ArgsProperty {
+			switch a := v.(type) {
+			case []any:
+				for _, item := range a {
+					positional = append(positional, formatValue(item))
+				}
+			case nil:
+			default:
+				positional = append(positional, formatValue(a))
+			}
+			continue
+		}
+
+		if tool.cmd.Flags().Lookup(k) == nil {
+			return nil, fmt.Errorf("unknown argument: %s", k)
+		}
+
+		switch a := v.(type) {
+		case []any:
+			for _, item := range a {
+				argv = append(argv, "--"+k+"="+formatValue(item))
+			}
+		case nil:
+		default:
+			argv = append(argv, "--"+k+"="+formatValue(a))
+		}
+	}
+
+	if len(positional) > 0 {
+		argv = append(argv, "--")
+		argv = append(argv, positional...)
+	}
+
+	return argv, nil
+}
+
+func formatValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"bufio"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/jsonrpc"
+	"github.com/unstablemind/pocket/internal/common/schema"
+	"github.com/unstablemind/pocket/internal/common/testutil"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+func TestMain(m *testing.M) {
+	testutil.Main(m, nil)
+}
+
+func newTestRoot() *cobra.Command {
+	var format string
+	root := &cobra.Command{
+		Use:           "pocket",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			output.SetFormat(cmd.Context(), format)
+			return nil
+		},
+	}
+	root.PersistentFlags().StringVarP(&format, "output", "o", "json", "Output format")
+
+	group := &cobra.Command{Use: "dev", Short: "Dev commands"}
+	var limit int
+	var draft bool
+	list := &cobra.Command{
+		Use:   "echo [text]",
+		Short: "Echo text",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return output.Print(ctx, map[string]any{"args": args, "limit": limit, "draft": draft})
+		},
+	}
+	list.Flags().IntVarP(&limit, "limit", "l", 10, "Number of items")
+	list.Flags().BoolVar(&draft, "draft", false, "Draft mode")
+	group.AddCommand(list)
+
+	group.AddCommand(&cobra.Command{
+		Use:   "fail",
+		Short: "Always fails",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			return output.PrintError(ctx, "boom", "it broke", nil)
+		},
+	})
+
+	root.AddCommand(group)
+	root.AddCommand(&cobra.Command{Use: "mcp", Run: func(cmd *cobra.Command, args []string) {}})
+	return root
+}
+
+func TestBuildTools(t *testing.T) {
+	tools := buildTools(newTestRoot())
+
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	if strings.Join(names, ",") != "dev_echo,dev_fail" {
+		t.Fatalf("unexpected tools: %v", names)
+	}
+
+	echo := tools[0]
+	if _, ok := echo.InputSchema.Properties[schema.ArgsProperty]; !ok {
+		t.Error("expected args property for command with positional args")
+	}
+	limit := echo.InputSchema.Properties["limit"]
+	if limit == nil || limit.Type != "integer" || limit.Default != 10 {
+		t.Errorf("unexpected limit schema: %+v", limit)
+	}
+	if draft := echo.InputSchema.Properties["draft"]; draft == nil || draft.Type != "boolean" {
+		t.Errorf("unexpected draft schema: %+v", draft)
+	}
+	if _, ok := tools[1].InputSchema.Properties[schema.ArgsProperty]; ok {
+		t.Error("expected no args property for command without positional args")
+	}
+}
+
+func TestToArgv(t *testing.T) {
+	tools := buildTools(newTestRoot())
+
+	argv, err := toArgv(tools[0], map[string]any{
+		"args":  []any{"-hello"},
+		"limit": float64(3),
+		"draft": true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := "dev echo --draft=true --limit=3 -- -hello"
+	if strings.Join(argv, " ") != expected {
+		t.Errorf("expected %q, got %q", expected, strings.Join(argv, " "))
+	}
+
+	if _, err := toArgv(tools[0], map[string]any{"bogus": "x"}); err == nil {
+		t.Error("expected error for unknown argument")
+	}
+}
+
+func TestServe(t *testing.T) {
+	in := strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2024-11-05"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"dev_echo","arguments":{"args":["hi"],"limit":5}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"dev_fail","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"nope"}`,
+	}, "\n")
+
+	var out strings.Builder
+	if err := NewServer(newTestRoot).Serve(strings.NewReader(in), &out); err != nil {
+		t.Fatalf("serve failed: %v", err)
+	}
+
+	var responses []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var resp map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid response line %q: %v", scanner.Text(), err)
+		}
+		responses = append(responses, resp)
+	}
+
+	if len(responses) != 5 {
+		t.Fatalf("expected 5 responses (notification gets none), got %d", len(responses))
+	}
+
+	list := responses[1]["result"].(map[string]any)["tools"].([]any)
+	if len(list) != 2 {
+		t.Errorf("expected 2 tools, got %d", len(list))
+	}
+
+	call := responses[2]["result"].(map[string]any)
+	if call["isError"] != false {
+		t.Errorf("expected successful call, got %v", call)
+	}
+	text := call["content"].([]any)[0].(map[string]any)["text"].(string)
+	var resp output.Response
+	if err := json.Unmarshal([]byte(text), &resp); err != nil {
+		t.Fatalf("tool text is not a response envelope: %v", err)
+	}
+	data := resp.Data.(map[string]any)
+	if data["limit"] != float64(5) || data["args"].([]any)[0] != "hi" {
+		t.Errorf("unexpected data: %v", data)
+	}
+
+	failed := responses[3]["result"].(map[string]any)
+	if failed["isError"] != true {
+		t.Errorf("expected isError for failing command, got %v", failed)
+	}
+
+	if responses[4]["error"].(map[string]any)["code"] != float64(jsonrpc.CodeMethodNotFound) {
+		t.Errorf("expected method not found, got %v", responses[4])
+	}
+}
+
+func TestRunForcesJSON(t *testing.T) {
+	s := NewServer(newTestRoot)
+	if res := s.run([]string{"dev", "echo", "-o", "table", "--", "hi"}); res.IsError || !strings.HasPrefix(res.Content[0].Text, `{"success":true`) {
+		t.Errorf("run with -o table = %+v", res)
+	}
+	if res := s.run([]string{"mcp"}); !res.IsError || !strings.Contains(res.Content[0].Text, "invalid_request") {
+		t.Errorf("run mcp = %+v", res)
+	}
+}
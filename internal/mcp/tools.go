@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/common/schema"
+)
+
+// Tool is an MCP tool definition derived from a cobra command
+type Tool struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema *schema.Schema `json:"inputSchema"`
+
+	path []string
+	cmd  *cobra.Command
+}
+
+// skipCommands are never exposed as tools
+var skipCommands = map[string]bool{
+	"help":       true,
+	"completion": true,
+	"mcp":        true,
+	"batch":      true,
+	"serve":      true,
+	"run":        true,
+}
+
+// buildTools walks the cobra tree and returns one tool per runnable command
+func buildTools(root *cobra.Command) []Tool {
+	var tools []Tool
+	var walk func(cmd *cobra.Command, path []string)
+	walk = func(cmd *cobra.Command, path []string) {
+		for _, sub := range cmd.Commands() {
+			if sub.Hidden || skipCommands[sub.Name()] {
+				continue
+			}
+			subPath := append(append([]string{}, path...), sub.Name())
+			if sub.Runnable() {
+				tools = append(tools, newTool(sub, subPath))
+			}
+			walk(sub, subPath)
+		}
+	}
+	walk(root, nil)
+
+	sort.Slice(tools, func(i, j int) bool { return tools[i].Name < tools[j].Name })
+	return tools
+}
+
+func newTool(cmd *cobra.Command, path []string) Tool {
+	desc := cmd.Short
+	usage := append([]string{"pocket"}, path[:len(path)-1]...)
+	desc += " (" + strings.Join(append(usage, cmd.Use), " ") + ")"
+
+	return Tool{
+		Name:        strings.Join(path, "_"),
+		Description: desc,
+		InputSchema: schema.Input(cmd),
+		path:        path,
+		cmd:         cmd,
+	}
+}
@@ -1,6 +1,7 @@
 package feeds
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"html"
@@ -13,6 +14,8 @@ import (
 	"github.com/mmcdole/gofeed"
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -45,6 +48,16 @@ type SavedFeed struct {
 	URL  string `json:"url"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "rss",
+		Name:        "RSS/Atom Feeds",
+		Group:       "news",
+		Description: "Fetch and manage RSS/Atom feeds from any source",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "feeds",
@@ -70,7 +83,8 @@ func newFetchCmd() *cobra.Command {
 		Short: "Fetch and parse a feed",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fetchFeed(args[0], limit, summaryLen)
+			ctx := cmd.Context()
+			return fetchFeed(ctx, args[0], limit, summaryLen)
 		},
 	}
 
@@ -85,14 +99,15 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List saved feeds",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			feeds, err := loadSavedFeeds()
 			if err != nil {
-				return output.PrintError("load_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "load_failed", err, nil)
 			}
 			if len(feeds) == 0 {
-				return output.Print([]SavedFeed{})
+				return output.Print(ctx, []SavedFeed{})
 			}
-			return output.Print(feeds)
+			return output.Print(ctx, feeds)
 		},
 	}
 
@@ -107,13 +122,14 @@ func newAddCmd() *cobra.Command {
 		Short: "Save a feed",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			url := args[0]
 
 			// If no name provided, fetch the feed to get its title
 			if name == "" {
 				feed, err := parser.ParseURL(url)
 				if err != nil {
-					return output.PrintError("fetch_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "fetch_failed", err, nil)
 				}
 				name = feed.Title
 			}
@@ -123,17 +139,17 @@ func newAddCmd() *cobra.Command {
 			// Check for duplicates
 			for _, f := range feeds {
 				if f.URL == url {
-					return output.PrintError("duplicate", "Feed already saved", nil)
+					return output.PrintError(ctx, "duplicate", "Feed already saved", nil)
 				}
 			}
 
 			feeds = append(feeds, SavedFeed{Name: name, URL: url})
 
-			if err := saveSavedFeeds(feeds); err != nil {
-				return output.PrintError("save_failed", err.Error(), nil)
+			if err := saveSavedFeeds(ctx, feeds); err != nil {
+				return output.PrintErr(ctx, "save_failed", err, nil)
 			}
 
-			return output.Print(map[string]string{
+			return output.Print(ctx, map[string]string{
 				"status": "added",
 				"name":   name,
 				"url":    url,
@@ -153,10 +169,11 @@ func newRemoveCmd() *cobra.Command {
 		Short:   "Remove a saved feed",
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := args[0]
 			feeds, err := loadSavedFeeds()
 			if err != nil {
-				return output.PrintError("load_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "load_failed", err, nil)
 			}
 
 			var newFeeds []SavedFeed
@@ -171,14 +188,14 @@ func newRemoveCmd() *cobra.Command {
 			}
 
 			if removed == nil {
-				return output.PrintError("not_found", "Feed not found", nil)
+				return output.PrintError(ctx, "not_found", "Feed not found", nil)
 			}
 
-			if err := saveSavedFeeds(newFeeds); err != nil {
-				return output.PrintError("save_failed", err.Error(), nil)
+			if err := saveSavedFeeds(ctx, newFeeds); err != nil {
+				return output.PrintErr(ctx, "save_failed", err, nil)
 			}
 
-			return output.Print(map[string]string{
+			return output.Print(ctx, map[string]string{
 				"status": "removed",
 				"name":   removed.Name,
 				"url":    removed.URL,
@@ -198,19 +215,20 @@ func newReadCmd() *cobra.Command {
 		Short: "Fetch a saved feed by name",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			name := args[0]
 			feeds, err := loadSavedFeeds()
 			if err != nil {
-				return output.PrintError("load_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "load_failed", err, nil)
 			}
 
 			for _, f := range feeds {
 				if f.Name == name || f.URL == name {
-					return fetchFeed(f.URL, limit, summaryLen)
+					return fetchFeed(ctx, f.URL, limit, summaryLen)
 				}
 			}
 
-			return output.PrintError("not_found", "Feed not found: "+name, nil)
+			return output.PrintError(ctx, "not_found", "Feed not found: "+name, nil)
 		},
 	}
 
@@ -220,10 +238,10 @@ func newReadCmd() *cobra.Command {
 	return cmd
 }
 
-func fetchFeed(url string, limit, summaryLen int) error {
+func fetchFeed(ctx context.Context, url string, limit, summaryLen int) error {
 	feed, err := parser.ParseURL(url)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	if limit > len(feed.Items) {
@@ -247,7 +265,7 @@ func fetchFeed(url string, limit, summaryLen int) error {
 		info.Updated = timeAgo(*feed.UpdatedParsed)
 	}
 
-	return output.Print(info)
+	return output.Print(ctx, info)
 }
 
 func toFeedItem(item *gofeed.Item, summaryLen int) FeedItem {
@@ -337,16 +355,19 @@ func loadSavedFeeds() ([]SavedFeed, error) {
 	return feeds, nil
 }
 
-func saveSavedFeeds(feeds []SavedFeed) error {
+func saveSavedFeeds(ctx context.Context, feeds []SavedFeed) error {
 	path := feedsFilePath()
 
-	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o700); err != nil {
+	data, err := json.MarshalIndent(feeds, "", "  ")
+	if err != nil {
 		return err
 	}
+	if dryrun.Enabled(ctx) {
+		return dryrun.Record(ctx, dryrun.Request{Protocol: "file", Target: path, Command: "write", Body: string(data)})
+	}
 
-	data, err := json.MarshalIndent(feeds, "", "  ")
-	if err != nil {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
 		return err
 	}
 
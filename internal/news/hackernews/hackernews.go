@@ -13,6 +13,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -22,7 +24,7 @@ var baseURL = "https://hacker-news.firebaseio.com/v0"
 
 const maxWorkers = 10
 
-var client = &http.Client{Timeout: 10 * time.Second}
+var client = transport.New(10 * time.Second)
 
 // Item represents a HN item (story, comment, etc)
 type Item struct {
@@ -58,6 +60,16 @@ type Comment struct {
 	Age    string `json:"age"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "hackernews",
+		Name:        "Hacker News",
+		Group:       "news",
+		Description: "Tech news, stories, and discussions from Hacker News",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "hackernews",
@@ -82,7 +94,8 @@ func newTopCmd() *cobra.Command {
 		Use:   "top",
 		Short: "Top stories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fetchStories("topstories", limit)
+			ctx := cmd.Context()
+			return fetchStories(ctx, "topstories", limit)
 		},
 	}
 
@@ -97,7 +110,8 @@ func newNewCmd() *cobra.Command {
 		Use:   "new",
 		Short: "New stories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fetchStories("newstories", limit)
+			ctx := cmd.Context()
+			return fetchStories(ctx, "newstories", limit)
 		},
 	}
 
@@ -112,7 +126,8 @@ func newBestCmd() *cobra.Command {
 		Use:   "best",
 		Short: "Best stories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fetchStories("beststories", limit)
+			ctx := cmd.Context()
+			return fetchStories(ctx, "beststories", limit)
 		},
 	}
 
@@ -127,7 +142,8 @@ func newAskCmd() *cobra.Command {
 		Use:   "ask",
 		Short: "Ask HN stories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fetchStories("askstories", limit)
+			ctx := cmd.Context()
+			return fetchStories(ctx, "askstories", limit)
 		},
 	}
 
@@ -142,7 +158,8 @@ func newShowCmd() *cobra.Command {
 		Use:   "show",
 		Short: "Show HN stories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fetchStories("showstories", limit)
+			ctx := cmd.Context()
+			return fetchStories(ctx, "showstories", limit)
 		},
 	}
 
@@ -158,11 +175,12 @@ func newItemCmd() *cobra.Command {
 		Short: "Get item with comments",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			var id int
 			if _, err := fmt.Sscanf(args[0], "%d", &id); err != nil {
-				return output.PrintError("invalid_id", "ID must be a number", nil)
+				return output.PrintError(ctx, "invalid_id", "ID must be a number", nil)
 			}
-			return fetchItem(id, commentsLimit)
+			return fetchItem(ctx, id, commentsLimit)
 		},
 	}
 
@@ -170,10 +188,10 @@ func newItemCmd() *cobra.Command {
 	return cmd
 }
 
-func fetchStories(endpoint string, limit int) error {
-	ids, err := getStoryIDs(endpoint)
+func fetchStories(ctx context.Context, endpoint string, limit int) error {
+	ids, err := getStoryIDs(ctx, endpoint)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	if limit > len(ids) {
@@ -181,7 +199,7 @@ func fetchStories(endpoint string, limit int) error {
 	}
 	ids = ids[:limit]
 
-	stories := fetchItemsConcurrent(ids)
+	stories := fetchItemsConcurrent(ctx, ids)
 
 	result := make([]Story, 0, len(stories))
 	for _, item := range stories {
@@ -190,13 +208,13 @@ func fetchStories(endpoint string, limit int) error {
 		}
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
 
-func fetchItem(id, commentsLimit int) error {
-	item, err := getItem(id)
+func fetchItem(ctx context.Context, id, commentsLimit int) error {
+	item, err := getItem(ctx, id)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	if item.Type == "story" || item.Type == "job" {
@@ -208,7 +226,7 @@ func fetchItem(id, commentsLimit int) error {
 			if commentsLimit > len(item.Kids) {
 				commentsLimit = len(item.Kids)
 			}
-			commentItems := fetchItemsConcurrent(item.Kids[:commentsLimit])
+			commentItems := fetchItemsConcurrent(ctx, item.Kids[:commentsLimit])
 			for _, c := range commentItems {
 				if c != nil && c.Type == "comment" {
 					comments = append(comments, toComment(c))
@@ -216,19 +234,19 @@ func fetchItem(id, commentsLimit int) error {
 			}
 		}
 
-		return output.Print(map[string]any{
+		return output.Print(ctx, map[string]any{
 			"story":    story,
 			"comments": comments,
 		})
 	}
 
 	// It's a comment
-	return output.Print(toComment(item))
+	return output.Print(ctx, toComment(item))
 }
 
-func getStoryIDs(endpoint string) ([]int, error) {
+func getStoryIDs(ctx context.Context, endpoint string) ([]int, error) {
 	reqURL := fmt.Sprintf("%s/%s.json", baseURL, endpoint)
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, http.NoBody)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 	if err != nil {
 		return nil, err
 	}
@@ -245,9 +263,9 @@ func getStoryIDs(endpoint string) ([]int, error) {
 	return ids, nil
 }
 
-func getItem(id int) (*Item, error) {
+func getItem(ctx context.Context, id int) (*Item, error) {
 	reqURL := fmt.Sprintf("%s/item/%d.json", baseURL, id)
-	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, reqURL, http.NoBody)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, http.NoBody)
 	if err != nil {
 		return nil, err
 	}
@@ -264,7 +282,7 @@ func getItem(id int) (*Item, error) {
 	return &item, nil
 }
 
-func fetchItemsConcurrent(ids []int) []*Item {
+func fetchItemsConcurrent(ctx context.Context, ids []int) []*Item {
 	items := make([]*Item, len(ids))
 	var wg sync.WaitGroup
 	sem := make(chan struct{}, maxWorkers)
@@ -276,7 +294,7 @@ func fetchItemsConcurrent(ids []int) []*Item {
 			sem <- struct{}{}
 			defer func() { <-sem }()
 
-			item, err := getItem(itemID)
+			item, err := getItem(ctx, itemID)
 			if err == nil {
 				items[idx] = item
 			}
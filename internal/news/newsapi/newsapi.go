@@ -12,11 +12,29 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var apiBaseURL = "https://newsapi.org/v2"
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "newsapi",
+		Name:        "NewsAPI",
+		Group:       "news",
+		Description: "Search news articles and get headlines from 80,000+ sources",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "newsapi_key", Description: "API key", Required: true},
+		},
+		SetupGuide:  "1. Go to https://newsapi.org/register\n2. Register for free account\n3. Copy your API key\n4. Run: pocket config set newsapi_key <key>",
+		TestCommand: "pocket news newsapi headlines -l 1",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "newsapi",
@@ -37,20 +55,20 @@ type newsClient struct {
 	httpClient *http.Client
 }
 
-func newNewsClient() (*newsClient, error) {
-	apiKey, err := config.MustGet("newsapi_key")
+func newNewsClient(ctx context.Context) (*newsClient, error) {
+	apiKey, err := config.MustGet(ctx, "newsapi_key")
 	if err != nil {
 		return nil, err
 	}
 
 	return &newsClient{
 		apiKey:     apiKey,
-		httpClient: &http.Client{},
+		httpClient: transport.New(0),
 	}, nil
 }
 
-func (c *newsClient) doRequest(endpoint string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (c *newsClient) doRequest(ctx context.Context, endpoint string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", apiBaseURL+endpoint, http.NoBody)
@@ -127,7 +145,8 @@ func newHeadlinesCmd() *cobra.Command {
 		Use:   "headlines",
 		Short: "Get top headlines",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newNewsClient()
+			ctx := cmd.Context()
+			client, err := newNewsClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -143,9 +162,9 @@ func newHeadlinesCmd() *cobra.Command {
 				endpoint += "&q=" + url.QueryEscape(query)
 			}
 
-			body, err := client.doRequest(endpoint)
+			body, err := client.doRequest(ctx, endpoint)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -155,10 +174,10 @@ func newHeadlinesCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"total":    result.TotalResults,
 				"count":    len(result.Articles),
 				"articles": formatArticles(result.Articles),
@@ -186,7 +205,8 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search news articles",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newNewsClient()
+			ctx := cmd.Context()
+			client, err := newNewsClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -204,9 +224,9 @@ func newSearchCmd() *cobra.Command {
 				endpoint += "&to=" + to
 			}
 
-			body, err := client.doRequest(endpoint)
+			body, err := client.doRequest(ctx, endpoint)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -216,10 +236,10 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"query":    args[0],
 				"total":    result.TotalResults,
 				"count":    len(result.Articles),
@@ -246,7 +266,8 @@ func newSourcesCmd() *cobra.Command {
 		Use:   "sources",
 		Short: "List news sources",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newNewsClient()
+			ctx := cmd.Context()
+			client, err := newNewsClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -271,9 +292,9 @@ func newSourcesCmd() *cobra.Command {
 				endpoint += p
 			}
 
-			body, err := client.doRequest(endpoint)
+			body, err := client.doRequest(ctx, endpoint)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -290,7 +311,7 @@ func newSourcesCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			sources := make([]map[string]any, len(result.Sources))
@@ -306,7 +327,7 @@ func newSourcesCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"count":   len(sources),
 				"sources": sources,
 			})
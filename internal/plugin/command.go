@@ -0,0 +1,276 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/unstablemind/pocket/internal/common/audit"
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/policy"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// maxEcho caps how much of a plugin's stray stdout is quoted in an error
+const maxEcho = 2000
+
+// Annotation marks the commands a plugin added, with the plugin's name
+const Annotation = "pocket-plugin"
+
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   DefaultGroup,
+		Short: "Commands from external plugins",
+		Long: `Plugins add commands to pocket. A plugin is either a pocket-<name> executable on PATH, which prints its manifest when run with --pocket-manifest, or a manifest file (YAML or JSON) in the plugins directory next to the config file.
+
+Plugins without a group of their own are mounted here; others under the group their manifest names.`,
+	}
+
+	cmd.AddCommand(newListCmd())
+
+	return cmd
+}
+
+// Info is a plugin as shown by pocket plugins list
+type Info struct {
+	Name     string   `json:"name"`
+	Group    string   `json:"group"`
+	Desc     string   `json:"desc,omitempty"`
+	Status   string   `json:"status"`
+	Commands []string `json:"commands,omitempty"`
+	Source   string   `json:"source"`
+	Path     string   `json:"path,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+func newListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List discovered plugins and any that failed to load",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			result := make([]Info, 0)
+			for _, p := range All(ctx) {
+				info := Info{Name: p.Name, Group: p.Group, Desc: p.Description, Source: p.Source, Path: p.Path, Error: p.Error}
+				if p.Error != "" {
+					info.Status = "error"
+				} else {
+					info.Status = p.Status(ctx)
+					info.Commands = p.CommandPaths()
+				}
+				result = append(result, info)
+			}
+			return output.Print(ctx, result)
+		},
+	}
+
+	return cmd
+}
+
+var checkOnce sync.Once
+
+// Mount adds every loaded plugin to root under its group. A plugin whose
+// name is taken by a built-in command is not mounted and gets an Error.
+func Mount(root *cobra.Command) {
+	ctx := root.Context()
+	// Every tree has the same built-in commands, so checking the first is enough
+	checkOnce.Do(func() {
+		for _, p := range All(ctx) {
+			if p.Error != "" {
+				continue
+			}
+			if group := findChild(root, p.Group); group == nil {
+				p.Error = "group " + p.Group + " is not available"
+			} else if findChild(group, p.Name) != nil {
+				p.Error = fmt.Sprintf("pocket %s %s is a built-in command", p.Group, p.Name)
+			}
+		}
+	})
+
+	for _, p := range All(ctx) {
+		if p.Error != "" {
+			continue
+		}
+		findChild(root, p.Group).AddCommand(p.command())
+	}
+}
+
+func findChild(parent *cobra.Command, name string) *cobra.Command {
+	for _, c := range parent.Commands() {
+		if c.Name() == name || c.HasAlias(name) {
+			return c
+		}
+	}
+	return nil
+}
+
+// CommandPaths returns the full command line of each of p's commands
+func (p *Plugin) CommandPaths() []string {
+	paths := make([]string, 0, len(p.Commands))
+	for _, c := range p.Commands {
+		path := "pocket " + p.Group + " " + p.Name + " " + c.Name
+		if c.Args != "" {
+			path += " " + c.Args
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// FlagSummary lists c's flags the way the command catalog does
+func (c Command) FlagSummary() string {
+	parts := make([]string, 0, len(c.Flags))
+	for _, f := range c.Flags {
+		if f.Short != "" {
+			parts = append(parts, "-"+f.Short+" "+f.Name)
+		} else {
+			parts = append(parts, "--"+f.Name)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (p *Plugin) command() *cobra.Command {
+	group := &cobra.Command{
+		Use:         p.Name,
+		Short:       p.Description,
+		Annotations: map[string]string{Annotation: p.Name},
+	}
+
+	for _, c := range p.Commands {
+		sub := &cobra.Command{
+			Use:         strings.TrimSpace(c.Name + " " + c.Args),
+			Short:       c.Desc,
+			Args:        cobra.ArbitraryArgs,
+			Annotations: map[string]string{Annotation: p.Name},
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return p.run(cmd, c, args)
+			},
+		}
+		for _, f := range c.Flags {
+			addFlag(sub.Flags(), f)
+		}
+		group.AddCommand(sub)
+	}
+
+	return group
+}
+
+func addFlag(flags *pflag.FlagSet, f Flag) {
+	switch f.Type {
+	case "int":
+		n, _ := strconv.Atoi(f.Default)
+		flags.IntP(f.Name, f.Short, n, f.Desc)
+	case "bool":
+		b, _ := strconv.ParseBool(f.Default)
+		flags.BoolP(f.Name, f.Short, b, f.Desc)
+	case "strings":
+		var def []string
+		if f.Default != "" {
+			def = strings.Split(f.Default, ",")
+		}
+		flags.StringSliceP(f.Name, f.Short, def, f.Desc)
+	default:
+		flags.StringP(f.Name, f.Short, f.Default, f.Desc)
+	}
+}
+
+// pluginArgs is what the plugin is run with: the command name, each flag the
+// user gave as --name=value, then -- and the positional arguments
+func pluginArgs(cmd *cobra.Command, c Command, args []string) []string {
+	out := []string{c.Name}
+	for _, f := range c.Flags {
+		flag := cmd.Flags().Lookup(f.Name)
+		if flag == nil || !flag.Changed {
+			continue
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			for _, v := range slice.GetSlice() {
+				out = append(out, "--"+f.Name+"="+v)
+			}
+			continue
+		}
+		out = append(out, "--"+f.Name+"="+flag.Value.String())
+	}
+	out = append(out, "--")
+	return append(out, args...)
+}
+
+// env gives the plugin its config keys, resolved for the active profile
+func (p *Plugin) env(ctx context.Context) []string {
+	env := append(os.Environ(), "POCKET_PLUGIN_PROTOCOL="+Protocol, "POCKET_PROFILE="+config.ProfileName(ctx))
+	for _, k := range p.Config {
+		if v, _ := config.Get(ctx, k.Key); v != "" {
+			env = append(env, config.EnvVar(k.Key)+"="+v)
+		}
+	}
+	return env
+}
+
+func (p *Plugin) run(cmd *cobra.Command, c Command, args []string) error {
+	ctx := cmd.Context()
+	argv := pluginArgs(cmd, c, args)
+	if dryrun.Enabled(ctx) && policy.ClassOf(cmd) != policy.ClassRead {
+		return dryrun.Exec(ctx, append([]string{p.Path}, argv...)...)
+	}
+
+	audit.Contact(ctx, "exec", p.Path)
+	var stdout bytes.Buffer
+	run := exec.CommandContext(cmd.Context(), p.Path, argv...)
+	run.Env = p.env(ctx)
+	run.Stdin = cmd.InOrStdin()
+	run.Stdout = &stdout
+	run.Stderr = os.Stderr
+
+	err := run.Run()
+	return relay(ctx, stdout.Bytes(), err)
+}
+
+// relay prints the response envelope the plugin wrote to stdout in the
+// user's chosen format, as if a built-in command had produced it
+func relay(ctx context.Context, stdout []byte, runErr error) error {
+	var resp struct {
+		Success    bool            `json:"success"`
+		Data       json.RawMessage `json:"data"`
+		NextCursor string          `json:"next_cursor"`
+		Error      *output.Error   `json:"error"`
+	}
+	if err := json.Unmarshal(bytes.TrimSpace(stdout), &resp); err != nil || (!resp.Success && resp.Error == nil) {
+		details := map[string]any{}
+		if runErr != nil {
+			details["exit"] = runErr.Error()
+		}
+		if len(stdout) > 0 {
+			if len(stdout) > maxEcho {
+				stdout = stdout[:maxEcho]
+			}
+			details["stdout"] = string(stdout)
+		}
+		return output.PrintError(ctx, "plugin_failed", "plugin did not print a response envelope", details)
+	}
+
+	if !resp.Success {
+		return output.PrintError(ctx, resp.Error.Code, resp.Error.Message, resp.Error.Details)
+	}
+	// Decoded rather than passed through, so table and text output see
+	// objects and lists
+	var data any
+	dec := json.NewDecoder(bytes.NewReader(resp.Data))
+	dec.UseNumber()
+	if len(resp.Data) > 0 {
+		if err := dec.Decode(&data); err != nil {
+			return output.PrintError(ctx, "plugin_failed", "invalid data in plugin response: "+err.Error(), nil)
+		}
+	}
+	return output.PrintPage(ctx, data, resp.NextCursor)
+}
@@ -0,0 +1,379 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/unstablemind/pocket/internal/common/cache"
+	"github.com/unstablemind/pocket/internal/common/config"
+)
+
+// Prefix names the executables on PATH that are plugins: pocket-<name>
+const Prefix = "pocket-"
+
+// ManifestFlag is passed to a PATH plugin to ask for its manifest
+const ManifestFlag = "--pocket-manifest"
+
+// Protocol is the plugin protocol version, given to plugins as
+// $POCKET_PLUGIN_PROTOCOL
+const Protocol = "1"
+
+// DefaultGroup holds plugins that don't name a group of their own
+const DefaultGroup = "plugins"
+
+// Groups are the command groups a plugin can be mounted under
+var Groups = []string{DefaultGroup, "social", "comms", "dev", "productivity", "news", "knowledge", "utility", "system", "security", "marketing"}
+
+// manifestTimeout bounds how long a PATH plugin may take to print its manifest
+const manifestTimeout = 5 * time.Second
+
+// Manifest describes a plugin: the commands it adds, the config keys it
+// reads and how to set it up
+type Manifest struct {
+	Name        string `yaml:"name" json:"name"`
+	Title       string `yaml:"title" json:"title,omitempty"`
+	Description string `yaml:"description" json:"description,omitempty"`
+	// Group is the command group the plugin is mounted under, e.g. dev
+	Group string `yaml:"group" json:"group"`
+	// Exec is the program to run: relative to the manifest's directory,
+	// absolute, or a name looked up on PATH. Defaults to pocket-<name>.
+	Exec        string    `yaml:"exec" json:"exec,omitempty"`
+	Commands    []Command `yaml:"commands" json:"commands"`
+	Config      []Key     `yaml:"config" json:"config,omitempty"`
+	SetupGuide  string    `yaml:"setup_guide" json:"setup_guide,omitempty"`
+	TestCommand string    `yaml:"test_cmd" json:"test_cmd,omitempty"`
+}
+
+// Command is one subcommand a plugin provides
+type Command struct {
+	Name  string `yaml:"name" json:"name"`
+	Desc  string `yaml:"desc" json:"desc"`
+	Args  string `yaml:"args" json:"args,omitempty"`
+	Flags []Flag `yaml:"flags" json:"flags,omitempty"`
+}
+
+// Flag is a command flag, passed on to the plugin as --name=value
+type Flag struct {
+	Name    string `yaml:"name" json:"name"`
+	Short   string `yaml:"short" json:"short,omitempty"`
+	Type    string `yaml:"type" json:"type,omitempty"`
+	Desc    string `yaml:"desc" json:"desc,omitempty"`
+	Default string `yaml:"default" json:"default,omitempty"`
+}
+
+// Key is a config key the plugin reads, given to it as $POCKET_<KEY>
+type Key struct {
+	Key      string `yaml:"key" json:"key"`
+	Desc     string `yaml:"desc" json:"desc,omitempty"`
+	Required bool   `yaml:"required" json:"required,omitempty"`
+	Secret   bool   `yaml:"secret" json:"secret,omitempty"`
+	Example  string `yaml:"example" json:"example,omitempty"`
+}
+
+// Plugin is a discovered plugin. One that failed to load keeps its name,
+// source and Error so it can be listed.
+type Plugin struct {
+	Manifest
+	// Source is the manifest file, or the executable for PATH plugins
+	Source string `json:"source"`
+	Path   string `json:"path,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+var (
+	validName = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+	flagTypes = []string{"", "string", "int", "bool", "strings"}
+)
+
+// Dir returns the manifest directory: plugins next to the config file
+func Dir() string {
+	return filepath.Join(filepath.Dir(config.Path()), "plugins")
+}
+
+var (
+	discoverOnce sync.Once
+	discovered   []*Plugin
+)
+
+// All returns the plugins from manifests in Dir and pocket-<name>
+// executables on PATH, in name order. They are discovered and their config
+// keys registered once per process.
+func All(ctx context.Context) []*Plugin {
+	discoverOnce.Do(func() { discovered = discover(ctx) })
+	return discovered
+}
+
+// Lookup returns the loaded plugin called name
+func Lookup(ctx context.Context, name string) (*Plugin, bool) {
+	for _, p := range All(ctx) {
+		if p.Name == name && p.Error == "" {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func discover(ctx context.Context) []*Plugin {
+	byName := map[string]*Plugin{}
+
+	// Manifests win over executables of the same name
+	entries, _ := os.ReadDir(Dir())
+	for _, e := range entries {
+		switch filepath.Ext(e.Name()) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		p := fromManifest(filepath.Join(Dir(), e.Name()))
+		if _, dup := byName[p.Name]; !dup {
+			byName[p.Name] = p
+		}
+	}
+
+	// Earlier PATH entries win, as they do for the shell
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			name, ok := executableName(e.Name())
+			if !ok {
+				continue
+			}
+			if _, dup := byName[name]; dup {
+				continue
+			}
+			path := filepath.Join(dir, e.Name())
+			if !isExecutable(path) {
+				continue
+			}
+			byName[name] = fromExecutable(ctx, name, path)
+		}
+	}
+
+	plugins := make([]*Plugin, 0, len(byName))
+	for _, p := range byName {
+		if p.Error == "" {
+			if err := registerKeys(p); err != nil {
+				p.Error = err.Error()
+			}
+		}
+		plugins = append(plugins, p)
+	}
+	slices.SortFunc(plugins, func(a, b *Plugin) int { return strings.Compare(a.Name, b.Name) })
+	return plugins
+}
+
+func executableName(file string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		file = strings.TrimSuffix(strings.ToLower(file), ".exe")
+	}
+	name, ok := strings.CutPrefix(file, Prefix)
+	return name, ok && validName.MatchString(name)
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.Mode().IsRegular() {
+		return false
+	}
+	return runtime.GOOS == "windows" || info.Mode()&0o111 != 0
+}
+
+func fromManifest(path string) *Plugin {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	p := &Plugin{Manifest: Manifest{Name: name}, Source: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+	// JSON is YAML, so one decoder reads both
+	if err := yaml.Unmarshal(data, &p.Manifest); err != nil {
+		p.Error = "invalid manifest: " + err.Error()
+		return p
+	}
+	if p.Name == "" {
+		p.Name = name
+	}
+
+	switch exe := p.Exec; {
+	case exe == "":
+		p.Path, err = exec.LookPath(Prefix + p.Name)
+	case filepath.IsAbs(exe):
+		p.Path = exe
+	case strings.ContainsRune(exe, filepath.Separator) || strings.ContainsRune(exe, '/'):
+		p.Path = filepath.Join(filepath.Dir(path), exe)
+	default:
+		p.Path, err = exec.LookPath(exe)
+	}
+	if err != nil {
+		p.Error = "exec: " + err.Error()
+		return p
+	}
+
+	if err := p.validate(); err != nil {
+		p.Error = err.Error()
+	}
+	return p
+}
+
+func fromExecutable(ctx context.Context, name, path string) *Plugin {
+	p := &Plugin{Manifest: Manifest{Name: name}, Source: path, Path: path}
+
+	m, err := executableManifest(ctx, path)
+	if err != nil {
+		p.Error = err.Error()
+		return p
+	}
+	if m.Name != "" && m.Name != name {
+		p.Error = fmt.Sprintf("manifest names %q, but the executable is %s%s", m.Name, Prefix, name)
+		return p
+	}
+	m.Name, m.Exec = name, ""
+	p.Manifest = *m
+
+	if err := p.validate(); err != nil {
+		p.Error = err.Error()
+	}
+	return p
+}
+
+// cachedManifest is a PATH plugin's manifest, valid while the executable
+// is unchanged
+type cachedManifest struct {
+	Path     string    `json:"path"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"mod_time"`
+	Manifest Manifest  `json:"manifest"`
+}
+
+// executableManifest runs path --pocket-manifest, or reuses its last answer
+// if the executable hasn't changed since
+func executableManifest(ctx context.Context, path string) (*Manifest, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	cacheFile := filepath.Join(cache.Dir(), "plugins", filepath.Base(path)+".json")
+	if data, err := os.ReadFile(cacheFile); err == nil {
+		var c cachedManifest
+		if json.Unmarshal(data, &c) == nil && c.Path == path && c.Size == info.Size() && c.ModTime.Equal(info.ModTime()) {
+			return &c.Manifest, nil
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, manifestTimeout)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, path, ManifestFlag)
+	cmd.Env = append(os.Environ(), "POCKET_PLUGIN_PROTOCOL="+Protocol)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("%s %s failed: %s", filepath.Base(path), ManifestFlag, msg)
+	}
+
+	var m Manifest
+	if err := yaml.Unmarshal(stdout.Bytes(), &m); err != nil {
+		return nil, fmt.Errorf("invalid manifest from %s %s: %v", filepath.Base(path), ManifestFlag, err)
+	}
+
+	data, _ := json.Marshal(cachedManifest{Path: path, Size: info.Size(), ModTime: info.ModTime(), Manifest: m})
+	if err := os.MkdirAll(filepath.Dir(cacheFile), 0o700); err == nil {
+		_ = os.WriteFile(cacheFile, data, 0o600)
+	}
+	return &m, nil
+}
+
+func (p *Plugin) validate() error {
+	if !validName.MatchString(p.Name) {
+		return fmt.Errorf("invalid plugin name %q (lowercase letters, digits and dashes)", p.Name)
+	}
+	if p.Group == "" {
+		p.Group = DefaultGroup
+	}
+	if !slices.Contains(Groups, p.Group) {
+		return fmt.Errorf("unknown group %q (%s)", p.Group, strings.Join(Groups, ", "))
+	}
+	if len(p.Commands) == 0 {
+		return fmt.Errorf("plugin %s declares no commands", p.Name)
+	}
+
+	seen := map[string]bool{}
+	for _, c := range p.Commands {
+		if !validName.MatchString(c.Name) {
+			return fmt.Errorf("invalid command name %q", c.Name)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("command %s is declared twice", c.Name)
+		}
+		seen[c.Name] = true
+		for _, f := range c.Flags {
+			if !validName.MatchString(f.Name) {
+				return fmt.Errorf("%s: invalid flag name %q", c.Name, f.Name)
+			}
+			if len(f.Short) > 1 {
+				return fmt.Errorf("%s: short flag %q must be one letter", c.Name, f.Short)
+			}
+			if !slices.Contains(flagTypes, f.Type) {
+				return fmt.Errorf("%s: flag %s has unknown type %q (string, int, bool, strings)", c.Name, f.Name, f.Type)
+			}
+		}
+	}
+	return nil
+}
+
+func registerKeys(p *Plugin) error {
+	for _, k := range p.Config {
+		if err := config.RegisterKey(k.Key, k.Secret); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status is ready when every required config key is set, needs_setup
+// otherwise, and no_auth for plugins without required keys
+func (p *Plugin) Status(ctx context.Context) string {
+	required := false
+	for _, k := range p.Config {
+		if !k.Required {
+			continue
+		}
+		required = true
+		if v, _ := config.Get(ctx, k.Key); v == "" {
+			return "needs_setup"
+		}
+	}
+	if !required {
+		return "no_auth"
+	}
+	return "ready"
+}
+
+// DisplayName is the plugin's title, or its name
+func (p *Plugin) DisplayName() string {
+	if p.Title != "" {
+		return p.Title
+	}
+	return p.Name
+}
@@ -0,0 +1,198 @@
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+var configDir, binDir string
+
+func TestMain(m *testing.M) {
+	dir, _ := os.MkdirTemp("", "plugin")
+	configDir = filepath.Join(dir, "config")
+	binDir = filepath.Join(dir, "bin")
+	os.Setenv("POCKET_CONFIG", filepath.Join(configDir, "config.json"))
+	os.Setenv("POCKET_CACHE_DIR", filepath.Join(dir, "cache"))
+	os.Setenv("PATH", binDir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func writeFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+const ticketsManifest = `name: tickets
+description: Tickets from the tracker
+exec: ./tickets.sh
+commands:
+  - name: list
+    desc: List tickets
+    args: "[project]"
+    flags:
+      - {name: limit, short: l, type: int, default: "10"}
+      - {name: label, type: strings}
+  - name: fail
+    desc: Always fails
+config:
+  - {key: tickets_token, required: true, secret: true}
+`
+
+// ticketsScript echoes how it was run, or fails with an error envelope
+const ticketsScript = `#!/bin/sh
+if [ "$1" = fail ]; then
+  echo '{"success":false,"error":{"code":"auth_required","message":"bad token"}}'
+  exit 1
+fi
+printf '{"success":true,"data":{"argv":"%s","token":"%s"},"next_cursor":"p2"}' "$*" "$POCKET_TICKETS_TOKEN"
+`
+
+const helloScript = `#!/bin/sh
+[ "$1" = --pocket-manifest ] && echo '{"group":"utility","commands":[{"name":"say","desc":"Say hi"}]}'
+`
+
+func setupPlugins(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins here are shell scripts")
+	}
+	plugins := filepath.Join(configDir, "plugins")
+	writeFile(t, filepath.Join(plugins, "tickets.yaml"), ticketsManifest, 0o644)
+	writeFile(t, filepath.Join(plugins, "tickets.sh"), ticketsScript, 0o755)
+	writeFile(t, filepath.Join(plugins, "empty.json"), `{"description": "no commands"}`, 0o644)
+	writeFile(t, filepath.Join(plugins, "README.md"), "not a manifest", 0o644)
+	writeFile(t, filepath.Join(binDir, "pocket-hello"), helloScript, 0o755)
+	// Shadowed by the manifest of the same name
+	writeFile(t, filepath.Join(binDir, "pocket-tickets"), "#!/bin/sh\nexit 1\n", 0o755)
+	writeFile(t, filepath.Join(binDir, "pocket-noexec"), helloScript, 0o644)
+}
+
+func TestDiscover(t *testing.T) {
+	ctx := context.Background()
+	setupPlugins(t)
+
+	byName := map[string]*Plugin{}
+	for _, p := range discover(ctx) {
+		byName[p.Name] = p
+	}
+	if len(byName) != 3 {
+		t.Fatalf("found %d plugins: %v", len(byName), byName)
+	}
+
+	if p := byName["tickets"]; p.Error != "" || p.Group != DefaultGroup || p.Path != filepath.Join(configDir, "plugins", "tickets.sh") {
+		t.Errorf("tickets = %+v", p)
+	}
+	if p := byName["hello"]; p.Error != "" || p.Group != "utility" || p.Commands[0].Name != "say" {
+		t.Errorf("hello = %+v", p)
+	}
+	if p := byName["empty"]; p.Error == "" {
+		t.Error("a plugin without commands should fail to load")
+	}
+
+	// The manifest a PATH plugin printed is reused while it is unchanged
+	hello := filepath.Join(binDir, "pocket-hello")
+	info, _ := os.Stat(hello)
+	writeFile(t, hello, "#!/bin/sh\nexit 1\n", 0o755)
+	if p := fromExecutable(ctx, "hello", hello); p.Error == "" {
+		t.Error("a changed executable should be asked for its manifest again")
+	}
+	writeFile(t, hello, helloScript, 0o755)
+	_ = os.Chtimes(hello, info.ModTime(), info.ModTime())
+	if p := fromExecutable(ctx, "hello", hello); p.Error != "" {
+		t.Errorf("hello after restore: %s", p.Error)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Manifest
+		ok   bool
+	}{
+		{"minimal", Manifest{Name: "a", Commands: []Command{{Name: "x"}}}, true},
+		{"bad name", Manifest{Name: "A b", Commands: []Command{{Name: "x"}}}, false},
+		{"unknown group", Manifest{Name: "a", Group: "config", Commands: []Command{{Name: "x"}}}, false},
+		{"duplicate command", Manifest{Name: "a", Commands: []Command{{Name: "x"}, {Name: "x"}}}, false},
+		{"flag type", Manifest{Name: "a", Commands: []Command{{Name: "x", Flags: []Flag{{Name: "n", Type: "float"}}}}}, false},
+		{"long short flag", Manifest{Name: "a", Commands: []Command{{Name: "x", Flags: []Flag{{Name: "n", Short: "nn"}}}}}, false},
+	}
+	for _, tt := range tests {
+		p := &Plugin{Manifest: tt.m}
+		if err := p.validate(); (err == nil) != tt.ok {
+			t.Errorf("%s: validate() = %v", tt.name, err)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	setupPlugins(t)
+	p := fromManifest(filepath.Join(configDir, "plugins", "tickets.yaml"))
+	if p.Error != "" {
+		t.Fatal(p.Error)
+	}
+	if err := registerKeys(p); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("POCKET_TICKETS_TOKEN", "tok")
+
+	run := func(args ...string) output.Response {
+		t.Helper()
+		var buf bytes.Buffer
+		output.SetOutput(ctx, &buf)
+		t.Cleanup(func() { output.SetOutput(ctx, os.Stdout) })
+
+		root := &cobra.Command{Use: "pocket", SilenceErrors: true, SilenceUsage: true}
+		root.AddCommand(p.command())
+		root.SetArgs(append([]string{"tickets"}, args...))
+		_, _ = root.ExecuteC()
+
+		var resp output.Response
+		if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+			t.Fatalf("%v: %s", err, buf.String())
+		}
+		return resp
+	}
+
+	resp := run("list", "-l", "3", "--label", "a,b", "--", "-proj")
+	data, _ := resp.Data.(map[string]any)
+	if !resp.Success || data["argv"] != "list --limit=3 --label=a --label=b -- -proj" || data["token"] != "tok" || resp.NextCursor != "p2" {
+		t.Errorf("list = %+v", resp)
+	}
+
+	if resp := run("fail"); resp.Success || resp.Error == nil || resp.Error.Code != "auth_required" {
+		t.Errorf("fail = %+v", resp)
+	}
+}
+
+func TestRelayWithoutEnvelope(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	output.SetOutput(ctx, &buf)
+	defer output.SetOutput(ctx, os.Stdout)
+
+	_ = relay(ctx, []byte("plain text\n"), nil)
+	var resp output.Response
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Success || resp.Error == nil || resp.Error.Code != "plugin_failed" {
+		t.Errorf("resp = %+v", resp)
+	}
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -21,6 +24,24 @@ const (
 	calendarAPI = "https://www.googleapis.com/calendar/v3"
 )
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "calendar",
+		Name:        "Google Calendar",
+		Group:       "productivity",
+		Description: "View and create calendar events",
+		Auth:        registry.AuthOAuth,
+		Keys: []registry.Key{
+			{Key: "google_client_id", Description: "OAuth 2.0 Client ID", Required: true, Example: "123456789.apps.googleusercontent.com"},
+			{Key: "google_client_secret", Description: "OAuth 2.0 Client Secret", Required: true},
+			{Key: "google_refresh_token", Description: "OAuth 2.0 Refresh Token", Required: true},
+		},
+		SetupGuide:  "1. Go to https://console.cloud.google.com/\n2. Create a new project (or select existing)\n3. Enable \"Google Calendar API\" at:\n   https://console.cloud.google.com/apis/library/calendar-json.googleapis.com\n4. Go to Credentials > Create Credentials > OAuth client ID\n5. Select \"Desktop app\" as application type\n6. Copy Client ID and Client Secret\n7. Get a refresh token using OAuth Playground:\n   a. Go to https://developers.google.com/oauthplayground/\n   b. Click gear icon, check \"Use your own OAuth credentials\"\n   c. Enter your Client ID and Client Secret\n   d. In Step 1, select \"Google Calendar API v3\" scopes\n   e. Authorize and exchange for tokens\n   f. Copy the Refresh Token from Step 2\n8. Run:\n   pocket config set google_client_id <your-client-id>\n   pocket config set google_client_secret <your-client-secret>\n   pocket config set google_refresh_token <your-refresh-token>",
+		TestCommand: "pocket productivity calendar today",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "calendar",
@@ -43,36 +64,36 @@ type calendarClient struct {
 	httpClient  *http.Client
 }
 
-func newCalendarClient() (*calendarClient, error) {
-	clientID, err := config.MustGet("google_client_id")
+func newCalendarClient(ctx context.Context) (*calendarClient, error) {
+	clientID, err := config.MustGet(ctx, "google_client_id")
 	if err != nil {
 		return nil, fmt.Errorf("google_client_id not configured. Run 'pocket setup show calendar' for setup instructions")
 	}
 
-	clientSecret, err := config.MustGet("google_client_secret")
+	clientSecret, err := config.MustGet(ctx, "google_client_secret")
 	if err != nil {
 		return nil, fmt.Errorf("google_client_secret not configured. Run 'pocket setup show calendar' for setup instructions")
 	}
 
-	refreshToken, err := config.MustGet("google_refresh_token")
+	refreshToken, err := config.MustGet(ctx, "google_refresh_token")
 	if err != nil {
 		return nil, fmt.Errorf("google_refresh_token not configured. Run 'pocket setup show calendar' for setup instructions")
 	}
 
 	// Exchange refresh token for access token
-	accessToken, err := getAccessToken(clientID, clientSecret, refreshToken)
+	accessToken, err := getAccessToken(ctx, clientID, clientSecret, refreshToken)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get access token: %w", err)
 	}
 
 	return &calendarClient{
 		accessToken: accessToken,
-		httpClient:  &http.Client{},
+		httpClient:  transport.New(0),
 	}, nil
 }
 
-func getAccessToken(clientID, clientSecret, refreshToken string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func getAccessToken(ctx context.Context, clientID, clientSecret, refreshToken string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	data := url.Values{}
@@ -81,13 +102,13 @@ func getAccessToken(clientID, clientSecret, refreshToken string) (string, error)
 	data.Set("refresh_token", refreshToken)
 	data.Set("grant_type", "refresh_token")
 
-	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, bytes.NewBufferString(data.Encode()))
+	req, err := http.NewRequestWithContext(dryrun.ReadOnly(ctx), "POST", tokenURL, bytes.NewBufferString(data.Encode()))
 	if err != nil {
 		return "", err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := transport.New(0).Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -121,8 +142,8 @@ func getAccessToken(clientID, clientSecret, refreshToken string) (string, error)
 	return tokenResp.AccessToken, nil
 }
 
-func (c *calendarClient) doRequest(method, endpoint string, body interface{}) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (c *calendarClient) doRequest(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	var reqBody io.Reader
@@ -248,7 +269,8 @@ func newEventsCmd() *cobra.Command {
 		Use:   "events",
 		Short: "List upcoming events",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newCalendarClient()
+			ctx := cmd.Context()
+			client, err := newCalendarClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -260,9 +282,9 @@ func newEventsCmd() *cobra.Command {
 			endpoint := fmt.Sprintf("/calendars/%s/events?timeMin=%s&timeMax=%s&maxResults=%d&singleEvents=true&orderBy=startTime",
 				url.PathEscape(calendarID), url.QueryEscape(timeMin), url.QueryEscape(timeMax), limit)
 
-			body, err := client.doRequest("GET", endpoint, nil)
+			body, err := client.doRequest(ctx, "GET", endpoint, nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -270,10 +292,10 @@ func newEventsCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"calendar": calendarID,
 				"days":     days,
 				"count":    len(result.Items),
@@ -296,7 +318,8 @@ func newTodayCmd() *cobra.Command {
 		Use:   "today",
 		Short: "List today's events",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newCalendarClient()
+			ctx := cmd.Context()
+			client, err := newCalendarClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -311,9 +334,9 @@ func newTodayCmd() *cobra.Command {
 			endpoint := fmt.Sprintf("/calendars/%s/events?timeMin=%s&timeMax=%s&singleEvents=true&orderBy=startTime",
 				url.PathEscape(calendarID), url.QueryEscape(timeMin), url.QueryEscape(timeMax))
 
-			body, err := client.doRequest("GET", endpoint, nil)
+			body, err := client.doRequest(ctx, "GET", endpoint, nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -321,10 +344,10 @@ func newTodayCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"date":     now.Format("2006-01-02"),
 				"calendar": calendarID,
 				"count":    len(result.Items),
@@ -351,7 +374,8 @@ func newCreateCmd() *cobra.Command {
 		Use:   "create",
 		Short: "Create an event",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newCalendarClient()
+			ctx := cmd.Context()
+			client, err := newCalendarClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -379,14 +403,14 @@ func newCreateCmd() *cobra.Command {
 
 			endpoint := fmt.Sprintf("/calendars/%s/events", url.PathEscape(calendarID))
 
-			body, err := client.doRequest("POST", endpoint, event)
+			body, err := client.doRequest(ctx, "POST", endpoint, event)
 			if err != nil {
-				return output.PrintError("create_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "create_failed", err, nil)
 			}
 
 			var result calendarEvent
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			startTime := result.Start.DateTime
@@ -394,7 +418,7 @@ func newCreateCmd() *cobra.Command {
 				startTime = result.Start.Date
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"id":    result.ID,
 				"title": result.Summary,
 				"start": startTime,
@@ -422,14 +446,15 @@ func newCalendarsCmd() *cobra.Command {
 		Use:   "calendars",
 		Short: "List available calendars",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newCalendarClient()
+			ctx := cmd.Context()
+			client, err := newCalendarClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			body, err := client.doRequest("GET", "/users/me/calendarList", nil)
+			body, err := client.doRequest(ctx, "GET", "/users/me/calendarList", nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -444,7 +469,7 @@ func newCalendarsCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			calendars := make([]map[string]any, len(result.Items))
@@ -459,7 +484,7 @@ func newCalendarsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"count":     len(calendars),
 				"calendars": calendars,
 			})
@@ -477,7 +502,8 @@ func newDeleteCmd() *cobra.Command {
 		Short: "Delete an event",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newCalendarClient()
+			ctx := cmd.Context()
+			client, err := newCalendarClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -485,12 +511,12 @@ func newDeleteCmd() *cobra.Command {
 			endpoint := fmt.Sprintf("/calendars/%s/events/%s",
 				url.PathEscape(calendarID), url.PathEscape(args[0]))
 
-			_, err = client.doRequest("DELETE", endpoint, nil)
+			_, err = client.doRequest(ctx, "DELETE", endpoint, nil)
 			if err != nil {
-				return output.PrintError("delete_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "delete_failed", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"deleted":  true,
 				"event_id": args[0],
 			})
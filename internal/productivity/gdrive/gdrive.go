@@ -12,12 +12,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://www.googleapis.com/drive/v3"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // DriveSearchResult holds search results
 type DriveSearchResult struct {
@@ -50,6 +52,22 @@ type FileInfo struct {
 	Owner       string `json:"owner,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "gdrive",
+		Name:        "Google Drive",
+		Group:       "productivity",
+		Description: "Search and get file metadata from Google Drive",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "google_api_key", Description: "Google API key with Drive and Sheets APIs enabled", Required: true, Example: "AIzaSy..."},
+		},
+		SetupGuide:  "1. Go to https://console.cloud.google.com/\n2. Create a new project (or select existing)\n3. Enable these APIs:\n   - Google Drive API: https://console.cloud.google.com/apis/library/drive.googleapis.com\n   - Google Sheets API: https://console.cloud.google.com/apis/library/sheets.googleapis.com\n4. Go to Credentials > Create Credentials > API Key\n5. (Recommended) Restrict key to Drive and Sheets APIs\n6. Copy the API key\n7. Run: pocket config set google_api_key <your-api-key>\n\nNote: API key only allows access to publicly shared files. For private files, use OAuth (see calendar setup).",
+		TestCommand: "pocket productivity gdrive search test",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the Google Drive command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -64,10 +82,10 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func getAPIKey() (string, error) {
-	key, err := config.Get("google_api_key")
+func getAPIKey(ctx context.Context) (string, error) {
+	key, err := config.Get(ctx, "google_api_key")
 	if err != nil || key == "" {
-		return "", output.PrintError("setup_required", "Google API key not configured", map[string]any{
+		return "", output.PrintError(ctx, "setup_required", "Google API key not configured", map[string]any{
 			"missing":   []string{"google_api_key"},
 			"setup_cmd": "pocket config set google_api_key <your-key>",
 			"hint":      "Get an API key from https://console.cloud.google.com/apis/credentials",
@@ -85,7 +103,8 @@ func newSearchCmd() *cobra.Command {
 		Long:  `Search public files using Google Drive query syntax: name contains 'test', mimeType='application/pdf', etc.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			key, err := getAPIKey()
+			ctx := cmd.Context()
+			key, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -98,7 +117,7 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			reqURL := fmt.Sprintf("%s/files?%s", baseURL, params.Encode())
-			data, err := doRequest(reqURL, key)
+			data, err := doRequest(ctx, reqURL, key)
 			if err != nil {
 				return err
 			}
@@ -116,7 +135,7 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
 			}
 
 			files := make([]DriveFile, 0, len(resp.Files))
@@ -138,7 +157,7 @@ func newSearchCmd() *cobra.Command {
 				Count: len(files),
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -153,7 +172,8 @@ func newInfoCmd() *cobra.Command {
 		Short: "Get file metadata",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			key, err := getAPIKey()
+			ctx := cmd.Context()
+			key, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -164,7 +184,7 @@ func newInfoCmd() *cobra.Command {
 			}
 
 			reqURL := fmt.Sprintf("%s/files/%s?%s", baseURL, url.PathEscape(fileID), params.Encode())
-			data, err := doRequest(reqURL, key)
+			data, err := doRequest(ctx, reqURL, key)
 			if err != nil {
 				return err
 			}
@@ -184,7 +204,7 @@ func newInfoCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
 			}
 
 			ownerName := ""
@@ -204,20 +224,20 @@ func newInfoCmd() *cobra.Command {
 				Owner:       ownerName,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
 	return cmd
 }
 
-func doRequest(reqURL, key string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, reqURL, key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("request_failed", fmt.Sprintf("Failed to create request: %s", err.Error()), nil)
+		return nil, output.PrintError(ctx, "request_failed", fmt.Sprintf("Failed to create request: %s", err.Error()), nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -225,21 +245,21 @@ func doRequest(reqURL, key string) ([]byte, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("request_failed", fmt.Sprintf("Request failed: %s", err.Error()), nil)
+		return nil, output.PrintErr(ctx, "request_failed", fmt.Errorf("Request failed: %w", err), nil)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, output.PrintError("read_failed", fmt.Sprintf("Failed to read response: %s", err.Error()), nil)
+		return nil, output.PrintError(ctx, "read_failed", fmt.Sprintf("Failed to read response: %s", err.Error()), nil)
 	}
 
 	if resp.StatusCode == 403 {
-		return nil, output.PrintError("forbidden", "Access denied. Ensure the file is public and the API key is valid", nil)
+		return nil, output.PrintError(ctx, "forbidden", "Access denied. Ensure the file is public and the API key is valid", nil)
 	}
 
 	if resp.StatusCode == 404 {
-		return nil, output.PrintError("not_found", "File not found", nil)
+		return nil, output.PrintError(ctx, "not_found", "File not found", nil)
 	}
 
 	if resp.StatusCode >= 400 {
@@ -249,9 +269,9 @@ func doRequest(reqURL, key string) ([]byte, error) {
 			} `json:"error"`
 		}
 		if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
-			return nil, output.PrintError("api_error", errResp.Error.Message, nil)
+			return nil, output.PrintError(ctx, "api_error", errResp.Error.Message, nil)
 		}
-		return nil, output.PrintError("api_error", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "api_error", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	return body, nil
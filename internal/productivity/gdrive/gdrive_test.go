@@ -1,6 +1,7 @@
 package gdrive
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -68,6 +69,7 @@ func TestFormatSize(t *testing.T) {
 }
 
 func TestDoRequest(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check x-goog-api-key header
 		if r.Header.Get("x-goog-api-key") != "test-api-key" {
@@ -101,7 +103,7 @@ func TestDoRequest(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	data, err := doRequest(srv.URL+"/files", "test-api-key")
+	data, err := doRequest(ctx, srv.URL+"/files", "test-api-key")
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -129,6 +131,7 @@ func TestDoRequest(t *testing.T) {
 }
 
 func TestDoRequestForbidden(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusForbidden)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -143,13 +146,14 @@ func TestDoRequestForbidden(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest(srv.URL, "bad-key")
+	_, err := doRequest(ctx, srv.URL, "bad-key")
 	if err == nil {
 		t.Error("expected error for 403 response, got nil")
 	}
 }
 
 func TestDoRequestNotFound(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -164,13 +168,14 @@ func TestDoRequestNotFound(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest(srv.URL, "test-key")
+	_, err := doRequest(ctx, srv.URL, "test-key")
 	if err == nil {
 		t.Error("expected error for 404 response, got nil")
 	}
 }
 
 func TestSearchResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check query params
 		params := r.URL.Query()
@@ -202,7 +207,7 @@ func TestSearchResponse(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	data, err := doRequest(srv.URL+"?q=name+contains+'test'&pageSize=10", "test-key")
+	data, err := doRequest(ctx, srv.URL+"?q=name+contains+'test'&pageSize=10", "test-key")
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -228,6 +233,7 @@ func TestSearchResponse(t *testing.T) {
 }
 
 func TestFileInfoResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/files/file123" {
 			t.Errorf("expected path '/files/file123', got %q", r.URL.Path)
@@ -260,7 +266,7 @@ func TestFileInfoResponse(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	data, err := doRequest(srv.URL+"/files/file123?fields=id,name,mimeType,size,createdTime,modifiedTime,webViewLink,description,owners", "test-key")
+	data, err := doRequest(ctx, srv.URL+"/files/file123?fields=id,name,mimeType,size,createdTime,modifiedTime,webViewLink,description,owners", "test-key")
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -293,6 +299,7 @@ func TestFileInfoResponse(t *testing.T) {
 }
 
 func TestDoRequestAPIError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -307,7 +314,7 @@ func TestDoRequestAPIError(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest(srv.URL, "test-key")
+	_, err := doRequest(ctx, srv.URL, "test-key")
 	if err == nil {
 		t.Error("expected error for 400 response, got nil")
 	}
@@ -13,12 +13,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://sheets.googleapis.com/v4/spreadsheets"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // SpreadsheetInfo holds metadata about a spreadsheet
 type SpreadsheetInfo struct {
@@ -62,6 +64,22 @@ type CellMatch struct {
 	Col   int    `json:"col"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "gsheets",
+		Name:        "Google Sheets",
+		Group:       "productivity",
+		Description: "Read spreadsheets and search cell values in Google Sheets",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "google_api_key", Description: "Google API key with Drive and Sheets APIs enabled", Required: true, Example: "AIzaSy..."},
+		},
+		SetupGuide:  "1. Go to https://console.cloud.google.com/\n2. Create a new project (or select existing)\n3. Enable these APIs:\n   - Google Drive API: https://console.cloud.google.com/apis/library/drive.googleapis.com\n   - Google Sheets API: https://console.cloud.google.com/apis/library/sheets.googleapis.com\n4. Go to Credentials > Create Credentials > API Key\n5. (Recommended) Restrict key to Drive and Sheets APIs\n6. Copy the API key\n7. Run: pocket config set google_api_key <your-api-key>\n\nNote: API key only allows access to publicly shared files. For private files, use OAuth (see calendar setup).",
+		TestCommand: "pocket productivity gdrive search test",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the Google Sheets command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -77,10 +95,10 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func getAPIKey() (string, error) {
-	key, err := config.Get("google_api_key")
+func getAPIKey(ctx context.Context) (string, error) {
+	key, err := config.Get(ctx, "google_api_key")
 	if err != nil || key == "" {
-		return "", output.PrintError("setup_required", "Google API key not configured", map[string]any{
+		return "", output.PrintError(ctx, "setup_required", "Google API key not configured", map[string]any{
 			"missing":   []string{"google_api_key"},
 			"setup_cmd": "pocket config set google_api_key <your-key>",
 			"hint":      "Get an API key from https://console.cloud.google.com/apis/credentials",
@@ -95,7 +113,8 @@ func newGetCmd() *cobra.Command {
 		Short: "Get spreadsheet metadata",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			key, err := getAPIKey()
+			ctx := cmd.Context()
+			key, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -104,7 +123,7 @@ func newGetCmd() *cobra.Command {
 			reqURL := fmt.Sprintf("%s/%s?fields=properties,sheets.properties",
 				baseURL, url.PathEscape(spreadsheetID))
 
-			data, err := doRequest(reqURL, key)
+			data, err := doRequest(ctx, reqURL, key)
 			if err != nil {
 				return err
 			}
@@ -128,7 +147,7 @@ func newGetCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
 			}
 
 			sheets := make([]SheetInfo, 0, len(resp.Sheets))
@@ -149,7 +168,7 @@ func newGetCmd() *cobra.Command {
 				Sheets: sheets,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -163,7 +182,8 @@ func newReadCmd() *cobra.Command {
 		Long:  `Read cell values. Range format: "Sheet1!A1:D10" or "A1:D10"`,
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			key, err := getAPIKey()
+			ctx := cmd.Context()
+			key, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -175,7 +195,7 @@ func newReadCmd() *cobra.Command {
 				baseURL, url.PathEscape(spreadsheetID),
 				url.PathEscape(rangeStr))
 
-			data, err := doRequest(reqURL, key)
+			data, err := doRequest(ctx, reqURL, key)
 			if err != nil {
 				return err
 			}
@@ -186,7 +206,7 @@ func newReadCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
 			}
 
 			// Convert to string slices
@@ -211,7 +231,7 @@ func newReadCmd() *cobra.Command {
 				ColCount:      maxCols,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -224,7 +244,8 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search for a value across all sheets",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			key, err := getAPIKey()
+			ctx := cmd.Context()
+			key, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -236,7 +257,7 @@ func newSearchCmd() *cobra.Command {
 			metaURL := fmt.Sprintf("%s/%s?fields=sheets.properties.title",
 				baseURL, url.PathEscape(spreadsheetID))
 
-			metaData, err := doRequest(metaURL, key)
+			metaData, err := doRequest(ctx, metaURL, key)
 			if err != nil {
 				return err
 			}
@@ -250,11 +271,11 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(metaData, &metaResp); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse metadata: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse metadata: %s", err.Error()), nil)
 			}
 
 			if len(metaResp.Sheets) == 0 {
-				return output.Print(SearchResult{
+				return output.Print(ctx, SearchResult{
 					Query:   query,
 					Matches: []CellMatch{},
 					Count:   0,
@@ -270,7 +291,7 @@ func newSearchCmd() *cobra.Command {
 			batchURL := fmt.Sprintf("%s/%s/values:batchGet?%s",
 				baseURL, url.PathEscape(spreadsheetID), params.Encode())
 
-			batchData, err := doRequest(batchURL, key)
+			batchData, err := doRequest(ctx, batchURL, key)
 			if err != nil {
 				return err
 			}
@@ -283,7 +304,7 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(batchData, &batchResp); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse batch data: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse batch data: %s", err.Error()), nil)
 			}
 
 			queryLower := strings.ToLower(query)
@@ -325,20 +346,20 @@ func newSearchCmd() *cobra.Command {
 				Count:   len(matches),
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
 	return cmd
 }
 
-func doRequest(reqURL, key string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, reqURL, key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("request_failed", fmt.Sprintf("Failed to create request: %s", err.Error()), nil)
+		return nil, output.PrintError(ctx, "request_failed", fmt.Sprintf("Failed to create request: %s", err.Error()), nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -346,21 +367,21 @@ func doRequest(reqURL, key string) ([]byte, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("request_failed", fmt.Sprintf("Request failed: %s", err.Error()), nil)
+		return nil, output.PrintErr(ctx, "request_failed", fmt.Errorf("Request failed: %w", err), nil)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, output.PrintError("read_failed", fmt.Sprintf("Failed to read response: %s", err.Error()), nil)
+		return nil, output.PrintError(ctx, "read_failed", fmt.Sprintf("Failed to read response: %s", err.Error()), nil)
 	}
 
 	if resp.StatusCode == 403 {
-		return nil, output.PrintError("forbidden", "Access denied. Ensure the spreadsheet is public and the API key is valid", nil)
+		return nil, output.PrintError(ctx, "forbidden", "Access denied. Ensure the spreadsheet is public and the API key is valid", nil)
 	}
 
 	if resp.StatusCode == 404 {
-		return nil, output.PrintError("not_found", "Spreadsheet or range not found", nil)
+		return nil, output.PrintError(ctx, "not_found", "Spreadsheet or range not found", nil)
 	}
 
 	if resp.StatusCode >= 400 {
@@ -370,9 +391,9 @@ func doRequest(reqURL, key string) ([]byte, error) {
 			} `json:"error"`
 		}
 		if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
-			return nil, output.PrintError("api_error", errResp.Error.Message, nil)
+			return nil, output.PrintError(ctx, "api_error", errResp.Error.Message, nil)
 		}
-		return nil, output.PrintError("api_error", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "api_error", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	return body, nil
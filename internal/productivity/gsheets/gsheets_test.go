@@ -1,6 +1,7 @@
 package gsheets
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -43,6 +44,7 @@ func TestColToLetter(t *testing.T) {
 }
 
 func TestDoRequest(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check x-goog-api-key header
 		if r.Header.Get("x-goog-api-key") != "test-api-key" {
@@ -82,7 +84,7 @@ func TestDoRequest(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	data, err := doRequest(srv.URL+"/spreadsheet123", "test-api-key")
+	data, err := doRequest(ctx, srv.URL+"/spreadsheet123", "test-api-key")
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -102,6 +104,7 @@ func TestDoRequest(t *testing.T) {
 }
 
 func TestDoRequestForbidden(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusForbidden)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -116,13 +119,14 @@ func TestDoRequestForbidden(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest(srv.URL, "bad-key")
+	_, err := doRequest(ctx, srv.URL, "bad-key")
 	if err == nil {
 		t.Error("expected error for 403 response, got nil")
 	}
 }
 
 func TestDoRequestNotFound(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -137,13 +141,14 @@ func TestDoRequestNotFound(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest(srv.URL, "test-key")
+	_, err := doRequest(ctx, srv.URL, "test-key")
 	if err == nil {
 		t.Error("expected error for 404 response, got nil")
 	}
 }
 
 func TestGetSpreadsheetMetadata(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/spreadsheet123" {
 			t.Errorf("expected path '/spreadsheet123', got %q", r.URL.Path)
@@ -193,7 +198,7 @@ func TestGetSpreadsheetMetadata(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	data, err := doRequest(srv.URL+"/spreadsheet123?fields=properties,sheets.properties", "test-key")
+	data, err := doRequest(ctx, srv.URL+"/spreadsheet123?fields=properties,sheets.properties", "test-key")
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -226,6 +231,7 @@ func TestGetSpreadsheetMetadata(t *testing.T) {
 }
 
 func TestReadRangeResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/spreadsheet123/values/Sheet1!A1:C3" {
 			t.Errorf("expected path '/spreadsheet123/values/Sheet1!A1:C3', got %q", r.URL.Path)
@@ -247,7 +253,7 @@ func TestReadRangeResponse(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	data, err := doRequest(srv.URL+"/spreadsheet123/values/Sheet1!A1:C3", "test-key")
+	data, err := doRequest(ctx, srv.URL+"/spreadsheet123/values/Sheet1!A1:C3", "test-key")
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -274,6 +280,7 @@ func TestReadRangeResponse(t *testing.T) {
 }
 
 func TestBatchGetResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check that multiple ranges are requested
 		params := r.URL.Query()
@@ -306,7 +313,7 @@ func TestBatchGetResponse(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	data, err := doRequest(srv.URL+"/spreadsheet123/values:batchGet?ranges=Sheet1!A:ZZ&ranges=Sheet2!A:ZZ", "test-key")
+	data, err := doRequest(ctx, srv.URL+"/spreadsheet123/values:batchGet?ranges=Sheet1!A:ZZ&ranges=Sheet2!A:ZZ", "test-key")
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -401,6 +408,7 @@ func TestSearchLogic(t *testing.T) {
 }
 
 func TestDoRequestAPIError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -415,7 +423,7 @@ func TestDoRequestAPIError(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest(srv.URL, "test-key")
+	_, err := doRequest(ctx, srv.URL, "test-key")
 	if err == nil {
 		t.Error("expected error for 400 response, got nil")
 	}
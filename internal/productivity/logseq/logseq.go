@@ -1,6 +1,7 @@
 package logseq
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,6 +13,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -38,6 +41,24 @@ type SearchResult struct {
 	Content string `json:"content"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "logseq",
+		Name:        "Logseq",
+		Group:       "productivity",
+		Description: "Local Logseq graphs - read/write pages, search, journals",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "logseq_graph", Description: "Path to default Logseq graph", Required: true, Example: "/Users/you/Documents/logseq-graph"},
+			{Key: "logseq_graphs", Description: "JSON array of additional graphs (optional)", Example: "[{\"name\":\"work\",\"path\":\"/path/to/work\",\"format\":\"md\"}]"},
+			{Key: "logseq_format", Description: "File format: md or org (default: md)", Example: "md"},
+		},
+		SetupGuide:  "Logseq works with local graphs (markdown/org files). No API key required.\n\n1. Find your Logseq graph path (the folder containing pages/ and journals/ directories)\n2. Run: pocket config set logseq_graph /path/to/your/graph\n\nOptional - Set file format (md or org):\n   pocket config set logseq_format md\n\nOptional - Multiple graphs:\n   pocket config set logseq_graphs '[{\"name\":\"work\",\"path\":\"/path/to/work\",\"format\":\"md\"},{\"name\":\"personal\",\"path\":\"/path/to/personal\",\"format\":\"org\"}]'\n\nGraph structure:\n   your-graph/\n   ├── pages/          # Regular pages\n   ├── journals/       # Daily journal entries (YYYY_MM_DD.md)\n   └── logseq/         # Logseq config (not used by CLI)\n\nPage names with special characters (/, :, ?) are URL-encoded in filenames.",
+		TestCommand: "pocket productivity logseq graphs",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd creates the logseq command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -58,10 +79,10 @@ func NewCmd() *cobra.Command {
 }
 
 // getGraphPath returns the path to the default graph or specified graph
-func getGraphPath(graphName string) (graphPath, format string, err error) {
+func getGraphPath(ctx context.Context, graphName string) (graphPath, format string, err error) {
 	// If a specific graph is requested, look it up in graphs list
 	if graphName != "" {
-		graphsJSON, _ := config.Get("logseq_graphs")
+		graphsJSON, _ := config.Get(ctx, "logseq_graphs")
 		if graphsJSON != "" {
 			var graphs []Graph
 			if err := json.Unmarshal([]byte(graphsJSON), &graphs); err == nil {
@@ -74,7 +95,7 @@ func getGraphPath(graphName string) (graphPath, format string, err error) {
 		}
 		// If not in list, assume it's a path
 		if _, err := os.Stat(graphName); err == nil {
-			format, _ = config.Get("logseq_format")
+			format, _ = config.Get(ctx, "logseq_format")
 			if format == "" {
 				format = "md"
 			}
@@ -84,7 +105,7 @@ func getGraphPath(graphName string) (graphPath, format string, err error) {
 	}
 
 	// Use default graph
-	graphPath, err = config.Get("logseq_graph")
+	graphPath, err = config.Get(ctx, "logseq_graph")
 	if err != nil {
 		return "", "", err
 	}
@@ -92,7 +113,7 @@ func getGraphPath(graphName string) (graphPath, format string, err error) {
 		return "", "", fmt.Errorf("logseq_graph not configured (use: pocket config set logseq_graph /path/to/graph)")
 	}
 
-	format, _ = config.Get("logseq_format")
+	format, _ = config.Get(ctx, "logseq_format")
 	if format == "" {
 		format = "md"
 	}
@@ -114,11 +135,12 @@ func newGraphsCmd() *cobra.Command {
 		Use:   "graphs",
 		Short: "List configured graphs",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			var graphs []Graph
 
 			// Get default graph
-			defaultGraph, _ := config.Get("logseq_graph")
-			format, _ := config.Get("logseq_format")
+			defaultGraph, _ := config.Get(ctx, "logseq_graph")
+			format, _ := config.Get(ctx, "logseq_format")
 			if format == "" {
 				format = "md"
 			}
@@ -132,7 +154,7 @@ func newGraphsCmd() *cobra.Command {
 			}
 
 			// Get additional graphs
-			graphsJSON, _ := config.Get("logseq_graphs")
+			graphsJSON, _ := config.Get(ctx, "logseq_graphs")
 			if graphsJSON != "" {
 				var additionalGraphs []Graph
 				if err := json.Unmarshal([]byte(graphsJSON), &additionalGraphs); err == nil {
@@ -141,13 +163,13 @@ func newGraphsCmd() *cobra.Command {
 			}
 
 			if len(graphs) == 0 {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"graphs":  []Graph{},
 					"message": "No graphs configured. Set with: pocket config set logseq_graph /path/to/graph",
 				})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"graphs":        graphs,
 				"default_graph": defaultGraph,
 			})
@@ -166,9 +188,10 @@ func newPagesCmd() *cobra.Command {
 		Use:   "pages",
 		Short: "List pages in graph",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			graphPath, format, err := getGraphPath(graphName)
+			ctx := cmd.Context()
+			graphPath, format, err := getGraphPath(ctx, graphName)
 			if err != nil {
-				return output.PrintError("config_error", err.Error(), nil)
+				return output.PrintErr(ctx, "config_error", err, nil)
 			}
 
 			pagesDir := filepath.Join(graphPath, "pages")
@@ -176,10 +199,10 @@ func newPagesCmd() *cobra.Command {
 
 			pages, err := listPages(pagesDir, ext, limit)
 			if err != nil {
-				return output.PrintError("list_error", err.Error(), nil)
+				return output.PrintErr(ctx, "list_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"graph": graphPath,
 				"pages": pages,
 				"count": len(pages),
@@ -271,22 +294,23 @@ func newReadCmd() *cobra.Command {
 		Short: "Read a page's content",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			pageName := args[0]
 
-			graphPath, format, err := getGraphPath(graphName)
+			graphPath, format, err := getGraphPath(ctx, graphName)
 			if err != nil {
-				return output.PrintError("config_error", err.Error(), nil)
+				return output.PrintErr(ctx, "config_error", err, nil)
 			}
 
 			// Try to find the page
 			pagePath, err := findPage(graphPath, pageName, format)
 			if err != nil {
-				return output.PrintError("not_found", err.Error(), nil)
+				return output.PrintErr(ctx, "not_found", err, nil)
 			}
 
 			content, err := os.ReadFile(pagePath)
 			if err != nil {
-				return output.PrintError("read_error", err.Error(), nil)
+				return output.PrintErr(ctx, "read_error", err, nil)
 			}
 
 			info, _ := os.Stat(pagePath)
@@ -295,7 +319,7 @@ func newReadCmd() *cobra.Command {
 				modTime = info.ModTime()
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"page":        pageName,
 				"path":        pagePath,
 				"content":     string(content),
@@ -362,22 +386,18 @@ func newWriteCmd() *cobra.Command {
 		Short: "Create or update a page",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			pageName := args[0]
 			content := args[1]
 
-			graphPath, format, err := getGraphPath(graphName)
+			graphPath, format, err := getGraphPath(ctx, graphName)
 			if err != nil {
-				return output.PrintError("config_error", err.Error(), nil)
+				return output.PrintErr(ctx, "config_error", err, nil)
 			}
 
 			pagesDir := filepath.Join(graphPath, "pages")
 			ext := getFileExtension(format)
 
-			// Ensure pages directory exists
-			if err := os.MkdirAll(pagesDir, 0o755); err != nil {
-				return output.PrintError("write_error", "failed to create pages directory", err.Error())
-			}
-
 			encodedName := encodePageName(pageName)
 			pagePath := filepath.Join(pagesDir, encodedName+ext)
 
@@ -386,15 +406,24 @@ func newWriteCmd() *cobra.Command {
 				// Read existing content
 				existing, err := os.ReadFile(pagePath)
 				if err != nil && !os.IsNotExist(err) {
-					return output.PrintError("read_error", err.Error(), nil)
+					return output.PrintErr(ctx, "read_error", err, nil)
 				}
 				finalContent = string(existing) + "\n" + content
 			} else {
 				finalContent = content
 			}
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.Record(ctx, dryrun.Request{Protocol: "file", Target: pagePath, Command: "write", Body: finalContent})
+			}
+
+			// Ensure pages directory exists
+			if err := os.MkdirAll(pagesDir, 0o755); err != nil {
+				return output.PrintError(ctx, "write_error", "failed to create pages directory", err.Error())
+			}
+
 			if err := os.WriteFile(pagePath, []byte(finalContent), 0o600); err != nil {
-				return output.PrintError("write_error", err.Error(), nil)
+				return output.PrintErr(ctx, "write_error", err, nil)
 			}
 
 			action := "created"
@@ -402,7 +431,7 @@ func newWriteCmd() *cobra.Command {
 				action = "appended"
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"action":  action,
 				"page":    pageName,
@@ -428,19 +457,20 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search pages by content",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := args[0]
 
-			graphPath, format, err := getGraphPath(graphName)
+			graphPath, format, err := getGraphPath(ctx, graphName)
 			if err != nil {
-				return output.PrintError("config_error", err.Error(), nil)
+				return output.PrintErr(ctx, "config_error", err, nil)
 			}
 
 			results, err := searchPages(graphPath, query, format, limit, caseSensitive)
 			if err != nil {
-				return output.PrintError("search_error", err.Error(), nil)
+				return output.PrintErr(ctx, "search_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"query":   query,
 				"graph":   graphPath,
 				"results": results,
@@ -542,9 +572,10 @@ func newJournalCmd() *cobra.Command {
 		Use:   "journal",
 		Short: "Get or create journal entry",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			graphPath, format, err := getGraphPath(graphName)
+			ctx := cmd.Context()
+			graphPath, format, err := getGraphPath(ctx, graphName)
 			if err != nil {
-				return output.PrintError("config_error", err.Error(), nil)
+				return output.PrintErr(ctx, "config_error", err, nil)
 			}
 
 			// Parse date or use today
@@ -552,7 +583,7 @@ func newJournalCmd() *cobra.Command {
 			if dateStr != "" {
 				date, err = parseDate(dateStr)
 				if err != nil {
-					return output.PrintError("date_error", err.Error(), nil)
+					return output.PrintErr(ctx, "date_error", err, nil)
 				}
 			} else {
 				date = time.Now()
@@ -571,23 +602,26 @@ func newJournalCmd() *cobra.Command {
 
 			// If content is provided, write to journal
 			if content != "" {
-				// Ensure journals directory exists
-				if err := os.MkdirAll(journalsDir, 0o755); err != nil {
-					return output.PrintError("write_error", "failed to create journals directory", err.Error())
-				}
-
 				var finalContent string
 				if exists {
 					finalContent = string(existingContent) + "\n" + content
 				} else {
 					finalContent = content
 				}
+				if dryrun.Enabled(ctx) {
+					return dryrun.Record(ctx, dryrun.Request{Protocol: "file", Target: journalPath, Command: "write", Body: finalContent})
+				}
+
+				// Ensure journals directory exists
+				if err := os.MkdirAll(journalsDir, 0o755); err != nil {
+					return output.PrintError(ctx, "write_error", "failed to create journals directory", err.Error())
+				}
 
 				if err := os.WriteFile(journalPath, []byte(finalContent), 0o600); err != nil {
-					return output.PrintError("write_error", err.Error(), nil)
+					return output.PrintErr(ctx, "write_error", err, nil)
 				}
 
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"success": true,
 					"action":  "updated",
 					"date":    date.Format("2006-01-02"),
@@ -597,7 +631,7 @@ func newJournalCmd() *cobra.Command {
 
 			// Return journal content
 			if !exists {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"date":    date.Format("2006-01-02"),
 					"path":    journalPath,
 					"exists":  false,
@@ -612,7 +646,7 @@ func newJournalCmd() *cobra.Command {
 				modTime = info.ModTime()
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"date":        date.Format("2006-01-02"),
 				"path":        journalPath,
 				"exists":      true,
@@ -660,9 +694,10 @@ func newRecentCmd() *cobra.Command {
 		Use:   "recent",
 		Short: "List recently modified pages",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			graphPath, format, err := getGraphPath(graphName)
+			ctx := cmd.Context()
+			graphPath, format, err := getGraphPath(ctx, graphName)
 			if err != nil {
-				return output.PrintError("config_error", err.Error(), nil)
+				return output.PrintErr(ctx, "config_error", err, nil)
 			}
 
 			ext := getFileExtension(format)
@@ -693,7 +728,7 @@ func newRecentCmd() *cobra.Command {
 				allPages = allPages[:limit]
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"graph": graphPath,
 				"pages": allPages,
 				"count": len(allPages),
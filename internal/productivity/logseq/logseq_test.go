@@ -1,6 +1,7 @@
 package logseq
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -26,6 +27,7 @@ func TestNewCmd(t *testing.T) {
 }
 
 func setupTestGraph(t *testing.T) (string, func()) {
+	ctx := context.Background()
 	tmpDir, err := os.MkdirTemp("", "logseq-test-*")
 	if err != nil {
 		t.Fatal(err)
@@ -78,10 +80,10 @@ func setupTestGraph(t *testing.T) (string, func()) {
 	tmpConfig.Close()
 
 	os.Setenv("POCKET_CONFIG", tmpConfig.Name())
-	if err := config.Set("logseq_graph", tmpDir); err != nil {
+	if err := config.Set(ctx, "logseq_graph", tmpDir); err != nil {
 		t.Fatal(err)
 	}
-	if err := config.Set("logseq_format", "md"); err != nil {
+	if err := config.Set(ctx, "logseq_format", "md"); err != nil {
 		t.Fatal(err)
 	}
 
@@ -298,6 +300,7 @@ func TestEncodeDecodPageName(t *testing.T) {
 }
 
 func TestGetGraphPath_NoConfig(t *testing.T) {
+	ctx := context.Background()
 	tmpConfig, err := os.CreateTemp("", "config-*.json")
 	if err != nil {
 		t.Fatal(err)
@@ -313,12 +316,12 @@ func TestGetGraphPath_NoConfig(t *testing.T) {
 	defer os.Unsetenv("POCKET_CONFIG")
 
 	// Check if previous tests left state (due to sync.Once in config package)
-	graph, _ := config.Get("logseq_graph")
+	graph, _ := config.Get(ctx, "logseq_graph")
 	if graph != "" {
 		t.Skip("config state persists across tests due to sync.Once")
 	}
 
-	_, _, err = getGraphPath("")
+	_, _, err = getGraphPath(ctx, "")
 	if err == nil {
 		t.Error("expected error for missing graph config, got nil")
 	}
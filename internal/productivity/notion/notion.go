@@ -12,11 +12,30 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var apiBaseURL = "https://api.notion.com/v1"
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "notion",
+		Name:        "Notion",
+		Group:       "productivity",
+		Description: "Search pages and query databases in Notion",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "notion_token", Description: "Internal integration token", Required: true, Example: "ntn_xxxx"},
+		},
+		SetupGuide:  "1. Go to https://www.notion.so/my-integrations\n2. Create new integration\n3. Copy the Internal Integration Token\n4. Share your pages/databases with the integration\n5. Run: pocket config set notion_token <token>",
+		TestCommand: "pocket productivity notion search test",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "notion",
@@ -37,20 +56,20 @@ type notionClient struct {
 	httpClient *http.Client
 }
 
-func newNotionClient() (*notionClient, error) {
-	token, err := config.MustGet("notion_token")
+func newNotionClient(ctx context.Context) (*notionClient, error) {
+	token, err := config.MustGet(ctx, "notion_token")
 	if err != nil {
 		return nil, err
 	}
 
 	return &notionClient{
 		token:      token,
-		httpClient: &http.Client{},
+		httpClient: transport.New(0),
 	}, nil
 }
 
-func (c *notionClient) doRequest(method, endpoint string, body interface{}) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (c *notionClient) doRequest(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	var reqBody io.Reader
@@ -135,7 +154,8 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search Notion",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newNotionClient()
+			ctx := cmd.Context()
+			client, err := newNotionClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -152,9 +172,9 @@ func newSearchCmd() *cobra.Command {
 				}
 			}
 
-			body, err := client.doRequest("POST", "/search", payload)
+			body, err := client.doRequest(ctx, "POST", "/search", payload)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -171,7 +191,7 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			items := make([]map[string]any, len(result.Results))
@@ -191,7 +211,7 @@ func newSearchCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"query":    args[0],
 				"count":    len(items),
 				"has_more": result.HasMore,
@@ -212,15 +232,16 @@ func newPageCmd() *cobra.Command {
 		Short: "Get page content",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newNotionClient()
+			ctx := cmd.Context()
+			client, err := newNotionClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			// Get page metadata
-			pageBody, err := client.doRequest("GET", "/pages/"+args[0], nil)
+			pageBody, err := client.doRequest(ctx, "GET", "/pages/"+args[0], nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var page struct {
@@ -231,13 +252,13 @@ func newPageCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(pageBody, &page); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			// Get page blocks (content)
-			blocksBody, err := client.doRequest("GET", "/blocks/"+args[0]+"/children?page_size=100", nil)
+			blocksBody, err := client.doRequest(ctx, "GET", "/blocks/"+args[0]+"/children?page_size=100", nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var blocks struct {
@@ -245,7 +266,7 @@ func newPageCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(blocksBody, &blocks); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			// Extract text content from blocks
@@ -276,7 +297,7 @@ func newPageCmd() *cobra.Command {
 				content = append(content, item)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"id":      page.ID,
 				"url":     page.URL,
 				"title":   extractTitle(page.Properties),
@@ -289,7 +310,7 @@ func newPageCmd() *cobra.Command {
 }
 
 func newDatabaseCmd() *cobra.Command {
-	var limit int
+	var page paginate.Options
 
 	cmd := &cobra.Command{
 		Use:     "database [database-id]",
@@ -297,52 +318,58 @@ func newDatabaseCmd() *cobra.Command {
 		Short:   "Query a database",
 		Args:    cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newNotionClient()
+			ctx := cmd.Context()
+			client, err := newNotionClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			payload := map[string]any{
-				"page_size": limit,
-			}
+			return paginate.Run(ctx, page, func(cursor string, size int) (paginate.Page[map[string]any], error) {
+				payload := map[string]any{
+					"page_size": size,
+				}
+				if cursor != "" {
+					payload["start_cursor"] = cursor
+				}
 
-			body, err := client.doRequest("POST", "/databases/"+args[0]+"/query", payload)
-			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
-			}
+				body, err := client.doRequest(ctx, "POST", "/databases/"+args[0]+"/query", payload)
+				if err != nil {
+					return paginate.Page[map[string]any]{}, output.PrintErr(ctx, "request_failed", err, nil)
+				}
 
-			var result struct {
-				Results []struct {
-					ID         string         `json:"id"`
-					URL        string         `json:"url"`
-					Properties map[string]any `json:"properties"`
-				} `json:"results"`
-				HasMore bool `json:"has_more"`
-			}
+				var result struct {
+					Results []struct {
+						ID         string         `json:"id"`
+						URL        string         `json:"url"`
+						Properties map[string]any `json:"properties"`
+					} `json:"results"`
+					HasMore    bool   `json:"has_more"`
+					NextCursor string `json:"next_cursor"`
+				}
 
-			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
-			}
+				if err := json.Unmarshal(body, &result); err != nil {
+					return paginate.Page[map[string]any]{}, output.PrintErr(ctx, "parse_error", err, nil)
+				}
 
-			items := make([]map[string]any, len(result.Results))
-			for i, r := range result.Results {
-				items[i] = map[string]any{
-					"id":    r.ID,
-					"url":   r.URL,
-					"title": extractTitle(r.Properties),
+				items := make([]map[string]any, len(result.Results))
+				for i, r := range result.Results {
+					items[i] = map[string]any{
+						"id":    r.ID,
+						"url":   r.URL,
+						"title": extractTitle(r.Properties),
+					}
 				}
-			}
 
-			return output.Print(map[string]any{
-				"database_id": args[0],
-				"count":       len(items),
-				"has_more":    result.HasMore,
-				"items":       items,
+				next := ""
+				if result.HasMore {
+					next = result.NextCursor
+				}
+				return paginate.Page[map[string]any]{Items: items, Next: next}, nil
 			})
 		},
 	}
 
-	cmd.Flags().IntVarP(&limit, "limit", "l", 100, "Number of results")
+	paginate.AddFlags(cmd, &page, 100, 100, "results")
 
 	return cmd
 }
@@ -353,14 +380,15 @@ func newBlocksCmd() *cobra.Command {
 		Short: "Get block children",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newNotionClient()
+			ctx := cmd.Context()
+			client, err := newNotionClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			body, err := client.doRequest("GET", "/blocks/"+args[0]+"/children?page_size=100", nil)
+			body, err := client.doRequest(ctx, "GET", "/blocks/"+args[0]+"/children?page_size=100", nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -369,10 +397,10 @@ func newBlocksCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"block_id": args[0],
 				"count":    len(result.Results),
 				"has_more": result.HasMore,
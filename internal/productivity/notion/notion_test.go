@@ -1,6 +1,7 @@
 package notion
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -66,6 +67,7 @@ func TestExtractTitle(t *testing.T) {
 }
 
 func TestDoRequest(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check authorization header
 		if r.Header.Get("Authorization") != "Bearer test-token" {
@@ -106,7 +108,7 @@ func TestDoRequest(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	body, err := client.doRequest("GET", "/search", nil)
+	body, err := client.doRequest(ctx, "GET", "/search", nil)
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -130,6 +132,7 @@ func TestDoRequest(t *testing.T) {
 }
 
 func TestDoRequestPOST(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check method
 		if r.Method != "POST" {
@@ -177,7 +180,7 @@ func TestDoRequestPOST(t *testing.T) {
 		"query": "test query",
 	}
 
-	body, err := client.doRequest("POST", "/search", payload)
+	body, err := client.doRequest(ctx, "POST", "/search", payload)
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -197,6 +200,7 @@ func TestDoRequestPOST(t *testing.T) {
 }
 
 func TestDoRequestError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -215,13 +219,14 @@ func TestDoRequestError(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	_, err := client.doRequest("GET", "/search", nil)
+	_, err := client.doRequest(ctx, "GET", "/search", nil)
 	if err == nil {
 		t.Error("expected error for 401 response, got nil")
 	}
 }
 
 func TestDoRequestNotFound(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -240,13 +245,14 @@ func TestDoRequestNotFound(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	_, err := client.doRequest("GET", "/pages/nonexistent", nil)
+	_, err := client.doRequest(ctx, "GET", "/pages/nonexistent", nil)
 	if err == nil {
 		t.Error("expected error for 404 response, got nil")
 	}
 }
 
 func TestPageResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Mock page metadata response
 		w.WriteHeader(http.StatusOK)
@@ -271,7 +277,7 @@ func TestPageResponse(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	body, err := client.doRequest("GET", "/pages/page123", nil)
+	body, err := client.doRequest(ctx, "GET", "/pages/page123", nil)
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -296,6 +302,7 @@ func TestPageResponse(t *testing.T) {
 }
 
 func TestDatabaseQueryResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Mock database query response
 		w.WriteHeader(http.StatusOK)
@@ -327,7 +334,7 @@ func TestDatabaseQueryResponse(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	body, err := client.doRequest("POST", "/databases/db123/query", map[string]any{"page_size": 10})
+	body, err := client.doRequest(ctx, "POST", "/databases/db123/query", map[string]any{"page_size": 10})
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -1,6 +1,7 @@
 package obsidian
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/fs"
@@ -13,6 +14,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -31,6 +34,24 @@ type VaultInfo struct {
 	Path string `json:"path"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "obsidian",
+		Name:        "Obsidian",
+		Group:       "productivity",
+		Description: "Local Obsidian vaults - read/write notes, search, daily notes",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "obsidian_vault", Description: "Path to default Obsidian vault", Required: true, Example: "/Users/you/Documents/MyVault"},
+			{Key: "obsidian_vaults", Description: "JSON array of additional vaults (optional)", Example: "[{\"name\":\"work\",\"path\":\"/path/to/work\"}]"},
+			{Key: "obsidian_daily_format", Description: "Daily note date format (default: 2006-01-02)", Example: "2006-01-02"},
+		},
+		SetupGuide:  "Obsidian works with local markdown vaults. No API key required.\n\n1. Find your Obsidian vault path (the folder containing your .obsidian directory)\n2. Run: pocket config set obsidian_vault /path/to/your/vault\n\nOptional - Multiple vaults:\n   pocket config set obsidian_vaults '[{\"name\":\"work\",\"path\":\"/path/to/work\"},{\"name\":\"personal\",\"path\":\"/path/to/personal\"}]'\n\nOptional - Custom daily note format (Go date format):\n   pocket config set obsidian_daily_format \"2006-01-02\"\n\nCommon daily note formats:\n   2006-01-02      -> 2024-01-15\n   01-02-2006      -> 01-15-2024\n   2006/01/02      -> 2024/01/15\n   January 2, 2006 -> January 15, 2024",
+		TestCommand: "pocket productivity obsidian vaults",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the obsidian command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -52,10 +73,10 @@ func NewCmd() *cobra.Command {
 }
 
 // getVaultPath returns the configured vault path
-func getVaultPath(vaultName string) (string, error) {
+func getVaultPath(ctx context.Context, vaultName string) (string, error) {
 	// If a specific vault name is provided, look it up in the vaults list
 	if vaultName != "" {
-		vaultsJSON, err := config.Get("obsidian_vaults")
+		vaultsJSON, err := config.Get(ctx, "obsidian_vaults")
 		if err == nil && vaultsJSON != "" {
 			var vaults []VaultInfo
 			if err := json.Unmarshal([]byte(vaultsJSON), &vaults); err == nil {
@@ -74,7 +95,7 @@ func getVaultPath(vaultName string) (string, error) {
 	}
 
 	// Use default vault
-	vaultPath, err := config.Get("obsidian_vault")
+	vaultPath, err := config.Get(ctx, "obsidian_vault")
 	if err != nil {
 		return "", err
 	}
@@ -117,8 +138,8 @@ func validateInsideVault(vaultPath, fullPath string) error {
 }
 
 // getDailyFormat returns the daily note format
-func getDailyFormat() string {
-	format, err := config.Get("obsidian_daily_format")
+func getDailyFormat(ctx context.Context) string {
+	format, err := config.Get(ctx, "obsidian_daily_format")
 	if err != nil || format == "" {
 		return "2006-01-02" // Default Go date format (YYYY-MM-DD)
 	}
@@ -131,10 +152,11 @@ func newVaultsCmd() *cobra.Command {
 		Use:   "vaults",
 		Short: "List configured vaults",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			vaults := []VaultInfo{}
 
 			// Get default vault
-			defaultPath, _ := config.Get("obsidian_vault")
+			defaultPath, _ := config.Get(ctx, "obsidian_vault")
 			if defaultPath != "" {
 				vaults = append(vaults, VaultInfo{
 					Name: "default",
@@ -143,7 +165,7 @@ func newVaultsCmd() *cobra.Command {
 			}
 
 			// Get additional vaults
-			vaultsJSON, _ := config.Get("obsidian_vaults")
+			vaultsJSON, _ := config.Get(ctx, "obsidian_vaults")
 			if vaultsJSON != "" {
 				var additionalVaults []VaultInfo
 				if err := json.Unmarshal([]byte(vaultsJSON), &additionalVaults); err == nil {
@@ -152,13 +174,13 @@ func newVaultsCmd() *cobra.Command {
 			}
 
 			if len(vaults) == 0 {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"vaults":  []VaultInfo{},
 					"message": "No vaults configured. Use: pocket config set obsidian_vault /path/to/vault",
 				})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"vaults": vaults,
 				"count":  len(vaults),
 			})
@@ -178,9 +200,10 @@ func newNotesCmd() *cobra.Command {
 		Use:   "notes",
 		Short: "List notes in vault",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			vaultPath, err := getVaultPath(vault)
+			ctx := cmd.Context()
+			vaultPath, err := getVaultPath(ctx, vault)
 			if err != nil {
-				return output.PrintError("vault_error", err.Error(), nil)
+				return output.PrintErr(ctx, "vault_error", err, nil)
 			}
 
 			searchPath := vaultPath
@@ -188,10 +211,10 @@ func newNotesCmd() *cobra.Command {
 				searchPath = filepath.Join(vaultPath, folder)
 				// Prevent path traversal
 				if err := validateInsideVault(vaultPath, searchPath); err != nil {
-					return output.PrintError("invalid_path", "Invalid folder path: "+err.Error(), nil)
+					return output.PrintError(ctx, "invalid_path", "Invalid folder path: "+err.Error(), nil)
 				}
 				if _, err := os.Stat(searchPath); os.IsNotExist(err) {
-					return output.PrintError("folder_not_found", "Folder does not exist: "+folder, nil)
+					return output.PrintError(ctx, "folder_not_found", "Folder does not exist: "+folder, nil)
 				}
 			}
 
@@ -234,7 +257,7 @@ func newNotesCmd() *cobra.Command {
 			})
 
 			if err != nil {
-				return output.PrintError("walk_error", "Failed to list notes: "+err.Error(), nil)
+				return output.PrintError(ctx, "walk_error", "Failed to list notes: "+err.Error(), nil)
 			}
 
 			// Sort by name
@@ -247,7 +270,7 @@ func newNotesCmd() *cobra.Command {
 				notes = notes[:limit]
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"notes":  notes,
 				"count":  len(notes),
 				"vault":  vaultPath,
@@ -273,14 +296,15 @@ func newReadCmd() *cobra.Command {
 		Long:  `Read a note by name or path. If no extension is provided, .md is assumed.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			vaultPath, err := getVaultPath(vault)
+			ctx := cmd.Context()
+			vaultPath, err := getVaultPath(ctx, vault)
 			if err != nil {
-				return output.PrintError("vault_error", err.Error(), nil)
+				return output.PrintErr(ctx, "vault_error", err, nil)
 			}
 
 			notePath := args[0]
 			if strings.TrimSpace(notePath) == "" {
-				return output.PrintError("invalid_input", "Note name cannot be empty", nil)
+				return output.PrintError(ctx, "invalid_input", "Note name cannot be empty", nil)
 			}
 			// Add .md extension if not present
 			if !strings.HasSuffix(strings.ToLower(notePath), ".md") {
@@ -291,22 +315,22 @@ func newReadCmd() *cobra.Command {
 
 			// Prevent path traversal
 			if err := validateInsideVault(vaultPath, fullPath); err != nil {
-				return output.PrintError("invalid_path", "Invalid note path: "+err.Error(), nil)
+				return output.PrintError(ctx, "invalid_path", "Invalid note path: "+err.Error(), nil)
 			}
 
 			// Check if file exists
 			info, err := os.Stat(fullPath)
 			if os.IsNotExist(err) {
-				return output.PrintError("not_found", "Note not found: "+notePath, nil)
+				return output.PrintError(ctx, "not_found", "Note not found: "+notePath, nil)
 			}
 			if err != nil {
-				return output.PrintError("read_error", "Failed to access note: "+err.Error(), nil)
+				return output.PrintError(ctx, "read_error", "Failed to access note: "+err.Error(), nil)
 			}
 
 			// Read content
 			content, err := os.ReadFile(fullPath)
 			if err != nil {
-				return output.PrintError("read_error", "Failed to read note: "+err.Error(), nil)
+				return output.PrintError(ctx, "read_error", "Failed to read note: "+err.Error(), nil)
 			}
 
 			relPath, _ := filepath.Rel(vaultPath, fullPath)
@@ -315,7 +339,7 @@ func newReadCmd() *cobra.Command {
 				folderPath = ""
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"name":     strings.TrimSuffix(filepath.Base(notePath), ".md"),
 				"path":     relPath,
 				"folder":   folderPath,
@@ -342,16 +366,17 @@ func newWriteCmd() *cobra.Command {
 		Long:  `Create a new note or update an existing one. Use --append to add to existing content.`,
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			vaultPath, err := getVaultPath(vault)
+			ctx := cmd.Context()
+			vaultPath, err := getVaultPath(ctx, vault)
 			if err != nil {
-				return output.PrintError("vault_error", err.Error(), nil)
+				return output.PrintErr(ctx, "vault_error", err, nil)
 			}
 
 			notePath := args[0]
 			content := args[1]
 
 			if strings.TrimSpace(notePath) == "" {
-				return output.PrintError("invalid_input", "Note name cannot be empty", nil)
+				return output.PrintError(ctx, "invalid_input", "Note name cannot be empty", nil)
 			}
 
 			// Add .md extension if not present
@@ -363,13 +388,7 @@ func newWriteCmd() *cobra.Command {
 
 			// Prevent path traversal
 			if err := validateInsideVault(vaultPath, fullPath); err != nil {
-				return output.PrintError("invalid_path", "Invalid note path: "+err.Error(), nil)
-			}
-
-			// Ensure parent directory exists
-			parentDir := filepath.Dir(fullPath)
-			if err := os.MkdirAll(parentDir, 0o755); err != nil {
-				return output.PrintError("write_error", "Failed to create directory: "+err.Error(), nil)
+				return output.PrintError(ctx, "invalid_path", "Invalid note path: "+err.Error(), nil)
 			}
 
 			var finalContent string
@@ -382,7 +401,7 @@ func newWriteCmd() *cobra.Command {
 					// Read existing content and append
 					existing, err := os.ReadFile(fullPath)
 					if err != nil {
-						return output.PrintError("read_error", "Failed to read existing note: "+err.Error(), nil)
+						return output.PrintError(ctx, "read_error", "Failed to read existing note: "+err.Error(), nil)
 					}
 					finalContent = string(existing) + "\n" + content
 				} else {
@@ -392,9 +411,19 @@ func newWriteCmd() *cobra.Command {
 				finalContent = content
 			}
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.Record(ctx, dryrun.Request{Protocol: "file", Target: fullPath, Command: "write", Body: finalContent})
+			}
+
+			// Ensure parent directory exists
+			parentDir := filepath.Dir(fullPath)
+			if err := os.MkdirAll(parentDir, 0o755); err != nil {
+				return output.PrintError(ctx, "write_error", "Failed to create directory: "+err.Error(), nil)
+			}
+
 			// Write content
 			if err := os.WriteFile(fullPath, []byte(finalContent), 0o600); err != nil {
-				return output.PrintError("write_error", "Failed to write note: "+err.Error(), nil)
+				return output.PrintError(ctx, "write_error", "Failed to write note: "+err.Error(), nil)
 			}
 
 			relPath, _ := filepath.Rel(vaultPath, fullPath)
@@ -407,7 +436,7 @@ func newWriteCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"status":  "success",
 				"action":  action,
 				"name":    strings.TrimSuffix(filepath.Base(notePath), ".md"),
@@ -437,9 +466,10 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search notes by content",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			vaultPath, err := getVaultPath(vault)
+			ctx := cmd.Context()
+			vaultPath, err := getVaultPath(ctx, vault)
 			if err != nil {
-				return output.PrintError("vault_error", err.Error(), nil)
+				return output.PrintErr(ctx, "vault_error", err, nil)
 			}
 
 			query := args[0]
@@ -529,7 +559,7 @@ func newSearchCmd() *cobra.Command {
 			})
 
 			if err != nil {
-				return output.PrintError("search_error", "Search failed: "+err.Error(), nil)
+				return output.PrintError(ctx, "search_error", "Search failed: "+err.Error(), nil)
 			}
 
 			// Sort by modification time (newest first)
@@ -542,7 +572,7 @@ func newSearchCmd() *cobra.Command {
 				results = results[:limit]
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"results": results,
 				"count":   len(results),
 				"query":   args[0],
@@ -570,14 +600,15 @@ func newDailyCmd() *cobra.Command {
 		Short: "Get or create today's daily note",
 		Long:  `Get today's daily note. Use --create to create if it doesn't exist. Use --offset for other days (-1 for yesterday, 1 for tomorrow).`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			vaultPath, err := getVaultPath(vault)
+			ctx := cmd.Context()
+			vaultPath, err := getVaultPath(ctx, vault)
 			if err != nil {
-				return output.PrintError("vault_error", err.Error(), nil)
+				return output.PrintErr(ctx, "vault_error", err, nil)
 			}
 
 			// Calculate date with offset
 			targetDate := time.Now().AddDate(0, 0, offset)
-			dateFormat := getDailyFormat()
+			dateFormat := getDailyFormat(ctx)
 			noteName := targetDate.Format(dateFormat)
 
 			// Build path
@@ -590,7 +621,7 @@ func newDailyCmd() *cobra.Command {
 
 			// Prevent path traversal
 			if err := validateInsideVault(vaultPath, fullPath); err != nil {
-				return output.PrintError("invalid_path", "Invalid path: "+err.Error(), nil)
+				return output.PrintError(ctx, "invalid_path", "Invalid path: "+err.Error(), nil)
 			}
 
 			// Check if file exists
@@ -598,7 +629,7 @@ func newDailyCmd() *cobra.Command {
 			exists := statErr == nil
 
 			if !exists && !create {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"exists":  false,
 					"name":    noteName,
 					"path":    notePath,
@@ -608,20 +639,23 @@ func newDailyCmd() *cobra.Command {
 			}
 
 			if !exists && create {
+				// Create with default template
+				template := fmt.Sprintf("# %s\n\n", targetDate.Format("Monday, January 2, 2006"))
+				if dryrun.Enabled(ctx) {
+					return dryrun.Record(ctx, dryrun.Request{Protocol: "file", Target: fullPath, Command: "write", Body: template})
+				}
+
 				// Create the daily note
 				parentDir := filepath.Dir(fullPath)
 				if err := os.MkdirAll(parentDir, 0o755); err != nil {
-					return output.PrintError("write_error", "Failed to create directory: "+err.Error(), nil)
+					return output.PrintError(ctx, "write_error", "Failed to create directory: "+err.Error(), nil)
 				}
-
-				// Create with default template
-				template := fmt.Sprintf("# %s\n\n", targetDate.Format("Monday, January 2, 2006"))
 				if err := os.WriteFile(fullPath, []byte(template), 0o600); err != nil {
-					return output.PrintError("write_error", "Failed to create daily note: "+err.Error(), nil)
+					return output.PrintError(ctx, "write_error", "Failed to create daily note: "+err.Error(), nil)
 				}
 
 				info, _ = os.Stat(fullPath)
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"exists":   true,
 					"created":  true,
 					"name":     noteName,
@@ -635,10 +669,10 @@ func newDailyCmd() *cobra.Command {
 			// Read existing note
 			content, err := os.ReadFile(fullPath)
 			if err != nil {
-				return output.PrintError("read_error", "Failed to read daily note: "+err.Error(), nil)
+				return output.PrintError(ctx, "read_error", "Failed to read daily note: "+err.Error(), nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"exists":   true,
 				"created":  false,
 				"name":     noteName,
@@ -669,9 +703,10 @@ func newRecentCmd() *cobra.Command {
 		Use:   "recent",
 		Short: "List recently modified notes",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			vaultPath, err := getVaultPath(vault)
+			ctx := cmd.Context()
+			vaultPath, err := getVaultPath(ctx, vault)
 			if err != nil {
-				return output.PrintError("vault_error", err.Error(), nil)
+				return output.PrintErr(ctx, "vault_error", err, nil)
 			}
 
 			cutoff := time.Now().AddDate(0, 0, -days)
@@ -720,7 +755,7 @@ func newRecentCmd() *cobra.Command {
 			})
 
 			if err != nil {
-				return output.PrintError("walk_error", "Failed to list notes: "+err.Error(), nil)
+				return output.PrintError(ctx, "walk_error", "Failed to list notes: "+err.Error(), nil)
 			}
 
 			// Sort by modification time (newest first)
@@ -733,7 +768,7 @@ func newRecentCmd() *cobra.Command {
 				notes = notes[:limit]
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"notes": notes,
 				"count": len(notes),
 				"vault": vaultPath,
@@ -1,6 +1,7 @@
 package obsidian
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
@@ -26,6 +27,7 @@ func TestNewCmd(t *testing.T) {
 }
 
 func setupTestVault(t *testing.T) (string, func()) {
+	ctx := context.Background()
 	tmpDir, err := os.MkdirTemp("", "obsidian-test-*")
 	if err != nil {
 		t.Fatal(err)
@@ -63,7 +65,7 @@ func setupTestVault(t *testing.T) (string, func()) {
 	tmpConfig.Close()
 
 	os.Setenv("POCKET_CONFIG", tmpConfig.Name())
-	if err := config.Set("obsidian_vault", tmpDir); err != nil {
+	if err := config.Set(ctx, "obsidian_vault", tmpDir); err != nil {
 		t.Fatal(err)
 	}
 
@@ -315,11 +317,12 @@ func TestDaily_NotExisting(t *testing.T) {
 }
 
 func TestDaily_Create(t *testing.T) {
+	ctx := context.Background()
 	vaultPath, cleanup := setupTestVault(t)
 	defer cleanup()
 
 	// Set daily format
-	if err := config.Set("obsidian_daily_format", "2006-01-02"); err != nil {
+	if err := config.Set(ctx, "obsidian_daily_format", "2006-01-02"); err != nil {
 		t.Fatal(err)
 	}
 
@@ -396,6 +399,7 @@ func TestRecent_WithDays(t *testing.T) {
 }
 
 func TestGetVaultPath_NoConfig(t *testing.T) {
+	ctx := context.Background()
 	tmpConfig, err := os.CreateTemp("", "config-*.json")
 	if err != nil {
 		t.Fatal(err)
@@ -406,13 +410,14 @@ func TestGetVaultPath_NoConfig(t *testing.T) {
 	os.Setenv("POCKET_CONFIG", tmpConfig.Name())
 	defer os.Unsetenv("POCKET_CONFIG")
 
-	_, err = getVaultPath("")
+	_, err = getVaultPath(ctx, "")
 	if err == nil {
 		t.Error("expected error for missing vault config, got nil")
 	}
 }
 
 func TestGetVaultPath_InvalidPath(t *testing.T) {
+	ctx := context.Background()
 	tmpConfig, err := os.CreateTemp("", "config-*.json")
 	if err != nil {
 		t.Fatal(err)
@@ -427,11 +432,11 @@ func TestGetVaultPath_InvalidPath(t *testing.T) {
 	os.Setenv("POCKET_CONFIG", tmpConfig.Name())
 	defer os.Unsetenv("POCKET_CONFIG")
 
-	if err := config.Set("obsidian_vault", "/nonexistent/path/to/vault"); err != nil {
+	if err := config.Set(ctx, "obsidian_vault", "/nonexistent/path/to/vault"); err != nil {
 		t.Fatal(err)
 	}
 
-	_, err = getVaultPath("")
+	_, err = getVaultPath(ctx, "")
 	if err == nil {
 		t.Error("expected error for nonexistent vault path, got nil")
 	}
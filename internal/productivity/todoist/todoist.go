@@ -7,16 +7,37 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/paginate"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var apiBaseURL = "https://api.todoist.com/api/v1"
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "todoist",
+		Name:        "Todoist",
+		Group:       "productivity",
+		Description: "Tasks and projects in Todoist",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "todoist_token", Description: "API token", Required: true},
+		},
+		SetupGuide:  "1. Go to https://todoist.com/app/settings/integrations/developer\n2. Copy your API token\n3. Run: pocket config set todoist_token <token>",
+		TestCommand: "pocket productivity todoist projects",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "todoist",
@@ -39,20 +60,20 @@ type todoistClient struct {
 	httpClient *http.Client
 }
 
-func newTodoistClient() (*todoistClient, error) {
-	token, err := config.MustGet("todoist_token")
+func newTodoistClient(ctx context.Context) (*todoistClient, error) {
+	token, err := config.MustGet(ctx, "todoist_token")
 	if err != nil {
 		return nil, err
 	}
 
 	return &todoistClient{
 		token:      token,
-		httpClient: &http.Client{},
+		httpClient: transport.New(0),
 	}, nil
 }
 
-func (c *todoistClient) doRequest(method, endpoint string, body interface{}) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (c *todoistClient) doRequest(ctx context.Context, method, endpoint string, body interface{}) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	var reqBody io.Reader
@@ -135,47 +156,55 @@ func formatTasks(tasks []task) []map[string]any {
 func newTasksCmd() *cobra.Command {
 	var projectID string
 	var filter string
+	var page paginate.Options
 
 	cmd := &cobra.Command{
 		Use:   "tasks",
 		Short: "List tasks",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newTodoistClient()
+			ctx := cmd.Context()
+			client, err := newTodoistClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			var body []byte
-			if filter != "" {
-				// Filters use a separate endpoint in API v1
-				body, err = client.doRequest("GET", "/tasks/filter?query="+filter, nil)
-			} else {
+			return paginate.Run(ctx, page, func(cursor string, size int) (paginate.Page[map[string]any], error) {
+				params := url.Values{}
+				params.Set("limit", strconv.Itoa(size))
+				if cursor != "" {
+					params.Set("cursor", cursor)
+				}
+
 				endpoint := "/tasks"
-				if projectID != "" {
-					endpoint += "?project_id=" + projectID
+				if filter != "" {
+					// Filters use a separate endpoint in API v1
+					endpoint = "/tasks/filter"
+					params.Set("query", filter)
+				} else if projectID != "" {
+					params.Set("project_id", projectID)
 				}
-				body, err = client.doRequest("GET", endpoint, nil)
-			}
-			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
-			}
 
-			var resp struct {
-				Results []task `json:"results"`
-			}
-			if err := json.Unmarshal(body, &resp); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
-			}
+				body, err := client.doRequest(ctx, "GET", endpoint+"?"+params.Encode(), nil)
+				if err != nil {
+					return paginate.Page[map[string]any]{}, output.PrintErr(ctx, "request_failed", err, nil)
+				}
+
+				var resp struct {
+					Results    []task `json:"results"`
+					NextCursor string `json:"next_cursor"`
+				}
+				if err := json.Unmarshal(body, &resp); err != nil {
+					return paginate.Page[map[string]any]{}, output.PrintErr(ctx, "parse_error", err, nil)
+				}
 
-			return output.Print(map[string]any{
-				"count": len(resp.Results),
-				"tasks": formatTasks(resp.Results),
+				return paginate.Page[map[string]any]{Items: formatTasks(resp.Results), Next: resp.NextCursor}, nil
 			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&projectID, "project", "p", "", "Project ID to filter by")
 	cmd.Flags().StringVarP(&filter, "filter", "f", "", "Filter expression (e.g., 'today', 'overdue')")
+	paginate.AddFlags(cmd, &page, 50, 200, "tasks")
 
 	return cmd
 }
@@ -185,14 +214,15 @@ func newProjectsCmd() *cobra.Command {
 		Use:   "projects",
 		Short: "List projects",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newTodoistClient()
+			ctx := cmd.Context()
+			client, err := newTodoistClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			body, err := client.doRequest("GET", "/projects", nil)
+			body, err := client.doRequest(ctx, "GET", "/projects", nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var resp struct {
@@ -209,7 +239,7 @@ func newProjectsCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &resp); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			result := make([]map[string]any, len(resp.Results))
@@ -226,7 +256,7 @@ func newProjectsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"count":    len(resp.Results),
 				"projects": result,
 			})
@@ -248,7 +278,8 @@ func newAddCmd() *cobra.Command {
 		Short: "Add a task",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newTodoistClient()
+			ctx := cmd.Context()
+			client, err := newTodoistClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -273,14 +304,14 @@ func newAddCmd() *cobra.Command {
 				payload["description"] = description
 			}
 
-			body, err := client.doRequest("POST", "/tasks", payload)
+			body, err := client.doRequest(ctx, "POST", "/tasks", payload)
 			if err != nil {
-				return output.PrintError("create_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "create_failed", err, nil)
 			}
 
 			var result task
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			resp := map[string]any{
@@ -292,7 +323,7 @@ func newAddCmd() *cobra.Command {
 				resp["due"] = result.Due.String
 			}
 
-			return output.Print(resp)
+			return output.Print(ctx, resp)
 		},
 	}
 
@@ -311,18 +342,19 @@ func newCompleteCmd() *cobra.Command {
 		Short: "Complete a task",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newTodoistClient()
+			ctx := cmd.Context()
+			client, err := newTodoistClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			endpoint := "/tasks/" + args[0] + "/close"
-			_, err = client.doRequest("POST", endpoint, nil)
+			_, err = client.doRequest(ctx, "POST", endpoint, nil)
 			if err != nil {
-				return output.PrintError("complete_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "complete_failed", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"completed": true,
 				"task_id":   args[0],
 			})
@@ -338,18 +370,19 @@ func newDeleteCmd() *cobra.Command {
 		Short: "Delete a task",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newTodoistClient()
+			ctx := cmd.Context()
+			client, err := newTodoistClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			endpoint := "/tasks/" + args[0]
-			_, err = client.doRequest("DELETE", endpoint, nil)
+			_, err = client.doRequest(ctx, "DELETE", endpoint, nil)
 			if err != nil {
-				return output.PrintError("delete_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "delete_failed", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"deleted": true,
 				"task_id": args[0],
 			})
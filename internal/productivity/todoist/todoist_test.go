@@ -1,6 +1,7 @@
 package todoist
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -97,6 +98,7 @@ func TestFormatTasksNoDue(t *testing.T) {
 }
 
 func TestDoRequest(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check authorization header
 		if r.Header.Get("Authorization") != "Bearer test-token" {
@@ -131,7 +133,7 @@ func TestDoRequest(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	body, err := client.doRequest("GET", "/tasks", nil)
+	body, err := client.doRequest(ctx, "GET", "/tasks", nil)
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -153,6 +155,7 @@ func TestDoRequest(t *testing.T) {
 }
 
 func TestDoRequestPOST(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check method
 		if r.Method != "POST" {
@@ -199,7 +202,7 @@ func TestDoRequestPOST(t *testing.T) {
 		"project_id": "proj1",
 	}
 
-	body, err := client.doRequest("POST", "/tasks", payload)
+	body, err := client.doRequest(ctx, "POST", "/tasks", payload)
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -215,6 +218,7 @@ func TestDoRequestPOST(t *testing.T) {
 }
 
 func TestDoRequestDELETE(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check method
 		if r.Method != "DELETE" {
@@ -235,13 +239,14 @@ func TestDoRequestDELETE(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	_, err := client.doRequest("DELETE", "/tasks/task123", nil)
+	_, err := client.doRequest(ctx, "DELETE", "/tasks/task123", nil)
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
 }
 
 func TestDoRequestError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
 		w.Write([]byte(`{"error": "Invalid token"}`))
@@ -257,13 +262,14 @@ func TestDoRequestError(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	_, err := client.doRequest("GET", "/tasks", nil)
+	_, err := client.doRequest(ctx, "GET", "/tasks", nil)
 	if err == nil {
 		t.Error("expected error for 401 response, got nil")
 	}
 }
 
 func TestProjectsResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -292,7 +298,7 @@ func TestProjectsResponse(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	body, err := client.doRequest("GET", "/projects", nil)
+	body, err := client.doRequest(ctx, "GET", "/projects", nil)
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -12,6 +12,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -87,12 +89,12 @@ func NewClient(apiKey, token string) *Client {
 	return &Client{
 		apiKey: apiKey,
 		token:  token,
-		http:   &http.Client{Timeout: 30 * time.Second},
+		http:   transport.New(30 * time.Second),
 	}
 }
 
-func (c *Client) request(method, endpoint string, params url.Values) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (c *Client) request(ctx context.Context, method, endpoint string, params url.Values) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	if params == nil {
@@ -127,8 +129,8 @@ func (c *Client) request(method, endpoint string, params url.Values) ([]byte, er
 }
 
 // GetBoards returns all boards for the authenticated user
-func (c *Client) GetBoards() ([]Board, error) {
-	data, err := c.request("GET", "/members/me/boards", nil)
+func (c *Client) GetBoards(ctx context.Context) ([]Board, error) {
+	data, err := c.request(ctx, "GET", "/members/me/boards", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -142,8 +144,8 @@ func (c *Client) GetBoards() ([]Board, error) {
 }
 
 // GetBoard returns a single board by ID
-func (c *Client) GetBoard(id string) (*Board, error) {
-	data, err := c.request("GET", "/boards/"+id, nil)
+func (c *Client) GetBoard(ctx context.Context, id string) (*Board, error) {
+	data, err := c.request(ctx, "GET", "/boards/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -157,8 +159,8 @@ func (c *Client) GetBoard(id string) (*Board, error) {
 }
 
 // GetBoardLists returns all lists on a board
-func (c *Client) GetBoardLists(boardID string) ([]List, error) {
-	data, err := c.request("GET", "/boards/"+boardID+"/lists", nil)
+func (c *Client) GetBoardLists(ctx context.Context, boardID string) ([]List, error) {
+	data, err := c.request(ctx, "GET", "/boards/"+boardID+"/lists", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -172,8 +174,8 @@ func (c *Client) GetBoardLists(boardID string) ([]List, error) {
 }
 
 // GetBoardCards returns all cards on a board
-func (c *Client) GetBoardCards(boardID string) ([]Card, error) {
-	data, err := c.request("GET", "/boards/"+boardID+"/cards", nil)
+func (c *Client) GetBoardCards(ctx context.Context, boardID string) ([]Card, error) {
+	data, err := c.request(ctx, "GET", "/boards/"+boardID+"/cards", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -187,8 +189,8 @@ func (c *Client) GetBoardCards(boardID string) ([]Card, error) {
 }
 
 // GetListCards returns all cards in a list
-func (c *Client) GetListCards(listID string) ([]Card, error) {
-	data, err := c.request("GET", "/lists/"+listID+"/cards", nil)
+func (c *Client) GetListCards(ctx context.Context, listID string) ([]Card, error) {
+	data, err := c.request(ctx, "GET", "/lists/"+listID+"/cards", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -202,8 +204,8 @@ func (c *Client) GetListCards(listID string) ([]Card, error) {
 }
 
 // GetCard returns a single card by ID
-func (c *Client) GetCard(id string) (*Card, error) {
-	data, err := c.request("GET", "/cards/"+id, nil)
+func (c *Client) GetCard(ctx context.Context, id string) (*Card, error) {
+	data, err := c.request(ctx, "GET", "/cards/"+id, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -217,8 +219,8 @@ func (c *Client) GetCard(id string) (*Card, error) {
 }
 
 // GetCardLabels returns labels attached to a card
-func (c *Client) GetCardLabels(cardID string) ([]Label, error) {
-	data, err := c.request("GET", "/cards/"+cardID+"/labels", nil)
+func (c *Client) GetCardLabels(ctx context.Context, cardID string) ([]Label, error) {
+	data, err := c.request(ctx, "GET", "/cards/"+cardID+"/labels", nil)
 	if err != nil {
 		return nil, err
 	}
@@ -232,7 +234,7 @@ func (c *Client) GetCardLabels(cardID string) ([]Label, error) {
 }
 
 // CreateCard creates a new card on a list
-func (c *Client) CreateCard(name, listID, description string) (*Card, error) {
+func (c *Client) CreateCard(ctx context.Context, name, listID, description string) (*Card, error) {
 	params := url.Values{}
 	params.Set("name", name)
 	params.Set("idList", listID)
@@ -240,7 +242,7 @@ func (c *Client) CreateCard(name, listID, description string) (*Card, error) {
 		params.Set("desc", description)
 	}
 
-	data, err := c.request("POST", "/cards", params)
+	data, err := c.request(ctx, "POST", "/cards", params)
 	if err != nil {
 		return nil, err
 	}
@@ -253,6 +255,23 @@ func (c *Client) CreateCard(name, listID, description string) (*Card, error) {
 	return &card, nil
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "trello",
+		Name:        "Trello",
+		Group:       "productivity",
+		Description: "Boards, lists, and cards in Trello",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "trello_key", Description: "API Key", Required: true},
+			{Key: "trello_token", Description: "API Token", Required: true},
+		},
+		SetupGuide:  "1. Go to https://trello.com/power-ups/admin\n2. Click \"New\" to create a Power-Up (or use existing)\n3. Copy the API Key\n4. Click \"Token\" link next to the key to generate a token\n5. Authorize and copy the token\n6. Run:\n   pocket config set trello_key <your-api-key>\n   pocket config set trello_token <your-token>",
+		TestCommand: "pocket productivity trello boards",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the trello command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -270,13 +289,13 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func getClient() (*Client, error) {
-	apiKey, err := config.Get("trello_key")
+func getClient(ctx context.Context) (*Client, error) {
+	apiKey, err := config.Get(ctx, "trello_key")
 	if err != nil {
 		return nil, err
 	}
 	if apiKey == "" {
-		return nil, output.PrintError("config_missing", "Trello API key not configured", map[string]any{
+		return nil, output.PrintError(ctx, "config_missing", "Trello API key not configured", map[string]any{
 			"setup_instructions": "To configure Trello:\n" +
 				"1. Go to https://trello.com/power-ups/admin\n" +
 				"2. Click 'New' to create a new Power-Up (or use existing)\n" +
@@ -287,12 +306,12 @@ func getClient() (*Client, error) {
 		})
 	}
 
-	token, err := config.Get("trello_token")
+	token, err := config.Get(ctx, "trello_token")
 	if err != nil {
 		return nil, err
 	}
 	if token == "" {
-		return nil, output.PrintError("config_missing", "Trello token not configured", map[string]any{
+		return nil, output.PrintError(ctx, "config_missing", "Trello token not configured", map[string]any{
 			"setup_instructions": "To configure Trello token:\n" +
 				"1. Go to https://trello.com/power-ups/admin\n" +
 				"2. Select your Power-Up and find your API key\n" +
@@ -310,14 +329,15 @@ func newBoardsCmd() *cobra.Command {
 		Use:   "boards",
 		Short: "List my boards",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			ctx := cmd.Context()
+			client, err := getClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			boards, err := client.GetBoards()
+			boards, err := client.GetBoards(ctx)
 			if err != nil {
-				return output.PrintError("api_error", "Failed to fetch boards", map[string]any{
+				return output.PrintError(ctx, "api_error", "Failed to fetch boards", map[string]any{
 					"error": err.Error(),
 				})
 			}
@@ -330,7 +350,7 @@ func newBoardsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"boards": openBoards,
 				"count":  len(openBoards),
 			})
@@ -346,24 +366,25 @@ func newBoardCmd() *cobra.Command {
 		Short: "Get board details with lists",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			ctx := cmd.Context()
+			client, err := getClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			boardID := args[0]
 
-			board, err := client.GetBoard(boardID)
+			board, err := client.GetBoard(ctx, boardID)
 			if err != nil {
-				return output.PrintError("api_error", "Failed to fetch board", map[string]any{
+				return output.PrintError(ctx, "api_error", "Failed to fetch board", map[string]any{
 					"board_id": boardID,
 					"error":    err.Error(),
 				})
 			}
 
-			lists, err := client.GetBoardLists(boardID)
+			lists, err := client.GetBoardLists(ctx, boardID)
 			if err != nil {
-				return output.PrintError("api_error", "Failed to fetch board lists", map[string]any{
+				return output.PrintError(ctx, "api_error", "Failed to fetch board lists", map[string]any{
 					"board_id": boardID,
 					"error":    err.Error(),
 				})
@@ -377,7 +398,7 @@ func newBoardCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(BoardDetails{
+			return output.Print(ctx, BoardDetails{
 				Board: *board,
 				Lists: openLists,
 			})
@@ -395,7 +416,8 @@ func newCardsCmd() *cobra.Command {
 		Short: "List cards on a board",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			ctx := cmd.Context()
+			client, err := getClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -406,18 +428,18 @@ func newCardsCmd() *cobra.Command {
 
 			if listID != "" {
 				// Fetch cards from specific list
-				cards, err = client.GetListCards(listID)
+				cards, err = client.GetListCards(ctx, listID)
 				if err != nil {
-					return output.PrintError("api_error", "Failed to fetch cards from list", map[string]any{
+					return output.PrintError(ctx, "api_error", "Failed to fetch cards from list", map[string]any{
 						"list_id": listID,
 						"error":   err.Error(),
 					})
 				}
 			} else {
 				// Fetch all cards from board
-				cards, err = client.GetBoardCards(boardID)
+				cards, err = client.GetBoardCards(ctx, boardID)
 				if err != nil {
-					return output.PrintError("api_error", "Failed to fetch cards from board", map[string]any{
+					return output.PrintError(ctx, "api_error", "Failed to fetch cards from board", map[string]any{
 						"board_id": boardID,
 						"error":    err.Error(),
 					})
@@ -432,7 +454,7 @@ func newCardsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"cards":    openCards,
 				"count":    len(openCards),
 				"board_id": boardID,
@@ -452,28 +474,29 @@ func newCardCmd() *cobra.Command {
 		Short: "Get card details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			ctx := cmd.Context()
+			client, err := getClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			cardID := args[0]
 
-			card, err := client.GetCard(cardID)
+			card, err := client.GetCard(ctx, cardID)
 			if err != nil {
-				return output.PrintError("api_error", "Failed to fetch card", map[string]any{
+				return output.PrintError(ctx, "api_error", "Failed to fetch card", map[string]any{
 					"card_id": cardID,
 					"error":   err.Error(),
 				})
 			}
 
-			labels, err := client.GetCardLabels(cardID)
+			labels, err := client.GetCardLabels(ctx, cardID)
 			if err != nil {
 				// Labels are optional, don't fail if we can't get them
 				labels = nil
 			}
 
-			return output.Print(CardDetails{
+			return output.Print(ctx, CardDetails{
 				Card:   *card,
 				Labels: labels,
 			})
@@ -493,7 +516,8 @@ func newCreateCmd() *cobra.Command {
 		Short: "Create a card",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := getClient()
+			ctx := cmd.Context()
+			client, err := getClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -502,9 +526,9 @@ func newCreateCmd() *cobra.Command {
 
 			// If only board is provided, we need to find a list
 			if listID == "" && boardID != "" {
-				lists, err := client.GetBoardLists(boardID)
+				lists, err := client.GetBoardLists(ctx, boardID)
 				if err != nil {
-					return output.PrintError("api_error", "Failed to fetch board lists", map[string]any{
+					return output.PrintError(ctx, "api_error", "Failed to fetch board lists", map[string]any{
 						"board_id": boardID,
 						"error":    err.Error(),
 					})
@@ -519,26 +543,26 @@ func newCreateCmd() *cobra.Command {
 				}
 
 				if listID == "" {
-					return output.PrintError("no_list", "No open lists found on board", map[string]any{
+					return output.PrintError(ctx, "no_list", "No open lists found on board", map[string]any{
 						"board_id": boardID,
 					})
 				}
 			}
 
 			if listID == "" {
-				return output.PrintError("missing_list", "List ID is required (use --list or --board)", map[string]any{
+				return output.PrintError(ctx, "missing_list", "List ID is required (use --list or --board)", map[string]any{
 					"hint": "Specify --list with a list ID, or --board to use the first list on that board",
 				})
 			}
 
-			card, err := client.CreateCard(name, listID, description)
+			card, err := client.CreateCard(ctx, name, listID, description)
 			if err != nil {
-				return output.PrintError("api_error", "Failed to create card", map[string]any{
+				return output.PrintError(ctx, "api_error", "Failed to create card", map[string]any{
 					"error": err.Error(),
 				})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"message": "Card created successfully",
 				"card":    card,
 			})
@@ -1,6 +1,7 @@
 package trello
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -20,6 +21,7 @@ func TestNewCmd(t *testing.T) {
 }
 
 func TestClientRequest(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check query params
 		params := r.URL.Query()
@@ -51,7 +53,7 @@ func TestClientRequest(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	client := NewClient("test-key", "test-token")
-	data, err := client.request("GET", "/boards/me", nil)
+	data, err := client.request(ctx, "GET", "/boards/me", nil)
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
@@ -74,6 +76,7 @@ func TestClientRequest(t *testing.T) {
 }
 
 func TestClientRequestWithParams(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check custom params
 		params := r.URL.Query()
@@ -94,13 +97,14 @@ func TestClientRequestWithParams(t *testing.T) {
 	params := url.Values{}
 	params.Set("filter", "open")
 
-	_, err := client.request("GET", "/boards", params)
+	_, err := client.request(ctx, "GET", "/boards", params)
 	if err != nil {
 		t.Fatalf("request failed: %v", err)
 	}
 }
 
 func TestClientRequestError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
 		w.Write([]byte(`{"error": "invalid key"}`))
@@ -112,13 +116,14 @@ func TestClientRequestError(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	client := NewClient("bad-key", "bad-token")
-	_, err := client.request("GET", "/boards/me", nil)
+	_, err := client.request(ctx, "GET", "/boards/me", nil)
 	if err == nil {
 		t.Error("expected error for 401 response, got nil")
 	}
 }
 
 func TestGetBoards(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/members/me/boards" {
 			t.Errorf("expected path '/members/me/boards', got %q", r.URL.Path)
@@ -145,7 +150,7 @@ func TestGetBoards(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	client := NewClient("test-key", "test-token")
-	boards, err := client.GetBoards()
+	boards, err := client.GetBoards(ctx)
 	if err != nil {
 		t.Fatalf("GetBoards failed: %v", err)
 	}
@@ -160,6 +165,7 @@ func TestGetBoards(t *testing.T) {
 }
 
 func TestGetBoard(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/boards/board123" {
 			t.Errorf("expected path '/boards/board123', got %q", r.URL.Path)
@@ -178,7 +184,7 @@ func TestGetBoard(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	client := NewClient("test-key", "test-token")
-	board, err := client.GetBoard("board123")
+	board, err := client.GetBoard(ctx, "board123")
 	if err != nil {
 		t.Fatalf("GetBoard failed: %v", err)
 	}
@@ -192,6 +198,7 @@ func TestGetBoard(t *testing.T) {
 }
 
 func TestGetBoardLists(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/boards/board123/lists" {
 			t.Errorf("expected path '/boards/board123/lists', got %q", r.URL.Path)
@@ -222,7 +229,7 @@ func TestGetBoardLists(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	client := NewClient("test-key", "test-token")
-	lists, err := client.GetBoardLists("board123")
+	lists, err := client.GetBoardLists(ctx, "board123")
 	if err != nil {
 		t.Fatalf("GetBoardLists failed: %v", err)
 	}
@@ -237,6 +244,7 @@ func TestGetBoardLists(t *testing.T) {
 }
 
 func TestGetBoardCards(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/boards/board123/cards" {
 			t.Errorf("expected path '/boards/board123/cards', got %q", r.URL.Path)
@@ -261,7 +269,7 @@ func TestGetBoardCards(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	client := NewClient("test-key", "test-token")
-	cards, err := client.GetBoardCards("board123")
+	cards, err := client.GetBoardCards(ctx, "board123")
 	if err != nil {
 		t.Fatalf("GetBoardCards failed: %v", err)
 	}
@@ -276,6 +284,7 @@ func TestGetBoardCards(t *testing.T) {
 }
 
 func TestGetListCards(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/lists/list123/cards" {
 			t.Errorf("expected path '/lists/list123/cards', got %q", r.URL.Path)
@@ -297,7 +306,7 @@ func TestGetListCards(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	client := NewClient("test-key", "test-token")
-	cards, err := client.GetListCards("list123")
+	cards, err := client.GetListCards(ctx, "list123")
 	if err != nil {
 		t.Fatalf("GetListCards failed: %v", err)
 	}
@@ -308,6 +317,7 @@ func TestGetListCards(t *testing.T) {
 }
 
 func TestGetCard(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.URL.Path != "/cards/card123" {
 			t.Errorf("expected path '/cards/card123', got %q", r.URL.Path)
@@ -327,7 +337,7 @@ func TestGetCard(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	client := NewClient("test-key", "test-token")
-	card, err := client.GetCard("card123")
+	card, err := client.GetCard(ctx, "card123")
 	if err != nil {
 		t.Fatalf("GetCard failed: %v", err)
 	}
@@ -341,6 +351,7 @@ func TestGetCard(t *testing.T) {
 }
 
 func TestCreateCard(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Errorf("expected POST request, got %s", r.Method)
@@ -376,7 +387,7 @@ func TestCreateCard(t *testing.T) {
 	defer func() { baseURL = oldURL }()
 
 	client := NewClient("test-key", "test-token")
-	card, err := client.CreateCard("New Card", "list123", "Card description")
+	card, err := client.CreateCard(ctx, "New Card", "list123", "Card description")
 	if err != nil {
 		t.Fatalf("CreateCard failed: %v", err)
 	}
@@ -0,0 +1,225 @@
+// Package recipe runs declarative multi-step workflows: a sequence of
+// pocket commands whose arguments are filled in from earlier steps' output.
+package recipe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// maxAttempts caps retry.attempts, so a typo can't hammer an API
+const maxAttempts = 10
+
+// DefaultRetryDelay is the wait before the first retry unless retry.delay is set
+const DefaultRetryDelay = time.Second
+
+// DefaultAs names the current item of a for_each loop unless as is set
+const DefaultAs = "item"
+
+// nested are commands a step can't run: they read stdin, never return or
+// could run the recipe again
+var nested = []string{"run", "batch", "serve", "mcp"}
+
+// reserved names can't be used for a loop's item
+var reserved = []string{"vars", "steps", "index"}
+
+var (
+	validName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// template matches {{ expr }} in a step's arguments
+	template = regexp.MustCompile(`(?s)\{\{(.*?)\}\}`)
+)
+
+// Recipe is a named sequence of steps
+type Recipe struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	// Vars are available to expressions as .vars, and can be overridden
+	// with --set
+	Vars  map[string]any `yaml:"vars"`
+	Steps []Step         `yaml:"steps"`
+}
+
+// Step runs one pocket command, once or for each item of a list
+type Step struct {
+	// ID names the step's output for later steps: .steps.<id>. Defaults
+	// to step<n>, counting from 1.
+	ID string `yaml:"id"`
+	// Cmd is the command line without "pocket"; {{ expr }} in an argument
+	// is replaced with the expression's value
+	Cmd []string `yaml:"cmd"`
+	// If is a condition, written as inside select(...), that must hold for
+	// the step to run. In a loop it is tested for each item.
+	If string `yaml:"if"`
+	// ForEach is an expression giving a list; the step runs once per item
+	ForEach string `yaml:"for_each"`
+	// As names the current item, .item unless set
+	As              string `yaml:"as"`
+	Retry           Retry  `yaml:"retry"`
+	ContinueOnError bool   `yaml:"continue_on_error"`
+}
+
+// Retry reruns a failed step. Delay doubles after each attempt.
+type Retry struct {
+	Attempts int           `yaml:"attempts"`
+	Delay    time.Duration `yaml:"delay"`
+	// Writes retries a step whose command isn't a read. A write that
+	// failed with a network error may still have gone through, so only
+	// set it for commands that are safe to send twice.
+	Writes bool `yaml:"writes"`
+}
+
+// Parse reads a recipe from YAML or JSON and checks it
+func Parse(r io.Reader) (*Recipe, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	// Catches misspelt keys such as foreach, which would otherwise be ignored
+	dec.KnownFields(true)
+
+	var rec Recipe
+	if err := dec.Decode(&rec); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("recipe is empty")
+		}
+		return nil, fmt.Errorf("invalid recipe: %w", err)
+	}
+	if err := rec.validate(); err != nil {
+		return nil, err
+	}
+	return &rec, nil
+}
+
+func (rec *Recipe) validate() error {
+	if len(rec.Steps) == 0 {
+		return errors.New("recipe has no steps")
+	}
+	for name := range rec.Vars {
+		if !validName.MatchString(name) {
+			return fmt.Errorf("invalid var name %q (letters, digits and underscores)", name)
+		}
+	}
+
+	seen := map[string]bool{}
+	for i := range rec.Steps {
+		s := &rec.Steps[i]
+		if s.ID == "" {
+			s.ID = fmt.Sprintf("step%d", i+1)
+		}
+		if err := s.validate(); err != nil {
+			return fmt.Errorf("step %s: %w", s.ID, err)
+		}
+		if seen[s.ID] {
+			return fmt.Errorf("step %s: id is used twice", s.ID)
+		}
+		seen[s.ID] = true
+	}
+	return nil
+}
+
+func (s *Step) validate() error {
+	if !validName.MatchString(s.ID) {
+		return errors.New("invalid id (letters, digits and underscores)")
+	}
+	if len(s.Cmd) == 0 {
+		return errors.New("cmd is empty")
+	}
+	if slices.Contains(nested, s.Cmd[0]) {
+		return fmt.Errorf("pocket %s can't be run from a recipe", s.Cmd[0])
+	}
+	for _, arg := range s.Cmd {
+		for _, m := range template.FindAllStringSubmatch(arg, -1) {
+			if _, err := output.ParseQuery(strings.TrimSpace(m[1])); err != nil {
+				return fmt.Errorf("%s: %w", m[0], err)
+			}
+		}
+	}
+	if s.If != "" {
+		if _, err := output.ParseCondition(s.If); err != nil {
+			return fmt.Errorf("if: %w", err)
+		}
+	}
+
+	if s.ForEach != "" {
+		if _, err := output.ParseQuery(s.ForEach); err != nil {
+			return fmt.Errorf("for_each: %w", err)
+		}
+	}
+	if s.As == "" {
+		s.As = DefaultAs
+	}
+	if !validName.MatchString(s.As) || slices.Contains(reserved, s.As) {
+		return fmt.Errorf("as: %q can't name the loop item", s.As)
+	}
+
+	switch {
+	case s.Retry.Attempts == 0:
+		s.Retry.Attempts = 1
+	case s.Retry.Attempts < 0 || s.Retry.Attempts > maxAttempts:
+		return fmt.Errorf("retry.attempts must be between 1 and %d", maxAttempts)
+	}
+	if s.Retry.Delay == 0 {
+		s.Retry.Delay = DefaultRetryDelay
+	}
+	return nil
+}
+
+// render fills in every {{ expr }} in the step's arguments. An argument
+// before "--" that only starts with "-" once filled in is refused, so a
+// value such as "--repo=other/repo" can't pass for a flag.
+func (s *Step) render(data map[string]any) ([]string, error) {
+	argv := make([]string, len(s.Cmd))
+	positional := false
+	for i, arg := range s.Cmd {
+		var failed error
+		argv[i] = template.ReplaceAllStringFunc(arg, func(m string) string {
+			expr := strings.TrimSpace(template.FindStringSubmatch(m)[1])
+			v, err := output.Eval(expr, data)
+			if err != nil && failed == nil {
+				failed = fmt.Errorf("%s: %w", m, err)
+			}
+			return text(v)
+		})
+		if failed != nil {
+			return nil, failed
+		}
+		if !positional && !strings.HasPrefix(arg, "-") && strings.HasPrefix(argv[i], "-") {
+			return nil, fmt.Errorf("%s gave %q, which would be read as a flag; write it as --flag=%s or after --", arg, argv[i], arg)
+		}
+		positional = positional || arg == "--"
+	}
+	return argv, nil
+}
+
+// text is how a value is put into an argument: strings and numbers as
+// they are, null as nothing, lists and objects as JSON
+func text(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		if val {
+			return "true"
+		}
+		return "false"
+	default:
+		data, _ := json.Marshal(val)
+		return string(data)
+	}
+}
@@ -0,0 +1,317 @@
+package recipe
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/batch"
+	"github.com/unstablemind/pocket/internal/common/testutil"
+	"github.com/unstablemind/pocket/internal/common/transport"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+func TestMain(m *testing.M) {
+	testutil.Main(m, nil)
+}
+
+// newTestRoot has the output flags of the real root and a few commands:
+// issues lists fixed items, echo prints its arguments, flaky fails until
+// it has been called failures times, unavailable and issue-create fail
+// with a server and a network error, and fail always fails
+func newTestRoot(failures int32, calls *atomic.Int32) func() *cobra.Command {
+	return func() *cobra.Command {
+		var format string
+		root := &cobra.Command{
+			Use:           "pocket",
+			SilenceUsage:  true,
+			SilenceErrors: true,
+			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				output.SetFormat(ctx, format)
+				return nil
+			},
+		}
+		root.PersistentFlags().StringVarP(&format, "output", "o", "json", "")
+
+		root.AddCommand(&cobra.Command{
+			Use: "issues",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				return output.Print(ctx, []map[string]any{
+					{"title": "Crash on start", "count": 150},
+					{"title": "Typo", "count": 3},
+					{"title": "Timeout", "count": 120},
+				})
+			},
+		})
+		root.AddCommand(&cobra.Command{
+			Use: "echo",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				return output.Print(ctx, map[string]any{"args": args})
+			},
+		})
+		root.AddCommand(&cobra.Command{
+			Use: "flaky",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				if calls.Add(1) <= failures {
+					return output.PrintError(ctx, "rate_limited", "slow down", nil)
+				}
+				return output.Print(ctx, "done")
+			},
+		})
+		root.AddCommand(&cobra.Command{
+			Use: "unavailable",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				calls.Add(1)
+				return output.PrintErr(ctx, "fetch_failed", &transport.ServerError{Host: "api.github.com", StatusCode: 503}, nil)
+			},
+		})
+		root.AddCommand(&cobra.Command{
+			Use: "issue-create",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				calls.Add(1)
+				return output.PrintErr(ctx, "create_failed", &transport.NetworkError{Host: "api.github.com", Err: errors.New("connection reset")}, nil)
+			},
+		})
+		root.AddCommand(&cobra.Command{
+			Use: "fail",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				return output.PrintError(ctx, "boom", "it broke", nil)
+			},
+		})
+		return root
+	}
+}
+
+func parse(t *testing.T, src string) *Recipe {
+	t.Helper()
+	rec, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name, src string
+		ok        bool
+	}{
+		{"minimal", "steps: [{cmd: [echo]}]", true},
+		{"json", `{"steps": [{"cmd": ["echo", 1]}]}`, true},
+		{"empty", "", false},
+		{"no steps", "name: x", false},
+		{"unknown key", "steps: [{cmd: [echo], foreach: .x}]", false},
+		{"empty cmd", "steps: [{id: a}]", false},
+		{"nested run", "steps: [{cmd: [run, x.yaml]}]", false},
+		{"duplicate id", "steps: [{id: a, cmd: [echo]}, {id: a, cmd: [echo]}]", false},
+		{"default id clash", "steps: [{cmd: [echo]}, {id: step1, cmd: [echo]}]", false},
+		{"dashed id", "steps: [{id: a-b, cmd: [echo]}]", false},
+		{"bad template", "steps: [{cmd: [echo, '{{ .a | }}']}]", false},
+		{"bad condition", "steps: [{cmd: [echo], if: '.a >'}]", false},
+		{"reserved as", "steps: [{cmd: [echo], for_each: .vars.x, as: steps}]", false},
+		{"too many attempts", "steps: [{cmd: [echo], retry: {attempts: 50}}]", false},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(strings.NewReader(tt.src)); (err == nil) != tt.ok {
+			t.Errorf("%s: Parse() = %v", tt.name, err)
+		}
+	}
+
+	rec := parse(t, "steps: [{cmd: [echo]}, {cmd: [echo], for_each: .vars.x}]")
+	if s := rec.Steps[1]; s.ID != "step2" || s.As != DefaultAs || s.Retry.Attempts != 1 || s.Retry.Delay != DefaultRetryDelay {
+		t.Errorf("defaults = %+v", s)
+	}
+}
+
+func TestRun(t *testing.T) {
+	rec := parse(t, `
+name: triage
+vars: {project: OPS}
+steps:
+  - id: issues
+    cmd: [issues]
+  - id: tickets
+    for_each: .steps.issues
+    as: issue
+    if: .issue.count > 100
+    cmd: [echo, --, "{{ .vars.project }}-{{ .index }}", "{{ .issue.title }}"]
+  - id: summary
+    cmd: [echo, "{{ .steps.tickets | length }} tickets", "{{ .steps.tickets[0].args }}"]
+  - id: never
+    if: .vars.project == "DEV"
+    cmd: [fail]
+`)
+	var calls atomic.Int32
+	result, failed := rec.Run(context.Background(), batch.NewRunner(newTestRoot(0, &calls), 1))
+	if failed != nil {
+		t.Fatalf("step %s failed: %+v", failed.ID, failed.Error)
+	}
+
+	got, _ := json.Marshal(result)
+	for _, want := range []string{
+		`"cmd":["echo","--","OPS-0","Crash on start"]`,
+		`{"id":"tickets[1]","status":"skipped"}`,
+		`"cmd":["echo","--","OPS-2","Timeout"]`,
+		`"cmd":["echo","2 tickets","[\"OPS-0\",\"Crash on start\"]"]`,
+		`{"id":"never","status":"skipped"}`,
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("result lacks %s:\n%s", want, got)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var waits []time.Duration
+	orig := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	defer func() { sleep = orig }()
+
+	rec := parse(t, "steps: [{id: flaky, cmd: [flaky], retry: {attempts: 4, delay: 1s}}]")
+	var calls atomic.Int32
+	result, failed := rec.Run(context.Background(), batch.NewRunner(newTestRoot(2, &calls), 1))
+	if failed != nil {
+		t.Fatalf("failed: %+v", failed.Error)
+	}
+	if s := result.Steps[0]; s.Status != StatusOK || s.Attempts != 3 || s.Data != "done" {
+		t.Errorf("flaky = %+v", s)
+	}
+	if len(waits) != 2 || waits[0] != time.Second || waits[1] != 2*time.Second {
+		t.Errorf("waits = %v, want 1s then 2s", waits)
+	}
+
+	// Out of attempts, the step fails and stops the recipe
+	waits = nil
+	calls.Store(0)
+	rec = parse(t, "steps: [{id: flaky, cmd: [flaky], retry: {attempts: 2}}, {cmd: [echo]}]")
+	result, failed = rec.Run(context.Background(), batch.NewRunner(newTestRoot(5, &calls), 1))
+	if failed == nil || failed.ID != "flaky" || failed.Error.Code != "rate_limited" || failed.Attempts != 2 || len(result.Steps) != 1 {
+		t.Errorf("failed = %+v, steps = %+v", failed, result.Steps)
+	}
+
+	// Server errors are retried too
+	calls.Store(0)
+	rec = parse(t, "steps: [{cmd: [unavailable], retry: {attempts: 3}}]")
+	if _, failed = rec.Run(context.Background(), batch.NewRunner(newTestRoot(0, &calls), 1)); failed == nil || failed.Attempts != 3 || calls.Load() != 3 {
+		t.Errorf("HTTP 503: failed = %+v after %d calls", failed, calls.Load())
+	}
+}
+
+func TestNoRetryForWrites(t *testing.T) {
+	orig := sleep
+	sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	defer func() { sleep = orig }()
+
+	// The write may have gone through before the connection dropped
+	var calls atomic.Int32
+	rec := parse(t, "steps: [{cmd: [issue-create], retry: {attempts: 3}}]")
+	if _, failed := rec.Run(context.Background(), batch.NewRunner(newTestRoot(0, &calls), 1)); failed == nil || failed.Attempts != 1 || calls.Load() != 1 {
+		t.Errorf("write: failed = %+v after %d calls", failed, calls.Load())
+	}
+
+	calls.Store(0)
+	rec = parse(t, "steps: [{cmd: [issue-create], retry: {attempts: 3, writes: true}}]")
+	if _, failed := rec.Run(context.Background(), batch.NewRunner(newTestRoot(0, &calls), 1)); failed == nil || failed.Attempts != 3 || calls.Load() != 3 {
+		t.Errorf("writes: true: failed = %+v after %d calls", failed, calls.Load())
+	}
+}
+
+func TestNoRetryForPermanentFailure(t *testing.T) {
+	var waits []time.Duration
+	orig := sleep
+	sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+	defer func() { sleep = orig }()
+
+	// boom would fail the same way again, so it isn't retried
+	rec := parse(t, "steps: [{id: broken, cmd: [fail], retry: {attempts: 4}}]")
+	var calls atomic.Int32
+	_, failed := rec.Run(context.Background(), batch.NewRunner(newTestRoot(0, &calls), 1))
+	if failed == nil || failed.Error.Code != "boom" || failed.Attempts != 1 || len(waits) != 0 {
+		t.Errorf("failed = %+v, waits = %v", failed, waits)
+	}
+}
+
+func TestRenderedFlag(t *testing.T) {
+	rec := parse(t, `steps: [{cmd: [echo, "{{ .vars.title }}"]}]`)
+	rec.Vars = map[string]any{"title": "--repo=other/repo"}
+	var calls atomic.Int32
+	_, failed := rec.Run(context.Background(), batch.NewRunner(newTestRoot(0, &calls), 1))
+	if failed == nil || failed.Error.Code != "invalid_recipe" {
+		t.Errorf("failed = %+v", failed)
+	}
+
+	// As a flag's value or after --, it is only ever a value
+	rec = parse(t, `steps: [{cmd: [echo, "-o={{ .vars.format }}", --, "{{ .vars.title }}"]}]`)
+	rec.Vars = map[string]any{"title": "--repo=other/repo", "format": "json"}
+	result, failed := rec.Run(context.Background(), batch.NewRunner(newTestRoot(0, &calls), 1))
+	if failed != nil || result.Steps[0].Cmd[3] != "--repo=other/repo" {
+		t.Errorf("failed = %+v, steps = %+v", failed, result.Steps)
+	}
+}
+
+func TestContinueOnError(t *testing.T) {
+	rec := parse(t, `
+steps:
+  - id: each
+    for_each: '[.vars.words[]]'
+    continue_on_error: true
+    cmd: ["{{ .item }}"]
+  - id: after
+    cmd: [echo, "{{ .steps.each | length }}"]
+  - id: stop
+    for_each: .vars.words
+    cmd: ["{{ .item }}"]
+  - cmd: [echo]
+`)
+	rec.Vars = map[string]any{"words": []any{"echo", "fail", "run"}}
+	var calls atomic.Int32
+	result, failed := rec.Run(context.Background(), batch.NewRunner(newTestRoot(0, &calls), 1))
+
+	if s := result.Steps[0]; s.Status != StatusFailed || len(s.Runs) != 3 || s.Runs[2].Error.Code != "invalid_recipe" {
+		t.Errorf("each = %+v", s)
+	}
+	if s := result.Steps[1]; s.Status != StatusOK || s.Cmd[1] != "1" {
+		t.Errorf("after = %+v", s)
+	}
+	if failed == nil || failed.ID != "stop[1]" || failed.Error.Code != "boom" || len(result.Steps) != 3 || len(result.Steps[2].Runs) != 2 {
+		t.Errorf("failed = %+v, steps = %+v", failed, result.Steps)
+	}
+}
+
+func TestForEachNeedsList(t *testing.T) {
+	rec := parse(t, "vars: {n: 3}\nsteps: [{cmd: [echo], for_each: .vars.n}]")
+	var calls atomic.Int32
+	_, failed := rec.Run(context.Background(), batch.NewRunner(newTestRoot(0, &calls), 1))
+	if failed == nil || failed.Error.Code != "invalid_recipe" {
+		t.Errorf("failed = %+v", failed)
+	}
+}
+
+func TestRunCmdReadsCommandInput(t *testing.T) {
+	var calls atomic.Int32
+	cmd := NewCmd(newTestRoot(0, &calls))
+	cmd.SetIn(strings.NewReader("steps: [{id: hi, cmd: [echo, hi]}]"))
+	resp := testutil.Run(t, cmd, "-")
+	if got, _ := json.Marshal(resp.Data); !resp.Success || !strings.Contains(string(got), `"cmd":["echo","hi"]`) {
+		t.Errorf("run - = %+v", resp)
+	}
+}
@@ -0,0 +1,284 @@
+package recipe
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/batch"
+	"github.com/unstablemind/pocket/internal/common/policy"
+	"github.com/unstablemind/pocket/internal/common/transport"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// Step statuses
+const (
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+)
+
+// Result is what pocket run prints
+type Result struct {
+	Recipe string       `json:"recipe,omitempty"`
+	DryRun bool         `json:"dry_run,omitempty"`
+	Steps  []StepResult `json:"steps"`
+}
+
+// StepResult is how a step, or one item of a loop, went
+type StepResult struct {
+	ID       string        `json:"id"`
+	Status   string        `json:"status"`
+	Cmd      []string      `json:"cmd,omitempty"`
+	Attempts int           `json:"attempts,omitempty"`
+	Data     any           `json:"data,omitempty"`
+	Error    *output.Error `json:"error,omitempty"`
+	// Runs are the items of a for_each loop
+	Runs []StepResult `json:"runs,omitempty"`
+}
+
+// transientCodes are the error codes of failures that may clear up by
+// themselves, so are worth retrying
+var transientCodes = []string{transport.ErrorCode, transport.NetworkErrorCode, transport.ServerErrorCode, "timeout"}
+
+// sleep waits between attempts; replaced in tests
+var sleep = func(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func NewCmd(newRoot func() *cobra.Command) *cobra.Command {
+	var set []string
+
+	cmd := &cobra.Command{
+		Use:   "run [recipe.yaml]",
+		Short: "Run a multi-step recipe of pocket commands",
+		Long: `Run a recipe: pocket commands run in order, each able to use earlier steps' output. The recipe is read from a YAML or JSON file, or stdin when it is "-".
+
+  name: sentry-to-jira
+  vars: {project: OPS}
+  steps:
+    - id: issues
+      cmd: [dev, sentry, issues, --org, acme]
+      retry: {attempts: 3, delay: 2s}
+    - id: tickets
+      for_each: .steps.issues
+      as: issue
+      if: .issue.count > 100
+      cmd: [dev, jira, create, "--project={{ .vars.project }}", "--summary={{ .issue.title }}"]
+    - cmd: [comms, slack, send, "#ops", "Opened {{ .steps.tickets | length }} tickets"]
+
+{{ expr }} in an argument is replaced with the value of a --query expression evaluated over .vars, .steps.<id> (a step's data; a list of them for loops) and, in loops, the current item (.item unless as names it) and .index. A value that would start an argument with "-" must be given as --flag={{ expr }} or after --, so it can't be read as a flag. if takes a condition as written inside select(...). A read step failing with a rate limit, network error, timeout or server error is retried until it has run retry.attempts times, waiting retry.delay and then twice as long each time; other failures are not retried. Steps that write may have gone through before failing, so they are only retried with retry.writes set. A failed step stops the recipe unless continue_on_error is set.
+
+With --dry-run, read steps run and write steps show the requests they would send, so the output is a plan of the whole recipe.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			in := cmd.InOrStdin()
+			if args[0] != "-" {
+				f, err := os.Open(args[0])
+				if err != nil {
+					return output.PrintErr(ctx, "read_failed", err, nil)
+				}
+				defer f.Close()
+				in = f
+			}
+			rec, err := Parse(in)
+			if err != nil {
+				return output.PrintErr(ctx, "invalid_recipe", err, nil)
+			}
+			for _, kv := range set {
+				name, value, ok := strings.Cut(kv, "=")
+				if !ok || !validName.MatchString(name) {
+					return output.PrintError(ctx, "invalid_input", "--set takes name=value, got "+kv, nil)
+				}
+				if rec.Vars == nil {
+					rec.Vars = map[string]any{}
+				}
+				rec.Vars[name] = value
+			}
+
+			runner := batch.NewRunner(newRoot, 1)
+			runner.Inherit(cmd)
+			dryRun := false
+			if f := cmd.Flags().Lookup("dry-run"); f != nil {
+				dryRun = f.Value.String() == "true"
+			}
+
+			result, failed := rec.Run(cmd.Context(), runner)
+			result.DryRun = dryRun
+			if failed != nil {
+				return output.PrintError(ctx, "step_failed", fmt.Sprintf("step %s failed: %s", failed.ID, failed.Error.Message), map[string]any{
+					"step":  failed.ID,
+					"error": failed.Error,
+					"steps": result.Steps,
+				})
+			}
+			return output.Print(ctx, result)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&set, "set", nil, "Set a recipe var, as name=value (repeatable)")
+
+	return cmd
+}
+
+// Run runs the recipe's steps in order with runner. It stops at the first
+// step that fails without continue_on_error and also returns that step's
+// result; for a loop, the result of the item that failed.
+func (rec *Recipe) Run(ctx context.Context, runner *batch.Runner) (*Result, *StepResult) {
+	result := &Result{Recipe: rec.Name, Steps: make([]StepResult, 0, len(rec.Steps))}
+	vars := rec.Vars
+	if vars == nil {
+		vars = map[string]any{}
+	}
+	steps := map[string]any{}
+
+	for i := range rec.Steps {
+		s := &rec.Steps[i]
+		var res StepResult
+		var failed *StepResult
+		if s.ForEach != "" {
+			res, failed = s.loop(ctx, runner, vars, steps)
+		} else {
+			res = s.once(ctx, runner, map[string]any{"vars": vars, "steps": steps}, s.ID)
+			if res.Status == StatusFailed {
+				failed = &res
+			}
+		}
+		result.Steps = append(result.Steps, res)
+
+		steps[s.ID] = res.Data
+		if failed != nil && !s.ContinueOnError {
+			return result, failed
+		}
+	}
+	return result, nil
+}
+
+// loop runs the step for each item for_each gives. Its data is the list of
+// the data of the items that ran.
+func (s *Step) loop(ctx context.Context, runner *batch.Runner, vars, steps map[string]any) (StepResult, *StepResult) {
+	res := StepResult{ID: s.ID, Status: StatusOK, Runs: []StepResult{}}
+	list, err := output.Eval(s.ForEach, map[string]any{"vars": vars, "steps": steps})
+	if err == nil && list != nil {
+		if _, ok := list.([]any); !ok {
+			err = fmt.Errorf("for_each gave %s, not a list", text(list))
+		}
+	}
+	if err != nil {
+		res.Status, res.Error = StatusFailed, &output.Error{Code: "invalid_recipe", Message: "for_each: " + err.Error()}
+		return res, &res
+	}
+
+	items, _ := list.([]any)
+	data := make([]any, 0, len(items))
+	for i, item := range items {
+		scope := map[string]any{"vars": vars, "steps": steps, s.As: item, "index": i}
+		run := s.once(ctx, runner, scope, fmt.Sprintf("%s[%d]", s.ID, i))
+		res.Runs = append(res.Runs, run)
+		switch run.Status {
+		case StatusOK:
+			data = append(data, run.Data)
+		case StatusFailed:
+			res.Status = StatusFailed
+			if !s.ContinueOnError {
+				res.Data = data
+				return res, &res.Runs[len(res.Runs)-1]
+			}
+		}
+	}
+	res.Data = data
+	if res.Status == StatusFailed {
+		return res, &res
+	}
+	return res, nil
+}
+
+// once runs the step for one set of values, retrying transient failures
+func (s *Step) once(ctx context.Context, runner *batch.Runner, scope map[string]any, id string) StepResult {
+	res := StepResult{ID: id}
+	if s.If != "" {
+		ok, err := output.Test(s.If, scope)
+		if err != nil {
+			res.Status, res.Error = StatusFailed, &output.Error{Code: "invalid_recipe", Message: "if: " + err.Error()}
+			return res
+		}
+		if !ok {
+			res.Status = StatusSkipped
+			return res
+		}
+	}
+
+	argv, err := s.render(scope)
+	if err == nil && slices.Contains(nested, argv[0]) {
+		err = fmt.Errorf("pocket %s can't be run from a recipe", argv[0])
+	}
+	if err != nil {
+		res.Status, res.Error = StatusFailed, &output.Error{Code: "invalid_recipe", Message: err.Error()}
+		return res
+	}
+	res.Cmd = argv
+
+	attempts := s.Retry.Attempts
+	if attempts > 1 && !s.Retry.Writes && !reads(runner, argv) {
+		attempts = 1
+	}
+	delay := s.Retry.Delay
+	for res.Attempts = 1; ; res.Attempts++ {
+		resp := decode(runner.Exec(argv))
+		if resp.Success {
+			res.Status, res.Data, res.Error = StatusOK, resp.Data, nil
+			return res
+		}
+		res.Status, res.Error = StatusFailed, resp.Error
+		if res.Attempts >= attempts || !transient(resp.Error) {
+			return res
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return res
+		}
+		delay *= 2
+	}
+}
+
+// transient reports whether a failure may clear up on its own: a rate
+// limit, a network error or timeout, or a 5xx from the API. Others, such
+// as not_found or policy_denied, would fail the same way again.
+func transient(e *output.Error) bool {
+	return slices.Contains(transientCodes, e.Code)
+}
+
+// reads reports whether argv runs a command the policy classes as a read,
+// which is safe to send again
+func reads(runner *batch.Runner, argv []string) bool {
+	cmd, err := runner.Command(argv)
+	return err == nil && policy.ClassOf(cmd) == policy.ClassRead
+}
+
+// decode reads a command's response envelope, keeping numbers exact
+func decode(envelope []byte) output.Response {
+	var resp output.Response
+	dec := json.NewDecoder(bytes.NewReader(envelope))
+	dec.UseNumber()
+	if err := dec.Decode(&resp); err != nil {
+		return output.Response{Error: &output.Error{Code: "command_failed", Message: "unreadable response: " + err.Error()}}
+	}
+	if !resp.Success && resp.Error == nil {
+		resp.Error = &output.Error{Code: "command_failed", Message: "command failed"}
+	}
+	return resp
+}
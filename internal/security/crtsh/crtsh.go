@@ -11,12 +11,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var (
 	baseURL    = "https://crt.sh"
-	httpClient = &http.Client{Timeout: 30 * time.Second}
+	httpClient = transport.New(30 * time.Second)
 )
 
 // CertEntry represents a certificate transparency log entry
@@ -31,6 +33,16 @@ type CertEntry struct {
 	SerialNumber string `json:"serial_number"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "crtsh",
+		Name:        "crt.sh",
+		Group:       "security",
+		Description: "Certificate transparency log lookups via crt.sh",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "crtsh",
@@ -54,9 +66,10 @@ func newLookupCmd() *cobra.Command {
 		Long:  "Query Certificate Transparency logs via crt.sh for certificates issued for a domain.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			domain := args[0]
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			params := url.Values{}
@@ -69,23 +82,23 @@ func newLookupCmd() *cobra.Command {
 			reqURL := fmt.Sprintf("%s/?%s", baseURL, params.Encode())
 			req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("request_error", fmt.Sprintf("failed to create request: %s", err.Error()), nil)
+				return output.PrintError(ctx, "request_error", fmt.Sprintf("failed to create request: %s", err.Error()), nil)
 			}
 			req.Header.Set("User-Agent", "Pocket-CLI/1.0")
 
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				return output.PrintError("request_failed", fmt.Sprintf("request failed: %s", err.Error()), nil)
+				return output.PrintErr(ctx, "request_failed", fmt.Errorf("request failed: %w", err), nil)
 			}
 			defer resp.Body.Close()
 
 			body, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("read_error", fmt.Sprintf("failed to read response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "read_error", fmt.Sprintf("failed to read response: %s", err.Error()), nil)
 			}
 
 			if resp.StatusCode >= 400 {
-				return output.PrintError("api_error", fmt.Sprintf("crt.sh error (HTTP %d): %s", resp.StatusCode, string(body)), nil)
+				return output.PrintError(ctx, "api_error", fmt.Sprintf("crt.sh error (HTTP %d): %s", resp.StatusCode, string(body)), nil)
 			}
 
 			var rawEntries []struct {
@@ -100,7 +113,7 @@ func newLookupCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &rawEntries); err != nil {
-				return output.PrintError("parse_error", "failed to parse crt.sh response", nil)
+				return output.PrintError(ctx, "parse_error", "failed to parse crt.sh response", nil)
 			}
 
 			// Apply limit
@@ -122,7 +135,7 @@ func newLookupCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(results)
+			return output.Print(ctx, results)
 		},
 	}
 
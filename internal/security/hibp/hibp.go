@@ -14,11 +14,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var (
-	httpClient      = &http.Client{Timeout: 30 * time.Second}
+	httpClient      = transport.New(30 * time.Second)
 	passwordBaseURL = "https://api.pwnedpasswords.com"
 	breachesBaseURL = "https://haveibeenpwned.com/api/v3"
 )
@@ -42,6 +44,16 @@ type Breach struct {
 	IsVerified  bool     `json:"is_verified"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "hibp",
+		Name:        "Have I Been Pwned",
+		Group:       "security",
+		Description: "Check passwords against breaches and list public data breaches",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "hibp",
@@ -63,6 +75,7 @@ func newPasswordCmd() *cobra.Command {
 		Long:  "Uses k-anonymity to safely check if a password appears in known data breaches without sending the full password to the API.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			password := args[0]
 
 			// SHA1 hash the password
@@ -74,29 +87,29 @@ func newPasswordCmd() *cobra.Command {
 			prefix := hash[:5]
 			suffix := hash[5:]
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			reqURL := fmt.Sprintf("%s/range/%s", passwordBaseURL, prefix)
 			req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("request_error", fmt.Sprintf("failed to create request: %s", err.Error()), nil)
+				return output.PrintError(ctx, "request_error", fmt.Sprintf("failed to create request: %s", err.Error()), nil)
 			}
 			req.Header.Set("User-Agent", "Pocket-CLI/1.0")
 
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				return output.PrintError("request_failed", fmt.Sprintf("request failed: %s", err.Error()), nil)
+				return output.PrintErr(ctx, "request_failed", fmt.Errorf("request failed: %w", err), nil)
 			}
 			defer resp.Body.Close()
 
 			body, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("read_error", fmt.Sprintf("failed to read response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "read_error", fmt.Sprintf("failed to read response: %s", err.Error()), nil)
 			}
 
 			if resp.StatusCode >= 400 {
-				return output.PrintError("api_error", fmt.Sprintf("HIBP API error (HTTP %d): %s", resp.StatusCode, string(body)), nil)
+				return output.PrintError(ctx, "api_error", fmt.Sprintf("HIBP API error (HTTP %d): %s", resp.StatusCode, string(body)), nil)
 			}
 
 			// Parse response: each line is SUFFIX:COUNT
@@ -119,7 +132,7 @@ func newPasswordCmd() *cobra.Command {
 						TimesExposed: count,
 						Message:      "This password has been exposed in data breaches",
 					}
-					return output.Print(result)
+					return output.Print(ctx, result)
 				}
 			}
 
@@ -128,7 +141,7 @@ func newPasswordCmd() *cobra.Command {
 				TimesExposed: 0,
 				Message:      "This password was not found in known data breaches",
 			}
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -143,29 +156,30 @@ func newBreachesCmd() *cobra.Command {
 		Short: "List recent public data breaches",
 		Long:  "Retrieve a list of all public data breaches from Have I Been Pwned.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx := cmd.Context()
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			reqURL := breachesBaseURL + "/breaches"
 			req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("request_error", fmt.Sprintf("failed to create request: %s", err.Error()), nil)
+				return output.PrintError(ctx, "request_error", fmt.Sprintf("failed to create request: %s", err.Error()), nil)
 			}
 			req.Header.Set("User-Agent", "Pocket-CLI/1.0")
 
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				return output.PrintError("request_failed", fmt.Sprintf("request failed: %s", err.Error()), nil)
+				return output.PrintErr(ctx, "request_failed", fmt.Errorf("request failed: %w", err), nil)
 			}
 			defer resp.Body.Close()
 
 			body, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("read_error", fmt.Sprintf("failed to read response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "read_error", fmt.Sprintf("failed to read response: %s", err.Error()), nil)
 			}
 
 			if resp.StatusCode >= 400 {
-				return output.PrintError("api_error", fmt.Sprintf("HIBP API error (HTTP %d): %s", resp.StatusCode, string(body)), nil)
+				return output.PrintError(ctx, "api_error", fmt.Sprintf("HIBP API error (HTTP %d): %s", resp.StatusCode, string(body)), nil)
 			}
 
 			var rawBreaches []struct {
@@ -180,7 +194,7 @@ func newBreachesCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &rawBreaches); err != nil {
-				return output.PrintError("parse_error", "failed to parse breaches response", nil)
+				return output.PrintError(ctx, "parse_error", "failed to parse breaches response", nil)
 			}
 
 			// Apply limit
@@ -206,7 +220,7 @@ func newBreachesCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(results)
+			return output.Print(ctx, results)
 		},
 	}
 
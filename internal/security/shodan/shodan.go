@@ -11,12 +11,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var (
 	baseURL    = "https://internetdb.shodan.io"
-	httpClient = &http.Client{Timeout: 30 * time.Second}
+	httpClient = transport.New(30 * time.Second)
 )
 
 // Result represents the InternetDB lookup result for an IP
@@ -29,6 +31,16 @@ type Result struct {
 	Vulns     []string `json:"vulns"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "shodan",
+		Name:        "Shodan",
+		Group:       "security",
+		Description: "IP lookup for open ports and vulnerabilities via Shodan",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "shodan",
@@ -49,40 +61,41 @@ func newLookupCmd() *cobra.Command {
 		Long:  "Query the Shodan InternetDB for open ports, hostnames, CPEs, tags, and vulnerabilities associated with an IP address.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			ip := args[0]
 
 			// Validate IP address
 			if net.ParseIP(ip) == nil {
-				return output.PrintError("invalid_ip", fmt.Sprintf("'%s' is not a valid IP address", ip), nil)
+				return output.PrintError(ctx, "invalid_ip", fmt.Sprintf("'%s' is not a valid IP address", ip), nil)
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			reqURL := fmt.Sprintf("%s/%s", baseURL, ip)
 			req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 			if err != nil {
-				return output.PrintError("request_error", fmt.Sprintf("failed to create request: %s", err.Error()), nil)
+				return output.PrintError(ctx, "request_error", fmt.Sprintf("failed to create request: %s", err.Error()), nil)
 			}
 			req.Header.Set("User-Agent", "Pocket-CLI/1.0")
 
 			resp, err := httpClient.Do(req)
 			if err != nil {
-				return output.PrintError("request_failed", fmt.Sprintf("request failed: %s", err.Error()), nil)
+				return output.PrintErr(ctx, "request_failed", fmt.Errorf("request failed: %w", err), nil)
 			}
 			defer resp.Body.Close()
 
 			body, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("read_error", fmt.Sprintf("failed to read response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "read_error", fmt.Sprintf("failed to read response: %s", err.Error()), nil)
 			}
 
 			if resp.StatusCode == 404 {
-				return output.PrintError("not_found", fmt.Sprintf("no information found for IP %s", ip), nil)
+				return output.PrintError(ctx, "not_found", fmt.Sprintf("no information found for IP %s", ip), nil)
 			}
 
 			if resp.StatusCode >= 400 {
-				return output.PrintError("api_error", fmt.Sprintf("Shodan API error (HTTP %d): %s", resp.StatusCode, string(body)), nil)
+				return output.PrintError(ctx, "api_error", fmt.Sprintf("Shodan API error (HTTP %d): %s", resp.StatusCode, string(body)), nil)
 			}
 
 			var raw struct {
@@ -95,7 +108,7 @@ func newLookupCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &raw); err != nil {
-				return output.PrintError("parse_error", "failed to parse Shodan response", nil)
+				return output.PrintError(ctx, "parse_error", "failed to parse Shodan response", nil)
 			}
 
 			// Ensure slices are non-nil for clean JSON output
@@ -129,7 +142,7 @@ func newLookupCmd() *cobra.Command {
 				Vulns:     vulns,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -13,12 +13,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var (
 	baseURL    = "https://www.virustotal.com/api/v3"
-	httpClient = &http.Client{Timeout: 30 * time.Second}
+	httpClient = transport.New(30 * time.Second)
 )
 
 // URLScanResult represents the result of a URL scan
@@ -74,6 +76,22 @@ type HashReport struct {
 	LastAnalysis string `json:"last_analysis"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "virustotal",
+		Name:        "VirusTotal",
+		Group:       "security",
+		Description: "Scan URLs, domains, IPs, and file hashes for threats via VirusTotal",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "virustotal_api_key", Description: "API key for VirusTotal", Required: true},
+		},
+		SetupGuide:  "1. Sign up at https://www.virustotal.com/gui/join-us\n2. Go to https://www.virustotal.com/gui/my-apikey\n3. Copy your API key\n4. Run: pocket config set virustotal_api_key <your-api-key>\n\nNote: Free tier allows 4 lookups/min, 500/day.",
+		TestCommand: "pocket security virustotal domain example.com",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "virustotal",
@@ -90,8 +108,8 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func getAPIKey() (string, error) {
-	key, err := config.Get("virustotal_api_key")
+func getAPIKey(ctx context.Context) (string, error) {
+	key, err := config.Get(ctx, "virustotal_api_key")
 	if err != nil {
 		return "", fmt.Errorf("VirusTotal API key not configured. Set it with: pocket config set virustotal_api_key <key>")
 	}
@@ -102,7 +120,7 @@ func getAPIKey() (string, error) {
 }
 
 func doRequest(ctx context.Context, method, endpoint string, body io.Reader, contentType string) ([]byte, error) {
-	apiKey, err := getAPIKey()
+	apiKey, err := getAPIKey(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -152,10 +170,11 @@ func newURLCmd() *cobra.Command {
 		Long:  "Submit a URL to VirusTotal for scanning and retrieve the analysis results.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			targetURL := args[0]
 
 			// Step 1: Submit URL for scanning
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			formData := url.Values{}
@@ -163,7 +182,7 @@ func newURLCmd() *cobra.Command {
 
 			respBody, err := doRequest(ctx, "POST", baseURL+"/urls", strings.NewReader(formData.Encode()), "application/x-www-form-urlencoded")
 			if err != nil {
-				return output.PrintError("scan_submit_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "scan_submit_failed", err, nil)
 			}
 
 			var submitResp struct {
@@ -173,12 +192,12 @@ func newURLCmd() *cobra.Command {
 				} `json:"data"`
 			}
 			if err := json.Unmarshal(respBody, &submitResp); err != nil {
-				return output.PrintError("parse_error", "failed to parse scan submission response", nil)
+				return output.PrintError(ctx, "parse_error", "failed to parse scan submission response", nil)
 			}
 
 			analysisID := submitResp.Data.ID
 			if analysisID == "" {
-				return output.PrintError("scan_error", "no analysis ID returned", nil)
+				return output.PrintError(ctx, "scan_error", "no analysis ID returned", nil)
 			}
 
 			// Step 2: Poll for results
@@ -193,7 +212,7 @@ func newURLCmd() *cobra.Command {
 			for attempt := 0; attempt < 10; attempt++ {
 				time.Sleep(3 * time.Second)
 
-				pollCtx, pollCancel := context.WithTimeout(context.Background(), 30*time.Second)
+				pollCtx, pollCancel := context.WithTimeout(ctx, 30*time.Second)
 				pollBody, pollErr := doRequest(pollCtx, "GET", baseURL+"/analyses/"+analysisID, nil, "") //nolint:misspell // correct API path
 				pollCancel()
 
@@ -232,11 +251,11 @@ func newURLCmd() *cobra.Command {
 						TotalEngines: stats.Malicious + stats.Suspicious + stats.Harmless + stats.Undetected + stats.Timeout,
 						Permalink:    fmt.Sprintf("https://www.virustotal.com/gui/url/%s", analysisID),
 					}
-					return output.Print(result)
+					return output.Print(ctx, result)
 				}
 			}
 
-			return output.PrintError("scan_timeout", "analysis did not complete within the polling window", map[string]string{
+			return output.PrintError(ctx, "scan_timeout", "analysis did not complete within the polling window", map[string]string{
 				"analysis_id": analysisID,
 				"url":         targetURL,
 			})
@@ -253,14 +272,15 @@ func newDomainCmd() *cobra.Command {
 		Long:  "Retrieve a VirusTotal analysis report for a domain name.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			domain := args[0]
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			respBody, err := doRequest(ctx, "GET", baseURL+"/domains/"+domain, nil, "")
 			if err != nil {
-				return output.PrintError("domain_lookup_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "domain_lookup_failed", err, nil)
 			}
 
 			var resp struct {
@@ -280,7 +300,7 @@ func newDomainCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(respBody, &resp); err != nil {
-				return output.PrintError("parse_error", "failed to parse domain report", nil)
+				return output.PrintError(ctx, "parse_error", "failed to parse domain report", nil)
 			}
 
 			attrs := resp.Data.Attributes
@@ -305,7 +325,7 @@ func newDomainCmd() *cobra.Command {
 				LastAnalysis: lastAnalysis,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -319,14 +339,15 @@ func newIPCmd() *cobra.Command {
 		Long:  "Retrieve a VirusTotal analysis report for an IP address.",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			ip := args[0]
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			respBody, err := doRequest(ctx, "GET", baseURL+"/ip_addresses/"+ip, nil, "")
 			if err != nil {
-				return output.PrintError("ip_lookup_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "ip_lookup_failed", err, nil)
 			}
 
 			var resp struct {
@@ -347,7 +368,7 @@ func newIPCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(respBody, &resp); err != nil {
-				return output.PrintError("parse_error", "failed to parse IP report", nil)
+				return output.PrintError(ctx, "parse_error", "failed to parse IP report", nil)
 			}
 
 			attrs := resp.Data.Attributes
@@ -368,7 +389,7 @@ func newIPCmd() *cobra.Command {
 				LastAnalysis: lastAnalysis,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -382,14 +403,15 @@ func newHashCmd() *cobra.Command {
 		Long:  "Retrieve a VirusTotal analysis report for a file hash (MD5, SHA1, or SHA256).",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			hash := args[0]
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			respBody, err := doRequest(ctx, "GET", baseURL+"/files/"+hash, nil, "")
 			if err != nil {
-				return output.PrintError("hash_lookup_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "hash_lookup_failed", err, nil)
 			}
 
 			var resp struct {
@@ -413,7 +435,7 @@ func newHashCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(respBody, &resp); err != nil {
-				return output.PrintError("parse_error", "failed to parse hash report", nil)
+				return output.PrintError(ctx, "parse_error", "failed to parse hash report", nil)
 			}
 
 			attrs := resp.Data.Attributes
@@ -441,7 +463,7 @@ func newHashCmd() *cobra.Command {
 				LastAnalysis: lastAnalysis,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
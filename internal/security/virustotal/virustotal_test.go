@@ -1,6 +1,7 @@
 package virustotal
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -27,6 +28,7 @@ func TestNewCmd(t *testing.T) {
 }
 
 func setupTestConfig(t *testing.T) func() {
+	ctx := context.Background()
 	tmpFile, err := os.CreateTemp("", "vt-config-*.json")
 	if err != nil {
 		t.Fatal(err)
@@ -40,7 +42,7 @@ func setupTestConfig(t *testing.T) func() {
 	os.Setenv("POCKET_CONFIG", tmpFile.Name())
 
 	// Set API key
-	if err := config.Set("virustotal_api_key", "test-api-key"); err != nil {
+	if err := config.Set(ctx, "virustotal_api_key", "test-api-key"); err != nil {
 		t.Fatal(err)
 	}
 
@@ -226,6 +228,7 @@ func TestDomain_InvalidJSON(t *testing.T) {
 }
 
 func TestGetAPIKey_NoKey(t *testing.T) {
+	ctx := context.Background()
 	// Force config to reload by creating a new isolated environment
 	tmpFile, err := os.CreateTemp("", "vt-config-nokey-*.json")
 	if err != nil {
@@ -252,13 +255,13 @@ func TestGetAPIKey_NoKey(t *testing.T) {
 	// Force config package to reload by creating a fresh instance
 	// (Note: config package uses sync.Once, so it won't reload in same test run)
 	// This test verifies getAPIKey logic when key is empty string
-	key, err := config.Get("virustotal_api_key")
+	key, err := config.Get(ctx, "virustotal_api_key")
 	if key != "" {
 		// Skip if previous test left state
 		t.Skip("config state persists across tests due to sync.Once")
 	}
 
-	_, err = getAPIKey()
+	_, err = getAPIKey(ctx)
 	if err == nil {
 		t.Error("expected error for missing API key, got nil")
 	}
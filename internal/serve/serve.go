@@ -0,0 +1,327 @@
+// Package serve runs pocket as a local daemon that answers JSON-RPC calls
+// over HTTP, so tools in other languages can run commands without starting
+// a process for each one.
+package serve
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/batch"
+	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/jsonrpc"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+// DefaultListen is the address served unless --listen is given
+const DefaultListen = "127.0.0.1:7777"
+
+// TokenEnv overrides the bearer token kept in the token file
+const TokenEnv = "POCKET_SERVE_TOKEN"
+
+// maxBody caps a request body, as the batch reader caps a line
+const maxBody = 16 * 1024 * 1024
+
+// shutdownTimeout is how long calls in flight get to finish on exit
+const shutdownTimeout = 30 * time.Second
+
+// Server answers JSON-RPC calls by running commands in this process
+type Server struct {
+	runner *batch.Runner
+	token  string
+	// slots bounds how many commands run at once
+	slots chan struct{}
+}
+
+// NewServer returns a Server that runs up to concurrency commands at once
+// with runner, for callers presenting token
+func NewServer(runner *batch.Runner, token string, concurrency int) *Server {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Server{runner: runner, token: token, slots: make(chan struct{}, concurrency)}
+}
+
+func NewCmd(newRoot func() *cobra.Command) *cobra.Command {
+	var (
+		listen      string
+		concurrency int
+		allowRemote bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve every command over a local HTTP JSON-RPC API",
+		Long: `Run pocket as a long-lived daemon that answers JSON-RPC 2.0 calls on POST /rpc, such as
+  {"jsonrpc": "2.0", "id": 1, "method": "run", "params": {"cmd": ["dev", "npm", "info", "react"]}}
+The result is the command's JSON response envelope. Arrays of calls run concurrently, up to -j commands at a time across all clients. GET /health answers without auth.
+
+Calls need an "Authorization: Bearer <token>" header. The token is read from $POCKET_SERVE_TOKEN, or else from serve.token next to the config file, which is created with a random token on first start.
+
+Config, secrets, HTTP connections and refreshed OAuth tokens stay warm between calls. Secrets from secret_command are loaded once; call the reload method after changing them elsewhere. Global flags given to serve (--profile, --no-cache, --cache-ttl, --dry-run) apply to every call.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			if concurrency < 1 {
+				return output.PrintError(ctx, "invalid_input", "--concurrency must be at least 1", nil)
+			}
+			host, _, err := net.SplitHostPort(listen)
+			if err != nil {
+				return output.PrintError(ctx, "invalid_input", "--listen: "+err.Error(), nil)
+			}
+			if !allowRemote && !isLoopback(host) {
+				return output.PrintError(ctx, "invalid_input", "--listen "+listen+" is not a loopback address",
+					map[string]any{"hint": "bearer tokens travel in plain HTTP; add --allow-remote to listen there anyway"})
+			}
+
+			token, source, err := loadToken()
+			if err != nil {
+				return output.PrintErr(ctx, "token_failed", err, nil)
+			}
+
+			runner := batch.NewRunner(newRoot, concurrency)
+			runner.Inherit(cmd)
+			s := NewServer(runner, token, concurrency)
+
+			ln, err := net.Listen("tcp", listen)
+			if err != nil {
+				return output.PrintErr(ctx, "listen_failed", err, nil)
+			}
+			fmt.Fprintf(os.Stderr, "pocket serve: listening on http://%s (token from %s)\n", ln.Addr(), source)
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			srv := &http.Server{Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+			served := make(chan error, 1)
+			go func() { served <- srv.Serve(ln) }()
+
+			select {
+			case err := <-served:
+				return output.PrintErr(ctx, "serve_failed", err, nil)
+			case <-ctx.Done():
+			}
+			shutdown, cancel := context.WithTimeout(ctx, shutdownTimeout)
+			defer cancel()
+			if err := srv.Shutdown(shutdown); err != nil {
+				return output.PrintErr(ctx, "serve_failed", err, nil)
+			}
+			return output.Print(ctx, map[string]any{"listen": ln.Addr().String(), "status": "stopped"})
+		},
+	}
+
+	cmd.Flags().StringVar(&listen, "listen", DefaultListen, "Address to listen on (host:port)")
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "j", batch.DefaultConcurrency, "Commands to run at once")
+	cmd.Flags().BoolVar(&allowRemote, "allow-remote", false, "Allow listening on an address other than loopback")
+
+	return cmd
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// TokenPath is the file holding the bearer token: serve.token next to the
+// config file
+func TokenPath() string {
+	return filepath.Join(filepath.Dir(config.Path()), "serve.token")
+}
+
+// loadToken returns the bearer token and where it came from, creating the
+// token file with a random token if there is none
+func loadToken() (token, source string, err error) {
+	if t := strings.TrimSpace(os.Getenv(TokenEnv)); t != "" {
+		return t, "$" + TokenEnv, nil
+	}
+
+	path := TokenPath()
+	data, err := os.ReadFile(path)
+	if err == nil && len(bytes.TrimSpace(data)) > 0 {
+		return string(bytes.TrimSpace(data)), path, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return "", "", err
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+	token = hex.EncodeToString(b)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(path, []byte(token+"\n"), 0o600); err != nil {
+		return "", "", err
+	}
+	return token, path, nil
+}
+
+// Handler serves POST /rpc and GET /health
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, output.Response{Success: true, Data: map[string]any{"status": "ok"}})
+	})
+	mux.HandleFunc("POST /rpc", s.serveRPC)
+	return mux
+}
+
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) == 1
+}
+
+func (s *Server) serveRPC(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeJSON(w, http.StatusUnauthorized, output.Response{Error: &output.Error{
+			Code:    "unauthorized",
+			Message: "missing or wrong bearer token",
+		}})
+		return
+	}
+
+	var body json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody)).Decode(&body); err != nil {
+		writeJSON(w, http.StatusOK, jsonrpc.ErrorResponse(json.RawMessage("null"), jsonrpc.CodeParseError, err.Error()))
+		return
+	}
+
+	// A batch is an array of calls, answered by an array of responses
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+		var calls []json.RawMessage
+		if err := json.Unmarshal(trimmed, &calls); err != nil || len(calls) == 0 {
+			writeJSON(w, http.StatusOK, jsonrpc.ErrorResponse(json.RawMessage("null"), jsonrpc.CodeInvalidRequest, "batch must be a non-empty array"))
+			return
+		}
+
+		responses := make([]*jsonrpc.Response, len(calls))
+		var wg sync.WaitGroup
+		for i, call := range calls {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				responses[i] = s.call(r.Context(), call)
+			}()
+		}
+		wg.Wait()
+
+		answered := make([]*jsonrpc.Response, 0, len(responses))
+		for _, resp := range responses {
+			if resp != nil {
+				answered = append(answered, resp)
+			}
+		}
+		if len(answered) == 0 {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, http.StatusOK, answered)
+		return
+	}
+
+	resp := s.call(r.Context(), body)
+	if resp == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// call handles one JSON-RPC call. Notifications, which have no id, are
+// carried out but get no response.
+func (s *Server) call(ctx context.Context, raw json.RawMessage) *jsonrpc.Response {
+	var req jsonrpc.Request
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return jsonrpc.ErrorResponse(json.RawMessage("null"), jsonrpc.CodeInvalidRequest, err.Error())
+	}
+	resp := s.handle(ctx, &req)
+	if len(req.ID) == 0 {
+		return nil
+	}
+	return resp
+}
+
+func (s *Server) handle(ctx context.Context, req *jsonrpc.Request) *jsonrpc.Response {
+	if req.JSONRPC != jsonrpc.Version {
+		return jsonrpc.ErrorResponse(req.ID, jsonrpc.CodeInvalidRequest, "jsonrpc must be 2.0")
+	}
+
+	switch req.Method {
+	case "run":
+		cmdline, err := runParams(req.Params)
+		if err != nil {
+			return jsonrpc.ErrorResponse(req.ID, jsonrpc.CodeInvalidParams, err.Error())
+		}
+		select {
+		case s.slots <- struct{}{}:
+		case <-ctx.Done():
+			return jsonrpc.ErrorResponse(req.ID, jsonrpc.CodeInvalidRequest, "client went away: "+ctx.Err().Error())
+		}
+		defer func() { <-s.slots }()
+		return jsonrpc.Result(req.ID, json.RawMessage(s.runner.Exec(cmdline)))
+
+	case "ping":
+		return jsonrpc.Result(req.ID, envelope(map[string]any{"status": "ok"}))
+
+	case "reload":
+		config.Reload()
+		return jsonrpc.Result(req.ID, envelope(map[string]any{"reloaded": true}))
+
+	default:
+		return jsonrpc.ErrorResponse(req.ID, jsonrpc.CodeMethodNotFound, "method not found: "+req.Method)
+	}
+}
+
+// runParams reads the command line of a run call, given either as
+// {"cmd": [...]} like a batch request or as the array itself
+func runParams(params json.RawMessage) ([]string, error) {
+	var cmdline []string
+	if trimmed := bytes.TrimSpace(params); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &cmdline); err != nil {
+			return nil, err
+		}
+		return cmdline, nil
+	}
+
+	var p struct {
+		Cmd []string `json:"cmd"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil || len(p.Cmd) == 0 {
+		return nil, errors.New(`params must be {"cmd": [...]} or an array of arguments`)
+	}
+	return p.Cmd, nil
+}
+
+func envelope(data any) json.RawMessage {
+	resp, _ := json.Marshal(output.Response{Success: true, Data: data})
+	return resp
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,234 @@
+package serve
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/unstablemind/pocket/internal/batch"
+	"github.com/unstablemind/pocket/internal/common/jsonrpc"
+	"github.com/unstablemind/pocket/internal/common/testutil"
+	"github.com/unstablemind/pocket/pkg/output"
+)
+
+const token = "s3cret"
+
+func TestMain(m *testing.M) {
+	testutil.Main(m, nil)
+}
+
+// newTestRoot has the output flags of the real root, a command that waits
+// until every call in a test has started, and one that fails
+func newTestRoot(started *sync.WaitGroup, release chan struct{}) func() *cobra.Command {
+	return func() *cobra.Command {
+		var format string
+		var fields []string
+		root := &cobra.Command{
+			Use:           "pocket",
+			SilenceUsage:  true,
+			SilenceErrors: true,
+			PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				output.SetFormat(ctx, format)
+				output.SetFields(ctx, fields)
+				return nil
+			},
+		}
+		root.PersistentFlags().StringVarP(&format, "output", "o", "json", "")
+		root.PersistentFlags().StringSliceVar(&fields, "fields", nil, "")
+
+		root.AddCommand(&cobra.Command{
+			Use: "wait [name]",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				started.Done()
+				<-release
+				return output.Print(ctx, map[string]any{"name": args[0], "size": len(args[0])})
+			},
+		})
+		root.AddCommand(&cobra.Command{
+			Use: "fail",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				ctx := cmd.Context()
+				return output.PrintError(ctx, "boom", "it broke", nil)
+			},
+		})
+		return root
+	}
+}
+
+func newTestServer(t *testing.T, calls int) *httptest.Server {
+	t.Helper()
+	var started sync.WaitGroup
+	started.Add(calls)
+	release := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(release)
+	}()
+
+	runner := batch.NewRunner(newTestRoot(&started, release), calls)
+	srv := httptest.NewServer(NewServer(runner, token, calls).Handler())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func post(t *testing.T, srv *httptest.Server, auth, body string) (int, []byte) {
+	t.Helper()
+	req, _ := http.NewRequest("POST", srv.URL+"/rpc", strings.NewReader(body))
+	if auth != "" {
+		req.Header.Set("Authorization", "Bearer "+auth)
+	}
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, data
+}
+
+type rpcResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result *struct {
+		Success bool           `json:"success"`
+		Data    map[string]any `json:"data"`
+		Error   *output.Error  `json:"error"`
+	} `json:"result"`
+	Error *jsonrpc.Error `json:"error"`
+}
+
+func TestAuth(t *testing.T) {
+	srv := newTestServer(t, 1)
+	call := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+
+	for _, auth := range []string{"", "wrong"} {
+		status, body := post(t, srv, auth, call)
+		var resp output.Response
+		_ = json.Unmarshal(body, &resp)
+		if status != http.StatusUnauthorized || resp.Error == nil || resp.Error.Code != "unauthorized" {
+			t.Errorf("token %q: %d %s", auth, status, body)
+		}
+	}
+	if status, body := post(t, srv, token, call); status != http.StatusOK {
+		t.Errorf("right token: %d %s", status, body)
+	}
+
+	resp, err := srv.Client().Get(srv.URL + "/health")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("health without a token: %d", resp.StatusCode)
+	}
+}
+
+func TestBatchRunsConcurrently(t *testing.T) {
+	// The three wait calls only finish once all of them are running
+	srv := newTestServer(t, 3)
+	body := `[
+		{"jsonrpc":"2.0","id":1,"method":"run","params":{"cmd":["wait","alpha","--fields","name"]}},
+		{"jsonrpc":"2.0","id":"two","method":"run","params":["wait","beta","-o","table"]},
+		{"jsonrpc":"2.0","id":3,"method":"run","params":{"cmd":["wait","gamma"]}},
+		{"jsonrpc":"2.0","id":4,"method":"run","params":{"cmd":["fail"]}},
+		{"jsonrpc":"2.0","id":5,"method":"run","params":{"cmd":["serve"]}},
+		{"jsonrpc":"2.0","id":6,"method":"run","params":{"argv":"fail"}},
+		{"jsonrpc":"2.0","id":7,"method":"nope"},
+		{"jsonrpc":"2.0","method":"ping"}
+	]`
+
+	status, data := post(t, srv, token, body)
+	var responses []rpcResponse
+	if err := json.Unmarshal(data, &responses); err != nil || status != http.StatusOK {
+		t.Fatalf("%d %s: %v", status, data, err)
+	}
+	byID := map[string]rpcResponse{}
+	for _, r := range responses {
+		byID[string(r.ID)] = r
+	}
+	if len(byID) != 7 {
+		t.Fatalf("got %d responses, the notification should get none: %s", len(byID), data)
+	}
+
+	if r := byID["1"].Result; r == nil || !r.Success || len(r.Data) != 1 || r.Data["name"] != "alpha" {
+		t.Errorf("call 1 = %s", data)
+	}
+	if r := byID[`"two"`].Result; r == nil || !r.Success || len(r.Data) != 2 {
+		t.Errorf("call two = %s", data)
+	}
+	if r := byID["3"].Result; r == nil || !r.Success || r.Data["size"] != float64(5) {
+		t.Errorf("call 3 = %s", data)
+	}
+	// Failed commands are results carrying an error envelope
+	if r := byID["4"].Result; r == nil || r.Success || r.Error == nil || r.Error.Code != "boom" {
+		t.Errorf("call 4 = %s", data)
+	}
+	if r := byID["5"].Result; r == nil || r.Success || r.Error.Code != "invalid_request" {
+		t.Errorf("call 5 = %s", data)
+	}
+	if r := byID["6"]; r.Result != nil || r.Error == nil || r.Error.Code != jsonrpc.CodeInvalidParams {
+		t.Errorf("call 6 = %s", data)
+	}
+	if r := byID["7"]; r.Error == nil || r.Error.Code != jsonrpc.CodeMethodNotFound {
+		t.Errorf("call 7 = %s", data)
+	}
+}
+
+func TestInvalidBodies(t *testing.T) {
+	srv := newTestServer(t, 1)
+	tests := []struct {
+		body string
+		code int
+	}{
+		{`{"jsonrpc":`, jsonrpc.CodeParseError},
+		{`[]`, jsonrpc.CodeInvalidRequest},
+		{`{"jsonrpc":"1.0","id":1,"method":"ping"}`, jsonrpc.CodeInvalidRequest},
+	}
+	for _, tt := range tests {
+		_, data := post(t, srv, token, tt.body)
+		var resp rpcResponse
+		if err := json.Unmarshal(data, &resp); err != nil || resp.Error == nil || resp.Error.Code != tt.code {
+			t.Errorf("%s: %s", tt.body, data)
+		}
+	}
+
+	if status, data := post(t, srv, token, `{"jsonrpc":"2.0","method":"ping"}`); status != http.StatusNoContent || len(data) != 0 {
+		t.Errorf("notification: %d %s", status, data)
+	}
+}
+
+func TestLoadToken(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("POCKET_CONFIG", filepath.Join(dir, "config.json"))
+	path := filepath.Join(dir, "serve.token")
+	if TokenPath() != path {
+		// config.Path is resolved once per process
+		t.Skip("config path was fixed by an earlier test")
+	}
+
+	t.Setenv(TokenEnv, "")
+	first, source, err := loadToken()
+	if err != nil || len(first) != 64 || source != path {
+		t.Fatalf("loadToken() = %q, %q, %v", first, source, err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("token file: %v, %v", info, err)
+	}
+	if again, _, _ := loadToken(); again != first {
+		t.Errorf("the token should be kept across starts: %q, then %q", first, again)
+	}
+
+	t.Setenv(TokenEnv, "from-env")
+	if tok, source, _ := loadToken(); tok != "from-env" || source != "$"+TokenEnv {
+		t.Errorf("with %s set: %q from %q", TokenEnv, tok, source)
+	}
+}
@@ -13,9 +13,28 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "mastodon",
+		Name:        "Mastodon",
+		Group:       "social",
+		Description: "Fediverse: timelines, posting, and search",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "mastodon_server", Description: "Server URL (e.g., mastodon.social)", Required: true, Example: "mastodon.social"},
+			{Key: "mastodon_token", Description: "Access token", Required: true},
+		},
+		SetupGuide:  "1. Go to your Mastodon instance's settings\n2. Development > New Application\n3. Create app with read/write scopes\n4. Copy the access token\n5. Run:\n   pocket config set mastodon_server <server>\n   pocket config set mastodon_token <token>",
+		TestCommand: "pocket social mastodon timeline -l 1",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "mastodon",
@@ -39,12 +58,12 @@ type mastoClient struct {
 	httpClient *http.Client
 }
 
-func newMastoClient() (*mastoClient, error) {
-	server, err := config.MustGet("mastodon_server")
+func newMastoClient(ctx context.Context) (*mastoClient, error) {
+	server, err := config.MustGet(ctx, "mastodon_server")
 	if err != nil {
 		return nil, err
 	}
-	token, err := config.MustGet("mastodon_token")
+	token, err := config.MustGet(ctx, "mastodon_token")
 	if err != nil {
 		return nil, err
 	}
@@ -57,12 +76,12 @@ func newMastoClient() (*mastoClient, error) {
 	return &mastoClient{
 		server:     server,
 		token:      token,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: transport.New(30 * time.Second),
 	}, nil
 }
 
-func (c *mastoClient) doRequest(method, endpoint string, body any) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (c *mastoClient) doRequest(ctx context.Context, method, endpoint string, body any) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	var reqBody io.Reader
@@ -158,7 +177,8 @@ func newTimelineCmd() *cobra.Command {
 		Use:   "timeline",
 		Short: "Get timeline",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newMastoClient()
+			ctx := cmd.Context()
+			client, err := newMastoClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -183,17 +203,17 @@ func newTimelineCmd() *cobra.Command {
 				}
 			}
 
-			body, err := client.doRequest("GET", endpoint, nil)
+			body, err := client.doRequest(ctx, "GET", endpoint, nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var statuses []status
 			if err := json.Unmarshal(body, &statuses); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"timeline": timeline,
 				"count":    len(statuses),
 				"statuses": formatStatuses(statuses),
@@ -217,7 +237,8 @@ func newPostCmd() *cobra.Command {
 		Short: "Post a toot",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newMastoClient()
+			ctx := cmd.Context()
+			client, err := newMastoClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -234,17 +255,17 @@ func newPostCmd() *cobra.Command {
 				payload["spoiler_text"] = spoiler
 			}
 
-			body, err := client.doRequest("POST", "/statuses", payload)
+			body, err := client.doRequest(ctx, "POST", "/statuses", payload)
 			if err != nil {
-				return output.PrintError("post_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "post_failed", err, nil)
 			}
 
 			var result status
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"id":         result.ID,
 				"url":        result.URL,
 				"content":    result.Content,
@@ -270,7 +291,8 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search Mastodon",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newMastoClient()
+			ctx := cmd.Context()
+			client, err := newMastoClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -281,12 +303,12 @@ func newSearchCmd() *cobra.Command {
 				endpoint += "&type=" + searchType
 			}
 
-			body, err := client.doRequest("GET", "/api/v2/search?q="+query+fmt.Sprintf("&limit=%d", limit), nil)
+			body, err := client.doRequest(ctx, "GET", "/api/v2/search?q="+query+fmt.Sprintf("&limit=%d", limit), nil)
 			if err != nil {
 				// Fall back to v1 search
-				body, err = client.doRequest("GET", endpoint, nil)
+				body, err = client.doRequest(ctx, "GET", endpoint, nil)
 				if err != nil {
-					return output.PrintError("request_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "request_failed", err, nil)
 				}
 			}
 
@@ -307,7 +329,7 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			accounts := make([]map[string]any, len(result.Accounts))
@@ -329,7 +351,7 @@ func newSearchCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"query":    args[0],
 				"accounts": accounts,
 				"statuses": formatStatuses(result.Statuses),
@@ -351,15 +373,16 @@ func newNotificationsCmd() *cobra.Command {
 		Use:   "notifications",
 		Short: "Get notifications",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newMastoClient()
+			ctx := cmd.Context()
+			client, err := newMastoClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			endpoint := fmt.Sprintf("/notifications?limit=%d", limit)
-			body, err := client.doRequest("GET", endpoint, nil)
+			body, err := client.doRequest(ctx, "GET", endpoint, nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var notifications []struct {
@@ -374,7 +397,7 @@ func newNotificationsCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &notifications); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			result := make([]map[string]any, len(notifications))
@@ -393,7 +416,7 @@ func newNotificationsCmd() *cobra.Command {
 				result[i] = item
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"count":         len(notifications),
 				"notifications": result,
 			})
@@ -410,14 +433,15 @@ func newMeCmd() *cobra.Command {
 		Use:   "me",
 		Short: "Get your account info",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newMastoClient()
+			ctx := cmd.Context()
+			client, err := newMastoClient(ctx)
 			if err != nil {
 				return err
 			}
 
-			body, err := client.doRequest("GET", "/accounts/verify_credentials", nil)
+			body, err := client.doRequest(ctx, "GET", "/accounts/verify_credentials", nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var account struct {
@@ -435,10 +459,10 @@ func newMeCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(body, &account); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"id":         account.ID,
 				"username":   account.Username,
 				"acct":       account.Acct,
@@ -1,6 +1,7 @@
 package mastodon
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -129,6 +130,7 @@ func TestFormatStatusesWithReblog(t *testing.T) {
 }
 
 func TestDoRequest(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check authorization header
 		if r.Header.Get("Authorization") != "Bearer test-token" {
@@ -167,7 +169,7 @@ func TestDoRequest(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	body, err := client.doRequest("GET", "/timelines/home", nil)
+	body, err := client.doRequest(ctx, "GET", "/timelines/home", nil)
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -187,6 +189,7 @@ func TestDoRequest(t *testing.T) {
 }
 
 func TestDoRequestPOST(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check method
 		if r.Method != "POST" {
@@ -230,7 +233,7 @@ func TestDoRequestPOST(t *testing.T) {
 		"visibility": "public",
 	}
 
-	body, err := client.doRequest("POST", "/statuses", payload)
+	body, err := client.doRequest(ctx, "POST", "/statuses", payload)
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -246,6 +249,7 @@ func TestDoRequestPOST(t *testing.T) {
 }
 
 func TestDoRequestError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -260,7 +264,7 @@ func TestDoRequestError(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	_, err := client.doRequest("GET", "/timelines/home", nil)
+	_, err := client.doRequest(ctx, "GET", "/timelines/home", nil)
 	if err == nil {
 		t.Error("expected error for 401 response, got nil")
 	}
@@ -11,11 +11,15 @@ import (
 	"net/url"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -29,6 +33,22 @@ var (
 	scopes       = "identity read mysubreddits history"
 )
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "reddit",
+		Name:        "Reddit",
+		Group:       "social",
+		Description: "Browse feeds, subreddits, search, users, and comments (free tier: 100 req/min)",
+		Auth:        registry.AuthOAuth,
+		Keys: []registry.Key{
+			{Key: "reddit_client_id", Description: "OAuth Client ID (installed app)", Required: true},
+		},
+		SetupGuide:  "1. Go to https://www.reddit.com/prefs/apps\n2. Click 'create another app' at the bottom\n3. Select 'installed app' type, name it\n4. Set redirect URI to http://localhost:8766/callback\n5. Copy the client ID (shown under app name)\n6. Run: pocket config set reddit_client_id <id>\n7. Run: pocket social reddit auth\n\nNote: Free tier allows 100 req/min for non-commercial use.",
+		TestCommand: "pocket social reddit feed -l 1",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "reddit",
@@ -54,15 +74,21 @@ type redditClient struct {
 	httpClient *http.Client
 }
 
-func newRedditClient() (*redditClient, error) {
-	clientID, err := config.MustGet("reddit_client_id")
+// refreshMu makes concurrent requests in one process refresh an expired
+// token once and share the result
+var refreshMu sync.Mutex
+
+func newRedditClient(ctx context.Context) (*redditClient, error) {
+	clientID, err := config.MustGet(ctx, "reddit_client_id")
 	if err != nil {
 		return nil, err
 	}
 
-	accessToken, _ := config.Get("reddit_access_token")
-	refreshToken, _ := config.Get("reddit_refresh_token")
-	expiryStr, _ := config.Get("reddit_token_expiry")
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+	accessToken, _ := config.Get(ctx, "reddit_access_token")
+	refreshToken, _ := config.Get(ctx, "reddit_refresh_token")
+	expiryStr, _ := config.Get(ctx, "reddit_token_expiry")
 
 	// Check if token exists and is valid
 	if accessToken != "" && expiryStr != "" {
@@ -71,38 +97,38 @@ func newRedditClient() (*redditClient, error) {
 			return &redditClient{
 				clientID:   clientID,
 				token:      accessToken,
-				httpClient: &http.Client{Timeout: 30 * time.Second},
+				httpClient: transport.New(30 * time.Second),
 			}, nil
 		}
 	}
 
 	// Token expired or missing — try refresh
 	if refreshToken != "" {
-		newToken, err := refreshAccessToken(clientID, refreshToken)
+		newToken, err := refreshAccessToken(ctx, clientID, refreshToken)
 		if err == nil {
 			return &redditClient{
 				clientID:   clientID,
 				token:      newToken,
-				httpClient: &http.Client{Timeout: 30 * time.Second},
+				httpClient: transport.New(30 * time.Second),
 			}, nil
 		}
 	}
 
-	return nil, output.PrintError("auth_required",
+	return nil, output.PrintError(ctx, "auth_required",
 		"Reddit OAuth not configured or expired. Run: pocket social reddit auth",
 		nil)
 }
 
-func refreshAccessToken(clientID, refreshToken string) (string, error) {
+func refreshAccessToken(ctx context.Context, clientID, refreshToken string) (string, error) {
 	data := url.Values{
 		"grant_type":    {"refresh_token"},
 		"refresh_token": {refreshToken},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(dryrun.ReadOnly(ctx), "POST", tokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return "", err
 	}
@@ -113,7 +139,7 @@ func refreshAccessToken(clientID, refreshToken string) (string, error) {
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("User-Agent", userAgent)
 
-	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	resp, err := transport.New(30 * time.Second).Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -143,14 +169,14 @@ func refreshAccessToken(clientID, refreshToken string) (string, error) {
 
 	// Store new access token (Reddit refresh tokens don't rotate)
 	expiry := time.Now().Add(time.Duration(tokenResp.ExpiresIn-60) * time.Second)
-	_ = config.Set("reddit_access_token", tokenResp.AccessToken)
-	_ = config.Set("reddit_token_expiry", expiry.Format(time.RFC3339))
+	_ = config.Set(ctx, "reddit_access_token", tokenResp.AccessToken)
+	_ = config.Set(ctx, "reddit_token_expiry", expiry.Format(time.RFC3339))
 
 	return tokenResp.AccessToken, nil
 }
 
-func (c *redditClient) doRequest(endpoint string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (c *redditClient) doRequest(ctx context.Context, endpoint string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	reqURL := apiBaseURL + endpoint
@@ -189,9 +215,10 @@ func newAuthCmd() *cobra.Command {
 		Short: "Authenticate with Reddit using OAuth 2.0",
 		Long:  "Opens your browser to authorize Pocket CLI with your Reddit account.\nRequires reddit_client_id to be configured first.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clientID, err := config.MustGet("reddit_client_id")
+			ctx := cmd.Context()
+			clientID, err := config.MustGet(ctx, "reddit_client_id")
 			if err != nil {
-				return output.PrintError("setup_required",
+				return output.PrintError(ctx, "setup_required",
 					"reddit_client_id not configured. Set it first: pocket config set reddit_client_id <your-client-id>",
 					map[string]string{
 						"setup": "1. Go to https://www.reddit.com/prefs/apps\n2. Click 'create another app'\n3. Select 'installed app' type\n4. Set redirect URI to " + redirectURI + "\n5. Copy the client ID (shown under app name)",
@@ -214,7 +241,7 @@ func newAuthCmd() *cobra.Command {
 			// Start local callback server
 			listener, err := net.Listen("tcp", "localhost:"+callbackPort)
 			if err != nil {
-				return output.PrintError("auth_error",
+				return output.PrintError(ctx, "auth_error",
 					fmt.Sprintf("Failed to start callback server on port %s: %s", callbackPort, err.Error()), nil)
 			}
 
@@ -260,10 +287,10 @@ func newAuthCmd() *cobra.Command {
 				// Success
 			case errMsg := <-errCh:
 				server.Close()
-				return output.PrintError("auth_denied", "Authorization failed: "+errMsg, nil)
+				return output.PrintError(ctx, "auth_denied", "Authorization failed: "+errMsg, nil)
 			case <-time.After(5 * time.Minute):
 				server.Close()
-				return output.PrintError("auth_timeout", "Authorization timed out after 5 minutes", nil)
+				return output.PrintError(ctx, "auth_timeout", "Authorization timed out after 5 minutes", nil)
 			}
 			server.Close()
 
@@ -274,12 +301,12 @@ func newAuthCmd() *cobra.Command {
 				"redirect_uri": {redirectURI},
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(tokenData.Encode()))
 			if err != nil {
-				return output.PrintError("auth_error", "Failed to create token request: "+err.Error(), nil)
+				return output.PrintError(ctx, "auth_error", "Failed to create token request: "+err.Error(), nil)
 			}
 
 			// Installed apps: Basic auth with empty password
@@ -288,19 +315,19 @@ func newAuthCmd() *cobra.Command {
 			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 			req.Header.Set("User-Agent", userAgent)
 
-			resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+			resp, err := transport.New(30 * time.Second).Do(req)
 			if err != nil {
-				return output.PrintError("auth_error", "Token exchange failed: "+err.Error(), nil)
+				return output.PrintErr(ctx, "auth_error", fmt.Errorf("Token exchange failed: %w", err), nil)
 			}
 			defer resp.Body.Close()
 
 			respBody, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("auth_error", "Failed to read token response: "+err.Error(), nil)
+				return output.PrintError(ctx, "auth_error", "Failed to read token response: "+err.Error(), nil)
 			}
 
 			if resp.StatusCode != 200 {
-				return output.PrintError("auth_error",
+				return output.PrintError(ctx, "auth_error",
 					fmt.Sprintf("Token exchange failed (HTTP %d): %s", resp.StatusCode, string(respBody)), nil)
 			}
 
@@ -312,20 +339,20 @@ func newAuthCmd() *cobra.Command {
 				Error        string `json:"error"`
 			}
 			if err := json.Unmarshal(respBody, &tokenResp); err != nil {
-				return output.PrintError("auth_error", "Failed to parse token response: "+err.Error(), nil)
+				return output.PrintError(ctx, "auth_error", "Failed to parse token response: "+err.Error(), nil)
 			}
 
 			if tokenResp.Error != "" {
-				return output.PrintError("auth_error", "Reddit auth error: "+tokenResp.Error, nil)
+				return output.PrintError(ctx, "auth_error", "Reddit auth error: "+tokenResp.Error, nil)
 			}
 
 			// Store tokens
 			expiry := time.Now().Add(time.Duration(tokenResp.ExpiresIn-60) * time.Second)
-			_ = config.Set("reddit_access_token", tokenResp.AccessToken)
-			_ = config.Set("reddit_refresh_token", tokenResp.RefreshToken)
-			_ = config.Set("reddit_token_expiry", expiry.Format(time.RFC3339))
+			_ = config.Set(ctx, "reddit_access_token", tokenResp.AccessToken)
+			_ = config.Set(ctx, "reddit_refresh_token", tokenResp.RefreshToken)
+			_ = config.Set(ctx, "reddit_token_expiry", expiry.Format(time.RFC3339))
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"status": "authenticated",
 				"scopes": tokenResp.Scope,
 				"expiry": expiry.Format(time.RFC3339),
@@ -399,23 +426,24 @@ func newFeedCmd() *cobra.Command {
 		Use:   "feed",
 		Short: "Get your home feed",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newRedditClient()
+			ctx := cmd.Context()
+			client, err := newRedditClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			endpoint := fmt.Sprintf("/%s?limit=%d", sort, limit)
-			body, err := client.doRequest(endpoint)
+			body, err := client.doRequest(ctx, endpoint)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			posts, err := parseListingResponse(body)
 			if err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"count": len(posts),
 				"posts": formatPosts(posts),
 			})
@@ -438,7 +466,8 @@ func newSubredditCmd() *cobra.Command {
 		Short: "Get posts from a subreddit",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newRedditClient()
+			ctx := cmd.Context()
+			client, err := newRedditClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -449,17 +478,17 @@ func newSubredditCmd() *cobra.Command {
 				endpoint += "&t=" + timeFrame
 			}
 
-			body, err := client.doRequest(endpoint)
+			body, err := client.doRequest(ctx, endpoint)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			posts, err := parseListingResponse(body)
 			if err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"subreddit": subreddit,
 				"count":     len(posts),
 				"posts":     formatPosts(posts),
@@ -485,7 +514,8 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search Reddit",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newRedditClient()
+			ctx := cmd.Context()
+			client, err := newRedditClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -497,17 +527,17 @@ func newSearchCmd() *cobra.Command {
 					strings.TrimPrefix(subreddit, "r/"), query, limit, sort, timeFrame)
 			}
 
-			body, err := client.doRequest(endpoint)
+			body, err := client.doRequest(ctx, endpoint)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			posts, err := parseListingResponse(body)
 			if err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"query": args[0],
 				"count": len(posts),
 				"posts": formatPosts(posts),
@@ -531,7 +561,8 @@ func newUserCmd() *cobra.Command {
 		Short: "Get user info and posts",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newRedditClient()
+			ctx := cmd.Context()
+			client, err := newRedditClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -539,9 +570,9 @@ func newUserCmd() *cobra.Command {
 			username := strings.TrimPrefix(args[0], "u/")
 
 			// Get user info
-			aboutBody, err := client.doRequest("/user/" + username + "/about")
+			aboutBody, err := client.doRequest(ctx, "/user/"+username+"/about")
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var userInfo struct {
@@ -555,18 +586,18 @@ func newUserCmd() *cobra.Command {
 				} `json:"data"`
 			}
 			if err := json.Unmarshal(aboutBody, &userInfo); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			// Get recent posts
-			postsBody, err := client.doRequest(fmt.Sprintf("/user/%s/submitted?limit=%d", username, limit))
+			postsBody, err := client.doRequest(ctx, fmt.Sprintf("/user/%s/submitted?limit=%d", username, limit))
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			posts, _ := parseListingResponse(postsBody)
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"username":      userInfo.Data.Name,
 				"created":       time.Unix(userInfo.Data.Created, 0).Format(time.RFC3339),
 				"link_karma":    userInfo.Data.LinkKarma,
@@ -593,7 +624,8 @@ func newCommentsCmd() *cobra.Command {
 		Short: "Get comments on a post",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newRedditClient()
+			ctx := cmd.Context()
+			client, err := newRedditClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -601,19 +633,19 @@ func newCommentsCmd() *cobra.Command {
 			postID := args[0]
 			endpoint := fmt.Sprintf("/comments/%s?limit=%d&sort=%s", postID, limit, sort)
 
-			body, err := client.doRequest(endpoint)
+			body, err := client.doRequest(ctx, endpoint)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			// Comments response is an array [post, comments]
 			var response []json.RawMessage
 			if err := json.Unmarshal(body, &response); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			if len(response) < 2 {
-				return output.PrintError("parse_error", "unexpected response format", nil)
+				return output.PrintError(ctx, "parse_error", "unexpected response format", nil)
 			}
 
 			// Parse comments
@@ -633,7 +665,7 @@ func newCommentsCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(response[1], &commentsListing); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
 			comments := make([]map[string]any, 0)
@@ -651,7 +683,7 @@ func newCommentsCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"post_id":  postID,
 				"count":    len(comments),
 				"comments": comments,
@@ -675,7 +707,8 @@ func newPostCmd() *cobra.Command {
 		Short: "Create a text post",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return output.PrintError("not_implemented",
+			ctx := cmd.Context()
+			return output.PrintError(ctx, "not_implemented",
 				"Posting to Reddit requires the 'submit' OAuth scope. Re-run 'pocket social reddit auth' with submit scope enabled, then use Reddit's API.",
 				map[string]string{
 					"docs": "https://www.reddit.com/dev/api#POST_api_submit",
@@ -1,6 +1,7 @@
 package reddit
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -110,6 +111,7 @@ func TestFormatPosts(t *testing.T) {
 }
 
 func TestDoRequest(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check headers
 		if r.Header.Get("Authorization") != "Bearer test-token" {
@@ -153,7 +155,7 @@ func TestDoRequest(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	body, err := client.doRequest("/test")
+	body, err := client.doRequest(ctx, "/test")
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -173,6 +175,7 @@ func TestDoRequest(t *testing.T) {
 }
 
 func TestDoRequestError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusUnauthorized)
 		w.Write([]byte(`{"error": "invalid_token"}`))
@@ -188,13 +191,14 @@ func TestDoRequestError(t *testing.T) {
 		httpClient: &http.Client{},
 	}
 
-	_, err := client.doRequest("/test")
+	_, err := client.doRequest(ctx, "/test")
 	if err == nil {
 		t.Error("expected error for 401 response, got nil")
 	}
 }
 
 func TestRefreshAccessToken(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check method
 		if r.Method != "POST" {
@@ -219,7 +223,7 @@ func TestRefreshAccessToken(t *testing.T) {
 	tokenURL = srv.URL
 	defer func() { tokenURL = oldTokenURL }()
 
-	token, err := refreshAccessToken("test-client-id", "test-refresh-token")
+	token, err := refreshAccessToken(ctx, "test-client-id", "test-refresh-token")
 	if err != nil {
 		t.Fatalf("refreshAccessToken failed: %v", err)
 	}
@@ -230,6 +234,7 @@ func TestRefreshAccessToken(t *testing.T) {
 }
 
 func TestRefreshAccessTokenError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -242,7 +247,7 @@ func TestRefreshAccessTokenError(t *testing.T) {
 	tokenURL = srv.URL
 	defer func() { tokenURL = oldTokenURL }()
 
-	_, err := refreshAccessToken("test-client-id", "bad-refresh-token")
+	_, err := refreshAccessToken(ctx, "test-client-id", "bad-refresh-token")
 	if err == nil {
 		t.Error("expected error for bad refresh token, got nil")
 	}
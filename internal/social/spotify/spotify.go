@@ -15,6 +15,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -23,7 +25,7 @@ var (
 	tokenURL   = "https://accounts.spotify.com/api/token" //nolint:gosec // OAuth endpoint URL, not a credential
 )
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Token cache
 var (
@@ -103,6 +105,23 @@ type AlbumTrack struct {
 	Duration string `json:"duration"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "spotify",
+		Name:        "Spotify",
+		Group:       "social",
+		Description: "Search tracks, artists, and albums on Spotify",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "spotify_client_id", Description: "OAuth Client ID", Required: true},
+			{Key: "spotify_client_secret", Description: "OAuth Client Secret", Required: true},
+		},
+		SetupGuide:  "1. Go to https://developer.spotify.com/dashboard\n2. Log in and create a new app\n3. Set redirect URI to http://localhost:8767/callback\n4. Copy the Client ID and Client Secret\n5. Run:\n   pocket config set spotify_client_id <your-client-id>\n   pocket config set spotify_client_secret <your-client-secret>\n\nNote: Free Spotify account works for search. Premium needed for playback control.",
+		TestCommand: "pocket social spotify search test -l 1",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the Spotify command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -119,7 +138,7 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func getToken() (string, error) {
+func getToken(ctx context.Context) (string, error) {
 	tokenMu.Lock()
 	defer tokenMu.Unlock()
 
@@ -128,18 +147,18 @@ func getToken() (string, error) {
 		return cachedToken, nil
 	}
 
-	clientID, err := config.Get("spotify_client_id")
+	clientID, err := config.Get(ctx, "spotify_client_id")
 	if err != nil || clientID == "" {
-		return "", output.PrintError("setup_required", "Spotify client ID not configured", map[string]any{
+		return "", output.PrintError(ctx, "setup_required", "Spotify client ID not configured", map[string]any{
 			"missing":   []string{"spotify_client_id", "spotify_client_secret"},
 			"setup_cmd": "pocket config set spotify_client_id <your-id>",
 			"hint":      "Create an app at https://developer.spotify.com/dashboard",
 		})
 	}
 
-	clientSecret, err := config.Get("spotify_client_secret")
+	clientSecret, err := config.Get(ctx, "spotify_client_secret")
 	if err != nil || clientSecret == "" {
-		return "", output.PrintError("setup_required", "Spotify client secret not configured", map[string]any{
+		return "", output.PrintError(ctx, "setup_required", "Spotify client secret not configured", map[string]any{
 			"missing":   []string{"spotify_client_secret"},
 			"setup_cmd": "pocket config set spotify_client_secret <your-secret>",
 			"hint":      "Create an app at https://developer.spotify.com/dashboard",
@@ -147,13 +166,13 @@ func getToken() (string, error) {
 	}
 
 	// Request token using Client Credentials flow
-	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
 	defer cancel()
 
 	form := url.Values{"grant_type": {"client_credentials"}}
 	req, err := http.NewRequestWithContext(ctx, "POST", tokenURL, strings.NewReader(form.Encode()))
 	if err != nil {
-		return "", output.PrintError("auth_failed", fmt.Sprintf("Failed to create auth request: %s", err.Error()), nil)
+		return "", output.PrintError(ctx, "auth_failed", fmt.Sprintf("Failed to create auth request: %s", err.Error()), nil)
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
@@ -162,17 +181,17 @@ func getToken() (string, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return "", output.PrintError("auth_failed", fmt.Sprintf("Auth request failed: %s", err.Error()), nil)
+		return "", output.PrintErr(ctx, "auth_failed", fmt.Errorf("Auth request failed: %w", err), nil)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", output.PrintError("auth_failed", fmt.Sprintf("Failed to read auth response: %s", err.Error()), nil)
+		return "", output.PrintError(ctx, "auth_failed", fmt.Sprintf("Failed to read auth response: %s", err.Error()), nil)
 	}
 
 	if resp.StatusCode != 200 {
-		return "", output.PrintError("auth_failed", fmt.Sprintf("Auth failed with HTTP %d", resp.StatusCode), map[string]any{
+		return "", output.PrintError(ctx, "auth_failed", fmt.Sprintf("Auth failed with HTTP %d", resp.StatusCode), map[string]any{
 			"hint": "Check your spotify_client_id and spotify_client_secret",
 		})
 	}
@@ -184,7 +203,7 @@ func getToken() (string, error) {
 	}
 
 	if err := json.Unmarshal(body, &tokenResp); err != nil {
-		return "", output.PrintError("auth_failed", fmt.Sprintf("Failed to parse auth response: %s", err.Error()), nil)
+		return "", output.PrintError(ctx, "auth_failed", fmt.Sprintf("Failed to parse auth response: %s", err.Error()), nil)
 	}
 
 	cachedToken = tokenResp.AccessToken
@@ -202,7 +221,8 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search for tracks, artists, albums, or playlists",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -215,22 +235,22 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			reqURL := fmt.Sprintf("%s/search?%s", apiBaseURL, params.Encode())
-			data, err := doRequest(token, reqURL)
+			data, err := doRequest(ctx, token, reqURL)
 			if err != nil {
 				return err
 			}
 
 			switch searchType {
 			case "track":
-				return parseTrackSearch(data)
+				return parseTrackSearch(ctx, data)
 			case "artist":
-				return parseArtistSearch(data)
+				return parseArtistSearch(ctx, data)
 			case "album":
-				return parseAlbumSearch(data)
+				return parseAlbumSearch(ctx, data)
 			case "playlist":
-				return parsePlaylistSearch(data)
+				return parsePlaylistSearch(ctx, data)
 			default:
-				return parseTrackSearch(data)
+				return parseTrackSearch(ctx, data)
 			}
 		},
 	}
@@ -247,14 +267,15 @@ func newTrackCmd() *cobra.Command {
 		Short: "Get track details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
 
 			trackID := extractID(args[0])
 			reqURL := fmt.Sprintf("%s/tracks/%s", apiBaseURL, url.PathEscape(trackID))
-			data, err := doRequest(token, reqURL)
+			data, err := doRequest(ctx, token, reqURL)
 			if err != nil {
 				return err
 			}
@@ -280,7 +301,7 @@ func newTrackCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
 			}
 
 			artistNames := make([]string, 0, len(resp.Artists))
@@ -300,7 +321,7 @@ func newTrackCmd() *cobra.Command {
 				Explicit:   resp.Explicit,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -313,7 +334,8 @@ func newArtistCmd() *cobra.Command {
 		Short: "Get artist details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
@@ -322,7 +344,7 @@ func newArtistCmd() *cobra.Command {
 
 			// Get artist info
 			reqURL := fmt.Sprintf("%s/artists/%s", apiBaseURL, url.PathEscape(artistID))
-			data, err := doRequest(token, reqURL)
+			data, err := doRequest(ctx, token, reqURL)
 			if err != nil {
 				return err
 			}
@@ -340,12 +362,12 @@ func newArtistCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
 			}
 
 			// Get top tracks
 			topTracksURL := fmt.Sprintf("%s/artists/%s/top-tracks?market=US", apiBaseURL, url.PathEscape(artistID))
-			topData, topErr := doRequest(token, topTracksURL)
+			topData, topErr := doRequest(ctx, token, topTracksURL)
 
 			var topTrackNames []string
 			if topErr == nil {
@@ -373,7 +395,7 @@ func newArtistCmd() *cobra.Command {
 				TopTracks:  topTrackNames,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -386,14 +408,15 @@ func newAlbumCmd() *cobra.Command {
 		Short: "Get album details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			token, err := getToken()
+			ctx := cmd.Context()
+			token, err := getToken(ctx)
 			if err != nil {
 				return err
 			}
 
 			albumID := extractID(args[0])
 			reqURL := fmt.Sprintf("%s/albums/%s", apiBaseURL, url.PathEscape(albumID))
-			data, err := doRequest(token, reqURL)
+			data, err := doRequest(ctx, token, reqURL)
 			if err != nil {
 				return err
 			}
@@ -420,7 +443,7 @@ func newAlbumCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
+				return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
 			}
 
 			artistNames := make([]string, 0, len(resp.Artists))
@@ -448,20 +471,20 @@ func newAlbumCmd() *cobra.Command {
 				Genres:      resp.Genres,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
 	return cmd
 }
 
-func doRequest(token, reqURL string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, token, reqURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("request_failed", fmt.Sprintf("Failed to create request: %s", err.Error()), nil)
+		return nil, output.PrintError(ctx, "request_failed", fmt.Sprintf("Failed to create request: %s", err.Error()), nil)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token)
@@ -469,13 +492,13 @@ func doRequest(token, reqURL string) ([]byte, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("request_failed", fmt.Sprintf("Request failed: %s", err.Error()), nil)
+		return nil, output.PrintErr(ctx, "request_failed", fmt.Errorf("Request failed: %w", err), nil)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, output.PrintError("read_failed", fmt.Sprintf("Failed to read response: %s", err.Error()), nil)
+		return nil, output.PrintError(ctx, "read_failed", fmt.Sprintf("Failed to read response: %s", err.Error()), nil)
 	}
 
 	if resp.StatusCode == 401 {
@@ -483,11 +506,11 @@ func doRequest(token, reqURL string) ([]byte, error) {
 		tokenMu.Lock()
 		cachedToken = ""
 		tokenMu.Unlock()
-		return nil, output.PrintError("auth_expired", "Access token expired. Please retry the command.", nil)
+		return nil, output.PrintError(ctx, "auth_expired", "Access token expired. Please retry the command.", nil)
 	}
 
 	if resp.StatusCode == 404 {
-		return nil, output.PrintError("not_found", "Resource not found", nil)
+		return nil, output.PrintError(ctx, "not_found", "Resource not found", nil)
 	}
 
 	if resp.StatusCode >= 400 {
@@ -497,15 +520,15 @@ func doRequest(token, reqURL string) ([]byte, error) {
 			} `json:"error"`
 		}
 		if json.Unmarshal(body, &errResp) == nil && errResp.Error.Message != "" {
-			return nil, output.PrintError("api_error", errResp.Error.Message, nil)
+			return nil, output.PrintError(ctx, "api_error", errResp.Error.Message, nil)
 		}
-		return nil, output.PrintError("api_error", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "api_error", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	return body, nil
 }
 
-func parseTrackSearch(data []byte) error {
+func parseTrackSearch(ctx context.Context, data []byte) error {
 	var resp struct {
 		Tracks struct {
 			Items []struct {
@@ -527,7 +550,7 @@ func parseTrackSearch(data []byte) error {
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
-		return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
+		return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
 	}
 
 	results := make([]TrackResult, 0, len(resp.Tracks.Items))
@@ -547,10 +570,10 @@ func parseTrackSearch(data []byte) error {
 		})
 	}
 
-	return output.Print(results)
+	return output.Print(ctx, results)
 }
 
-func parseArtistSearch(data []byte) error {
+func parseArtistSearch(ctx context.Context, data []byte) error {
 	var resp struct {
 		Artists struct {
 			Items []struct {
@@ -568,7 +591,7 @@ func parseArtistSearch(data []byte) error {
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
-		return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
+		return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
 	}
 
 	results := make([]ArtistResult, 0, len(resp.Artists.Items))
@@ -582,10 +605,10 @@ func parseArtistSearch(data []byte) error {
 		})
 	}
 
-	return output.Print(results)
+	return output.Print(ctx, results)
 }
 
-func parseAlbumSearch(data []byte) error {
+func parseAlbumSearch(ctx context.Context, data []byte) error {
 	var resp struct {
 		Albums struct {
 			Items []struct {
@@ -603,7 +626,7 @@ func parseAlbumSearch(data []byte) error {
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
-		return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
+		return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
 	}
 
 	results := make([]AlbumResult, 0, len(resp.Albums.Items))
@@ -621,10 +644,10 @@ func parseAlbumSearch(data []byte) error {
 		})
 	}
 
-	return output.Print(results)
+	return output.Print(ctx, results)
 }
 
-func parsePlaylistSearch(data []byte) error {
+func parsePlaylistSearch(ctx context.Context, data []byte) error {
 	var resp struct {
 		Playlists struct {
 			Items []struct {
@@ -644,7 +667,7 @@ func parsePlaylistSearch(data []byte) error {
 	}
 
 	if err := json.Unmarshal(data, &resp); err != nil {
-		return output.PrintError("parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
+		return output.PrintError(ctx, "parse_failed", fmt.Sprintf("Failed to parse response: %s", err.Error()), nil)
 	}
 
 	type PlaylistResult struct {
@@ -666,7 +689,7 @@ func parsePlaylistSearch(data []byte) error {
 		})
 	}
 
-	return output.Print(results)
+	return output.Print(ctx, results)
 }
 
 func formatDuration(ms int) string {
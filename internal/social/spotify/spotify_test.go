@@ -1,6 +1,7 @@
 package spotify
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -105,6 +106,7 @@ func TestGetToken(t *testing.T) {
 }
 
 func TestDoRequest(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check authorization header
 		if r.Header.Get("Authorization") != "Bearer test-token" {
@@ -139,7 +141,7 @@ func TestDoRequest(t *testing.T) {
 	apiBaseURL = srv.URL
 	defer func() { apiBaseURL = oldAPIURL }()
 
-	data, err := doRequest("test-token", srv.URL)
+	data, err := doRequest(ctx, "test-token", srv.URL)
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -157,6 +159,7 @@ func TestDoRequest(t *testing.T) {
 }
 
 func TestDoRequestUnauthorized(t *testing.T) {
+	ctx := context.Background()
 	// Reset cache
 	cachedToken = ""
 	tokenExpiry = time.Time{}
@@ -175,7 +178,7 @@ func TestDoRequestUnauthorized(t *testing.T) {
 	apiBaseURL = srv.URL
 	defer func() { apiBaseURL = oldAPIURL }()
 
-	_, err := doRequest("bad-token", srv.URL)
+	_, err := doRequest(ctx, "bad-token", srv.URL)
 	if err == nil {
 		t.Error("expected error for 401 response, got nil")
 	}
@@ -187,6 +190,7 @@ func TestDoRequestUnauthorized(t *testing.T) {
 }
 
 func TestDoRequestNotFound(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -201,7 +205,7 @@ func TestDoRequestNotFound(t *testing.T) {
 	apiBaseURL = srv.URL
 	defer func() { apiBaseURL = oldAPIURL }()
 
-	_, err := doRequest("test-token", srv.URL)
+	_, err := doRequest(ctx, "test-token", srv.URL)
 	if err == nil {
 		t.Error("expected error for 404 response, got nil")
 	}
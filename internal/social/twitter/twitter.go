@@ -13,11 +13,15 @@ import (
 	"net/url"
 	"os/exec"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -31,6 +35,22 @@ const (
 	defaultScopes = "tweet.read tweet.write users.read offline.access"
 )
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "twitter",
+		Name:        "Twitter/X",
+		Group:       "social",
+		Description: "Post tweets, delete tweets, get account info (free tier: 1,500 posts/month)",
+		Auth:        registry.AuthOAuth,
+		Keys: []registry.Key{
+			{Key: "x_client_id", Description: "OAuth 2.0 Client ID", Required: true},
+		},
+		SetupGuide:  "1. Go to https://developer.x.com/en/portal/dashboard\n2. Create a project and app (Free tier works)\n3. Enable OAuth 2.0, select 'Native App' type\n4. Set callback URL to http://127.0.0.1:8765/callback\n5. Copy the Client ID\n6. Run: pocket config set x_client_id <client-id>\n7. Run: pocket social twitter auth\n\nNote: Free tier allows posting tweets. Reading requires paid tier ($200/mo).",
+		TestCommand: "pocket social twitter me",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "twitter",
@@ -56,15 +76,23 @@ type xClient struct {
 	httpClient *http.Client
 }
 
-func newXClient() (*xClient, error) {
-	clientID, err := config.MustGet("x_client_id")
+// refreshMu serializes token refreshes. X rotates refresh tokens, so two
+// requests in one process (pocket serve, batch) refreshing at once would
+// leave one of them, and possibly the saved config, with a spent token.
+var refreshMu sync.Mutex
+
+func newXClient(ctx context.Context) (*xClient, error) {
+	clientID, err := config.MustGet(ctx, "x_client_id")
 	if err != nil {
 		return nil, err
 	}
 
-	accessToken, _ := config.Get("x_access_token")
-	refreshToken, _ := config.Get("x_refresh_token")
-	expiryStr, _ := config.Get("x_token_expiry")
+	// Read under the lock to pick up a refresh that finished meanwhile
+	refreshMu.Lock()
+	defer refreshMu.Unlock()
+	accessToken, _ := config.Get(ctx, "x_access_token")
+	refreshToken, _ := config.Get(ctx, "x_refresh_token")
+	expiryStr, _ := config.Get(ctx, "x_token_expiry")
 
 	// Check if token exists and is valid
 	if accessToken != "" && expiryStr != "" {
@@ -73,45 +101,45 @@ func newXClient() (*xClient, error) {
 			return &xClient{
 				clientID:   clientID,
 				token:      accessToken,
-				httpClient: &http.Client{Timeout: 30 * time.Second},
+				httpClient: transport.New(30 * time.Second),
 			}, nil
 		}
 	}
 
 	// Token expired or missing — try refresh
 	if refreshToken != "" {
-		newToken, err := refreshAccessToken(clientID, refreshToken)
+		newToken, err := refreshAccessToken(ctx, clientID, refreshToken)
 		if err == nil {
 			return &xClient{
 				clientID:   clientID,
 				token:      newToken,
-				httpClient: &http.Client{Timeout: 30 * time.Second},
+				httpClient: transport.New(30 * time.Second),
 			}, nil
 		}
 	}
 
-	return nil, output.PrintError("auth_required",
+	return nil, output.PrintError(ctx, "auth_required",
 		"X/Twitter OAuth not configured or expired. Run: pocket social twitter auth",
 		nil)
 }
 
-func refreshAccessToken(clientID, refreshToken string) (string, error) {
+func refreshAccessToken(ctx context.Context, clientID, refreshToken string) (string, error) {
 	data := url.Values{
 		"grant_type":    {"refresh_token"},
 		"refresh_token": {refreshToken},
 		"client_id":     {clientID},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, "POST", tokenEndpoint, strings.NewReader(data.Encode()))
+	req, err := http.NewRequestWithContext(dryrun.ReadOnly(ctx), "POST", tokenEndpoint, strings.NewReader(data.Encode()))
 	if err != nil {
 		return "", err
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+	resp, err := transport.New(30 * time.Second).Do(req)
 	if err != nil {
 		return "", err
 	}
@@ -137,15 +165,15 @@ func refreshAccessToken(clientID, refreshToken string) (string, error) {
 
 	// Store new tokens (X uses rotating refresh tokens)
 	expiry := time.Now().Add(time.Duration(tokenResp.ExpiresIn-60) * time.Second)
-	_ = config.Set("x_access_token", tokenResp.AccessToken)
-	_ = config.Set("x_refresh_token", tokenResp.RefreshToken)
-	_ = config.Set("x_token_expiry", expiry.Format(time.RFC3339))
+	_ = config.Set(ctx, "x_access_token", tokenResp.AccessToken)
+	_ = config.Set(ctx, "x_refresh_token", tokenResp.RefreshToken)
+	_ = config.Set(ctx, "x_token_expiry", expiry.Format(time.RFC3339))
 
 	return tokenResp.AccessToken, nil
 }
 
-func (c *xClient) doRequest(method, reqURL string, body any) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func (c *xClient) doRequest(ctx context.Context, method, reqURL string, body any) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	var reqBody io.Reader
@@ -228,9 +256,10 @@ func newAuthCmd() *cobra.Command {
 		Short: "Authenticate with X/Twitter using OAuth 2.0",
 		Long:  "Opens your browser to authorize Pocket CLI with your X account.\nRequires x_client_id to be configured first.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			clientID, err := config.MustGet("x_client_id")
+			ctx := cmd.Context()
+			clientID, err := config.MustGet(ctx, "x_client_id")
 			if err != nil {
-				return output.PrintError("setup_required",
+				return output.PrintError(ctx, "setup_required",
 					"x_client_id not configured. Set it first: pocket config set x_client_id <your-client-id>",
 					map[string]string{
 						"setup": "1. Go to https://developer.x.com/en/portal/dashboard\n2. Create or select your app\n3. Enable OAuth 2.0 and select 'Native App'\n4. Set callback URL to " + redirectURI + "\n5. Copy the Client ID",
@@ -239,12 +268,12 @@ func newAuthCmd() *cobra.Command {
 
 			verifier, err := generateCodeVerifier()
 			if err != nil {
-				return output.PrintError("auth_error", "Failed to generate PKCE verifier: "+err.Error(), nil)
+				return output.PrintError(ctx, "auth_error", "Failed to generate PKCE verifier: "+err.Error(), nil)
 			}
 
 			state, err := generateState()
 			if err != nil {
-				return output.PrintError("auth_error", "Failed to generate state: "+err.Error(), nil)
+				return output.PrintError(ctx, "auth_error", "Failed to generate state: "+err.Error(), nil)
 			}
 
 			challenge := generateCodeChallenge(verifier)
@@ -264,7 +293,7 @@ func newAuthCmd() *cobra.Command {
 			// Start local callback server
 			listener, err := net.Listen("tcp", "127.0.0.1:"+callbackPort)
 			if err != nil {
-				return output.PrintError("auth_error",
+				return output.PrintError(ctx, "auth_error",
 					fmt.Sprintf("Failed to start callback server on port %s: %s", callbackPort, err.Error()), nil)
 			}
 
@@ -310,10 +339,10 @@ func newAuthCmd() *cobra.Command {
 				// Success
 			case errMsg := <-errCh:
 				server.Close()
-				return output.PrintError("auth_denied", "Authorization failed: "+errMsg, nil)
+				return output.PrintError(ctx, "auth_denied", "Authorization failed: "+errMsg, nil)
 			case <-time.After(5 * time.Minute):
 				server.Close()
-				return output.PrintError("auth_timeout", "Authorization timed out after 5 minutes", nil)
+				return output.PrintError(ctx, "auth_timeout", "Authorization timed out after 5 minutes", nil)
 			}
 			server.Close()
 
@@ -326,28 +355,28 @@ func newAuthCmd() *cobra.Command {
 				"code_verifier": {verifier},
 			}
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			req, err := http.NewRequestWithContext(ctx, "POST", tokenEndpoint, strings.NewReader(tokenData.Encode()))
 			if err != nil {
-				return output.PrintError("auth_error", "Failed to create token request: "+err.Error(), nil)
+				return output.PrintError(ctx, "auth_error", "Failed to create token request: "+err.Error(), nil)
 			}
 			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-			resp, err := (&http.Client{Timeout: 30 * time.Second}).Do(req)
+			resp, err := transport.New(30 * time.Second).Do(req)
 			if err != nil {
-				return output.PrintError("auth_error", "Token exchange failed: "+err.Error(), nil)
+				return output.PrintErr(ctx, "auth_error", fmt.Errorf("Token exchange failed: %w", err), nil)
 			}
 			defer resp.Body.Close()
 
 			respBody, err := io.ReadAll(resp.Body)
 			if err != nil {
-				return output.PrintError("auth_error", "Failed to read token response: "+err.Error(), nil)
+				return output.PrintError(ctx, "auth_error", "Failed to read token response: "+err.Error(), nil)
 			}
 
 			if resp.StatusCode != 200 {
-				return output.PrintError("auth_error",
+				return output.PrintError(ctx, "auth_error",
 					fmt.Sprintf("Token exchange failed (HTTP %d): %s", resp.StatusCode, string(respBody)), nil)
 			}
 
@@ -358,16 +387,16 @@ func newAuthCmd() *cobra.Command {
 				Scope        string `json:"scope"`
 			}
 			if err := json.Unmarshal(respBody, &tokenResp); err != nil {
-				return output.PrintError("auth_error", "Failed to parse token response: "+err.Error(), nil)
+				return output.PrintError(ctx, "auth_error", "Failed to parse token response: "+err.Error(), nil)
 			}
 
 			// Store tokens
 			expiry := time.Now().Add(time.Duration(tokenResp.ExpiresIn-60) * time.Second)
-			_ = config.Set("x_access_token", tokenResp.AccessToken)
-			_ = config.Set("x_refresh_token", tokenResp.RefreshToken)
-			_ = config.Set("x_token_expiry", expiry.Format(time.RFC3339))
+			_ = config.Set(ctx, "x_access_token", tokenResp.AccessToken)
+			_ = config.Set(ctx, "x_refresh_token", tokenResp.RefreshToken)
+			_ = config.Set(ctx, "x_token_expiry", expiry.Format(time.RFC3339))
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"status": "authenticated",
 				"scopes": tokenResp.Scope,
 				"expiry": expiry.Format(time.RFC3339),
@@ -383,10 +412,11 @@ func newPostCmd() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "post [message]",
-		Short: "Post a tweet",
+		Short: "Post a tweet (free tier: 1,500/mo)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newXClient()
+			ctx := cmd.Context()
+			client, err := newXClient(ctx)
 			if err != nil {
 				return err
 			}
@@ -401,9 +431,9 @@ func newPostCmd() *cobra.Command {
 				}
 			}
 
-			respBody, err := client.doRequest("POST", tweetEndpoint, payload)
+			respBody, err := client.doRequest(ctx, "POST", tweetEndpoint, payload)
 			if err != nil {
-				return output.PrintError("post_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "post_failed", err, nil)
 			}
 
 			var result struct {
@@ -413,10 +443,10 @@ func newPostCmd() *cobra.Command {
 				} `json:"data"`
 			}
 			if err := json.Unmarshal(respBody, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"id":   result.Data.ID,
 				"text": result.Data.Text,
 				"url":  fmt.Sprintf("https://x.com/i/status/%s", result.Data.ID),
@@ -435,18 +465,19 @@ func newDeleteCmd() *cobra.Command {
 		Short: "Delete a tweet",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newXClient()
+			ctx := cmd.Context()
+			client, err := newXClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			deleteURL := fmt.Sprintf("%s/%s", tweetEndpoint, args[0])
-			_, err = client.doRequest("DELETE", deleteURL, nil)
+			_, err = client.doRequest(ctx, "DELETE", deleteURL, nil)
 			if err != nil {
-				return output.PrintError("delete_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "delete_failed", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"deleted": true,
 				"id":      args[0],
 			})
@@ -461,15 +492,16 @@ func newMeCmd() *cobra.Command {
 		Use:   "me",
 		Short: "Get your account info",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newXClient()
+			ctx := cmd.Context()
+			client, err := newXClient(ctx)
 			if err != nil {
 				return err
 			}
 
 			meURL := userEndpoint + "/me?user.fields=id,name,username,description,public_metrics,profile_image_url,created_at"
-			respBody, err := client.doRequest("GET", meURL, nil)
+			respBody, err := client.doRequest(ctx, "GET", meURL, nil)
 			if err != nil {
-				return output.PrintError("request_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "request_failed", err, nil)
 			}
 
 			var result struct {
@@ -488,10 +520,10 @@ func newMeCmd() *cobra.Command {
 				} `json:"data"`
 			}
 			if err := json.Unmarshal(respBody, &result); err != nil {
-				return output.PrintError("parse_error", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"id":          result.Data.ID,
 				"name":        result.Data.Name,
 				"username":    result.Data.Username,
@@ -514,7 +546,8 @@ func newTimelineCmd() *cobra.Command {
 		Use:   "timeline",
 		Short: "Get home timeline (requires paid tier)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return output.PrintError("paid_tier_required",
+			ctx := cmd.Context()
+			return output.PrintError(ctx, "paid_tier_required",
 				"Timeline access requires X API Basic tier ($200/month) or higher. Free tier only supports posting tweets.",
 				map[string]string{
 					"pricing": "https://developer.x.com/en/products/twitter-api",
@@ -531,7 +564,8 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search tweets (requires paid tier)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return output.PrintError("paid_tier_required",
+			ctx := cmd.Context()
+			return output.PrintError(ctx, "paid_tier_required",
 				"Search access requires X API Basic tier ($200/month) or higher. Free tier only supports posting tweets.",
 				map[string]string{
 					"pricing": "https://developer.x.com/en/products/twitter-api",
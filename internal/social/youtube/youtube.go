@@ -13,12 +13,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://www.googleapis.com/youtube/v3"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Video is LLM-friendly video output
 type Video struct {
@@ -59,6 +61,22 @@ type Comment struct {
 	Replies   int64  `json:"replies,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "youtube",
+		Name:        "YouTube",
+		Group:       "social",
+		Description: "Search videos, get channel info, video metrics, comments, and trending",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "youtube_api_key", Description: "YouTube Data API v3 key", Required: true, Example: "AIzaSy..."},
+		},
+		SetupGuide:  "1. Go to https://console.cloud.google.com/\n2. Create a new project (or select existing)\n3. Enable \"YouTube Data API v3\" at:\n   https://console.cloud.google.com/apis/library/youtube.googleapis.com\n4. Go to Credentials > Create Credentials > API Key\n5. (Optional) Restrict key to YouTube Data API v3\n6. Copy the API key\n7. Run: pocket config set youtube_api_key <your-api-key>\n\nNote: Free tier allows ~10,000 units/day (search=100, video=1, channel=1)",
+		TestCommand: "pocket social youtube trending -l 1",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "youtube",
@@ -88,7 +106,8 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search for videos",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			apiKey, err := getAPIKey()
+			ctx := cmd.Context()
+			apiKey, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -121,7 +140,7 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			reqURL := fmt.Sprintf("%s/search?%s", baseURL, params.Encode())
-			data, err := doRequest(reqURL)
+			data, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -147,7 +166,7 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			var videos []Video
@@ -165,7 +184,7 @@ func newSearchCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(videos)
+			return output.Print(ctx, videos)
 		},
 	}
 
@@ -184,7 +203,8 @@ func newVideoCmd() *cobra.Command {
 		Short: "Get video details",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			apiKey, err := getAPIKey()
+			ctx := cmd.Context()
+			apiKey, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -198,7 +218,7 @@ func newVideoCmd() *cobra.Command {
 			}
 
 			reqURL := fmt.Sprintf("%s/videos?%s", baseURL, params.Encode())
-			data, err := doRequest(reqURL)
+			data, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -230,11 +250,11 @@ func newVideoCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			if len(resp.Items) == 0 {
-				return output.PrintError("not_found", "Video not found: "+videoID, nil)
+				return output.PrintError(ctx, "not_found", "Video not found: "+videoID, nil)
 			}
 
 			item := resp.Items[0]
@@ -253,7 +273,7 @@ func newVideoCmd() *cobra.Command {
 				URL:         "https://youtube.com/watch?v=" + item.ID,
 			}
 
-			return output.Print(video)
+			return output.Print(ctx, video)
 		},
 	}
 
@@ -266,7 +286,8 @@ func newChannelCmd() *cobra.Command {
 		Short: "Get channel info",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			apiKey, err := getAPIKey()
+			ctx := cmd.Context()
+			apiKey, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -288,7 +309,7 @@ func newChannelCmd() *cobra.Command {
 			}
 
 			reqURL := fmt.Sprintf("%s/channels?%s", baseURL, params.Encode())
-			data, err := doRequest(reqURL)
+			data, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -316,11 +337,11 @@ func newChannelCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			if len(resp.Items) == 0 {
-				return output.PrintError("not_found", "Channel not found: "+channelArg, nil)
+				return output.PrintError(ctx, "not_found", "Channel not found: "+channelArg, nil)
 			}
 
 			item := resp.Items[0]
@@ -337,7 +358,7 @@ func newChannelCmd() *cobra.Command {
 				URL:         "https://youtube.com/channel/" + item.ID,
 			}
 
-			return output.Print(channel)
+			return output.Print(ctx, channel)
 		},
 	}
 
@@ -353,7 +374,8 @@ func newVideosCmd() *cobra.Command {
 		Short: "List videos from a channel",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			apiKey, err := getAPIKey()
+			ctx := cmd.Context()
+			apiKey, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -368,7 +390,7 @@ func newVideosCmd() *cobra.Command {
 			}
 
 			reqURL := fmt.Sprintf("%s/channels?%s", baseURL, params.Encode())
-			data, err := doRequest(reqURL)
+			data, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -384,11 +406,11 @@ func newVideosCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &channelResp); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			if len(channelResp.Items) == 0 {
-				return output.PrintError("not_found", "Channel not found: "+channelID, nil)
+				return output.PrintError(ctx, "not_found", "Channel not found: "+channelID, nil)
 			}
 
 			uploadsPlaylist := channelResp.Items[0].ContentDetails.RelatedPlaylists.Uploads
@@ -402,7 +424,7 @@ func newVideosCmd() *cobra.Command {
 			}
 
 			reqURL = fmt.Sprintf("%s/playlistItems?%s", baseURL, params.Encode())
-			data, err = doRequest(reqURL)
+			data, err = doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -428,7 +450,7 @@ func newVideosCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &playlistResp); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			// Get video IDs to fetch statistics
@@ -453,7 +475,7 @@ func newVideosCmd() *cobra.Command {
 				}
 
 				reqURL = fmt.Sprintf("%s/videos?%s", baseURL, params.Encode())
-				data, err = doRequest(reqURL)
+				data, err = doRequest(ctx, reqURL)
 				if err == nil {
 					var statsResp struct {
 						Items []struct {
@@ -506,7 +528,7 @@ func newVideosCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(videos)
+			return output.Print(ctx, videos)
 		},
 	}
 
@@ -525,7 +547,8 @@ func newCommentsCmd() *cobra.Command {
 		Short: "Get video comments",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			apiKey, err := getAPIKey()
+			ctx := cmd.Context()
+			apiKey, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -541,7 +564,7 @@ func newCommentsCmd() *cobra.Command {
 			}
 
 			reqURL := fmt.Sprintf("%s/commentThreads?%s", baseURL, params.Encode())
-			data, err := doRequest(reqURL)
+			data, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -563,7 +586,7 @@ func newCommentsCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			var comments []Comment
@@ -578,7 +601,7 @@ func newCommentsCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(comments)
+			return output.Print(ctx, comments)
 		},
 	}
 
@@ -597,7 +620,8 @@ func newTrendingCmd() *cobra.Command {
 		Use:   "trending",
 		Short: "Get trending videos",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			apiKey, err := getAPIKey()
+			ctx := cmd.Context()
+			apiKey, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -615,7 +639,7 @@ func newTrendingCmd() *cobra.Command {
 			}
 
 			reqURL := fmt.Sprintf("%s/videos?%s", baseURL, params.Encode())
-			data, err := doRequest(reqURL)
+			data, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -647,7 +671,7 @@ func newTrendingCmd() *cobra.Command {
 			}
 
 			if err := json.Unmarshal(data, &resp); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			var videos []Video
@@ -669,7 +693,7 @@ func newTrendingCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(videos)
+			return output.Print(ctx, videos)
 		},
 	}
 
@@ -680,10 +704,10 @@ func newTrendingCmd() *cobra.Command {
 	return cmd
 }
 
-func getAPIKey() (string, error) {
-	key, err := config.Get("youtube_api_key")
+func getAPIKey(ctx context.Context) (string, error) {
+	key, err := config.Get(ctx, "youtube_api_key")
 	if err != nil || key == "" {
-		return "", output.PrintError("setup_required", "YouTube API key not configured", map[string]any{
+		return "", output.PrintError(ctx, "setup_required", "YouTube API key not configured", map[string]any{
 			"missing":   []string{"youtube_api_key"},
 			"setup_cmd": "pocket setup show youtube",
 			"hint":      "Run 'pocket setup show youtube' for setup instructions",
@@ -692,36 +716,36 @@ func getAPIKey() (string, error) {
 	return key, nil
 }
 
-func doRequest(reqURL string) ([]byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, reqURL string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("request_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "request_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("request_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "request_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 403 {
-		return nil, output.PrintError("quota_exceeded", "YouTube API quota exceeded or invalid API key", map[string]any{
+		return nil, output.PrintError(ctx, "quota_exceeded", "YouTube API quota exceeded or invalid API key", map[string]any{
 			"hint": "Check your API key or wait for quota reset",
 		})
 	}
 
 	if resp.StatusCode >= 400 {
-		return nil, output.PrintError("request_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "request_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	data, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, output.PrintError("read_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "read_failed", err, nil)
 	}
 
 	return data, nil
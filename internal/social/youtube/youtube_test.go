@@ -1,6 +1,7 @@
 package youtube
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -178,6 +179,7 @@ func TestCleanHTML(t *testing.T) {
 }
 
 func TestDoRequest(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check User-Agent
 		if r.Header.Get("User-Agent") != "Pocket-CLI/1.0" {
@@ -205,7 +207,7 @@ func TestDoRequest(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	data, err := doRequest(srv.URL)
+	data, err := doRequest(ctx, srv.URL)
 	if err != nil {
 		t.Fatalf("doRequest failed: %v", err)
 	}
@@ -229,6 +231,7 @@ func TestDoRequest(t *testing.T) {
 }
 
 func TestDoRequestQuotaExceeded(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusForbidden)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -243,13 +246,14 @@ func TestDoRequestQuotaExceeded(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest(srv.URL)
+	_, err := doRequest(ctx, srv.URL)
 	if err == nil {
 		t.Error("expected error for 403 response, got nil")
 	}
 }
 
 func TestDoRequestNotFound(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 		json.NewEncoder(w).Encode(map[string]any{
@@ -264,7 +268,7 @@ func TestDoRequestNotFound(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	_, err := doRequest(srv.URL)
+	_, err := doRequest(ctx, srv.URL)
 	if err == nil {
 		t.Error("expected error for 404 response, got nil")
 	}
@@ -2,6 +2,7 @@ package battery
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -42,6 +44,16 @@ type Health struct {
 	HasBattery     bool    `json:"has_battery"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "battery",
+		Name:        "Battery",
+		Group:       "system",
+		Description: "Battery status, health and cycle count",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "battery",
@@ -60,7 +72,8 @@ func newStatusCmd() *cobra.Command {
 		Use:   "status",
 		Short: "Current battery charge and state",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return getStatus()
+			ctx := cmd.Context()
+			return getStatus(ctx)
 		},
 	}
 }
@@ -70,47 +83,48 @@ func newHealthCmd() *cobra.Command {
 		Use:   "health",
 		Short: "Battery health, cycle count, and capacity",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return getHealth()
+			ctx := cmd.Context()
+			return getHealth(ctx)
 		},
 	}
 }
 
-func getStatus() error {
+func getStatus(ctx context.Context) error {
 	switch runtime.GOOS {
 	case "darwin":
-		return getStatusDarwin()
+		return getStatusDarwin(ctx)
 	case "linux":
-		return getStatusLinux()
+		return getStatusLinux(ctx)
 	default:
-		return output.PrintError("platform_unsupported",
+		return output.PrintError(ctx, "platform_unsupported",
 			fmt.Sprintf("battery status not supported on %s", runtime.GOOS),
 			map[string]string{"supported": "macOS, Linux"})
 	}
 }
 
-func getHealth() error {
+func getHealth(ctx context.Context) error {
 	switch runtime.GOOS {
 	case "darwin":
-		return getHealthDarwin()
+		return getHealthDarwin(ctx)
 	case "linux":
-		return getHealthLinux()
+		return getHealthLinux(ctx)
 	default:
-		return output.PrintError("platform_unsupported",
+		return output.PrintError(ctx, "platform_unsupported",
 			fmt.Sprintf("battery health not supported on %s", runtime.GOOS),
 			map[string]string{"supported": "macOS, Linux"})
 	}
 }
 
 // macOS implementations
-func getStatusDarwin() error {
+func getStatusDarwin(ctx context.Context) error {
 	out, err := exec.Command("pmset", "-g", "batt").Output()
 	if err != nil {
-		return output.PrintError("battery_error",
+		return output.PrintError(ctx, "battery_error",
 			fmt.Sprintf("pmset failed: %v", err), nil)
 	}
 
 	status := parsePmset(string(out))
-	return output.Print(status)
+	return output.Print(ctx, status)
 }
 
 //nolint:gocyclo // complex but clear sequential logic
@@ -166,15 +180,15 @@ func parsePmset(data string) Status {
 	return status
 }
 
-func getHealthDarwin() error {
+func getHealthDarwin(ctx context.Context) error {
 	out, err := exec.Command("system_profiler", "SPPowerDataType").Output()
 	if err != nil {
-		return output.PrintError("battery_error",
+		return output.PrintError(ctx, "battery_error",
 			fmt.Sprintf("system_profiler failed: %v", err), nil)
 	}
 
 	health := parseSystemProfilerPower(string(out))
-	return output.Print(health)
+	return output.Print(ctx, health)
 }
 
 //nolint:gocyclo // complex but clear sequential logic
@@ -231,7 +245,7 @@ func parseSystemProfilerPower(data string) Health {
 }
 
 // Linux implementations
-func getStatusLinux() error {
+func getStatusLinux(ctx context.Context) error {
 	status := Status{}
 
 	// Check if battery exists
@@ -239,7 +253,7 @@ func getStatusLinux() error {
 	if _, err := os.Stat(batPath); os.IsNotExist(err) {
 		batPath = "/sys/class/power_supply/BAT1"
 		if _, err := os.Stat(batPath); os.IsNotExist(err) {
-			return output.Print(Status{HasBattery: false})
+			return output.Print(ctx, Status{HasBattery: false})
 		}
 	}
 
@@ -262,18 +276,18 @@ func getStatusLinux() error {
 		}
 	}
 
-	return output.Print(status)
+	return output.Print(ctx, status)
 }
 
 //nolint:gocyclo // complex but clear sequential logic
-func getHealthLinux() error {
+func getHealthLinux(ctx context.Context) error {
 	health := Health{}
 
 	batPath := "/sys/class/power_supply/BAT0"
 	if _, err := os.Stat(batPath); os.IsNotExist(err) {
 		batPath = "/sys/class/power_supply/BAT1"
 		if _, err := os.Stat(batPath); os.IsNotExist(err) {
-			return output.Print(Health{HasBattery: false})
+			return output.Print(ctx, Health{HasBattery: false})
 		}
 	}
 
@@ -324,5 +338,5 @@ func getHealthLinux() error {
 		}
 	}
 
-	return output.Print(health)
+	return output.Print(ctx, health)
 }
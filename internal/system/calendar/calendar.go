@@ -1,6 +1,7 @@
 package calendar
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"runtime"
@@ -9,6 +10,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -32,9 +35,9 @@ type Event struct {
 }
 
 // checkPlatform returns an error if not running on macOS
-func checkPlatform() error {
+func checkPlatform(ctx context.Context) error {
 	if runtime.GOOS != "darwin" {
-		return output.PrintError(
+		return output.PrintError(ctx,
 			"unsupported_platform",
 			"Apple Calendar integration is only available on macOS",
 			map[string]any{
@@ -88,6 +91,16 @@ func parseDateTime(s string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("unable to parse date/time: %s", s)
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "apple-calendar",
+		Name:        "Apple Calendar",
+		Group:       "system",
+		Description: "Manage Apple Calendar events via AppleScript (macOS only)",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the Apple Calendar command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -114,7 +127,8 @@ func newCalendarsCmd() *cobra.Command {
 		Use:   "calendars",
 		Short: "List all calendars",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkPlatform(); err != nil {
+			ctx := cmd.Context()
+			if err := checkPlatform(ctx); err != nil {
 				return err
 			}
 
@@ -129,7 +143,7 @@ end tell
 `
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", "Failed to list calendars", map[string]any{
+				return output.PrintError(ctx, "applescript_error", "Failed to list calendars", map[string]any{
 					"error": err.Error(),
 				})
 			}
@@ -147,7 +161,7 @@ end tell
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"calendars": calendars,
 				"count":     len(calendars),
 			})
@@ -165,7 +179,8 @@ func newTodayCmd() *cobra.Command {
 		Use:   "today",
 		Short: "Show today's events",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkPlatform(); err != nil {
+			ctx := cmd.Context()
+			if err := checkPlatform(ctx); err != nil {
 				return err
 			}
 
@@ -173,7 +188,7 @@ func newTodayCmd() *cobra.Command {
 			startOfDay := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
 			endOfDay := startOfDay.Add(24 * time.Hour)
 
-			return fetchEvents(calendarName, startOfDay, endOfDay, "today")
+			return fetchEvents(ctx, calendarName, startOfDay, endOfDay, "today")
 		},
 	}
 
@@ -192,7 +207,8 @@ func newEventsCmd() *cobra.Command {
 		Short: "List events",
 		Long:  "List events from calendars. By default shows events for the next 7 days.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkPlatform(); err != nil {
+			ctx := cmd.Context()
+			if err := checkPlatform(ctx); err != nil {
 				return err
 			}
 
@@ -200,7 +216,7 @@ func newEventsCmd() *cobra.Command {
 			startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 			endDate := startOfDay.Add(time.Duration(days) * 24 * time.Hour)
 
-			return fetchEvents(calendarName, startOfDay, endDate, fmt.Sprintf("next %d days", days))
+			return fetchEvents(ctx, calendarName, startOfDay, endDate, fmt.Sprintf("next %d days", days))
 		},
 	}
 
@@ -219,7 +235,8 @@ func newEventCmd() *cobra.Command {
 		Short: "Get event details by title",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkPlatform(); err != nil {
+			ctx := cmd.Context()
+			if err := checkPlatform(ctx); err != nil {
 				return err
 			}
 
@@ -258,7 +275,7 @@ end tell
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", "Failed to search for event", map[string]any{
+				return output.PrintError(ctx, "applescript_error", "Failed to search for event", map[string]any{
 					"error": err.Error(),
 					"title": title,
 				})
@@ -267,14 +284,14 @@ end tell
 			events := parseEventResults(result)
 
 			if len(events) == 0 {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"events":  events,
 					"count":   0,
 					"message": fmt.Sprintf("No events found matching '%s'", title),
 				})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"events": events,
 				"count":  len(events),
 			})
@@ -300,7 +317,8 @@ func newCreateCmd() *cobra.Command {
 		Short: "Create a new event",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkPlatform(); err != nil {
+			ctx := cmd.Context()
+			if err := checkPlatform(ctx); err != nil {
 				return err
 			}
 
@@ -309,7 +327,7 @@ func newCreateCmd() *cobra.Command {
 			// Parse start time
 			start, err := parseDateTime(startTime)
 			if err != nil {
-				return output.PrintError("invalid_start_time", "Failed to parse start time", map[string]any{
+				return output.PrintError(ctx, "invalid_start_time", "Failed to parse start time", map[string]any{
 					"input": startTime,
 					"error": err.Error(),
 					"hint":  "Use format: 2006-01-02 15:04 or 2006-01-02T15:04:05",
@@ -319,7 +337,7 @@ func newCreateCmd() *cobra.Command {
 			// Parse end time
 			end, err := parseDateTime(endTime)
 			if err != nil {
-				return output.PrintError("invalid_end_time", "Failed to parse end time", map[string]any{
+				return output.PrintError(ctx, "invalid_end_time", "Failed to parse end time", map[string]any{
 					"input": endTime,
 					"error": err.Error(),
 					"hint":  "Use format: 2006-01-02 15:04 or 2006-01-02T15:04:05",
@@ -337,7 +355,7 @@ end tell
 `
 				result, err := runAppleScript(listScript)
 				if err != nil {
-					return output.PrintError("no_calendar", "Failed to find a calendar", map[string]any{
+					return output.PrintError(ctx, "no_calendar", "Failed to find a calendar", map[string]any{
 						"error": err.Error(),
 						"hint":  "Specify a calendar with --calendar flag",
 					})
@@ -382,14 +400,18 @@ end tell
 				allDayPart,
 			)
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, script)
+			}
+
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("create_failed", "Failed to create event", map[string]any{
+				return output.PrintError(ctx, "create_failed", "Failed to create event", map[string]any{
 					"error": err.Error(),
 				})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success":  true,
 				"message":  "Event created successfully",
 				"title":    result,
@@ -423,7 +445,8 @@ func newUpcomingCmd() *cobra.Command {
 		Use:   "upcoming",
 		Short: "Show next 7 days of events",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkPlatform(); err != nil {
+			ctx := cmd.Context()
+			if err := checkPlatform(ctx); err != nil {
 				return err
 			}
 
@@ -431,7 +454,7 @@ func newUpcomingCmd() *cobra.Command {
 			startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 			endDate := startOfDay.Add(7 * 24 * time.Hour)
 
-			return fetchEvents(calendarName, startOfDay, endDate, "upcoming 7 days")
+			return fetchEvents(ctx, calendarName, startOfDay, endDate, "upcoming 7 days")
 		},
 	}
 
@@ -448,7 +471,8 @@ func newWeekCmd() *cobra.Command {
 		Use:   "week",
 		Short: "Show this week's events",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := checkPlatform(); err != nil {
+			ctx := cmd.Context()
+			if err := checkPlatform(ctx); err != nil {
 				return err
 			}
 
@@ -458,7 +482,7 @@ func newWeekCmd() *cobra.Command {
 			startOfWeek := time.Date(now.Year(), now.Month(), now.Day()-weekday, 0, 0, 0, 0, now.Location())
 			endOfWeek := startOfWeek.Add(7 * 24 * time.Hour)
 
-			return fetchEvents(calendarName, startOfWeek, endOfWeek, "this week")
+			return fetchEvents(ctx, calendarName, startOfWeek, endOfWeek, "this week")
 		},
 	}
 
@@ -468,7 +492,7 @@ func newWeekCmd() *cobra.Command {
 }
 
 // fetchEvents fetches events within a date range
-func fetchEvents(calendarName string, startDate, endDate time.Time, period string) error {
+func fetchEvents(ctx context.Context, calendarName string, startDate, endDate time.Time, period string) error {
 	// Calculate offset from current date for AppleScript
 	now := time.Now()
 	startOffset := int(startDate.Sub(now).Hours() / 24)
@@ -511,7 +535,7 @@ end tell
 
 	result, err := runAppleScript(script)
 	if err != nil {
-		return output.PrintError("applescript_error", "Failed to fetch events", map[string]any{
+		return output.PrintError(ctx, "applescript_error", "Failed to fetch events", map[string]any{
 			"error":  err.Error(),
 			"period": period,
 		})
@@ -519,7 +543,7 @@ end tell
 
 	events := parseEventResults(result)
 
-	return output.Print(map[string]any{
+	return output.Print(ctx, map[string]any{
 		"events":     events,
 		"count":      len(events),
 		"period":     period,
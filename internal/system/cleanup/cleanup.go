@@ -1,6 +1,7 @@
 package cleanup
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -42,6 +44,16 @@ type DirEntry struct {
 
 const maxFiles = 100000 // Safety cap for directory walking
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "cleanup",
+		Name:        "Cleanup Scan",
+		Group:       "system",
+		Description: "Sizes of caches, temp files and logs (read-only, never deletes)",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "cleanup",
@@ -63,7 +75,8 @@ func newScanCmd() *cobra.Command {
 		Use:   "scan",
 		Short: "Full scan of all cleanable directories",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fullScan()
+			ctx := cmd.Context()
+			return fullScan(ctx)
 		},
 	}
 }
@@ -73,7 +86,8 @@ func newCachesCmd() *cobra.Command {
 		Use:   "caches",
 		Short: "Scan cache directories only",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return scanCategory("caches")
+			ctx := cmd.Context()
+			return scanCategory(ctx, "caches")
 		},
 	}
 }
@@ -83,7 +97,8 @@ func newLogsCmd() *cobra.Command {
 		Use:   "logs",
 		Short: "Scan log directories only",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return scanCategory("logs")
+			ctx := cmd.Context()
+			return scanCategory(ctx, "logs")
 		},
 	}
 }
@@ -93,12 +108,13 @@ func newTempCmd() *cobra.Command {
 		Use:   "temp",
 		Short: "Scan temp directories only",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return scanCategory("temp")
+			ctx := cmd.Context()
+			return scanCategory(ctx, "temp")
 		},
 	}
 }
 
-func fullScan() error {
+func fullScan(ctx context.Context) error {
 	categories := getAllCategories()
 
 	var totalSize float64
@@ -110,7 +126,7 @@ func fullScan() error {
 		totalItems += categories[i].Items
 	}
 
-	return output.Print(ScanResult{
+	return output.Print(ctx, ScanResult{
 		Categories:  categories,
 		TotalSizeMB: totalSize,
 		TotalItems:  totalItems,
@@ -119,17 +135,17 @@ func fullScan() error {
 	})
 }
 
-func scanCategory(name string) error {
+func scanCategory(ctx context.Context, name string) error {
 	categories := getAllCategories()
 
 	for i := range categories {
 		if categories[i].Name == name {
 			scanCategoryDirs(&categories[i])
-			return output.Print(categories[i])
+			return output.Print(ctx, categories[i])
 		}
 	}
 
-	return output.PrintError("not_found",
+	return output.PrintError(ctx, "not_found",
 		fmt.Sprintf("category '%s' not found", name),
 		map[string]string{"available": "caches, logs, temp"})
 }
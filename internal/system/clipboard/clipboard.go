@@ -2,6 +2,7 @@ package clipboard
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -11,6 +12,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -23,6 +26,16 @@ type Content struct {
 	Truncated bool   `json:"truncated,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "clipboard",
+		Name:        "Clipboard",
+		Group:       "system",
+		Description: "Get/set macOS clipboard content (macOS only)",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd creates the clipboard command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -31,8 +44,9 @@ func NewCmd() *cobra.Command {
 		Short:   "Clipboard commands (macOS only)",
 		Long:    `Interact with the system clipboard using pbcopy/pbpaste. Only available on macOS.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if runtime.GOOS != "darwin" {
-				return output.PrintError("platform_unsupported",
+				return output.PrintError(ctx, "platform_unsupported",
 					"Clipboard commands are only available on macOS",
 					map[string]string{
 						"current_platform": runtime.GOOS,
@@ -63,14 +77,15 @@ func newGetCmd() *cobra.Command {
 		Short: "Get current clipboard content",
 		Long:  `Retrieve the current text content from the system clipboard using pbpaste.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			content, err := getClipboard()
 			if err != nil {
-				return output.PrintError("clipboard_read_error", err.Error(), nil)
+				return output.PrintErr(ctx, "clipboard_read_error", err, nil)
 			}
 
 			// Check if content is valid UTF-8 text
 			if !utf8.ValidString(content) {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"is_text": false,
 					"message": "Clipboard contains non-text data (binary content)",
 					"hint":    "The clipboard may contain an image or other binary data",
@@ -78,7 +93,7 @@ func newGetCmd() *cobra.Command {
 			}
 
 			if content == "" {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"content": "",
 					"length":  0,
 					"lines":   0,
@@ -112,7 +127,7 @@ func newGetCmd() *cobra.Command {
 				result.Truncated = true
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -130,11 +145,12 @@ func newSetCmd() *cobra.Command {
 		Long:  `Set the system clipboard content using pbcopy. Pass the text as an argument.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			text := args[0]
 
-			err := setClipboard(text)
+			err := setClipboard(ctx, text)
 			if err != nil {
-				return output.PrintError("clipboard_write_error", err.Error(), nil)
+				return output.PrintErr(ctx, "clipboard_write_error", err, nil)
 			}
 
 			// Calculate line count
@@ -143,7 +159,7 @@ func newSetCmd() *cobra.Command {
 				lines--
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": "Clipboard content set successfully",
 				"length":  len(text),
@@ -162,12 +178,13 @@ func newClearCmd() *cobra.Command {
 		Short: "Clear clipboard content",
 		Long:  `Clear the system clipboard by setting it to an empty string.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			err := setClipboard("")
+			ctx := cmd.Context()
+			err := setClipboard(ctx, "")
 			if err != nil {
-				return output.PrintError("clipboard_clear_error", err.Error(), nil)
+				return output.PrintErr(ctx, "clipboard_clear_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": "Clipboard cleared",
 			})
@@ -185,23 +202,24 @@ func newCopyCmd() *cobra.Command {
 		Long:  `Read a file and copy its contents to the system clipboard.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			filePath := args[0]
 
 			// Read the file
 			content, err := os.ReadFile(filePath)
 			if err != nil {
 				if os.IsNotExist(err) {
-					return output.PrintError("file_not_found",
+					return output.PrintError(ctx, "file_not_found",
 						fmt.Sprintf("File not found: %s", filePath),
 						map[string]string{"path": filePath})
 				}
-				return output.PrintError("file_read_error", err.Error(),
+				return output.PrintErr(ctx, "file_read_error", err,
 					map[string]string{"path": filePath})
 			}
 
 			// Check if content is valid UTF-8 text
 			if !utf8.Valid(content) {
-				return output.PrintError("binary_content",
+				return output.PrintError(ctx, "binary_content",
 					"File contains binary (non-text) data",
 					map[string]any{
 						"path": filePath,
@@ -211,9 +229,9 @@ func newCopyCmd() *cobra.Command {
 			}
 
 			text := string(content)
-			err = setClipboard(text)
+			err = setClipboard(ctx, text)
 			if err != nil {
-				return output.PrintError("clipboard_write_error", err.Error(), nil)
+				return output.PrintErr(ctx, "clipboard_write_error", err, nil)
 			}
 
 			// Calculate line count
@@ -222,7 +240,7 @@ func newCopyCmd() *cobra.Command {
 				lines--
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": "File contents copied to clipboard",
 				"file":    filePath,
@@ -242,7 +260,8 @@ func newHistoryCmd() *cobra.Command {
 		Short: "Clipboard history information",
 		Long:  `macOS does not provide native clipboard history. This command provides information about alternatives.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return output.Print(map[string]any{
+			ctx := cmd.Context()
+			return output.Print(ctx, map[string]any{
 				"available": false,
 				"message":   "macOS does not have native clipboard history",
 				"alternatives": []map[string]string{
@@ -295,7 +314,11 @@ func getClipboard() (string, error) {
 }
 
 // setClipboard sets the clipboard content using pbcopy
-func setClipboard(text string) error {
+func setClipboard(ctx context.Context, text string) error {
+	if dryrun.Enabled(ctx) {
+		return dryrun.Record(ctx, dryrun.Request{Protocol: "exec", Command: "pbcopy", Body: text})
+	}
+
 	cmd := exec.Command("pbcopy")
 	cmd.Stdin = strings.NewReader(text)
 	var stderr bytes.Buffer
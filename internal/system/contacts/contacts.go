@@ -11,6 +11,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -67,6 +69,16 @@ type ContactSummary struct {
 	Company string `json:"company,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "contacts",
+		Name:        "Apple Contacts",
+		Group:       "system",
+		Description: "Search and manage Apple Contacts via AppleScript (macOS only)",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd creates the contacts command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -75,8 +87,9 @@ func NewCmd() *cobra.Command {
 		Short:   "Apple Contacts commands (macOS only)",
 		Long:    `Interact with Apple Contacts via AppleScript. Only available on macOS.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if runtime.GOOS != "darwin" {
-				return output.PrintError("platform_unsupported",
+				return output.PrintError(ctx, "platform_unsupported",
 					"Apple Contacts is only available on macOS",
 					map[string]string{
 						"current_platform": runtime.GOOS,
@@ -106,8 +119,8 @@ func escapeAppleScript(s string) string {
 
 // runOsascript executes an osascript command with a timeout and returns the output.
 // The lang parameter specifies the scripting language ("AppleScript" or "JavaScript").
-func runOsascript(lang string, script string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), appleScriptTimeout)
+func runOsascript(ctx context.Context, lang string, script string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, appleScriptTimeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, "osascript", "-l", lang, "-e", script)
@@ -131,13 +144,13 @@ func runOsascript(lang string, script string) (string, error) {
 }
 
 // runAppleScript executes an AppleScript with a timeout and returns the output
-func runAppleScript(script string) (string, error) {
-	return runOsascript("AppleScript", script)
+func runAppleScript(ctx context.Context, script string) (string, error) {
+	return runOsascript(ctx, "AppleScript", script)
 }
 
 // runJXA executes a JavaScript for Automation (JXA) script with a timeout
-func runJXA(script string) (string, error) {
-	return runOsascript("JavaScript", script)
+func runJXA(ctx context.Context, script string) (string, error) {
+	return runOsascript(ctx, "JavaScript", script)
 }
 
 // newListCmd lists all contacts
@@ -148,6 +161,7 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List all contacts",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			// Use JXA for fast batch property access instead of AppleScript's
 			// per-contact iteration which is extremely slow for large databases.
 			maxResults := limit
@@ -179,13 +193,13 @@ for (var i = 0; i < count; i++) {
 total + '~~~' + results.join(':::');
 `, maxResults)
 
-			result, err := runJXA(script)
+			result, err := runJXA(ctx, script)
 			if err != nil {
-				return output.PrintError("list_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "list_failed", err, nil)
 			}
 
 			if result == "" {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"contacts": []ContactSummary{},
 					"count":    0,
 					"total":    0,
@@ -203,7 +217,7 @@ total + '~~~' + results.join(':::');
 			}
 
 			if contactData == "" {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"contacts": []ContactSummary{},
 					"count":    0,
 					"total":    total,
@@ -229,7 +243,7 @@ total + '~~~' + results.join(':::');
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"contacts": contacts,
 				"count":    len(contacts),
 				"total":    total,
@@ -263,6 +277,7 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search contacts by name, email, or phone",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := args[0]
 
 			// Default limit for search to avoid unbounded results
@@ -339,13 +354,13 @@ for (var j = 0; j < matchIndices.length; j++) {
 results.join(':::');
 `, escapeJSString(query), maxResults)
 
-			result, err := runJXA(script)
+			result, err := runJXA(ctx, script)
 			if err != nil {
-				return output.PrintError("search_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "search_failed", err, nil)
 			}
 
 			if result == "" {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"query":    query,
 					"contacts": []ContactSummary{},
 					"count":    0,
@@ -372,7 +387,7 @@ results.join(':::');
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"query":    query,
 				"contacts": contacts,
 				"count":    len(contacts),
@@ -394,6 +409,7 @@ func newGetCmd() *cobra.Command {
 		Short: "Get full contact details by name",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			contactName := args[0]
 
 			script := fmt.Sprintf(`
@@ -478,25 +494,25 @@ tell application "Contacts"
 	end try
 end tell`, escapeAppleScript(contactName))
 
-			result, err := runAppleScript(script)
+			result, err := runAppleScript(ctx, script)
 			if err != nil {
-				return output.PrintError("get_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "get_failed", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
 				errMsg := strings.TrimPrefix(result, "ERROR: ")
 				if strings.Contains(errMsg, "Can't get person") {
-					return output.PrintError("contact_not_found",
+					return output.PrintError(ctx, "contact_not_found",
 						fmt.Sprintf("Contact not found: %s", contactName),
 						map[string]string{"name": contactName})
 				}
-				return output.PrintError("get_failed", errMsg, nil)
+				return output.PrintError(ctx, "get_failed", errMsg, nil)
 			}
 
 			// Parse the result
 			parts := strings.Split(result, "|||")
 			if len(parts) < 10 {
-				return output.PrintError("parse_failed", "Failed to parse contact data", nil)
+				return output.PrintError(ctx, "parse_failed", "Failed to parse contact data", nil)
 			}
 
 			contact := Contact{
@@ -573,7 +589,7 @@ end tell`, escapeAppleScript(contactName))
 				}
 			}
 
-			return output.Print(contact)
+			return output.Print(ctx, contact)
 		},
 	}
 
@@ -593,6 +609,7 @@ func newGroupsCmd() *cobra.Command {
 		Use:   "groups",
 		Short: "List all contact groups",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			script := `
 tell application "Contacts"
 	set groupList to {}
@@ -605,13 +622,13 @@ tell application "Contacts"
 	return groupList as text
 end tell`
 
-			result, err := runAppleScript(script)
+			result, err := runAppleScript(ctx, script)
 			if err != nil {
-				return output.PrintError("groups_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "groups_failed", err, nil)
 			}
 
 			if result == "" {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"groups": []Group{},
 					"count":  0,
 				})
@@ -632,7 +649,7 @@ end tell`
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"groups": groups,
 				"count":  len(groups),
 			})
@@ -651,6 +668,7 @@ func newGroupCmd() *cobra.Command {
 		Short: "List contacts in a specific group",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			groupName := args[0]
 
 			script := fmt.Sprintf(`
@@ -681,23 +699,23 @@ tell application "Contacts"
 	end try
 end tell`, escapeAppleScript(groupName))
 
-			result, err := runAppleScript(script)
+			result, err := runAppleScript(ctx, script)
 			if err != nil {
-				return output.PrintError("group_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "group_failed", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
 				errMsg := strings.TrimPrefix(result, "ERROR: ")
 				if strings.Contains(errMsg, "Can't get group") {
-					return output.PrintError("group_not_found",
+					return output.PrintError(ctx, "group_not_found",
 						fmt.Sprintf("Group not found: %s", groupName),
 						map[string]string{"name": groupName})
 				}
-				return output.PrintError("group_failed", errMsg, nil)
+				return output.PrintError(ctx, "group_failed", errMsg, nil)
 			}
 
 			if result == "" {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"group":    groupName,
 					"contacts": []ContactSummary{},
 					"count":    0,
@@ -724,7 +742,7 @@ end tell`, escapeAppleScript(groupName))
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"group":    groupName,
 				"contacts": contacts,
 				"count":    len(contacts),
@@ -750,6 +768,7 @@ func newCreateCmd() *cobra.Command {
 		Long:  `Create a new contact with the specified name. Optionally add email, phone, company, and notes.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			name := args[0]
 
 			// Parse name into first and last
@@ -802,13 +821,17 @@ tell application "Contacts"
 end tell
 `)
 
-			result, err := runAppleScript(scriptBuilder.String())
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, scriptBuilder.String())
+			}
+
+			result, err := runAppleScript(ctx, scriptBuilder.String())
 			if err != nil {
-				return output.PrintError("create_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "create_failed", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
-				return output.PrintError("create_failed", strings.TrimPrefix(result, "ERROR: "), nil)
+				return output.PrintError(ctx, "create_failed", strings.TrimPrefix(result, "ERROR: "), nil)
 			}
 
 			response := map[string]any{
@@ -829,7 +852,7 @@ end tell
 				response["note"] = note
 			}
 
-			return output.Print(response)
+			return output.Print(ctx, response)
 		},
 	}
 
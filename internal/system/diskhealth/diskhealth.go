@@ -2,6 +2,7 @@ package diskhealth
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os/exec"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -48,6 +50,16 @@ type PartitionEntry struct {
 	Size       string `json:"size,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "diskhealth",
+		Name:        "Disk Health",
+		Group:       "system",
+		Description: "Disk health and S.M.A.R.T. status",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "diskhealth",
@@ -66,7 +78,8 @@ func newStatusCmd() *cobra.Command {
 		Use:   "status",
 		Short: "Show disk health status overview",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return getStatus()
+			ctx := cmd.Context()
+			return getStatus(ctx)
 		},
 	}
 }
@@ -78,59 +91,60 @@ func newInfoCmd() *cobra.Command {
 		Long:  "Get detailed info for a specific disk. Use 'disk0' on macOS or '/dev/sda' on Linux.",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			disk := ""
 			if len(args) > 0 {
 				disk = args[0]
 			}
-			return getInfo(disk)
+			return getInfo(ctx, disk)
 		},
 	}
 }
 
-func getStatus() error {
+func getStatus(ctx context.Context) error {
 	switch runtime.GOOS {
 	case "darwin":
-		return getStatusDarwin()
+		return getStatusDarwin(ctx)
 	case "linux":
-		return getStatusLinux()
+		return getStatusLinux(ctx)
 	default:
-		return output.PrintError("platform_unsupported",
+		return output.PrintError(ctx, "platform_unsupported",
 			fmt.Sprintf("disk health not supported on %s", runtime.GOOS),
 			map[string]string{"supported": "macOS, Linux"})
 	}
 }
 
-func getInfo(disk string) error {
+func getInfo(ctx context.Context, disk string) error {
 	switch runtime.GOOS {
 	case "darwin":
 		if disk == "" {
 			disk = "disk0"
 		}
-		return getInfoDarwin(disk)
+		return getInfoDarwin(ctx, disk)
 	case "linux":
 		if disk == "" {
 			disk = "/dev/sda"
 		}
-		return getInfoLinux(disk)
+		return getInfoLinux(ctx, disk)
 	default:
-		return output.PrintError("platform_unsupported",
+		return output.PrintError(ctx, "platform_unsupported",
 			fmt.Sprintf("disk info not supported on %s", runtime.GOOS),
 			map[string]string{"supported": "macOS, Linux"})
 	}
 }
 
 // macOS implementations
-func getStatusDarwin() error {
+func getStatusDarwin(ctx context.Context) error {
 	out, err := exec.Command("system_profiler", "SPStorageDataType", "-json").Output()
 	if err != nil {
-		return output.PrintError("diskhealth_error",
+		return output.PrintError(ctx, "diskhealth_error",
 			fmt.Sprintf("system_profiler failed: %v", err), nil)
 	}
 
 	var spData map[string]any
 	if err := json.Unmarshal(out, &spData); err != nil {
 		// Fallback to diskutil
-		return getStatusDarwinDiskutil()
+		return getStatusDarwinDiskutil(ctx)
 	}
 
 	var disks []DiskEntry
@@ -162,17 +176,17 @@ func getStatusDarwin() error {
 	}
 
 	if len(disks) == 0 {
-		return getStatusDarwinDiskutil()
+		return getStatusDarwinDiskutil(ctx)
 	}
 
-	return output.Print(DiskStatus{Disks: disks})
+	return output.Print(ctx, DiskStatus{Disks: disks})
 }
 
 //nolint:gocyclo // complex but clear sequential logic
-func getStatusDarwinDiskutil() error {
+func getStatusDarwinDiskutil(ctx context.Context) error {
 	out, err := exec.Command("diskutil", "list").Output()
 	if err != nil {
-		return output.PrintError("diskhealth_error",
+		return output.PrintError(ctx, "diskhealth_error",
 			fmt.Sprintf("diskutil failed: %v", err), nil)
 	}
 
@@ -234,14 +248,14 @@ func getStatusDarwinDiskutil() error {
 		}
 	}
 
-	return output.Print(DiskStatus{Disks: disks})
+	return output.Print(ctx, DiskStatus{Disks: disks})
 }
 
 //nolint:gocyclo // complex but clear sequential logic
-func getInfoDarwin(disk string) error {
+func getInfoDarwin(ctx context.Context, disk string) error {
 	out, err := exec.Command("diskutil", "info", disk).Output()
 	if err != nil {
-		return output.PrintError("diskhealth_error",
+		return output.PrintError(ctx, "diskhealth_error",
 			fmt.Sprintf("diskutil info failed for %s: %v", disk, err), nil)
 	}
 
@@ -299,14 +313,14 @@ func getInfoDarwin(disk string) error {
 		}
 	}
 
-	return output.Print(detail)
+	return output.Print(ctx, detail)
 }
 
 // Linux implementations
-func getStatusLinux() error {
+func getStatusLinux(ctx context.Context) error {
 	out, err := exec.Command("lsblk", "-Jdo", "NAME,SIZE,TYPE,TRAN,MODEL").Output()
 	if err != nil {
-		return output.PrintError("diskhealth_error",
+		return output.PrintError(ctx, "diskhealth_error",
 			fmt.Sprintf("lsblk failed: %v", err),
 			map[string]string{"suggestion": "Ensure lsblk is available"})
 	}
@@ -322,7 +336,7 @@ func getStatusLinux() error {
 	}
 
 	if err := json.Unmarshal(out, &lsblkData); err != nil {
-		return output.PrintError("parse_error", fmt.Sprintf("failed to parse lsblk: %v", err), nil)
+		return output.PrintError(ctx, "parse_error", fmt.Sprintf("failed to parse lsblk: %v", err), nil)
 	}
 
 	disks := make([]DiskEntry, 0, len(lsblkData.Blockdevices))
@@ -358,15 +372,15 @@ func getStatusLinux() error {
 		disks = append(disks, entry)
 	}
 
-	return output.Print(DiskStatus{Disks: disks})
+	return output.Print(ctx, DiskStatus{Disks: disks})
 }
 
-func getInfoLinux(disk string) error {
+func getInfoLinux(ctx context.Context, disk string) error {
 	// Try smartctl for detailed info
 	out, err := exec.Command("smartctl", "-a", disk).Output()
 	if err != nil {
 		// Fallback to basic lsblk info
-		return getInfoLinuxBasic(disk)
+		return getInfoLinuxBasic(ctx, disk)
 	}
 
 	detail := DiskDetail{
@@ -403,10 +417,10 @@ func getInfoLinux(disk string) error {
 		}
 	}
 
-	return output.Print(detail)
+	return output.Print(ctx, detail)
 }
 
-func getInfoLinuxBasic(disk string) error {
+func getInfoLinuxBasic(ctx context.Context, disk string) error {
 	detail := DiskDetail{
 		Name:       disk,
 		Attributes: make(map[string]string),
@@ -439,7 +453,7 @@ func getInfoLinuxBasic(disk string) error {
 
 	detail.Attributes["note"] = "Install smartmontools for detailed S.M.A.R.T. data"
 
-	return output.Print(detail)
+	return output.Print(ctx, detail)
 }
 
 func getString(m map[string]any, key string) string {
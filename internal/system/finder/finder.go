@@ -2,6 +2,7 @@ package finder
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html"
 	"os"
@@ -13,6 +14,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -54,6 +57,16 @@ type SearchResult struct {
 	ContentType string `json:"content_type,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "finder",
+		Name:        "Finder",
+		Group:       "system",
+		Description: "Finder operations, file info, tags, Spotlight search (macOS only)",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd creates the finder command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -62,8 +75,9 @@ func NewCmd() *cobra.Command {
 		Short:   "Finder/filesystem commands (macOS only)",
 		Long:    `Interact with Finder and the filesystem on macOS. Uses AppleScript and shell commands.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if runtime.GOOS != "darwin" {
-				return output.PrintError("platform_unsupported",
+				return output.PrintError(ctx, "platform_unsupported",
 					"Finder integration is only available on macOS",
 					map[string]string{
 						"current_platform": runtime.GOOS,
@@ -176,14 +190,15 @@ func newOpenCmd() *cobra.Command {
 		Long:  `Opens a file or folder in Finder. Optionally specify an application to open with.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			path, err := resolvePath(args[0])
 			if err != nil {
-				return output.PrintError("invalid_path", err.Error(), nil)
+				return output.PrintErr(ctx, "invalid_path", err, nil)
 			}
 
 			// Check if path exists
 			if _, err := os.Stat(path); os.IsNotExist(err) {
-				return output.PrintError("path_not_found",
+				return output.PrintError(ctx, "path_not_found",
 					fmt.Sprintf("Path does not exist: %s", path),
 					map[string]string{"path": path})
 			}
@@ -195,12 +210,16 @@ func newOpenCmd() *cobra.Command {
 				openCmd = exec.Command("open", path)
 			}
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.Exec(ctx, openCmd.Args...)
+			}
+
 			if err := openCmd.Run(); err != nil {
-				return output.PrintError("open_failed", err.Error(),
+				return output.PrintErr(ctx, "open_failed", err,
 					map[string]string{"path": path})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": "Opened successfully",
 				"path":    path,
@@ -218,27 +237,30 @@ func newOpenCmd() *cobra.Command {
 // from scriptTemplate (which must contain a single %s for the escaped path),
 // and returns a JSON success result.  action is used for the error code prefix
 // (e.g. "reveal" → "reveal_failed") and successMsg is the human-readable message.
-func runFinderAction(rawPath, scriptTemplate, action, successMsg string) error {
+func runFinderAction(ctx context.Context, rawPath, scriptTemplate, action, successMsg string) error {
 	path, err := resolvePath(rawPath)
 	if err != nil {
-		return output.PrintError("invalid_path", err.Error(), nil)
+		return output.PrintErr(ctx, "invalid_path", err, nil)
 	}
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return output.PrintError("path_not_found",
+		return output.PrintError(ctx, "path_not_found",
 			fmt.Sprintf("Path does not exist: %s", path),
 			map[string]string{"path": path})
 	}
 
 	script := fmt.Sprintf(scriptTemplate, escapeAppleScript(path))
+	if dryrun.Enabled(ctx) {
+		return dryrun.AppleScript(ctx, script)
+	}
 
 	err = runAppleScript(script)
 	if err != nil {
-		return output.PrintError(action+"_failed", err.Error(),
+		return output.PrintErr(ctx, action+"_failed", err,
 			map[string]string{"path": path})
 	}
 
-	return output.Print(map[string]any{
+	return output.Print(ctx, map[string]any{
 		"success": true,
 		"message": successMsg,
 		"path":    path,
@@ -253,7 +275,8 @@ func newRevealCmd() *cobra.Command {
 		Long:  `Opens a Finder window and selects the specified file or folder.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runFinderAction(args[0], `
+			ctx := cmd.Context()
+			return runFinderAction(ctx, args[0], `
 tell application "Finder"
 	reveal POSIX file "%s"
 	activate
@@ -274,20 +297,21 @@ func newInfoCmd() *cobra.Command {
 		Long:  `Returns detailed information about a file or folder including size, dates, and type.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			path, err := resolvePath(args[0])
 			if err != nil {
-				return output.PrintError("invalid_path", err.Error(), nil)
+				return output.PrintErr(ctx, "invalid_path", err, nil)
 			}
 
 			// Check if path exists
 			stat, err := os.Stat(path)
 			if os.IsNotExist(err) {
-				return output.PrintError("path_not_found",
+				return output.PrintError(ctx, "path_not_found",
 					fmt.Sprintf("Path does not exist: %s", path),
 					map[string]string{"path": path})
 			}
 			if err != nil {
-				return output.PrintError("stat_failed", err.Error(),
+				return output.PrintErr(ctx, "stat_failed", err,
 					map[string]string{"path": path})
 			}
 
@@ -356,7 +380,7 @@ func newInfoCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(info)
+			return output.Print(ctx, info)
 		},
 	}
 
@@ -375,6 +399,7 @@ func newListCmd() *cobra.Command {
 		Long:  `Lists the contents of a directory with details including size and modification date.`,
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			path := "."
 			if len(args) > 0 {
 				path = args[0]
@@ -382,29 +407,29 @@ func newListCmd() *cobra.Command {
 
 			resolvedPath, err := resolvePath(path)
 			if err != nil {
-				return output.PrintError("invalid_path", err.Error(), nil)
+				return output.PrintErr(ctx, "invalid_path", err, nil)
 			}
 
 			// Check if path exists and is a directory
 			stat, err := os.Stat(resolvedPath)
 			if os.IsNotExist(err) {
-				return output.PrintError("path_not_found",
+				return output.PrintError(ctx, "path_not_found",
 					fmt.Sprintf("Path does not exist: %s", resolvedPath),
 					map[string]string{"path": resolvedPath})
 			}
 			if err != nil {
-				return output.PrintError("stat_failed", err.Error(),
+				return output.PrintErr(ctx, "stat_failed", err,
 					map[string]string{"path": resolvedPath})
 			}
 			if !stat.IsDir() {
-				return output.PrintError("not_directory",
+				return output.PrintError(ctx, "not_directory",
 					fmt.Sprintf("Path is not a directory: %s", resolvedPath),
 					map[string]string{"path": resolvedPath})
 			}
 
 			entries, err := os.ReadDir(resolvedPath)
 			if err != nil {
-				return output.PrintError("read_dir_failed", err.Error(),
+				return output.PrintErr(ctx, "read_dir_failed", err,
 					map[string]string{"path": resolvedPath})
 			}
 
@@ -444,7 +469,7 @@ func newListCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"path":    resolvedPath,
 				"entries": results,
 				"count":   len(results),
@@ -467,14 +492,15 @@ func newTagsCmd() *cobra.Command {
 		Long:  `Returns the Finder tags (labels) associated with a file or folder.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			path, err := resolvePath(args[0])
 			if err != nil {
-				return output.PrintError("invalid_path", err.Error(), nil)
+				return output.PrintErr(ctx, "invalid_path", err, nil)
 			}
 
 			// Check if path exists
 			if _, err := os.Stat(path); os.IsNotExist(err) {
-				return output.PrintError("path_not_found",
+				return output.PrintError(ctx, "path_not_found",
 					fmt.Sprintf("Path does not exist: %s", path),
 					map[string]string{"path": path})
 			}
@@ -482,7 +508,7 @@ func newTagsCmd() *cobra.Command {
 			// Get tags using mdls
 			mdlsOutput, err := runCommand("mdls", "-name", "kMDItemUserTags", "-raw", path)
 			if err != nil {
-				return output.PrintError("mdls_failed", err.Error(),
+				return output.PrintErr(ctx, "mdls_failed", err,
 					map[string]string{"path": path})
 			}
 
@@ -506,7 +532,7 @@ func newTagsCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"path":  path,
 				"tags":  tags,
 				"count": len(tags),
@@ -525,15 +551,16 @@ func newTagCmd() *cobra.Command {
 		Long:  `Adds a Finder tag (label) to a file or folder.`,
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			path, err := resolvePath(args[0])
 			if err != nil {
-				return output.PrintError("invalid_path", err.Error(), nil)
+				return output.PrintErr(ctx, "invalid_path", err, nil)
 			}
 			tag := args[1]
 
 			// Check if path exists
 			if _, err := os.Stat(path); os.IsNotExist(err) {
-				return output.PrintError("path_not_found",
+				return output.PrintError(ctx, "path_not_found",
 					fmt.Sprintf("Path does not exist: %s", path),
 					map[string]string{"path": path})
 			}
@@ -562,7 +589,7 @@ func newTagCmd() *cobra.Command {
 			// Check if tag already exists
 			for _, t := range existingTags {
 				if t == tag {
-					return output.Print(map[string]any{
+					return output.Print(ctx, map[string]any{
 						"success": true,
 						"message": "Tag already exists",
 						"path":    path,
@@ -580,12 +607,15 @@ func newTagCmd() *cobra.Command {
 			plistTags += "</array></plist>"
 
 			xattrCmd := exec.Command("xattr", "-w", "com.apple.metadata:_kMDItemUserTags", plistTags, path)
+			if dryrun.Enabled(ctx) {
+				return dryrun.Exec(ctx, xattrCmd.Args...)
+			}
 			if err := xattrCmd.Run(); err != nil {
-				return output.PrintError("tag_failed", err.Error(),
+				return output.PrintErr(ctx, "tag_failed", err,
 					map[string]string{"path": path, "tag": tag})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": "Tag added successfully",
 				"path":    path,
@@ -605,15 +635,16 @@ func newUntagCmd() *cobra.Command {
 		Long:  `Removes a Finder tag (label) from a file or folder.`,
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			path, err := resolvePath(args[0])
 			if err != nil {
-				return output.PrintError("invalid_path", err.Error(), nil)
+				return output.PrintErr(ctx, "invalid_path", err, nil)
 			}
 			tag := args[1]
 
 			// Check if path exists
 			if _, err := os.Stat(path); os.IsNotExist(err) {
-				return output.PrintError("path_not_found",
+				return output.PrintError(ctx, "path_not_found",
 					fmt.Sprintf("Path does not exist: %s", path),
 					map[string]string{"path": path})
 			}
@@ -650,7 +681,7 @@ func newUntagCmd() *cobra.Command {
 			}
 
 			if !found {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"success": true,
 					"message": "Tag not found on file",
 					"path":    path,
@@ -662,6 +693,9 @@ func newUntagCmd() *cobra.Command {
 			if len(newTags) == 0 {
 				// Remove the attribute entirely
 				xattrCmd := exec.Command("xattr", "-d", "com.apple.metadata:_kMDItemUserTags", path)
+				if dryrun.Enabled(ctx) {
+					return dryrun.Exec(ctx, xattrCmd.Args...)
+				}
 				_ = xattrCmd.Run() // Ignore error if attribute doesn't exist
 			} else {
 				plistTags := "<!DOCTYPE plist PUBLIC \"-//Apple//DTD PLIST 1.0//EN\" \"http://www.apple.com/DTDs/PropertyList-1.0.dtd\"><plist version=\"1.0\"><array>"
@@ -671,13 +705,16 @@ func newUntagCmd() *cobra.Command {
 				plistTags += "</array></plist>"
 
 				xattrCmd := exec.Command("xattr", "-w", "com.apple.metadata:_kMDItemUserTags", plistTags, path)
+				if dryrun.Enabled(ctx) {
+					return dryrun.Exec(ctx, xattrCmd.Args...)
+				}
 				if err := xattrCmd.Run(); err != nil {
-					return output.PrintError("untag_failed", err.Error(),
+					return output.PrintErr(ctx, "untag_failed", err,
 						map[string]string{"path": path, "tag": tag})
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": "Tag removed successfully",
 				"path":    path,
@@ -697,7 +734,8 @@ func newTrashCmd() *cobra.Command {
 		Long:  `Moves a file or folder to the Trash using Finder.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runFinderAction(args[0], `
+			ctx := cmd.Context()
+			return runFinderAction(ctx, args[0], `
 tell application "Finder"
 	move POSIX file "%s" to trash
 end tell`, "trash", "File moved to trash")
@@ -722,6 +760,7 @@ func newSearchCmd() *cobra.Command {
 		Long:  `Searches for files using Spotlight (mdfind). Supports name matching and content search.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := strings.ReplaceAll(args[0], "'", "")
 
 			// Build mdfind command
@@ -731,13 +770,13 @@ func newSearchCmd() *cobra.Command {
 			if searchPath != "" {
 				resolvedPath, err := resolvePath(searchPath)
 				if err != nil {
-					return output.PrintError("invalid_path", err.Error(), nil)
+					return output.PrintErr(ctx, "invalid_path", err, nil)
 				}
 				mdfindArgs = append(mdfindArgs, "-onlyin", resolvedPath)
 			} else if onlyIn != "" {
 				resolvedPath, err := resolvePath(onlyIn)
 				if err != nil {
-					return output.PrintError("invalid_path", err.Error(), nil)
+					return output.PrintErr(ctx, "invalid_path", err, nil)
 				}
 				mdfindArgs = append(mdfindArgs, "-onlyin", resolvedPath)
 			}
@@ -771,7 +810,7 @@ func newSearchCmd() *cobra.Command {
 
 			result, err := runCommand("mdfind", mdfindArgs...)
 			if err != nil {
-				return output.PrintError("search_failed", err.Error(),
+				return output.PrintErr(ctx, "search_failed", err,
 					map[string]string{"query": query})
 			}
 
@@ -826,7 +865,7 @@ func newSearchCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"query":   query,
 				"results": results,
 				"count":   len(results),
@@ -855,6 +894,7 @@ func newRecentCmd() *cobra.Command {
 		Short: "List recently modified files",
 		Long:  `Lists recently modified files using Spotlight (mdfind). Filter by directory and time range.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			// Build date threshold
 			threshold := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
 			dateStr := threshold.Format("2006-01-02")
@@ -865,10 +905,10 @@ func newRecentCmd() *cobra.Command {
 			if dir != "" {
 				resolvedDir, err := resolvePath(dir)
 				if err != nil {
-					return output.PrintError("invalid_path", err.Error(), nil)
+					return output.PrintErr(ctx, "invalid_path", err, nil)
 				}
 				if stat, err := os.Stat(resolvedDir); err != nil || !stat.IsDir() {
-					return output.PrintError("invalid_path",
+					return output.PrintError(ctx, "invalid_path",
 						fmt.Sprintf("Path is not a directory: %s", resolvedDir),
 						map[string]string{"path": resolvedDir})
 				}
@@ -882,7 +922,7 @@ func newRecentCmd() *cobra.Command {
 
 			result, err := runCommand("mdfind", mdfindArgs...)
 			if err != nil {
-				return output.PrintError("recent_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "recent_failed", err, nil)
 			}
 
 			type RecentFile struct {
@@ -940,7 +980,7 @@ func newRecentCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"results": results,
 				"count":   len(results),
 				"days":    days,
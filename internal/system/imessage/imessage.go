@@ -14,6 +14,8 @@ import (
 	_ "github.com/mattn/go-sqlite3" // sqlite3 driver registration
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -42,6 +44,16 @@ type Message struct {
 // appleEpoch is January 1, 2001 00:00:00 UTC (Apple's Core Data timestamp reference)
 var appleEpoch = time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "imessage",
+		Name:        "iMessage",
+		Group:       "system",
+		Description: "Send and read iMessages via Messages.app (macOS only)",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd creates the imessage command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -50,8 +62,9 @@ func NewCmd() *cobra.Command {
 		Short:   "iMessage commands (macOS only)",
 		Long:    `Interact with iMessage via AppleScript (send) and SQLite (read history). Only available on macOS.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if runtime.GOOS != "darwin" {
-				return output.PrintError("platform_unsupported",
+				return output.PrintError(ctx, "platform_unsupported",
 					"iMessage is only available on macOS",
 					map[string]string{
 						"current_platform": runtime.GOOS,
@@ -181,6 +194,7 @@ func newSendCmd() *cobra.Command {
 - A contact name (as saved in Contacts)`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			recipient := args[0]
 			message := args[1]
 
@@ -195,6 +209,10 @@ tell application "Messages"
 end tell
 `, service, escapeAppleScript(recipient), escapeAppleScript(message))
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, script)
+			}
+
 			err := runAppleScript(script)
 			if err != nil {
 				// Try alternative approach - sending directly to participant
@@ -206,14 +224,14 @@ end tell
 
 				err = runAppleScript(altScript)
 				if err != nil {
-					return output.PrintError("send_failed", err.Error(), map[string]string{
+					return output.PrintErr(ctx, "send_failed", err, map[string]string{
 						"recipient": recipient,
 						"hint":      "Make sure Messages.app is running and signed in",
 					})
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success":   true,
 				"message":   "Message sent successfully",
 				"recipient": recipient,
@@ -234,9 +252,10 @@ func newChatsCmd() *cobra.Command {
 		Short: "List recent conversations",
 		Long:  `List recent iMessage conversations from the local database.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			db, err := openChatDB()
 			if err != nil {
-				return output.PrintError("db_error", err.Error(), map[string]string{
+				return output.PrintErr(ctx, "db_error", err, map[string]string{
 					"path": getChatDBPath(),
 				})
 			}
@@ -265,7 +284,7 @@ func newChatsCmd() *cobra.Command {
 
 			rows, err := db.Query(query, limit)
 			if err != nil {
-				return output.PrintError("query_error", err.Error(), nil)
+				return output.PrintErr(ctx, "query_error", err, nil)
 			}
 			defer rows.Close()
 
@@ -310,10 +329,10 @@ func newChatsCmd() *cobra.Command {
 				chats = append(chats, chat)
 			}
 			if err := rows.Err(); err != nil {
-				return output.PrintError("query_error", err.Error(), nil)
+				return output.PrintErr(ctx, "query_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"chats": chats,
 				"count": len(chats),
 			})
@@ -338,11 +357,12 @@ func newReadCmd() *cobra.Command {
 The contact can be a phone number, email, or name as it appears in your chats.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			contact := args[0]
 
 			db, err := openChatDB()
 			if err != nil {
-				return output.PrintError("db_error", err.Error(), map[string]string{
+				return output.PrintErr(ctx, "db_error", err, map[string]string{
 					"path": getChatDBPath(),
 				})
 			}
@@ -374,7 +394,7 @@ The contact can be a phone number, email, or name as it appears in your chats.`,
 			searchPattern := "%" + contact + "%"
 			rows, err := db.Query(query, searchPattern, searchPattern, limit)
 			if err != nil {
-				return output.PrintError("query_error", err.Error(), nil)
+				return output.PrintErr(ctx, "query_error", err, nil)
 			}
 			defer rows.Close()
 
@@ -430,11 +450,11 @@ The contact can be a phone number, email, or name as it appears in your chats.`,
 				messages = append(messages, msg)
 			}
 			if err := rows.Err(); err != nil {
-				return output.PrintError("query_error", err.Error(), nil)
+				return output.PrintErr(ctx, "query_error", err, nil)
 			}
 
 			if len(messages) == 0 {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"contact":  contact,
 					"messages": []Message{},
 					"count":    0,
@@ -447,7 +467,7 @@ The contact can be a phone number, email, or name as it appears in your chats.`,
 				messages[i], messages[j] = messages[j], messages[i]
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"contact":  contact,
 				"messages": messages,
 				"count":    len(messages),
@@ -470,11 +490,12 @@ func newSearchCmd() *cobra.Command {
 		Long:  `Search all iMessage messages for text content.`,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			searchQuery := args[0]
 
 			db, err := openChatDB()
 			if err != nil {
-				return output.PrintError("db_error", err.Error(), map[string]string{
+				return output.PrintErr(ctx, "db_error", err, map[string]string{
 					"path": getChatDBPath(),
 				})
 			}
@@ -503,7 +524,7 @@ func newSearchCmd() *cobra.Command {
 			searchPattern := "%" + searchQuery + "%"
 			rows, err := db.Query(query, searchPattern, limit)
 			if err != nil {
-				return output.PrintError("query_error", err.Error(), nil)
+				return output.PrintErr(ctx, "query_error", err, nil)
 			}
 			defer rows.Close()
 
@@ -550,10 +571,10 @@ func newSearchCmd() *cobra.Command {
 				messages = append(messages, msg)
 			}
 			if err := rows.Err(); err != nil {
-				return output.PrintError("query_error", err.Error(), nil)
+				return output.PrintErr(ctx, "query_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"query":    searchQuery,
 				"messages": messages,
 				"count":    len(messages),
@@ -573,9 +594,10 @@ func newUnreadCmd() *cobra.Command {
 		Short: "Show unread message count",
 		Long:  `Show the count of unread messages in iMessage.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			db, err := openChatDB()
 			if err != nil {
-				return output.PrintError("db_error", err.Error(), map[string]string{
+				return output.PrintErr(ctx, "db_error", err, map[string]string{
 					"path": getChatDBPath(),
 				})
 			}
@@ -592,7 +614,7 @@ func newUnreadCmd() *cobra.Command {
 			var count int
 			err = db.QueryRow(query).Scan(&count)
 			if err != nil {
-				return output.PrintError("query_error", err.Error(), nil)
+				return output.PrintErr(ctx, "query_error", err, nil)
 			}
 
 			// Also get unread per chat
@@ -613,7 +635,7 @@ func newUnreadCmd() *cobra.Command {
 			rows, err := db.Query(chatQuery)
 			if err != nil {
 				// If chat query fails, just return the total count
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"total_unread": count,
 				})
 			}
@@ -642,10 +664,10 @@ func newUnreadCmd() *cobra.Command {
 				})
 			}
 			if err := rows.Err(); err != nil {
-				return output.PrintError("query_error", err.Error(), nil)
+				return output.PrintErr(ctx, "query_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"total_unread":   count,
 				"unread_by_chat": unreadByChat,
 			})
@@ -11,6 +11,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -79,6 +81,16 @@ type Message struct {
 	Account      string `json:"account,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "apple-mail",
+		Name:        "Apple Mail",
+		Group:       "system",
+		Description: "Read and send emails via Apple Mail (macOS only)",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd creates the Apple Mail command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -87,8 +99,9 @@ func NewCmd() *cobra.Command {
 		Short:   "Apple Mail commands (macOS only)",
 		Long:    `Interact with Apple Mail via AppleScript. Only available on macOS.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if runtime.GOOS != "darwin" {
-				return output.PrintError("platform_unsupported",
+				return output.PrintError(ctx, "platform_unsupported",
 					"Apple Mail is only available on macOS",
 					map[string]string{
 						"current_platform": runtime.GOOS,
@@ -149,6 +162,7 @@ func newAccountsCmd() *cobra.Command {
 		Use:   "accounts",
 		Short: "List all mail accounts",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			script := `
 tell application "Mail"
 	set output to ""
@@ -167,13 +181,13 @@ end tell
 `
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), map[string]string{
+				return output.PrintErr(ctx, "applescript_error", err, map[string]string{
 					"hint": "Make sure Mail.app is running",
 				})
 			}
 
 			accounts := parseAccounts(result)
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"accounts": accounts,
 				"count":    len(accounts),
 			})
@@ -191,6 +205,7 @@ func newMailboxesCmd() *cobra.Command {
 		Use:   "mailboxes",
 		Short: "List mailboxes/folders",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			var script string
 			if accountName != "" {
 				script = fmt.Sprintf(`
@@ -239,15 +254,15 @@ end tell
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
-				return output.PrintError("mail_error", strings.TrimPrefix(result, "ERROR: "), nil)
+				return output.PrintError(ctx, "mail_error", strings.TrimPrefix(result, "ERROR: "), nil)
 			}
 
 			mailboxes := parseMailboxes(result)
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"mailboxes": mailboxes,
 				"count":     len(mailboxes),
 				"account":   accountName,
@@ -271,6 +286,7 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List recent messages",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			// Default to inbox
 			if mailbox == "" {
 				mailbox = "INBOX"
@@ -337,15 +353,15 @@ end tell
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
-				return output.PrintError("mail_error", strings.TrimPrefix(result, "ERROR: "), nil)
+				return output.PrintError(ctx, "mail_error", strings.TrimPrefix(result, "ERROR: "), nil)
 			}
 
 			messages := parseMessageList(result)
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"messages": messages,
 				"count":    len(messages),
 				"mailbox":  mailbox,
@@ -369,6 +385,7 @@ func newReadCmd() *cobra.Command {
 		Short: "Read a message by ID",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			msgID := args[0]
 
 			script := fmt.Sprintf(`
@@ -436,25 +453,25 @@ end tell
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
 				errMsg := strings.TrimPrefix(result, "ERROR: ")
 				if strings.Contains(errMsg, "not found") {
-					return output.PrintError("not_found", "Message not found", map[string]string{
+					return output.PrintError(ctx, "not_found", "Message not found", map[string]string{
 						"id": msgID,
 					})
 				}
-				return output.PrintError("mail_error", errMsg, nil)
+				return output.PrintError(ctx, "mail_error", errMsg, nil)
 			}
 
 			message := parseFullMessage(result)
 			if message == nil {
-				return output.PrintError("parse_error", "Failed to parse message", nil)
+				return output.PrintError(ctx, "parse_error", "Failed to parse message", nil)
 			}
 
-			return output.Print(message)
+			return output.Print(ctx, message)
 		},
 	}
 
@@ -472,6 +489,7 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search messages",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := args[0]
 
 			var script string
@@ -532,15 +550,15 @@ end tell
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
-				return output.PrintError("mail_error", strings.TrimPrefix(result, "ERROR: "), nil)
+				return output.PrintError(ctx, "mail_error", strings.TrimPrefix(result, "ERROR: "), nil)
 			}
 
 			messages := parseMessageList(result)
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"messages": messages,
 				"count":    len(messages),
 				"query":    query,
@@ -570,14 +588,15 @@ func newSendCmd() *cobra.Command {
 		Use:   "send",
 		Short: "Send an email",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if to == "" {
-				return output.PrintError("missing_recipient", "Recipient (--to) is required", nil)
+				return output.PrintError(ctx, "missing_recipient", "Recipient (--to) is required", nil)
 			}
 			if subject == "" {
-				return output.PrintError("missing_subject", "Subject (--subject) is required", nil)
+				return output.PrintError(ctx, "missing_subject", "Subject (--subject) is required", nil)
 			}
 			if body == "" {
-				return output.PrintError("missing_body", "Body (--body) is required", nil)
+				return output.PrintError(ctx, "missing_body", "Body (--body) is required", nil)
 			}
 
 			// Build recipient parts
@@ -638,16 +657,20 @@ tell application "Mail"
 end tell
 `, escapeAppleScript(subject), escapeAppleScript(body), accountPart, toScript.String(), ccScript.String(), bccScript.String())
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, script)
+			}
+
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
-				return output.PrintError("send_failed", strings.TrimPrefix(result, "ERROR: "), nil)
+				return output.PrintError(ctx, "send_failed", strings.TrimPrefix(result, "ERROR: "), nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": "Email sent successfully",
 				"to":      to,
@@ -677,6 +700,7 @@ func newUnreadCmd() *cobra.Command {
 		Use:   "unread",
 		Short: "List unread messages",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			var script string
 			if accountName != "" {
 				script = fmt.Sprintf(`
@@ -731,15 +755,15 @@ end tell
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
-				return output.PrintError("mail_error", strings.TrimPrefix(result, "ERROR: "), nil)
+				return output.PrintError(ctx, "mail_error", strings.TrimPrefix(result, "ERROR: "), nil)
 			}
 
 			messages := parseUnreadMessages(result)
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"messages": messages,
 				"count":    len(messages),
 				"account":  accountName,
@@ -761,6 +785,7 @@ func newCountCmd() *cobra.Command {
 		Use:   "count",
 		Short: "Get unread message count",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			var script string
 			if accountName != "" {
 				script = fmt.Sprintf(`
@@ -791,15 +816,15 @@ end tell
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
-				return output.PrintError("mail_error", strings.TrimPrefix(result, "ERROR: "), nil)
+				return output.PrintError(ctx, "mail_error", strings.TrimPrefix(result, "ERROR: "), nil)
 			}
 
 			count, _ := strconv.Atoi(result)
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"unread_count": count,
 				"account":      accountName,
 			})
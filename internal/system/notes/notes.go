@@ -10,6 +10,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -65,6 +67,16 @@ type Folder struct {
 	Count int    `json:"count,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "notes",
+		Name:        "Apple Notes",
+		Group:       "system",
+		Description: "Read and manage Apple Notes via AppleScript (macOS only)",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd creates the notes command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -73,8 +85,9 @@ func NewCmd() *cobra.Command {
 		Short:   "Apple Notes commands (macOS only)",
 		Long:    `Interact with Apple Notes via AppleScript. Only available on macOS.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if runtime.GOOS != "darwin" {
-				return output.PrintError("platform_unsupported",
+				return output.PrintError(ctx, "platform_unsupported",
 					"Apple Notes is only available on macOS",
 					map[string]string{"current_platform": runtime.GOOS})
 			}
@@ -128,6 +141,7 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List all notes",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			var script string
 
 			if folder != "" {
@@ -170,11 +184,11 @@ end tell`
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("list_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "list_failed", err, nil)
 			}
 
 			if result == "" {
-				return output.Print([]Note{})
+				return output.Print(ctx, []Note{})
 			}
 
 			// Parse the result
@@ -196,7 +210,7 @@ end tell`
 				}
 			}
 
-			return output.Print(notes)
+			return output.Print(ctx, notes)
 		},
 	}
 
@@ -212,6 +226,7 @@ func newFoldersCmd() *cobra.Command {
 		Use:   "folders",
 		Short: "List all folders",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			script := `
 tell application "Notes"
 	set folderList to {}
@@ -226,11 +241,11 @@ end tell`
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("folders_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "folders_failed", err, nil)
 			}
 
 			if result == "" {
-				return output.Print([]Folder{})
+				return output.Print(ctx, []Folder{})
 			}
 
 			// Parse the result
@@ -248,7 +263,7 @@ end tell`
 				}
 			}
 
-			return output.Print(folders)
+			return output.Print(ctx, folders)
 		},
 	}
 
@@ -264,6 +279,7 @@ func newReadCmd() *cobra.Command {
 		Short: "Read a note by name",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			noteName := args[0]
 
 			// We fetch metadata and body in separate AppleScript calls
@@ -309,18 +325,18 @@ end tell`, escapeAppleScript(noteName))
 
 			metaResult, err := runAppleScript(metaScript)
 			if err != nil {
-				return output.PrintError("read_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "read_failed", err, nil)
 			}
 
 			if metaResult == "NOT_FOUND" {
-				return output.PrintError("note_not_found",
+				return output.PrintError(ctx, "note_not_found",
 					fmt.Sprintf("Note not found: %s", noteName),
 					map[string]string{"name": noteName, "folder": folder})
 			}
 
 			metaParts := strings.Split(metaResult, "|||")
 			if len(metaParts) < 3 {
-				return output.PrintError("parse_failed", "Failed to parse note metadata", nil)
+				return output.PrintError(ctx, "parse_failed", "Failed to parse note metadata", nil)
 			}
 
 			// Fetch the HTML body separately to preserve formatting
@@ -353,7 +369,7 @@ end tell`, escapeAppleScript(noteName))
 				ModifiedAt: strings.TrimSpace(metaParts[2]),
 			}
 
-			return output.Print(note)
+			return output.Print(ctx, note)
 		},
 	}
 
@@ -371,6 +387,7 @@ func newCreateCmd() *cobra.Command {
 		Short: "Create a new note",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			noteName := args[0]
 			noteBody := args[1]
 
@@ -395,17 +412,21 @@ tell application "Notes"
 end tell`, escapeAppleScript(noteName), htmlBody)
 			}
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, script)
+			}
+
 			result, err := runAppleScript(script)
 			if err != nil {
 				if strings.Contains(err.Error(), "Can't get folder") {
-					return output.PrintError("folder_not_found",
+					return output.PrintError(ctx, "folder_not_found",
 						fmt.Sprintf("Folder not found: %s", folder),
 						map[string]string{"folder": folder})
 				}
-				return output.PrintError("create_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "create_failed", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": "Note created successfully",
 				"name":    result,
@@ -428,6 +449,7 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search notes by name or content",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := strings.ToLower(args[0])
 
 			script := fmt.Sprintf(`
@@ -455,11 +477,11 @@ end tell`, escapeAppleScript(query))
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("search_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "search_failed", err, nil)
 			}
 
 			if result == "" {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"query":   query,
 					"results": []Note{},
 					"count":   0,
@@ -485,7 +507,7 @@ end tell`, escapeAppleScript(query))
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"query":   query,
 				"results": notes,
 				"count":   len(notes),
@@ -507,6 +529,7 @@ func newAppendCmd() *cobra.Command {
 		Short: "Append text to an existing note",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			noteName := args[0]
 			textToAppend := args[1]
 
@@ -533,17 +556,21 @@ tell application "Notes"
 end tell`, escapeAppleScript(noteName), htmlText)
 			}
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, script)
+			}
+
 			result, err := runAppleScript(script)
 			if err != nil {
 				if strings.Contains(err.Error(), "Can't get") {
-					return output.PrintError("note_not_found",
+					return output.PrintError(ctx, "note_not_found",
 						fmt.Sprintf("Note not found: %s", noteName),
 						map[string]string{"name": noteName, "folder": folder})
 				}
-				return output.PrintError("append_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "append_failed", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": "Text appended successfully",
 				"name":    result,
@@ -10,6 +10,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -34,6 +36,16 @@ type ReminderList struct {
 	Count int    `json:"count"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "reminders",
+		Name:        "Apple Reminders",
+		Group:       "system",
+		Description: "Manage Apple Reminders via AppleScript (macOS only)",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "reminders",
@@ -41,8 +53,9 @@ func NewCmd() *cobra.Command {
 		Short:   "Apple Reminders commands (macOS only)",
 		Long:    `Interact with Apple Reminders via AppleScript. Only available on macOS.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if runtime.GOOS != "darwin" {
-				return output.PrintError("platform_unsupported",
+				return output.PrintError(ctx, "platform_unsupported",
 					"Apple Reminders is only available on macOS",
 					map[string]string{
 						"current_platform": runtime.GOOS,
@@ -70,6 +83,7 @@ func newListsCmd() *cobra.Command {
 		Use:   "lists",
 		Short: "List all reminder lists",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			script := `
 tell application "Reminders"
 	set output to ""
@@ -84,11 +98,11 @@ end tell
 `
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			lists := parseReminderLists(result)
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"lists": lists,
 				"count": len(lists),
 			})
@@ -107,6 +121,7 @@ func newListCmd() *cobra.Command {
 		Short: "List reminders in a specific list (or all if no name given)",
 		Args:  cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			listName := ""
 			if len(args) > 0 {
 				listName = args[0]
@@ -187,15 +202,15 @@ end tell
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
-				return output.PrintError("reminders_error", strings.TrimPrefix(result, "ERROR: "), nil)
+				return output.PrintError(ctx, "reminders_error", strings.TrimPrefix(result, "ERROR: "), nil)
 			}
 
 			reminders := parseReminders(result, listName)
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"reminders": reminders,
 				"count":     len(reminders),
 				"list":      listName,
@@ -220,6 +235,7 @@ func newAddCmd() *cobra.Command {
 		Short: "Add a new reminder",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			title := args[0]
 
 			// Default to "Reminders" list if not specified
@@ -256,7 +272,7 @@ tell application "Reminders"
 			if dueDate != "" {
 				parsedDate, err := parseFlexibleDate(dueDate)
 				if err != nil {
-					return output.PrintError("invalid_date", err.Error(), map[string]string{
+					return output.PrintErr(ctx, "invalid_date", err, map[string]string{
 						"input":   dueDate,
 						"formats": "YYYY-MM-DD, YYYY-MM-DD HH:MM, today, tomorrow, next week",
 					})
@@ -273,13 +289,17 @@ tell application "Reminders"
 end tell
 `)
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, scriptBuilder.String())
+			}
+
 			result, err := runAppleScript(scriptBuilder.String())
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
-				return output.PrintError("reminders_error", strings.TrimPrefix(result, "ERROR: "), nil)
+				return output.PrintError(ctx, "reminders_error", strings.TrimPrefix(result, "ERROR: "), nil)
 			}
 
 			response := map[string]any{
@@ -299,7 +319,7 @@ end tell
 				response["priority"] = priority
 			}
 
-			return output.Print(response)
+			return output.Print(ctx, response)
 		},
 	}
 
@@ -318,6 +338,7 @@ func newCompleteCmd() *cobra.Command {
 		Short: "Mark a reminder as complete",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			identifier := args[0]
 
 			// Try to find by ID first, then by name
@@ -361,17 +382,21 @@ tell application "Reminders"
 end tell
 `, escapeAppleScriptString(identifier), escapeAppleScriptString(identifier))
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, script)
+			}
+
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
-				return output.PrintError("reminders_error", strings.TrimPrefix(result, "ERROR: "), nil)
+				return output.PrintError(ctx, "reminders_error", strings.TrimPrefix(result, "ERROR: "), nil)
 			}
 
 			completedName := strings.TrimPrefix(strings.TrimSpace(result), "SUCCESS: ")
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success":   true,
 				"message":   "Reminder marked as complete",
 				"name":      completedName,
@@ -390,6 +415,7 @@ func newDeleteCmd() *cobra.Command {
 		Short: "Delete a reminder",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			identifier := args[0]
 
 			script := fmt.Sprintf(`
@@ -436,17 +462,21 @@ tell application "Reminders"
 end tell
 `, escapeAppleScriptString(identifier), escapeAppleScriptString(identifier))
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, script)
+			}
+
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			if strings.HasPrefix(result, "ERROR:") {
-				return output.PrintError("reminders_error", strings.TrimPrefix(result, "ERROR: "), nil)
+				return output.PrintError(ctx, "reminders_error", strings.TrimPrefix(result, "ERROR: "), nil)
 			}
 
 			deletedName := strings.TrimPrefix(strings.TrimSpace(result), "SUCCESS: ")
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": "Reminder deleted",
 				"name":    deletedName,
@@ -463,6 +493,7 @@ func newTodayCmd() *cobra.Command {
 		Use:   "today",
 		Short: "Show reminders due today",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			script := `
 tell application "Reminders"
 	set output to ""
@@ -495,11 +526,11 @@ end tell
 `
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			reminders := parseReminders(result, "")
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"reminders": reminders,
 				"count":     len(reminders),
 				"filter":    "today",
@@ -517,6 +548,7 @@ func newOverdueCmd() *cobra.Command {
 		Use:   "overdue",
 		Short: "Show overdue reminders",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			script := `
 tell application "Reminders"
 	set output to ""
@@ -548,11 +580,11 @@ end tell
 `
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("applescript_error", err.Error(), nil)
+				return output.PrintErr(ctx, "applescript_error", err, nil)
 			}
 
 			reminders := parseReminders(result, "")
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"reminders": reminders,
 				"count":     len(reminders),
 				"filter":    "overdue",
@@ -15,6 +15,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 
 	_ "github.com/mattn/go-sqlite3" // sqlite3 driver registration
@@ -55,6 +57,16 @@ type HistoryItem struct {
 	LastVisitTime string `json:"last_visit_time,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "safari",
+		Name:        "Safari",
+		Group:       "system",
+		Description: "Safari tabs, bookmarks, reading list, history (macOS only)",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd creates the Safari command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -63,8 +75,9 @@ func NewCmd() *cobra.Command {
 		Short:   "Safari browser commands (macOS only)",
 		Long:    `Interact with Safari browser via AppleScript. Only available on macOS.`,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if runtime.GOOS != "darwin" {
-				return output.PrintError("platform_unsupported",
+				return output.PrintError(ctx, "platform_unsupported",
 					"Safari is only available on macOS",
 					map[string]string{
 						"current_platform": runtime.GOOS,
@@ -138,8 +151,9 @@ func newTabsCmd() *cobra.Command {
 		Short: "List all open tabs in Safari",
 		Long:  "List all open tabs across all Safari windows. Optionally filter by window index.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if !isSafariRunning() {
-				return output.PrintError("safari_not_running",
+				return output.PrintError(ctx, "safari_not_running",
 					"Safari is not running",
 					map[string]string{"suggestion": "Launch Safari first"})
 			}
@@ -165,11 +179,11 @@ end tell`
 
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("tabs_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "tabs_failed", err, nil)
 			}
 
 			if result == "" {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"tabs":  []Tab{},
 					"count": 0,
 				})
@@ -197,7 +211,7 @@ end tell`
 				}
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"tabs":  tabs,
 				"count": len(tabs),
 			})
@@ -218,8 +232,9 @@ func newURLCmd() *cobra.Command {
 		Use:   "url",
 		Short: "Get URL of the current or specified tab",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if !isSafariRunning() {
-				return output.PrintError("safari_not_running",
+				return output.PrintError(ctx, "safari_not_running",
 					"Safari is not running",
 					map[string]string{"suggestion": "Launch Safari first"})
 			}
@@ -250,17 +265,17 @@ end tell`
 			result, err := runAppleScript(script)
 			if err != nil {
 				if strings.Contains(err.Error(), "Can't get window") {
-					return output.PrintError("no_window", "No Safari window is open", nil)
+					return output.PrintError(ctx, "no_window", "No Safari window is open", nil)
 				}
-				return output.PrintError("url_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "url_failed", err, nil)
 			}
 
 			parts := strings.Split(result, "|||")
 			if len(parts) < 2 {
-				return output.PrintError("parse_failed", "Failed to parse tab data", nil)
+				return output.PrintError(ctx, "parse_failed", "Failed to parse tab data", nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"title": strings.TrimSpace(parts[0]),
 				"url":   strings.TrimSpace(parts[1]),
 			})
@@ -279,8 +294,9 @@ func newTitleCmd() *cobra.Command {
 		Use:   "title",
 		Short: "Get title of the current tab",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if !isSafariRunning() {
-				return output.PrintError("safari_not_running",
+				return output.PrintError(ctx, "safari_not_running",
 					"Safari is not running",
 					map[string]string{"suggestion": "Launch Safari first"})
 			}
@@ -298,17 +314,17 @@ end tell`
 			result, err := runAppleScript(script)
 			if err != nil {
 				if strings.Contains(err.Error(), "Can't get window") {
-					return output.PrintError("no_window", "No Safari window is open", nil)
+					return output.PrintError(ctx, "no_window", "No Safari window is open", nil)
 				}
-				return output.PrintError("title_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "title_failed", err, nil)
 			}
 
 			parts := strings.Split(result, "|||")
 			if len(parts) < 2 {
-				return output.PrintError("parse_failed", "Failed to parse tab data", nil)
+				return output.PrintError(ctx, "parse_failed", "Failed to parse tab data", nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"title": strings.TrimSpace(parts[0]),
 				"url":   strings.TrimSpace(parts[1]),
 			})
@@ -327,6 +343,7 @@ func newOpenCmd() *cobra.Command {
 		Short: "Open URL in a new tab",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			url := args[0]
 
 			// Add https:// if no protocol specified
@@ -382,9 +399,13 @@ end tell`, escapeAppleScript(url))
 				}
 			}
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, script)
+			}
+
 			result, err := runAppleScript(script)
 			if err != nil {
-				return output.PrintError("open_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "open_failed", err, nil)
 			}
 
 			parts := strings.Split(result, "|||")
@@ -395,7 +416,7 @@ end tell`, escapeAppleScript(url))
 				actualURL = strings.TrimSpace(parts[1])
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success":    true,
 				"message":    "URL opened successfully",
 				"url":        actualURL,
@@ -420,8 +441,9 @@ func newCloseCmd() *cobra.Command {
 		Use:   "close",
 		Short: "Close the current or specified tab",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			if !isSafariRunning() {
-				return output.PrintError("safari_not_running",
+				return output.PrintError(ctx, "safari_not_running",
 					"Safari is not running",
 					map[string]string{"suggestion": "Launch Safari first"})
 			}
@@ -460,22 +482,26 @@ tell application "Safari"
 end tell`
 			}
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, script)
+			}
+
 			result, err := runAppleScript(script)
 			if err != nil {
 				if strings.Contains(err.Error(), "Can't get window") {
-					return output.PrintError("no_window", "No Safari window is open", nil)
+					return output.PrintError(ctx, "no_window", "No Safari window is open", nil)
 				}
-				return output.PrintError("close_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "close_failed", err, nil)
 			}
 
 			if closeWindow {
-				return output.Print(map[string]any{
+				return output.Print(ctx, map[string]any{
 					"success": true,
 					"message": "Window closed",
 				})
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": "Tab closed",
 				"title":   strings.TrimSpace(result),
@@ -500,9 +526,10 @@ func newBookmarksCmd() *cobra.Command {
 		Short: "List Safari bookmarks (Favorites and other folders)",
 		Long:  "List Safari bookmarks from Bookmarks.plist. Requires Full Disk Access for your terminal.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			homeDir, err := os.UserHomeDir()
 			if err != nil {
-				return output.PrintError("home_dir_failed", "Failed to get home directory", nil)
+				return output.PrintError(ctx, "home_dir_failed", "Failed to get home directory", nil)
 			}
 
 			bookmarksPlist := filepath.Join(homeDir, "Library", "Safari", "Bookmarks.plist")
@@ -516,14 +543,14 @@ func newBookmarksCmd() *cobra.Command {
 			if err := plistCmd.Run(); err != nil {
 				errMsg := stderr.String()
 				if strings.Contains(errMsg, "permission") || strings.Contains(errMsg, "couldn't be opened") {
-					return output.PrintError("permission_denied",
+					return output.PrintError(ctx, "permission_denied",
 						"Full Disk Access required to read Safari bookmarks",
 						map[string]string{
 							"path":       bookmarksPlist,
 							"suggestion": "Go to System Settings > Privacy & Security > Full Disk Access and add your terminal",
 						})
 				}
-				return output.PrintError("bookmarks_failed",
+				return output.PrintError(ctx, "bookmarks_failed",
 					"Failed to read bookmarks plist",
 					map[string]string{"error": errMsg})
 			}
@@ -542,7 +569,7 @@ func newBookmarksCmd() *cobra.Command {
 				folderName = "Favorites"
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"bookmarks": bookmarks,
 				"count":     len(bookmarks),
 				"folder":    folderName,
@@ -689,9 +716,10 @@ func newReadingListCmd() *cobra.Command {
 		Short: "List Safari Reading List items",
 		Long:  "List Safari Reading List items from Bookmarks.plist. Requires Full Disk Access for your terminal.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			homeDir, err := os.UserHomeDir()
 			if err != nil {
-				return output.PrintError("home_dir_failed", "Failed to get home directory", nil)
+				return output.PrintError(ctx, "home_dir_failed", "Failed to get home directory", nil)
 			}
 
 			bookmarksPlist := filepath.Join(homeDir, "Library", "Safari", "Bookmarks.plist")
@@ -705,21 +733,21 @@ func newReadingListCmd() *cobra.Command {
 			if err := plistCmd.Run(); err != nil {
 				errMsg := stderr.String()
 				if strings.Contains(errMsg, "permission") || strings.Contains(errMsg, "couldn't be opened") {
-					return output.PrintError("permission_denied",
+					return output.PrintError(ctx, "permission_denied",
 						"Full Disk Access required to read Safari Reading List",
 						map[string]string{
 							"path":       bookmarksPlist,
 							"suggestion": "Go to System Settings > Privacy & Security > Full Disk Access and add your terminal",
 						})
 				}
-				return output.PrintError("reading_list_failed",
+				return output.PrintError(ctx, "reading_list_failed",
 					"Failed to read bookmarks plist",
 					map[string]string{"error": errMsg})
 			}
 
 			items := parseReadingListJSON(stdout.Bytes(), limit)
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"items": items,
 				"count": len(items),
 			})
@@ -740,6 +768,7 @@ func newAddReadingCmd() *cobra.Command {
 		Short: "Add URL to Safari Reading List",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			url := args[0]
 
 			// Add https:// if no protocol specified
@@ -765,6 +794,10 @@ tell application "Safari"
 	return "Added to Reading List"
 end tell`, escapeAppleScript(url))
 
+			if dryrun.Enabled(ctx) {
+				return dryrun.AppleScript(ctx, script)
+			}
+
 			result, err := runAppleScript(script)
 			if err != nil {
 				// Try alternative method using Safari's menu
@@ -783,7 +816,7 @@ return "Added to Reading List"`, escapeAppleScript(url))
 
 				result, err = runAppleScript(altScript)
 				if err != nil {
-					return output.PrintError("add_reading_failed", err.Error(),
+					return output.PrintErr(ctx, "add_reading_failed", err,
 						map[string]string{
 							"url":        url,
 							"suggestion": "Make sure Safari has accessibility permissions enabled",
@@ -796,7 +829,7 @@ return "Added to Reading List"`, escapeAppleScript(url))
 				displayTitle = url
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"success": true,
 				"message": strings.TrimSpace(result),
 				"url":     url,
@@ -823,16 +856,17 @@ func newHistoryCmd() *cobra.Command {
 		Short: "Get recent Safari history",
 		Long:  "Get recent Safari browsing history. History is read from Safari's History.db SQLite database.",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			homeDir, err := os.UserHomeDir()
 			if err != nil {
-				return output.PrintError("home_dir_failed", "Failed to get home directory", nil)
+				return output.PrintError(ctx, "home_dir_failed", "Failed to get home directory", nil)
 			}
 
 			historyDB := filepath.Join(homeDir, "Library", "Safari", "History.db")
 
 			// Check if the file exists
 			if _, err := os.Stat(historyDB); os.IsNotExist(err) {
-				return output.PrintError("history_not_found",
+				return output.PrintError(ctx, "history_not_found",
 					"Safari history database not found",
 					map[string]string{
 						"path":       historyDB,
@@ -843,7 +877,7 @@ func newHistoryCmd() *cobra.Command {
 			// Safari may have the database locked, so we need to copy it first
 			tmpFile, err := os.CreateTemp("", "safari_history_*.db")
 			if err != nil {
-				return output.PrintError("temp_file_failed",
+				return output.PrintError(ctx, "temp_file_failed",
 					"Failed to create temporary file",
 					map[string]string{"error": err.Error()})
 			}
@@ -852,7 +886,7 @@ func newHistoryCmd() *cobra.Command {
 			cpCmd := exec.Command("cp", historyDB, tmpDB)
 			if err := cpCmd.Run(); err != nil {
 				os.Remove(tmpDB)
-				return output.PrintError("permission_denied",
+				return output.PrintError(ctx, "permission_denied",
 					"Full Disk Access required to read Safari history",
 					map[string]string{
 						"path":       historyDB,
@@ -864,7 +898,7 @@ func newHistoryCmd() *cobra.Command {
 			// Open the copied database
 			db, err := sql.Open("sqlite3", tmpDB+"?mode=ro")
 			if err != nil {
-				return output.PrintError("db_open_failed",
+				return output.PrintError(ctx, "db_open_failed",
 					"Failed to open history database",
 					map[string]string{"error": err.Error()})
 			}
@@ -924,7 +958,7 @@ func newHistoryCmd() *cobra.Command {
 
 				rows, err = db.Query(altQuery, queryArgs...)
 				if err != nil {
-					return output.PrintError("query_failed",
+					return output.PrintError(ctx, "query_failed",
 						"Failed to query history database",
 						map[string]string{"error": err.Error()})
 				}
@@ -960,10 +994,10 @@ func newHistoryCmd() *cobra.Command {
 				items = append(items, item)
 			}
 			if err := rows.Err(); err != nil {
-				return output.PrintError("query_error", err.Error(), nil)
+				return output.PrintErr(ctx, "query_error", err, nil)
 			}
 
-			return output.Print(map[string]any{
+			return output.Print(ctx, map[string]any{
 				"items":  items,
 				"count":  len(items),
 				"days":   days,
@@ -2,6 +2,7 @@ package sysinfo
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -94,6 +96,16 @@ type NetInfo struct {
 	Interfaces []NetInterface `json:"interfaces"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "sysinfo",
+		Name:        "System Info",
+		Group:       "system",
+		Description: "CPU, memory, disk and process monitoring",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "sysinfo",
@@ -116,7 +128,8 @@ func newOverviewCmd() *cobra.Command {
 		Use:   "overview",
 		Short: "System overview (CPU, RAM, uptime)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return getOverview()
+			ctx := cmd.Context()
+			return getOverview(ctx)
 		},
 	}
 }
@@ -126,7 +139,8 @@ func newCPUCmd() *cobra.Command {
 		Use:   "cpu",
 		Short: "CPU information and usage",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return getCPU()
+			ctx := cmd.Context()
+			return getCPU(ctx)
 		},
 	}
 }
@@ -136,7 +150,8 @@ func newMemoryCmd() *cobra.Command {
 		Use:   "memory",
 		Short: "Memory usage details",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return getMemory()
+			ctx := cmd.Context()
+			return getMemory(ctx)
 		},
 	}
 }
@@ -146,7 +161,8 @@ func newDiskCmd() *cobra.Command {
 		Use:   "disk",
 		Short: "Disk usage by partition",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return getDisk()
+			ctx := cmd.Context()
+			return getDisk(ctx)
 		},
 	}
 }
@@ -158,7 +174,8 @@ func newProcessesCmd() *cobra.Command {
 		Use:   "processes",
 		Short: "Top processes by CPU and memory",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return getProcesses(limit)
+			ctx := cmd.Context()
+			return getProcesses(ctx, limit)
 		},
 	}
 
@@ -172,12 +189,13 @@ func newNetworkCmd() *cobra.Command {
 		Use:   "network",
 		Short: "Network interface information",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return getNetwork()
+			ctx := cmd.Context()
+			return getNetwork(ctx)
 		},
 	}
 }
 
-func getOverview() error {
+func getOverview(ctx context.Context) error {
 	hostname, _ := os.Hostname()
 
 	info := Overview{
@@ -217,10 +235,10 @@ func getOverview() error {
 	mem, _ := getMemInfo()
 	info.Memory = mem
 
-	return output.Print(info)
+	return output.Print(ctx, info)
 }
 
-func getCPU() error {
+func getCPU(ctx context.Context) error {
 	info := CPUInfo{
 		Cores:       runtime.NumCPU(),
 		LogicalCPUs: runtime.NumCPU(),
@@ -253,15 +271,15 @@ func getCPU() error {
 		}
 	}
 
-	return output.Print(info)
+	return output.Print(ctx, info)
 }
 
-func getMemory() error {
+func getMemory(ctx context.Context) error {
 	mem, err := getMemInfo()
 	if err != nil {
-		return output.PrintError("memory_error", err.Error(), nil)
+		return output.PrintErr(ctx, "memory_error", err, nil)
 	}
-	return output.Print(mem)
+	return output.Print(ctx, mem)
 }
 
 func getMemInfo() (*MemInfo, error) {
@@ -374,10 +392,10 @@ func getMemLinux() (*MemInfo, error) {
 	}, nil
 }
 
-func getDisk() error {
+func getDisk(ctx context.Context) error {
 	out, err := exec.Command("df", "-h").Output()
 	if err != nil {
-		return output.PrintError("disk_error", fmt.Sprintf("df failed: %v", err), nil)
+		return output.PrintError(ctx, "disk_error", fmt.Sprintf("df failed: %v", err), nil)
 	}
 
 	var partitions []DiskPartition
@@ -416,7 +434,7 @@ func getDisk() error {
 		partitions = append(partitions, p)
 	}
 
-	return output.Print(DiskInfo{Partitions: partitions})
+	return output.Print(ctx, DiskInfo{Partitions: partitions})
 }
 
 func parseSizeToGB(s string) float64 {
@@ -442,7 +460,7 @@ func parseSizeToGB(s string) float64 {
 	return v * multiplier
 }
 
-func getProcesses(limit int) error {
+func getProcesses(ctx context.Context, limit int) error {
 	var out []byte
 	var err error
 
@@ -450,12 +468,12 @@ func getProcesses(limit int) error {
 	case osDarwin, osLinux:
 		out, err = exec.Command("ps", "axo", "pid,pcpu,pmem,rss,comm", "-r").Output()
 	default:
-		return output.PrintError("platform_unsupported",
+		return output.PrintError(ctx, "platform_unsupported",
 			fmt.Sprintf("process listing not supported on %s", runtime.GOOS), nil)
 	}
 
 	if err != nil {
-		return output.PrintError("process_error", fmt.Sprintf("ps failed: %v", err), nil)
+		return output.PrintError(ctx, "process_error", fmt.Sprintf("ps failed: %v", err), nil)
 	}
 
 	var procs []ProcessInfo
@@ -515,20 +533,20 @@ func getProcesses(limit int) error {
 		byMem = byMem[:limit]
 	}
 
-	return output.Print(ProcessList{
+	return output.Print(ctx, ProcessList{
 		ByCPU:    byCPU,
 		ByMemory: byMem,
 	})
 }
 
-func getNetwork() error {
+func getNetwork(ctx context.Context) error {
 	var interfaces []NetInterface
 
 	switch runtime.GOOS {
 	case osDarwin:
 		out, err := exec.Command("ifconfig").Output()
 		if err != nil {
-			return output.PrintError("network_error", fmt.Sprintf("ifconfig failed: %v", err), nil)
+			return output.PrintError(ctx, "network_error", fmt.Sprintf("ifconfig failed: %v", err), nil)
 		}
 		interfaces = parseIfconfig(string(out))
 	case osLinux:
@@ -537,18 +555,18 @@ func getNetwork() error {
 			// Fallback to ifconfig
 			out, err = exec.Command("ifconfig").Output()
 			if err != nil {
-				return output.PrintError("network_error", "could not get network info", nil)
+				return output.PrintError(ctx, "network_error", "could not get network info", nil)
 			}
 			interfaces = parseIfconfig(string(out))
 		} else {
 			interfaces = parseIPBrief(string(out))
 		}
 	default:
-		return output.PrintError("platform_unsupported",
+		return output.PrintError(ctx, "platform_unsupported",
 			fmt.Sprintf("network info not supported on %s", runtime.GOOS), nil)
 	}
 
-	return output.Print(NetInfo{Interfaces: interfaces})
+	return output.Print(ctx, NetInfo{Interfaces: interfaces})
 }
 
 //nolint:gocyclo // complex but clear sequential logic
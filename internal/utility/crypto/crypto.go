@@ -11,12 +11,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://api.coingecko.com/api/v3"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Price is LLM-friendly price output
 type Price struct {
@@ -57,6 +59,16 @@ type TrendingCoin struct {
 	PriceBTC   float64 `json:"price_btc"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "crypto",
+		Name:        "CoinGecko",
+		Group:       "utility",
+		Description: "Cryptocurrency prices, market data, and trending coins",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "crypto",
@@ -79,11 +91,12 @@ func newPriceCmd() *cobra.Command {
 		Short: "Get current prices for coins (e.g., bitcoin ethereum)",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			ids := strings.Join(args, ",")
 			reqURL := fmt.Sprintf("%s/coins/markets?vs_currency=usd&ids=%s&order=market_cap_desc&sparkline=false",
 				baseURL, url.QueryEscape(ids))
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -100,11 +113,11 @@ func newPriceCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			if len(data) == 0 {
-				return output.PrintError("not_found", "No coins found", nil)
+				return output.PrintError(ctx, "not_found", "No coins found", nil)
 			}
 
 			var prices []Price
@@ -120,7 +133,7 @@ func newPriceCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(prices)
+			return output.Print(ctx, prices)
 		},
 	}
 
@@ -133,18 +146,19 @@ func newInfoCmd() *cobra.Command {
 		Short: "Get detailed info for a coin (e.g., bitcoin)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			coinID := strings.ToLower(args[0])
 			reqURL := fmt.Sprintf("%s/coins/%s?localization=false&tickers=false&community_data=false&developer_data=false",
 				baseURL, url.PathEscape(coinID))
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode == 404 {
-				return output.PrintError("not_found", "Coin not found: "+coinID, nil)
+				return output.PrintError(ctx, "not_found", "Coin not found: "+coinID, nil)
 			}
 
 			var data struct {
@@ -186,7 +200,7 @@ func newInfoCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			// Truncate description for LLM friendliness
@@ -218,7 +232,7 @@ func newInfoCmd() *cobra.Command {
 				Homepage:      homepage,
 			}
 
-			return output.Print(info)
+			return output.Print(ctx, info)
 		},
 	}
 
@@ -232,10 +246,11 @@ func newTopCmd() *cobra.Command {
 		Use:   "top",
 		Short: "Get top coins by market cap",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			reqURL := fmt.Sprintf("%s/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=%d&page=1&sparkline=false",
 				baseURL, limit)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -252,7 +267,7 @@ func newTopCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			var prices []Price
@@ -268,7 +283,7 @@ func newTopCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(prices)
+			return output.Print(ctx, prices)
 		},
 	}
 
@@ -282,9 +297,10 @@ func newTrendingCmd() *cobra.Command {
 		Use:   "trending",
 		Short: "Get trending coins (most searched)",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			reqURL := fmt.Sprintf("%s/search/trending", baseURL)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -303,7 +319,7 @@ func newTrendingCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			var trending []TrendingCoin
@@ -318,10 +334,10 @@ func newTrendingCmd() *cobra.Command {
 			}
 
 			if len(trending) == 0 {
-				return output.PrintError("not_found", "No trending coins found", nil)
+				return output.PrintError(ctx, "not_found", "No trending coins found", nil)
 			}
 
-			return output.Print(trending)
+			return output.Print(ctx, trending)
 		},
 	}
 
@@ -336,10 +352,11 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search for coins by name or symbol",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := args[0]
 			reqURL := fmt.Sprintf("%s/search?query=%s", baseURL, url.QueryEscape(query))
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -355,11 +372,11 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			if len(data.Coins) == 0 {
-				return output.PrintError("not_found", "No coins found for: "+query, nil)
+				return output.PrintError(ctx, "not_found", "No coins found for: "+query, nil)
 			}
 
 			// Limit results
@@ -385,7 +402,7 @@ func newSearchCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(results)
+			return output.Print(ctx, results)
 		},
 	}
 
@@ -394,13 +411,13 @@ func newSearchCmd() *cobra.Command {
 	return cmd
 }
 
-func doRequest(reqURL string) (*http.Response, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, reqURL string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -408,17 +425,17 @@ func doRequest(reqURL string) (*http.Response, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	if resp.StatusCode == 429 {
 		resp.Body.Close()
-		return nil, output.PrintError("rate_limited", "CoinGecko rate limit exceeded, try again later", nil)
+		return nil, output.PrintError(ctx, "rate_limited", "CoinGecko rate limit exceeded, try again later", nil)
 	}
 
 	if resp.StatusCode >= 400 {
 		resp.Body.Close()
-		return nil, output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	return resp, nil
@@ -11,12 +11,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://api.frankfurter.app"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // ExchangeRate is LLM-friendly exchange rate output
 type ExchangeRate struct {
@@ -42,6 +44,16 @@ type Currency struct {
 	Name string `json:"name"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "currency",
+		Name:        "Currency Exchange",
+		Group:       "utility",
+		Description: "Real-time currency exchange rates and conversion",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "currency",
@@ -62,12 +74,13 @@ func newRateCmd() *cobra.Command {
 		Short: "Get exchange rate between two currencies (e.g., USD EUR)",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			from := strings.ToUpper(args[0])
 			to := strings.ToUpper(args[1])
 
 			reqURL := fmt.Sprintf("%s/latest?from=%s&to=%s", baseURL, from, to)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -81,12 +94,12 @@ func newRateCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			rate, ok := data.Rates[to]
 			if !ok {
-				return output.PrintError("not_found", "Currency not found: "+to, nil)
+				return output.PrintError(ctx, "not_found", "Currency not found: "+to, nil)
 			}
 
 			result := ExchangeRate{
@@ -96,7 +109,7 @@ func newRateCmd() *cobra.Command {
 				Date: data.Date,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -109,9 +122,10 @@ func newConvertCmd() *cobra.Command {
 		Short: "Convert amount between currencies (e.g., 100 USD EUR)",
 		Args:  cobra.ExactArgs(3),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			amount, err := strconv.ParseFloat(args[0], 64)
 			if err != nil {
-				return output.PrintError("invalid_amount", "Amount must be a number", nil)
+				return output.PrintError(ctx, "invalid_amount", "Amount must be a number", nil)
 			}
 
 			from := strings.ToUpper(args[1])
@@ -119,7 +133,7 @@ func newConvertCmd() *cobra.Command {
 
 			reqURL := fmt.Sprintf("%s/latest?amount=%f&from=%s&to=%s", baseURL, amount, from, to)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -133,12 +147,12 @@ func newConvertCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			converted, ok := data.Rates[to]
 			if !ok {
-				return output.PrintError("not_found", "Currency not found: "+to, nil)
+				return output.PrintError(ctx, "not_found", "Currency not found: "+to, nil)
 			}
 
 			// Calculate the rate (converted / amount)
@@ -153,7 +167,7 @@ func newConvertCmd() *cobra.Command {
 				Date:   data.Date,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -165,9 +179,10 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List available currencies",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			reqURL := fmt.Sprintf("%s/currencies", baseURL)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -176,7 +191,7 @@ func newListCmd() *cobra.Command {
 			var data map[string]string
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			var currencies []Currency
@@ -188,23 +203,23 @@ func newListCmd() *cobra.Command {
 			}
 
 			if len(currencies) == 0 {
-				return output.PrintError("not_found", "No currencies found", nil)
+				return output.PrintError(ctx, "not_found", "No currencies found", nil)
 			}
 
-			return output.Print(currencies)
+			return output.Print(ctx, currencies)
 		},
 	}
 
 	return cmd
 }
 
-func doRequest(reqURL string) (*http.Response, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, reqURL string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -212,22 +227,22 @@ func doRequest(reqURL string) (*http.Response, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	if resp.StatusCode == 429 {
 		resp.Body.Close()
-		return nil, output.PrintError("rate_limited", "Rate limit exceeded, try again later", nil)
+		return nil, output.PrintError(ctx, "rate_limited", "Rate limit exceeded, try again later", nil)
 	}
 
 	if resp.StatusCode == 404 {
 		resp.Body.Close()
-		return nil, output.PrintError("not_found", "Currency not found or invalid", nil)
+		return nil, output.PrintError(ctx, "not_found", "Currency not found or invalid", nil)
 	}
 
 	if resp.StatusCode >= 400 {
 		resp.Body.Close()
-		return nil, output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	return resp, nil
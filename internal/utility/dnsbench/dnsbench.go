@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -78,6 +79,16 @@ var testDomains = []string{
 	"netflix.com",
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "dnsbench",
+		Name:        "DNS Benchmark",
+		Group:       "utility",
+		Description: "Benchmark DNS resolvers by response time",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "dnsbench",
@@ -99,7 +110,8 @@ func newRunCmd() *cobra.Command {
 		Short: "Benchmark all popular DNS resolvers",
 		Long:  "Benchmark popular public DNS resolvers to find the fastest one from your location.",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runBenchmark(queries)
+			ctx := cmd.Context()
+			return runBenchmark(ctx, queries)
 		},
 	}
 
@@ -114,16 +126,17 @@ func newTestCmd() *cobra.Command {
 		Short: "Test a specific DNS resolver",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return testResolver(args[0])
+			ctx := cmd.Context()
+			return testResolver(ctx, args[0])
 		},
 	}
 }
 
-func runBenchmark(queriesPerResolver int) error {
+func runBenchmark(ctx context.Context, queriesPerResolver int) error {
 	results := make([]BenchResult, 0, len(defaultResolvers))
 
 	for _, resolver := range defaultResolvers {
-		result := benchmarkResolver(resolver, queriesPerResolver)
+		result := benchmarkResolver(ctx, resolver, queriesPerResolver)
 		results = append(results, result)
 	}
 
@@ -147,10 +160,10 @@ func runBenchmark(queriesPerResolver int) error {
 		TestedAt:   time.Now().Format(time.RFC3339),
 	}
 
-	return output.Print(report)
+	return output.Print(ctx, report)
 }
 
-func benchmarkResolver(resolver Resolver, numQueries int) BenchResult {
+func benchmarkResolver(ctx context.Context, resolver Resolver, numQueries int) BenchResult {
 	var times []float64
 	failures := 0
 
@@ -165,7 +178,7 @@ func benchmarkResolver(resolver Resolver, numQueries int) BenchResult {
 	for i := 0; i < numQueries; i++ {
 		domain := testDomains[i%len(testDomains)]
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		start := time.Now()
 		_, err := r.LookupHost(ctx, domain)
 		elapsed := float64(time.Since(start).Microseconds()) / 1000.0
@@ -213,7 +226,7 @@ func benchmarkResolver(resolver Resolver, numQueries int) BenchResult {
 	}
 }
 
-func testResolver(address string) error {
+func testResolver(ctx context.Context, address string) error {
 	if _, _, err := net.SplitHostPort(address); err != nil {
 		address += ":53"
 	}
@@ -232,7 +245,7 @@ func testResolver(address string) error {
 	var totalMs float64
 
 	for _, domain := range testDomains {
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 		start := time.Now()
 		ips, err := r.LookupHost(ctx, domain)
 		elapsed := float64(time.Since(start).Microseconds()) / 1000.0
@@ -265,7 +278,7 @@ func testResolver(address string) error {
 		avgMs = totalMs / float64(successCount)
 	}
 
-	return output.Print(TestResult{
+	return output.Print(ctx, TestResult{
 		Name:    resolver.Name,
 		Address: resolver.Address,
 		AvgMs:   avgMs,
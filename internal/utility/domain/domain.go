@@ -12,11 +12,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var (
-	httpClient = &http.Client{Timeout: 30 * time.Second}
+	httpClient = transport.New(30 * time.Second)
 	whoisURL   = "https://whois.freeaiapi.xyz/"
 	dnsURL     = "https://dns.google/resolve"
 )
@@ -65,6 +67,16 @@ type SSLInfo struct {
 	IsNotYet  bool     `json:"is_not_yet_valid"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "domain",
+		Name:        "DNS/WHOIS/SSL",
+		Group:       "utility",
+		Description: "DNS lookups, WHOIS domain info, and SSL certificate inspection",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "domain",
@@ -87,6 +99,7 @@ func newDNSCmd() *cobra.Command {
 		Short: "DNS lookup (A, AAAA, MX, TXT, NS records)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			domain := cleanDomain(args[0])
 
 			types := []string{"A", "AAAA", "MX", "TXT", "NS"}
@@ -96,7 +109,7 @@ func newDNSCmd() *cobra.Command {
 
 			var allRecords []DNSRecord
 			for _, t := range types {
-				records, err := dnsLookup(domain, t)
+				records, err := dnsLookup(ctx, domain, t)
 				if err != nil {
 					continue
 				}
@@ -104,7 +117,7 @@ func newDNSCmd() *cobra.Command {
 			}
 
 			if len(allRecords) == 0 {
-				return output.PrintError("not_found", "No DNS records found for: "+domain, nil)
+				return output.PrintError(ctx, "not_found", "No DNS records found for: "+domain, nil)
 			}
 
 			result := DNSResult{
@@ -112,7 +125,7 @@ func newDNSCmd() *cobra.Command {
 				Records: allRecords,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -127,6 +140,7 @@ func newWhoisCmd() *cobra.Command {
 		Short: "WHOIS lookup for domain registration info",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			domain := cleanDomain(args[0])
 
 			// API requires name and suffix as separate parameters
@@ -139,7 +153,7 @@ func newWhoisCmd() *cobra.Command {
 				reqURL = fmt.Sprintf("%s?name=%s", whoisURL, url.QueryEscape(domain))
 			}
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -148,7 +162,7 @@ func newWhoisCmd() *cobra.Command {
 			var data map[string]any
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			// Check if the API returned an error
@@ -157,7 +171,7 @@ func newWhoisCmd() *cobra.Command {
 				if errMsg == "" {
 					errMsg = "WHOIS lookup failed"
 				}
-				return output.PrintError("whois_error", errMsg,
+				return output.PrintError(ctx, "whois_error", errMsg,
 					map[string]string{"domain": domain})
 			}
 
@@ -187,7 +201,7 @@ func newWhoisCmd() *cobra.Command {
 				info.NameServers = getString(data, "nameServers")
 			}
 
-			return output.Print(info)
+			return output.Print(ctx, info)
 		},
 	}
 
@@ -202,6 +216,7 @@ func newSSLCmd() *cobra.Command {
 		Short: "SSL certificate information for a domain",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			domain := cleanDomain(args[0])
 
 			addr := fmt.Sprintf("%s:%d", domain, port)
@@ -210,13 +225,13 @@ func newSSLCmd() *cobra.Command {
 				InsecureSkipVerify: true, //nolint:gosec // intentional: inspecting invalid certs
 			})
 			if err != nil {
-				return output.PrintError("connection_failed", "Could not connect to "+addr+": "+err.Error(), nil)
+				return output.PrintError(ctx, "connection_failed", "Could not connect to "+addr+": "+err.Error(), nil)
 			}
 			defer conn.Close()
 
 			certs := conn.ConnectionState().PeerCertificates
 			if len(certs) == 0 {
-				return output.PrintError("no_cert", "No certificate found for: "+domain, nil)
+				return output.PrintError(ctx, "no_cert", "No certificate found for: "+domain, nil)
 			}
 
 			cert := certs[0]
@@ -242,7 +257,7 @@ func newSSLCmd() *cobra.Command {
 				IsNotYet:  isNotYet,
 			}
 
-			return output.Print(info)
+			return output.Print(ctx, info)
 		},
 	}
 
@@ -251,11 +266,11 @@ func newSSLCmd() *cobra.Command {
 	return cmd
 }
 
-func dnsLookup(domain, recordType string) ([]DNSRecord, error) {
+func dnsLookup(ctx context.Context, domain, recordType string) ([]DNSRecord, error) {
 	reqURL := fmt.Sprintf("%s?name=%s&type=%s",
 		dnsURL, url.QueryEscape(domain), url.QueryEscape(recordType))
 
-	resp, err := doRequest(reqURL)
+	resp, err := doRequest(ctx, reqURL)
 	if err != nil {
 		return nil, err
 	}
@@ -342,13 +357,13 @@ func getString(data map[string]any, key string) string {
 	return ""
 }
 
-func doRequest(reqURL string) (*http.Response, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, reqURL string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -356,17 +371,17 @@ func doRequest(reqURL string) (*http.Response, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	if resp.StatusCode == 429 {
 		resp.Body.Close()
-		return nil, output.PrintError("rate_limited", "Rate limit exceeded, try again later", nil)
+		return nil, output.PrintError(ctx, "rate_limited", "Rate limit exceeded, try again later", nil)
 	}
 
 	if resp.StatusCode >= 400 {
 		resp.Body.Close()
-		return nil, output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	return resp, nil
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -123,6 +124,7 @@ func TestDNSTypeToString(t *testing.T) {
 }
 
 func TestDNSLookup(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		data := map[string]any{
 			"Status": 0,
@@ -146,7 +148,7 @@ func TestDNSLookup(t *testing.T) {
 	dnsURL = srv.URL
 	defer func() { dnsURL = oldURL }()
 
-	records, err := dnsLookup("example.com", "A")
+	records, err := dnsLookup(ctx, "example.com", "A")
 	if err != nil {
 		t.Errorf("dnsLookup failed: %v", err)
 	}
@@ -162,6 +164,7 @@ func TestDNSLookup(t *testing.T) {
 }
 
 func TestDNSLookupNotFound(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		data := map[string]any{
 			"Status": 3,
@@ -174,7 +177,7 @@ func TestDNSLookupNotFound(t *testing.T) {
 	dnsURL = srv.URL
 	defer func() { dnsURL = oldURL }()
 
-	_, err := dnsLookup("invalid.example", "A")
+	_, err := dnsLookup(ctx, "invalid.example", "A")
 	if err == nil {
 		t.Error("expected error for not found domain, got nil")
 	}
@@ -11,10 +11,12 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 const baseURL = "https://nominatim.openstreetmap.org"
 
@@ -27,6 +29,16 @@ type GeoResult struct {
 	Importance  float64 `json:"importance"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "geocoding",
+		Name:        "Geocoding",
+		Group:       "utility",
+		Description: "Forward and reverse geocoding (address to coordinates and back)",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the geocoding command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -47,8 +59,9 @@ func newForwardCmd() *cobra.Command {
 		Short: "Convert address to coordinates",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			query := strings.Join(args, " ")
-			return forwardGeocode(query)
+			return forwardGeocode(ctx, query)
 		},
 	}
 
@@ -61,22 +74,23 @@ func newReverseCmd() *cobra.Command {
 		Short: "Convert coordinates to address",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return reverseGeocode(args[0], args[1])
+			ctx := cmd.Context()
+			return reverseGeocode(ctx, args[0], args[1])
 		},
 	}
 
 	return cmd
 }
 
-func forwardGeocode(query string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func forwardGeocode(ctx context.Context, query string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	reqURL := fmt.Sprintf("%s/search?q=%s&format=json&limit=5", baseURL, url.QueryEscape(query))
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -84,12 +98,12 @@ func forwardGeocode(query string) error {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	var data []struct {
@@ -101,11 +115,11 @@ func forwardGeocode(query string) error {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return output.PrintError("parse_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "parse_failed", err, nil)
 	}
 
 	if len(data) == 0 {
-		return output.PrintError("not_found", "No results found for: "+query, nil)
+		return output.PrintError(ctx, "not_found", "No results found for: "+query, nil)
 	}
 
 	results := make([]GeoResult, 0, len(data))
@@ -119,11 +133,11 @@ func forwardGeocode(query string) error {
 		})
 	}
 
-	return output.Print(results)
+	return output.Print(ctx, results)
 }
 
-func reverseGeocode(lat, lon string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func reverseGeocode(ctx context.Context, lat, lon string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	reqURL := fmt.Sprintf("%s/reverse?lat=%s&lon=%s&format=json",
@@ -131,7 +145,7 @@ func reverseGeocode(lat, lon string) error {
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -139,12 +153,12 @@ func reverseGeocode(lat, lon string) error {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	var data struct {
@@ -157,11 +171,11 @@ func reverseGeocode(lat, lon string) error {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return output.PrintError("parse_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "parse_failed", err, nil)
 	}
 
 	if data.Error != "" {
-		return output.PrintError("not_found", data.Error, nil)
+		return output.PrintError(ctx, "not_found", data.Error, nil)
 	}
 
 	result := GeoResult{
@@ -172,5 +186,5 @@ func reverseGeocode(lat, lon string) error {
 		Importance:  data.Importance,
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
@@ -11,12 +11,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://date.nager.at/api/v3"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Holiday is LLM-friendly holiday output
 type Holiday struct {
@@ -52,6 +54,16 @@ type UpcomingHolidays struct {
 	Holidays    []Holiday `json:"holidays"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "holidays",
+		Name:        "Public Holidays",
+		Group:       "utility",
+		Description: "Public holidays by country and year",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "holidays",
@@ -72,27 +84,28 @@ func newListCmd() *cobra.Command {
 		Short: "List public holidays for a country (e.g., US 2024)",
 		Args:  cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			countryCode := strings.ToUpper(args[0])
 			year := time.Now().Year()
 
 			if len(args) > 1 {
 				parsedYear, err := strconv.Atoi(args[1])
 				if err != nil {
-					return output.PrintError("invalid_year", "Year must be a valid number", nil)
+					return output.PrintError(ctx, "invalid_year", "Year must be a valid number", nil)
 				}
 				year = parsedYear
 			}
 
 			reqURL := fmt.Sprintf("%s/PublicHolidays/%d/%s", baseURL, year, countryCode)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode == 404 {
-				return output.PrintError("not_found", "Country not found: "+countryCode, nil)
+				return output.PrintError(ctx, "not_found", "Country not found: "+countryCode, nil)
 			}
 
 			var data []struct {
@@ -107,7 +120,7 @@ func newListCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			var holidays []Holiday
@@ -131,7 +144,7 @@ func newListCmd() *cobra.Command {
 				Holidays:    holidays,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -146,6 +159,7 @@ func newNextCmd() *cobra.Command {
 		Short: "Get upcoming holidays from current date",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			countryCode := strings.ToUpper(args[0])
 			now := time.Now()
 			year := now.Year()
@@ -153,14 +167,14 @@ func newNextCmd() *cobra.Command {
 
 			reqURL := fmt.Sprintf("%s/PublicHolidays/%d/%s", baseURL, year, countryCode)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
 			defer resp.Body.Close()
 
 			if resp.StatusCode == 404 {
-				return output.PrintError("not_found", "Country not found: "+countryCode, nil)
+				return output.PrintError(ctx, "not_found", "Country not found: "+countryCode, nil)
 			}
 
 			var data []struct {
@@ -175,7 +189,7 @@ func newNextCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			var upcoming []Holiday
@@ -200,7 +214,7 @@ func newNextCmd() *cobra.Command {
 			}
 
 			if len(upcoming) == 0 {
-				return output.PrintError("not_found", "No upcoming holidays found for "+countryCode+" in "+strconv.Itoa(year), nil)
+				return output.PrintError(ctx, "not_found", "No upcoming holidays found for "+countryCode+" in "+strconv.Itoa(year), nil)
 			}
 
 			result := UpcomingHolidays{
@@ -210,7 +224,7 @@ func newNextCmd() *cobra.Command {
 				Holidays:    upcoming,
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -224,9 +238,10 @@ func newCountriesCmd() *cobra.Command {
 		Use:   "countries",
 		Short: "List available country codes",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			reqURL := fmt.Sprintf("%s/AvailableCountries", baseURL)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -238,11 +253,11 @@ func newCountriesCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			if len(data) == 0 {
-				return output.PrintError("not_found", "No countries found", nil)
+				return output.PrintError(ctx, "not_found", "No countries found", nil)
 			}
 
 			var countries []Country
@@ -253,20 +268,20 @@ func newCountriesCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(countries)
+			return output.Print(ctx, countries)
 		},
 	}
 
 	return cmd
 }
 
-func doRequest(reqURL string) (*http.Response, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, reqURL string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -274,17 +289,17 @@ func doRequest(reqURL string) (*http.Response, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	if resp.StatusCode == 429 {
 		resp.Body.Close()
-		return nil, output.PrintError("rate_limited", "Nager.Date rate limit exceeded, try again later", nil)
+		return nil, output.PrintError(ctx, "rate_limited", "Nager.Date rate limit exceeded, try again later", nil)
 	}
 
 	if resp.StatusCode >= 400 && resp.StatusCode != 404 {
 		resp.Body.Close()
-		return nil, output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	return resp, nil
@@ -10,12 +10,14 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://ipinfo.io"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // IPInfo is LLM-friendly IP information
 type IPInfo struct {
@@ -30,6 +32,16 @@ type IPInfo struct {
 	Timezone string `json:"timezone,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "ipinfo",
+		Name:        "IP Geolocation",
+		Group:       "utility",
+		Description: "IP address lookup with geolocation data",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "ip",
@@ -49,14 +61,15 @@ func newLookupCmd() *cobra.Command {
 		Short: "Get geolocation info for an IP address",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			ip := args[0]
 
 			// Validate IP
 			if net.ParseIP(ip) == nil {
-				return output.PrintError("invalid_ip", "Invalid IP address: "+ip, nil)
+				return output.PrintError(ctx, "invalid_ip", "Invalid IP address: "+ip, nil)
 			}
 
-			return fetchIPInfo(ip)
+			return fetchIPInfo(ctx, ip)
 		},
 	}
 
@@ -68,15 +81,16 @@ func newMyIPCmd() *cobra.Command {
 		Use:   "me",
 		Short: "Get your current public IP and location",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fetchIPInfo("")
+			ctx := cmd.Context()
+			return fetchIPInfo(ctx, "")
 		},
 	}
 
 	return cmd
 }
 
-func fetchIPInfo(ip string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func fetchIPInfo(ctx context.Context, ip string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	reqURL := baseURL + "/json"
@@ -86,7 +100,7 @@ func fetchIPInfo(ip string) error {
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -94,22 +108,22 @@ func fetchIPInfo(ip string) error {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 429 {
-		return output.PrintError("rate_limited", "Rate limit exceeded, try again later", nil)
+		return output.PrintError(ctx, "rate_limited", "Rate limit exceeded, try again later", nil)
 	}
 
 	if resp.StatusCode >= 400 {
-		return output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	var info IPInfo
 	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
-		return output.PrintError("parse_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "parse_failed", err, nil)
 	}
 
-	return output.Print(info)
+	return output.Print(ctx, info)
 }
@@ -1,6 +1,7 @@
 package ipinfo
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -94,6 +95,7 @@ func TestMyIPCmd(t *testing.T) {
 }
 
 func TestFetchIPInfoRateLimit(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusTooManyRequests)
 		json.NewEncoder(w).Encode(map[string]string{"error": "rate limited"})
@@ -104,13 +106,14 @@ func TestFetchIPInfoRateLimit(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	err := fetchIPInfo("8.8.8.8")
+	err := fetchIPInfo(ctx, "8.8.8.8")
 	if err == nil {
 		t.Error("expected rate limit error, got nil")
 	}
 }
 
 func TestFetchIPInfoHTTPError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
@@ -120,13 +123,14 @@ func TestFetchIPInfoHTTPError(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	err := fetchIPInfo("8.8.8.8")
+	err := fetchIPInfo(ctx, "8.8.8.8")
 	if err == nil {
 		t.Error("expected HTTP error, got nil")
 	}
 }
 
 func TestFetchIPInfoParseError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("invalid json"))
 	}))
@@ -136,7 +140,7 @@ func TestFetchIPInfoParseError(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	err := fetchIPInfo("8.8.8.8")
+	err := fetchIPInfo(ctx, "8.8.8.8")
 	if err == nil {
 		t.Error("expected parse error, got nil")
 	}
@@ -11,15 +11,17 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
-var httpClient = &http.Client{
+var httpClient = transport.Wrap(&http.Client{
 	CheckRedirect: func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
 	},
 	Timeout: 30 * time.Second,
-}
+})
 
 // HeaderResult is LLM-friendly HTTP header result
 type HeaderResult struct {
@@ -67,6 +69,16 @@ var portServices = map[int]string{
 	27017: "MongoDB",
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "netdiag",
+		Name:        "Network Diagnostics",
+		Group:       "utility",
+		Description: "HTTP headers, port scanning, and DNS/ping diagnostics",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the netdiag command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -88,7 +100,8 @@ func newHeadersCmd() *cobra.Command {
 		Short: "Get HTTP response headers for a URL",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return fetchHeaders(args[0])
+			ctx := cmd.Context()
+			return fetchHeaders(ctx, args[0])
 		},
 	}
 
@@ -103,7 +116,8 @@ func newPortsCmd() *cobra.Command {
 		Short: "Scan common ports on a host",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return scanPorts(args[0], time.Duration(timeout)*time.Second)
+			ctx := cmd.Context()
+			return scanPorts(ctx, args[0], time.Duration(timeout)*time.Second)
 		},
 	}
 
@@ -118,32 +132,33 @@ func newPingCmd() *cobra.Command {
 		Short: "Resolve host to IPs and measure DNS resolve time",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return pingHost(args[0])
+			ctx := cmd.Context()
+			return pingHost(ctx, args[0])
 		},
 	}
 
 	return cmd
 }
 
-func fetchHeaders(rawURL string) error {
+func fetchHeaders(ctx context.Context, rawURL string) error {
 	// Ensure URL has a scheme
 	if !strings.HasPrefix(rawURL, "http://") && !strings.HasPrefix(rawURL, "https://") {
 		rawURL = "https://" + rawURL
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "HEAD", rawURL, http.NoBody)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
@@ -164,10 +179,10 @@ func fetchHeaders(rawURL string) error {
 		result.RedirectTo = location
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
 
-func scanPorts(host string, timeout time.Duration) error {
+func scanPorts(ctx context.Context, host string, timeout time.Duration) error {
 	ports := []int{80, 443, 22, 21, 25, 53, 3306, 5432, 6379, 8080, 8443, 27017}
 
 	var mu sync.Mutex
@@ -212,15 +227,15 @@ func scanPorts(host string, timeout time.Duration) error {
 		OpenPorts: open,
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
 
-func pingHost(host string) error {
+func pingHost(ctx context.Context, host string) error {
 	start := time.Now()
 
 	ips, err := net.LookupHost(host)
 	if err != nil {
-		return output.PrintError("resolve_failed", fmt.Sprintf("Failed to resolve %s: %s", host, err.Error()), nil)
+		return output.PrintError(ctx, "resolve_failed", fmt.Sprintf("Failed to resolve %s: %s", host, err.Error()), nil)
 	}
 
 	elapsed := time.Since(start)
@@ -231,5 +246,5 @@ func pingHost(host string) error {
 		ResolveTimeMs: elapsed.Milliseconds(),
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
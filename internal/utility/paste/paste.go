@@ -12,15 +12,17 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
-var httpClient = &http.Client{
+var httpClient = transport.Wrap(&http.Client{
 	CheckRedirect: func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
 	},
 	Timeout: 30 * time.Second,
-}
+})
 
 var apiURL = "https://dpaste.com/api/"
 
@@ -37,6 +39,16 @@ type Content struct {
 	Content string `json:"content"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "paste",
+		Name:        "Paste",
+		Group:       "utility",
+		Description: "Create and fetch text pastes (pastebin-like)",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the paste command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -59,6 +71,7 @@ func newCreateCmd() *cobra.Command {
 		Use:   "create [content]",
 		Short: "Create a new paste (also accepts stdin)",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			var content string
 
 			if len(args) > 0 {
@@ -69,17 +82,17 @@ func newCreateCmd() *cobra.Command {
 				if (stat.Mode() & os.ModeCharDevice) == 0 {
 					data, err := io.ReadAll(os.Stdin)
 					if err != nil {
-						return output.PrintError("read_failed", "Failed to read stdin: "+err.Error(), nil)
+						return output.PrintError(ctx, "read_failed", "Failed to read stdin: "+err.Error(), nil)
 					}
 					content = string(data)
 				}
 			}
 
 			if strings.TrimSpace(content) == "" {
-				return output.PrintError("missing_content", "Content is required (pass as argument or pipe via stdin)", nil)
+				return output.PrintError(ctx, "missing_content", "Content is required (pass as argument or pipe via stdin)", nil)
 			}
 
-			return createPaste(content, expiry, title)
+			return createPaste(ctx, content, expiry, title)
 		},
 	}
 
@@ -95,15 +108,16 @@ func newGetCmd() *cobra.Command {
 		Short: "Fetch a paste by URL",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return getPaste(args[0])
+			ctx := cmd.Context()
+			return getPaste(ctx, args[0])
 		},
 	}
 
 	return cmd
 }
 
-func createPaste(content string, expiryDays int, title string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func createPaste(ctx context.Context, content string, expiryDays int, title string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	formData := url.Values{}
@@ -115,7 +129,7 @@ func createPaste(content string, expiryDays int, title string) error {
 
 	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, strings.NewReader(formData.Encode()))
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -123,13 +137,13 @@ func createPaste(content string, expiryDays int, title string) error {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
-		return output.PrintError("create_failed", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body))), nil)
+		return output.PrintError(ctx, "create_failed", fmt.Sprintf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body))), nil)
 	}
 
 	// dpaste returns the URL in the response body (or Location header)
@@ -138,7 +152,7 @@ func createPaste(content string, expiryDays int, title string) error {
 		// Fall back to reading body
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return output.PrintError("parse_failed", err.Error(), nil)
+			return output.PrintErr(ctx, "parse_failed", err, nil)
 		}
 		pasteURL = strings.TrimSpace(string(body))
 	}
@@ -149,11 +163,11 @@ func createPaste(content string, expiryDays int, title string) error {
 		Title:     title,
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
 
-func getPaste(pasteURL string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func getPaste(ctx context.Context, pasteURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	// Ensure we fetch raw content by appending .txt
@@ -164,30 +178,30 @@ func getPaste(pasteURL string) error {
 
 	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, http.NoBody)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
 
 	// Use a separate client that follows redirects for GET
-	getClient := &http.Client{Timeout: 30 * time.Second}
+	getClient := transport.New(30 * time.Second)
 	resp, err := getClient.Do(req)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 404 {
-		return output.PrintError("not_found", "Paste not found: "+pasteURL, nil)
+		return output.PrintError(ctx, "not_found", "Paste not found: "+pasteURL, nil)
 	}
 
 	if resp.StatusCode >= 400 {
-		return output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return output.PrintError("read_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "read_failed", err, nil)
 	}
 
 	result := Content{
@@ -195,5 +209,5 @@ func getPaste(pasteURL string) error {
 		Content: string(body),
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
@@ -2,6 +2,7 @@ package paste
 
 import (
 	"bytes"
+	"context"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -32,6 +33,7 @@ func TestNewCmd(t *testing.T) {
 }
 
 func TestCreatePaste(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != "POST" {
 			t.Errorf("expected POST, got %s", r.Method)
@@ -45,13 +47,14 @@ func TestCreatePaste(t *testing.T) {
 	apiURL = srv.URL
 	defer func() { apiURL = oldURL }()
 
-	err := createPaste("test content", 7, "Test Title")
+	err := createPaste(ctx, "test content", 7, "Test Title")
 	if err != nil {
 		t.Errorf("createPaste failed: %v", err)
 	}
 }
 
 func TestCreatePasteBodyResponse(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("https://dpaste.com/XYZ789"))
 	}))
@@ -61,13 +64,14 @@ func TestCreatePasteBodyResponse(t *testing.T) {
 	apiURL = srv.URL
 	defer func() { apiURL = oldURL }()
 
-	err := createPaste("test content", 3, "")
+	err := createPaste(ctx, "test content", 3, "")
 	if err != nil {
 		t.Errorf("createPaste with body response failed: %v", err)
 	}
 }
 
 func TestCreatePasteHTTPError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusBadRequest)
 		w.Write([]byte("Invalid content"))
@@ -78,13 +82,14 @@ func TestCreatePasteHTTPError(t *testing.T) {
 	apiURL = srv.URL
 	defer func() { apiURL = oldURL }()
 
-	err := createPaste("", 7, "")
+	err := createPaste(ctx, "", 7, "")
 	if err == nil {
 		t.Error("expected HTTP error, got nil")
 	}
 }
 
 func TestGetPaste(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if !strings.HasSuffix(r.URL.Path, ".txt") {
 			t.Errorf("expected .txt suffix in URL, got %s", r.URL.Path)
@@ -93,43 +98,46 @@ func TestGetPaste(t *testing.T) {
 	}))
 	defer srv.Close()
 
-	err := getPaste(srv.URL + "/ABC123")
+	err := getPaste(ctx, srv.URL+"/ABC123")
 	if err != nil {
 		t.Errorf("getPaste failed: %v", err)
 	}
 }
 
 func TestGetPasteAlreadyTxt(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("Content"))
 	}))
 	defer srv.Close()
 
-	err := getPaste(srv.URL + "/ABC123.txt")
+	err := getPaste(ctx, srv.URL+"/ABC123.txt")
 	if err != nil {
 		t.Errorf("getPaste with .txt URL failed: %v", err)
 	}
 }
 
 func TestGetPasteNotFound(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 	}))
 	defer srv.Close()
 
-	err := getPaste(srv.URL + "/INVALID")
+	err := getPaste(ctx, srv.URL+"/INVALID")
 	if err == nil {
 		t.Error("expected not found error, got nil")
 	}
 }
 
 func TestGetPasteHTTPError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
 	defer srv.Close()
 
-	err := getPaste(srv.URL + "/ABC123")
+	err := getPaste(ctx, srv.URL+"/ABC123")
 	if err == nil {
 		t.Error("expected HTTP error, got nil")
 	}
@@ -9,10 +9,12 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
-var httpClient = &http.Client{Timeout: 60 * time.Second}
+var httpClient = transport.New(60 * time.Second)
 
 const (
 	cfDownloadURL = "https://speed.cloudflare.com/__down?bytes=%d"
@@ -45,6 +47,16 @@ type LatencyResult struct {
 	MaxMs float64 `json:"max_ms"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "speedtest",
+		Name:        "Speed Test",
+		Group:       "utility",
+		Description: "Download and upload speed and latency via Cloudflare",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "speedtest",
@@ -65,7 +77,8 @@ func newRunCmd() *cobra.Command {
 		Use:   "run",
 		Short: "Run full speed test (download + upload + latency)",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runFullTest()
+			ctx := cmd.Context()
+			return runFullTest(ctx)
 		},
 	}
 }
@@ -75,7 +88,8 @@ func newDownloadCmd() *cobra.Command {
 		Use:   "download",
 		Short: "Test download speed only",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDownloadTest()
+			ctx := cmd.Context()
+			return runDownloadTest(ctx)
 		},
 	}
 }
@@ -85,7 +99,8 @@ func newUploadCmd() *cobra.Command {
 		Use:   "upload",
 		Short: "Test upload speed only",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runUploadTest()
+			ctx := cmd.Context()
+			return runUploadTest(ctx)
 		},
 	}
 }
@@ -95,27 +110,28 @@ func newLatencyCmd() *cobra.Command {
 		Use:   "latency",
 		Short: "Test latency only",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runLatencyTest()
+			ctx := cmd.Context()
+			return runLatencyTest(ctx)
 		},
 	}
 }
 
-func runFullTest() error {
+func runFullTest(ctx context.Context) error {
 	start := time.Now()
 
-	latency, err := measureLatency()
+	latency, err := measureLatency(ctx)
 	if err != nil {
-		return output.PrintError("latency_error", err.Error(), nil)
+		return output.PrintErr(ctx, "latency_error", err, nil)
 	}
 
-	dl, err := measureDownload()
+	dl, err := measureDownload(ctx)
 	if err != nil {
-		return output.PrintError("download_error", err.Error(), nil)
+		return output.PrintErr(ctx, "download_error", err, nil)
 	}
 
-	ul, err := measureUpload()
+	ul, err := measureUpload(ctx)
 	if err != nil {
-		return output.PrintError("upload_error", err.Error(), nil)
+		return output.PrintErr(ctx, "upload_error", err, nil)
 	}
 
 	result := SpeedResult{
@@ -127,16 +143,16 @@ func runFullTest() error {
 		DurationSec: time.Since(start).Seconds(),
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
 
-func runDownloadTest() error {
+func runDownloadTest(ctx context.Context) error {
 	start := time.Now()
-	dl, err := measureDownload()
+	dl, err := measureDownload(ctx)
 	if err != nil {
-		return output.PrintError("download_error", err.Error(), nil)
+		return output.PrintErr(ctx, "download_error", err, nil)
 	}
-	return output.Print(SpeedResult{
+	return output.Print(ctx, SpeedResult{
 		Download:    dl,
 		TestedAt:    time.Now().Format(time.RFC3339),
 		TestType:    "download",
@@ -144,13 +160,13 @@ func runDownloadTest() error {
 	})
 }
 
-func runUploadTest() error {
+func runUploadTest(ctx context.Context) error {
 	start := time.Now()
-	ul, err := measureUpload()
+	ul, err := measureUpload(ctx)
 	if err != nil {
-		return output.PrintError("upload_error", err.Error(), nil)
+		return output.PrintErr(ctx, "upload_error", err, nil)
 	}
-	return output.Print(SpeedResult{
+	return output.Print(ctx, SpeedResult{
 		Upload:      ul,
 		TestedAt:    time.Now().Format(time.RFC3339),
 		TestType:    "upload",
@@ -158,13 +174,13 @@ func runUploadTest() error {
 	})
 }
 
-func runLatencyTest() error {
+func runLatencyTest(ctx context.Context) error {
 	start := time.Now()
-	lat, err := measureLatency()
+	lat, err := measureLatency(ctx)
 	if err != nil {
-		return output.PrintError("latency_error", err.Error(), nil)
+		return output.PrintErr(ctx, "latency_error", err, nil)
 	}
-	return output.Print(SpeedResult{
+	return output.Print(ctx, SpeedResult{
 		Latency:     lat,
 		TestedAt:    time.Now().Format(time.RFC3339),
 		TestType:    "latency",
@@ -172,12 +188,12 @@ func runLatencyTest() error {
 	})
 }
 
-func measureLatency() (*LatencyResult, error) {
+func measureLatency(ctx context.Context) (*LatencyResult, error) {
 	var times []float64
 	// Small download to measure latency (1 byte)
 	for i := 0; i < 5; i++ {
 		start := time.Now()
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 		req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf(cfDownloadURL, 1), http.NoBody)
 		if err != nil {
 			cancel()
@@ -215,11 +231,11 @@ func measureLatency() (*LatencyResult, error) {
 	}, nil
 }
 
-func measureDownload() (*BandwidthResult, error) {
+func measureDownload(ctx context.Context) (*BandwidthResult, error) {
 	// Test with 25MB download
 	testBytes := int64(25 * 1024 * 1024)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf(cfDownloadURL, testBytes), http.NoBody)
@@ -249,11 +265,11 @@ func measureDownload() (*BandwidthResult, error) {
 	}, nil
 }
 
-func measureUpload() (*BandwidthResult, error) {
+func measureUpload(ctx context.Context) (*BandwidthResult, error) {
 	// Test with 10MB upload
 	testBytes := int64(10 * 1024 * 1024)
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	reader := io.LimitReader(&zeroReader{}, testBytes)
@@ -12,12 +12,14 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/unstablemind/pocket/internal/common/config"
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var baseURL = "https://www.alphavantage.co/query"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Quote is LLM-friendly stock quote output
 type Quote struct {
@@ -60,6 +62,22 @@ type CompanyInfo struct {
 	Website       string  `json:"website,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "stocks",
+		Name:        "Stock Market",
+		Group:       "utility",
+		Description: "Stock quotes, search, and company info via Alpha Vantage",
+		Auth:        registry.AuthKey,
+		Keys: []registry.Key{
+			{Key: "alphavantage_key", Description: "API key for stock market data", Required: true},
+		},
+		SetupGuide:  "1. Go to https://www.alphavantage.co/support/#api-key\n2. Enter your email to get a free API key\n3. Copy the API key from email\n4. Run: pocket config set alphavantage_key <your-api-key>\n\nNote: Free tier allows 25 requests/day, 5/min",
+		TestCommand: "pocket utility stocks quote AAPL",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "stocks",
@@ -80,7 +98,8 @@ func newQuoteCmd() *cobra.Command {
 		Short: "Get current stock quote (price, change, volume)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			apiKey, err := getAPIKey()
+			ctx := cmd.Context()
+			apiKey, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -89,7 +108,7 @@ func newQuoteCmd() *cobra.Command {
 			reqURL := fmt.Sprintf("%s?function=GLOBAL_QUOTE&symbol=%s&apikey=%s",
 				baseURL, url.QueryEscape(symbol), apiKey)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -112,16 +131,16 @@ func newQuoteCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			// Check for rate limit note
 			if data.Note != "" {
-				return output.PrintError("rate_limited", "Alpha Vantage API rate limit reached. Free tier allows 25 requests/day.", nil)
+				return output.PrintError(ctx, "rate_limited", "Alpha Vantage API rate limit reached. Free tier allows 25 requests/day.", nil)
 			}
 
 			if data.GlobalQuote.Symbol == "" {
-				return output.PrintError("not_found", "Stock symbol not found: "+symbol, nil)
+				return output.PrintError(ctx, "not_found", "Stock symbol not found: "+symbol, nil)
 			}
 
 			quote := Quote{
@@ -137,7 +156,7 @@ func newQuoteCmd() *cobra.Command {
 				LatestDay:     data.GlobalQuote.LatestDay,
 			}
 
-			return output.Print(quote)
+			return output.Print(ctx, quote)
 		},
 	}
 
@@ -152,7 +171,8 @@ func newSearchCmd() *cobra.Command {
 		Short: "Search for stock symbols",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			apiKey, err := getAPIKey()
+			ctx := cmd.Context()
+			apiKey, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -161,7 +181,7 @@ func newSearchCmd() *cobra.Command {
 			reqURL := fmt.Sprintf("%s?function=SYMBOL_SEARCH&keywords=%s&apikey=%s",
 				baseURL, url.QueryEscape(query), apiKey)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -179,16 +199,16 @@ func newSearchCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			// Check for rate limit note
 			if data.Note != "" {
-				return output.PrintError("rate_limited", "Alpha Vantage API rate limit reached. Free tier allows 25 requests/day.", nil)
+				return output.PrintError(ctx, "rate_limited", "Alpha Vantage API rate limit reached. Free tier allows 25 requests/day.", nil)
 			}
 
 			if len(data.BestMatches) == 0 {
-				return output.PrintError("not_found", "No symbols found for: "+query, nil)
+				return output.PrintError(ctx, "not_found", "No symbols found for: "+query, nil)
 			}
 
 			// Limit results
@@ -208,7 +228,7 @@ func newSearchCmd() *cobra.Command {
 				})
 			}
 
-			return output.Print(results)
+			return output.Print(ctx, results)
 		},
 	}
 
@@ -223,7 +243,8 @@ func newInfoCmd() *cobra.Command {
 		Short: "Get company overview/info",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			apiKey, err := getAPIKey()
+			ctx := cmd.Context()
+			apiKey, err := getAPIKey(ctx)
 			if err != nil {
 				return err
 			}
@@ -232,7 +253,7 @@ func newInfoCmd() *cobra.Command {
 			reqURL := fmt.Sprintf("%s?function=OVERVIEW&symbol=%s&apikey=%s",
 				baseURL, url.QueryEscape(symbol), apiKey)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -258,21 +279,21 @@ func newInfoCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			// Check for rate limit note
 			if data.Note != "" {
-				return output.PrintError("rate_limited", "Alpha Vantage API rate limit reached. Free tier allows 25 requests/day.", nil)
+				return output.PrintError(ctx, "rate_limited", "Alpha Vantage API rate limit reached. Free tier allows 25 requests/day.", nil)
 			}
 
 			// Check for information notice (often indicates invalid API key or symbol)
 			if data.InformationNotice != "" {
-				return output.PrintError("api_error", data.InformationNotice, nil)
+				return output.PrintError(ctx, "api_error", data.InformationNotice, nil)
 			}
 
 			if data.Symbol == "" {
-				return output.PrintError("not_found", "Company not found: "+symbol, nil)
+				return output.PrintError(ctx, "not_found", "Company not found: "+symbol, nil)
 			}
 
 			// Truncate description for LLM friendliness
@@ -298,39 +319,39 @@ func newInfoCmd() *cobra.Command {
 				Website:       data.OfficialSite,
 			}
 
-			return output.Print(info)
+			return output.Print(ctx, info)
 		},
 	}
 
 	return cmd
 }
 
-func getAPIKey() (string, error) {
-	apiKey, err := config.Get("alphavantage_key")
+func getAPIKey(ctx context.Context) (string, error) {
+	apiKey, err := config.Get(ctx, "alphavantage_key")
 	if err != nil {
-		return "", output.PrintError("config_error", err.Error(), nil)
+		return "", output.PrintErr(ctx, "config_error", err, nil)
 	}
 
 	if apiKey == "" {
-		return "", output.PrintError("missing_api_key",
+		return "", output.PrintError(ctx, "missing_api_key",
 			"Alpha Vantage API key not configured",
 			map[string]string{
 				"setup":     "Get a free API key at https://www.alphavantage.co/support/#api-key",
 				"configure": "pocket config set alphavantage_key YOUR_API_KEY",
-				"guide":     "pocket setup show alphavantage",
+				"guide":     "pocket setup show stocks",
 			})
 	}
 
 	return apiKey, nil
 }
 
-func doRequest(reqURL string) (*http.Response, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, reqURL string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -338,12 +359,12 @@ func doRequest(reqURL string) (*http.Response, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	if resp.StatusCode >= 400 {
 		resp.Body.Close()
-		return nil, output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	return resp, nil
@@ -1,6 +1,7 @@
 package stocks
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -34,6 +35,7 @@ func TestNewCmd(t *testing.T) {
 
 func setupTestConfig(t *testing.T) func() {
 	t.Helper()
+	ctx := context.Background()
 
 	tmpfile, err := os.CreateTemp("", "stocks_test_*.json")
 	if err != nil {
@@ -50,7 +52,7 @@ func setupTestConfig(t *testing.T) func() {
 	os.Setenv("POCKET_CONFIG", tmpfile.Name())
 
 	// Set test API key
-	if err := config.Set("alphavantage_key", "test_api_key"); err != nil {
+	if err := config.Set(ctx, "alphavantage_key", "test_api_key"); err != nil {
 		t.Fatal(err)
 	}
 
@@ -12,11 +12,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var (
-	httpClient = &http.Client{Timeout: 30 * time.Second}
+	httpClient = transport.New(30 * time.Second)
 	// baseURL is used only for IP-based timezone lookups via timeapi.io
 	baseURL = "https://timeapi.io/api"
 )
@@ -33,6 +35,16 @@ type TimeInfo struct {
 	UnixTime     int64  `json:"unixtime"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "timezone",
+		Name:        "Timezone",
+		Group:       "utility",
+		Description: "Get time in timezones, lookup timezone by IP, list all timezones",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the timezone command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -54,8 +66,9 @@ func newGetCmd() *cobra.Command {
 		Short: "Get time for a timezone (e.g., America/New_York)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			tz := args[0]
-			return getTimezoneLocal(tz)
+			return getTimezoneLocal(ctx, tz)
 		},
 	}
 
@@ -68,8 +81,9 @@ func newIPCmd() *cobra.Command {
 		Short: "Get timezone by IP address",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			ip := args[0]
-			return fetchTimezoneByIP(ip)
+			return fetchTimezoneByIP(ctx, ip)
 		},
 	}
 
@@ -81,7 +95,8 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List all available timezones",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return listTimezones()
+			ctx := cmd.Context()
+			return listTimezones(ctx)
 		},
 	}
 
@@ -90,10 +105,10 @@ func newListCmd() *cobra.Command {
 
 // getTimezoneLocal uses Go's built-in time package to get timezone info
 // without requiring any external API.
-func getTimezoneLocal(tz string) error {
+func getTimezoneLocal(ctx context.Context, tz string) error {
 	loc, err := time.LoadLocation(tz)
 	if err != nil {
-		return output.PrintError("not_found", fmt.Sprintf("Timezone not found: %s", tz), nil)
+		return output.PrintError(ctx, "not_found", fmt.Sprintf("Timezone not found: %s", tz), nil)
 	}
 
 	now := time.Now().In(loc)
@@ -125,19 +140,19 @@ func getTimezoneLocal(tz string) error {
 		UnixTime:     now.Unix(),
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
 
 // fetchTimezoneByIP uses timeapi.io to look up timezone by IP address.
-func fetchTimezoneByIP(ip string) error {
+func fetchTimezoneByIP(ctx context.Context, ip string) error {
 	reqURL := fmt.Sprintf("%s/time/current/ip?ipAddress=%s", baseURL, url.QueryEscape(ip))
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -145,16 +160,16 @@ func fetchTimezoneByIP(ip string) error {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == 404 {
-		return output.PrintError("not_found", "Timezone not found for IP", nil)
+		return output.PrintError(ctx, "not_found", "Timezone not found for IP", nil)
 	}
 
 	if resp.StatusCode >= 400 {
-		return output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	// timeapi.io response structure
@@ -175,7 +190,7 @@ func fetchTimezoneByIP(ip string) error {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return output.PrintError("parse_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "parse_failed", err, nil)
 	}
 
 	// Calculate UTC offset string from seconds
@@ -227,7 +242,7 @@ func fetchTimezoneByIP(ip string) error {
 		UnixTime:     time.Now().Unix(),
 	}
 
-	return output.Print(result)
+	return output.Print(ctx, result)
 }
 
 // knownTimezones is the list of IANA timezone names from Go's time package.
@@ -347,7 +362,7 @@ var knownTimezones = []string{
 	"UTC",
 }
 
-func listTimezones() error {
+func listTimezones(ctx context.Context) error {
 	// Group by region for LLM-friendly output
 	regions := make(map[string][]string)
 	for _, tz := range knownTimezones {
@@ -366,7 +381,7 @@ func listTimezones() error {
 		Regions map[string][]string `json:"regions"`
 	}
 
-	return output.Print(TimezoneList{
+	return output.Print(ctx, TimezoneList{
 		Total:   len(knownTimezones),
 		Regions: regions,
 	})
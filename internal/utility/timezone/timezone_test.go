@@ -1,6 +1,7 @@
 package timezone
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -92,6 +93,7 @@ func TestListCmd(t *testing.T) {
 }
 
 func TestFetchTimezoneByIPHTTPError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
@@ -101,13 +103,14 @@ func TestFetchTimezoneByIPHTTPError(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	err := fetchTimezoneByIP("8.8.8.8")
+	err := fetchTimezoneByIP(ctx, "8.8.8.8")
 	if err == nil {
 		t.Error("expected HTTP error, got nil")
 	}
 }
 
 func TestFetchTimezoneByIPNotFound(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
 	}))
@@ -117,13 +120,14 @@ func TestFetchTimezoneByIPNotFound(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	err := fetchTimezoneByIP("0.0.0.0")
+	err := fetchTimezoneByIP(ctx, "0.0.0.0")
 	if err == nil {
 		t.Error("expected not found error, got nil")
 	}
 }
 
 func TestFetchTimezoneByIPParseError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("invalid json"))
 	}))
@@ -133,29 +137,32 @@ func TestFetchTimezoneByIPParseError(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	err := fetchTimezoneByIP("8.8.8.8")
+	err := fetchTimezoneByIP(ctx, "8.8.8.8")
 	if err == nil {
 		t.Error("expected parse error, got nil")
 	}
 }
 
 func TestGetTimezoneLocalUTC(t *testing.T) {
+	ctx := context.Background()
 	// UTC should always work
-	err := getTimezoneLocal("UTC")
+	err := getTimezoneLocal(ctx, "UTC")
 	if err != nil {
 		t.Errorf("getTimezoneLocal(UTC) failed: %v", err)
 	}
 }
 
 func TestGetTimezoneLocalInvalid(t *testing.T) {
-	err := getTimezoneLocal("Not/A/Real/Zone")
+	ctx := context.Background()
+	err := getTimezoneLocal(ctx, "Not/A/Real/Zone")
 	if err == nil {
 		t.Error("expected error for invalid timezone, got nil")
 	}
 }
 
 func TestListTimezonesOutput(t *testing.T) {
-	err := listTimezones()
+	ctx := context.Background()
+	err := listTimezones(ctx)
 	if err != nil {
 		t.Errorf("listTimezones failed: %v", err)
 	}
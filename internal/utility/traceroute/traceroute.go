@@ -2,6 +2,7 @@ package traceroute
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os/exec"
 	"regexp"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -31,6 +33,16 @@ type Hop struct {
 	Timeout bool     `json:"timeout,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "traceroute",
+		Name:        "Traceroute",
+		Group:       "utility",
+		Description: "Trace the network path to a host",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "traceroute",
@@ -51,7 +63,8 @@ func newRunCmd() *cobra.Command {
 		Short: "Trace the network path to a host",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runTraceroute(args[0], maxHops)
+			ctx := cmd.Context()
+			return runTraceroute(ctx, args[0], maxHops)
 		},
 	}
 
@@ -60,7 +73,7 @@ func newRunCmd() *cobra.Command {
 	return cmd
 }
 
-func runTraceroute(host string, maxHops int) error {
+func runTraceroute(ctx context.Context, host string, maxHops int) error {
 	if maxHops < 1 || maxHops > 255 {
 		maxHops = 30
 	}
@@ -76,7 +89,7 @@ func runTraceroute(host string, maxHops int) error {
 		cmdName = "tracert"
 		cmdArgs = []string{"-h", strconv.Itoa(maxHops), "-d", host}
 	default:
-		return output.PrintError("platform_unsupported",
+		return output.PrintError(ctx, "platform_unsupported",
 			fmt.Sprintf("traceroute not supported on %s", runtime.GOOS), nil)
 	}
 
@@ -85,7 +98,7 @@ func runTraceroute(host string, maxHops int) error {
 	if err != nil {
 		// traceroute may return non-zero even on partial success
 		if len(outBytes) == 0 {
-			return output.PrintError("traceroute_error",
+			return output.PrintError(ctx, "traceroute_error",
 				fmt.Sprintf("traceroute failed: %v", err),
 				map[string]string{"suggestion": "Ensure traceroute is installed"})
 		}
@@ -99,7 +112,7 @@ func runTraceroute(host string, maxHops int) error {
 		complete = !lastHop.Timeout && lastHop.IP != ""
 	}
 
-	return output.Print(TraceResult{
+	return output.Print(ctx, TraceResult{
 		Host:     host,
 		Hops:     hops,
 		HopCount: len(hops),
@@ -11,6 +11,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -19,7 +21,7 @@ import (
 
 var baseURL = "https://api.mymemory.translated.net"
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Translation is LLM-friendly translation output
 type Translation struct {
@@ -36,6 +38,16 @@ type Language struct {
 	Name string `json:"name"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "translate",
+		Name:        "Translation",
+		Group:       "utility",
+		Description: "Translate text between languages",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "translate",
@@ -57,6 +69,7 @@ func newTextCmd() *cobra.Command {
 		Short: "Translate text between languages",
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			text := strings.Join(args, " ")
 
 			// Build the langpair as "from|to". The pipe must NOT be percent-encoded
@@ -68,7 +81,7 @@ func newTextCmd() *cobra.Command {
 				url.QueryEscape(text),
 				langpair)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -89,7 +102,7 @@ func newTextCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			if data.ResponseStatus != 200 {
@@ -97,7 +110,7 @@ func newTextCmd() *cobra.Command {
 				if data.ResponseDetails != "" {
 					msg = data.ResponseDetails
 				}
-				return output.PrintError("api_error", msg, nil)
+				return output.PrintError(ctx, "api_error", msg, nil)
 			}
 
 			// Select the best translation from matches. The responseData.translatedText
@@ -119,7 +132,7 @@ func newTextCmd() *cobra.Command {
 				Match:          matchScore,
 			}
 
-			return output.Print(translation)
+			return output.Print(ctx, translation)
 		},
 	}
 
@@ -134,6 +147,7 @@ func newLanguagesCmd() *cobra.Command {
 		Use:   "languages",
 		Short: "List common supported languages",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			// MyMemory supports many languages, here are the most common ones
 			languages := []Language{
 				{Code: "en", Name: "English"},
@@ -168,7 +182,7 @@ func newLanguagesCmd() *cobra.Command {
 				{Code: "bn", Name: "Bengali"},
 			}
 
-			return output.Print(languages)
+			return output.Print(ctx, languages)
 		},
 	}
 
@@ -220,13 +234,13 @@ func bestTranslation(matches []struct {
 	return bestTrans, bestScore
 }
 
-func doRequest(reqURL string) (*http.Response, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, reqURL string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -234,17 +248,17 @@ func doRequest(reqURL string) (*http.Response, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	if resp.StatusCode == 429 {
 		resp.Body.Close()
-		return nil, output.PrintError("rate_limited", "Rate limit exceeded, try again later", nil)
+		return nil, output.PrintError(ctx, "rate_limited", "Rate limit exceeded, try again later", nil)
 	}
 
 	if resp.StatusCode >= 400 {
 		resp.Body.Close()
-		return nil, output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	return resp, nil
@@ -11,18 +11,20 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var isgdBaseURL = "https://is.gd/create.php"
 
-var httpClient = &http.Client{
+var httpClient = transport.Wrap(&http.Client{
 	CheckRedirect: func(req *http.Request, via []*http.Request) error {
 		// Don't follow redirects automatically for expand command
 		return http.ErrUseLastResponse
 	},
 	Timeout: 30 * time.Second,
-}
+})
 
 // ShortenResult is LLM-friendly shortened URL output
 type ShortenResult struct {
@@ -39,6 +41,16 @@ type ExpandResult struct {
 	FinalStatus int      `json:"final_status"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "urlshort",
+		Name:        "URL Shortener",
+		Group:       "utility",
+		Description: "Shorten and expand URLs",
+		New:         NewCmd,
+	})
+}
+
 // NewCmd returns the url shortener command
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
@@ -59,6 +71,7 @@ func newShortenCmd() *cobra.Command {
 		Short: "Shorten a URL using is.gd",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			originalURL := args[0]
 
 			// Ensure URL has a scheme
@@ -68,12 +81,12 @@ func newShortenCmd() *cobra.Command {
 
 			// Validate URL
 			if _, err := url.ParseRequestURI(originalURL); err != nil {
-				return output.PrintError("invalid_url", "Invalid URL format: "+err.Error(), nil)
+				return output.PrintError(ctx, "invalid_url", "Invalid URL format: "+err.Error(), nil)
 			}
 
 			reqURL := fmt.Sprintf("%s?format=json&url=%s", isgdBaseURL, url.QueryEscape(originalURL))
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -86,15 +99,15 @@ func newShortenCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			if data.ErrorCode != 0 {
-				return output.PrintError("shorten_failed", data.ErrorMsg, map[string]int{"error_code": data.ErrorCode})
+				return output.PrintError(ctx, "shorten_failed", data.ErrorMsg, map[string]int{"error_code": data.ErrorCode})
 			}
 
 			if data.ShortURL == "" {
-				return output.PrintError("shorten_failed", "No short URL returned", nil)
+				return output.PrintError(ctx, "shorten_failed", "No short URL returned", nil)
 			}
 
 			result := ShortenResult{
@@ -103,7 +116,7 @@ func newShortenCmd() *cobra.Command {
 				Service:     "is.gd",
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -118,6 +131,7 @@ func newExpandCmd() *cobra.Command {
 		Short: "Expand a shortened URL (follow redirects to get original)",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			shortURL := args[0]
 
 			// Ensure URL has a scheme
@@ -127,27 +141,27 @@ func newExpandCmd() *cobra.Command {
 
 			// Validate URL
 			if _, err := url.ParseRequestURI(shortURL); err != nil {
-				return output.PrintError("invalid_url", "Invalid URL format: "+err.Error(), nil)
+				return output.PrintError(ctx, "invalid_url", "Invalid URL format: "+err.Error(), nil)
 			}
 
 			var hops []string
 			currentURL := shortURL
 			finalStatus := 200
 
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 			defer cancel()
 
 			// Follow redirects manually to track each hop
 			for i := 0; i < maxHops; i++ {
 				req, err := http.NewRequestWithContext(ctx, "HEAD", currentURL, http.NoBody)
 				if err != nil {
-					return output.PrintError("fetch_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "fetch_failed", err, nil)
 				}
 				req.Header.Set("User-Agent", "Pocket-CLI/1.0")
 
 				resp, err := httpClient.Do(req)
 				if err != nil {
-					return output.PrintError("fetch_failed", err.Error(), nil)
+					return output.PrintErr(ctx, "fetch_failed", err, nil)
 				}
 				resp.Body.Close()
 
@@ -185,7 +199,7 @@ func newExpandCmd() *cobra.Command {
 				result.Hops = hops
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -194,16 +208,16 @@ func newExpandCmd() *cobra.Command {
 	return cmd
 }
 
-func doRequest(reqURL string) (*http.Response, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, reqURL string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	// Create a separate client for API requests that follows redirects
-	apiClient := &http.Client{}
+	apiClient := transport.New(0)
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -211,17 +225,17 @@ func doRequest(reqURL string) (*http.Response, error) {
 
 	resp, err := apiClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	if resp.StatusCode == 429 {
 		resp.Body.Close()
-		return nil, output.PrintError("rate_limited", "is.gd rate limit exceeded, try again later", nil)
+		return nil, output.PrintError(ctx, "rate_limited", "is.gd rate limit exceeded, try again later", nil)
 	}
 
 	if resp.StatusCode >= 400 {
 		resp.Body.Close()
-		return nil, output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	return resp, nil
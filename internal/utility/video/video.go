@@ -2,6 +2,7 @@ package video
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -11,6 +12,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/dryrun"
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -34,6 +37,16 @@ type ytdlpOutput struct {
 	Ext      string  `json:"ext"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "video",
+		Name:        "Video Downloader",
+		Group:       "utility",
+		Description: "Download videos and audio with yt-dlp",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "video",
@@ -55,7 +68,8 @@ func newDownloadCmd() *cobra.Command {
 		Short: "Download video from URL via yt-dlp",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return runDownload(args[0], format, cookies)
+			ctx := cmd.Context()
+			return runDownload(ctx, args[0], format, cookies)
 		},
 	}
 
@@ -65,7 +79,7 @@ func newDownloadCmd() *cobra.Command {
 	return cmd
 }
 
-func ensureYtdlp() error {
+func ensureYtdlp(ctx context.Context) error {
 	if _, err := exec.LookPath("yt-dlp"); err == nil {
 		return nil
 	}
@@ -73,7 +87,7 @@ func ensureYtdlp() error {
 	// Attempt auto-install
 	installer, name := findInstaller()
 	if installer == nil {
-		return output.PrintError("not_installed", "yt-dlp is not installed and no supported package manager found", map[string]any{
+		return output.PrintError(ctx, "not_installed", "yt-dlp is not installed and no supported package manager found", map[string]any{
 			"hint": "Install manually from https://github.com/yt-dlp/yt-dlp",
 		})
 	}
@@ -83,14 +97,14 @@ func ensureYtdlp() error {
 	installCmd := exec.Command(installer[0], installer[1:]...)
 	installCmd.Stderr = os.Stderr
 	if err := installCmd.Run(); err != nil {
-		return output.PrintError("install_failed",
+		return output.PrintError(ctx, "install_failed",
 			fmt.Sprintf("auto-install via %s failed: %v", name, err),
 			map[string]any{"hint": "Install manually: " + installHint()})
 	}
 
 	// Verify it's now available
 	if _, err := exec.LookPath("yt-dlp"); err != nil {
-		return output.PrintError("install_failed",
+		return output.PrintError(ctx, "install_failed",
 			"yt-dlp installed but not found in PATH",
 			map[string]any{"hint": "You may need to restart your shell"})
 	}
@@ -133,14 +147,10 @@ func installHint() string {
 	}
 }
 
-func runDownload(url, format, cookies string) error {
-	if err := ensureYtdlp(); err != nil {
-		return err
-	}
-
+func runDownload(ctx context.Context, url, format, cookies string) error {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return output.PrintError("download_failed", fmt.Sprintf("cannot determine home directory: %v", err), nil)
+		return output.PrintError(ctx, "download_failed", fmt.Sprintf("cannot determine home directory: %v", err), nil)
 	}
 	downloadDir := filepath.Join(homeDir, "Downloads")
 
@@ -157,6 +167,13 @@ func runDownload(url, format, cookies string) error {
 	}
 	cmdArgs = append(cmdArgs, url)
 
+	if dryrun.Enabled(ctx) {
+		return dryrun.Exec(ctx, append([]string{"yt-dlp"}, cmdArgs...)...)
+	}
+	if err := ensureYtdlp(ctx); err != nil {
+		return err
+	}
+
 	cmd := exec.Command("yt-dlp", cmdArgs...)
 
 	var stdout, stderr bytes.Buffer
@@ -164,14 +181,14 @@ func runDownload(url, format, cookies string) error {
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return output.PrintError("download_failed",
+		return output.PrintError(ctx, "download_failed",
 			fmt.Sprintf("yt-dlp failed: %v", err),
 			map[string]any{"stderr": stderr.String()})
 	}
 
 	var meta ytdlpOutput
 	if err := json.Unmarshal(stdout.Bytes(), &meta); err != nil {
-		return output.PrintError("download_failed",
+		return output.PrintError(ctx, "download_failed",
 			fmt.Sprintf("failed to parse yt-dlp output: %v", err), nil)
 	}
 
@@ -183,7 +200,7 @@ func runDownload(url, format, cookies string) error {
 		fullPath = filepath.Join(downloadDir, fullPath)
 	}
 
-	return output.Print(DownloadResult{
+	return output.Print(ctx, DownloadResult{
 		Title:    meta.Title,
 		Filename: filepath.Base(fullPath),
 		URL:      meta.URL,
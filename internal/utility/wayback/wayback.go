@@ -10,6 +10,8 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -18,7 +20,7 @@ var (
 	cdxAPI          = "http://web.archive.org/cdx/search/cdx"
 )
 
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+var httpClient = transport.New(30 * time.Second)
 
 // Snapshot is an LLM-friendly archived snapshot
 type Snapshot struct {
@@ -47,6 +49,16 @@ type AvailabilityResult struct {
 	CheckedAt string          `json:"checked_at"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "wayback",
+		Name:        "Wayback Machine",
+		Group:       "utility",
+		Description: "Check archived versions of websites via Internet Archive",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "wayback",
@@ -67,10 +79,11 @@ func newCheckCmd() *cobra.Command {
 		Short: "Check if URL has archived snapshots",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			targetURL := normalizeURL(args[0])
 			reqURL := fmt.Sprintf("%s?url=%s", availabilityAPI, url.QueryEscape(targetURL))
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -89,7 +102,7 @@ func newCheckCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			result := AvailabilityResult{
@@ -108,7 +121,7 @@ func newCheckCmd() *cobra.Command {
 				}
 			}
 
-			return output.Print(result)
+			return output.Print(ctx, result)
 		},
 	}
 
@@ -121,10 +134,11 @@ func newLatestCmd() *cobra.Command {
 		Short: "Get latest archived snapshot of URL",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			targetURL := normalizeURL(args[0])
 			reqURL := fmt.Sprintf("%s?url=%s", availabilityAPI, url.QueryEscape(targetURL))
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -143,11 +157,11 @@ func newLatestCmd() *cobra.Command {
 			}
 
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			if data.ArchivedSnapshots.Closest == nil || !data.ArchivedSnapshots.Closest.Available {
-				return output.PrintError("not_found", "No archived snapshot found for: "+targetURL, nil)
+				return output.PrintError(ctx, "not_found", "No archived snapshot found for: "+targetURL, nil)
 			}
 
 			snapshot := SnapshotDetail{
@@ -157,7 +171,7 @@ func newLatestCmd() *cobra.Command {
 				Status:      data.ArchivedSnapshots.Closest.Status,
 			}
 
-			return output.Print(snapshot)
+			return output.Print(ctx, snapshot)
 		},
 	}
 
@@ -172,11 +186,12 @@ func newSnapshotsCmd() *cobra.Command {
 		Short: "List available snapshots for URL",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			targetURL := normalizeURL(args[0])
 			reqURL := fmt.Sprintf("%s?url=%s&output=json&limit=%d&fl=timestamp,original,statuscode,mimetype,digest,length",
 				cdxAPI, url.QueryEscape(targetURL), limit)
 
-			resp, err := doRequest(reqURL)
+			resp, err := doRequest(ctx, reqURL)
 			if err != nil {
 				return err
 			}
@@ -184,11 +199,11 @@ func newSnapshotsCmd() *cobra.Command {
 
 			var data [][]string
 			if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-				return output.PrintError("parse_failed", err.Error(), nil)
+				return output.PrintErr(ctx, "parse_failed", err, nil)
 			}
 
 			if len(data) <= 1 {
-				return output.PrintError("not_found", "No snapshots found for: "+targetURL, nil)
+				return output.PrintError(ctx, "not_found", "No snapshots found for: "+targetURL, nil)
 			}
 
 			// First row is headers: [timestamp, original, statuscode, mimetype, digest, length]
@@ -213,10 +228,10 @@ func newSnapshotsCmd() *cobra.Command {
 			}
 
 			if len(snapshots) == 0 {
-				return output.PrintError("not_found", "No snapshots found for: "+targetURL, nil)
+				return output.PrintError(ctx, "not_found", "No snapshots found for: "+targetURL, nil)
 			}
 
-			return output.Print(snapshots)
+			return output.Print(ctx, snapshots)
 		},
 	}
 
@@ -225,13 +240,13 @@ func newSnapshotsCmd() *cobra.Command {
 	return cmd
 }
 
-func doRequest(reqURL string) (*http.Response, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func doRequest(ctx context.Context, reqURL string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "Pocket-CLI/1.0")
@@ -239,17 +254,17 @@ func doRequest(reqURL string) (*http.Response, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return nil, output.PrintError("fetch_failed", err.Error(), nil)
+		return nil, output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	if resp.StatusCode == 429 {
 		resp.Body.Close()
-		return nil, output.PrintError("rate_limited", "Wayback Machine rate limit exceeded, try again later", nil)
+		return nil, output.PrintError(ctx, "rate_limited", "Wayback Machine rate limit exceeded, try again later", nil)
 	}
 
 	if resp.StatusCode >= 400 {
 		resp.Body.Close()
-		return nil, output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return nil, output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	return resp, nil
@@ -10,11 +10,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
+	"github.com/unstablemind/pocket/internal/common/transport"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
 var (
-	httpClient = &http.Client{Timeout: 30 * time.Second}
+	httpClient = transport.New(30 * time.Second)
 	baseURL    = "https://wttr.in"
 )
 
@@ -52,6 +54,16 @@ type Weather struct {
 	Forecast []Forecast `json:"forecast,omitempty"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "weather",
+		Name:        "Weather",
+		Group:       "utility",
+		Description: "Current weather and forecasts for any location",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "weather",
@@ -71,8 +83,9 @@ func newNowCmd() *cobra.Command {
 		Short: "Get current weather",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			location := args[0]
-			return fetchWeather(location, 0)
+			return fetchWeather(ctx, location, 0)
 		},
 	}
 
@@ -87,8 +100,9 @@ func newForecastCmd() *cobra.Command {
 		Short: "Get weather forecast",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
 			location := args[0]
-			return fetchWeather(location, days)
+			return fetchWeather(ctx, location, days)
 		},
 	}
 
@@ -98,8 +112,8 @@ func newForecastCmd() *cobra.Command {
 }
 
 //nolint:gocyclo // complex but clear sequential logic
-func fetchWeather(location string, forecastDays int) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+func fetchWeather(ctx context.Context, location string, forecastDays int) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	// Using wttr.in JSON API
@@ -107,19 +121,19 @@ func fetchWeather(location string, forecastDays int) error {
 
 	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, http.NoBody)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 
 	req.Header.Set("User-Agent", "curl/7.68.0")
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return output.PrintError("fetch_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "fetch_failed", err, nil)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return output.PrintError("fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
+		return output.PrintError(ctx, "fetch_failed", fmt.Sprintf("HTTP %d", resp.StatusCode), nil)
 	}
 
 	var data struct {
@@ -163,11 +177,11 @@ func fetchWeather(location string, forecastDays int) error {
 	}
 
 	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
-		return output.PrintError("parse_failed", err.Error(), nil)
+		return output.PrintErr(ctx, "parse_failed", err, nil)
 	}
 
 	if len(data.CurrentCondition) == 0 {
-		return output.PrintError("not_found", "Location not found: "+location, nil)
+		return output.PrintError(ctx, "not_found", "Location not found: "+location, nil)
 	}
 
 	cc := data.CurrentCondition[0]
@@ -240,7 +254,7 @@ func fetchWeather(location string, forecastDays int) error {
 		}
 	}
 
-	return output.Print(weather)
+	return output.Print(ctx, weather)
 }
 
 func atoi(s string) int {
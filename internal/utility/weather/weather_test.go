@@ -1,6 +1,7 @@
 package weather
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
@@ -51,6 +52,7 @@ func TestAtoi(t *testing.T) {
 }
 
 func TestFetchWeatherNow(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		data := map[string]any{
 			"current_condition": []map[string]any{
@@ -83,13 +85,14 @@ func TestFetchWeatherNow(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	err := fetchWeather("test", 0)
+	err := fetchWeather(ctx, "test", 0)
 	if err != nil {
 		t.Errorf("fetchWeather failed: %v", err)
 	}
 }
 
 func TestFetchWeatherForecast(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		data := map[string]any{
 			"current_condition": []map[string]any{
@@ -138,13 +141,14 @@ func TestFetchWeatherForecast(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	err := fetchWeather("test", 1)
+	err := fetchWeather(ctx, "test", 1)
 	if err != nil {
 		t.Errorf("fetchWeather with forecast failed: %v", err)
 	}
 }
 
 func TestFetchWeatherNotFound(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		data := map[string]any{
 			"current_condition": []map[string]any{},
@@ -157,13 +161,14 @@ func TestFetchWeatherNotFound(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	err := fetchWeather("invalid", 0)
+	err := fetchWeather(ctx, "invalid", 0)
 	if err == nil {
 		t.Error("expected error for not found location, got nil")
 	}
 }
 
 func TestFetchWeatherHTTPError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 	}))
@@ -173,13 +178,14 @@ func TestFetchWeatherHTTPError(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	err := fetchWeather("test", 0)
+	err := fetchWeather(ctx, "test", 0)
 	if err == nil {
 		t.Error("expected error for HTTP 500, got nil")
 	}
 }
 
 func TestFetchWeatherParseError(t *testing.T) {
+	ctx := context.Background()
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Write([]byte("invalid json"))
 	}))
@@ -189,7 +195,7 @@ func TestFetchWeatherParseError(t *testing.T) {
 	baseURL = srv.URL
 	defer func() { baseURL = oldURL }()
 
-	err := fetchWeather("test", 0)
+	err := fetchWeather(ctx, "test", 0)
 	if err == nil {
 		t.Error("expected parse error, got nil")
 	}
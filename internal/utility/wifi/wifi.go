@@ -2,6 +2,7 @@ package wifi
 
 import (
 	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os/exec"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/unstablemind/pocket/internal/common/registry"
 	"github.com/unstablemind/pocket/pkg/output"
 )
 
@@ -42,6 +44,16 @@ type ConnectionInfo struct {
 	Connected bool   `json:"connected"`
 }
 
+func init() {
+	registry.Register(registry.Integration{
+		ID:          "wifi",
+		Name:        "WiFi",
+		Group:       "utility",
+		Description: "WiFi network analysis and signal quality",
+		New:         NewCmd,
+	})
+}
+
 func NewCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "wifi",
@@ -60,7 +72,8 @@ func newScanCmd() *cobra.Command {
 		Use:   "scan",
 		Short: "Scan nearby WiFi networks with signal strength",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return scanNetworks()
+			ctx := cmd.Context()
+			return scanNetworks(ctx)
 		},
 	}
 }
@@ -70,32 +83,33 @@ func newCurrentCmd() *cobra.Command {
 		Use:   "current",
 		Short: "Show current WiFi connection details",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return currentConnection()
+			ctx := cmd.Context()
+			return currentConnection(ctx)
 		},
 	}
 }
 
-func scanNetworks() error {
+func scanNetworks(ctx context.Context) error {
 	switch runtime.GOOS {
 	case "darwin":
-		return scanDarwin()
+		return scanDarwin(ctx)
 	case "linux":
-		return scanLinux()
+		return scanLinux(ctx)
 	default:
-		return output.PrintError("platform_unsupported",
+		return output.PrintError(ctx, "platform_unsupported",
 			fmt.Sprintf("WiFi scan not supported on %s", runtime.GOOS),
 			map[string]string{"supported": "macOS, Linux"})
 	}
 }
 
-func currentConnection() error {
+func currentConnection(ctx context.Context) error {
 	switch runtime.GOOS {
 	case "darwin":
-		return currentDarwin()
+		return currentDarwin(ctx)
 	case "linux":
-		return currentLinux()
+		return currentLinux(ctx)
 	default:
-		return output.PrintError("platform_unsupported",
+		return output.PrintError(ctx, "platform_unsupported",
 			fmt.Sprintf("WiFi info not supported on %s", runtime.GOOS),
 			map[string]string{"supported": "macOS, Linux"})
 	}
@@ -127,32 +141,32 @@ type spAirPortNetwork struct {
 }
 
 // macOS implementation using system_profiler (airport CLI was removed in macOS 14 Sonoma)
-func scanDarwin() error {
+func scanDarwin(ctx context.Context) error {
 	out, err := exec.Command("system_profiler", "SPAirPortDataType", "-json").CombinedOutput()
 	if err != nil {
-		return output.PrintError("wifi_scan_error",
+		return output.PrintError(ctx, "wifi_scan_error",
 			fmt.Sprintf("system_profiler failed: %v", err),
 			map[string]string{"suggestion": "WiFi may be disabled"})
 	}
 
 	networks := parseSystemProfilerScan(out)
 
-	return output.Print(ScanResult{
+	return output.Print(ctx, ScanResult{
 		Networks: networks,
 		Count:    len(networks),
 	})
 }
 
-func currentDarwin() error {
+func currentDarwin(ctx context.Context) error {
 	out, err := exec.Command("system_profiler", "SPAirPortDataType", "-json").CombinedOutput()
 	if err != nil {
-		return output.PrintError("wifi_info_error",
+		return output.PrintError(ctx, "wifi_info_error",
 			fmt.Sprintf("system_profiler failed: %v", err),
 			map[string]string{"suggestion": "WiFi may be disabled"})
 	}
 
 	info := parseSystemProfilerCurrent(out)
-	return output.Print(info)
+	return output.Print(ctx, info)
 }
 
 // parseSystemProfilerScan extracts nearby networks from system_profiler JSON output
@@ -288,10 +302,10 @@ func parseSignalNoise(sn string) (rssi int, noise int) {
 }
 
 // Linux implementation using nmcli
-func scanLinux() error {
+func scanLinux(ctx context.Context) error {
 	out, err := exec.Command("nmcli", "-t", "-f", "SSID,BSSID,SIGNAL,CHAN,SECURITY", "dev", "wifi", "list").CombinedOutput()
 	if err != nil {
-		return output.PrintError("wifi_scan_error",
+		return output.PrintError(ctx, "wifi_scan_error",
 			fmt.Sprintf("nmcli scan failed: %v", err),
 			map[string]string{"suggestion": "Ensure NetworkManager is installed and WiFi is enabled"})
 	}
@@ -323,19 +337,19 @@ func scanLinux() error {
 		networks = append(networks, n)
 	}
 
-	return output.Print(ScanResult{
+	return output.Print(ctx, ScanResult{
 		Networks: networks,
 		Count:    len(networks),
 	})
 }
 
-func currentLinux() error {
+func currentLinux(ctx context.Context) error {
 	out, err := exec.Command("nmcli", "-t", "-f", "GENERAL.CONNECTION,WIFI.SSID,WIFI.BSSID,WIFI.CHAN,WIFI.RATE,WIFI.SIGNAL,WIFI.SECURITY", "dev", "show", "wlan0").CombinedOutput()
 	if err != nil {
 		// Try common alternative interface names
 		out, err = exec.Command("nmcli", "-t", "-f", "active,ssid,bssid,signal,chan,security", "dev", "wifi").CombinedOutput()
 		if err != nil {
-			return output.PrintError("wifi_info_error",
+			return output.PrintError(ctx, "wifi_info_error",
 				fmt.Sprintf("nmcli failed: %v", err), nil)
 		}
 	}
@@ -374,5 +388,5 @@ func currentLinux() error {
 		}
 	}
 
-	return output.Print(info)
+	return output.Print(ctx, info)
 }
@@ -0,0 +1,37 @@
+package output
+
+import "sync"
+
+// Classifier recognizes error messages that deserve a more specific code
+// than the one chosen by the failing command, such as "rate_limited" for an
+// HTTP error raised deep inside a shared transport.
+type Classifier func(message string) (code string, details any, ok bool)
+
+var (
+	classifiersMu sync.RWMutex
+	classifiers   []Classifier
+)
+
+// RegisterClassifier adds a classifier consulted by PrintError
+func RegisterClassifier(c Classifier) {
+	classifiersMu.Lock()
+	defer classifiersMu.Unlock()
+	classifiers = append(classifiers, c)
+}
+
+// classify applies the first matching classifier. Details passed by the
+// caller win over the classifier's.
+func classify(code, message string, details any) (string, any) {
+	classifiersMu.RLock()
+	defer classifiersMu.RUnlock()
+
+	for _, c := range classifiers {
+		if newCode, newDetails, ok := c(message); ok {
+			if details == nil {
+				details = newDetails
+			}
+			return newCode, details
+		}
+	}
+	return code, details
+}
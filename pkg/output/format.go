@@ -0,0 +1,300 @@
+package output
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// object is a JSON object that remembers key order, so struct fields keep
+// their declaration order through normalization, queries and rendering
+type object struct {
+	keys []string
+	vals map[string]any
+}
+
+func newObject() *object {
+	return &object{vals: make(map[string]any)}
+}
+
+func (o *object) get(key string) (any, bool) {
+	v, ok := o.vals[key]
+	return v, ok
+}
+
+func (o *object) set(key string, val any) {
+	if _, ok := o.vals[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.vals[key] = val
+}
+
+// MarshalJSON writes keys in insertion order
+func (o *object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		val, err := json.Marshal(o.vals[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// normalize converts any value into its generic JSON form (nil, bool,
+// json.Number, string, []any, *object) honoring json struct tags
+func normalize(data any) (any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := newObject()
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, _ := keyTok.(string)
+				val, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj.set(key, val)
+			}
+			_, err := dec.Token()
+			return obj, err
+		case '[':
+			arr := make([]any, 0)
+			for dec.More() {
+				val, err := decodeValue(dec)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, val)
+			}
+			_, err := dec.Token()
+			return arr, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", t)
+	default:
+		return t, nil
+	}
+}
+
+// records flattens normalized data into rows for tabular formats
+func records(data any) []*object {
+	switch v := data.(type) {
+	case []any:
+		rows := make([]*object, 0, len(v))
+		for _, item := range v {
+			if obj, ok := item.(*object); ok {
+				rows = append(rows, obj)
+			} else {
+				row := newObject()
+				row.set("value", item)
+				rows = append(rows, row)
+			}
+		}
+		return rows
+	case *object:
+		return []*object{v}
+	case nil:
+		return nil
+	default:
+		row := newObject()
+		row.set("value", v)
+		return []*object{row}
+	}
+}
+
+// columnsOf returns the union of row keys in first-seen order
+func columnsOf(rows []*object) []string {
+	seen := make(map[string]bool)
+	var cols []string
+	for _, row := range rows {
+		for _, k := range row.keys {
+			if !seen[k] {
+				seen[k] = true
+				cols = append(cols, k)
+			}
+		}
+	}
+	return cols
+}
+
+// cellString renders a normalized value as a single cell
+func cellString(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case json.Number:
+		return val.String()
+	case bool:
+		if val {
+			return "true"
+		}
+		return "false"
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+}
+
+func printYAML(w io.Writer, data any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(data)); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func yamlNode(v any) *yaml.Node {
+	switch val := v.(type) {
+	case *object:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		for _, k := range val.keys {
+			n.Content = append(n.Content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k},
+				yamlNode(val.vals[k]))
+		}
+		return n
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		for _, item := range val {
+			n.Content = append(n.Content, yamlNode(item))
+		}
+		return n
+	case nil:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: cellString(val)}
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(val.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: val.String()}
+	default:
+		n := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: cellString(val)}
+		if strings.Contains(n.Value, "\n") {
+			n.Style = yaml.LiteralStyle
+		}
+		return n
+	}
+}
+
+func printCSV(w io.Writer, data any) error {
+	rows := records(data)
+	if len(rows) == 0 {
+		return nil
+	}
+	cols := columnsOf(rows)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(cols); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		line := make([]string, len(cols))
+		for i, c := range cols {
+			line[i] = cellString(row.vals[c])
+		}
+		if err := cw.Write(line); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func printNDJSON(w io.Writer, data any) error {
+	items, ok := data.([]any)
+	if !ok {
+		items = []any{data}
+	}
+	enc := json.NewEncoder(w)
+	for _, item := range items {
+		if err := enc.Encode(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printMarkdown(w io.Writer, data any) error {
+	switch v := data.(type) {
+	case *object:
+		fmt.Fprintln(w, "| key | value |")
+		fmt.Fprintln(w, "| --- | --- |")
+		for _, k := range v.keys {
+			fmt.Fprintf(w, "| %s | %s |\n", markdownCell(k), markdownCell(cellString(v.vals[k])))
+		}
+	case []any:
+		rows := records(v)
+		if len(rows) == 0 {
+			return nil
+		}
+		cols := columnsOf(rows)
+		header := make([]string, len(cols))
+		sep := make([]string, len(cols))
+		for i, c := range cols {
+			header[i] = markdownCell(c)
+			sep[i] = "---"
+		}
+		fmt.Fprintf(w, "| %s |\n", strings.Join(header, " | "))
+		fmt.Fprintf(w, "| %s |\n", strings.Join(sep, " | "))
+		for _, row := range rows {
+			cells := make([]string, len(cols))
+			for i, c := range cols {
+				cells[i] = markdownCell(cellString(row.vals[c]))
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(cells, " | "))
+		}
+	default:
+		fmt.Fprintln(w, cellString(v))
+	}
+	return nil
+}
+
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
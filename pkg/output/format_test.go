@@ -0,0 +1,125 @@
+package output
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type formatItem struct {
+	Name  string   `json:"name"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags,omitempty"`
+	Note  string   `json:"note,omitempty"`
+}
+
+var formatItems = []formatItem{
+	{Name: "alpha", Count: 1, Tags: []string{"a", "b"}},
+	{Name: "beta|pipe", Count: 2, Note: "line1\nline2"},
+}
+
+func printWithFormat(t *testing.T, f string, data any) string {
+	t.Helper()
+	ctx := context.Background()
+	SetFormat(ctx, f)
+	defer SetFormat(ctx, "json")
+	return captureStdout(func() {
+		if err := Print(ctx, data); err != nil {
+			t.Errorf("print failed: %v", err)
+		}
+	})
+}
+
+func TestPrintYAML(t *testing.T) {
+	out := printWithFormat(t, "yaml", formatItems)
+
+	expected := `- name: alpha
+  count: 1
+  tags:
+    - a
+    - b
+- name: beta|pipe
+  count: 2
+  note: |-
+    line1
+    line2
+`
+	if out != expected {
+		t.Errorf("unexpected yaml:\n%s\nwant:\n%s", out, expected)
+	}
+}
+
+func TestPrintYAMLQuotesAmbiguousStrings(t *testing.T) {
+	out := printWithFormat(t, "yaml", map[string]string{"value": "true"})
+	if strings.TrimSpace(out) != `value: "true"` {
+		t.Errorf("expected quoted string, got %q", out)
+	}
+}
+
+func TestPrintCSV(t *testing.T) {
+	out := printWithFormat(t, "csv", formatItems)
+
+	expected := "name,count,tags,note\n" +
+		"alpha,1,\"[\"\"a\"\",\"\"b\"\"]\",\n" +
+		"beta|pipe,2,,\"line1\nline2\"\n"
+	if out != expected {
+		t.Errorf("unexpected csv:\n%q\nwant:\n%q", out, expected)
+	}
+}
+
+func TestPrintCSVScalars(t *testing.T) {
+	out := printWithFormat(t, "csv", []string{"x", "y"})
+	if out != "value\nx\ny\n" {
+		t.Errorf("unexpected csv: %q", out)
+	}
+}
+
+func TestPrintNDJSON(t *testing.T) {
+	out := printWithFormat(t, "ndjson", formatItems)
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), out)
+	}
+	if lines[0] != `{"name":"alpha","count":1,"tags":["a","b"]}` {
+		t.Errorf("unexpected first line: %s", lines[0])
+	}
+}
+
+func TestPrintNDJSONSingleValue(t *testing.T) {
+	out := printWithFormat(t, "ndjson", formatItem{Name: "solo"})
+	if strings.TrimSpace(out) != `{"name":"solo","count":0}` {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestPrintMarkdown(t *testing.T) {
+	out := printWithFormat(t, "markdown", formatItems)
+
+	expected := "| name | count | tags | note |\n" +
+		"| --- | --- | --- | --- |\n" +
+		"| alpha | 1 | [\"a\",\"b\"] |  |\n" +
+		"| beta\\|pipe | 2 |  | line1<br>line2 |\n"
+	if out != expected {
+		t.Errorf("unexpected markdown:\n%s\nwant:\n%s", out, expected)
+	}
+}
+
+func TestPrintMarkdownObject(t *testing.T) {
+	out := printWithFormat(t, "markdown", formatItem{Name: "solo", Count: 3})
+
+	if !strings.Contains(out, "| name | solo |") || !strings.Contains(out, "| count | 3 |") {
+		t.Errorf("unexpected markdown: %q", out)
+	}
+}
+
+func TestNormalizeKeepsFieldOrder(t *testing.T) {
+	v, err := normalize(formatItem{Name: "n", Count: 1})
+	if err != nil {
+		t.Fatalf("normalize failed: %v", err)
+	}
+	obj := v.(*object)
+	if strings.Join(obj.keys, ",") != "name,count" {
+		t.Errorf("unexpected key order: %v", obj.keys)
+	}
+}
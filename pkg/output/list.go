@@ -0,0 +1,50 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// List prints a result set that arrives in pages. In NDJSON format each
+// item is written as soon as it is added, so consumers can start on large
+// results before the last page arrives. Other formats, and any --query,
+// need the whole set and print it on Close.
+type List struct {
+	settings *settings
+	stream   bool
+	items    []any
+}
+
+// NewList starts a list in the format configured in ctx
+func NewList(ctx context.Context) *List {
+	s := from(ctx)
+	return &List{settings: s, stream: s.format == formatNDJSON && s.query == nil, items: []any{}}
+}
+
+// Add appends one item, writing it immediately when streaming
+func (l *List) Add(item any) error {
+	if !l.stream {
+		l.items = append(l.items, item)
+		return nil
+	}
+	v, err := normalize(item)
+	if err != nil {
+		return l.settings.printError("format_failed", err.Error(), nil)
+	}
+	if fields := l.settings.fields; len(fields) > 0 {
+		v = projectFields(v, fields)
+	}
+	return json.NewEncoder(l.settings.writer()).Encode(v)
+}
+
+// Close finishes the list. next is the cursor for the following page, or
+// "" when the list is complete.
+func (l *List) Close(next string) error {
+	if !l.stream {
+		return l.settings.printPage(l.items, next)
+	}
+	if next != "" {
+		return l.settings.printCursor(next)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package output
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestListStreamsNDJSON(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	SetOutput(ctx, &buf)
+	SetFormat(ctx, "ndjson")
+	SetFields(ctx, []string{"name"})
+	defer func() {
+		SetOutput(ctx, nil)
+		SetFormat(ctx, "json")
+		SetFields(ctx, nil)
+	}()
+
+	list := NewList(ctx)
+	if err := list.Add(formatItems[0]); err != nil {
+		t.Fatal(err)
+	}
+	// Written before the list is closed
+	if got := strings.TrimSpace(buf.String()); got != `{"name":"alpha"}` {
+		t.Fatalf("after first Add: %q", got)
+	}
+	if err := list.Add(formatItems[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := list.Close("abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	want := []string{`{"name":"alpha"}`, `{"name":"beta|pipe"}`, `{"next_cursor":"abc"}`}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("lines = %q", lines)
+	}
+}
+
+func TestListJSONNextCursor(t *testing.T) {
+	ctx := context.Background()
+	var buf bytes.Buffer
+	SetOutput(ctx, &buf)
+	defer SetOutput(ctx, nil)
+
+	list := NewList(ctx)
+	for _, item := range formatItems {
+		if err := list.Add(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := list.Close("abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	var resp struct {
+		Data       []formatItem `json:"data"`
+		NextCursor string       `json:"next_cursor"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data) != 2 || resp.NextCursor != "abc" {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestPrintPageTableNotesCursor(t *testing.T) {
+	ctx := context.Background()
+	var out, errOut bytes.Buffer
+	SetOutput(ctx, &out)
+	SetErrorOutput(ctx, &errOut)
+	SetFormat(ctx, "table")
+	defer func() {
+		SetOutput(ctx, nil)
+		SetErrorOutput(ctx, nil)
+		SetFormat(ctx, "json")
+	}()
+
+	if err := PrintPage(ctx, formatItems, "abc"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "abc") {
+		t.Errorf("cursor in table output: %q", out.String())
+	}
+	if errOut.String() != "More results: --cursor abc\n" {
+		t.Errorf("stderr = %q", errOut.String())
+	}
+}
@@ -1,23 +1,59 @@
 package output
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
-	"text/tabwriter"
+	"strings"
 )
 
-const formatJSON = "json"
-
-var (
-	format  = formatJSON
-	verbose = false
+const (
+	formatJSON   = "json"
+	formatNDJSON = "ndjson"
 )
 
+// settings are the output options of the command being run
+type settings struct {
+	format  string
+	verbose bool
+	out     io.Writer
+	errOut  io.Writer
+	query   *Query
+	fields  []string
+	columns []string
+}
+
+func newSettings() *settings {
+	return &settings{format: formatJSON}
+}
+
+type settingsKey struct{}
+
+// defaults are the settings of code running outside NewContext, such as
+// tests printing directly
+var defaults = newSettings()
+
+// NewContext returns a copy of ctx with output settings of its own, so a
+// command run with it prints independently of commands running alongside
+func NewContext(ctx context.Context) context.Context {
+	return context.WithValue(ctx, settingsKey{}, newSettings())
+}
+
+// from returns the settings carried by ctx
+func from(ctx context.Context) *settings {
+	if s, ok := ctx.Value(settingsKey{}).(*settings); ok {
+		return s
+	}
+	return defaults
+}
+
 // PrintedError wraps an error that has already been printed
 type PrintedError struct {
-	Err error
+	Code string
+	Err  error
 }
 
 func (e *PrintedError) Error() string {
@@ -34,21 +70,88 @@ func IsPrinted(err error) bool {
 	return errors.As(err, &pe)
 }
 
-// SetFormat sets the global output format
-func SetFormat(f string) {
-	format = f
+// ErrorCode returns the code err was printed with, or "" if it wasn't
+func ErrorCode(err error) string {
+	var pe *PrintedError
+	if errors.As(err, &pe) {
+		return pe.Code
+	}
+	return ""
+}
+
+// SetFormat sets the output format
+func SetFormat(ctx context.Context, f string) {
+	from(ctx).format = f
 }
 
 // SetVerbose sets verbose mode
-func SetVerbose(v bool) {
-	verbose = v
+func SetVerbose(ctx context.Context, v bool) {
+	from(ctx).verbose = v
+}
+
+// SetQuery sets a jq-like expression applied to data before printing.
+// An empty expression clears it.
+func SetQuery(ctx context.Context, expr string) error {
+	if strings.TrimSpace(expr) == "" {
+		from(ctx).query = nil
+		return nil
+	}
+	q, err := ParseQuery(expr)
+	if err != nil {
+		return err
+	}
+	from(ctx).query = q
+	return nil
+}
+
+// SetFields restricts printed data to the given dotted field paths.
+// Fields are applied before the query.
+func SetFields(ctx context.Context, f []string) {
+	from(ctx).fields = f
+}
+
+// SetOutput redirects printed responses to w. Passing nil restores stdout.
+func SetOutput(ctx context.Context, w io.Writer) {
+	from(ctx).out = w
+}
+
+// Writer returns where responses are currently printed
+func Writer(ctx context.Context) io.Writer {
+	return from(ctx).writer()
+}
+
+// SetErrorOutput redirects errors printed in text formats to w. Passing
+// nil restores stderr.
+func SetErrorOutput(ctx context.Context, w io.Writer) {
+	from(ctx).errOut = w
+}
+
+// ErrorWriter returns where errors in text formats are currently printed
+func ErrorWriter(ctx context.Context) io.Writer {
+	return from(ctx).errorWriter()
+}
+
+func (s *settings) writer() io.Writer {
+	if s.out != nil {
+		return s.out
+	}
+	return os.Stdout
+}
+
+func (s *settings) errorWriter() io.Writer {
+	if s.errOut != nil {
+		return s.errOut
+	}
+	return os.Stderr
 }
 
 // Response is the standard response structure
 type Response struct {
-	Success bool   `json:"success"`
-	Data    any    `json:"data,omitempty"`
-	Error   *Error `json:"error,omitempty"`
+	Success bool `json:"success"`
+	Data    any  `json:"data,omitempty"`
+	// NextCursor continues a paginated list where this response stopped
+	NextCursor string `json:"next_cursor,omitempty"`
+	Error      *Error `json:"error,omitempty"`
 }
 
 // Error represents an error response
@@ -59,21 +162,119 @@ type Error struct {
 }
 
 // Print outputs data in the configured format
-func Print(data any) error {
-	switch format {
+func Print(ctx context.Context, data any) error {
+	return from(ctx).printPage(data, "")
+}
+
+// PrintPage outputs one page of a list and the cursor for the next page,
+// if any. JSON carries it as next_cursor, NDJSON as a final
+// {"next_cursor": ...} line and the other formats as a note on stderr.
+func PrintPage(ctx context.Context, data any, next string) error {
+	return from(ctx).printPage(data, next)
+}
+
+func (s *settings) printPage(data any, next string) error {
+	data, err := s.shape(data)
+	if err != nil {
+		return s.printError("invalid_query", err.Error(), nil)
+	}
+
+	switch s.format {
 	case formatJSON:
-		return printJSON(Response{Success: true, Data: data})
+		return s.printJSON(Response{Success: true, Data: data, NextCursor: next})
 	case "text":
-		return printText(data)
+		err = s.printText(data)
 	case "table":
-		return printTable(data)
+		err = s.printTable(data)
+	case "yaml", "csv", formatNDJSON, "markdown":
+		normalized, nerr := normalize(data)
+		if nerr != nil {
+			return s.printError("format_failed", nerr.Error(), nil)
+		}
+		switch s.format {
+		case "yaml":
+			err = printYAML(s.writer(), normalized)
+		case "csv":
+			err = printCSV(s.writer(), normalized)
+		case formatNDJSON:
+			if err = printNDJSON(s.writer(), normalized); err == nil && next != "" {
+				return s.printCursor(next)
+			}
+			return err
+		default:
+			err = printMarkdown(s.writer(), normalized)
+		}
 	default:
-		return printJSON(Response{Success: true, Data: data})
+		return s.printJSON(Response{Success: true, Data: data, NextCursor: next})
+	}
+	if err == nil && next != "" {
+		fmt.Fprintf(s.errorWriter(), "More results: --cursor %s\n", next)
+	}
+	return err
+}
+
+// printCursor ends an NDJSON list that has more pages
+func (s *settings) printCursor(next string) error {
+	return json.NewEncoder(s.writer()).Encode(map[string]string{"next_cursor": next})
+}
+
+// shape applies the configured field projection and query, if any
+func (s *settings) shape(data any) (any, error) {
+	if s.query == nil && len(s.fields) == 0 {
+		return data, nil
+	}
+
+	normalized, err := normalize(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.fields) > 0 {
+		normalized = projectFields(normalized, s.fields)
 	}
+	if s.query != nil {
+		return s.query.Apply(normalized)
+	}
+	return normalized, nil
+}
+
+// CodedError is an error that knows the code it should be reported under,
+// such as a rate limit raised deep inside a shared transport
+type CodedError interface {
+	error
+	ErrorCode() string
+	ErrorDetails() any
+}
+
+// timeout is implemented by net.Error, including the error http.Client
+// returns when its Timeout runs out
+type timeout interface {
+	Timeout() bool
+}
+
+// PrintErr outputs err like PrintError. When a CodedError is in its chain,
+// that error's code replaces code, and its details are used unless the
+// caller passed some. Timeouts are reported as "timeout".
+func PrintErr(ctx context.Context, code string, err error, details any) error {
+	var coded CodedError
+	var t timeout
+	switch {
+	case errors.As(err, &coded):
+		code = coded.ErrorCode()
+		if details == nil {
+			details = coded.ErrorDetails()
+		}
+	case errors.As(err, &t) && t.Timeout():
+		code = "timeout"
+	}
+	return PrintError(ctx, code, err.Error(), details)
 }
 
 // PrintError outputs an error in the configured format and returns a PrintedError
-func PrintError(code, message string, details any) error {
+func PrintError(ctx context.Context, code, message string, details any) error {
+	return from(ctx).printError(code, message, details)
+}
+
+func (s *settings) printError(code, message string, details any) error {
 	resp := Response{
 		Success: false,
 		Error: &Error{
@@ -83,67 +284,44 @@ func PrintError(code, message string, details any) error {
 		},
 	}
 
-	switch format {
-	case formatJSON:
-		_ = printJSON(resp)
+	switch s.format {
+	case formatJSON, formatNDJSON:
+		_ = s.printJSON(resp)
 	default:
-		fmt.Fprintf(os.Stderr, "Error [%s]: %s\n", code, message)
+		fmt.Fprintf(s.errorWriter(), "Error [%s]: %s\n", code, message)
 	}
 
 	// Return a PrintedError so callers know it's already been output
-	return &PrintedError{Err: fmt.Errorf("%s: %s", code, message)}
+	return &PrintedError{Code: code, Err: fmt.Errorf("%s: %s", code, message)}
 }
 
-func printJSON(v any) error {
-	enc := json.NewEncoder(os.Stdout)
-	if verbose {
+func (s *settings) printJSON(v any) error {
+	enc := json.NewEncoder(s.writer())
+	if s.verbose {
 		enc.SetIndent("", "  ")
 	}
 	return enc.Encode(v)
 }
 
-func printText(data any) error {
+func (s *settings) printText(data any) error {
 	switch v := data.(type) {
 	case string:
-		fmt.Println(v)
+		fmt.Fprintln(s.writer(), v)
 	case map[string]string:
 		for k, val := range v {
-			fmt.Printf("%s: %s\n", k, val)
+			fmt.Fprintf(s.writer(), "%s: %s\n", k, val)
 		}
 	case map[string]any:
 		for k, val := range v {
-			fmt.Printf("%s: %v\n", k, val)
+			fmt.Fprintf(s.writer(), "%s: %v\n", k, val)
 		}
-	default:
-		// Fall back to JSON for complex types
-		return printJSON(data)
-	}
-	return nil
-}
-
-func printTable(data any) error {
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	defer w.Flush()
-
-	switch v := data.(type) {
-	case []map[string]any:
-		if len(v) == 0 {
-			return nil
-		}
-		// Print headers from first item
-		for k := range v[0] {
-			fmt.Fprintf(w, "%s\t", k)
-		}
-		fmt.Fprintln(w)
-		// Print rows
-		for _, row := range v {
-			for _, val := range row {
-				fmt.Fprintf(w, "%v\t", val)
-			}
-			fmt.Fprintln(w)
+	case *object:
+		for _, k := range v.keys {
+			fmt.Fprintf(s.writer(), "%s: %s\n", k, cellString(v.vals[k]))
 		}
 	default:
-		return printJSON(data)
+		// Fall back to JSON for complex types
+		return s.printJSON(data)
 	}
 	return nil
 }
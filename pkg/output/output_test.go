@@ -2,6 +2,7 @@ package output
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -26,11 +27,12 @@ func captureStdout(fn func()) string {
 }
 
 func TestPrintJSON(t *testing.T) {
-	SetFormat("json")
-	defer SetFormat("json")
+	ctx := context.Background()
+	SetFormat(ctx, "json")
+	defer SetFormat(ctx, "json")
 
 	out := captureStdout(func() {
-		_ = Print(map[string]string{"key": "value"})
+		_ = Print(ctx, map[string]string{"key": "value"})
 	})
 
 	var resp Response
@@ -55,11 +57,12 @@ func TestPrintJSON(t *testing.T) {
 }
 
 func TestPrintText(t *testing.T) {
-	SetFormat("text")
-	defer SetFormat("json")
+	ctx := context.Background()
+	SetFormat(ctx, "text")
+	defer SetFormat(ctx, "json")
 
 	out := captureStdout(func() {
-		_ = Print("hello world")
+		_ = Print(ctx, "hello world")
 	})
 
 	if strings.TrimSpace(out) != "hello world" {
@@ -68,11 +71,12 @@ func TestPrintText(t *testing.T) {
 }
 
 func TestPrintTextMap(t *testing.T) {
-	SetFormat("text")
-	defer SetFormat("json")
+	ctx := context.Background()
+	SetFormat(ctx, "text")
+	defer SetFormat(ctx, "json")
 
 	out := captureStdout(func() {
-		_ = Print(map[string]string{"name": "test"})
+		_ = Print(ctx, map[string]string{"name": "test"})
 	})
 
 	if !strings.Contains(out, "name: test") {
@@ -81,11 +85,12 @@ func TestPrintTextMap(t *testing.T) {
 }
 
 func TestPrintErrorJSON(t *testing.T) {
-	SetFormat("json")
-	defer SetFormat("json")
+	ctx := context.Background()
+	SetFormat(ctx, "json")
+	defer SetFormat(ctx, "json")
 
 	out := captureStdout(func() {
-		_ = PrintError("test_code", "test message", map[string]string{"detail": "info"})
+		_ = PrintError(ctx, "test_code", "test message", map[string]string{"detail": "info"})
 	})
 
 	var resp Response
@@ -108,12 +113,13 @@ func TestPrintErrorJSON(t *testing.T) {
 }
 
 func TestPrintErrorReturnsPrintedError(t *testing.T) {
-	SetFormat("json")
-	defer SetFormat("json")
+	ctx := context.Background()
+	SetFormat(ctx, "json")
+	defer SetFormat(ctx, "json")
 
 	var err error
 	captureStdout(func() {
-		err = PrintError("code", "msg", nil)
+		err = PrintError(ctx, "code", "msg", nil)
 	})
 
 	if err == nil {
@@ -156,50 +162,52 @@ func TestPrintedErrorUnwrap(t *testing.T) {
 }
 
 func TestSetFormat(t *testing.T) {
-	original := format
-	defer func() { format = original }()
+	ctx := NewContext(context.Background())
 
-	SetFormat("text")
-	if format != "text" {
-		t.Errorf("expected format=text, got %s", format)
+	SetFormat(ctx, "text")
+	if from(ctx).format != "text" {
+		t.Errorf("expected format=text, got %s", from(ctx).format)
 	}
 
-	SetFormat("table")
-	if format != "table" {
-		t.Errorf("expected format=table, got %s", format)
+	SetFormat(ctx, "table")
+	if from(ctx).format != "table" {
+		t.Errorf("expected format=table, got %s", from(ctx).format)
 	}
 
-	SetFormat("json")
-	if format != "json" {
-		t.Errorf("expected format=json, got %s", format)
+	SetFormat(ctx, "json")
+	if from(ctx).format != "json" {
+		t.Errorf("expected format=json, got %s", from(ctx).format)
+	}
+	if defaults.format != "json" {
+		t.Errorf("default format changed to %s", defaults.format)
 	}
 }
 
 func TestSetVerbose(t *testing.T) {
-	original := verbose
-	defer func() { verbose = original }()
+	ctx := NewContext(context.Background())
 
-	SetVerbose(true)
-	if !verbose {
+	SetVerbose(ctx, true)
+	if !from(ctx).verbose {
 		t.Error("expected verbose=true")
 	}
 
-	SetVerbose(false)
-	if verbose {
+	SetVerbose(ctx, false)
+	if from(ctx).verbose {
 		t.Error("expected verbose=false")
 	}
 }
 
 func TestPrintJSONVerboseIndent(t *testing.T) {
-	SetFormat("json")
-	SetVerbose(true)
+	ctx := context.Background()
+	SetFormat(ctx, "json")
+	SetVerbose(ctx, true)
 	defer func() {
-		SetFormat("json")
-		SetVerbose(false)
+		SetFormat(ctx, "json")
+		SetVerbose(ctx, false)
 	}()
 
 	out := captureStdout(func() {
-		_ = Print(map[string]string{"key": "val"})
+		_ = Print(ctx, map[string]string{"key": "val"})
 	})
 
 	// Verbose mode should produce indented JSON (multi-line)
@@ -210,11 +218,12 @@ func TestPrintJSONVerboseIndent(t *testing.T) {
 }
 
 func TestPrintNilData(t *testing.T) {
-	SetFormat("json")
-	defer SetFormat("json")
+	ctx := context.Background()
+	SetFormat(ctx, "json")
+	defer SetFormat(ctx, "json")
 
 	out := captureStdout(func() {
-		_ = Print(nil)
+		_ = Print(ctx, nil)
 	})
 
 	var resp Response
@@ -227,11 +236,12 @@ func TestPrintNilData(t *testing.T) {
 }
 
 func TestPrintErrorNilDetails(t *testing.T) {
-	SetFormat("json")
-	defer SetFormat("json")
+	ctx := context.Background()
+	SetFormat(ctx, "json")
+	defer SetFormat(ctx, "json")
 
 	out := captureStdout(func() {
-		_ = PrintError("err", "message", nil)
+		_ = PrintError(ctx, "err", "message", nil)
 	})
 
 	var resp Response
@@ -244,11 +254,12 @@ func TestPrintErrorNilDetails(t *testing.T) {
 }
 
 func TestPrintTableEmpty(t *testing.T) {
-	SetFormat("table")
-	defer SetFormat("json")
+	ctx := context.Background()
+	SetFormat(ctx, "table")
+	defer SetFormat(ctx, "json")
 
 	out := captureStdout(func() {
-		_ = Print([]map[string]any{})
+		_ = Print(ctx, []map[string]any{})
 	})
 
 	if out != "" {
@@ -257,11 +268,12 @@ func TestPrintTableEmpty(t *testing.T) {
 }
 
 func TestPrintDefaultFallsBackToJSON(t *testing.T) {
-	SetFormat("unknown_format")
-	defer SetFormat("json")
+	ctx := context.Background()
+	SetFormat(ctx, "unknown_format")
+	defer SetFormat(ctx, "json")
 
 	out := captureStdout(func() {
-		_ = Print(map[string]string{"k": "v"})
+		_ = Print(ctx, map[string]string{"k": "v"})
 	})
 
 	var resp Response
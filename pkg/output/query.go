@@ -0,0 +1,906 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Query is a compiled jq-like expression. The supported subset is:
+//
+//	.                identity
+//	.a.b  ."a b"     field access
+//	.[2]  .[1:3]     index and slice
+//	.[]              iterate array elements or object values
+//	a | b            pipe
+//	select(cond)     keep values where cond holds; cond compares a path with
+//	                 ==, !=, <, <=, >, >= or tests it for truthiness, joined by and/or
+//	{a, b: .c.d}     object construction
+//	[expr]           collect results into an array
+//	length, keys     builtins
+//
+// When a query iterates, results are always returned as an array.
+type Query struct {
+	root  node
+	multi bool
+}
+
+// ParseQuery compiles a jq-like expression
+func ParseQuery(src string) (*Query, error) {
+	p := &parser{src: src}
+	root, err := p.parsePipe()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	return &Query{root: root, multi: root.iterates()}, nil
+}
+
+// Apply evaluates the query against normalized data
+func (q *Query) Apply(data any) (any, error) {
+	results, err := q.root.eval(data)
+	if err != nil {
+		return nil, err
+	}
+	if q.multi || len(results) != 1 {
+		if results == nil {
+			results = []any{}
+		}
+		return results, nil
+	}
+	return results[0], nil
+}
+
+// Eval evaluates a jq-like expression against any JSON-encodable data
+// and returns the result as plain JSON values: map[string]any, []any,
+// string, json.Number, bool or nil
+func Eval(expr string, data any) (any, error) {
+	q, err := ParseQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	n, err := normalize(data)
+	if err != nil {
+		return nil, err
+	}
+	result, err := q.Apply(n)
+	if err != nil {
+		return nil, err
+	}
+	return plain(result)
+}
+
+// ParseCondition compiles a condition written as inside select(...),
+// e.g. `.count > 10 and .state == "open"`
+func ParseCondition(cond string) (*Query, error) {
+	p := &parser{src: "(" + cond + ")"}
+	root, err := p.parseSelect()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+	return &Query{root: root}, nil
+}
+
+// Test reports whether cond, written as inside select(...), holds for any
+// JSON-encodable data
+func Test(cond string, data any) (bool, error) {
+	q, err := ParseCondition(cond)
+	if err != nil {
+		return false, err
+	}
+	n, err := normalize(data)
+	if err != nil {
+		return false, err
+	}
+	results, err := q.root.eval(n)
+	return len(results) > 0, err
+}
+
+// plain turns normalized data back into encoding/json's generic values
+func plain(v any) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	err = dec.Decode(&out)
+	return out, err
+}
+
+// node is one stage of a compiled query
+type node interface {
+	eval(v any) ([]any, error)
+	iterates() bool
+}
+
+type pipeNode struct{ stages []node }
+
+func (n *pipeNode) eval(v any) ([]any, error) {
+	in := []any{v}
+	for _, stage := range n.stages {
+		var out []any
+		for _, item := range in {
+			res, err := stage.eval(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, res...)
+		}
+		in = out
+	}
+	return in, nil
+}
+
+func (n *pipeNode) iterates() bool {
+	for _, s := range n.stages {
+		if s.iterates() {
+			return true
+		}
+	}
+	return false
+}
+
+type stepKind int
+
+const (
+	stepField stepKind = iota
+	stepIndex
+	stepSlice
+	stepIter
+)
+
+type step struct {
+	kind       stepKind
+	field      string
+	index      int
+	start, end *int
+}
+
+type pathNode struct{ steps []step }
+
+func (n *pathNode) eval(v any) ([]any, error) {
+	vals := []any{v}
+	for _, s := range n.steps {
+		var next []any
+		for _, cur := range vals {
+			res, err := s.apply(cur)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, res...)
+		}
+		vals = next
+	}
+	return vals, nil
+}
+
+func (n *pathNode) iterates() bool {
+	for _, s := range n.steps {
+		if s.kind == stepIter {
+			return true
+		}
+	}
+	return false
+}
+
+func (s step) apply(v any) ([]any, error) {
+	switch s.kind {
+	case stepField:
+		switch obj := v.(type) {
+		case *object:
+			val, _ := obj.get(s.field)
+			return []any{val}, nil
+		case nil:
+			return []any{nil}, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with %q", typeName(v), s.field)
+		}
+	case stepIndex:
+		switch arr := v.(type) {
+		case []any:
+			i := s.index
+			if i < 0 {
+				i += len(arr)
+			}
+			if i < 0 || i >= len(arr) {
+				return []any{nil}, nil
+			}
+			return []any{arr[i]}, nil
+		case nil:
+			return []any{nil}, nil
+		default:
+			return nil, fmt.Errorf("cannot index %s with number", typeName(v))
+		}
+	case stepSlice:
+		arr, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("cannot slice %s", typeName(v))
+		}
+		start, end := 0, len(arr)
+		if s.start != nil {
+			start = clampIndex(*s.start, len(arr))
+		}
+		if s.end != nil {
+			end = clampIndex(*s.end, len(arr))
+		}
+		if start > end {
+			start = end
+		}
+		return []any{append([]any{}, arr[start:end]...)}, nil
+	case stepIter:
+		switch c := v.(type) {
+		case []any:
+			return c, nil
+		case *object:
+			out := make([]any, 0, len(c.keys))
+			for _, k := range c.keys {
+				out = append(out, c.vals[k])
+			}
+			return out, nil
+		default:
+			return nil, fmt.Errorf("cannot iterate over %s", typeName(v))
+		}
+	}
+	return nil, nil
+}
+
+func clampIndex(i, n int) int {
+	if i < 0 {
+		i += n
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > n {
+		return n
+	}
+	return i
+}
+
+type condNode struct {
+	left  node
+	op    string
+	right any
+}
+
+type selectNode struct {
+	// conds is a disjunction of conjunctions
+	conds [][]condNode
+}
+
+func (n *selectNode) eval(v any) ([]any, error) {
+	for _, and := range n.conds {
+		ok := true
+		for _, c := range and {
+			match, err := c.test(v)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return []any{v}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (n *selectNode) iterates() bool { return false }
+
+func (c condNode) test(v any) (bool, error) {
+	vals, err := c.left.eval(v)
+	if err != nil {
+		return false, err
+	}
+	for _, val := range vals {
+		if c.op == "" {
+			if truthy(val) {
+				return true, nil
+			}
+			continue
+		}
+		if compare(val, c.op, c.right) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type objectNode struct {
+	keys  []string
+	exprs []node
+}
+
+func (n *objectNode) eval(v any) ([]any, error) {
+	obj := newObject()
+	for i, k := range n.keys {
+		vals, err := n.exprs[i].eval(v)
+		if err != nil {
+			return nil, err
+		}
+		switch len(vals) {
+		case 0:
+			obj.set(k, nil)
+		case 1:
+			obj.set(k, vals[0])
+		default:
+			obj.set(k, vals)
+		}
+	}
+	return []any{obj}, nil
+}
+
+func (n *objectNode) iterates() bool { return false }
+
+type collectNode struct{ inner node }
+
+func (n *collectNode) eval(v any) ([]any, error) {
+	vals, err := n.inner.eval(v)
+	if err != nil {
+		return nil, err
+	}
+	if vals == nil {
+		vals = []any{}
+	}
+	return []any{vals}, nil
+}
+
+func (n *collectNode) iterates() bool { return false }
+
+type builtinNode struct{ name string }
+
+func (n *builtinNode) eval(v any) ([]any, error) {
+	switch n.name {
+	case "length":
+		switch c := v.(type) {
+		case []any:
+			return []any{json.Number(strconv.Itoa(len(c)))}, nil
+		case *object:
+			return []any{json.Number(strconv.Itoa(len(c.keys)))}, nil
+		case string:
+			return []any{json.Number(strconv.Itoa(len([]rune(c))))}, nil
+		case nil:
+			return []any{json.Number("0")}, nil
+		}
+		return nil, fmt.Errorf("%s has no length", typeName(v))
+	case "keys":
+		obj, ok := v.(*object)
+		if !ok {
+			return nil, fmt.Errorf("%s has no keys", typeName(v))
+		}
+		keys := make([]any, len(obj.keys))
+		for i, k := range obj.keys {
+			keys[i] = k
+		}
+		return []any{keys}, nil
+	}
+	return nil, fmt.Errorf("unknown function %s", n.name)
+}
+
+func (n *builtinNode) iterates() bool { return false }
+
+func truthy(v any) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	default:
+		return true
+	}
+}
+
+func compare(left any, op string, right any) bool {
+	if ln, ok := toFloat(left); ok {
+		if rn, ok := toFloat(right); ok {
+			switch op {
+			case "==":
+				return ln == rn
+			case "!=":
+				return ln != rn
+			case "<":
+				return ln < rn
+			case "<=":
+				return ln <= rn
+			case ">":
+				return ln > rn
+			case ">=":
+				return ln >= rn
+			}
+		}
+	}
+
+	if ls, ok := left.(string); ok {
+		if rs, ok := right.(string); ok {
+			switch op {
+			case "==":
+				return ls == rs
+			case "!=":
+				return ls != rs
+			case "<":
+				return ls < rs
+			case "<=":
+				return ls <= rs
+			case ">":
+				return ls > rs
+			case ">=":
+				return ls >= rs
+			}
+		}
+	}
+
+	switch op {
+	case "==":
+		return cellString(left) == cellString(right) && typeName(left) == typeName(right)
+	case "!=":
+		return cellString(left) != cellString(right) || typeName(left) != typeName(right)
+	}
+	return false
+}
+
+func toFloat(v any) (float64, bool) {
+	if n, ok := v.(json.Number); ok {
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case *object:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(rune(p.src[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *parser) peek() byte {
+	p.skipSpace()
+	if p.pos >= len(p.src) {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) consume(s string) bool {
+	p.skipSpace()
+	if strings.HasPrefix(p.src[p.pos:], s) {
+		p.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("query: "+format+" at position %d", append(args, p.pos)...)
+}
+
+func (p *parser) parsePipe() (node, error) {
+	var stages []node
+	for {
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		stages = append(stages, term)
+		if !p.consume("|") {
+			break
+		}
+	}
+	if len(stages) == 1 {
+		return stages[0], nil
+	}
+	return &pipeNode{stages: stages}, nil
+}
+
+func (p *parser) parseTerm() (node, error) {
+	switch c := p.peek(); {
+	case c == '.':
+		return p.parsePath()
+	case c == '{':
+		return p.parseObject()
+	case c == '[':
+		p.pos++
+		inner, err := p.parsePipe()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consume("]") {
+			return nil, p.errorf("expected ]")
+		}
+		return &collectNode{inner: inner}, nil
+	case isIdentStart(c):
+		name := p.parseIdent()
+		switch name {
+		case "select":
+			return p.parseSelect()
+		case "length", "keys":
+			return &builtinNode{name: name}, nil
+		}
+		return nil, p.errorf("unknown function %q", name)
+	case c == 0:
+		return nil, p.errorf("unexpected end of query")
+	default:
+		return nil, p.errorf("unexpected %q", string(c))
+	}
+}
+
+func (p *parser) parsePath() (node, error) {
+	if !p.consume(".") {
+		return nil, p.errorf("expected .")
+	}
+
+	var steps []step
+	// the first field may follow the leading dot directly
+	if p.pos < len(p.src) && (isIdentStart(p.src[p.pos]) || p.src[p.pos] == '"') {
+		name, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step{kind: stepField, field: name})
+	}
+
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '.':
+			p.pos++
+			name, err := p.parseKey()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step{kind: stepField, field: name})
+		case '[':
+			p.pos++
+			s, err := p.parseBracket()
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, s)
+		default:
+			return &pathNode{steps: steps}, nil
+		}
+	}
+	return &pathNode{steps: steps}, nil
+}
+
+func (p *parser) parseKey() (string, error) {
+	if p.pos < len(p.src) && p.src[p.pos] == '"' {
+		return p.parseString()
+	}
+	if p.pos >= len(p.src) || !isIdentStart(p.src[p.pos]) {
+		return "", p.errorf("expected field name")
+	}
+	return p.parseIdent(), nil
+}
+
+func (p *parser) parseBracket() (step, error) {
+	p.skipSpace()
+	if p.consume("]") {
+		return step{kind: stepIter}, nil
+	}
+
+	var start, end *int
+	if p.peek() != ':' {
+		n, err := p.parseInt()
+		if err != nil {
+			return step{}, err
+		}
+		if p.consume("]") {
+			return step{kind: stepIndex, index: n}, nil
+		}
+		start = &n
+	}
+	if !p.consume(":") {
+		return step{}, p.errorf("expected ] or :")
+	}
+	if p.peek() != ']' {
+		n, err := p.parseInt()
+		if err != nil {
+			return step{}, err
+		}
+		end = &n
+	}
+	if !p.consume("]") {
+		return step{}, p.errorf("expected ]")
+	}
+	return step{kind: stepSlice, start: start, end: end}, nil
+}
+
+func (p *parser) parseInt() (int, error) {
+	p.skipSpace()
+	begin := p.pos
+	if p.pos < len(p.src) && p.src[p.pos] == '-' {
+		p.pos++
+	}
+	for p.pos < len(p.src) && p.src[p.pos] >= '0' && p.src[p.pos] <= '9' {
+		p.pos++
+	}
+	n, err := strconv.Atoi(p.src[begin:p.pos])
+	if err != nil {
+		return 0, p.errorf("expected integer")
+	}
+	return n, nil
+}
+
+func (p *parser) parseObject() (node, error) {
+	p.consume("{")
+	obj := &objectNode{}
+	for {
+		if p.consume("}") {
+			return obj, nil
+		}
+		p.skipSpace()
+		key, err := p.parseKey()
+		if err != nil {
+			return nil, err
+		}
+		var expr node = &pathNode{steps: []step{{kind: stepField, field: key}}}
+		if p.consume(":") {
+			expr, err = p.parseTerm()
+			if err != nil {
+				return nil, err
+			}
+		}
+		obj.keys = append(obj.keys, key)
+		obj.exprs = append(obj.exprs, expr)
+		if !p.consume(",") {
+			if !p.consume("}") {
+				return nil, p.errorf("expected , or }")
+			}
+			return obj, nil
+		}
+	}
+}
+
+func (p *parser) parseSelect() (node, error) {
+	if !p.consume("(") {
+		return nil, p.errorf("expected ( after select")
+	}
+
+	sel := &selectNode{}
+	var and []condNode
+	for {
+		c, err := p.parseCond()
+		if err != nil {
+			return nil, err
+		}
+		and = append(and, c)
+
+		switch {
+		case p.consumeWord("and"):
+			continue
+		case p.consumeWord("or"):
+			sel.conds = append(sel.conds, and)
+			and = nil
+			continue
+		}
+		break
+	}
+	sel.conds = append(sel.conds, and)
+
+	if !p.consume(")") {
+		return nil, p.errorf("expected )")
+	}
+	return sel, nil
+}
+
+func (p *parser) parseCond() (condNode, error) {
+	left, err := p.parsePath()
+	if err != nil {
+		return condNode{}, err
+	}
+
+	for _, op := range []string{"==", "!=", "<=", ">=", "<", ">"} {
+		if p.consume(op) {
+			right, err := p.parseLiteral()
+			if err != nil {
+				return condNode{}, err
+			}
+			return condNode{left: left, op: op, right: right}, nil
+		}
+	}
+	return condNode{left: left}, nil
+}
+
+func (p *parser) consumeWord(w string) bool {
+	p.skipSpace()
+	if !strings.HasPrefix(p.src[p.pos:], w) {
+		return false
+	}
+	end := p.pos + len(w)
+	if end < len(p.src) && isIdentChar(p.src[end]) {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *parser) parseLiteral() (any, error) {
+	switch c := p.peek(); {
+	case c == '"':
+		return p.parseString()
+	case c == '-' || (c >= '0' && c <= '9'):
+		begin := p.pos
+		p.pos++
+		for p.pos < len(p.src) && strings.IndexByte("0123456789.eE+-", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		num := json.Number(p.src[begin:p.pos])
+		if _, err := num.Float64(); err != nil {
+			return nil, p.errorf("invalid number %q", string(num))
+		}
+		return num, nil
+	case p.consumeWord("true"):
+		return true, nil
+	case p.consumeWord("false"):
+		return false, nil
+	case p.consumeWord("null"):
+		return nil, nil
+	default:
+		return nil, p.errorf("expected literal")
+	}
+}
+
+func (p *parser) parseString() (string, error) {
+	begin := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+			continue
+		case '"':
+			p.pos++
+			s, err := strconv.Unquote(p.src[begin:p.pos])
+			if err != nil {
+				return "", p.errorf("invalid string")
+			}
+			return s, nil
+		}
+		p.pos++
+	}
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) parseIdent() string {
+	begin := p.pos
+	for p.pos < len(p.src) && isIdentChar(p.src[p.pos]) {
+		p.pos++
+	}
+	return p.src[begin:p.pos]
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || c == '-' || (c >= '0' && c <= '9')
+}
+
+// projectFields keeps only the given dotted field paths, applying to every
+// element when data (or any value along a path) is an array
+func projectFields(data any, fields []string) any {
+	switch data.(type) {
+	case []any, *object:
+	default:
+		return data
+	}
+
+	var result any
+	for _, f := range fields {
+		if f = strings.TrimSpace(f); f == "" {
+			continue
+		}
+		if projected, ok := projectPath(data, strings.Split(f, ".")); ok {
+			result = mergeProjected(result, projected)
+		}
+	}
+	if result == nil {
+		if _, ok := data.([]any); ok {
+			return projectPathEmpty(data)
+		}
+		return newObject()
+	}
+	return result
+}
+
+func projectPath(v any, parts []string) (any, bool) {
+	if len(parts) == 0 {
+		return v, true
+	}
+
+	switch c := v.(type) {
+	case *object:
+		child, ok := c.get(parts[0])
+		if !ok {
+			return nil, false
+		}
+		sub, ok := projectPath(child, parts[1:])
+		if !ok {
+			return nil, false
+		}
+		out := newObject()
+		out.set(parts[0], sub)
+		return out, true
+	case []any:
+		out := make([]any, len(c))
+		for i, item := range c {
+			sub, ok := projectPath(item, parts)
+			if !ok {
+				sub = newObject()
+			}
+			out[i] = sub
+		}
+		return out, true
+	default:
+		return nil, false
+	}
+}
+
+// projectPathEmpty maps every array element to an empty object
+func projectPathEmpty(data any) any {
+	arr := data.([]any)
+	out := make([]any, len(arr))
+	for i := range arr {
+		out[i] = newObject()
+	}
+	return out
+}
+
+func mergeProjected(a, b any) any {
+	switch av := a.(type) {
+	case *object:
+		if bv, ok := b.(*object); ok {
+			for _, k := range bv.keys {
+				existing, _ := av.get(k)
+				av.set(k, mergeProjected(existing, bv.vals[k]))
+			}
+			return av
+		}
+	case []any:
+		if bv, ok := b.([]any); ok && len(av) == len(bv) {
+			for i := range av {
+				av[i] = mergeProjected(av[i], bv[i])
+			}
+			return av
+		}
+	}
+	return b
+}
@@ -0,0 +1,182 @@
+package output
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+var queryData = map[string]any{
+	"total": 3,
+	"items": []map[string]any{
+		{"title": "Go 1.26", "score": 150, "tags": []string{"go"}, "author": map[string]any{"name": "rsc"}},
+		{"title": "Rust", "score": 90, "tags": []string{"rust"}, "author": map[string]any{"name": "steve"}},
+		{"title": "Zig", "score": 120, "author": map[string]any{"name": "andrew"}},
+	},
+}
+
+func runQuery(t *testing.T, expr string, data any) string {
+	t.Helper()
+	q, err := ParseQuery(expr)
+	if err != nil {
+		t.Fatalf("parse %q: %v", expr, err)
+	}
+	normalized, err := normalize(data)
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	result, err := q.Apply(normalized)
+	if err != nil {
+		t.Fatalf("apply %q: %v", expr, err)
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(out)
+}
+
+func TestQuery(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{".", `{"items":[{"author":{"name":"rsc"},"score":150,"tags":["go"],"title":"Go 1.26"},{"author":{"name":"steve"},"score":90,"tags":["rust"],"title":"Rust"},{"author":{"name":"andrew"},"score":120,"title":"Zig"}],"total":3}`},
+		{".total", `3`},
+		{".items[0].title", `"Go 1.26"`},
+		{".items[-1].title", `"Zig"`},
+		{".items[1:] | length", `2`},
+		{".items[].title", `["Go 1.26","Rust","Zig"]`},
+		{".items[] | .author.name", `["rsc","steve","andrew"]`},
+		{".items[] | select(.score > 100) | .title", `["Go 1.26","Zig"]`},
+		{".items[] | select(.score >= 90 and .title != \"Rust\") | .title", `["Go 1.26","Zig"]`},
+		{".items[] | select(.title == \"Rust\" or .score == 120) | .title", `["Rust","Zig"]`},
+		{".items[] | select(.tags) | .title", `["Go 1.26","Rust"]`},
+		{".items[] | select(.score > 1000)", `[]`},
+		{".items[] | {title, who: .author.name}", `[{"title":"Go 1.26","who":"rsc"},{"title":"Rust","who":"steve"},{"title":"Zig","who":"andrew"}]`},
+		{"[.items[] | .score]", `[150,90,120]`},
+		{".items[0] | keys", `["author","score","tags","title"]`},
+		{".items | length", `3`},
+		{".missing.deeper", `null`},
+		{".\"total\"", `3`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if got := runQuery(t, tt.expr, queryData); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestQueryParseErrors(t *testing.T) {
+	for _, expr := range []string{"", ".items |", ".items[", "select(.a >)", "nope", ".a b", "{a"} {
+		if _, err := ParseQuery(expr); err == nil {
+			t.Errorf("expected parse error for %q", expr)
+		}
+	}
+}
+
+func TestQueryRuntimeError(t *testing.T) {
+	q, err := ParseQuery(".total[]")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	normalized, _ := normalize(queryData)
+	if _, err := q.Apply(normalized); err == nil {
+		t.Error("expected error iterating over a number")
+	}
+}
+
+func TestEvalAndTest(t *testing.T) {
+	got, err := Eval(".items[] | select(.score > 100) | {title}", queryData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, ok := got.([]any)
+	if !ok || len(items) != 2 {
+		t.Fatalf("Eval = %#v", got)
+	}
+	if first, ok := items[0].(map[string]any); !ok || first["title"] != "Go 1.26" {
+		t.Errorf("first item = %#v, want a plain map", items[0])
+	}
+	if n, _ := Eval(".total", queryData); n != json.Number("3") {
+		t.Errorf("Eval(.total) = %#v", n)
+	}
+
+	tests := []struct {
+		cond string
+		want bool
+	}{
+		{".total == 3", true},
+		{".total > 3 or .items[0].title == \"Go 1.26\"", true},
+		{".items[2].tags", false},
+		{".items[].tags", true},
+		{".missing", false},
+	}
+	for _, tt := range tests {
+		if got, err := Test(tt.cond, queryData); err != nil || got != tt.want {
+			t.Errorf("Test(%s) = %v, %v; want %v", tt.cond, got, err, tt.want)
+		}
+	}
+	for _, cond := range []string{"", ".a >", ".a) | (.b", "length > 1"} {
+		if _, err := ParseCondition(cond); err == nil {
+			t.Errorf("expected parse error for condition %q", cond)
+		}
+	}
+}
+
+func TestProjectFields(t *testing.T) {
+	normalized, err := normalize(queryData)
+	if err != nil {
+		t.Fatalf("normalize: %v", err)
+	}
+	out, _ := json.Marshal(projectFields(normalized, []string{"total", "items.title", "items.author.name"}))
+	expected := `{"total":3,"items":[{"title":"Go 1.26","author":{"name":"rsc"}},{"title":"Rust","author":{"name":"steve"}},{"title":"Zig","author":{"name":"andrew"}}]}`
+	if string(out) != expected {
+		t.Errorf("expected %s, got %s", expected, out)
+	}
+}
+
+func TestPrintWithFieldsAndQuery(t *testing.T) {
+	ctx := context.Background()
+	SetFormat(ctx, "json")
+	SetFields(ctx, []string{"items"})
+	if err := SetQuery(ctx, ".items[] | select(.score < 100) | .title"); err != nil {
+		t.Fatalf("set query: %v", err)
+	}
+	defer func() {
+		SetFields(ctx, nil)
+		_ = SetQuery(ctx, "")
+	}()
+
+	out := captureStdout(func() {
+		_ = Print(ctx, queryData)
+	})
+
+	if out != "{\"success\":true,\"data\":[\"Rust\"]}\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestPrintInvalidQueryRuntime(t *testing.T) {
+	ctx := context.Background()
+	SetFormat(ctx, "json")
+	if err := SetQuery(ctx, ".total.x"); err != nil {
+		t.Fatalf("set query: %v", err)
+	}
+	defer func() { _ = SetQuery(ctx, "") }()
+
+	var err error
+	out := captureStdout(func() {
+		err = Print(ctx, queryData)
+	})
+	if !IsPrinted(err) {
+		t.Errorf("expected printed error, got %v", err)
+	}
+
+	var resp Response
+	if jerr := json.Unmarshal([]byte(out), &resp); jerr != nil || resp.Error == nil || resp.Error.Code != "invalid_query" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
@@ -0,0 +1,218 @@
+package output
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// tableGap is the space between columns
+	tableGap = 2
+	// minColumnWidth is the narrowest a column is shrunk to when truncating
+	minColumnWidth = 6
+	// maxCellWidth caps any single cell, even when the table fits the terminal
+	maxCellWidth = 80
+)
+
+var tableWidth int
+
+// SetColumns selects and orders table columns. Dotted paths reach into
+// nested objects, e.g. "author.name".
+func SetColumns(ctx context.Context, c []string) {
+	from(ctx).columns = c
+}
+
+// SetTableWidth overrides the detected terminal width. Zero means detect.
+func SetTableWidth(w int) {
+	tableWidth = w
+}
+
+// terminalWidth returns the width tables should fit, or 0 for no limit.
+// COLUMNS wins over the detected terminal size; pipes are not truncated.
+func terminalWidth(w io.Writer) int {
+	if tableWidth > 0 {
+		return tableWidth
+	}
+	if c, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && c > 0 {
+		return c
+	}
+	if f, ok := w.(*os.File); ok {
+		if width, ok := terminalSize(f); ok {
+			return width
+		}
+	}
+	return 0
+}
+
+func (s *settings) printTable(data any) error {
+	normalized, err := normalize(data)
+	if err != nil {
+		return s.printError("format_failed", err.Error(), nil)
+	}
+
+	w := s.writer()
+	columns := s.columns
+
+	// A single object reads best as a vertical key/value listing
+	if obj, ok := normalized.(*object); ok && len(columns) == 0 {
+		rows := make([][]string, 0, len(obj.keys))
+		for _, k := range obj.keys {
+			rows = append(rows, []string{k, tableCell(obj.vals[k])})
+		}
+		renderTable(w, []string{"KEY", "VALUE"}, rows, terminalWidth(w))
+		return nil
+	}
+
+	records := records(normalized)
+	if len(records) == 0 {
+		return nil
+	}
+
+	cols := columns
+	if len(cols) == 0 {
+		cols = columnsOf(records)
+	}
+
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		row := make([]string, len(cols))
+		for i, c := range cols {
+			if val, ok := lookupColumn(rec, c); ok {
+				row[i] = tableCell(val)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	headers := make([]string, len(cols))
+	for i, c := range cols {
+		headers[i] = strings.ToUpper(c)
+	}
+
+	renderTable(w, headers, rows, terminalWidth(w))
+	return nil
+}
+
+// lookupColumn resolves a possibly dotted column name against a row
+func lookupColumn(rec *object, col string) (any, bool) {
+	if val, ok := rec.get(col); ok {
+		return val, true
+	}
+
+	var cur any = rec
+	for _, part := range strings.Split(col, ".") {
+		obj, ok := cur.(*object)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = obj.get(part); !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// tableCell renders a value on a single line. Lists of scalars are joined
+// with commas and objects become key=value pairs.
+func tableCell(v any) string {
+	switch val := v.(type) {
+	case []any:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, tableCell(item))
+		}
+		return strings.Join(parts, ", ")
+	case *object:
+		parts := make([]string, 0, len(val.keys))
+		for _, k := range val.keys {
+			parts = append(parts, k+"="+tableCell(val.vals[k]))
+		}
+		return strings.Join(parts, " ")
+	default:
+		s := cellString(val)
+		s = strings.ReplaceAll(s, "\r\n", " ")
+		s = strings.ReplaceAll(s, "\n", " ")
+		return strings.ReplaceAll(s, "\t", " ")
+	}
+}
+
+// renderTable pads cells into aligned columns, shrinking the widest columns
+// first until the table fits maxWidth (0 disables fitting)
+func renderTable(w io.Writer, headers []string, rows [][]string, maxWidth int) {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := utf8.RuneCountInString(cell); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+	for i := range widths {
+		if widths[i] > maxCellWidth {
+			widths[i] = maxCellWidth
+		}
+	}
+
+	if maxWidth > 0 {
+		fitWidths(widths, maxWidth)
+	}
+
+	writeRow(w, headers, widths)
+	for _, row := range rows {
+		writeRow(w, row, widths)
+	}
+}
+
+func fitWidths(widths []int, maxWidth int) {
+	total := func() int {
+		sum := tableGap * (len(widths) - 1)
+		for _, wd := range widths {
+			sum += wd
+		}
+		return sum
+	}
+
+	for total() > maxWidth {
+		widest := 0
+		for i := range widths {
+			if widths[i] > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumnWidth {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+func writeRow(w io.Writer, cells []string, widths []int) {
+	var sb strings.Builder
+	for i, cell := range cells {
+		cell = truncateCell(cell, widths[i])
+		sb.WriteString(cell)
+		if i < len(cells)-1 {
+			sb.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell)+tableGap))
+		}
+	}
+	fmt.Fprintln(w, strings.TrimRight(sb.String(), " "))
+}
+
+func truncateCell(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	if width <= 3 {
+		return string(runes[:width])
+	}
+	return string(runes[:width-3]) + "..."
+}
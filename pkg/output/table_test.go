@@ -0,0 +1,101 @@
+package output
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+type tableAuthor struct {
+	Name string `json:"name"`
+}
+
+type tableItem struct {
+	Title  string      `json:"title"`
+	Stars  int         `json:"stars"`
+	Author tableAuthor `json:"author"`
+	Labels []string    `json:"labels,omitempty"`
+}
+
+var tableItems = []tableItem{
+	{Title: "first", Stars: 10, Author: tableAuthor{Name: "ann"}, Labels: []string{"bug", "p1"}},
+	{Title: "second\tline", Stars: 2, Author: tableAuthor{Name: "bob"}},
+}
+
+func TestPrintTableStructOrder(t *testing.T) {
+	out := printWithFormat(t, "table", tableItems)
+
+	expected := "TITLE        STARS  AUTHOR    LABELS\n" +
+		"first        10     name=ann  bug, p1\n" +
+		"second line  2      name=bob\n"
+	if out != expected {
+		t.Errorf("unexpected table:\n%s\nwant:\n%s", out, expected)
+	}
+}
+
+func TestPrintTableColumns(t *testing.T) {
+	ctx := context.Background()
+	SetColumns(ctx, []string{"author.name", "title", "missing"})
+	defer SetColumns(ctx, nil)
+
+	out := printWithFormat(t, "table", tableItems)
+
+	expected := "AUTHOR.NAME  TITLE        MISSING\n" +
+		"ann          first\n" +
+		"bob          second line\n"
+	if out != expected {
+		t.Errorf("unexpected table:\n%s\nwant:\n%s", out, expected)
+	}
+}
+
+func TestPrintTableObject(t *testing.T) {
+	out := printWithFormat(t, "table", tableItems[0])
+
+	expected := "KEY     VALUE\n" +
+		"title   first\n" +
+		"stars   10\n" +
+		"author  name=ann\n" +
+		"labels  bug, p1\n"
+	if out != expected {
+		t.Errorf("unexpected table:\n%s\nwant:\n%s", out, expected)
+	}
+}
+
+func TestPrintTableTruncatesToWidth(t *testing.T) {
+	SetTableWidth(30)
+	defer SetTableWidth(0)
+
+	items := []map[string]string{
+		{"id": "1", "body": strings.Repeat("x", 100)},
+	}
+	out := printWithFormat(t, "table", items)
+
+	for _, line := range strings.Split(strings.TrimRight(out, "\n"), "\n") {
+		if len(line) > 30 {
+			t.Errorf("line exceeds width: %q", line)
+		}
+	}
+	if !strings.Contains(out, "...") {
+		t.Errorf("expected truncation marker, got %q", out)
+	}
+}
+
+func TestTruncateCell(t *testing.T) {
+	tests := []struct {
+		in    string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"truncated", 6, "tru..."},
+		{"abc", 2, "ab"},
+		{"héllo wörld", 8, "héllo..."},
+	}
+
+	for _, tt := range tests {
+		if got := truncateCell(tt.in, tt.width); got != tt.want {
+			t.Errorf("truncateCell(%q, %d) = %q, want %q", tt.in, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !darwin && !linux
+
+package output
+
+import "os"
+
+// terminalSize is not supported on this platform; tables are not truncated
+func terminalSize(f *os.File) (int, bool) {
+	return 0, false
+}